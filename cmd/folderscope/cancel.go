@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// runContext は GUI を使用しない実行のコンテキストを作成します。
+// 割り込み（Ctrl+C）・SIGTERM のシグナルを受け取った場合と、timeout が正の場合はその時間が経過した場合にキャンセルされ、
+// キャンセルの原因（context.Cause）に中止の理由を持つ apperrors.CancelError を設定します
+func runContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			cancel(apperrors.Cancelled(apperrors.CancelSignal, sig.String()))
+		case <-ctx.Done():
+		}
+	}()
+
+	runCtx, stopTimer := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		runCtx, stopTimer = context.WithTimeoutCause(ctx, timeout, apperrors.Cancelled(apperrors.CancelTimeout, "上限 "+timeout.String()))
+	}
+	return runCtx, func() {
+		signal.Stop(signals)
+		stopTimer()
+		cancel(nil)
+	}
+}
+
+// withCancelReason は、ctx のキャンセルによって err が返された場合に、キャンセルの原因の中止の理由を err に付加します。
+// スキャナーなどはコンテキストのエラー（context.Canceled）のみを返すため、ログや履歴に理由を記録できるようにします
+func withCancelReason(ctx context.Context, err error) error {
+	var cancelErr *apperrors.CancelError
+	if err == nil || apperrors.Classify(err) != apperrors.ErrCancelled || errors.As(err, &cancelErr) {
+		return err
+	}
+	if errors.As(context.Cause(ctx), &cancelErr) {
+		return apperrors.New(cancelErr, "", "", err)
+	}
+	return err
+}
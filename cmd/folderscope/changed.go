@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/gitinfo"
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// restrictToChanged は、-changed-against が指定されている場合に、entries を ref から変更されたファイルと
+// その親フォルダに絞り込みます。-hunks-only が指定されている場合は、ファイルの本文の代わりに出力する変更箇所も設定します
+func (cfg *runConfig) restrictToChanged(ctx context.Context, logger logging.Logger, entries []model.FileSystemEntry, sourceDir string) ([]model.FileSystemEntry, error) {
+	if cfg.changedAgainst == "" {
+		return entries, nil
+	}
+	hunks, err := gitinfo.ChangedHunks(ctx, sourceDir, cfg.changedAgainst)
+	if err != nil {
+		return nil, fmt.Errorf("%s からの変更の取得に失敗しました: %w", cfg.changedAgainst, err)
+	}
+	if cfg.hunksOnly {
+		cfg.hunks = hunks
+	}
+	changed := filterChanged(entries, hunks)
+	logger.Log("INFO", fmt.Sprintf("%s から変更されたファイルに絞り込みました（%d 件中 %d 件）",
+		cfg.changedAgainst, countFiles(entries), countFiles(changed)), nil)
+	return changed, nil
+}
+
+// filterChanged は entries のうち、changed に含まれるファイルと、それらを含むフォルダのみを返します
+func filterChanged(entries []model.FileSystemEntry, changed map[string]string) []model.FileSystemEntry {
+	dirs := make(map[string]bool)
+	for relPath := range changed {
+		for dir := path.Dir(relPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	filtered := make([]model.FileSystemEntry, 0, len(changed)+len(dirs))
+	for _, entry := range entries {
+		if entry.IsDir && dirs[entry.RelPath] {
+			filtered = append(filtered, entry)
+			continue
+		}
+		if _, ok := changed[entry.RelPath]; ok && !entry.IsDir {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// countFiles は entries に含まれるファイル（フォルダ以外）の数を返します
+func countFiles(entries []model.FileSystemEntry) int {
+	n := 0
+	for _, entry := range entries {
+		if !entry.IsDir {
+			n++
+		}
+	}
+	return n
+}
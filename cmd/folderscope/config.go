@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"FolderScope/internal/usecase/config"
+)
+
+// runConfigCommand は設定ファイルに関する操作を行います。現在は validate（設定ファイルの検証）に対応します
+func runConfigCommand(args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return usageError("使い方: folderscope config validate [-rules <ファイル>] [-mask-rules <ファイル>]")
+	}
+	flags := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	rulesPath := flags.String("rules", "", "検証するパスごとのルールのファイル（JSON・YAML）")
+	maskRulesPath := flags.String("mask-rules", "", "検証するマスクのルールのファイル（JSON・YAML）")
+	parseFlags(flags, args[1:])
+	if *rulesPath == "" && *maskRulesPath == "" {
+		return usageError("検証する設定ファイルを -rules または -mask-rules で指定してください")
+	}
+
+	valid := true
+	check := func(path string, load func(path string) (int, error)) {
+		if path == "" {
+			return
+		}
+		count, err := load(path)
+		if err != nil {
+			valid = false
+			var validationErr *config.ValidationError
+			if errors.As(err, &validationErr) {
+				fmt.Println(validationErr.Error())
+			} else {
+				fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			}
+			return
+		}
+		fmt.Printf("問題はありません: %s（ルール: %d 件）\n", path, count)
+	}
+	check(*rulesPath, func(path string) (int, error) {
+		loaded, err := config.LoadRules(path)
+		return len(loaded), err
+	})
+	check(*maskRulesPath, func(path string) (int, error) {
+		loaded, err := config.LoadMaskRules(path)
+		return len(loaded), err
+	})
+	if !valid {
+		return usageError("設定ファイルに誤りがあります")
+	}
+	return nil
+}
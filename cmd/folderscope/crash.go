@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"FolderScope/internal/infrastructure/crash"
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// effectiveConfig は診断情報に記録する、実行時の実際の設定を返します
+func (cfg *runConfig) effectiveConfig() map[string]any {
+	config := map[string]any{
+		"args":           os.Args[1:],
+		"scannerOptions": cfg.scannerOptions,
+		"reportOptions":  cfg.reportOptions,
+		"settings":       cfg.settings,
+		"sortKey":        cfg.sortKey,
+		"dirsFirst":      cfg.dirsFirst,
+		"changedAgainst": cfg.changedAgainst,
+		"hunksOnly":      cfg.hunksOnly,
+		"maskRules":      len(cfg.maskRules),
+		"stdout":         cfg.toStdout,
+		"source":         cfg.sourcePath,
+		"report":         cfg.reportPath,
+	}
+	if cfg.where != nil {
+		config["where"] = cfg.where.String()
+	}
+	if cfg.patchPath != "" {
+		config["patch"] = cfg.patchPath
+	}
+	if cfg.template != nil {
+		config["template"] = cfg.template.Name()
+	}
+	if cfg.formatter != nil {
+		config["pluginFormat"] = cfg.formatter.Name
+	}
+	return config
+}
+
+// recoverCrash は panic から復帰し、スタックトレース・実際の設定・直近のログ・実行環境をまとめた診断情報のバンドルを保存して、
+// 保存先を表示してから終了コード exitCrash で終了します。panic が発生していない場合は何もしません。
+// recover を呼び出すため、defer で直接呼び出してください。
+// バンドルは outputDir（空の場合や書き込めない場合は一時ディレクトリ）に保存します
+func recoverCrash(logger *logging.RecentLogger, cfg *runConfig, outputDir string) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	r := crash.Report{
+		Time:   time.Now(),
+		Panic:  recovered,
+		Stack:  debug.Stack(),
+		Config: cfg.effectiveConfig(),
+		Logs:   logger.Entries(),
+	}
+	if outputDir == "" && cfg.reportPath != "" {
+		outputDir = filepath.Dir(cfg.reportPath)
+	}
+
+	fmt.Fprintf(os.Stderr, "予期しないエラーが発生しました: %v\n", recovered)
+	path, err := "", fmt.Errorf("出力先フォルダが指定されていません")
+	if outputDir != "" {
+		path, err = crash.WriteBundle(outputDir, r)
+	}
+	if err != nil {
+		path, err = crash.WriteBundle(os.TempDir(), r)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "診断情報を保存できませんでした: %v\n%s", err, r.Stack)
+		os.Exit(exitCrash)
+	}
+	fmt.Fprintf(os.Stderr, "診断情報を保存しました: %s\n"+
+		"問題を報告する際はこのファイルを添付してください（フォルダのパスやコマンドライン引数が含まれるため、共有する前に内容を確認してください）\n", path)
+	os.Exit(exitCrash)
+}
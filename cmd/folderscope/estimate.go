@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/archive"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+// runEstimate はファイル内容を読み込まずに調査対象をスキャンし、出力形式ごとのレポートのサイズとトークン数の見積もりを表示します。
+// フィルタと条件式は通常の実行と同じく適用します
+func runEstimate(ctx context.Context, logger logging.Logger, cfg *runConfig, sourceDir string) error {
+	// ハッシュの計算はファイル全体を読み込むため、見積もりでは行わない
+	cfg.scannerOptions.ComputeHash = false
+	scanner := cfg.newScanner(logger)
+	var entries []model.FileSystemEntry
+	if archive.IsArchive(sourceDir) {
+		a, err := archive.Open(sourceDir)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		scanner, stop := withHeartbeat(logger, scanner, "アーカイブのスキャン", cfg.heartbeat)
+		entries, err = scanner.ScanFS(ctx, a.FS(), sourceDir)
+		stop()
+		if err != nil {
+			return fmt.Errorf("アーカイブのスキャンに失敗しました: %w", err)
+		}
+		cfg.contentFS = a.FS()
+	} else {
+		if err := scanner.ValidateSourceDirectory(sourceDir); err != nil {
+			return fmt.Errorf("調査対象フォルダが無効です: %w", err)
+		}
+		var err error
+		scanner, stop := withHeartbeat(logger, scanner, "スキャン", cfg.heartbeat)
+		entries, err = scanner.Scan(ctx, sourceDir)
+		stop()
+		if err != nil {
+			return fmt.Errorf("フォルダ構造のスキャンに失敗しました: %w", err)
+		}
+	}
+	entries = report.SortEntries(cfg.selectEntries(logger, entries), cfg.sortKey, cfg.dirsFirst)
+
+	generator, err := cfg.newGenerator()
+	if err != nil {
+		return err
+	}
+	// PDF はフォントの埋め込みと圧縮により、SQLite はページ単位の確保によりサイズが内容に比例せず、トークン数も意味を持たないため見積もらない
+	formats := make([]report.Format, 0, len(report.SupportedFormats))
+	for _, format := range report.SupportedFormats {
+		if format != report.FormatPDF && format != report.FormatSQLite {
+			formats = append(formats, format)
+		}
+	}
+	estimate, err := generator.Estimate(entries, formats)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("調査対象: %s\n", sourceDir)
+	fmt.Printf("フォルダ: %d, ファイル: %d（内容を出力: %d, バイナリ: %d, サイズ上限超過: %d, 読み込みエラー: %d）\n",
+		estimate.Dirs, estimate.Files, estimate.ContentFiles, estimate.BinaryFiles, estimate.OversizedFiles, estimate.UnreadableFiles)
+	fmt.Printf("出力する内容の合計: %s\n\n", report.FormatSize(estimate.ContentBytes))
+	// 見出しは全角文字の表示幅に合わせて桁をそろえる
+	fmt.Println("形式         推定サイズ 推定トークン数")
+	for _, f := range estimate.Formats {
+		fmt.Printf("%-10s %12s %14d\n", f.Format, report.FormatSize(f.Bytes), f.Tokens)
+	}
+	fmt.Printf("\nトークン数は %d バイトを 1 トークンとした目安です。文字コードの変換やエスケープ、秘密情報のマスクによる増減は含みません\n", report.BytesPerToken)
+	return nil
+}
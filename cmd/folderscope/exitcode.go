@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// 終了コードです。スクリプトからログのメッセージを解析せずに結果を判定できるよう、エラーの種類（apperrors）ごとに決めます
+const (
+	// exitSuccess は成功した場合の終了コードです
+	exitSuccess = 0
+	// exitFailure は他の終了コードに分類されないエラーで失敗した場合の終了コードです
+	exitFailure = 1
+	// exitPartial はレポートを出力したものの、権限や読み込みのエラーで一部のファイル・フォルダを含められなかった場合の終了コードです
+	exitPartial = 2
+	// exitInvalidInput はオプションの値・組み合わせや、調査対象・出力先の指定が不正な場合の終了コードです
+	exitInvalidInput = 3
+	// exitCancelled は中止した場合の終了コードです。中止の理由（ユーザー操作・タイムアウト・シグナル）はログと実行履歴に記録します
+	exitCancelled = 4
+	// exitCrash は予期しないエラー（panic）で異常終了した場合の終了コードです（sysexits.h の EX_SOFTWARE と同じ値）
+	exitCrash = 70
+)
+
+// exitCode は err の種類に応じた終了コードを返します
+func exitCode(err error) int {
+	if err == nil {
+		return exitSuccess
+	}
+	switch {
+	case errors.Is(err, apperrors.ErrCancelled):
+		return exitCancelled
+	case errors.Is(err, apperrors.ErrPartial):
+		return exitPartial
+	case errors.Is(err, apperrors.ErrInvalidInput), errors.Is(err, apperrors.ErrNotFound),
+		errors.Is(err, apperrors.ErrNotDirectory), errors.Is(err, apperrors.ErrOutputExists):
+		return exitInvalidInput
+	}
+	return exitFailure
+}
+
+// exitOnError は err が nil でない場合に、最後のログを終了コード（exitCode）とともに記録して、その終了コードで終了します。
+// 中止の場合は理由を、一部を処理できなかった場合は警告を記録し、それ以外のエラーは message とともに記録します
+func exitOnError(logger logging.Logger, message string, err error) {
+	if err == nil {
+		return
+	}
+	code := exitCode(err)
+	switch reason := apperrors.ReasonOf(err); {
+	case reason != "":
+		logger.Log("WARN", fmt.Sprintf("処理を中止しました（理由: %s）", reason.Label()), err, "exitCode", code)
+		fmt.Fprintf(os.Stderr, "中止しました（理由: %s）: %v\n", reason.Label(), err)
+	case code == exitPartial:
+		logger.Log("WARN", "一部のファイル・フォルダを処理できずに完了しました", err, "exitCode", code)
+		fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+	default:
+		logger.Log("ERROR", message, err, "exitCode", code)
+		log.Printf("エラー: %v", err)
+	}
+	os.Exit(code)
+}
+
+// invalidInputf はオプションの誤りを表示して、終了コード exitInvalidInput で終了します
+func invalidInputf(format string, args ...any) {
+	log.Printf(format, args...)
+	os.Exit(exitInvalidInput)
+}
+
+// parseFlags は args を解析します。誤りがある場合は flag パッケージが表示したエラーと使い方に続けて終了コード exitInvalidInput で、
+// -h・-help の場合は終了コード exitSuccess で終了します（flag.ExitOnError の終了コード 2 は exitPartial と重なるため使用しません）
+func parseFlags(flags *flag.FlagSet, args []string) {
+	flags.Init(flags.Name(), flag.ContinueOnError)
+	err := flags.Parse(args)
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(exitSuccess)
+	}
+	os.Exit(exitInvalidInput)
+}
+
+// usageError は、サブコマンドの引数の不足や誤りを示す ErrInvalidInput の種類のエラーを返します。
+// オプションの誤りと同じく、終了コード exitInvalidInput で終了します
+func usageError(message string) error {
+	return apperrors.New(apperrors.ErrInvalidInput, message, "", nil)
+}
+
+// partialError は、スキャンで権限や読み込みのエラーが発生していた場合に ErrPartial の種類のエラーを返します。エラーがない場合は nil を返します
+func partialError(stats model.ScanStats) error {
+	if stats.Errors == 0 {
+		return nil
+	}
+	return apperrors.New(apperrors.ErrPartial,
+		fmt.Sprintf("スキャン中に %d 件のパスでアクセスまたは読み込みのエラーが発生したため、レポートに含まれていない内容があります", stats.Errors), "", nil)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"成功", nil, exitSuccess},
+		{"分類されないエラー", errors.New("書き込みに失敗"), exitFailure},
+		{"一部を処理できなかった", partialError(model.ScanStats{Errors: 3}), exitPartial},
+		{"不正な入力", apperrors.New(apperrors.ErrInvalidInput, "オプションが不正です", "", nil), exitInvalidInput},
+		{"存在しないパス", fmt.Errorf("調査対象フォルダが無効です: %w", apperrors.New(apperrors.ErrNotFound, "", "/missing", nil)), exitInvalidInput},
+		{"ディレクトリではない", apperrors.New(apperrors.ErrNotDirectory, "", "/file", nil), exitInvalidInput},
+		{"出力ファイルが存在する", apperrors.New(apperrors.ErrOutputExists, "", "/out/report.txt", nil), exitInvalidInput},
+		{"復元先にファイルが存在する", fmt.Errorf("レポートの復元に失敗しました: %w: %s", report.ErrRestoreExists, "/out/a.txt"), exitInvalidInput},
+		{"ユーザー操作による中止", apperrors.Cancelled(apperrors.CancelUser, ""), exitCancelled},
+		{"タイムアウトによる中止", fmt.Errorf("スキャンに失敗しました: %w", apperrors.Cancelled(apperrors.CancelTimeout, "10m")), exitCancelled},
+		{"シグナルによる中止", apperrors.Cancelled(apperrors.CancelSignal, "interrupt"), exitCancelled},
+		{"理由のない中止", apperrors.ErrCancelled, exitCancelled},
+		{"中止は一部のエラーより優先", errors.Join(partialError(model.ScanStats{Errors: 1}), apperrors.ErrCancelled), exitCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d; want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+	if got := partialError(model.ScanStats{}); got != nil {
+		t.Errorf("partialError(エラーなし) = %v; want nil", got)
+	}
+}
+
+func TestPartialError_Symlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "shared"), 0755); err != nil {
+		t.Fatalf("フォルダの作成に失敗: %v", err)
+	}
+	for link, target := range map[string]string{"vendor": "shared", "dangling": "missing.txt"} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skipf("シンボリックリンクを作成できません: %v", err)
+		}
+	}
+
+	// フォルダへのリンクとリンク切れだけの正常なフォルダは、一部を処理できなかった扱いにしない
+	scanner, err := filesystem.NewScannerWithOptions(logging.NewJSONLogger(io.Discard), filesystem.ScannerOptions{IncludeHidden: true})
+	if err != nil {
+		t.Fatalf("NewScannerWithOptions() error = %v", err)
+	}
+	_, stats, err := scanner.ScanWithStats(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("ScanWithStats() error = %v", err)
+	}
+	if err := partialError(stats); err != nil {
+		t.Errorf("partialError() = %v; want nil", err)
+	}
+}
+
+func TestSubcommands_UsageErrors(t *testing.T) {
+	invalidRules := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(invalidRules, []byte(`{"rules": [{"pattern": "*.go", "mode": "unknown"}]}`), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+	}{
+		{"restore の引数なし", "restore", nil},
+		{"restore の復元先なし", "restore", []string{"report.json"}},
+		{"view の引数なし", "view", nil},
+		{"view の引数が多い", "view", []string{"a.json", "b.json"}},
+		{"snapshot の -source なし", "snapshot", nil},
+		{"compare の -snapshot なし", "compare", nil},
+		{"compare の -to と -source", "compare", []string{"-snapshot", "a.snapshot", "-to", "b.snapshot", "-source", "."}},
+		{"config の操作なし", "config", nil},
+		{"config の不明な操作", "config", []string{"show"}},
+		{"config validate のファイルなし", "config", []string{"validate"}},
+		{"config validate の誤りのあるファイル", "config", []string{"validate", "-rules", invalidRules}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := subcommands[tt.command](tt.args)
+			if got := exitCode(err); got != exitInvalidInput {
+				t.Errorf("%s %v: exitCode(%v) = %d; want %d", tt.command, tt.args, err, got, exitInvalidInput)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+// runExplain は、sourceDir をスキャンした場合に paths のそれぞれがレポートに含まれるかどうかと、
+// その判定に使用した設定（既定の無視パターン・-ignore・-preset・-rules など）を表示します。
+// paths は sourceDir からの相対パスまたは sourceDir の中の絶対パスです。表示できなかったパスがある場合はエラーを返します
+func runExplain(logger logging.Logger, cfg *runConfig, sourceDir string, paths []string) error {
+	scanner := cfg.newScanner(logger)
+	if err := scanner.ValidateSourceDirectory(sourceDir); err != nil {
+		return fmt.Errorf("調査対象フォルダが無効です: %w", err)
+	}
+	absSource, err := filepath.Abs(sourceDir)
+	if err != nil {
+		return fmt.Errorf("フォルダのパスの解決に失敗しました: %w", err)
+	}
+
+	failed := 0
+	for _, p := range paths {
+		relPath := p
+		if filepath.IsAbs(p) {
+			if relPath, err = filepath.Rel(absSource, p); err != nil {
+				relPath = p
+			}
+		}
+		exp, err := scanner.ExplainPath(absSource, relPath)
+		if err != nil {
+			fmt.Printf("%s: 判定できません: %v\n", p, err)
+			failed++
+			continue
+		}
+		for _, line := range cfg.explainLines(exp) {
+			fmt.Println(line)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 件のパスを判定できませんでした", failed)
+	}
+	return nil
+}
+
+// explainLines は判定の結果を、1 行目に含まれるかどうか、2 行目以降に判定に使用した設定を記載した行の一覧で返します
+func (cfg *runConfig) explainLines(exp filesystem.Explanation) []string {
+	var details []string
+	if exp.Included && !exp.IsDir && cfg.where != nil && !cfg.where.Match(exp.Entry, time.Now()) {
+		return []string{exp.RelPath + ": 除外されます", fmt.Sprintf("  -where の条件式 '%s' を満たしません", cfg.where)}
+	}
+	if !exp.Included {
+		reason := cfg.exclusionReason(exp)
+		if exp.MatchedPath != exp.RelPath {
+			reason = fmt.Sprintf("フォルダ %s が除外されるため、配下も除外されます（%s）", exp.MatchedPath, reason)
+		}
+		return []string{exp.RelPath + ": 除外されます", "  " + reason}
+	}
+	if exp.IncludedBy != "" {
+		details = append(details, fmt.Sprintf("  -include の正規表現 '%s' に一致します", exp.IncludedBy))
+	}
+	if exp.Rule != nil {
+		details = append(details, fmt.Sprintf("  -rules のルール '%s' により %s で出力します", exp.Rule.Path, exp.Rule.Mode))
+	}
+	if cfg.where != nil && !exp.IsDir {
+		details = append(details, fmt.Sprintf("  -where の条件式 '%s' を満たします", cfg.where))
+	}
+	if len(details) == 0 {
+		details = append(details, "  除外する設定に一致しません")
+	}
+	return append([]string{exp.RelPath + ": 含まれます"}, details...)
+}
+
+// exclusionReason は除外の理由を、一致したパターンと指定したオプションとともに返します
+func (cfg *runConfig) exclusionReason(exp filesystem.Explanation) string {
+	switch exp.Reason {
+	case model.SkipIgnored:
+		return fmt.Sprintf("%sの無視パターン '%s' に一致します", cfg.ignoreOrigin(exp), exp.Pattern)
+	case model.SkipHidden:
+		return "隠しファイル・隠しフォルダのため除外されます（-hidden=false）"
+	case model.SkipExcluded:
+		return fmt.Sprintf("-exclude の正規表現 '%s' に一致します", exp.Pattern)
+	case model.SkipNotIncluded:
+		return "-include の正規表現のいずれにも一致しません"
+	case model.SkipRule:
+		return fmt.Sprintf("-rules のルール '%s' で除外（exclude）が指定されています", exp.Pattern)
+	case model.SkipSymlink:
+		return fmt.Sprintf("シンボリックリンクのため除外されるか、リンク先をたどりません（-symlinks %s）", cfg.scannerOptions.SymlinkPolicy)
+	case model.SkipSize:
+		return fmt.Sprintf("ファイルサイズ（%s）が -min-size・-max-size の範囲外です", report.FormatSize(exp.Entry.Size))
+	case model.SkipModTime:
+		return "更新日時が -modified-after・-modified-before の期間外です"
+	case model.SkipBinary:
+		return "バイナリファイルを除外する設定です（-binary omit または -ignore-binary）"
+	}
+	return string(exp.Reason)
+}
+
+// ignoreOrigin は一致した無視パターンを指定した設定の説明を返します
+func (cfg *runConfig) ignoreOrigin(exp filesystem.Explanation) string {
+	if exp.Default {
+		return "既定"
+	}
+	if preset, ok := cfg.ignoreOrigins[exp.Pattern]; ok {
+		return fmt.Sprintf("-preset %s ", preset)
+	}
+	return "-ignore "
+}
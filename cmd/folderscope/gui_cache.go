@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/usecase/report"
+)
+
+// scanCache は GUI のセッションで最後にスキャンしたエントリを、スキャン時の設定とともに保持します。
+// 出力形式などの設定を変更した場合に、ファイルシステムを再スキャンせずにレポートを再生成するために使用します
+type scanCache struct {
+	entries []model.FileSystemEntry
+	// ignorePatterns はスキャン時に適用した追加の無視パターンです
+	ignorePatterns []string
+	// binaryOmitted はスキャン時にバイナリファイルを除外したかどうかを示します
+	binaryOmitted bool
+}
+
+// newScanCache は cfg の現在の設定でスキャンした entries を保持する scanCache を作成します
+func newScanCache(cfg *runConfig, entries []model.FileSystemEntry) *scanCache {
+	return &scanCache{
+		entries:        entries,
+		ignorePatterns: slices.Clone(cfg.settings.IgnorePatterns),
+		binaryOmitted:  cfg.settings.BinaryPolicy == string(report.BinaryOmit),
+	}
+}
+
+// reusable は cfg の現在の設定でのレポートを、キャッシュしたエントリから作成できるかどうかを返します。
+// スキャン時に適用した無視パターンが削除された場合や、除外したバイナリファイルを出力する設定に変更された場合は、
+// 必要なエントリがキャッシュにないため false を返します
+func (c *scanCache) reusable(cfg *runConfig) bool {
+	for _, pattern := range c.ignorePatterns {
+		if !slices.Contains(cfg.settings.IgnorePatterns, pattern) {
+			return false
+		}
+	}
+	return !c.binaryOmitted || cfg.settings.BinaryPolicy == string(report.BinaryOmit)
+}
+
+// entriesFor はキャッシュしたエントリのうち、cfg の現在の設定でスキャンした場合にも含まれるエントリを返します。
+// 追加された無視パターンに一致するファイル・フォルダ（とその配下）と、バイナリファイルを除外する設定の場合のバイナリファイルを取り除きます
+func (c *scanCache) entriesFor(cfg *runConfig, scanner *filesystem.Scanner) []model.FileSystemEntry {
+	omitBinary := cfg.settings.BinaryPolicy == string(report.BinaryOmit)
+	entries := make([]model.FileSystemEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if omitBinary && !entry.IsDir && entry.IsBinary {
+			continue
+		}
+		if isIgnoredPath(scanner, entry) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// isIgnoredPath は entry の相対パスのいずれかの要素が scanner の無視パターンに一致するかどうかを返します
+func isIgnoredPath(scanner *filesystem.Scanner, entry model.FileSystemEntry) bool {
+	names := strings.Split(filepath.ToSlash(entry.RelPath), "/")
+	for i, name := range names {
+		isDir := i < len(names)-1 || entry.IsDir
+		if scanner.IsIgnoredName(name, isDir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/gui"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/instance"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/state"
+	"FolderScope/internal/usecase/report"
+)
+
+// loadState は前回の実行状態を読み込みます。
+// 状態ファイルを利用できない場合でも処理は続行できるため、警告を記録して空の状態を返します
+func loadState(logger logging.Logger) (*state.Store, *state.State) {
+	path, err := state.DefaultPath()
+	if err != nil {
+		logger.Log("WARN", "状態ファイルのパスを決定できません", err)
+		return nil, &state.State{}
+	}
+	store := state.NewStore(path)
+	st, err := store.Load()
+	if err != nil {
+		logger.Log("WARN", "状態ファイルの読み込みに失敗", err)
+		return store, &state.State{}
+	}
+	return store, st
+}
+
+// confirmBroadScope は、調査対象がドライブのルートやホームディレクトリのように広範囲である場合に、
+// 規模を見積もって表示し、スキャンを続行するかどうかをユーザーに確認します
+func confirmBroadScope(ui *gui.Window, logger logging.Logger, scanner *filesystem.Scanner, sourceDir, reason string) bool {
+	var estimate filesystem.ScopeEstimate
+	err := ui.RunWithProgress("フォルダの規模を見積もっています", func(ctx context.Context, update func(gui.Progress)) error {
+		var estimateErr error
+		estimate, estimateErr = scanner.WithProgress(func(p filesystem.ScanProgress) {
+			update(gui.Progress{CurrentPath: p.CurrentPath, Files: p.Files})
+		}).EstimateScope(ctx, sourceDir, filesystem.DefaultEstimateBudget)
+		return estimateErr
+	})
+	if errors.Is(err, apperrors.ErrCancelled) {
+		return false
+	}
+
+	message := fmt.Sprintf("選択された調査対象フォルダ %s は%sです。\n", sourceDir, reason)
+	scale := fmt.Sprintf("%d 個のファイル、%d 個のフォルダ（合計 %s）", estimate.Files, estimate.Dirs, report.FormatSize(estimate.Bytes))
+	switch {
+	case err != nil:
+		logger.Log("WARN", "フォルダの規模の見積もりに失敗", err)
+		message += "フォルダの規模を見積もれませんでした。"
+	case estimate.Complete:
+		message += scale + "が含まれています。"
+	default:
+		message += fmt.Sprintf("%d 秒間の見積もりで、少なくとも %sが見つかりました。スキャンには長時間かかる可能性があります。",
+			int(filesystem.DefaultEstimateBudget.Seconds()), scale)
+	}
+	message += "\nスキャンを続行しますか？"
+	return ui.Confirm("広範囲のスキャンの確認", message)
+}
+
+// errorTitle はエラーの種類に応じて、エラー画面に表示する見出しを返します
+func errorTitle(err error) string {
+	switch {
+	case errors.Is(err, apperrors.ErrPermission):
+		return "アクセス権限がありません"
+	case errors.Is(err, apperrors.ErrNotFound):
+		return "フォルダが見つかりません"
+	case errors.Is(err, apperrors.ErrNotDirectory):
+		return "フォルダではありません"
+	case errors.Is(err, apperrors.ErrOutputExists):
+		return "出力ファイルがすでに存在します"
+	}
+	return "エラー"
+}
+
+// scanFolder は進捗画面を表示しながら sourceDir をスキャンし、スキャンの統計情報を cfg に記録します。
+// 進捗画面からキャンセルされた場合は apperrors.ErrCancelled を返し、cfg の統計情報は変更しません
+func scanFolder(ui *gui.Window, logger logging.Logger, cfg *runConfig, scanner *filesystem.Scanner, sourceDir string) ([]model.FileSystemEntry, error) {
+	var (
+		entries []model.FileSystemEntry
+		stats   model.ScanStats
+	)
+	err := ui.RunWithProgress("フォルダをスキャンしています", func(ctx context.Context, update func(gui.Progress)) error {
+		var scanErr error
+		entries, stats, scanErr = scanner.WithProgress(func(p filesystem.ScanProgress) {
+			update(gui.Progress{CurrentPath: p.CurrentPath, Files: p.Files})
+		}).ScanWithStats(ctx, sourceDir)
+		return scanErr
+	})
+	if errors.Is(err, apperrors.ErrCancelled) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("フォルダ構造のスキャンに失敗しました: %w", err)
+	}
+	cfg.scanStats = stats
+	return entries, nil
+}
+
+// selectAndScan は、フォルダ選択とスキャンを行います。
+// スキャンがキャンセルされた場合は、直前の選択内容を保持したままフォルダ選択に戻ります。
+// フォルダ選択がキャンセルされた場合は apperrors.ErrCancelled を返します
+func selectAndScan(ui *gui.Window, logger logging.Logger, cfg *runConfig, selector *gui.DirectorySelector, initial *gui.DirectoryPaths) (*gui.DirectoryPaths, []model.FileSystemEntry, error) {
+	dirs := initial
+	for {
+		var err error
+		dirs, err = ui.SelectDirectories(selector, dirs, cfg.settings)
+		if err != nil {
+			return nil, nil, err
+		}
+		logger.Log("INFO", fmt.Sprintf("選択されたフォルダ - 調査対象: %s, 出力先: %s", dirs.Source, dirs.Output), nil)
+
+		logger.Log("INFO", fmt.Sprintf("設定 - %s", cfg.settings.Summary()), nil)
+		scanner := cfg.newScanner(logger)
+
+		// ドライブのルートやホームディレクトリが選択された場合は、誤って長時間のスキャンを始めないよう確認する
+		sourceDir := dirs.Source
+		if reason := filesystem.BroadScopeReason(sourceDir); reason != "" {
+			if !confirmBroadScope(ui, logger, scanner, sourceDir, reason) {
+				logger.Log("INFO", "広範囲のスキャンが取り消されました。フォルダ選択に戻ります", nil)
+				continue
+			}
+		}
+
+		// フォルダ構造のスキャン（進捗画面からキャンセル可能）
+		entries, err := scanFolder(ui, logger, cfg, scanner, sourceDir)
+		if errors.Is(err, apperrors.ErrCancelled) {
+			logger.Log("INFO", "スキャンがキャンセルされました。フォルダ選択に戻ります", err)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		logger.Log("INFO", "フォルダ構造のスキャンが完了しました", nil)
+		return dirs, entries, nil
+	}
+}
+
+// runGUI は、フォルダ選択からレポートの出力・エクスポートまでを 1 つのウィンドウ上で実行し、
+// 完了時にはウィンドウに結果を表示します。
+// 完了画面では、スキャン済みのエントリから設定を変更したレポートを再生成するか、ファイルシステムを再スキャンできます。
+// requested は起動時に指定された調査対象フォルダで、空でない場合はフォルダ選択画面の初期値とします。
+// スキャン中などに他のプロセスから調査対象フォルダが渡された場合は、完了画面を閉じた後にそのフォルダでフォルダ選択画面に戻ります
+func runGUI(ui *gui.Window, logger logging.Logger, cfg *runConfig, selector *gui.DirectorySelector, requested string) error {
+	// 前回の出力先フォルダを初期値とし、最近使用したフォルダをクイック選択できるようにする
+	stateStore, appState := loadState(logger)
+	var initial *gui.DirectoryPaths
+	if appState.LastOutputDir != "" || requested != "" {
+		initial = &gui.DirectoryPaths{Source: requested, Output: appState.LastOutputDir}
+	}
+
+	// 見積もったレポートのサイズが上限を超える場合は、ウィンドウで対応を選択させる
+	cfg.askReportSize = func(message string, actions []reportSizeAction) reportSizeAction {
+		return chooseReportSize(ui, message, actions)
+	}
+
+	for {
+		selector.SetRecentDirectories(appState.RecentSourceDirs, appState.RecentOutputDirs)
+		dirs, entries, err := selectAndScan(ui, logger, cfg, selector, initial)
+		if err != nil {
+			return err
+		}
+		cache := newScanCache(cfg, entries)
+
+		result, err := writeReport(context.Background(), logger, cfg, entries, dirs.Source, dirs.Output)
+		if errors.Is(err, apperrors.ErrCancelled) {
+			logger.Log("INFO", "レポートの出力が中止されました。フォルダ選択に戻ります", err)
+			initial = dirs
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// 次回のために使用したフォルダを記録
+		if stateStore != nil {
+			appState.RecordSelection(dirs.Source, dirs.Output)
+			if err := stateStore.Save(appState); err != nil {
+				logger.Log("WARN", "状態ファイルの保存に失敗", err)
+			}
+		}
+
+		for {
+			logger.Log("INFO", "処理が完了しました", nil)
+			log.Printf("処理が完了しました。出力先: %s\n", result.outputPath)
+			action := ui.ShowResult("完了", resultMessage(result), cfg.settings)
+			if action == gui.ResultClose {
+				break
+			}
+			// 再生成に失敗した場合も、再スキャンしたエントリは以降の再生成に使用する
+			revised, revisedResult, err := reviseReport(ui, logger, cfg, dirs, cache, action == gui.ResultRescan)
+			cache = revised
+			if errors.Is(err, apperrors.ErrCancelled) {
+				continue
+			}
+			if err != nil {
+				// エラーを表示して完了画面に戻る
+				logger.Log("ERROR", "レポートの再生成に失敗", err)
+				ui.ShowMessage(errorTitle(err), err.Error())
+				continue
+			}
+			result = revisedResult
+		}
+
+		next := ui.TakeRequestedFolder()
+		if next == "" {
+			return nil
+		}
+		logger.Log("INFO", fmt.Sprintf("他のプロセスから渡された調査対象フォルダでフォルダ選択に戻ります: %s", next), nil)
+		initial = &gui.DirectoryPaths{Source: next, Output: dirs.Output}
+	}
+}
+
+// chooseReportSize は見積もったレポートのサイズが上限を超える場合の対応を、ウィンドウの確認画面で選択させます。
+// ウィンドウが閉じられた場合は中止として扱います
+func chooseReportSize(ui *gui.Window, message string, actions []reportSizeAction) reportSizeAction {
+	labels := make([]string, len(actions))
+	for i, action := range actions {
+		labels[i] = action.label()
+	}
+	choice := ui.Choose("レポートのサイズの確認", message+"\n対応を選択してください。", labels)
+	if choice < 0 {
+		return sizeAbort
+	}
+	return actions[choice]
+}
+
+// resultMessage は完了画面に表示する、レポートの出力先（Gist へエクスポートした場合はその URL）のメッセージを返します
+func resultMessage(result reportResult) string {
+	message := fmt.Sprintf("レポートを出力しました。\n%s", result.outputPath)
+	if result.gistURL != "" {
+		message += fmt.Sprintf("\n\nGist URL: %s", result.gistURL)
+	}
+	return message
+}
+
+// reviseReport は完了画面で選択された操作に応じて、レポートを出力し直します。
+// rescan が false の場合はキャッシュしたエントリから cfg の現在の設定でレポートを再生成し、
+// 無視パターンの削除などでキャッシュにないエントリが必要な場合は、再スキャンするかどうかを確認します。
+// 再スキャンがキャンセルされた場合は apperrors.ErrCancelled を返します。
+// エラーの場合も、以降の再生成に使用する（再スキャンした場合はその結果の）キャッシュを返します
+func reviseReport(ui *gui.Window, logger logging.Logger, cfg *runConfig, dirs *gui.DirectoryPaths, cache *scanCache, rescan bool) (*scanCache, reportResult, error) {
+	logger.Log("INFO", fmt.Sprintf("設定 - %s", cfg.settings.Summary()), nil)
+	scanner := cfg.newScanner(logger)
+	if !rescan && !cache.reusable(cfg) {
+		if !ui.Confirm("再スキャンの確認", "スキャン時に適用した無視パターンの削除やバイナリファイルの扱いの変更を反映するには、"+
+			"ファイルシステムの再スキャンが必要です。\n再スキャンしますか？") {
+			return cache, reportResult{}, apperrors.ErrCancelled
+		}
+		rescan = true
+	}
+
+	entries := cache.entries
+	if rescan {
+		var err error
+		entries, err = scanFolder(ui, logger, cfg, scanner, dirs.Source)
+		if errors.Is(err, apperrors.ErrCancelled) {
+			logger.Log("INFO", "再スキャンがキャンセルされました。スキャン済みのエントリを保持します", err)
+			return cache, reportResult{}, err
+		}
+		if err != nil {
+			return cache, reportResult{}, err
+		}
+		logger.Log("INFO", "フォルダ構造の再スキャンが完了しました", nil)
+		cache = newScanCache(cfg, entries)
+	} else {
+		entries = cache.entriesFor(cfg, scanner)
+		logger.Log("INFO", fmt.Sprintf("スキャン済みのエントリからレポートを再生成します（%d 件中 %d 件）", len(cache.entries), len(entries)), nil)
+	}
+
+	result, err := writeReport(context.Background(), logger, cfg, entries, dirs.Source, dirs.Output)
+	if err != nil {
+		return cache, reportResult{}, err
+	}
+	return cache, result, nil
+}
+
+// acquireInstance は GUI を 1 つのプロセスで実行するため、起動中のインスタンスがある場合は調査対象フォルダ requested を渡して true を返します
+// （呼び出し元は新しいウィンドウを開かずに終了します）。
+// 起動中のインスタンスがない場合は、他のプロセスからの要求を受け付ける Listener を返します。受け付けを開始できない場合は nil です
+func acquireInstance(logger logging.Logger, requested string) (*instance.Listener, bool) {
+	path, err := instance.DefaultPath()
+	if err != nil {
+		logger.Log("WARN", "起動中のインスタンスとの通信に使用するソケットのパスを決定できません", err)
+		return nil, false
+	}
+	err = instance.Forward(path, requested)
+	if err == nil {
+		logger.Log("INFO", fmt.Sprintf("起動中の FolderScope に調査対象フォルダを渡しました: %s", requested), nil)
+		return nil, true
+	}
+	if !errors.Is(err, instance.ErrNoInstance) {
+		logger.Log("WARN", "起動中の FolderScope に調査対象フォルダを渡せないため、新しいウィンドウで起動します", err)
+		return nil, false
+	}
+	listener, err := instance.Listen(path)
+	if err != nil {
+		logger.Log("WARN", "他のプロセスからの調査対象フォルダの受け付けを開始できません", err)
+		return nil, false
+	}
+	return listener, false
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/archive"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/gitinfo"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/watcher"
+	"FolderScope/internal/usecase/diff"
+	"FolderScope/internal/usecase/report"
+)
+
+// withHeartbeat は interval ごとに進捗をログに出力するスキャナーを返します。
+// スキャンの終了後に stop を呼び出してください。interval が 0 以下の場合は scanner をそのまま返します
+func withHeartbeat(logger logging.Logger, scanner *filesystem.Scanner, label string, interval time.Duration) (*filesystem.Scanner, func()) {
+	if interval <= 0 {
+		return scanner, func() {}
+	}
+	heartbeat := filesystem.StartHeartbeat(logger, label, interval)
+	return scanner.WithProgress(heartbeat.Update), heartbeat.Stop
+}
+
+// validateDirectories は GUI を使用しない実行で、調査対象フォルダと出力先フォルダを検証します。
+// outputDir が空の場合（標準出力に書き込む場合）は出力先フォルダを検証しません
+func validateDirectories(scanner *filesystem.Scanner, sourceDir, outputDir string) error {
+	if err := scanner.ValidateSourceDirectory(sourceDir); err != nil {
+		return apperrors.New(apperrors.ErrInvalidInput, "調査対象フォルダが無効です", sourceDir, err)
+	}
+	if outputDir == "" {
+		return nil
+	}
+	if err := scanner.ValidateOutputDirectory(outputDir, sourceDir); err != nil {
+		return apperrors.New(apperrors.ErrInvalidInput, "出力先フォルダが無効です", outputDir, err)
+	}
+	return nil
+}
+
+// runHeadless は GUI を使用せずに、指定されたフォルダのレポートを 1 回生成します
+func runHeadless(ctx context.Context, logger logging.Logger, cfg *runConfig, sourceDir, outputDir string) error {
+	scanner := cfg.newScanner(logger)
+	var entries []model.FileSystemEntry
+	if cfg.gitRef != "" || cfg.patchPath != "" || archive.IsArchive(sourceDir) || cfg.plugins.SourceFor(sourceDir) != nil {
+		a, err := scanArchive(ctx, logger, scanner, cfg, sourceDir, outputDir)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		entries = a.entries
+	} else {
+		if err := validateDirectories(scanner, sourceDir, outputDir); err != nil {
+			return err
+		}
+		var err error
+		scanner, stop := withHeartbeat(logger, scanner, "スキャン", cfg.heartbeat)
+		entries, cfg.scanStats, err = scanner.ScanWithStats(ctx, sourceDir)
+		stop()
+		if err != nil {
+			return fmt.Errorf("フォルダ構造のスキャンに失敗しました: %w", err)
+		}
+	}
+	logger.Log("INFO", "フォルダ構造のスキャンが完了しました", nil,
+		"path", sourceDir, "entries", len(entries), "duration", time.Duration(cfg.scanStats.DurationMillis)*time.Millisecond)
+	if cfg.saveScanPath != "" {
+		if err := saveScan(logger, cfg, sourceDir, entries); err != nil {
+			return err
+		}
+	}
+
+	result, err := writeReport(ctx, logger, cfg, entries, sourceDir, outputDir)
+	if err != nil {
+		return err
+	}
+	logger.Log("INFO", "処理が完了しました", nil)
+	if !cfg.toStdout {
+		fmt.Printf("レポートを出力しました: %s\n", result.outputPath)
+		if t := result.deltaTokens; t != nil {
+			fmt.Printf("トークン数: 変更のみ 約 %d / 全体 約 %d（%.1f%%）\n", t.Delta, t.Full, t.Percent())
+		}
+		if n := len(cfg.scanStats.Inaccessible); n > 0 {
+			fmt.Printf("権限がないため読み込めなかったパス: %d 件（レポートの「アクセスできなかったパス」を参照してください）\n", n)
+		}
+	}
+	return partialError(cfg.scanStats)
+}
+
+// scannedArchive はスキャン済みのアーカイブです。レポートの出力が終わるまで閉じずに保持します
+type scannedArchive struct {
+	*archive.Archive
+	entries []model.FileSystemEntry
+}
+
+// scanArchive はアーカイブを展開せずにスキャンし、レポートの生成時にアーカイブから内容を読み込むよう cfg を設定します。
+// archivePath を扱う source のプラグインがある場合は、プラグインが出力した内容をアーカイブとしてスキャンします。
+// git の参照が指定されている場合は、archivePath の git リポジトリからその時点の内容を tar 形式で読み込んでスキャンします。
+// パッチが指定されている場合は、archivePath のフォルダにパッチを適用した後の、変更されたファイルのみをスキャンします。
+// outputDir が空の場合（標準出力に書き込む場合）は出力先フォルダを検証しません
+func scanArchive(ctx context.Context, logger logging.Logger, scanner *filesystem.Scanner, cfg *runConfig, archivePath, outputDir string) (*scannedArchive, error) {
+	if outputDir != "" {
+		if err := scanner.ValidateOutputDirectory(outputDir, ""); err != nil {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "出力先フォルダが無効です", outputDir, err)
+		}
+	}
+	open := archive.Open
+	switch p := cfg.plugins.SourceFor(archivePath); {
+	case cfg.gitRef != "":
+		logger.Log("INFO", fmt.Sprintf("git の参照 '%s' の内容を読み込みます", cfg.gitRef), nil)
+		open = func(location string) (*archive.Archive, error) { return openGitRef(ctx, location, cfg.gitRef) }
+	case cfg.patchPath != "":
+		logger.Log("INFO", fmt.Sprintf("パッチ '%s' を適用した内容を読み込みます", cfg.patchPath), nil)
+		open = func(location string) (*archive.Archive, error) { return cfg.openPatch(ctx, logger, location) }
+	case p != nil:
+		logger.Log("INFO", fmt.Sprintf("プラグイン '%s' で調査対象を読み込みます", p.Name), nil)
+		open = func(location string) (*archive.Archive, error) { return p.Open(ctx, location) }
+	}
+	a, err := open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	scanner, stop := withHeartbeat(logger, scanner, "アーカイブのスキャン", cfg.heartbeat)
+	entries, stats, err := scanner.ScanFSWithStats(ctx, a.FS(), archivePath)
+	stop()
+	if err != nil {
+		a.Close()
+		return nil, fmt.Errorf("アーカイブのスキャンに失敗しました: %w", err)
+	}
+	cfg.contentFS = a.FS()
+	cfg.scanStats = stats
+	return &scannedArchive{Archive: a, entries: entries}, nil
+}
+
+// openGitRef は dir の git リポジトリから ref の時点の内容を読み込み、アーカイブとして返します
+func openGitRef(ctx context.Context, dir, ref string) (*archive.Archive, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("-git-ref の調査対象にはフォルダを指定してください: %s", dir)
+	}
+	data, err := gitinfo.RefArchive(ctx, dir, ref)
+	if err != nil {
+		return nil, fmt.Errorf("git の参照 '%s' の読み込みに失敗しました: %w", ref, err)
+	}
+	return archive.ReadTar(bytes.NewReader(data), dir+"@"+ref)
+}
+
+// runWatch は調査対象フォルダの変更を監視し、変更のたびにレポートを再生成します。
+// 再生成では変更されたファイルのセクションのみをレンダリングし、それ以外は前回の内容を再利用します。
+// レポートは一時ファイルに書き込んでから置き換えるため、読み手が途中まで書かれたレポートを目にすることはありません。
+// ctx がキャンセルされるまで実行を続けます
+func runWatch(ctx context.Context, logger logging.Logger, cfg *runConfig, sourceDir, outputDir string) error {
+	scanner := cfg.newScanner(logger)
+	if err := validateDirectories(scanner, sourceDir, outputDir); err != nil {
+		return err
+	}
+	cfg.loadGitInfo(logger, sourceDir)
+	cfg.reportOptions.ReproduceCommand = cfg.reproduceCommand(sourceDir, outputDir)
+	generator, err := cfg.newGenerator()
+	if err != nil {
+		return err
+	}
+	// 再生成の途中で終了した場合は、中止したことをレポートに記載して置き換えない
+	incremental := report.NewIncrementalGenerator(generator.WithContext(ctx))
+
+	// 監視中は同じ出力ファイルを更新し続ける。-output にファイル名を指定した場合は、そのファイルを更新する
+	outputPath := filepath.Join(outputDir, cfg.outputFile)
+	if cfg.outputFile == "" {
+		outputFile, createdPath, err := generator.CreateOutputFile(outputDir)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		outputFile.Close()
+		outputPath = createdPath
+	}
+	cfg.reportPath = outputPath
+
+	regenerate := func() error {
+		scanner, stop := withHeartbeat(logger, scanner, "スキャン", cfg.heartbeat)
+		entries, stats, err := scanner.ScanWithStats(ctx, sourceDir)
+		stop()
+		if err != nil {
+			return fmt.Errorf("フォルダ構造のスキャンに失敗しました: %w", err)
+		}
+		entries = report.SortEntries(cfg.selectEntries(logger, entries), cfg.sortKey, cfg.dirsFirst)
+		cfg.scanStats = stats
+		var (
+			rendered report.IncrementalStats
+			indexed  []report.IndexEntry
+		)
+		err = writeFileAtomic(outputPath, func(w io.Writer) error {
+			reportWriter := report.NewIndexingWriter(w)
+			rendered, err = incremental.Write(reportWriter, entries)
+			indexed = reportWriter.Entries()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if cfg.writeIndex {
+			if err := writeIndexFile(logger, outputPath, indexed); err != nil {
+				return err
+			}
+		}
+		logger.Log("INFO", fmt.Sprintf("レポートを更新しました: %s（再生成: %d, 再利用: %d, 削除: %d）",
+			outputPath, rendered.Rendered, rendered.Reused, rendered.Pruned), nil)
+		return nil
+	}
+	if err := regenerate(); err != nil {
+		return err
+	}
+
+	w, err := watcher.New(logger, sourceDir, scanner.IsIgnoredName)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	logger.Log("INFO", fmt.Sprintf("変更の監視を開始しました: %s", sourceDir), nil)
+	fmt.Printf("レポートを出力しました: %s\n変更を監視しています（Ctrl+C で終了）...\n", outputPath)
+
+	err = w.Run(ctx, func(changed []string) {
+		logger.Log("INFO", fmt.Sprintf("%d 件の変更を検出しました", len(changed)), nil)
+		// 更新日時の精度が粗いファイルシステムでも変更を取りこぼさないよう、変更されたパスのキャッシュは必ず破棄する
+		incremental.Invalidate(changed...)
+		if err := regenerate(); err != nil {
+			if errors.Is(err, apperrors.ErrCancelled) {
+				return
+			}
+			logger.Log("ERROR", "レポートの更新に失敗", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	// 監視の終了時は、最後に更新したレポートのスキャンで発生したエラーを終了コードに反映する
+	return partialError(cfg.scanStats)
+}
+
+// runDiff は比較元 oldDir と比較先 newDir をスキャンし、追加・削除・変更されたファイルの差分レポートを出力します
+func runDiff(ctx context.Context, logger logging.Logger, cfg *runConfig, oldDir, newDir, outputDir string, method diff.Method, opts diff.ReportOptions) error {
+	// ハッシュで判定する場合は、-hash の指定にかかわらず両方のスキャンでハッシュを計算する
+	if method == diff.MethodHash {
+		cfg.scannerOptions.ComputeHash = true
+	}
+	scanner := cfg.newScanner(logger)
+	if err := validateDirectories(scanner, oldDir, outputDir); err != nil {
+		return err
+	}
+	if err := scanner.ValidateSourceDirectory(newDir); err != nil {
+		return fmt.Errorf("比較先フォルダが無効です: %w", err)
+	}
+	if err := scanner.ValidateOutputDirectory(outputDir, newDir); err != nil {
+		return fmt.Errorf("出力先フォルダが無効です: %w", err)
+	}
+
+	scanner, stop := withHeartbeat(logger, scanner, "比較元フォルダのスキャン", cfg.heartbeat)
+	oldEntries, oldStats, err := scanner.ScanWithStats(ctx, oldDir)
+	stop()
+	if err != nil {
+		return fmt.Errorf("比較元フォルダのスキャンに失敗しました: %w", err)
+	}
+	scanner, stop = withHeartbeat(logger, scanner, "比較先フォルダのスキャン", cfg.heartbeat)
+	newEntries, newStats, err := scanner.ScanWithStats(ctx, newDir)
+	stop()
+	if err != nil {
+		return fmt.Errorf("比較先フォルダのスキャンに失敗しました: %w", err)
+	}
+	logger.Log("INFO", "フォルダ構造のスキャンが完了しました", nil)
+
+	result := diff.Compare(cfg.selectEntries(logger, oldEntries), cfg.selectEntries(logger, newEntries), method)
+	logger.Log("INFO", fmt.Sprintf("差分の比較が完了しました（追加: %d, 削除: %d, 変更: %d）",
+		result.Count(diff.Added), result.Count(diff.Removed), result.Count(diff.Modified)), nil)
+	opts.OldRoot, opts.NewRoot = oldDir, newDir
+	opts.ContextLines = diff.DefaultContextLines
+	cfg.reportPath, err = writeDiffReport(outputDir, result, opts)
+	if err != nil {
+		return err
+	}
+	return partialError(model.ScanStats{Errors: oldStats.Errors + newStats.Errors})
+}
+
+// writeFileAtomic は write で書き込んだ内容で path を置き換えます。
+// 同じディレクトリの一時ファイルに書き込んでから名前を変更するため、書き込み途中の内容が path に現れることはありません。
+// write がエラーを返した場合は一時ファイルを削除し、path は変更しません
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return apperrors.Wrap("一時ファイルの作成に失敗しました", path, err)
+	}
+	tmpName := tmp.Name()
+	output := report.NewReportWriter(tmp)
+	if err := write(output); err != nil {
+		output.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := output.Close(); err != nil {
+		os.Remove(tmpName)
+		return apperrors.Wrap("一時ファイルの書き込みに失敗しました", tmpName, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return apperrors.Wrap("出力ファイルの置き換えに失敗しました", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/infrastructure/history"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+// recordRun は started に開始した実行の結果を履歴ファイルに追記します。
+// 履歴は補助的な情報のため、記録に失敗しても実行の結果には影響させず、警告を記録します
+func recordRun(logger logging.Logger, entry history.Entry, started time.Time, runErr error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		logger.Log("WARN", "履歴ファイルのパスを決定できません", err)
+		return
+	}
+
+	entry.Time = started
+	entry.DurationMillis = time.Since(started).Milliseconds()
+	entry.Args = os.Args[1:]
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	entry.Host, _ = os.Hostname()
+	// 調査対象はアーカイブのスキームなどを含む場合があるため、ローカルのパスのみ絶対パスにする
+	if entry.Source != "" && !strings.Contains(entry.Source, "://") {
+		if abs, err := filepath.Abs(entry.Source); err == nil {
+			entry.Source = abs
+		}
+	}
+	switch {
+	case runErr == nil:
+		entry.Result = history.ResultSuccess
+	case errors.Is(runErr, apperrors.ErrCancelled) || errors.Is(runErr, context.Canceled):
+		entry.Result = history.ResultCancelled
+		entry.CancelReason = string(apperrors.ReasonOf(runErr))
+	case errors.Is(runErr, apperrors.ErrPartial):
+		entry.Result = history.ResultPartial
+		entry.Error = runErr.Error()
+	default:
+		entry.Result = history.ResultFailure
+		entry.Error = runErr.Error()
+	}
+
+	if err := history.NewLog(path).Append(entry); err != nil {
+		logger.Log("WARN", "実行履歴の記録に失敗", err)
+	}
+}
+
+// runHistoryCommand は履歴ファイルに記録された実行を新しい順に表示します
+func runHistoryCommand(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	sourceDir := flags.String("source", "", "このフォルダまたはその配下を対象とした実行のみを表示する")
+	command := flags.String("command", "", "実行の種類（report, render, watch, diff, gui, snapshot, compare）で絞り込む")
+	since := flags.Duration("since", 0, "指定した期間内（例: 168h）に開始した実行のみを表示する（0 で無制限）")
+	limit := flags.Int("limit", 20, "表示する件数の上限（0 で無制限）")
+	asJSON := flags.Bool("json", false, "1 行に 1 件の JSON で出力する")
+	parseFlags(flags, args)
+
+	path, err := history.DefaultPath()
+	if err != nil {
+		return err
+	}
+	filter := history.Filter{Command: *command, Limit: *limit}
+	if *sourceDir != "" {
+		if filter.Source, err = filepath.Abs(*sourceDir); err != nil {
+			return fmt.Errorf("フォルダのパスの解決に失敗しました: %w", err)
+		}
+	}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+	entries, skipped, err := history.NewLog(path).Query(filter)
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		logging.NewJSONLogger(os.Stderr).Log("WARN", fmt.Sprintf("解析できない %d 行を読み飛ばしました: %s", skipped, path), nil)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("履歴の出力に失敗しました: %w", err)
+			}
+		}
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Println("記録された実行はありません")
+		return nil
+	}
+	for _, entry := range entries {
+		who := entry.User
+		if entry.Host != "" {
+			who += "@" + entry.Host
+		}
+		fmt.Printf("%s  %-9s %-8s %s  %s\n", entry.Time.Local().Format(report.MetadataTimeLayout), entry.Result, entry.Command, who, entry.Source)
+		if entry.Output != "" {
+			fmt.Printf("    出力: %s\n", entry.Output)
+		}
+		if entry.Error != "" {
+			fmt.Printf("    エラー: %s\n", entry.Error)
+		}
+	}
+	return nil
+}
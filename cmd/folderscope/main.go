@@ -3,66 +3,607 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
 
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
 	"FolderScope/internal/gui"
 	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/gist"
+	"FolderScope/internal/infrastructure/gitinfo"
+	"FolderScope/internal/infrastructure/history"
 	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/plugin"
+	"FolderScope/internal/infrastructure/state"
+	"FolderScope/internal/rpc"
+	"FolderScope/internal/usecase/diff"
+	"FolderScope/internal/usecase/query"
 	"FolderScope/internal/usecase/report"
+	"FolderScope/internal/usecase/report/sqlite"
 )
 
-func main() {
-	// ロガーの初期化
-	logger := logging.NewJSONLogger(os.Stdout)
+// stringList は複数回指定可能なコマンドラインオプションの値を保持します
+type stringList []string
 
-	// ファイルシステムスキャナーの初期化
-	scanner := filesystem.NewScanner(logger, nil, false)
+func (l *stringList) String() string { return strings.Join(*l, ",") }
 
-	// ディレクトリセレクターの初期化（Fyneベース）
-	selector := gui.NewDirectorySelector(scanner)
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
 
-	// レポートジェネレーターの初期化
-	generator := report.NewGenerator()
+// runConfig はコマンドラインオプションから組み立てた、レポート生成に必要な設定です
+type runConfig struct {
+	// scannerOptions のうち無視パターンとバイナリの扱いは、settings の内容で上書きされます
+	scannerOptions filesystem.ScannerOptions
+	// reportOptions のうち出力形式・サイズ上限・バイナリファイルの扱いは、settings の内容で上書きされます
+	reportOptions report.Options
+	// settings は GUI の設定画面で変更できる項目です。GUI を使用しない場合はコマンドラインオプションの値のままです
+	settings   *gui.Settings
+	writeIndex bool
+	// heartbeat は GUI を使用しない実行で、スキャン中の進捗をログに出力する間隔です。0 の場合は出力しません
+	heartbeat  time.Duration
+	exportGist bool
+	// contentFS はファイル内容の読み込み元です。アーカイブや git の参照をスキャンした場合に設定します
+	contentFS fs.FS
+	// gitRef は git の参照（タグ・ブランチ・コミットなど）です。指定された場合は、作業ツリーの代わりにその時点の内容をスキャンします
+	gitRef string
+	// patchPath はパッチ（unified diff）または git バンドルのファイルです。指定された場合は、調査対象のフォルダに変更を適用した後の、
+	// 変更されたファイルのみをスキャンします
+	patchPath string
+	// scanStats は直前のスキャンの統計情報です。データ形式のエクスポートに含めます
+	scanStats model.ScanStats
+	// commitTimes はファイルごとの最終コミット日時です。内容を git の更新順に並べる場合に設定します
+	commitTimes map[string]time.Time
+	// showAuthors は各ファイルのヘッダーに git の主な作成者を表示するかどうかを示します
+	showAuthors bool
+	// authors は相対パスごとの主な作成者の表示文字列です。showAuthors が有効な場合に設定します
+	authors map[string]string
+	// changedAgainst は git の参照（ブランチ名やコミットなど）です。指定された場合は、その参照から変更されたファイルのみを出力します
+	changedAgainst string
+	// hunksOnly は changedAgainst の指定時に、ファイルの本文の代わりに変更箇所のみを出力するかどうかを示します
+	hunksOnly bool
+	// hunks は相対パスごとの変更箇所です。hunksOnly が有効な場合に設定します
+	hunks map[string]string
+	// contentFilter はファイルの内容を出力前に加工する外部コマンドです。-pipe-content を指定した場合に設定します
+	contentFilter report.ContentFilter
+	// filterFallback は contentFilter が失敗した場合の扱いです
+	filterFallback report.FilterFallback
+	// where はレポートに含めるファイルの条件式です。-where を指定した場合に設定します
+	where *query.Query
+	// ignoreOrigins は -preset で追加した無視パターンと、そのプリセットの名前です。-explain で除外の理由を表示するために使用します
+	ignoreOrigins map[string]string
+	// sortKey と dirsFirst はフォルダ構成で同じフォルダ内のエントリを並べる順です
+	sortKey   report.SortKey
+	dirsFirst bool
+	// maskRules はファイル内容に適用する利用者定義のマスクのルールです。-mask, -mask-rules を指定した場合に設定します
+	maskRules report.MaskRules
+	// template はレポート全体の構成を決めるテンプレートです。-template を指定した場合に設定します
+	template *template.Template
+	// plugins はプラグインディレクトリから検出したプラグインです
+	plugins *plugin.Registry
+	// formatter はレポートを独自の形式に変換するプラグインです。-plugin-format を指定した場合に設定します
+	formatter *plugin.Plugin
+	// saveScanPath はスキャン結果を保存するファイルです。-save-scan を指定した場合に設定します
+	saveScanPath string
+	// toStdout はレポートをファイルではなく標準出力に書き込むかどうかを示します
+	toStdout bool
+	// outputFile は -output にファイル名を指定した場合の、出力先フォルダの中のレポートのファイル名です。
+	// 空の場合は日時を含む名前のファイルを作成します
+	outputFile string
+	// maxReportSize は出力前に見積もったレポートのサイズの上限（バイト）です。超える場合は askReportSize で対応を確認します。0 の場合は確認しません
+	maxReportSize int64
+	// askReportSize は見積もったレポートのサイズが上限を超える場合に、actions から対応を選択させます。nil の場合は端末で確認します
+	askReportSize func(message string, actions []reportSizeAction) reportSizeAction
+	// sourcePath と reportPath は実行履歴に記録する調査対象と、出力したレポートのパスです
+	sourcePath, reportPath string
+	// args は起動時のコマンドライン引数（プログラム名を除く）です。レポートの末尾に記載する再作成コマンドに使用します
+	args []string
+	// interactive は GUI で実行しているかどうかを示します。再作成コマンドには GUI で選択したフォルダと設定画面の値を指定します
+	interactive bool
+}
+
+// StdoutPath は標準出力に書き込んだレポートの出力先として、ログと実行履歴に記録する名前です
+const StdoutPath = "-"
+
+// nopWriteCloser は Close で何もしない WriteCloser です。ReportWriter の終了時に標準出力を閉じないために使用します
+type nopWriteCloser struct {
+	io.Writer
+}
 
-	// フォルダ選択処理の実行
-	dirs, err := gui.SelectDirectories(selector)
+func (nopWriteCloser) Close() error { return nil }
+
+// openOutput はレポートの出力先を圧縮の指定に応じて開き、バッファリングする ReportWriter と、その下位の圧縮する Writer、出力先のパスを返します。
+// toStdout が有効な場合はファイルを作成せず、標準出力に書き込みます。-output にファイル名を指定した場合は、そのファイルを上書きします。正規化した出力の場合は、毎回同じ名前のファイルを上書きします。formatter のプラグインを使用する場合は、プラグインの拡張子で作成します
+func (cfg *runConfig) openOutput(generator *report.Generator, outputDir string) (*report.ReportWriter, *report.OutputWriter, string, error) {
+	var (
+		output     *report.OutputWriter
+		outputPath string
+		err        error
+	)
+	switch {
+	case cfg.toStdout && cfg.formatter != nil:
+		output, err = report.NewOutputWriter(nopWriteCloser{os.Stdout}, cfg.reportOptions.Compression, "report"+cfg.formatter.Extension)
+		outputPath = StdoutPath
+	case cfg.toStdout:
+		output, err = generator.WrapOutput(nopWriteCloser{os.Stdout}, "report")
+		outputPath = StdoutPath
+	case cfg.outputFile != "":
+		outputPath = filepath.Join(outputDir, cfg.outputFile)
+		output, err = report.CreateNamedOutput(outputPath)
+	case cfg.reportOptions.Normalize && cfg.formatter == nil:
+		output, outputPath, err = generator.CreateNormalizedOutput(outputDir)
+	case cfg.formatter != nil:
+		output, outputPath, err = report.CreateOutput(outputDir, cfg.formatter.Extension, cfg.reportOptions.Compression)
+	default:
+		output, outputPath, err = generator.CreateOutput(outputDir)
+	}
 	if err != nil {
-		logger.Log("ERROR", "フォルダ選択に失敗", err)
-		log.Fatalf("エラー: %v", err)
+		return nil, nil, "", fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
 	}
+	return report.NewReportWriter(output), output, outputPath, nil
+}
 
-	sourceDir := dirs.Source
-	outputDir := dirs.Output
-	logger.Log("INFO", fmt.Sprintf("選択されたフォルダ - 調査対象: %s, 出力先: %s", sourceDir, outputDir), nil)
+// render はレポートを writer に出力します。formatter のプラグインを使用する場合は、JSONL 形式のエクスポートをプラグインで変換して出力します
+func (cfg *runConfig) render(generator *report.Generator, writer io.Writer, entries []model.FileSystemEntry) error {
+	if cfg.formatter == nil {
+		return generator.WriteReport(writer, entries)
+	}
+	return cfg.formatter.Format(context.Background(), writer, func(w io.Writer) error {
+		return generator.WriteReport(w, entries)
+	})
+}
 
-	// 出力ファイルの作成
-	outputFile, outputPath, err := generator.CreateOutputFile(outputDir)
+// loadGitInfo は、内容を git の更新順に並べる場合や作成者を表示する場合に、sourceDir の git の履歴から必要な情報を取得します。
+// 取得できない場合（git リポジトリではない場合など）は警告を記録し、その情報を使用せずに出力します
+func (cfg *runConfig) loadGitInfo(logger logging.Logger, sourceDir string) {
+	// アーカイブの内容には git の履歴がない
+	if cfg.contentFS != nil {
+		return
+	}
+	if cfg.reportOptions.ContentOrder == report.OrderGitRecent {
+		times, err := gitinfo.LastCommitTimes(context.Background(), sourceDir)
+		if err != nil {
+			logger.Log("WARN", "git の履歴を取得できないため、ファイル内容は相対パスの順で出力します", err)
+		} else {
+			cfg.commitTimes = times
+		}
+	}
+	if cfg.showAuthors {
+		authors, err := gitinfo.FileAuthors(context.Background(), sourceDir)
+		if err != nil {
+			logger.Log("WARN", "git の履歴を取得できないため、作成者は表示しません", err)
+			return
+		}
+		cfg.authors = make(map[string]string, len(authors))
+		for path, list := range authors {
+			cfg.authors[path] = gitinfo.FormatAuthors(list, gitinfo.DefaultAuthorLimit)
+		}
+	}
+}
+
+// newScanner は settings の内容を反映したスキャナーを作成します
+func (cfg *runConfig) newScanner(logger logging.Logger) *filesystem.Scanner {
+	cfg.scannerOptions.IgnorePatterns = cfg.settings.IgnorePatterns
+	cfg.scannerOptions.IgnoreBinaryFiles = cfg.settings.BinaryPolicy == string(report.BinaryOmit)
+	return cfg.newScannerWithOptions(logger)
+}
+
+// newScannerWithOptions は cfg.scannerOptions のとおりのスキャナーを作成します。
+// 正規表現フィルタはオプションの解析時に検証済みですが、誤りがあれば即座に終了します
+func (cfg *runConfig) newScannerWithOptions(logger logging.Logger) *filesystem.Scanner {
+	scanner, err := filesystem.NewScannerWithOptions(logger, cfg.scannerOptions)
 	if err != nil {
-		logger.Log("ERROR", "出力ファイルの作成に失敗", err)
-		log.Fatalf("エラー: %v", err)
+		invalidInputf("エラー: %v", err)
 	}
-	defer outputFile.Close()
-	logger.Log("INFO", "出力ファイルを作成しました", nil)
+	return scanner
+}
 
-	// フォルダ構造のスキャン
-	entries, err := scanner.Scan(context.Background(), sourceDir)
+// newGenerator は settings の内容を反映したレポートジェネレーターを作成します
+func (cfg *runConfig) newGenerator() (*report.Generator, error) {
+	format, err := report.ParseFormat(cfg.settings.Format)
+	if err != nil {
+		return nil, err
+	}
+	// formatter のプラグインには JSONL 形式のエクスポートを渡す
+	if cfg.formatter != nil {
+		format = report.FormatJSONL
+	}
+	binaryPolicy, err := report.ParseBinaryPolicy(cfg.settings.BinaryPolicy)
 	if err != nil {
-		logger.Log("ERROR", "フォルダ構造のスキャンに失敗", err)
-		log.Fatalf("エラー: %v", err)
+		return nil, err
+	}
+	options := cfg.reportOptions
+	options.Format = format
+	options.BinaryPolicy = binaryPolicy
+	options.MaxContentSize = cfg.settings.MaxFileSizeKB * 1024
+	generator := report.NewGeneratorWithOptions(options)
+	if cfg.contentFS != nil {
+		generator = generator.WithFS(cfg.contentFS)
+	}
+	if cfg.commitTimes != nil {
+		generator = generator.WithCommitTimes(cfg.commitTimes)
+	}
+	if cfg.authors != nil {
+		generator = generator.WithAuthors(cfg.authors)
+	}
+	if cfg.hunks != nil {
+		generator = generator.WithHunks(cfg.hunks)
+	}
+	if cfg.contentFilter != nil {
+		generator = generator.WithContentFilter(cfg.contentFilter, cfg.filterFallback)
 	}
-	logger.Log("INFO", "フォルダ構造のスキャンが完了しました", nil)
+	if len(cfg.maskRules) > 0 {
+		generator = generator.WithMaskRules(cfg.maskRules)
+	}
+	if cfg.template != nil {
+		generator = generator.WithTemplate(cfg.template)
+	}
+	if !cfg.scanStats.StartedAt.IsZero() {
+		generator = generator.WithScanStats(cfg.scanStats)
+	}
+	return generator, nil
+}
+
+// reportResult はレポート出力の結果です
+type reportResult struct {
+	outputPath string
+	// gistURL は Gist へエクスポートした場合の URL です
+	gistURL string
+	// deltaTokens は -changed-against の指定時の、変更のみのレポートと全体のレポートのトークン数です
+	deltaTokens *report.DeltaTokens
+}
+
+// writeReport は entries からレポートファイルを作成し、指定に応じてインデックスの出力と Gist へのエクスポートを行います。
+// ctx がキャンセルされた場合は、ファイル内容の出力を中止して中止の理由をレポートの末尾に記載します
+func writeReport(ctx context.Context, logger logging.Logger, cfg *runConfig, entries []model.FileSystemEntry, sourceDir, outputDir string) (reportResult, error) {
+	var result reportResult
+	cfg.sourcePath = sourceDir
+
+	// 条件式と変更されたファイルへの絞り込みと並べ替え
+	entries = report.SortEntries(cfg.selectEntries(logger, entries), cfg.sortKey, cfg.dirsFirst)
+	all := entries
+	entries, err := cfg.restrictToChanged(ctx, logger, entries, sourceDir)
+	if err != nil {
+		return result, err
+	}
+
+	// レポートジェネレーターの初期化
+	cfg.loadGitInfo(logger, sourceDir)
+	cfg.reportOptions.ReproduceCommand = cfg.reproduceCommand(sourceDir, outputDir)
+	generator, err := cfg.newGenerator()
+	if err != nil {
+		return result, err
+	}
+	// 大きすぎるレポートを書き込む前に、切り詰めなどの対応を確認する
+	if generator, err = cfg.preflightReportSize(logger, generator, entries); err != nil {
+		return result, err
+	}
+	// 中止された場合は、出力したところまでのレポートの末尾に中止の理由を記載する
+	generator = generator.WithContext(ctx)
+
+	// sqlite 形式は出力先フォルダの同じデータベースにスキャン結果を追加する
+	if format, _ := report.ParseFormat(cfg.settings.Format); format == report.FormatSQLite && !cfg.toStdout && cfg.formatter == nil {
+		outputPath, err := appendSQLite(logger, generator, entries, outputDir)
+		if err != nil {
+			return result, err
+		}
+		result.outputPath = outputPath
+		cfg.reportPath = outputPath
+		return result, nil
+	}
+
+	// 出力先の作成
+	output, compressed, outputPath, err := cfg.openOutput(generator, outputDir)
+	if err != nil {
+		return result, err
+	}
+	defer output.Close()
+	if !cfg.toStdout {
+		logger.Log("INFO", "出力ファイルを作成しました", nil)
+	}
+	result.outputPath = outputPath
+	cfg.reportPath = outputPath
 
 	// レポートの生成
-	generator.WriteFileSystemStructure(outputFile, entries)
-	generator.WriteFileContents(outputFile, entries)
-	logger.Log("INFO", fmt.Sprintf("レポートを生成しました: %s", outputPath), nil)
+	reportWriter := report.NewIndexingWriter(output)
+	if err := cfg.render(generator, reportWriter, entries); err != nil {
+		return result, err
+	}
+	// zip の場合、インデックスはレポートと同じ出力ファイルにまとめる
+	bundleIndex := cfg.writeIndex && compressed.Bundles()
+	if bundleIndex {
+		if err := addIndexToBundle(output, compressed, reportWriter.Entries()); err != nil {
+			return result, err
+		}
+	}
+	if err := output.Close(); err != nil {
+		return result, fmt.Errorf("出力ファイルの書き込みに失敗しました: %w", err)
+	}
+	logger.Log("INFO", fmt.Sprintf("レポートのサイズ: %s", report.FormatSize(output.Written())), nil, "bytes", output.Written())
+	if cfg.toStdout {
+		logger.Log("INFO", "レポートを標準出力に書き込みました", nil, "bytes", output.Written())
+	} else {
+		logger.Log("INFO", fmt.Sprintf("レポートを生成しました: %s", outputPath), nil, "path", outputPath, "bytes", output.Written())
+	}
+
+	// 変更のみのレポートと、すべてのファイルを出力した場合のトークン数の比較
+	if format, _ := report.ParseFormat(cfg.settings.Format); cfg.changedAgainst != "" && cfg.formatter == nil && format != report.FormatPDF {
+		tokens, err := generator.CompareDeltaTokens(all, reportWriter.Written())
+		if err != nil {
+			logger.Log("WARN", "全体のレポートのトークン数の見積もりに失敗しました", err)
+		} else {
+			logger.Log("INFO", fmt.Sprintf("変更のみのレポートのトークン数: 約 %d（全体のレポート: 約 %d, %.1f%%）", tokens.Delta, tokens.Full, tokens.Percent()), nil)
+			result.deltaTokens = &tokens
+		}
+	}
+
+	// インデックスファイルの出力
+	if cfg.writeIndex && !bundleIndex {
+		if err := writeIndexFile(logger, outputPath, reportWriter.Entries()); err != nil {
+			return result, err
+		}
+	}
+
+	// Gistへのエクスポート
+	if cfg.exportGist {
+		content, err := os.ReadFile(outputPath)
+		if err != nil {
+			return result, fmt.Errorf("レポートの読み込みに失敗しました: %w", err)
+		}
+		exporter := gist.NewExporter(os.Getenv(gist.TokenEnvVar))
+		baseName := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+		url, err := exporter.Export(context.Background(), baseName, fmt.Sprintf("FolderScope report: %s", filepath.Base(sourceDir)), content)
+		if err != nil {
+			return result, fmt.Errorf("Gistへのエクスポートに失敗しました: %w", err)
+		}
+		logger.Log("INFO", fmt.Sprintf("Gistを作成しました: %s", url), nil)
+		fmt.Printf("Gist URL: %s\n", url)
+		result.gistURL = url
+	}
+	return result, nil
+}
+
+// appendSQLite は出力先フォルダのデータベース（folderscope.sqlite）にスキャン結果を追加し、データベースのパスを返します
+func appendSQLite(logger logging.Logger, generator *report.Generator, entries []model.FileSystemEntry, outputDir string) (string, error) {
+	dbPath := filepath.Join(outputDir, sqlite.DatabaseName)
+	scanID, err := sqlite.Append(generator, dbPath, entries)
+	if err != nil {
+		return "", err
+	}
+	logger.Log("INFO", fmt.Sprintf("スキャン結果をデータベースに追加しました（scan_id: %d）: %s", scanID, dbPath), nil)
+	return dbPath, nil
+}
 
-	logger.Log("INFO", "処理が完了しました", nil)
-	log.Printf("処理が完了しました。出力先: %s\n", outputPath)
+// userTemplateDir は組み込みのテンプレートを上書きするテンプレートを置くディレクトリ（例: ~/.config/folderscope/templates）を返します。
+// ユーザー設定ディレクトリを決定できない場合は空文字列を返し、組み込みのテンプレートのみを使用します
+func userTemplateDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, state.AppDirName, report.TemplateDirName)
+}
+
+// dateTimeLayouts は -modified-after・-modified-before で受け付ける日時の形式です。タイムゾーンのない形式はローカル時刻として扱います
+var dateTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// parseDateTime は日時の文字列を dateTimeLayouts のいずれかの形式として解釈します
+func parseDateTime(s string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("日時 %q は 2006-01-02、2006-01-02 15:04 または RFC 3339 形式で指定してください", s)
+}
 
-	// プログラム終了前にEnterキーの入力を待機
-	fmt.Print("\nEnterキーを押して終了してください...")
-	fmt.Scanln()
+// pdfFontCandidates は -pdf-font を省略した場合に pdf 形式で使用する、日本語を表示できる TrueType フォントの候補です
+var pdfFontCandidates = []string{
+	"/usr/share/fonts/opentype/ipafont-gothic/ipag.ttf",
+	"/usr/share/fonts/truetype/fonts-japanese-gothic.ttf",
+	"/usr/share/fonts/truetype/takao-gothic/TakaoGothic.ttf",
+	"/usr/share/fonts/ipa-gothic/ipag.ttf",
+	"/Library/Fonts/Arial Unicode.ttf",
+	`C:\Windows\Fonts\ipag.ttf`,
+}
+
+// findPDFFont は pdfFontCandidates のうち最初に見つかったフォントファイルのパスを返します。見つからない場合は空文字列を返します
+func findPDFFont() string {
+	for _, candidate := range pdfFontCandidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// addIndexToBundle はレポートの書き込みを終えた zip の出力ファイルに、レポートに対応するインデックスを追加します
+func addIndexToBundle(output *report.ReportWriter, bundle *report.OutputWriter, entries []report.IndexEntry) error {
+	if err := output.Flush(); err != nil {
+		return fmt.Errorf("出力ファイルの書き込みに失敗しました: %w", err)
+	}
+	data, err := report.EncodeIndex(report.Index{Report: bundle.ReportName(), Entries: entries})
+	if err != nil {
+		return err
+	}
+	return bundle.AddFile(bundle.ReportName()+report.IndexFileSuffix, data)
+}
+
+// writeIndexFile はレポートに対応するインデックスファイルを出力します
+func writeIndexFile(logger logging.Logger, outputPath string, entries []report.IndexEntry) error {
+	indexPath := outputPath + report.IndexFileSuffix
+	index := report.Index{Report: filepath.Base(outputPath), Entries: entries}
+	if err := report.WriteIndexFile(indexPath, index); err != nil {
+		return fmt.Errorf("インデックスファイルの出力に失敗しました: %w", err)
+	}
+	logger.Log("INFO", fmt.Sprintf("インデックスファイルを出力しました: %s", indexPath), nil)
+	return nil
+}
+
+func main() {
+	// サブコマンドは独自のオプションを持つため、通常のオプションより先に判定する
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Printf("エラー: %v", err)
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+	}
+
+	opts := parseOptions(os.Args[1:])
+	logLevel := opts.logLevel()
+	if opts.stdioMode {
+		runStdioMode(logLevel)
+		return
+	}
+	cfg := opts.newRunConfig()
+	logger := opts.newLogger(logLevel)
+	if cfg.settings.Format == string(report.FormatPDF) && cfg.reportOptions.PDFFont == "" {
+		logger.Log("WARN", "日本語を表示できるフォントが見つからないため、PDF の標準フォントを使用します。英数字以外の文字は '.' で表示されます（-pdf-font でフォントを指定できます）", nil)
+	}
+	opts.loadPlugins(logger, cfg)
+
+	switch {
+	case len(opts.explainPaths) > 0:
+		runExplainMode(logger, cfg, opts.sourceDir, opts.explainPaths)
+	case opts.estimateMode:
+		runEstimateMode(logger, cfg, opts.sourceDir, opts.timeout)
+	case opts.renderCommand:
+		runRenderMode(logger, cfg, opts.fromScan, opts.outputDir, opts.timeout)
+	case opts.headless():
+		runHeadlessMode(logger, cfg, opts)
+	default:
+		runGUIMode(logger, cfg)
+	}
+}
+
+// runStdioMode はエディタ連携用の stdio JSON-RPC サーバーとして実行します。
+// 標準出力をプロトコル通信に使用するため、ログは標準エラー出力に書き込みます
+func runStdioMode(logLevel slog.Level) {
+	logger := logging.NewJSONLoggerWithOptions(os.Stderr, logging.Options{Level: logLevel})
+	server := rpc.NewServer(logger, report.NewGenerator())
+	if err := server.Serve(context.Background(), os.Stdin, os.Stdout); err != nil {
+		logger.Log("ERROR", "JSON-RPCサーバーが異常終了しました", err)
+		os.Exit(1)
+	}
+}
+
+// runExplainMode は paths（sourceDir からの相対パス）がレポートに含まれるかどうかと、除外の理由を表示します。
+// スキャンとレポートの出力を行わないため、実行履歴には記録しません
+func runExplainMode(logger *logging.RecentLogger, cfg *runConfig, sourceDir string, paths []string) {
+	if err := runExplain(logger, cfg, sourceDir, paths); err != nil {
+		exitOnError(logger, "除外の理由の判定に失敗", err)
+	}
+}
+
+// runEstimateMode はファイル内容を読み込まずに見積もったレポートの規模を表示します。
+// レポートを出力しないため、実行履歴には記録しません
+func runEstimateMode(logger *logging.RecentLogger, cfg *runConfig, sourceDir string, timeout time.Duration) {
+	defer recoverCrash(logger, cfg, "")
+	ctx, stop := runContext(timeout)
+	err := withCancelReason(ctx, runEstimate(ctx, logger, cfg, sourceDir))
+	stop()
+	exitOnError(logger, "レポートの規模の見積もりに失敗", err)
+}
+
+// runRenderMode は render で、fromPath に保存したスキャン結果から outputDir にレポートを出力します
+func runRenderMode(logger *logging.RecentLogger, cfg *runConfig, fromPath, outputDir string, timeout time.Duration) {
+	defer recoverCrash(logger, cfg, outputDir)
+	ctx, stop := runContext(timeout)
+	started := time.Now()
+	err := withCancelReason(ctx, runRender(ctx, logger, cfg, fromPath, outputDir))
+	stop()
+	recordRun(logger, history.Entry{Command: "render", Source: cfg.sourcePath, Output: cfg.reportPath}, started, err)
+	exitOnError(logger, "レポートの生成に失敗", err)
+}
+
+// runHeadlessMode は GUI を使用せずに、指定に応じて差分レポートの出力・変更の監視・レポートの出力を行います（Ctrl+C・SIGTERM・-timeout で中断）
+func runHeadlessMode(logger *logging.RecentLogger, cfg *runConfig, opts *cliOptions) {
+	defer recoverCrash(logger, cfg, opts.outputDir)
+	ctx, stop := runContext(opts.timeout)
+	started := time.Now()
+	var (
+		command string
+		err     error
+	)
+	switch {
+	case opts.diffDir != "":
+		command = "diff"
+		err = runDiff(ctx, logger, cfg, opts.sourceDir, opts.diffDir, opts.outputDir, opts.diffMethod, diff.ReportOptions{Unified: opts.unified, Summary: opts.diffSummary})
+	case opts.watchMode:
+		command = "watch"
+		err = runWatch(ctx, logger, cfg, opts.sourceDir, opts.outputDir)
+	default:
+		command = "report"
+		err = runHeadless(ctx, logger, cfg, opts.sourceDir, opts.outputDir)
+	}
+	err = withCancelReason(ctx, err)
+	stop()
+	recordRun(logger, history.Entry{Command: command, Source: opts.sourceDir, Output: cfg.reportPath}, started, err)
+	exitOnError(logger, "レポートの生成に失敗", err)
+}
+
+// runGUIMode はフォルダ選択から完了までを 1 つのウィンドウで実行します。
+// 起動中の GUI がある場合（コンテキストメニューからの 2 回目の起動など）は、引数の調査対象フォルダを渡して終了します
+func runGUIMode(logger *logging.RecentLogger, cfg *runConfig) {
+	// ディレクトリセレクターの初期化（Fyneベース）
+	// フォルダの検証はスキャンの設定に依存しないため、設定画面の変更前のスキャナーを使用する
+	selector := gui.NewDirectorySelector(cfg.newScannerWithOptions(logger))
+
+	requested := ""
+	if flag.NArg() == 1 {
+		abs, err := filepath.Abs(flag.Arg(0))
+		if err != nil {
+			log.Fatalf("エラー: フォルダのパスの解決に失敗しました: %v", err)
+		}
+		requested = abs
+	}
+	listener, forwarded := acquireInstance(logger, requested)
+	if forwarded {
+		return
+	}
+
+	// エラーはウィンドウに表示してから、ウィンドウを閉じた後に終了する
+	ui := gui.NewWindow("FolderScope")
+	if listener != nil {
+		defer listener.Close()
+		go func() {
+			if err := listener.Serve(ui.RequestFolder); err != nil {
+				logger.Log("WARN", "他のプロセスからの調査対象フォルダの受け付けを終了しました", err)
+			}
+		}()
+	}
+	cfg.interactive = true
+	var runErr error
+	ui.Run(func() {
+		defer recoverCrash(logger, cfg, "")
+		started := time.Now()
+		runErr = runGUI(ui, logger, cfg, selector, requested)
+		// フォルダを選択せずに終了した場合は何も実行していないため記録しない
+		if cfg.sourcePath != "" {
+			recordRun(logger, history.Entry{Command: "gui", Source: cfg.sourcePath, Output: cfg.reportPath}, started, runErr)
+		}
+		if runErr != nil && !errors.Is(runErr, apperrors.ErrCancelled) {
+			ui.ShowMessage(errorTitle(runErr), runErr.Error())
+		}
+	})
+	if errors.Is(runErr, apperrors.ErrCancelled) {
+		logger.Log("INFO", "フォルダ選択がキャンセルされたため終了します", nil)
+		return
+	}
+	exitOnError(logger, "レポートの生成に失敗", runErr)
 }
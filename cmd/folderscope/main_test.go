@@ -0,0 +1,13 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// reproduceCommand などはオプションの種類（値を取るかどうか）を flag.CommandLine から判定するため、実行時と同じオプションを登録する
+	new(cliOptions).register(flag.CommandLine)
+	os.Exit(m.Run())
+}
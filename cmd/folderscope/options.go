@@ -0,0 +1,622 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"FolderScope/internal/domain/rules"
+	"FolderScope/internal/gui"
+	"FolderScope/internal/infrastructure/archive"
+	"FolderScope/internal/infrastructure/crash"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/pipe"
+	"FolderScope/internal/infrastructure/plugin"
+	"FolderScope/internal/usecase/config"
+	"FolderScope/internal/usecase/diff"
+	"FolderScope/internal/usecase/query"
+	"FolderScope/internal/usecase/report"
+)
+
+// cliOptions はサブコマンド以外の実行（GUI、GUI を使用しない実行、render、-estimate、-explain、-stdio）のコマンドラインオプションです
+type cliOptions struct {
+	// renderCommand は最初の引数に render を指定したかどうかを示します
+	renderCommand bool
+
+	// スキャンの条件
+	ignorePatterns, ignorePresets, includeRegexps, excludeRegexps                                           stringList
+	ignoreBinary, includeHidden, computeHash, dedupHardLinks                                                bool
+	symlinkPolicyName, accessPolicyName, minSizeSpec, maxSizeSpec, modifiedAfter, modifiedBefore, rulesPath string
+	hashWorkers                                                                                             int
+
+	// レポートの内容
+	binaryPolicyName, compressName, maxReportSizeSpec, sizeTiersSpec, whereExpr, formatName string
+	sortKey, treeStyle, contentOrder, maskRulesPath, pdfFont, templateName                  string
+	binaryEmbedLimitKB, maxFileSizeKB                                                       int64
+	headLines, tailLines, htmlPageSize                                                      int
+	dirsFirst, showAuthors, showSummary, showLanguages, showLicenses, auditEncoding         bool
+	highlight, showMetrics, lineNumbers, noRedact, longListing, showMetadata, normalize     bool
+	maskPresets                                                                             stringList
+
+	// 実行方法と出力先
+	sourceDir, outputDir, diffDir, diffBy, gitRef, changedAgainst, patchPath, fromScan, saveScanPath      string
+	writeIndex, exportGist, toStdout, estimateMode, stdioMode, watchMode, unified, diffSummary, hunksOnly bool
+	explainPaths                                                                                          stringList
+	heartbeat, timeout                                                                                    time.Duration
+
+	// ログ
+	logLevelName                string
+	verbose, veryVerbose, quiet bool
+
+	// 外部コマンドとプラグイン
+	pipeContent, pipeFallback, pluginsDir, pluginFormat string
+	pipeTimeout                                         time.Duration
+	pluginFilters                                       stringList
+
+	// diffMethod は -diff-by を解析した差分モードでの変更の判定方法です。newRunConfig で設定します
+	diffMethod diff.Method
+}
+
+// parseOptions は args（プログラム名を除くコマンドライン引数）を解析します。最初の引数が render の場合は render のオプションとして解析します。
+// 誤りがある場合は終了コード exitInvalidInput で終了します
+func parseOptions(args []string) *cliOptions {
+	// render は保存したスキャン結果からレポートを出力し直すコマンドで、通常の実行と同じオプションを使用する
+	o := &cliOptions{renderCommand: len(args) > 0 && args[0] == "render"}
+	if o.renderCommand {
+		args = args[1:]
+	}
+	o.register(flag.CommandLine)
+	parseFlags(flag.CommandLine, args)
+	return o
+}
+
+// register はオプションを flags に登録します
+func (o *cliOptions) register(flags *flag.FlagSet) {
+	flags.Var(&o.ignorePatterns, "ignore", "デフォルトに追加して無視するファイル・ディレクトリ名のパターン（複数指定可）")
+	flags.Var(&o.ignorePresets, "preset", "依存パッケージやビルド成果物などのフォルダを無視する組み込みのパターン（"+strings.Join(filesystem.IgnorePresetNames(), ", ")+"、複数指定可）")
+	flags.BoolVar(&o.ignoreBinary, "ignore-binary", false, "バイナリファイルをレポートから除外する（-binary omit と同じ）")
+	flags.StringVar(&o.symlinkPolicyName, "symlinks", string(filesystem.SymlinkLink), "シンボリックリンクの扱い（link: リンクとして記録しディレクトリへのリンクはたどらない, skip: 除外, follow: リンク先をたどる（親フォルダを指す循環するリンクはたどらない））")
+	flags.StringVar(&o.accessPolicyName, "on-access-error", string(filesystem.AccessReport), "権限がないために読み込めなかったフォルダの扱い（skip: 配下をスキップしログにのみ記録, report: 配下をスキップしレポートに一覧を記載, fatal: スキャンを中止してエラーにする）")
+	flags.BoolVar(&o.includeHidden, "hidden", true, "隠しファイル・隠しフォルダ（名前が '.' で始まるもの、Windows で隠し属性を持つもの）をスキャンする（-hidden=false で配下も含めて除外）")
+	flags.StringVar(&o.binaryPolicyName, "binary", string(report.BinarySkip), "バイナリファイルの扱い（skip: 構成に表示せず内容を省略, omit: 除外, structure: 構成にのみ表示, hexdump: 先頭を16進ダンプで出力, base64: Base64で埋め込む）")
+	flags.StringVar(&o.compressName, "compress", string(report.CompressionNone), "レポートの圧縮方式（none, gzip: .gz で圧縮, zip: レポートとインデックスを 1 つの .zip にまとめる）")
+	flags.Int64Var(&o.binaryEmbedLimitKB, "binary-embed-limit", report.DefaultBinaryEmbedLimit/1024, "-binary base64 で埋め込むファイルサイズの上限（KB）")
+	flags.Int64Var(&o.maxFileSizeKB, "max-file-size", 0, "内容を出力するファイルサイズの上限（KB、0で無制限）")
+	flags.IntVar(&o.headLines, "head-lines", 0, "先頭と末尾のみを出力するファイルで出力する先頭の行数（0で既定の60行）。-size-tiers を指定しない場合は、64KBを超えるテキストファイルを先頭と末尾のみにする")
+	flags.IntVar(&o.tailLines, "tail-lines", 0, "先頭と末尾のみを出力するファイルで出力する末尾の行数（0で既定の20行）。-size-tiers を指定しない場合は、64KBを超えるテキストファイルを先頭と末尾のみにする")
+	flags.StringVar(&o.maxReportSizeSpec, "max-report-size", "100MB", "出力前に見積もったレポートのサイズがこの値を超える場合に、切り詰めなどの対応を確認する（例: \"50MB\"、0 で確認しない）")
+	flags.StringVar(&o.sizeTiersSpec, "size-tiers", "", "ファイルサイズの段階ごとの内容の出力方法（例: \"64KB:full,1MB:headtail,*:structure\"。full: すべて, headtail: 先頭と末尾のみ, outline: 関数・クラスなどの宣言のみ, skip: 省略, structure: 構成にのみ表示）")
+	flags.Var(&o.includeRegexps, "include", "相対パスに一致するファイルのみを含める正規表現（複数指定可）")
+	flags.Var(&o.excludeRegexps, "exclude", "相対パスに一致するファイル・ディレクトリを除外する正規表現（複数指定可）")
+	flags.StringVar(&o.minSizeSpec, "min-size", "", "このサイズより小さいファイルを内容を読み込まずに除外する（例: 1KB）")
+	flags.StringVar(&o.maxSizeSpec, "max-size", "", "このサイズより大きいファイルを内容を読み込まずに除外する（例: 10MB）")
+	flags.StringVar(&o.modifiedAfter, "modified-after", "", "この日時より前に更新されたファイルを除外する（2006-01-02 または RFC 3339 形式）")
+	flags.StringVar(&o.modifiedBefore, "modified-before", "", "この日時以降に更新されたファイルを除外する（2006-01-02 または RFC 3339 形式）")
+	flags.StringVar(&o.rulesPath, "rules", "", "パスごとにファイルの扱い（exclude, full, headtail, outline, skip, structure）を指定するルールを定義した JSON・YAML ファイル")
+	flags.StringVar(&o.whereExpr, "where", "", "条件式を満たすファイルのみを含める（例: \"size < 1MB and not path matches '^vendor/'\"）")
+	flags.StringVar(&o.formatName, "format", string(report.FormatText), "レポートの出力形式（text, markdown, html, json, jsonl, xml, yaml, pdf, sqlite）。sqlite は出力先フォルダの folderscope.sqlite にスキャン結果を追加する")
+	flags.StringVar(&o.sortKey, "sort", string(report.SortPath), "フォルダ構成で同じフォルダ内のエントリを並べる順（path: 名前の順, size: サイズの大きい順, mtime: 更新日時の新しい順）")
+	flags.StringVar(&o.treeStyle, "tree-style", string(report.TreeIndent), "フォルダ構成の描画方法（indent: 字下げと [DIR]/[FILE], tree: tree コマンドのような罫線）")
+	flags.BoolVar(&o.dirsFirst, "dirs-first", false, "フォルダ構成で同じフォルダ内のフォルダをファイルより先に並べる")
+	flags.StringVar(&o.contentOrder, "order", string(report.OrderPath), "ファイル内容の並び順（path: 相対パス順, git-recent: 最終コミット日時の新しい順）")
+	flags.BoolVar(&o.showAuthors, "authors", false, "各ファイルのヘッダーに git の履歴から主な作成者を表示する")
+	flags.IntVar(&o.htmlPageSize, "html-page-size", 100, "HTML形式で1ページに含めるファイル数（0でページ分割しない）")
+	flags.BoolVar(&o.showSummary, "summary", false, "レポート冒頭にファイル数・合計サイズ・拡張子別などのサマリーを出力する")
+	flags.BoolVar(&o.showLanguages, "languages", false, "レポート冒頭に言語ごとのファイル数と行数（空行・コメント・コード）の統計を出力する")
+	flags.BoolVar(&o.showLicenses, "licenses", false, "レポート冒頭に LICENSE・COPYING などのライセンスファイルと判定したライセンスの種類を出力する")
+	flags.BoolVar(&o.auditEncoding, "audit-encoding", false, "レポート冒頭に文字コード・改行コードの集計と、主な文字コード・改行コードとそろっていないファイル（LF のプロジェクトの CRLF のファイルなど）を出力する")
+	flags.BoolVar(&o.highlight, "highlight", false, "ファイル内容を言語に応じて色付けする（HTML形式、およびテキスト形式ではANSIエスケープシーケンス）")
+	flags.BoolVar(&o.showMetrics, "metrics", false, "各ファイルのヘッダーに行数・コメント率・関数の数の目安を表示する")
+	flags.BoolVar(&o.lineNumbers, "line-numbers", false, "ファイル内容の各行の先頭に行番号を付ける")
+	flags.Var(&o.maskPresets, "mask", "ファイル内容のうち組み込みのルールに一致した部分をマスクする（"+strings.Join(report.PresetMaskRuleNames(), ", ")+"、複数指定可）")
+	flags.StringVar(&o.maskRulesPath, "mask-rules", "", "ファイル内容をマスクするルール（正規表現と置き換える文字列）を定義した JSON・YAML ファイル")
+	flags.BoolVar(&o.noRedact, "no-redact", false, "ファイル内容に含まれる秘密情報（アクセスキー・秘密鍵・トークン・パスワードなど）をマスクしない")
+	flags.BoolVar(&o.longListing, "long", false, "フォルダ構成の各行の先頭に、ls -l のようにパーミッション・所有者・グループ・サイズ・更新日時を表示する（所有者とグループは Unix のみ）")
+	flags.BoolVar(&o.showMetadata, "metadata", false, "フォルダ構成にサイズ・更新日時・パーミッションを表示する")
+	flags.BoolVar(&o.computeHash, "hash", false, "ファイルごとにSHA-256ハッシュを計算してレポートに含める")
+	flags.IntVar(&o.hashWorkers, "hash-workers", 0, "ハッシュを並行して計算するワーカーの数（0で CPU 数）")
+	flags.BoolVar(&o.normalize, "normalize", false, "リポジトリにコミットして git diff で比較できるよう、作成日時・更新日時・スキャンの所要時間を含めず、相対パスの順・LF の改行で出力する（出力先フォルダの folderscope.<拡張子> を上書きする）")
+	flags.StringVar(&o.pdfFont, "pdf-font", "", "pdf 形式で使用する TrueType フォントファイル（.ttf）。省略時は日本語のフォントを既定の場所から探し、見つからない場合は英数字のみ表示できる標準フォントを使用する")
+	flags.BoolVar(&o.dedupHardLinks, "dedup-hardlinks", false, "同じ inode を共有するファイル（ハードリンク）は、最初のファイルのみ内容を出力する（Unix のみ。ハードリンクはオプションに関わらずフォルダ構成に表示する）")
+	flags.BoolVar(&o.writeIndex, "index", false, "各ファイルセクションのバイト位置を記録したインデックスファイルを出力する")
+	flags.DurationVar(&o.heartbeat, "heartbeat", filesystem.DefaultHeartbeatInterval, "GUIを使用しない実行で、スキャン中の進捗をログに出力する間隔（0で無効）")
+	flags.BoolVar(&o.exportGist, "gist", false, "生成したレポートをシークレットGistとしてアップロードする（環境変数 GITHUB_TOKEN が必要）")
+	flags.StringVar(&o.fromScan, "from", "", "render で使用する、-save-scan で保存したスキャン結果のファイル")
+	flags.StringVar(&o.saveScanPath, "save-scan", "", "スキャン結果（エントリと内容の参照先）を保存するファイル。render -from で再びスキャンせずに別の形式や条件で出力できる")
+	flags.BoolVar(&o.toStdout, "stdout", false, "レポートをファイルを作成せずに標準出力に書き込む（-source が必要、ログは標準エラー出力に書き込む）")
+	flags.Var(&o.explainPaths, "explain", "レポートを生成せずに、指定したパス（-source からの相対パス）がレポートに含まれるかどうかと、除外した無視パターン・オプションを表示する（複数指定可、-source が必要）")
+	flags.BoolVar(&o.estimateMode, "estimate", false, "レポートを生成せずに、ファイル内容を読み込まずに見積もった出力形式ごとのサイズ・トークン数とファイル数を表示する（-source が必要）")
+	flags.BoolVar(&o.stdioMode, "stdio", false, "エディタ連携用のstdio JSON-RPCサーバーとして起動する")
+	flags.StringVar(&o.sourceDir, "source", "", "調査対象フォルダまたはアーカイブ（.zip, .tar, .tar.gz, .7z）。-output と併用すると GUI を使用せずに実行する")
+	flags.StringVar(&o.outputDir, "output", "", "レポートの出力先フォルダ、またはレポートのファイル名（-source と併用。ファイル名の場合は拡張子から出力形式を判定する）")
+	flags.BoolVar(&o.watchMode, "watch", false, "調査対象フォルダの変更を監視し、レポートを自動的に再生成する（-source と -output が必要）")
+	flags.StringVar(&o.diffDir, "diff", "", "-source（比較元）と比較するフォルダ。指定すると差分レポートを出力する（-output が必要）")
+	flags.StringVar(&o.diffBy, "diff-by", string(diff.MethodHash), "差分モードでの変更の判定方法（hash, mtime）")
+	flags.BoolVar(&o.unified, "unified", false, "差分モードで、変更されたテキストファイルの内容の差分を unified 形式で出力する")
+	flags.BoolVar(&o.diffSummary, "diff-summary", false, "差分モードで、差分の一覧の前に変更の件数とフォルダごとの変更の件数をまとめた概要（LLM へのレビュー依頼向け）を出力する")
+	flags.StringVar(&o.gitRef, "git-ref", "", "作業ツリーの代わりに、指定した git の参照（タグ・ブランチ・コミットなど）の時点の内容を、チェックアウトせずに git のオブジェクトから読み込んでスキャンする")
+	flags.StringVar(&o.changedAgainst, "changed-against", "", "指定した git の参照（ブランチ名やコミットなど）から変更されたファイルのみを出力する")
+	flags.BoolVar(&o.hunksOnly, "hunks-only", false, "-changed-against または -patch の指定時に、ファイルの本文の代わりに git diff・パッチの変更箇所のみを出力する")
+	flags.StringVar(&o.patchPath, "patch", "", "パッチ（git diff・git format-patch・diff -u の出力）または git バンドル（.bundle）を -source のフォルダに適用し、変更されたファイルの変更後の内容のみを出力する")
+	flags.StringVar(&o.pipeContent, "pipe-content", "", "各ファイルの内容を標準入力で渡し、標準出力を内容として出力する外部コマンド（相対パスは環境変数 FOLDERSCOPE_PATH で参照可能）")
+	flags.StringVar(&o.logLevelName, "log-level", "info", "出力するログの最も低いレベル（trace, debug, info, warn, error）")
+	flags.BoolVar(&o.verbose, "v", false, "除外したパスと一致したパターンもログに出力する（-log-level debug と同じ）")
+	flags.BoolVar(&o.veryVerbose, "vv", false, "記録したパスを含め、パスごとの判定をすべてログに出力する（-log-level trace と同じ）")
+	flags.BoolVar(&o.quiet, "quiet", false, "エラーのみをログに出力する（-log-level error と同じ）")
+	flags.DurationVar(&o.timeout, "timeout", 0, "GUIを使用しない実行の実行時間の上限（例: 10m、0で無制限）。超えた場合は中止し、終了コード 4 で終了します")
+	flags.DurationVar(&o.pipeTimeout, "pipe-timeout", pipe.DefaultTimeout, "-pipe-content の 1 ファイルあたりの実行時間の上限")
+	flags.StringVar(&o.pipeFallback, "pipe-fallback", string(report.FallbackOriginal), "-pipe-content が失敗した場合の扱い（original: 加工前の内容を出力, skip: 内容を出力しない）")
+	flags.StringVar(&o.pluginsDir, "plugins-dir", "", "プラグインを検出するディレクトリ（既定: ユーザー設定ディレクトリの folderscope/plugins）")
+	flags.StringVar(&o.templateName, "template", "", "レポート全体の構成を決める Go の text/template ファイル、または組み込みのテンプレート名（"+strings.Join(report.BuiltinTemplateNames(), ", ")+"）")
+	flags.StringVar(&o.pluginFormat, "plugin-format", "", "レポートを指定した名前の formatter プラグインで出力する（-source と -output が必要）")
+	flags.Var(&o.pluginFilters, "plugin-filter", "ファイルの内容を指定した名前の filter プラグインで加工する（複数指定可、指定順に適用）")
+}
+
+// logLevel は -log-level・-v・-vv・-quiet の指定から、出力するログの最も低いレベルを返します
+func (o *cliOptions) logLevel() slog.Level {
+	logLevel, err := logging.ParseLevel(o.logLevelName)
+	if err != nil {
+		invalidInputf("エラー: -log-level が無効です: %v", err)
+	}
+	if verbosity, ok, err := verbosityLevel(o.verbose, o.veryVerbose, o.quiet); err != nil {
+		invalidInputf("エラー: %v", err)
+	} else if ok {
+		if flagPassed("log-level") {
+			invalidInputf("エラー: -v, -vv, -quiet は -log-level と同時に指定できません")
+		}
+		logLevel = verbosity
+	}
+	return logLevel
+}
+
+// headless は GUI を使用せずにレポートを出力する（-source・-output・-diff を指定した）実行かどうかを返します
+func (o *cliOptions) headless() bool {
+	return !o.estimateMode && len(o.explainPaths) == 0 && !o.renderCommand && (o.sourceDir != "" || o.outputDir != "" || o.diffDir != "")
+}
+
+// newRunConfig はオプションを検証し、設定画面の初期値を含むレポート生成の設定を組み立てます。
+// プラグインと外部コマンドは loadPlugins で設定します。値や組み合わせに誤りがある場合は終了コード exitInvalidInput で終了します
+func (o *cliOptions) newRunConfig() *runConfig {
+	scannerOptions := o.scannerOptions()
+
+	var where *query.Query
+	if o.whereExpr != "" {
+		var err error
+		if where, err = query.Compile(o.whereExpr); err != nil {
+			invalidInputf("エラー: %v", err)
+		}
+	}
+
+	// -output にレポートのファイル名を指定した場合は、出力先フォルダとファイル名に分け、拡張子から出力形式を判定する
+	outputDir, outputFile, format, err := resolveOutput(o.outputDir, o.formatName, flagPassed("format"))
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	o.outputDir = outputDir
+	if outputFile != "" && (format == report.FormatSQLite || o.diffDir != "" || o.normalize) {
+		invalidInputf("エラー: -output のファイル名の指定は、sqlite 形式、-diff、-normalize と同時に指定できません（-output にはフォルダを指定してください）")
+	}
+	binaryPolicy, err := report.ParseBinaryPolicy(o.binaryPolicyName)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	if o.ignoreBinary {
+		if binaryPolicy != report.BinarySkip && binaryPolicy != report.BinaryOmit {
+			invalidInputf("エラー: -ignore-binary と -binary %s は同時に指定できません", binaryPolicy)
+		}
+		binaryPolicy = report.BinaryOmit
+	}
+	reportOptions := o.reportOptions(format, outputFile)
+	if o.maxFileSizeKB < 0 {
+		invalidInputf("エラー: -max-file-size には 0 以上の値を指定してください")
+	}
+	var maxReportSize int64
+	if strings.TrimSpace(o.maxReportSizeSpec) != "0" {
+		if maxReportSize, err = report.ParseByteSize(o.maxReportSizeSpec); err != nil {
+			invalidInputf("エラー: -max-report-size: %v", err)
+		}
+	}
+	sortBy, err := report.ParseSortKey(o.sortKey)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	if o.normalize && (sortBy == report.SortModTime || reportOptions.ContentOrder != report.OrderPath) {
+		invalidInputf("エラー: -normalize では -sort mtime と -order git-recent は指定できません（実行のたびに並び順が変わるため）")
+	}
+	if o.diffMethod, err = diff.ParseMethod(o.diffBy); err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	filterFallback, err := report.ParseFilterFallback(o.pipeFallback)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	ignorePatterns, ignoreOrigins := o.ignorePatternsWithPresets()
+	maskRules := o.maskRules()
+	reportTemplate := o.reportTemplate(format)
+	o.checkModes(scannerOptions, len(ignorePatterns) > 0)
+
+	// 設定画面の初期値はコマンドラインオプションから設定する
+	cfg := &runConfig{
+		scannerOptions: scannerOptions,
+		reportOptions:  reportOptions,
+		settings: &gui.Settings{
+			IgnorePatterns: ignorePatterns,
+			BinaryPolicy:   string(binaryPolicy),
+			Format:         string(format),
+			MaxFileSizeKB:  o.maxFileSizeKB,
+		},
+		writeIndex:     o.writeIndex,
+		heartbeat:      o.heartbeat,
+		showAuthors:    o.showAuthors,
+		exportGist:     o.exportGist,
+		changedAgainst: o.changedAgainst,
+		gitRef:         o.gitRef,
+		patchPath:      o.patchPath,
+		hunksOnly:      o.hunksOnly,
+		filterFallback: filterFallback,
+		where:          where,
+		sortKey:        sortBy,
+		dirsFirst:      o.dirsFirst,
+		maskRules:      maskRules,
+		template:       reportTemplate,
+		saveScanPath:   o.saveScanPath,
+		toStdout:       o.toStdout,
+		outputFile:     outputFile,
+		maxReportSize:  maxReportSize,
+		ignoreOrigins:  ignoreOrigins,
+		args:           os.Args[1:],
+	}
+	for _, f := range report.SupportedFormats {
+		cfg.settings.Formats = append(cfg.settings.Formats, string(f))
+	}
+	for _, p := range report.BinaryPolicies {
+		cfg.settings.BinaryPolicies = append(cfg.settings.BinaryPolicies, string(p))
+	}
+	return cfg
+}
+
+// scannerOptions はスキャンの条件のオプションを検証し、スキャナーのオプションに変換します。
+// 無視パターンとバイナリの扱いは settings の内容で設定するため含みません
+func (o *cliOptions) scannerOptions() filesystem.ScannerOptions {
+	// 正規表現フィルタはフォルダ選択前に検証し、誤りがあれば即座に終了する
+	for _, patterns := range [][]string{o.includeRegexps, o.excludeRegexps} {
+		if _, err := filesystem.CompileRegexps(patterns); err != nil {
+			invalidInputf("エラー: %v", err)
+		}
+	}
+
+	symlinkPolicy, err := filesystem.ParseSymlinkPolicy(o.symlinkPolicyName)
+	if err != nil {
+		invalidInputf("エラー: -symlinks: %v", err)
+	}
+	accessPolicy, err := filesystem.ParseAccessPolicy(o.accessPolicyName)
+	if err != nil {
+		invalidInputf("エラー: -on-access-error: %v", err)
+	}
+	if o.hashWorkers < 0 {
+		invalidInputf("エラー: -hash-workers には 0 以上の値を指定してください")
+	}
+
+	var minSize, maxSize int64
+	for _, size := range []struct {
+		name  string
+		spec  string
+		bytes *int64
+	}{{"-min-size", o.minSizeSpec, &minSize}, {"-max-size", o.maxSizeSpec, &maxSize}} {
+		if size.spec == "" {
+			continue
+		}
+		var err error
+		if *size.bytes, err = report.ParseByteSize(size.spec); err != nil {
+			invalidInputf("エラー: %s: %v", size.name, err)
+		}
+	}
+	if maxSize > 0 && minSize > maxSize {
+		invalidInputf("エラー: -min-size には -max-size 以下のサイズを指定してください")
+	}
+	modifiedRange := [2]time.Time{}
+	for i, date := range []struct {
+		name  string
+		value string
+	}{{"-modified-after", o.modifiedAfter}, {"-modified-before", o.modifiedBefore}} {
+		if date.value == "" {
+			continue
+		}
+		var err error
+		if modifiedRange[i], err = parseDateTime(date.value); err != nil {
+			invalidInputf("エラー: %s: %v", date.name, err)
+		}
+	}
+	if !modifiedRange[0].IsZero() && !modifiedRange[1].IsZero() && !modifiedRange[0].Before(modifiedRange[1]) {
+		invalidInputf("エラー: -modified-after には -modified-before より前の日時を指定してください")
+	}
+
+	var pathRules []rules.Rule
+	if o.rulesPath != "" {
+		var err error
+		if pathRules, err = config.LoadRules(o.rulesPath); err == nil {
+			_, err = rules.Compile(pathRules)
+		}
+		if err != nil {
+			invalidInputf("エラー: %v", err)
+		}
+	}
+
+	return filesystem.ScannerOptions{
+		IncludeHidden:  o.includeHidden,
+		SymlinkPolicy:  symlinkPolicy,
+		AccessPolicy:   accessPolicy,
+		IncludeRegexps: o.includeRegexps,
+		ExcludeRegexps: o.excludeRegexps,
+		ComputeHash:    o.computeHash,
+		HashWorkers:    o.hashWorkers,
+		Rules:          pathRules,
+		MinSize:        minSize,
+		MaxSize:        maxSize,
+		ModifiedAfter:  modifiedRange[0],
+		ModifiedBefore: modifiedRange[1],
+	}
+}
+
+// reportOptions はレポートの内容のオプションを検証し、format の出力形式のレポートのオプションに変換します。
+// 出力形式・サイズ上限・バイナリファイルの扱いは settings の内容で設定するため含みません。outputFile は -output に指定したレポートのファイル名です
+func (o *cliOptions) reportOptions(format report.Format, outputFile string) report.Options {
+	if o.binaryEmbedLimitKB <= 0 {
+		invalidInputf("エラー: -binary-embed-limit には 1 以上の値を指定してください")
+	}
+	compression, err := report.ParseCompression(o.compressName)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	if compression != report.CompressionNone && (o.watchMode || o.diffDir != "" || o.exportGist || outputFile != "") {
+		invalidInputf("エラー: -compress は -watch, -diff, -gist, -output のファイル名の指定と同時に指定できません")
+	}
+	if compression == report.CompressionGzip && o.writeIndex {
+		invalidInputf("エラー: -compress gzip と -index は同時に指定できません（インデックスは -compress zip で同じファイルにまとめられます）")
+	}
+	if format == report.FormatPDF && o.writeIndex {
+		invalidInputf("エラー: pdf 形式と -index は同時に指定できません")
+	}
+	if format == report.FormatSQLite && (compression != report.CompressionNone || o.writeIndex || o.watchMode || o.diffDir != "" || o.exportGist || o.pluginFormat != "") {
+		invalidInputf("エラー: sqlite 形式は -compress, -index, -watch, -diff, -gist, -plugin-format と同時に指定できません")
+	}
+	pdfFontPath := o.pdfFont
+	if pdfFontPath != "" {
+		if _, err := os.Stat(pdfFontPath); err != nil {
+			invalidInputf("エラー: -pdf-font のフォントファイルを読み込めません: %v", err)
+		}
+	} else {
+		pdfFontPath = findPDFFont()
+	}
+	order, err := report.ParseContentOrder(o.contentOrder)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	sizeTiers, err := report.ParseSizeTiers(o.sizeTiersSpec)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	if o.headLines < 0 || o.tailLines < 0 {
+		invalidInputf("エラー: -head-lines と -tail-lines には 0 以上の値を指定してください")
+	}
+	// 行数だけが指定された場合は、大きなファイルを先頭と末尾のみにする段階を使用する
+	if (o.headLines > 0 || o.tailLines > 0) && sizeTiers == nil {
+		sizeTiers = report.TruncatedSizeTiers
+	}
+	if o.normalize && (format == report.FormatPDF || format == report.FormatSQLite || compression != report.CompressionNone || o.watchMode || o.diffDir != "" || o.pluginFormat != "") {
+		invalidInputf("エラー: -normalize は pdf, sqlite 形式、-compress、-watch、-diff、-plugin-format と同時に指定できません")
+	}
+	style, err := report.ParseTreeStyle(o.treeStyle)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+
+	return report.Options{
+		ShowMetadata:      o.showMetadata,
+		HTMLPageSize:      o.htmlPageSize,
+		ShowSummary:       o.showSummary,
+		ContentOrder:      order,
+		LineNumbers:       o.lineNumbers,
+		ShowMetrics:       o.showMetrics,
+		Highlight:         o.highlight,
+		ShowLanguages:     o.showLanguages,
+		ShowLicenses:      o.showLicenses,
+		ShowEncodingAudit: o.auditEncoding,
+		LongListing:       o.longListing,
+		TreeStyle:         style,
+		BinaryEmbedLimit:  o.binaryEmbedLimitKB * 1024,
+		SizeTiers:         sizeTiers,
+		HeadLines:         o.headLines,
+		TailLines:         o.tailLines,
+		Compression:       compression,
+		DisableRedaction:  o.noRedact,
+		PDFFont:           pdfFontPath,
+		Normalize:         o.normalize,
+		DedupHardLinks:    o.dedupHardLinks,
+	}
+}
+
+// ignorePatternsWithPresets は -ignore のパターンに -preset のプリセットのパターンを加えた無視パターンと、
+// プリセットから追加したパターンごとのプリセットの名前を返します
+func (o *cliOptions) ignorePatternsWithPresets() ([]string, map[string]string) {
+	ignorePatterns := slices.Clone(o.ignorePatterns)
+	ignoreOrigins := make(map[string]string)
+	for _, preset := range o.ignorePresets {
+		patterns, err := filesystem.PresetIgnorePatterns(preset)
+		if err != nil {
+			invalidInputf("エラー: -preset: %v", err)
+		}
+		for _, pattern := range patterns {
+			// -ignore で指定したパターンと、先に指定したプリセットのパターンは、最初に指定したものとして扱う
+			if _, ok := ignoreOrigins[pattern]; !ok && !slices.Contains(o.ignorePatterns, pattern) {
+				ignoreOrigins[pattern] = preset
+			}
+		}
+		ignorePatterns = append(ignorePatterns, patterns...)
+	}
+	return ignorePatterns, ignoreOrigins
+}
+
+// maskRules は -mask と -mask-rules で指定したマスクのルールを読み込んでコンパイルします
+func (o *cliOptions) maskRules() report.MaskRules {
+	var maskRules []report.MaskRule
+	for _, preset := range o.maskPresets {
+		maskRules = append(maskRules, report.MaskRule{Preset: preset})
+	}
+	if o.maskRulesPath != "" {
+		rules, err := config.LoadMaskRules(o.maskRulesPath)
+		if err != nil {
+			invalidInputf("エラー: %v", err)
+		}
+		maskRules = append(maskRules, rules...)
+	}
+	compiled, err := report.CompileMaskRules(maskRules)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	return compiled
+}
+
+// reportTemplate は -template で指定したテンプレートを読み込みます。指定されていない場合は nil を返します
+func (o *cliOptions) reportTemplate(format report.Format) *template.Template {
+	if o.templateName == "" {
+		return nil
+	}
+	if format == report.FormatJSON || format == report.FormatJSONL || format == report.FormatXML || format == report.FormatYAML || format == report.FormatPDF || format == report.FormatSQLite || o.pluginFormat != "" || o.writeIndex {
+		invalidInputf("エラー: -template は json, jsonl, xml, yaml, pdf, sqlite 形式、-plugin-format、-index と同時に指定できません")
+	}
+	reportTemplate, err := report.LoadTemplate(o.templateName, userTemplateDir())
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	return reportTemplate
+}
+
+// checkModes は render・-estimate・-explain・-stdout・-watch・-diff などの実行方法と、他のオプションの組み合わせを検証します。
+// scannerOptions はスキャンの条件のオプションを変換したもの、hasIgnorePatterns は -ignore・-preset で無視パターンを指定したかどうかです
+func (o *cliOptions) checkModes(scannerOptions filesystem.ScannerOptions, hasIgnorePatterns bool) {
+	if o.renderCommand != (o.fromScan != "") {
+		invalidInputf("エラー: render には -from でスキャン結果のファイルを指定してください（-from は render でのみ使用できます）")
+	}
+	if o.renderCommand {
+		if o.sourceDir != "" || o.watchMode || o.diffDir != "" || o.changedAgainst != "" || o.estimateMode || o.saveScanPath != "" {
+			invalidInputf("エラー: render は -source, -watch, -diff, -changed-against, -estimate, -save-scan と同時に指定できません")
+		}
+		if hasIgnorePatterns || len(o.includeRegexps) > 0 || len(o.excludeRegexps) > 0 || o.ignoreBinary || !o.includeHidden || scannerOptions.SymlinkPolicy != filesystem.SymlinkLink || scannerOptions.AccessPolicy != filesystem.AccessReport ||
+			o.computeHash || o.rulesPath != "" || scannerOptions.MinSize > 0 || scannerOptions.MaxSize > 0 || o.modifiedAfter != "" || o.modifiedBefore != "" {
+			invalidInputf("エラー: -ignore, -preset, -include, -exclude, -ignore-binary, -hidden, -symlinks, -on-access-error, -hash, -rules, -min-size, -max-size, -modified-after, -modified-before はスキャン時の条件のため render では指定できません（-where で絞り込めます）")
+		}
+		if o.outputDir == "" && !o.toStdout {
+			invalidInputf("エラー: render には -output または -stdout を指定してください")
+		}
+	}
+	if o.saveScanPath != "" && (o.watchMode || o.diffDir != "" || o.estimateMode) {
+		invalidInputf("エラー: -save-scan は -watch, -diff, -estimate と同時に指定できません")
+	}
+	if o.estimateMode {
+		if o.sourceDir == "" {
+			invalidInputf("エラー: -estimate には -source を指定してください")
+		}
+		if o.outputDir != "" || o.watchMode || o.diffDir != "" || o.changedAgainst != "" || o.exportGist || o.pluginFormat != "" {
+			invalidInputf("エラー: -estimate は -output, -watch, -diff, -changed-against, -gist, -plugin-format と同時に指定できません")
+		}
+	}
+	if len(o.explainPaths) > 0 {
+		if o.sourceDir == "" {
+			invalidInputf("エラー: -explain には -source を指定してください")
+		}
+		if o.outputDir != "" || o.watchMode || o.diffDir != "" || o.estimateMode || o.toStdout || o.saveScanPath != "" || o.gitRef != "" || o.patchPath != "" {
+			invalidInputf("エラー: -explain は -output, -watch, -diff, -estimate, -stdout, -save-scan, -git-ref, -patch と同時に指定できません")
+		}
+	}
+	if o.toStdout {
+		if o.sourceDir == "" && !o.renderCommand {
+			invalidInputf("エラー: -stdout には -source を指定してください")
+		}
+		if o.outputDir != "" || o.watchMode || o.diffDir != "" || o.writeIndex || o.exportGist || o.estimateMode {
+			invalidInputf("エラー: -stdout は -output, -watch, -diff, -index, -gist, -estimate と同時に指定できません")
+		}
+	}
+	headless := o.headless()
+	if flag.NArg() > 1 || (flag.NArg() == 1 && (headless || o.renderCommand || o.estimateMode || len(o.explainPaths) > 0 || o.watchMode)) {
+		invalidInputf("エラー: 調査対象フォルダの引数は GUI で起動する場合に 1 つだけ指定できます（GUI を使用しない場合は -source を指定してください）")
+	}
+	if (headless || o.watchMode) && (o.sourceDir == "" || (o.outputDir == "" && !o.toStdout)) {
+		invalidInputf("エラー: -source と -output は両方指定してください")
+	}
+	if o.watchMode && archive.IsArchive(o.sourceDir) {
+		invalidInputf("エラー: アーカイブは -watch で監視できません")
+	}
+	if o.watchMode && o.exportGist {
+		invalidInputf("エラー: -watch と -gist は同時に指定できません")
+	}
+	if o.diffDir != "" && (o.watchMode || o.exportGist) {
+		invalidInputf("エラー: -diff は -watch, -gist と同時に指定できません")
+	}
+	if o.hunksOnly && o.changedAgainst == "" && o.patchPath == "" {
+		invalidInputf("エラー: -hunks-only は -changed-against または -patch と同時に指定してください")
+	}
+	if o.changedAgainst != "" && (o.watchMode || o.diffDir != "" || archive.IsArchive(o.sourceDir)) {
+		invalidInputf("エラー: -changed-against は -watch, -diff, アーカイブの調査対象と同時に指定できません")
+	}
+	if o.patchPath != "" {
+		if !headless || o.watchMode || o.diffDir != "" || o.changedAgainst != "" || o.gitRef != "" || o.saveScanPath != "" || archive.IsArchive(o.sourceDir) {
+			invalidInputf("エラー: -patch は -source に適用元のフォルダを指定し、-watch, -diff, -changed-against, -git-ref, -save-scan, -estimate, render と同時に指定しないでください")
+		}
+	}
+	if o.gitRef != "" {
+		if !headless || o.watchMode || o.diffDir != "" || o.changedAgainst != "" || o.saveScanPath != "" || archive.IsArchive(o.sourceDir) {
+			invalidInputf("エラー: -git-ref は -source に git リポジトリのフォルダを指定し、-watch, -diff, -changed-against, -save-scan, -estimate, render と同時に指定しないでください")
+		}
+	}
+}
+
+// newLogger はロガーを作成します。異常終了時の診断情報のため、直近のログを保持します。
+// レポートを標準出力に書き込む場合は、レポートと混ざらないようログは標準エラー出力に書き込みます
+func (o *cliOptions) newLogger(level slog.Level) *logging.RecentLogger {
+	logOutput := os.Stdout
+	if o.toStdout {
+		logOutput = os.Stderr
+	}
+	return logging.NewRecentLogger(logging.NewJSONLoggerWithOptions(logOutput, logging.Options{Level: level}), crash.DefaultLogEntries)
+}
+
+// loadPlugins はプラグインを検出し、-plugin-format・-plugin-filter・-pipe-content の指定を cfg に設定します
+func (o *cliOptions) loadPlugins(logger logging.Logger, cfg *runConfig) {
+	plugins, err := discoverPlugins(logger, o.pluginsDir)
+	if err != nil {
+		invalidInputf("エラー: %v", err)
+	}
+	pluginSource := plugins.SourceFor(o.sourceDir) != nil
+	if pluginSource && (o.watchMode || o.diffDir != "" || o.changedAgainst != "" || o.estimateMode) {
+		invalidInputf("エラー: プラグインで読み込む調査対象は -watch, -diff, -changed-against, -estimate と同時に指定できません")
+	}
+	cfg.plugins = plugins
+	if o.pluginFormat != "" {
+		if !(o.headless() || o.renderCommand) || o.watchMode || o.diffDir != "" || o.writeIndex {
+			invalidInputf("エラー: -plugin-format は -source と -output を指定し、-watch, -diff, -index と同時に指定しないでください")
+		}
+		if cfg.formatter, err = plugins.Lookup(plugin.KindFormatter, o.pluginFormat); err != nil {
+			invalidInputf("エラー: %v", err)
+		}
+	}
+	var contentFilters []report.ContentFilter
+	for _, name := range o.pluginFilters {
+		p, err := plugins.Lookup(plugin.KindFilter, name)
+		if err != nil {
+			invalidInputf("エラー: %v", err)
+		}
+		contentFilters = append(contentFilters, plugin.NewFilter(logger, p, o.pipeTimeout))
+	}
+	if o.pipeContent != "" {
+		contentFilters = append(contentFilters, pipe.NewCommand(logger, o.pipeContent, o.pipeTimeout))
+	}
+	if len(contentFilters) > 0 {
+		cfg.contentFilter = report.ChainFilters(contentFilters...)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"FolderScope/internal/usecase/report"
+)
+
+// isOutputFilePath は -output の指定がレポートのファイル名かどうかを返します。
+// 既存のフォルダではなく、拡張子を持つパスの場合にファイル名とみなします
+func isOutputFilePath(p string) bool {
+	if info, err := os.Stat(p); err == nil && info.IsDir() {
+		return false
+	}
+	return filepath.Ext(p) != ""
+}
+
+// resolveOutput は -output と -format の指定から、出力先フォルダ、レポートのファイル名、出力形式を返します。
+// -output にレポートのファイル名を指定した場合は出力先フォルダとファイル名に分け、拡張子から出力形式を判定します。
+// 拡張子から判定できない場合と、-output にフォルダを指定した場合（ファイル名は空）は formatName の出力形式とします。
+// formatPassed は -format を明示的に指定したかどうかで、拡張子と異なる出力形式を指定した場合はエラーを返します
+func resolveOutput(output, formatName string, formatPassed bool) (string, string, report.Format, error) {
+	dir, file := output, ""
+	if output != "" && isOutputFilePath(output) {
+		if inferred, ok := report.FormatFromPath(output); ok {
+			if explicit, err := report.ParseFormat(formatName); err == nil && formatPassed && explicit != inferred {
+				return "", "", "", fmt.Errorf("-format %s と -output のファイルの拡張子（%s）の出力形式が異なります", formatName, filepath.Ext(output))
+			}
+			formatName = string(inferred)
+		}
+		dir, file = filepath.Dir(output), filepath.Base(output)
+	}
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		return "", "", "", err
+	}
+	return dir, file, format, nil
+}
+
+// flagPassed はコマンドラインで name のオプションが指定されたかどうかを返します
+func flagPassed(name string) bool {
+	passed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
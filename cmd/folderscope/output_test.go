@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"FolderScope/internal/usecase/report"
+)
+
+func TestIsOutputFilePath(t *testing.T) {
+	dir := t.TempDir()
+	dottedDir := filepath.Join(dir, "reports.d")
+	if err := os.Mkdir(dottedDir, 0755); err != nil {
+		t.Fatalf("フォルダの作成に失敗: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"既存のフォルダ", dir, false},
+		{"拡張子を持つ既存のフォルダ", dottedDir, false},
+		{"拡張子を持つ存在しないパス", filepath.Join(dir, "report.md"), true},
+		{"出力形式に対応しない拡張子", filepath.Join(dir, "report.log"), true},
+		{"拡張子のない存在しないパス", filepath.Join(dir, "out"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOutputFilePath(tt.path); got != tt.want {
+				t.Errorf("isOutputFilePath(%q) = %v; want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOutput(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name         string
+		output       string
+		formatName   string
+		formatPassed bool
+		wantDir      string
+		wantFile     string
+		wantFormat   report.Format
+		wantErr      bool
+	}{
+		{"フォルダの指定は -format の出力形式", dir, "html", true, dir, "", report.FormatHTML, false},
+		{"-output の省略", "", "text", false, "", "", report.FormatText, false},
+		{"拡張子から判定", filepath.Join(dir, "report.md"), "text", false, dir, "report.md", report.FormatMarkdown, false},
+		{"-format と拡張子が一致", filepath.Join(dir, "report.json"), "json", true, dir, "report.json", report.FormatJSON, false},
+		{"既定値の -format は拡張子で上書き", filepath.Join(dir, "report.html"), "text", false, dir, "report.html", report.FormatHTML, false},
+		{"判定できない拡張子は -format の出力形式", filepath.Join(dir, "report.log"), "yaml", true, dir, "report.log", report.FormatYAML, false},
+		{"-format と拡張子が異なる", filepath.Join(dir, "report.md"), "html", true, "", "", "", true},
+		{"不正な -format", dir, "docx", true, "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDir, gotFile, gotFormat, err := resolveOutput(tt.output, tt.formatName, tt.formatPassed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveOutput(%q, %q, %v) error = %v; wantErr %v", tt.output, tt.formatName, tt.formatPassed, err, tt.wantErr)
+			}
+			if gotDir != tt.wantDir || gotFile != tt.wantFile || gotFormat != tt.wantFormat {
+				t.Errorf("resolveOutput(%q, %q, %v) = %q, %q, %v; want %q, %q, %v",
+					tt.output, tt.formatName, tt.formatPassed, gotDir, gotFile, gotFormat, tt.wantDir, tt.wantFile, tt.wantFormat)
+			}
+		})
+	}
+}
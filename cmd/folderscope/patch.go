@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"FolderScope/internal/infrastructure/archive"
+	"FolderScope/internal/infrastructure/gitinfo"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/patch"
+)
+
+// openPatch は baseDir のフォルダに -patch のパッチ（git バンドルの場合はバンドルに含まれる変更）を適用し、
+// 変更後に存在するファイルのみを含むアーカイブとして返します。ファイルの更新日時はパッチのファイルの更新日時とします。
+// -hunks-only が指定されている場合は、ファイルの本文の代わりに出力するパッチの変更箇所も設定します
+func (cfg *runConfig) openPatch(ctx context.Context, logger logging.Logger, baseDir string) (*archive.Archive, error) {
+	if info, err := os.Stat(baseDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("-patch の調査対象にはパッチの適用元のフォルダを指定してください: %s", baseDir)
+	}
+	info, err := os.Stat(cfg.patchPath)
+	if err != nil {
+		return nil, fmt.Errorf("パッチのファイルを開けませんでした: %w", err)
+	}
+	var data []byte
+	if strings.EqualFold(filepath.Ext(cfg.patchPath), ".bundle") {
+		data, err = gitinfo.BundleDiff(ctx, baseDir, cfg.patchPath)
+	} else {
+		data, err = os.ReadFile(cfg.patchPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("パッチ '%s' の読み込みに失敗しました: %w", cfg.patchPath, err)
+	}
+
+	files, err := patch.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("パッチ '%s' の読み込みに失敗しました: %w", cfg.patchPath, err)
+	}
+	fsys, err := patch.Apply(os.DirFS(baseDir), files, info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[patch.Status]int)
+	for _, f := range files {
+		counts[f.Status]++
+		switch {
+		case f.Status == patch.StatusDeleted:
+			logger.Log("INFO", fmt.Sprintf("パッチで削除されたファイル: %s", f.OldPath), nil)
+		case f.Binary:
+			logger.Log("WARN", fmt.Sprintf("バイナリファイルの変更は適用できないため、レポートに含めません: %s", f.Path()), nil)
+		}
+	}
+	logger.Log("INFO", fmt.Sprintf("パッチを適用しました（追加: %d, 変更: %d, 名前の変更: %d, 削除: %d）",
+		counts[patch.StatusAdded], counts[patch.StatusModified], counts[patch.StatusRenamed], counts[patch.StatusDeleted]), nil)
+
+	if cfg.hunksOnly {
+		cfg.hunks = make(map[string]string)
+		for _, f := range files {
+			if f.Status != patch.StatusDeleted {
+				cfg.hunks[f.NewPath] = f.Hunks
+			}
+		}
+	}
+	return archive.FromFS(fsys), nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/plugin"
+)
+
+// discoverPlugins は dir（空の場合は既定のプラグインディレクトリ）からプラグインを検出します
+func discoverPlugins(logger logging.Logger, dir string) (*plugin.Registry, error) {
+	if dir == "" {
+		var err error
+		if dir, err = plugin.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	plugins, err := plugin.Discover(logger, dir)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(plugins.Plugins()); n > 0 {
+		logger.Log("INFO", fmt.Sprintf("%d 個のプラグインを検出しました: %s", n, dir), nil)
+	}
+	return plugins, nil
+}
+
+// runPluginsCommand はプラグインディレクトリから検出したプラグインの一覧を表示します
+func runPluginsCommand(args []string) error {
+	flags := flag.NewFlagSet("plugins", flag.ContinueOnError)
+	pluginsDir := flags.String("plugins-dir", "", "プラグインを検出するディレクトリ（既定: ユーザー設定ディレクトリの folderscope/plugins）")
+	parseFlags(flags, args)
+
+	// 標準出力は結果の表示に使用するため、ログは標準エラー出力に書き込む
+	logger := logging.NewJSONLogger(os.Stderr)
+	plugins, err := discoverPlugins(logger, *pluginsDir)
+	if err != nil {
+		return err
+	}
+	list := plugins.Plugins()
+	if len(list) == 0 {
+		fmt.Println("プラグインはありません")
+		return nil
+	}
+	for _, p := range list {
+		fmt.Printf("%-20s %-10s %s\n", p.Name, p.Kind, p.Description)
+	}
+	return nil
+}
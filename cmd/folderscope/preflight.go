@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+// reportSizeAction は、出力前に見積もったレポートのサイズが上限を超える場合の対応です
+type reportSizeAction int
+
+const (
+	// sizeTruncate は大きなファイルの内容を先頭と末尾のみに切り詰めて出力します
+	sizeTruncate reportSizeAction = iota
+	// sizeSkipBinary はバイナリファイルの内容（16 進ダンプ・Base64）を出力せずにスキップします
+	sizeSkipBinary
+	// sizeContinue は設定を変更せずにそのまま出力します
+	sizeContinue
+	// sizeAbort はレポートを出力せずに中止します
+	sizeAbort
+)
+
+// label は確認画面のボタンやプロンプトに表示する対応の説明を返します
+func (a reportSizeAction) label() string {
+	switch a {
+	case sizeTruncate:
+		return fmt.Sprintf("%s を超えるファイルを先頭と末尾のみに切り詰める", report.FormatSize(report.TruncateSize))
+	case sizeSkipBinary:
+		return "バイナリファイルの内容をスキップする"
+	case sizeContinue:
+		return "このまま出力する"
+	}
+	return "中止する"
+}
+
+// preflightReportSize は、レポートを書き込む前にファイル内容を読み込まずにサイズを見積もり、cfg.maxReportSize を超える場合は対応を確認します。
+// 切り詰めやバイナリファイルのスキップが選択された場合は cfg の設定を変更し、その設定で作成し直したジェネレーターを返します。
+// 中止が選択された場合は apperrors.ErrCancelled を返します。
+// サイズが内容に比例しない pdf・sqlite 形式と、formatter のプラグインを使用する場合は見積もりません
+func (cfg *runConfig) preflightReportSize(logger logging.Logger, generator *report.Generator, entries []model.FileSystemEntry) (*report.Generator, error) {
+	format, _ := report.ParseFormat(cfg.settings.Format)
+	if cfg.maxReportSize <= 0 || cfg.formatter != nil || format == report.FormatPDF || format == report.FormatSQLite {
+		return generator, nil
+	}
+	estimate, err := generator.Estimate(entries, []report.Format{format})
+	if err != nil {
+		logger.Log("WARN", "レポートのサイズの見積もりに失敗しました", err)
+		return generator, nil
+	}
+	size := estimate.Formats[0].Bytes
+	if size <= cfg.maxReportSize {
+		return generator, nil
+	}
+
+	message := fmt.Sprintf("レポートのサイズは約 %s（約 %d トークン）と見積もられ、上限の %s を超えます（内容を出力するファイル: %d 件、合計 %s）。",
+		report.FormatSize(size), estimate.Formats[0].Tokens, report.FormatSize(cfg.maxReportSize), estimate.ContentFiles, report.FormatSize(estimate.ContentBytes))
+	logger.Log("WARN", message, nil)
+	actions := []reportSizeAction{sizeTruncate}
+	if policy := report.BinaryPolicy(cfg.settings.BinaryPolicy); (policy == report.BinaryHexdump || policy == report.BinaryBase64) && hasBinary(entries) {
+		actions = append(actions, sizeSkipBinary)
+	}
+	actions = append(actions, sizeContinue, sizeAbort)
+
+	ask := cfg.askReportSize
+	if ask == nil {
+		ask = promptReportSize
+	}
+	switch action := ask(message, actions); action {
+	case sizeTruncate:
+		logger.Log("INFO", fmt.Sprintf("%s を超えるファイルを先頭と末尾のみに切り詰めて出力します", report.FormatSize(report.TruncateSize)), nil)
+		cfg.reportOptions.SizeTiers = report.TruncatedSizeTiers
+	case sizeSkipBinary:
+		logger.Log("INFO", "バイナリファイルの内容をスキップして出力します", nil)
+		cfg.settings.BinaryPolicy = string(report.BinarySkip)
+	case sizeAbort:
+		return nil, fmt.Errorf("レポートのサイズが上限を超えるため、出力を中止しました: %w", apperrors.ErrCancelled)
+	default:
+		return generator, nil
+	}
+
+	generator, err = cfg.newGenerator()
+	if err != nil {
+		return nil, err
+	}
+	if revised, err := generator.Estimate(entries, []report.Format{format}); err == nil {
+		logger.Log("INFO", fmt.Sprintf("変更後のレポートのサイズの見積もり: 約 %s", report.FormatSize(revised.Formats[0].Bytes)), nil)
+	}
+	return generator, nil
+}
+
+// hasBinary は entries にバイナリファイルが含まれるかどうかを返します
+func hasBinary(entries []model.FileSystemEntry) bool {
+	for _, entry := range entries {
+		if !entry.IsDir && entry.IsBinary {
+			return true
+		}
+	}
+	return false
+}
+
+// promptReportSize は GUI を使用しない実行で、標準エラー出力に対応の選択肢を表示し、端末からの入力で選択させます。
+// 標準入力が端末ではない場合（パイプやスクリプトからの実行）や入力が終了した場合は確認できないため、そのまま出力します
+func promptReportSize(message string, actions []reportSizeAction) reportSizeAction {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", message)
+		return sizeContinue
+	}
+	fmt.Fprintln(os.Stderr, message)
+	for i, action := range actions {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, action.label())
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "番号を入力してください [1-%d]: ", len(actions))
+		line, err := reader.ReadString('\n')
+		if n, convErr := strconv.Atoi(strings.TrimSpace(line)); convErr == nil && n >= 1 && n <= len(actions) {
+			return actions[n-1]
+		}
+		// 入力が終了した場合（/dev/null からの入力など）は、端末ではない場合と同じくそのまま出力する
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+			return sizeContinue
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/archive"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+	"FolderScope/internal/usecase/scanresult"
+)
+
+// saveScan は -save-scan で指定されたファイルにスキャン結果を保存します。
+// 保存したスキャン結果は render -from で、フォルダを再びスキャンせずにレポートの出力に使用できます
+func saveScan(logger logging.Logger, cfg *runConfig, sourceDir string, entries []model.FileSystemEntry) error {
+	result := scanresult.New(sourceDir, entries, cfg.scanStats, time.Now())
+	if err := scanresult.WriteFile(cfg.saveScanPath, result); err != nil {
+		return err
+	}
+	logger.Log("INFO", fmt.Sprintf("スキャン結果を保存しました: %s", cfg.saveScanPath), nil)
+	return nil
+}
+
+// runRender は保存したスキャン結果からフォルダを再びスキャンせずにレポートを出力します。
+// ファイルの内容はスキャン結果の参照先（フォルダのファイルまたはアーカイブ）から読み込みます
+func runRender(ctx context.Context, logger logging.Logger, cfg *runConfig, fromPath, outputDir string) error {
+	result, err := scanresult.LoadFile(fromPath)
+	if err != nil {
+		return err
+	}
+	cfg.scanStats = result.Stats
+	logger.Log("INFO", fmt.Sprintf("スキャン結果を読み込みました: %s（%s にスキャン、%d 件）",
+		fromPath, result.ScannedAt.Local().Format(report.MetadataTimeLayout), len(result.Entries)), nil)
+
+	sourceRoot := result.Root
+	if p := cfg.plugins.SourceFor(result.Root); p != nil || archive.IsArchive(result.Root) {
+		open := archive.Open
+		if p != nil {
+			logger.Log("INFO", fmt.Sprintf("プラグイン '%s' で調査対象を読み込みます", p.Name), nil)
+			open = func(location string) (*archive.Archive, error) { return p.Open(ctx, location) }
+		}
+		a, err := open(result.Root)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		cfg.contentFS = a.FS()
+		sourceRoot = ""
+	} else if changed := result.ChangedFiles(); len(changed) > 0 {
+		logger.Log("WARN", fmt.Sprintf("スキャン後に %d 件のファイルが変更または削除されています（%s など）。内容は現在のファイルから読み込みます",
+			len(changed), changed[0]), nil)
+	}
+	if outputDir != "" {
+		if err := cfg.newScanner(logger).ValidateOutputDirectory(outputDir, sourceRoot); err != nil {
+			return fmt.Errorf("出力先フォルダが無効です: %w", err)
+		}
+	}
+
+	out, err := writeReport(ctx, logger, cfg, result.FileSystemEntries(), result.Root, outputDir)
+	if err != nil {
+		return err
+	}
+	logger.Log("INFO", "処理が完了しました", nil)
+	if !cfg.toStdout {
+		fmt.Printf("レポートを出力しました: %s\n", out.outputPath)
+	}
+	return partialError(cfg.scanStats)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// guiSettingFlags は GUI で変更できる項目と調査対象・出力先のオプションです。
+// GUI で実行したレポートの再作成コマンドでは、起動時の指定を取り除いて実行時の値で置き換えます
+var guiSettingFlags = []string{"source", "output", "format", "binary", "ignore-binary", "max-file-size", "ignore", "preset"}
+
+// shellSafeArg はシェルで引用符なしに使用できる引数です
+var shellSafeArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// reproduceCommand は sourceDir から outputDir に作成したレポートを再作成するコマンドラインを返します。
+// GUI を使用しない実行では起動時の引数のまま、GUI での実行では起動時の引数に、選択したフォルダと設定画面の値を指定します
+func (cfg *runConfig) reproduceCommand(sourceDir, outputDir string) string {
+	if !cfg.interactive {
+		return commandLine(cfg.args)
+	}
+	args := optionArgs(cfg.args, guiSettingFlags)
+	args = append(args, "-source", sourceDir, "-output", outputDir, "-format", cfg.settings.Format, "-binary", cfg.settings.BinaryPolicy)
+	if cfg.settings.MaxFileSizeKB > 0 {
+		args = append(args, "-max-file-size", strconv.FormatInt(cfg.settings.MaxFileSizeKB, 10))
+	}
+	for _, pattern := range cfg.settings.IgnorePatterns {
+		args = append(args, "-ignore", pattern)
+	}
+	return commandLine(args)
+}
+
+// optionArgs は args のうち、names 以外のオプションとその値のみを返します。
+// 起動時に指定した調査対象フォルダなどの位置引数は含めません。"-name value" と "-name=value" のどちらの形式にも対応します
+func optionArgs(args, names []string) []string {
+	var kept []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			continue
+		}
+		name, _, inline := strings.Cut(strings.TrimPrefix(arg[1:], "-"), "=")
+		option := []string{arg}
+		if !inline && !isBoolFlag(name) && i+1 < len(args) {
+			i++
+			option = append(option, args[i])
+		}
+		if !slices.Contains(names, name) {
+			kept = append(kept, option...)
+		}
+	}
+	return kept
+}
+
+// isBoolFlag は name のオプションが値を取らない（-name のみで有効になる）かどうかを返します
+func isBoolFlag(name string) bool {
+	f := flag.CommandLine.Lookup(name)
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// commandLine は args を folderscope のコマンドラインとして、シェルにそのまま貼り付けられるように引用符で囲んで連結します
+func commandLine(args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "folderscope")
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote は arg をシェルの 1 つの引数として扱われるように、必要な場合は単一引用符で囲みます
+func shellQuote(arg string) string {
+	if shellSafeArg.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
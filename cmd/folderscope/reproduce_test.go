@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"FolderScope/internal/gui"
+)
+
+func TestReproduceCommand(t *testing.T) {
+	settings := &gui.Settings{Format: "markdown", BinaryPolicy: "omit", MaxFileSizeKB: 512, IgnorePatterns: []string{"*.log", "build dir"}}
+
+	tests := []struct {
+		name        string
+		args        []string
+		interactive bool
+		want        string
+	}{
+		{
+			name: "起動時の引数のまま",
+			args: []string{"-source", "./src", "-output", "out", "-format", "markdown"},
+			want: "folderscope -source ./src -output out -format markdown",
+		},
+		{
+			name: "シェルで解釈される引数は引用符で囲む",
+			args: []string{"-source", "my src", "-where", "path matches '^a'", "-output", "out"},
+			want: `folderscope -source 'my src' -where 'path matches '\''^a'\''' -output out`,
+		},
+		{
+			name:        "GUI では選択したフォルダと設定画面の値で置き換える",
+			args:        []string{"-hash", "-format=text", "-summary", "-ignore", "*.tmp", "-sort", "size", "dir"},
+			interactive: true,
+			want:        "folderscope -hash -summary -sort size -source /work/src -output /work/out -format markdown -binary omit -max-file-size 512 -ignore '*.log' -ignore 'build dir'",
+		},
+		{
+			name:        "GUI では -- 以降の引数を含めない",
+			args:        []string{"-line-numbers", "--", "-source"},
+			interactive: true,
+			want:        "folderscope -line-numbers -source /work/src -output /work/out -format markdown -binary omit -max-file-size 512 -ignore '*.log' -ignore 'build dir'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &runConfig{args: tt.args, interactive: tt.interactive, settings: settings}
+			if got := cfg.reproduceCommand("/work/src", "/work/out"); got != tt.want {
+				t.Errorf("reproduceCommand() = %s; want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"FolderScope/internal/usecase/report"
+)
+
+// runRestoreCommand は JSON・JSONL 形式でエクスポートしたレポートから、フォルダ構成とテキストファイルの内容を復元します
+func runRestoreCommand(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	force := flags.Bool("force", false, "復元先にすでに存在するファイルを上書きする")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "使い方: folderscope restore [-force] <レポート.json|.jsonl|.json.gz> <復元先フォルダ>")
+		flags.PrintDefaults()
+	}
+	parseFlags(flags, args)
+	if flags.NArg() != 2 {
+		return usageError("復元するレポート（JSON・JSONL 形式）と復元先のフォルダを指定してください")
+	}
+
+	scan, err := report.LoadExport(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	dir := flags.Arg(1)
+	if redacted := report.RedactedFiles(scan); len(redacted) > 0 {
+		fmt.Fprintf(os.Stderr, "警告: 次の %d 件のファイルは秘密情報をマスクした内容（[REDACTED:…] など）のまま復元されます。元の値は含まれません\n", len(redacted))
+		for _, f := range redacted {
+			rules := make([]string, len(f.Redactions))
+			for i, r := range f.Redactions {
+				rules[i] = fmt.Sprintf("%s × %d", r.Rule, r.Count)
+			}
+			fmt.Fprintf(os.Stderr, "  %s（%s）\n", f.RelPath, strings.Join(rules, ", "))
+		}
+	}
+	result, err := report.Restore(scan, dir, report.RestoreOptions{Overwrite: *force})
+	if err != nil {
+		return fmt.Errorf("レポートの復元に失敗しました: %w", err)
+	}
+	fmt.Printf("レポートを復元しました: %s（フォルダ: %d, ファイル: %d）\n", dir, result.Dirs, result.Files)
+	if len(result.Skipped) > 0 {
+		fmt.Printf("内容がレポートに含まれていない、またはバイナリファイルのため復元しなかったファイル: %d 件\n", len(result.Skipped))
+		for _, relPath := range result.Skipped {
+			fmt.Printf("  %s\n", relPath)
+		}
+	}
+	return nil
+}
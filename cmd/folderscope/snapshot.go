@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/history"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/diff"
+	"FolderScope/internal/usecase/report"
+	"FolderScope/internal/usecase/snapshot"
+)
+
+// subcommands は最初の引数で指定できるサブコマンドです
+var subcommands = map[string]func(args []string) error{
+	"snapshot": runSnapshotCommand,
+	"compare":  runCompareCommand,
+	"plugins":  runPluginsCommand,
+	"history":  runHistoryCommand,
+	"view":     runViewCommand,
+	"restore":  runRestoreCommand,
+	"config":   runConfigCommand,
+}
+
+// filterFlags はサブコマンドで共通のスキャンの絞り込み条件のオプションです
+type filterFlags struct {
+	ignorePatterns, includeRegexps, excludeRegexps stringList
+	ignoreBinary                                   *bool
+}
+
+// register は絞り込み条件のオプションを flags に登録します
+func (f *filterFlags) register(flags *flag.FlagSet) {
+	flags.Var(&f.ignorePatterns, "ignore", "デフォルトに追加して無視するファイル・ディレクトリ名のパターン（複数指定可）")
+	flags.Var(&f.includeRegexps, "include", "相対パスに一致するファイルのみを含める正規表現（複数指定可）")
+	flags.Var(&f.excludeRegexps, "exclude", "相対パスに一致するファイル・ディレクトリを除外する正規表現（複数指定可）")
+	f.ignoreBinary = flags.Bool("ignore-binary", false, "バイナリファイルを除外する")
+}
+
+// filters はオプションの値をスナップショットに記録する絞り込み条件に変換します
+func (f *filterFlags) filters() snapshot.Filters {
+	return snapshot.Filters{
+		IgnorePatterns:    f.ignorePatterns,
+		IgnoreBinaryFiles: *f.ignoreBinary,
+		IncludeRegexps:    f.includeRegexps,
+		ExcludeRegexps:    f.excludeRegexps,
+	}
+}
+
+// newFilteredScanner は絞り込み条件を適用したスキャナーを作成します
+func newFilteredScanner(logger logging.Logger, filters snapshot.Filters, computeHash bool) (*filesystem.Scanner, error) {
+	return filesystem.NewScannerWithOptions(logger, filesystem.ScannerOptions{
+		IgnorePatterns:    filters.IgnorePatterns,
+		IgnoreBinaryFiles: filters.IgnoreBinaryFiles,
+		IncludeHidden:     true,
+		IncludeRegexps:    filters.IncludeRegexps,
+		ExcludeRegexps:    filters.ExcludeRegexps,
+		ComputeHash:       computeHash,
+	})
+}
+
+// runSnapshotCommand はフォルダをスキャンし、各ファイルのサイズ・更新日時・ハッシュをスナップショットファイルに保存します
+func runSnapshotCommand(args []string) (err error) {
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	sourceDir := flags.String("source", "", "スナップショットを作成するフォルダ")
+	outPath := flags.String("out", "", "スナップショットファイルのパス（省略時はカレントディレクトリに 'フォルダ名_日時"+snapshot.FileSuffix+"' を作成）")
+	noHash := flags.Bool("no-hash", false, "ハッシュを計算しない（比較時はサイズと更新日時で判定する）")
+	var filter filterFlags
+	filter.register(flags)
+	parseFlags(flags, args)
+
+	if *sourceDir == "" {
+		return usageError("-source を指定してください")
+	}
+	root, err := filepath.Abs(*sourceDir)
+	if err != nil {
+		return fmt.Errorf("フォルダのパスの解決に失敗しました: %w", err)
+	}
+
+	// 標準出力は結果の表示に使用するため、ログは標準エラー出力に書き込む
+	logger := logging.NewJSONLogger(os.Stderr)
+	path := *outPath
+	started := time.Now()
+	defer func() {
+		recordRun(logger, history.Entry{Command: "snapshot", Source: root, Output: path}, started, err)
+	}()
+	filters := filter.filters()
+	scanner, err := newFilteredScanner(logger, filters, !*noHash)
+	if err != nil {
+		return err
+	}
+	if err := scanner.ValidateSourceDirectory(root); err != nil {
+		return fmt.Errorf("調査対象フォルダが無効です: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	scanner, stopHeartbeat := withHeartbeat(logger, scanner, "スキャン", filesystem.DefaultHeartbeatInterval)
+	entries, err := scanner.Scan(ctx, root)
+	stopHeartbeat()
+	if err != nil {
+		return fmt.Errorf("フォルダ構造のスキャンに失敗しました: %w", err)
+	}
+
+	now := time.Now()
+	if path == "" {
+		path = filepath.Base(root) + "_" + now.Format(report.TimestampLayout) + snapshot.FileSuffix
+	}
+	if err := snapshot.WriteFile(path, snapshot.New(root, entries, filters, now)); err != nil {
+		return err
+	}
+	fmt.Printf("スナップショットを保存しました: %s（%d 件）\n", path, len(entries))
+	return nil
+}
+
+// runCompareCommand は現在のフォルダをスナップショットと比較し、スナップショット作成後に追加・削除・変更されたファイルを報告します。
+// -output を指定した場合は差分レポートをファイルに出力し、省略した場合は標準出力に出力します
+func runCompareCommand(args []string) (err error) {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	snapshotPath := flags.String("snapshot", "", "比較するスナップショットファイル")
+	sourceDir := flags.String("source", "", "比較するフォルダ（省略時はスナップショットを作成したフォルダ）")
+	toPath := flags.String("to", "", "フォルダをスキャンする代わりに比較する、後から作成したスナップショットファイル（-source と同時に指定できません）")
+	outputDir := flags.String("output", "", "差分レポートの出力先フォルダ（省略時は標準出力）")
+	summary := flags.Bool("summary", false, "差分の一覧の前に、変更の件数とフォルダごとの変更の件数をまとめた概要（LLM へのレビュー依頼向け）を出力する")
+	parseFlags(flags, args)
+
+	if *snapshotPath == "" {
+		return usageError("-snapshot を指定してください")
+	}
+	if *toPath != "" && *sourceDir != "" {
+		return usageError("-to と -source は同時に指定できません")
+	}
+	snap, err := snapshot.LoadFile(*snapshotPath)
+	if err != nil {
+		return err
+	}
+	root := *sourceDir
+	if root == "" {
+		root = snap.Root
+	}
+
+	logger := logging.NewJSONLogger(os.Stderr)
+	var reportPath string
+	started := time.Now()
+	defer func() {
+		recordRun(logger, history.Entry{Command: "compare", Source: root, Output: reportPath}, started, err)
+	}()
+	opts := diff.ReportOptions{
+		OldRoot: fmt.Sprintf("%s（スナップショット %s, %s）", snap.Root, *snapshotPath, snap.CreatedAt.Local().Format(report.MetadataTimeLayout)),
+		NewRoot: root,
+		Summary: *summary,
+	}
+
+	var result diff.Result
+	if *toPath != "" {
+		// 2 つのスナップショットを比較する場合はスキャンしない
+		to, err := snapshot.LoadFile(*toPath)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(snap.Filters, to.Filters) {
+			logger.Log("WARN", "2 つのスナップショットの絞り込み条件が異なるため、条件の違いによる差分が含まれる可能性があります", nil)
+		}
+		root = to.Root
+		opts.NewRoot = fmt.Sprintf("%s（スナップショット %s, %s）", to.Root, *toPath, to.CreatedAt.Local().Format(report.MetadataTimeLayout))
+		if *outputDir != "" {
+			if err := filesystem.NewScanner(logger, nil, false).ValidateOutputDirectory(*outputDir, ""); err != nil {
+				return fmt.Errorf("出力先フォルダが無効です: %w", err)
+			}
+		}
+		result = snap.CompareSnapshot(to)
+	} else {
+		method := snap.CompareMethod()
+		// スナップショットの作成時と同じ条件でスキャンし、条件の違いが差分として報告されないようにする
+		scanner, err := newFilteredScanner(logger, snap.Filters, method == diff.MethodHash)
+		if err != nil {
+			return err
+		}
+		if err := scanner.ValidateSourceDirectory(root); err != nil {
+			return fmt.Errorf("比較するフォルダが無効です: %w", err)
+		}
+		if *outputDir != "" {
+			if err := scanner.ValidateOutputDirectory(*outputDir, root); err != nil {
+				return fmt.Errorf("出力先フォルダが無効です: %w", err)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		scanner, stopHeartbeat := withHeartbeat(logger, scanner, "スキャン", filesystem.DefaultHeartbeatInterval)
+		entries, err := scanner.Scan(ctx, root)
+		stopHeartbeat()
+		if err != nil {
+			return fmt.Errorf("フォルダ構造のスキャンに失敗しました: %w", err)
+		}
+		result = snap.Compare(entries)
+	}
+
+	if *outputDir == "" {
+		out := bufio.NewWriter(os.Stdout)
+		diff.WriteReport(out, result, opts)
+		return out.Flush()
+	}
+	reportPath, err = writeDiffReport(*outputDir, result, opts)
+	return err
+}
+
+// writeDiffReport は差分レポートを出力先フォルダのファイルに書き込み、そのパスを表示して返します
+func writeDiffReport(outputDir string, result diff.Result, opts diff.ReportOptions) (string, error) {
+	outputFile, outputPath, err := diff.CreateOutputFile(outputDir)
+	if err != nil {
+		return "", err
+	}
+	output := report.NewReportWriter(outputFile)
+	defer output.Close()
+	diff.WriteReport(output, result, opts)
+	if err := output.Close(); err != nil {
+		return "", fmt.Errorf("差分レポートの書き込みに失敗しました: %w", err)
+	}
+	fmt.Printf("差分レポートを出力しました: %s\n", outputPath)
+	return outputPath, nil
+}
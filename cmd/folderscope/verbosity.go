@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// verbosityLevel は -v・-vv・-quiet の指定に対応するログレベルを返します。いずれも指定されていない場合は false を返します
+func verbosityLevel(verbose, veryVerbose, quiet bool) (slog.Level, bool, error) {
+	switch {
+	case quiet && (verbose || veryVerbose):
+		return 0, false, fmt.Errorf("-quiet と -v, -vv は同時に指定できません")
+	case quiet:
+		return slog.LevelError, true, nil
+	case veryVerbose:
+		return logging.LevelTrace, true, nil
+	case verbose:
+		return slog.LevelDebug, true, nil
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"FolderScope/internal/gui"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/usecase/report"
+)
+
+// runViewCommand は JSON・JSONL 形式でエクスポートしたレポートを、読み取り専用のビューアーで表示します。
+// 内容はレポートから読み込むため、元のフォルダがない環境でもフォルダ構成とファイルの内容を閲覧できます。
+// -source で元のフォルダを指定した場合、レポートに内容を含まないファイルは、選択したときにフォルダから先頭部分を表示します
+func runViewCommand(args []string) error {
+	flags := flag.NewFlagSet("view", flag.ContinueOnError)
+	sourceDir := flags.String("source", "", "レポートに内容を含まないファイルのプレビューを読み込む、元のフォルダ")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "使い方: folderscope view [-source <フォルダ>] <レポート.json|.jsonl|.json.gz>")
+		flags.PrintDefaults()
+	}
+	parseFlags(flags, args)
+	if flags.NArg() != 1 {
+		return usageError("表示するレポート（JSON・JSONL 形式）を 1 つ指定してください")
+	}
+
+	path := flags.Arg(0)
+	scan, err := report.LoadExport(path)
+	if err != nil {
+		return err
+	}
+	gui.ShowViewer("FolderScope ビューアー - "+filepath.Base(path), viewerSummary(scan), viewerNodes(scan.Entries, *sourceDir))
+	return nil
+}
+
+// viewerSummary はビューアーの上部に表示する、レポートの作成日時と集計の文字列を返します
+func viewerSummary(scan *report.ExportedScan) string {
+	summary := fmt.Sprintf("ファイル: %d, フォルダ: %d, 合計サイズ: %s",
+		scan.Stats.TotalFiles, scan.Stats.TotalDirs, report.FormatSize(scan.Stats.TotalBytes))
+	if scan.GeneratedAt != nil {
+		summary = "作成日時: " + scan.GeneratedAt.Local().Format(report.MetadataTimeLayout) + ", " + summary
+	}
+	return summary
+}
+
+// viewerNodes はレポートのエントリを、ビューアーに表示する要素に変換します。
+// sourceDir が空でない場合、レポートに内容を含まないファイルは sourceDir の中のファイルをプレビューします
+func viewerNodes(entries []report.ExportEntry, sourceDir string) []gui.ViewerNode {
+	nodes := make([]gui.ViewerNode, 0, len(entries))
+	for _, e := range entries {
+		var details []string
+		if !e.IsDir {
+			details = append(details, "サイズ: "+report.FormatSize(e.Size))
+		}
+		if e.ModTime != nil {
+			details = append(details, "更新日時: "+e.ModTime.Local().Format(report.MetadataTimeLayout))
+		}
+		if e.Permissions != "" {
+			details = append(details, "パーミッション: "+e.Permissions)
+		}
+		for _, field := range []struct{ label, value string }{
+			{"リンク先", e.LinkTarget}, {"種類", e.MIMEType}, {"文字コード", e.Encoding}, {"改行コード", e.LineEnding},
+			{"所有者", e.Owner}, {"グループ", e.Group}, {"作成者", e.Authors}, {"SHA-256", e.Hash},
+		} {
+			if field.value != "" {
+				details = append(details, field.label+": "+field.value)
+			}
+		}
+		if e.Dangling {
+			details = append(details, "リンク切れ")
+		}
+		node := gui.ViewerNode{RelPath: e.RelPath, IsDir: e.IsDir, Details: strings.Join(details, ", "), Notice: e.Notice}
+		if e.Content != nil {
+			node.Content = *e.Content
+		} else if !e.IsDir && node.Notice == "" {
+			node.Notice = "内容はレポートに含まれていません"
+			if sourceDir != "" {
+				node.Preview = previewFunc(filepath.Join(sourceDir, filepath.FromSlash(e.RelPath)))
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// previewFunc は filePath の先頭部分を読み込み、ビューアーに表示する内容または表示できない理由を返す関数を返します
+func previewFunc(filePath string) func() (string, string) {
+	return func() (string, string) {
+		p, err := filesystem.PreviewFile(filePath, filesystem.DefaultPreviewBytes)
+		switch {
+		case err != nil:
+			return "", fmt.Sprintf("元のフォルダのファイルを読み込めません: %v", err)
+		case p.IsBinary:
+			return "", fmt.Sprintf("バイナリファイル（%s）のため表示しません", p.MIMEType)
+		case p.Truncated:
+			return p.Content + fmt.Sprintf("\n... 先頭の %s のみ表示しています（全体: %s）", report.FormatSize(filesystem.DefaultPreviewBytes), report.FormatSize(p.Size)), ""
+		}
+		return p.Content, ""
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// selectEntries は、-where が指定されている場合に、entries を条件式を満たすファイルとそれらを含むフォルダに絞り込みます
+func (cfg *runConfig) selectEntries(logger logging.Logger, entries []model.FileSystemEntry) []model.FileSystemEntry {
+	if cfg.where == nil {
+		return entries
+	}
+	selected := cfg.where.Filter(entries, time.Now())
+	logger.Log("INFO", fmt.Sprintf("条件式 '%s' に一致するファイルに絞り込みました（%d 件中 %d 件）",
+		cfg.where, countFiles(entries), countFiles(selected)), nil)
+	return selected
+}
@@ -0,0 +1,87 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+)
+
+// CancelReason は処理が中止された理由です。ログ・レポート・履歴に記録し、終了コードの決定に使用します
+type CancelReason string
+
+// 処理が中止された理由です
+const (
+	// CancelUser はユーザー操作（キャンセルボタン・確認画面での中止など）による中止です
+	CancelUser CancelReason = "user"
+	// CancelTimeout は実行時間の上限（タイムアウト）による中止です
+	CancelTimeout CancelReason = "timeout"
+	// CancelSignal は割り込みや終了のシグナル（Ctrl+C・SIGTERM など）による中止です
+	CancelSignal CancelReason = "signal"
+)
+
+// Label は理由の説明を返します
+func (r CancelReason) Label() string {
+	switch r {
+	case CancelTimeout:
+		return "タイムアウト"
+	case CancelSignal:
+		return "シグナル"
+	}
+	return "ユーザー操作"
+}
+
+// CancelError は中止の理由をあわせ持つ、ErrCancelled の種類のエラーです。
+// コンテキストのキャンセルの原因（context.Cause）として使用し、中止の理由を呼び出し側に伝えます
+type CancelError struct {
+	// Reason は中止の理由です
+	Reason CancelReason
+	// Detail は理由の補足（シグナルの名前・タイムアウトの時間など）です
+	Detail string
+}
+
+// Cancelled は理由と補足を指定して CancelError を作成します
+func Cancelled(reason CancelReason, detail string) *CancelError {
+	return &CancelError{Reason: reason, Detail: detail}
+}
+
+// Error はエラーメッセージを返します
+func (e *CancelError) Error() string {
+	msg := ErrCancelled.Error() + "（理由: " + e.Reason.Label()
+	if e.Detail != "" {
+		msg += "、" + e.Detail
+	}
+	return msg + "）"
+}
+
+// Is は errors.Is で ErrCancelled と判定できるようにします
+func (e *CancelError) Is(target error) bool {
+	return target == ErrCancelled
+}
+
+// ReasonOf は err が処理の中止を示す場合にその理由を返します。中止ではない場合は空文字列を返します。
+// 理由を持たない中止は、タイムアウト（context.DeadlineExceeded）を除いてユーザー操作による中止とみなします
+func ReasonOf(err error) CancelReason {
+	var cancelErr *CancelError
+	switch {
+	case errors.As(err, &cancelErr):
+		return cancelErr.Reason
+	case errors.Is(err, context.DeadlineExceeded):
+		return CancelTimeout
+	case Classify(err) == ErrCancelled:
+		return CancelUser
+	}
+	return ""
+}
+
+// ContextReason は ctx がキャンセルされた原因（context.Cause）から、中止の理由を返します。
+// ctx がキャンセルされていない場合は、err から理由を判定します
+func ContextReason(ctx context.Context, err error) CancelReason {
+	if Classify(err) != ErrCancelled {
+		return ""
+	}
+	if ctx != nil {
+		if reason := ReasonOf(context.Cause(ctx)); reason != "" {
+			return reason
+		}
+	}
+	return ReasonOf(err)
+}
@@ -0,0 +1,100 @@
+// Package apperrors はパッケージをまたいで判定に使用するエラーの種類を提供します。
+// 呼び出し側はエラーメッセージの文字列ではなく errors.Is / errors.As で種類を判定します
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+// エラーの種類です。各パッケージのエラーはこれらのいずれかを errors.Is で判定できるように返します
+var (
+	// ErrNotFound は指定されたパスが存在しないことを示します
+	ErrNotFound = errors.New("パスが存在しません")
+	// ErrNotDirectory は指定されたパスがディレクトリではないことを示します
+	ErrNotDirectory = errors.New("ディレクトリではありません")
+	// ErrPermission はアクセス権限が不足していることを示します
+	ErrPermission = errors.New("アクセス権限がありません")
+	// ErrCancelled はユーザー操作やコンテキストのキャンセルによって処理が中止されたことを示します
+	ErrCancelled = errors.New("処理がキャンセルされました")
+	// ErrOutputExists は出力ファイルがすでに存在することを示します
+	ErrOutputExists = errors.New("出力ファイルがすでに存在します")
+	// ErrInvalidInput はオプションの値・組み合わせや、調査対象・出力先の指定が不正であることを示します
+	ErrInvalidInput = errors.New("入力が不正です")
+	// ErrPartial は処理を完了したものの、一部のファイル・フォルダを読み込めなかったことを示します
+	ErrPartial = errors.New("一部のファイル・フォルダを処理できませんでした")
+)
+
+// Error はエラーの種類（Kind）と原因（Err）をあわせ持つエラーです。
+// errors.Is で種類と原因の両方を判定でき、errors.As で対象のパスを取り出せます
+type Error struct {
+	// Kind はエラーの種類です（ErrNotFound など）。分類できない場合は nil です
+	Kind error
+	// Message はエラーの説明です。空の場合は Kind のメッセージを使用します
+	Message string
+	// Path はエラーの対象となったパスです
+	Path string
+	// Err は原因となったエラーです
+	Err error
+}
+
+// New は種類・説明・対象パス・原因を指定して Error を作成します
+func New(kind error, message, path string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Path: path, Err: err}
+}
+
+// Wrap は原因のエラーから種類を判定して Error を作成します
+func Wrap(message, path string, err error) *Error {
+	return New(Classify(err), message, path, err)
+}
+
+// Error はエラーメッセージを返します
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.Kind != nil {
+		msg = e.Kind.Error()
+	}
+	if e.Err != nil {
+		if msg == "" {
+			return e.Err.Error()
+		}
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap は種類と原因のエラーを返します
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// Classify は OS やコンテキストのエラーからエラーの種類を判定します。分類できない場合は nil を返します
+func Classify(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		return ErrNotFound
+	case errors.Is(err, ErrPermission), errors.Is(err, fs.ErrPermission):
+		return ErrPermission
+	case errors.Is(err, ErrCancelled), errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrCancelled
+	case errors.Is(err, ErrNotDirectory):
+		return ErrNotDirectory
+	case errors.Is(err, ErrOutputExists):
+		return ErrOutputExists
+	case errors.Is(err, ErrInvalidInput):
+		return ErrInvalidInput
+	case errors.Is(err, ErrPartial):
+		return ErrPartial
+	}
+	return nil
+}
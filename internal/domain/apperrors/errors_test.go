@@ -0,0 +1,92 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"存在しない", fmt.Errorf("stat: %w", fs.ErrNotExist), ErrNotFound},
+		{"権限なし", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}, ErrPermission},
+		{"キャンセル", fmt.Errorf("walk: %w", context.Canceled), ErrCancelled},
+		{"タイムアウト", context.DeadlineExceeded, ErrCancelled},
+		{"種類つきのエラー", New(ErrNotDirectory, "ディレクトリではありません", "/x", nil), ErrNotDirectory},
+		{"不正な入力", fmt.Errorf("解析: %w", New(ErrInvalidInput, "-format が不正です", "", nil)), ErrInvalidInput},
+		{"一部を処理できない", New(ErrPartial, "2 件のエラー", "", nil), ErrPartial},
+		{"分類できない", errors.New("unknown"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := &fs.PathError{Op: "open", Path: "/data", Err: fs.ErrPermission}
+	err := fmt.Errorf("スキャンに失敗しました: %w", Wrap("ディレクトリを読み取れません", "/data", cause))
+
+	if !errors.Is(err, ErrPermission) {
+		t.Error("種類で判定できるべきです")
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Error("原因のエラーでも判定できるべきです")
+	}
+	var appErr *Error
+	if !errors.As(err, &appErr) || appErr.Path != "/data" {
+		t.Errorf("errors.As でパスを取り出せるべきです: %v", appErr)
+	}
+	if got, want := appErr.Error(), "ディレクトリを読み取れません: "+cause.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := New(ErrCancelled, "", "", nil).Error(); got != ErrCancelled.Error() {
+		t.Errorf("説明がない場合は種類のメッセージを使用するべきです: %q", got)
+	}
+}
+
+func TestContextReason(t *testing.T) {
+	signalled, cancel := context.WithCancelCause(context.Background())
+	cancel(Cancelled(CancelSignal, "interrupt"))
+	timedOut, cancelTimeout := context.WithTimeout(context.Background(), 0)
+	defer cancelTimeout()
+	<-timedOut.Done()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want CancelReason
+	}{
+		{"中止ではない", signalled, errors.New("unknown"), ""},
+		{"シグナルによるキャンセル", signalled, fmt.Errorf("スキャンに失敗しました: %w", signalled.Err()), CancelSignal},
+		{"タイムアウト", timedOut, fmt.Errorf("スキャンに失敗しました: %w", timedOut.Err()), CancelTimeout},
+		{"理由を持つエラー", context.Background(), fmt.Errorf("出力を中止しました: %w", Cancelled(CancelTimeout, "")), CancelTimeout},
+		{"理由を持たないキャンセル", context.Background(), ErrCancelled, CancelUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContextReason(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("ContextReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	err := Cancelled(CancelSignal, "interrupt")
+	if !errors.Is(err, ErrCancelled) || Classify(err) != ErrCancelled {
+		t.Error("理由を持つエラーは ErrCancelled で判定できるべきです")
+	}
+	if want := "処理がキャンセルされました（理由: シグナル、interrupt）"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+// テキストファイルの文字コードを表す名前です（IANA の登録名）
+const (
+	EncodingUTF8     = "UTF-8"
+	EncodingUTF16LE  = "UTF-16LE"
+	EncodingUTF16BE  = "UTF-16BE"
+	EncodingShiftJIS = "Shift_JIS"
+	EncodingEUCJP    = "EUC-JP"
+	EncodingLatin1   = "ISO-8859-1"
+)
+
+// テキストファイルの改行コードを表す名前です
+const (
+	LineEndingLF   = "LF"
+	LineEndingCRLF = "CRLF"
+	LineEndingCR   = "CR"
+	// LineEndingMixed は 1 つのファイルに複数の種類の改行コードが含まれていることを表します
+	LineEndingMixed = "mixed"
+)
@@ -1,18 +1,58 @@
 // package model はドメインモデルを定義します
 package model
 
+import (
+	"io/fs"
+	"time"
+)
+
 // FileSystemEntry はファイルシステムの要素（ファイルまたはディレクトリ）を表します
 type FileSystemEntry struct {
 	// Path は要素の絶対パスを表します
-	Path string
+	Path string `json:"path"`
 	// IsDir はディレクトリであるかどうかを示します
-	IsDir bool
+	IsDir bool `json:"isDir"`
 	// RelPath はルートディレクトリからの相対パスを表します
-	RelPath string
+	RelPath string `json:"relPath"`
 	// Depth はルートディレクトリからの深さを表します
-	Depth int
+	Depth int `json:"depth"`
 	// ReadErr はファイル読み込み時のエラーを保持します
-	ReadErr error
+	ReadErr error `json:"-"`
 	// IsBinary はファイルがバイナリファイルであるかどうかを示します
-	IsBinary bool
+	IsBinary bool `json:"isBinary"`
+	// MIMEType は内容から判定したメディアタイプ（"image/png" など）を表します。判定していない場合は空です
+	MIMEType string `json:"mimeType,omitempty"`
+	// Encoding はテキストファイルの文字コード（EncodingUTF8 など）を表します。バイナリファイルや判定していない場合は空です
+	Encoding string `json:"encoding,omitempty"`
+	// LineEnding はテキストファイルの改行コード（LineEndingLF など）を表します。改行を含まないファイル・バイナリファイル・判定していない場合は空です
+	LineEnding string `json:"lineEnding,omitempty"`
+	// Size はファイルサイズ（バイト）を表します
+	Size int64 `json:"size"`
+	// ModTime は最終更新日時を表します
+	ModTime time.Time `json:"modTime"`
+	// Permissions はファイルモード（種別とパーミッションビット）を表します
+	Permissions fs.FileMode `json:"permissions"`
+	// Owner と Group はファイルの所有者とグループの名前を表します。名前を解決できない場合は ID の数値で、取得できない環境（Windows・アーカイブなど）では空です
+	Owner string `json:"owner,omitempty"`
+	Group string `json:"group,omitempty"`
+	// Hash はファイル内容の SHA-256 ハッシュ（16進文字列）を表します。計算していない場合は空です
+	Hash string `json:"hash,omitempty"`
+	// LinkTarget はシンボリックリンクの場合のリンク先のパス（リンクに記録された文字列）を表します。リンクでない場合や取得できない場合は空です
+	LinkTarget string `json:"linkTarget,omitempty"`
+	// Dangling はシンボリックリンクのリンク先が存在しない（リンク切れである）かどうかを示します
+	Dangling bool `json:"dangling,omitempty"`
+	// LinkToDir はシンボリックリンクのリンク先がディレクトリで、リンクとして記録した（たどらなかった）かどうかを示します
+	LinkToDir bool `json:"linkToDir,omitempty"`
+	// HardLinkOf は同じ inode を共有するファイル（ハードリンク）のうち、先に記録したファイルの相対パスを表します。
+	// ハードリンクでない場合や、最初に記録したファイルの場合は空です
+	HardLinkOf string `json:"hardLinkOf,omitempty"`
+	// ContentMode はパスごとのルールで指定されたファイル内容の出力方法（"full", "headtail", "skip", "structure"）を表します。
+	// 空の場合はレポートの設定（サイズの段階など）に従います
+	ContentMode string `json:"contentMode,omitempty"`
+}
+
+// IsLinkOnly は内容を持たないシンボリックリンク（ディレクトリへのリンク、リンク切れ）かどうかを返します。
+// このエントリはリンクとしてのみ記録し、ファイルの内容は読み込みません
+func (e FileSystemEntry) IsLinkOnly() bool {
+	return e.LinkTarget != "" && (e.LinkToDir || e.Dangling)
 }
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -65,3 +67,28 @@ func TestFileSystemEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestFileSystemEntry_JSON(t *testing.T) {
+	entry := FileSystemEntry{
+		Path:    "/test/file.txt",
+		RelPath: "file.txt",
+		Size:    42,
+		ReadErr: errors.New("読み込みエラー"),
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("JSONエンコードに失敗: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("JSONデコードに失敗: %v", err)
+	}
+	if decoded["relPath"] != "file.txt" || decoded["size"] != float64(42) {
+		t.Errorf("JSONの内容が不正: %s", data)
+	}
+	if _, ok := decoded["ReadErr"]; ok {
+		t.Errorf("ReadErr はJSONに含まれるべきではありません: %s", data)
+	}
+}
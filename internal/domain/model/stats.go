@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// SkipReason はスキャン時にエントリが結果から除外された理由です
+type SkipReason string
+
+const (
+	// SkipIgnored は無視パターンに一致したことを示します
+	SkipIgnored SkipReason = "ignored"
+	// SkipHidden は隠しファイル・隠しフォルダを含めない設定によって除外されたことを示します
+	SkipHidden SkipReason = "hidden"
+	// SkipExcluded は除外正規表現に一致したことを示します
+	SkipExcluded SkipReason = "excluded"
+	// SkipNotIncluded は包含正規表現のいずれにも一致しなかったことを示します
+	SkipNotIncluded SkipReason = "notIncluded"
+	// SkipBinary はバイナリファイルを除外する設定によって除外されたことを示します
+	SkipBinary SkipReason = "binary"
+	// SkipRule はパスごとのルールで除外（exclude）が指定されたことを示します
+	SkipRule SkipReason = "rule"
+	// SkipSize はファイルサイズが指定された範囲外であったことを示します
+	SkipSize SkipReason = "size"
+	// SkipModTime は更新日時が指定された期間外であったことを示します
+	SkipModTime SkipReason = "modTime"
+	// SkipSymlink はシンボリックリンクを含めない設定によって除外されたことを示します
+	SkipSymlink SkipReason = "symlink"
+	// SkipAccessError はアクセスできなかったことを示します
+	SkipAccessError SkipReason = "accessError"
+)
+
+// ScanStats は 1 回のスキャンの実行に関する統計情報です
+type ScanStats struct {
+	// StartedAt はスキャンを開始した時刻です
+	StartedAt time.Time `json:"startedAt"`
+	// DurationMillis はスキャンにかかった時間（ミリ秒）です
+	DurationMillis int64 `json:"durationMs"`
+	// Files と Dirs はスキャン結果に含まれたファイルとフォルダの数です
+	Files int `json:"files"`
+	Dirs  int `json:"dirs"`
+	// BytesRead はスキャン中にファイルから読み込んだバイト数です。種類の判定に読み込んだ先頭部分と、ハッシュの計算で読み込んだ内容を数えます
+	BytesRead int64 `json:"bytesRead"`
+	// Errors はアクセスや読み込みに失敗したパスの数です。結果に含まれたエントリの読み込みエラーも数えます
+	Errors int `json:"errors"`
+	// Skipped は除外された理由ごとのエントリ数です。除外されたディレクトリの配下は数えません
+	Skipped map[SkipReason]int `json:"skipped"`
+	// Inaccessible は権限がないために読み込めなかったパスです（走査した順）。フォルダの場合は配下がスキャン結果に含まれていません
+	Inaccessible []InaccessiblePath `json:"inaccessible,omitempty"`
+}
+
+// InaccessiblePath は権限がないために読み込めなかったパスです
+type InaccessiblePath struct {
+	// RelPath はルートディレクトリからの相対パスです。ルートディレクトリ自体の場合は "." です
+	RelPath string `json:"relPath"`
+	// IsDir はフォルダであるかどうかを示します
+	IsDir bool `json:"isDir"`
+}
+
+// RecordSkip は除外されたエントリを 1 件記録します
+func (s *ScanStats) RecordSkip(reason SkipReason) {
+	if s.Skipped == nil {
+		s.Skipped = make(map[SkipReason]int)
+	}
+	s.Skipped[reason]++
+}
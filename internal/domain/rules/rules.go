@@ -0,0 +1,190 @@
+// Package rules は、パスごとにファイルの扱い（除外・内容の出力方法）を上書きするルールを評価する機能を提供します。
+//
+// ルールは相対パスに対するパターンと扱いの組で、ルールファイルに次のように記述します:
+//
+//	{"rules": [
+//	  {"path": "docs/**", "mode": "full"},
+//	  {"path": "testdata/**", "mode": "structure"},
+//	  {"path": "third_party/**", "mode": "exclude"}
+//	]}
+//
+// パスに一致するルールが複数ある場合は、後に記述したルールを優先します
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Mode はルールで指定するファイルの扱いです
+type Mode string
+
+const (
+	// ModeExclude はスキャン結果から除外します。フォルダの場合は配下も除外します
+	ModeExclude Mode = "exclude"
+	// ModeFull はファイル内容をサイズにかかわらずすべて出力します
+	ModeFull Mode = "full"
+	// ModeHeadTail はファイル内容の先頭と末尾のみを出力します
+	ModeHeadTail Mode = "headtail"
+	// ModeOutline はソースコードの宣言の行のみを出力します
+	ModeOutline Mode = "outline"
+	// ModeSkip はファイル内容を出力せず、スキップした旨のみを記載します
+	ModeSkip Mode = "skip"
+	// ModeStructure はファイルをフォルダ構成にのみ表示します
+	ModeStructure Mode = "structure"
+)
+
+// ParseMode は文字列からファイルの扱いを解決します
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "exclude":
+		return ModeExclude, nil
+	case "full":
+		return ModeFull, nil
+	case "headtail", "head-tail":
+		return ModeHeadTail, nil
+	case "outline":
+		return ModeOutline, nil
+	case "skip":
+		return ModeSkip, nil
+	case "structure":
+		return ModeStructure, nil
+	}
+	return "", fmt.Errorf("未対応のファイルの扱いです: %s（exclude, full, headtail, outline, skip, structure のいずれかを指定してください）", s)
+}
+
+// Rule はパスごとにファイルの扱いを上書きするルールです
+type Rule struct {
+	// Path はルートからの相対パス（'/' 区切り）に対するパターンです。
+	// '*' と '?' は '/' 以外の文字に、"**" は 0 個以上のフォルダに一致します。
+	// '/' を含まないパターン（"*.min.js" など）は、どの深さのファイル名・フォルダ名にも一致します
+	Path string `json:"path"`
+	// Mode は一致したファイルの扱いです
+	Mode string `json:"mode"`
+}
+
+// File はルールを定義するファイルの内容です
+type File struct {
+	Rules []Rule `json:"rules"`
+}
+
+// ParseFile はルールファイルの内容を解析します
+func ParseFile(data []byte) ([]Rule, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("ルールファイルの解析に失敗しました: %w", err)
+	}
+	return file.Rules, nil
+}
+
+// ValidatePattern はルールのパスのパターンが空でなく、正しい形式かどうかを検証します
+func ValidatePattern(pattern string) error {
+	pattern = strings.Trim(strings.TrimSpace(pattern), "/")
+	if pattern == "" {
+		return fmt.Errorf("path を指定してください")
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		// path.Match は不正なパターンの場合のみエラーを返すため、空文字列との照合で検証する
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("パターンが不正です: %w", err)
+		}
+	}
+	return nil
+}
+
+// compiledRule はパターンを区切りごとに分割したルールです
+type compiledRule struct {
+	segments []string
+	// anyDepth はパターンが '/' を含まず、どの深さの名前にも一致するかどうかを示します
+	anyDepth bool
+	mode     Mode
+	// rule は定義したときのルールです
+	rule Rule
+}
+
+// Engine はコンパイル済みのルールの一覧です。nil の Engine はどのパスにも一致しません
+type Engine struct {
+	rules []compiledRule
+}
+
+// Compile はルールを検証し、評価できる形式に変換します。ルールがない場合は nil を返します
+func Compile(rules []Rule) (*Engine, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	engine := &Engine{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		if err := ValidatePattern(rule.Path); err != nil {
+			return nil, fmt.Errorf("ルール %d: %w", i+1, err)
+		}
+		mode, err := ParseMode(rule.Mode)
+		if err != nil {
+			return nil, fmt.Errorf("ルール '%s': %w", rule.Path, err)
+		}
+		pattern := strings.Trim(strings.TrimSpace(rule.Path), "/")
+		segments := strings.Split(pattern, "/")
+		engine.rules = append(engine.rules, compiledRule{segments: segments, anyDepth: !strings.Contains(pattern, "/"), mode: mode, rule: rule})
+	}
+	return engine, nil
+}
+
+// Match は相対パス relPath に一致するルールのうち、最後のルールの扱いを返します。一致するルールがない場合は false を返します
+func (e *Engine) Match(relPath string) (Mode, bool) {
+	rule, ok := e.lookup(relPath)
+	if !ok {
+		return "", false
+	}
+	return rule.mode, true
+}
+
+// MatchRule は相対パス relPath に一致するルールのうち、最後のルールを定義したときの内容で返します。
+// 一致するルールがない場合は false を返します。どのルールが適用されたかを説明するために使用します
+func (e *Engine) MatchRule(relPath string) (Rule, bool) {
+	rule, ok := e.lookup(relPath)
+	return rule.rule, ok
+}
+
+// lookup は相対パス relPath に一致する最後のルールを返します
+func (e *Engine) lookup(relPath string) (compiledRule, bool) {
+	if e == nil {
+		return compiledRule{}, false
+	}
+	parts := strings.Split(relPath, "/")
+	for i := len(e.rules) - 1; i >= 0; i-- {
+		rule := e.rules[i]
+		if rule.anyDepth {
+			if matched, _ := path.Match(rule.segments[0], parts[len(parts)-1]); matched {
+				return rule, true
+			}
+			continue
+		}
+		if matchSegments(rule.segments, parts) {
+			return rule, true
+		}
+	}
+	return compiledRule{}, false
+}
+
+// matchSegments はパターンの区切り patterns がパスの区切り parts に一致するかどうかを返します。"**" は 0 個以上の区切りに一致します
+func matchSegments(patterns, parts []string) bool {
+	if len(patterns) == 0 {
+		return len(parts) == 0
+	}
+	if patterns[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(patterns[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(patterns[0], parts[0]); !matched {
+		return false
+	}
+	return matchSegments(patterns[1:], parts[1:])
+}
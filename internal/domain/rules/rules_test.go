@@ -0,0 +1,91 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEngine_Match(t *testing.T) {
+	engine, err := Compile([]Rule{
+		{Path: "docs/**", Mode: "full"},
+		{Path: "docs/generated/**", Mode: "structure"},
+		{Path: "*.min.js", Mode: "skip"},
+		{Path: "src/*/vendor", Mode: "exclude"},
+		{Path: "/third_party/", Mode: "exclude"},
+	})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		relPath string
+		want    Mode
+		wantOK  bool
+	}{
+		{relPath: "docs", want: ModeFull, wantOK: true},
+		{relPath: "docs/a/b/guide.md", want: ModeFull, wantOK: true},
+		{relPath: "docs/generated/api.md", want: ModeStructure, wantOK: true},
+		{relPath: "web/app.min.js", want: ModeSkip, wantOK: true},
+		{relPath: "docs/app.min.js", want: ModeSkip, wantOK: true},
+		{relPath: "src/pkg/vendor", want: ModeExclude, wantOK: true},
+		{relPath: "src/pkg/sub/vendor", wantOK: false},
+		{relPath: "third_party", want: ModeExclude, wantOK: true},
+		{relPath: "third_party/x.go", wantOK: false},
+		{relPath: "readme.md", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			got, ok := engine.Match(tt.relPath)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Match(%q) = %q, %v, want %q, %v", tt.relPath, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEngine_MatchRule(t *testing.T) {
+	engine, err := Compile([]Rule{
+		{Path: "docs/**", Mode: "full"},
+		{Path: "/third_party/", Mode: "exclude"},
+	})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	// 定義したときのパターンを返す
+	if rule, ok := engine.MatchRule("third_party"); !ok || rule.Path != "/third_party/" || rule.Mode != "exclude" {
+		t.Errorf("MatchRule(third_party) = %+v, %v", rule, ok)
+	}
+	if rule, ok := engine.MatchRule("src/main.go"); ok {
+		t.Errorf("MatchRule(src/main.go) = %+v, want no match", rule)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr string
+	}{
+		{name: "パスがない", rules: []Rule{{Path: " ", Mode: "full"}}, wantErr: "ルール 1: path を指定してください"},
+		{name: "未対応の扱い", rules: []Rule{{Path: "docs/**", Mode: "all"}}, wantErr: "未対応のファイルの扱いです: all"},
+		{name: "不正なパターン", rules: []Rule{{Path: "docs/[a", Mode: "full"}}, wantErr: "パターンが不正です"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.rules)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want %q を含むエラー", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	rules, err := ParseFile([]byte(`{"rules": [{"path": "testdata/**", "mode": "structure"}]}`))
+	if err != nil || len(rules) != 1 || rules[0] != (Rule{Path: "testdata/**", Mode: "structure"}) {
+		t.Errorf("ParseFile() = %v, %v", rules, err)
+	}
+	if _, err := ParseFile([]byte(`{"rules": `)); err == nil {
+		t.Error("ParseFile() で不正な JSON がエラーになりません")
+	}
+}
@@ -0,0 +1,15 @@
+package gui
+
+import (
+	_ "embed"
+
+	"fyne.io/fyne/v2"
+)
+
+// iconSVG はアプリケーションのアイコンです。配布するバイナリだけで表示できるよう埋め込みます
+//
+//go:embed assets/icon.svg
+var iconSVG []byte
+
+// appIcon はウィンドウとタスクバーに表示するアイコンのリソースです
+var appIcon = fyne.NewStaticResource("folderscope.svg", iconSVG)
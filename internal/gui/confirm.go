@@ -0,0 +1,71 @@
+package gui
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Confirm は message を表示して続行するかどうかを確認し、「続行」が押された場合に true を返します。
+// 「キャンセル」が押された場合やウィンドウが閉じられた場合は false を返します
+func Confirm(title, message string) (confirmed bool) {
+	runInWindow(title, func(w *Window) {
+		confirmed = w.Confirm(title, message)
+	})
+	return confirmed
+}
+
+// Confirm はウィンドウに確認画面を表示し、選択されるまで待機します。
+// 画面の動作はパッケージ関数の Confirm と同じです
+func (w *Window) Confirm(title, message string) bool {
+	done := make(chan bool, 1)
+	var once sync.Once
+	finish := func(confirmed bool) { once.Do(func() { done <- confirmed }) }
+
+	messageLabel := widget.NewLabel(message)
+	messageLabel.Wrapping = fyne.TextWrapWord
+
+	continueButton := widget.NewButton("続行", func() { finish(true) })
+	continueButton.Importance = widget.DangerImportance
+	cancelButton := widget.NewButton("キャンセル", func() { finish(false) })
+
+	w.setPage(container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		messageLabel,
+		layout.NewSpacer(),
+		container.NewHBox(layout.NewSpacer(), cancelButton, continueButton),
+	), func() { finish(false) })
+	return <-done
+}
+
+// Choose はウィンドウに message と options の各選択肢のボタンを表示し、押されたボタンの options 内の位置を返します。
+// ウィンドウが閉じられた場合は -1 を返します。options の最初の選択肢を推奨する選択肢として強調します
+func (w *Window) Choose(title, message string, options []string) int {
+	done := make(chan int, 1)
+	var once sync.Once
+	finish := func(choice int) { once.Do(func() { done <- choice }) }
+
+	messageLabel := widget.NewLabel(message)
+	messageLabel.Wrapping = fyne.TextWrapWord
+
+	buttons := container.NewHBox(layout.NewSpacer())
+	for i, option := range options {
+		i := i
+		button := widget.NewButton(option, func() { finish(i) })
+		if i == 0 {
+			button.Importance = widget.HighImportance
+		}
+		buttons.Add(button)
+	}
+
+	w.setPage(container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		messageLabel,
+		layout.NewSpacer(),
+		buttons,
+	), func() { finish(-1) })
+	return <-done
+}
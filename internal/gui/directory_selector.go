@@ -22,7 +22,9 @@ type DirectoryValidator interface {
 
 // DirectorySelector は、Fyneを使用してディレクトリ選択を行う構造体
 type DirectorySelector struct {
-	validator DirectoryValidator
+	validator     DirectoryValidator
+	recentSources []string
+	recentOutputs []string
 }
 
 // NewDirectorySelector は、DirectorySelectorの新しいインスタンスを作成します
@@ -32,6 +34,12 @@ func NewDirectorySelector(validator DirectoryValidator) *DirectorySelector {
 	}
 }
 
+// SetRecentDirectories は、ウィザードにクイック選択ボタンとして表示する最近使用したフォルダを設定します
+func (s *DirectorySelector) SetRecentDirectories(sources, outputs []string) {
+	s.recentSources = sources
+	s.recentOutputs = outputs
+}
+
 // SelectDirectory は、Fyneダイアログを使用してディレクトリを選択し、
 // 選択されたパスまたはエラーを返します
 func (s *DirectorySelector) SelectDirectory(title string) (string, error) {
@@ -42,6 +50,7 @@ func (s *DirectorySelector) SelectDirectory(title string) (string, error) {
 	}
 
 	a := app.New()
+	a.SetIcon(appIcon)
 	w := a.NewWindow(title)
 	w.Resize(fyne.NewSize(DefaultWindowWidth, DefaultWindowHeight))
 
@@ -54,18 +63,23 @@ func (s *DirectorySelector) SelectDirectory(title string) (string, error) {
 			return
 		}
 		if selectedURI == nil {
-			result.err = fmt.Errorf("ユーザーがキャンセルしました")
+			result.err = errSelectionCancelled
 			close(done)
 			return
 		}
 		path := selectedURI.Path()
-		if err := s.validator.ValidateDirectoryPath(path); err != nil {
-			result.err = fmt.Errorf("パス検証エラー: %w", err)
-			close(done)
-			return
-		}
-		result.path = path
-		close(done)
+		// ネットワークフォルダの検証で UI スレッドが止まらないよう、タイムアウト付きで別の goroutine から検証する
+		go func() {
+			defer close(done)
+			err := validateWithTimeout(ValidationTimeout, func() error {
+				return s.validator.ValidateDirectoryPath(path)
+			})
+			if err != nil {
+				result.err = fmt.Errorf("パス検証エラー: %w", err)
+				return
+			}
+			result.path = path
+		}()
 	}, w)
 	d.Show()
 	w.Show()
@@ -117,76 +131,3 @@ func openFolderDialog(w fyne.Window, title string, validator DirectoryValidator)
 	d.Show()
 	return selectedPath, resultErr
 }
-
-// SelectDirectories は、調査対象フォルダと出力先フォルダの選択を一括で行います。
-// UI 操作はメインスレッド上で、コールバックを連鎖させる形で実現します。
-func SelectDirectories(selector *DirectorySelector) (*DirectoryPaths, error) {
-	a := app.New()
-	w := a.NewWindow("FolderScope")
-	w.Resize(fyne.NewSize(DefaultWindowWidth, DefaultWindowHeight))
-
-	paths := &DirectoryPaths{}
-	var currentError error
-
-	// チェーン形式でダイアログを連続表示する
-	// まず、調査対象フォルダの選択
-	dialog.NewFolderOpen(func(sourceURI fyne.ListableURI, err error) {
-		if err != nil {
-			currentError = fmt.Errorf("調査対象フォルダの選択エラー: %w", err)
-			w.Close()
-			a.Quit()
-			return
-		}
-		if sourceURI == nil {
-			currentError = fmt.Errorf("調査対象フォルダの選択がキャンセルされました")
-			w.Close()
-			a.Quit()
-			return
-		}
-		sourcePath := sourceURI.Path()
-		if err := selector.validator.ValidateDirectoryPath(sourcePath); err != nil {
-			currentError = fmt.Errorf("調査対象フォルダが無効です: %w", err)
-			w.Close()
-			a.Quit()
-			return
-		}
-		paths.Source = sourcePath
-
-		// 次に、出力先フォルダの選択
-		dialog.NewFolderOpen(func(outputURI fyne.ListableURI, err error) {
-			if err != nil {
-				currentError = fmt.Errorf("出力先フォルダの選択エラー: %w", err)
-				w.Close()
-				a.Quit()
-				return
-			}
-			if outputURI == nil {
-				currentError = fmt.Errorf("出力先フォルダの選択がキャンセルされました")
-				w.Close()
-				a.Quit()
-				return
-			}
-			outputPath := outputURI.Path()
-			if err := selector.validator.ValidateDirectoryPath(outputPath); err != nil {
-				currentError = fmt.Errorf("出力先フォルダが無効です: %w", err)
-				w.Close()
-				a.Quit()
-				return
-			}
-			paths.Output = outputPath
-			// 両方の選択が完了したのでウィンドウを閉じる
-			w.Close()
-			a.Quit()
-		}, w).Show()
-
-	}, w).Show()
-
-	// ウィンドウ表示
-	w.Show()
-	a.Run()
-
-	if currentError != nil {
-		return nil, currentError
-	}
-	return paths, nil
-}
@@ -0,0 +1,115 @@
+package gui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// progressRefreshInterval は進捗表示を更新する間隔です。
+// スキャン中のコールバックごとに画面を更新すると描画が追いつかないため、一定間隔でまとめて反映します
+const progressRefreshInterval = 100 * time.Millisecond
+
+// Progress は進捗ウィンドウに表示する進捗状況です
+type Progress struct {
+	// CurrentPath は処理中のパスです
+	CurrentPath string
+	// Files は処理済みのファイル数です
+	Files int
+}
+
+// ProgressTask は進捗ウィンドウで実行する処理です。
+// ctx はキャンセルボタンが押されるとキャンセルされ、update で進捗を通知します
+type ProgressTask func(ctx context.Context, update func(Progress)) error
+
+// RunWithProgress は進捗ウィンドウを表示しながら task を実行し、task の結果を返します。
+// ウィンドウには処理中のパス・処理済みファイル数・キャンセルボタンが表示され、
+// キャンセルボタンまたはウィンドウを閉じる操作で task に渡したコンテキストがキャンセルされます
+func RunWithProgress(title string, task ProgressTask) (err error) {
+	runInWindow(title, func(w *Window) {
+		err = w.RunWithProgress(title, task)
+	})
+	return err
+}
+
+// RunWithProgress はウィンドウに進捗画面を表示しながら task を実行し、task の結果を返します。
+// 画面の動作はパッケージ関数の RunWithProgress と同じです
+func (w *Window) RunWithProgress(title string, task ProgressTask) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pathLabel := widget.NewLabel("準備中...")
+	pathLabel.Truncation = fyne.TextTruncateEllipsis
+	countLabel := widget.NewLabel("処理済みファイル: 0")
+	bar := widget.NewProgressBarInfinite()
+
+	cancelButton := widget.NewButton("キャンセル", nil)
+	cancelButton.OnTapped = func() {
+		cancelButton.Disable()
+		pathLabel.SetText("キャンセルしています...")
+		cancel()
+	}
+
+	// ウィンドウを閉じた場合もキャンセル扱いとし、task の終了を待ってから次の画面に進む
+	w.setPage(container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		bar,
+		pathLabel,
+		countLabel,
+		layout.NewSpacer(),
+		container.NewHBox(layout.NewSpacer(), cancelButton),
+	), func() {
+		cancelButton.OnTapped()
+	})
+
+	var (
+		mu      sync.Mutex
+		latest  Progress
+		changed bool
+	)
+	update := func(p Progress) {
+		mu.Lock()
+		latest = p
+		changed = true
+		mu.Unlock()
+	}
+
+	var taskErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		taskErr = task(ctx, update)
+	}()
+
+	ticker := time.NewTicker(progressRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			bar.Stop()
+			// キャンセルによる終了は、task がコンテキストのエラーをそのまま返した場合も apperrors.ErrCancelled で判定できるようにする
+			if errors.Is(taskErr, context.Canceled) && !errors.Is(taskErr, apperrors.ErrCancelled) {
+				return apperrors.New(apperrors.ErrCancelled, "", "", taskErr)
+			}
+			return taskErr
+		case <-ticker.C:
+			mu.Lock()
+			p, ok := latest, changed
+			changed = false
+			mu.Unlock()
+			if ok && ctx.Err() == nil {
+				pathLabel.SetText(p.CurrentPath)
+				countLabel.SetText(fmt.Sprintf("処理済みファイル: %d", p.Files))
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package gui
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ResultAction は完了画面で選択された操作です
+type ResultAction int
+
+const (
+	// ResultClose は「閉じる」が押されたか、ウィンドウが閉じられたことを示します
+	ResultClose ResultAction = iota
+	// ResultRegenerate は設定を変更し、スキャン済みのエントリからレポートを再生成することを示します
+	ResultRegenerate
+	// ResultRescan はファイルシステムを再スキャンしてレポートを生成し直すことを示します
+	ResultRescan
+)
+
+// ShowResult はレポートの出力結果を表示し、次の操作が選択されるまで待機します。
+// 「設定を変更して再生成」は設定ダイアログを表示し、「保存」が押された場合のみ変更内容を settings に反映して ResultRegenerate を返します。
+// 「ファイルシステムを再スキャン」はフォルダの内容が変わった場合に使用し、ResultRescan を返します
+func (w *Window) ShowResult(title, message string, settings *Settings) ResultAction {
+	done := make(chan ResultAction, 1)
+	var once sync.Once
+	finish := func(action ResultAction) { once.Do(func() { done <- action }) }
+
+	messageLabel := widget.NewLabel(message)
+	messageLabel.Wrapping = fyne.TextWrapWord
+	settingsLabel := widget.NewLabel(settings.Summary())
+	settingsLabel.Wrapping = fyne.TextWrapWord
+
+	regenerateButton := widget.NewButton("設定を変更して再生成...", func() {
+		showSettingsDialog(w.window, settings, func() { finish(ResultRegenerate) })
+	})
+	rescanButton := widget.NewButton("ファイルシステムを再スキャン", func() { finish(ResultRescan) })
+	closeButton := widget.NewButton("閉じる", func() { finish(ResultClose) })
+	closeButton.Importance = widget.HighImportance
+
+	w.setPage(container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		messageLabel,
+		layout.NewSpacer(),
+		settingsLabel,
+		container.NewHBox(regenerateButton, rescanButton, layout.NewSpacer(), closeButton),
+	), func() { finish(ResultClose) })
+	action := <-done
+	w.setPage(widget.NewLabel(""), nil)
+	return action
+}
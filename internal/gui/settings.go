@@ -0,0 +1,108 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Settings はスキャン開始前に設定画面から変更できる項目です
+type Settings struct {
+	// IgnorePatterns はデフォルトの無視パターンに追加するパターンです
+	IgnorePatterns []string
+	// BinaryPolicy はバイナリファイルの扱い（skip, omit, structure, hexdump, base64）です
+	BinaryPolicy string
+	// BinaryPolicies は選択可能なバイナリファイルの扱いの一覧です
+	BinaryPolicies []string
+	// Format はレポートの出力形式です
+	Format string
+	// Formats は選択可能な出力形式の一覧です
+	Formats []string
+	// MaxFileSizeKB は内容を出力するファイルサイズの上限（KB）です。0 の場合は無制限です
+	MaxFileSizeKB int64
+}
+
+// Summary は設定内容を 1 行で表した文字列を返します
+func (s *Settings) Summary() string {
+	limit := "無制限"
+	if s.MaxFileSizeKB > 0 {
+		limit = fmt.Sprintf("%d KB", s.MaxFileSizeKB)
+	}
+	return fmt.Sprintf("出力形式: %s / バイナリ: %s / サイズ上限: %s / 追加の無視パターン: %d 件",
+		s.Format, s.BinaryPolicy, limit, len(s.IgnorePatterns))
+}
+
+// parseIgnorePatterns は 1 行 1 パターンで入力された無視パターンを分割します。空行は無視します
+func parseIgnorePatterns(text string) []string {
+	var patterns []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns
+}
+
+// parseSizeLimit はサイズ上限（KB）の入力値を解析します。空の場合は無制限（0）として扱います
+func parseSizeLimit(text string) (int64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || size < 0 {
+		return 0, errors.New("0 以上の整数を入力してください")
+	}
+	return size, nil
+}
+
+// showSettingsDialog は設定を編集するダイアログを表示します。
+// 「保存」が押された場合のみ settings を更新し、onSaved を呼び出します
+func showSettingsDialog(w fyne.Window, settings *Settings, onSaved func()) {
+	ignoreEntry := widget.NewMultiLineEntry()
+	ignoreEntry.SetPlaceHolder("1 行に 1 つずつ入力（例: node_modules）")
+	ignoreEntry.SetText(strings.Join(settings.IgnorePatterns, "\n"))
+	ignoreEntry.SetMinRowsVisible(4)
+
+	binarySelect := widget.NewSelect(settings.BinaryPolicies, nil)
+	binarySelect.SetSelected(settings.BinaryPolicy)
+
+	formatSelect := widget.NewSelect(settings.Formats, nil)
+	formatSelect.SetSelected(settings.Format)
+
+	sizeEntry := widget.NewEntry()
+	sizeEntry.SetPlaceHolder("0 で無制限")
+	sizeEntry.SetText(strconv.FormatInt(settings.MaxFileSizeKB, 10))
+	sizeEntry.Validator = func(text string) error {
+		_, err := parseSizeLimit(text)
+		return err
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("無視パターン", ignoreEntry),
+		{Text: "バイナリ", Widget: binarySelect, HintText: "omit: 除外 / skip: 内容を省略 / structure: 構成のみ / hexdump: 先頭を16進表示 / base64: 埋め込み"},
+		widget.NewFormItem("出力形式", formatSelect),
+		{Text: "サイズ上限（KB）", Widget: sizeEntry, HintText: "上限を超えるファイルは内容を出力しません"},
+	}
+	d := dialog.NewForm("設定", "保存", "キャンセル", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		// 入力値はダイアログの検証を通過しているため、ここでのエラーは発生しない
+		size, _ := parseSizeLimit(sizeEntry.Text)
+		settings.IgnorePatterns = parseIgnorePatterns(ignoreEntry.Text)
+		settings.BinaryPolicy = binarySelect.Selected
+		settings.Format = formatSelect.Selected
+		settings.MaxFileSizeKB = size
+		if onSaved != nil {
+			onSaved()
+		}
+	}, w)
+	d.Resize(fyne.NewSize(DefaultWindowWidth*0.8, DefaultWindowHeight*0.8))
+	d.Show()
+}
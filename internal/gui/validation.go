@@ -0,0 +1,37 @@
+package gui
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// ValidationTimeout はフォルダ検証の待ち時間の上限です。
+	// 応答のないネットワークフォルダ（UNC パスなど）を検証すると OS の呼び出しが長時間戻らないため、
+	// この時間を過ぎた場合は検証失敗として扱います
+	ValidationTimeout = 5 * time.Second
+	// validationSpinnerDelay は検証中の表示を出すまでの待ち時間です。
+	// ローカルフォルダの検証はすぐに終わるため、入力のたびに表示がちらつかないよう少し遅らせます
+	validationSpinnerDelay = 150 * time.Millisecond
+)
+
+// ErrValidationTimeout はフォルダの検証が ValidationTimeout 以内に終わらなかったことを示します
+var ErrValidationTimeout = errors.New("フォルダの検証がタイムアウトしました（ネットワークフォルダに接続できない可能性があります）")
+
+// validateWithTimeout は validate を別の goroutine で実行し、timeout 以内に終わらなければ ErrValidationTimeout を返します。
+// タイムアウトした validate は中断できないため、終了するまでバックグラウンドで実行され続けます
+func validateWithTimeout(timeout time.Duration, validate func() error) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- validate()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return ErrValidationTimeout
+	}
+}
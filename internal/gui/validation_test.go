@@ -0,0 +1,30 @@
+package gui
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateWithTimeout(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	block := make(chan struct{})
+	defer close(block)
+
+	tests := []struct {
+		name     string
+		validate func() error
+		want     error
+	}{
+		{"成功", func() error { return nil }, nil},
+		{"検証エラー", func() error { return errInvalid }, errInvalid},
+		{"応答なし", func() error { <-block; return nil }, ErrValidationTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateWithTimeout(50*time.Millisecond, tt.validate); !errors.Is(err, tt.want) {
+				t.Errorf("validateWithTimeout() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
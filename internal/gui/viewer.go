@@ -0,0 +1,128 @@
+package gui
+
+import (
+	"path"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ViewerNode はビューアーに表示する 1 要素分の情報です
+type ViewerNode struct {
+	// RelPath はルートからの相対パス（'/' 区切り）です
+	RelPath string
+	IsDir   bool
+	// Details はサイズ・更新日時などの表示用の文字列です
+	Details string
+	// Content はファイルの内容です。内容を表示できない場合は空で、Notice に理由を記載します
+	Content string
+	Notice  string
+	// Preview は Content を持たないファイルの内容を、選択したときに読み込む関数です。
+	// 内容または表示できない理由を返します。nil の場合は Notice を表示します
+	Preview func() (content, notice string)
+}
+
+// viewerTree はビューアーのフォルダ構成です。キーは相対パスで、ルートは空文字列です
+type viewerTree struct {
+	children map[string][]string
+	nodes    map[string]*ViewerNode
+}
+
+// buildViewerTree は nodes からフォルダ構成を作成します。子の並び順は nodes の順です。
+// 条件で絞り込んだレポートのように親フォルダのエントリがない場合は、フォルダを補います
+func buildViewerTree(nodes []ViewerNode) viewerTree {
+	t := viewerTree{children: make(map[string][]string), nodes: make(map[string]*ViewerNode)}
+	var add func(node *ViewerNode)
+	add = func(node *ViewerNode) {
+		if _, ok := t.nodes[node.RelPath]; ok {
+			return
+		}
+		parent := path.Dir(node.RelPath)
+		if parent == "." {
+			parent = ""
+		} else if _, ok := t.nodes[parent]; !ok {
+			add(&ViewerNode{RelPath: parent, IsDir: true})
+		}
+		t.nodes[node.RelPath] = node
+		t.children[parent] = append(t.children[parent], node.RelPath)
+	}
+	for i := range nodes {
+		if nodes[i].RelPath == "" || nodes[i].RelPath == "." {
+			continue
+		}
+		add(&nodes[i])
+	}
+	return t
+}
+
+// ShowViewer はエクスポートしたスキャン結果を閲覧する読み取り専用のビューアーを表示し、ウィンドウが閉じられるまで待機します
+func ShowViewer(title, summary string, nodes []ViewerNode) {
+	runInWindow(title, func(w *Window) {
+		w.ShowViewer(title, summary, nodes)
+	})
+}
+
+// ShowViewer はウィンドウにビューアーを表示し、「閉じる」ボタンが押されるかウィンドウが閉じられるまで待機します。
+// 左側のフォルダ構成で選択したファイルの情報と内容を右側に表示します。内容は編集できません
+func (w *Window) ShowViewer(title, summary string, nodes []ViewerNode) {
+	done := make(chan struct{})
+	var once sync.Once
+	finish := func() { once.Do(func() { close(done) }) }
+
+	t := buildViewerTree(nodes)
+	details := widget.NewLabel("左のフォルダ構成からファイルを選択してください")
+	details.Wrapping = fyne.TextWrapWord
+	content := widget.NewTextGrid()
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID { return t.children[id] },
+		func(id widget.TreeNodeID) bool { return id == "" || (t.nodes[id] != nil && t.nodes[id].IsDir) },
+		func(branch bool) fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TreeNodeID, branch bool, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(path.Base(id))
+		},
+	)
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		node := t.nodes[id]
+		if node == nil {
+			return
+		}
+		text := node.RelPath
+		if node.Details != "" {
+			text += "\n" + node.Details
+		}
+		details.SetText(text)
+		switch {
+		case node.IsDir:
+			content.SetText("")
+		case node.Content == "" && node.Preview != nil:
+			if text, notice := node.Preview(); notice != "" {
+				content.SetText(notice)
+			} else {
+				content.SetText(text)
+			}
+		case node.Notice != "":
+			content.SetText(node.Notice)
+		default:
+			content.SetText(node.Content)
+		}
+	}
+
+	summaryLabel := widget.NewLabel(summary)
+	summaryLabel.Wrapping = fyne.TextWrapWord
+	closeButton := widget.NewButton("閉じる", finish)
+
+	split := container.NewHSplit(tree, container.NewBorder(details, nil, nil, nil, container.NewScroll(content)))
+	split.Offset = 0.3
+	w.setPage(container.NewBorder(
+		container.NewVBox(widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), summaryLabel),
+		container.NewHBox(layout.NewSpacer(), closeButton),
+		nil, nil,
+		split,
+	), finish)
+	<-done
+	w.setPage(widget.NewLabel(""), nil)
+}
@@ -0,0 +1,29 @@
+package gui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildViewerTree(t *testing.T) {
+	tree := buildViewerTree([]ViewerNode{
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/main.go"},
+		// 親フォルダのエントリがないファイル
+		{RelPath: "docs/guide/intro.md"},
+		{RelPath: "README.md"},
+	})
+
+	want := map[string][]string{
+		"":           {"src", "docs", "README.md"},
+		"src":        {"src/main.go"},
+		"docs":       {"docs/guide"},
+		"docs/guide": {"docs/guide/intro.md"},
+	}
+	if !reflect.DeepEqual(tree.children, want) {
+		t.Errorf("children = %v, want %v", tree.children, want)
+	}
+	if node := tree.nodes["docs/guide"]; node == nil || !node.IsDir {
+		t.Errorf("補ったフォルダ = %+v, want フォルダ", node)
+	}
+}
@@ -0,0 +1,129 @@
+package gui
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Window は、フォルダ選択・設定・進捗・確認・完了の各画面を 1 つのウィンドウで切り替えて表示します。
+// 画面ごとのメソッドはユーザーの操作が終わるまでブロックするため、Run に渡した処理の中から順に呼び出します
+type Window struct {
+	app    fyne.App
+	window fyne.Window
+
+	mu      sync.Mutex
+	onClose func()
+	// onFolderRequest は他のプロセスから調査対象フォルダが渡されたときに呼び出されます。フォルダ選択画面の表示中のみ設定します
+	onFolderRequest func(dir string)
+	// requestedFolder はフォルダ選択画面以外の表示中に渡された調査対象フォルダです。次にフォルダ選択画面を表示するときに使用します
+	requestedFolder string
+}
+
+// NewWindow は指定されたタイトルで Window を作成します
+func NewWindow(title string) *Window {
+	a := app.New()
+	a.SetIcon(appIcon)
+	w := &Window{app: a, window: a.NewWindow(title)}
+	w.window.Resize(fyne.NewSize(DefaultWindowWidth, DefaultWindowHeight))
+	w.window.SetMaster()
+	w.window.SetCloseIntercept(w.handleClose)
+	return w
+}
+
+// Run はウィンドウを表示して flow を別の goroutine で実行し、flow が終了するとウィンドウを閉じます。
+// Fyne のイベントループを実行するため、メインの goroutine から呼び出す必要があります
+func (w *Window) Run(flow func()) {
+	go func() {
+		defer w.app.Quit()
+		flow()
+	}()
+	w.window.ShowAndRun()
+}
+
+// setPage はウィンドウの内容を切り替えます。
+// onClose はウィンドウを閉じる操作が行われたときに呼び出され、表示中の画面の処理を中止させます
+func (w *Window) setPage(content fyne.CanvasObject, onClose func()) {
+	w.mu.Lock()
+	w.onClose = onClose
+	w.mu.Unlock()
+	w.window.SetContent(content)
+}
+
+// handleClose はウィンドウを閉じる操作を表示中の画面に通知します。
+// 画面の切り替え中（レポートの書き込み中など）は処理を途中で打ち切らないよう、閉じる操作を無視します
+func (w *Window) handleClose() {
+	w.mu.Lock()
+	onClose := w.onClose
+	w.mu.Unlock()
+	if onClose != nil {
+		onClose()
+	}
+}
+
+// RequestFolder は他のプロセス（2 つ目に起動した FolderScope など）から渡された調査対象フォルダを受け取り、ウィンドウを前面に表示します。
+// フォルダ選択画面の表示中は調査対象の入力欄に設定し、スキャン中などそれ以外の画面では、次にフォルダ選択画面を表示するまで保持します。
+// dir が空の場合はウィンドウを前面に表示するだけです。任意の goroutine から呼び出せます
+func (w *Window) RequestFolder(dir string) {
+	w.window.RequestFocus()
+	if dir == "" {
+		return
+	}
+	w.mu.Lock()
+	handle := w.onFolderRequest
+	if handle == nil {
+		w.requestedFolder = dir
+	}
+	w.mu.Unlock()
+	if handle != nil {
+		handle(dir)
+	}
+}
+
+// TakeRequestedFolder は RequestFolder で渡されて保持している調査対象フォルダを返し、保持を解除します。ない場合は空文字列を返します
+func (w *Window) TakeRequestedFolder() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dir := w.requestedFolder
+	w.requestedFolder = ""
+	return dir
+}
+
+// setFolderHandler は他のプロセスから調査対象フォルダが渡されたときの処理を設定します。nil で解除します
+func (w *Window) setFolderHandler(handle func(dir string)) {
+	w.mu.Lock()
+	w.onFolderRequest = handle
+	w.mu.Unlock()
+}
+
+// ShowMessage はタイトルとメッセージを表示し、「閉じる」ボタンが押されるかウィンドウが閉じられるまで待機します。
+// 処理の完了やエラーの通知に使用します
+func (w *Window) ShowMessage(title, message string) {
+	done := make(chan struct{})
+	var once sync.Once
+	finish := func() { once.Do(func() { close(done) }) }
+
+	messageLabel := widget.NewLabel(message)
+	messageLabel.Wrapping = fyne.TextWrapWord
+	closeButton := widget.NewButton("閉じる", finish)
+	closeButton.Importance = widget.HighImportance
+
+	w.setPage(container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		messageLabel,
+		layout.NewSpacer(),
+		container.NewHBox(layout.NewSpacer(), closeButton),
+	), finish)
+	<-done
+	w.setPage(widget.NewLabel(""), nil)
+}
+
+// runInWindow は 1 画面だけを表示する一時的な Window で flow を実行します
+func runInWindow(title string, flow func(w *Window)) {
+	w := NewWindow(title)
+	w.Run(func() { flow(w) })
+}
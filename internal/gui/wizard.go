@@ -0,0 +1,258 @@
+package gui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ErrCancelled はユーザーがウィザードを閉じる・キャンセルするなどして、選択を中止したことを示します。
+// apperrors.ErrCancelled と同じ値のため、どちらでも errors.Is で判定できます
+var ErrCancelled = apperrors.ErrCancelled
+
+// errSelectionCancelled はフォルダ選択がキャンセルされた場合に返すエラーです
+var errSelectionCancelled = apperrors.New(apperrors.ErrCancelled, "フォルダ選択がキャンセルされました", "", nil)
+
+// AccessValidator は、調査対象・出力先として実際に使用できるか（読み取り・書き込み可否、入れ子）を
+// 検証するインターフェースです。DirectoryValidator がこれを実装している場合、ウィザードは詳細な検証を行います
+type AccessValidator interface {
+	ValidateSourceDirectory(path string) error
+	ValidateOutputDirectory(path, sourceDir string) error
+}
+
+// validateSource は調査対象フォルダを検証します
+func (s *DirectorySelector) validateSource(path string) error {
+	if v, ok := s.validator.(AccessValidator); ok {
+		return v.ValidateSourceDirectory(path)
+	}
+	return s.validator.ValidateDirectoryPath(path)
+}
+
+// validateOutput は出力先フォルダを検証します
+func (s *DirectorySelector) validateOutput(path, sourceDir string) error {
+	if v, ok := s.validator.(AccessValidator); ok {
+		return v.ValidateOutputDirectory(path, sourceDir)
+	}
+	return s.validator.ValidateDirectoryPath(path)
+}
+
+// newHintLabel は入力欄の下に表示する検証メッセージ用のラベルを作成します
+func newHintLabel() *widget.Label {
+	hint := widget.NewLabel("")
+	hint.Importance = widget.DangerImportance
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Hide()
+	return hint
+}
+
+// setHint は検証結果を入力欄の下に表示します。未入力の場合はメッセージを表示しません
+func setHint(hint *widget.Label, text string, err error) {
+	if text == "" || err == nil {
+		hint.SetText("")
+		hint.Hide()
+		return
+	}
+	hint.SetText(err.Error())
+	hint.Show()
+}
+
+// browseButton はフォルダ選択ダイアログを開き、選択結果を entry に設定するボタンを作成します
+func browseButton(w fyne.Window, entry *widget.Entry) *widget.Button {
+	return widget.NewButton("参照...", func() {
+		d := dialog.NewFolderOpen(func(selectedURI fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("フォルダ選択エラー: %w", err), w)
+				return
+			}
+			if selectedURI == nil {
+				// キャンセル時は入力内容をそのまま残す
+				return
+			}
+			entry.SetText(selectedURI.Path())
+		}, w)
+		// 入力済みのフォルダがあれば、そこからダイアログを開始する
+		if entry.Text != "" {
+			if lister, err := storage.ListerForURI(storage.NewFileURI(entry.Text)); err == nil {
+				d.SetLocation(lister)
+			}
+		}
+		d.Resize(fyne.NewSize(DefaultWindowWidth, DefaultWindowHeight))
+		d.Show()
+	})
+}
+
+// recentButtons は、最近使用したフォルダを entry に設定するクイック選択ボタンの一覧を作成します。
+// 履歴がない場合は何も表示しません
+func recentButtons(entry *widget.Entry, dirs []string) fyne.CanvasObject {
+	box := container.NewHBox(widget.NewLabel("最近使用:"))
+	for _, dir := range dirs {
+		dir := dir
+		label := filepath.Base(dir)
+		if label == "." || label == string(filepath.Separator) {
+			label = dir
+		}
+		box.Add(widget.NewButton(label, func() {
+			entry.SetText(dir)
+		}))
+	}
+	scroll := container.NewHScroll(box)
+	if len(dirs) == 0 {
+		scroll.Hide()
+	}
+	return scroll
+}
+
+// SelectDirectories は、調査対象フォルダと出力先フォルダを 1 つのウィザード画面で選択させます。
+// 最近使用したフォルダが設定されている場合は、入力欄の下にクイック選択ボタンを表示します。
+// 入力内容は変更のたびにバックグラウンドで検証され、問題がある場合は入力欄の下に赤字で理由を表示します。
+// 検証が ValidationTimeout 以内に終わらない場合（応答のないネットワークフォルダなど）はタイムアウトとして扱います。
+// すべての入力が有効になるまで「生成」ボタンは押せません。
+// initial が指定された場合は、その内容を入力欄の初期値とします（前回の選択に戻る場合など）。
+// 表示中に Window.RequestFolder で渡された調査対象フォルダは、入力欄に設定します。
+// settings が指定された場合は「設定...」ボタンを表示し、変更内容を settings に直接反映します。
+// ユーザーがウィザードを閉じた場合は ErrCancelled を返します
+func SelectDirectories(selector *DirectorySelector, initial *DirectoryPaths, settings *Settings) (paths *DirectoryPaths, err error) {
+	runInWindow("FolderScope", func(w *Window) {
+		paths, err = w.SelectDirectories(selector, initial, settings)
+	})
+	return paths, err
+}
+
+// SelectDirectories はウィンドウにフォルダ選択画面を表示し、選択が完了するまで待機します。
+// 画面の動作はパッケージ関数の SelectDirectories と同じです
+func (w *Window) SelectDirectories(selector *DirectorySelector, initial *DirectoryPaths, settings *Settings) (*DirectoryPaths, error) {
+	type result struct {
+		paths *DirectoryPaths
+		err   error
+	}
+	done := make(chan result, 1)
+	var once sync.Once
+	finish := func(r result) { once.Do(func() { done <- r }) }
+
+	sourceEntry := widget.NewEntry()
+	sourceEntry.SetPlaceHolder("調査対象フォルダのパス")
+	outputEntry := widget.NewEntry()
+	outputEntry.SetPlaceHolder("レポート出力先フォルダのパス")
+	sourceHint := newHintLabel()
+	outputHint := newHintLabel()
+
+	generateButton := widget.NewButton("生成", nil)
+	generateButton.Importance = widget.HighImportance
+	generateButton.Disable()
+
+	validatingBar := widget.NewProgressBarInfinite()
+	validatingBar.Stop()
+	validating := container.NewBorder(nil, nil, widget.NewLabel("フォルダを確認しています..."), nil, validatingBar)
+	validating.Hide()
+
+	// 検証は入力のたびに別の goroutine で実行し、応答のないネットワークフォルダでも画面が固まらないようにする。
+	// 入力が続けて変更された場合は、最新の入力に対する結果のみを反映する
+	var (
+		mu         sync.Mutex
+		generation int
+	)
+	validate := func() {
+		source, output := sourceEntry.Text, outputEntry.Text
+		mu.Lock()
+		generation++
+		current := generation
+		mu.Unlock()
+		generateButton.Disable()
+
+		spinner := time.AfterFunc(validationSpinnerDelay, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if current == generation {
+				validating.Show()
+				validatingBar.Start()
+			}
+		})
+		go func() {
+			sourceErr := validateWithTimeout(ValidationTimeout, func() error {
+				return selector.validateSource(source)
+			})
+			outputErr := validateWithTimeout(ValidationTimeout, func() error {
+				return selector.validateOutput(output, source)
+			})
+			spinner.Stop()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if current != generation {
+				return
+			}
+			validatingBar.Stop()
+			validating.Hide()
+			setHint(sourceHint, source, sourceErr)
+			setHint(outputHint, output, outputErr)
+			if sourceErr == nil && outputErr == nil {
+				generateButton.Enable()
+			}
+		}()
+	}
+	sourceEntry.OnChanged = func(string) { validate() }
+	outputEntry.OnChanged = func(string) { validate() }
+	if initial != nil {
+		sourceEntry.SetText(initial.Source)
+		outputEntry.SetText(initial.Output)
+	}
+	// 他のプロセスから渡された調査対象フォルダは、初期値より優先して入力欄に設定する
+	if dir := w.TakeRequestedFolder(); dir != "" {
+		sourceEntry.SetText(dir)
+	}
+	w.setFolderHandler(sourceEntry.SetText)
+	defer w.setFolderHandler(nil)
+
+	generateButton.OnTapped = func() {
+		finish(result{paths: &DirectoryPaths{Source: sourceEntry.Text, Output: outputEntry.Text}})
+	}
+	cancel := func() { finish(result{err: errSelectionCancelled}) }
+	cancelButton := widget.NewButton("キャンセル", cancel)
+
+	buttons := container.NewHBox(layout.NewSpacer(), cancelButton, generateButton)
+	settingsLabel := widget.NewLabel("")
+	settingsLabel.Wrapping = fyne.TextWrapWord
+	settingsLabel.Hide()
+	if settings != nil {
+		settingsLabel.SetText(settings.Summary())
+		settingsLabel.Show()
+		settingsButton := widget.NewButton("設定...", func() {
+			showSettingsDialog(w.window, settings, func() {
+				settingsLabel.SetText(settings.Summary())
+			})
+		})
+		buttons = container.NewHBox(settingsButton, layout.NewSpacer(), cancelButton, generateButton)
+	}
+
+	w.setPage(container.NewVBox(
+		widget.NewLabelWithStyle("調査対象フォルダ", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, browseButton(w.window, sourceEntry), sourceEntry),
+		recentButtons(sourceEntry, selector.recentSources),
+		sourceHint,
+		widget.NewLabelWithStyle("出力先フォルダ", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, browseButton(w.window, outputEntry), outputEntry),
+		recentButtons(outputEntry, selector.recentOutputs),
+		outputHint,
+		validating,
+		layout.NewSpacer(),
+		settingsLabel,
+		buttons,
+	), cancel)
+
+	r := <-done
+	// 検証中の goroutine が画面を更新しないよう、以降の検証結果を破棄する
+	mu.Lock()
+	generation++
+	mu.Unlock()
+	return r.paths, r.err
+}
@@ -0,0 +1,205 @@
+// Package archive は ZIP・tar・7z 形式のアーカイブを展開せずに fs.FS として読み込む機能を提供します
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/bodgit/sevenzip"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// Kind はアーカイブの形式です
+type Kind string
+
+const (
+	KindZip   Kind = "zip"
+	KindTar   Kind = "tar"
+	KindTarGz Kind = "tar.gz"
+	Kind7z    Kind = "7z"
+)
+
+// ErrUnsupported は対応していないアーカイブ形式であることを示します
+var ErrUnsupported = errors.New("対応していないアーカイブ形式です")
+
+// DetectKind はファイル名の拡張子からアーカイブの形式を判定します。アーカイブでない場合は空文字列を返します
+func DetectKind(name string) Kind {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return KindZip
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return KindTarGz
+	case strings.HasSuffix(lower, ".tar"):
+		return KindTar
+	case strings.HasSuffix(lower, ".7z"):
+		return Kind7z
+	}
+	return ""
+}
+
+// IsArchive はパスがアーカイブファイルを指しているかどうかを拡張子で判定します
+func IsArchive(name string) bool {
+	return DetectKind(name) != ""
+}
+
+// Archive は読み込んだアーカイブです。FS でアーカイブ内のファイルを参照できます
+type Archive struct {
+	fsys   fs.FS
+	closer io.Closer
+}
+
+// FS はアーカイブの内容を表す fs.FS を返します
+func (a *Archive) FS() fs.FS {
+	return a.fsys
+}
+
+// Close はアーカイブファイルを閉じます
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Open はアーカイブファイルを開きます。
+// ZIP・7z・非圧縮の tar はファイルを開いたまま、必要な部分のみを読み込みます。
+// gzip 圧縮した tar は先頭から順にしか読み込めないため、maxInMemorySize までの内容をメモリに読み込みます
+func Open(name string) (*Archive, error) {
+	switch DetectKind(name) {
+	case KindZip:
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, apperrors.Wrap("ZIPファイルの読み込みに失敗しました", name, err)
+		}
+		return &Archive{fsys: r, closer: r}, nil
+	case Kind7z:
+		r, err := sevenzip.OpenReader(name)
+		if err != nil {
+			return nil, apperrors.Wrap("7zファイルの読み込みに失敗しました", name, err)
+		}
+		return &Archive{fsys: r, closer: r}, nil
+	case KindTar, KindTarGz:
+		return openTar(name)
+	}
+	return nil, apperrors.New(ErrUnsupported, fmt.Sprintf("%s（zip, tar, tar.gz, 7z に対応しています）", ErrUnsupported), name, nil)
+}
+
+// openTar は tar ファイル（gzip 圧縮を含む）を開きます
+func openTar(name string) (*Archive, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, apperrors.Wrap("tarファイルを開けませんでした", name, err)
+	}
+
+	if DetectKind(name) == KindTar {
+		// 内容は読み込まずに位置のみを記録するため、ファイルは Close まで開いたままにする
+		fsys, err := readTar(nil, f, name)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &Archive{fsys: fsys, closer: f}, nil
+	}
+
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, apperrors.Wrap("gzipの展開に失敗しました", name, err)
+	}
+	defer gz.Close()
+	fsys, err := readTar(gz, nil, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{fsys: fsys}, nil
+}
+
+// FromFS はメモリ上に用意した内容などの fs.FS を、閉じる必要のないアーカイブとして返します
+func FromFS(fsys fs.FS) *Archive {
+	return &Archive{fsys: fsys}
+}
+
+// ReadTar は r から読み込んだ tar 形式（非圧縮）のデータを、メモリ上に展開したアーカイブとして返します。
+// 内容の合計サイズが maxInMemorySize を超える場合は ErrInvalidInput の種類のエラーを返します。
+// name はエラーメッセージに使用する名前です。ソースプラグインのように、tar をファイルではなくストリームで受け取る場合に使用します
+func ReadTar(r io.Reader, name string) (*Archive, error) {
+	fsys, err := readTar(r, nil, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{fsys: fsys}, nil
+}
+
+// maxInMemorySize は、tar をメモリに読み込む場合の内容の合計サイズの上限です
+var maxInMemorySize int64 = 1 << 30
+
+// readTar は r から tar 形式のデータを読み込み、MemFS に格納します。
+// file を指定した場合は r の代わりに file から読み込み、ファイルの内容はメモリに読み込まずに tar ファイル内の位置のみを記録します
+func readTar(r io.Reader, file *os.File, name string) (*MemFS, error) {
+	seekable := file != nil
+	if seekable {
+		r = file
+	}
+	fsys := NewMemFS()
+	var loaded int64
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, apperrors.Wrap("tarファイルの読み込みに失敗しました", name, err)
+		}
+		entryName := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if entryName == "." || !fs.ValidPath(entryName) {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			fsys.AddDir(entryName, hdr.FileInfo().Mode(), hdr.ModTime)
+		case tar.TypeReg:
+			if seekable && !isSparse(hdr) {
+				// tar.Reader はバッファリングしないため、ヘッダーを読み込んだ直後の位置が内容の先頭になる
+				offset, err := file.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return nil, apperrors.Wrap("tarファイルの読み込みに失敗しました", name, err)
+				}
+				fsys.addFile(entryName, io.NewSectionReader(file, offset, hdr.Size), hdr.Size, hdr.FileInfo().Mode(), hdr.ModTime)
+				continue
+			}
+			if loaded += hdr.Size; loaded > maxInMemorySize {
+				return nil, apperrors.New(apperrors.ErrInvalidInput,
+					fmt.Sprintf("アーカイブの内容が大きすぎるため読み込めません（上限 %d MB）。展開したフォルダを指定してください", maxInMemorySize>>20), name, nil)
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, apperrors.Wrap("tarファイルの読み込みに失敗しました", name, err)
+			}
+			fsys.AddFile(entryName, data, hdr.FileInfo().Mode(), hdr.ModTime)
+		}
+		// シンボリックリンクなどの特殊なエントリは対象外とする
+	}
+	return fsys, nil
+}
+
+// isSparse は hdr が GNU 形式のスパースファイルかどうかを返します。
+// スパースファイルは tar ファイル内の内容が連続していないため、位置を記録せずに読み込みます
+func isSparse(hdr *tar.Header) bool {
+	for key := range hdr.PAXRecords {
+		if strings.HasPrefix(key, "GNU.sparse.") {
+			return true
+		}
+	}
+	return false
+}
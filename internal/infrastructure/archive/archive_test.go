@@ -0,0 +1,204 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// testFiles はテスト用アーカイブに格納するファイルです
+var testFiles = map[string]string{
+	"a.txt":     "alpha",
+	"sub/b.txt": "beta",
+}
+
+func writeZip(t *testing.T, path string) {
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range testFiles {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+}
+
+func writeTar(t *testing.T, path string, compress bool) {
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	var w io.Writer = f
+	if compress {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for name, content := range testFiles {
+		// ディレクトリのエントリを含めず、"./" 付きの名前で格納する
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "./" + name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := io.WriteString(tw, content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+}
+
+// write7z は testFiles を無圧縮（Copy）で格納した 7z ファイルを作成します。
+// 各数値は 7z の可変長形式で 1 バイトに収まる大きさ（0x80 未満）である必要があります
+func write7z(t *testing.T, path string) {
+	names := make([]string, 0, len(testFiles))
+	for name := range testFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var packed bytes.Buffer
+	var nameProp bytes.Buffer
+	nameProp.WriteByte(0) // external
+	for _, name := range names {
+		packed.WriteString(testFiles[name])
+		for _, r := range utf16.Encode([]rune(name + "\x00")) {
+			binary.Write(&nameProp, binary.LittleEndian, r)
+		}
+	}
+	number := func(n int) byte {
+		if n >= 0x80 {
+			t.Fatalf("7z の数値が大きすぎます: %d", n)
+		}
+		return byte(n)
+	}
+
+	header := []byte{
+		0x01,                                               // Header
+		0x04,                                               // MainStreamsInfo
+		0x06, 0x00, 0x01, 0x09, number(packed.Len()), 0x00, // PackInfo
+		0x07, 0x0B, 0x01, 0x00, 0x01, 0x01, 0x00, // UnPackInfo: Copy のみの Folder 1 個
+		0x0C, number(packed.Len()), 0x00, // CodersUnPackSize
+		0x08, 0x0D, number(len(names)), 0x09, // SubStreamsInfo
+	}
+	for _, name := range names[:len(names)-1] {
+		header = append(header, number(len(testFiles[name])))
+	}
+	header = append(header, 0x0A, 0x01) // CRC（すべて定義済み）
+	for _, name := range names {
+		header = binary.LittleEndian.AppendUint32(header, crc32.ChecksumIEEE([]byte(testFiles[name])))
+	}
+	header = append(header, 0x00, 0x00, 0x05, number(len(names)), 0x11, number(nameProp.Len()))
+	header = append(header, nameProp.Bytes()...)
+	header = append(header, 0x00, 0x00)
+
+	var start bytes.Buffer
+	binary.Write(&start, binary.LittleEndian, uint64(packed.Len()))
+	binary.Write(&start, binary.LittleEndian, uint64(len(header)))
+	binary.Write(&start, binary.LittleEndian, crc32.ChecksumIEEE(header))
+
+	var data bytes.Buffer
+	data.Write([]byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C, 0x00, 0x04})
+	binary.Write(&data, binary.LittleEndian, crc32.ChecksumIEEE(start.Bytes()))
+	data.Write(start.Bytes())
+	data.Write(packed.Bytes())
+	data.Write(header)
+	assert.NoError(t, os.WriteFile(path, data.Bytes(), 0644))
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		file  string
+		write func(t *testing.T, path string)
+	}{
+		{"ZIP", "test.zip", writeZip},
+		{"tar", "test.tar", func(t *testing.T, path string) { writeTar(t, path, false) }},
+		{"tar.gz", "test.tar.gz", func(t *testing.T, path string) { writeTar(t, path, true) }},
+		{"7z", "test.7z", write7z},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			tt.write(t, path)
+
+			a, err := Open(path)
+			assert.NoError(t, err)
+			defer a.Close()
+
+			for name, content := range testFiles {
+				data, err := fs.ReadFile(a.FS(), name)
+				assert.NoError(t, err)
+				assert.Equal(t, content, string(data))
+			}
+			info, err := fs.Stat(a.FS(), "sub")
+			assert.NoError(t, err)
+			assert.True(t, info.IsDir(), "親ディレクトリが補完されていません")
+		})
+	}
+}
+
+func TestOpen_Unsupported(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "test.rar"))
+	assert.True(t, errors.Is(err, ErrUnsupported), "error = %v", err)
+}
+
+func TestOpen_InMemoryLimit(t *testing.T) {
+	saved := maxInMemorySize
+	maxInMemorySize = 8
+	defer func() { maxInMemorySize = saved }()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		file     string
+		compress bool
+		wantErr  bool
+	}{
+		// 非圧縮の tar は内容をメモリに読み込まないため、上限の対象外
+		{"tar", "test.tar", false, false},
+		{"tar.gz", "test.tar.gz", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			writeTar(t, path, tt.compress)
+
+			a, err := Open(path)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				a.Close()
+				return
+			}
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidInput), "error = %v", err)
+		})
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := map[string]Kind{
+		"a.ZIP":    KindZip,
+		"a.tgz":    KindTarGz,
+		"a.tar.gz": KindTarGz,
+		"a.tar":    KindTar,
+		"a.7z":     Kind7z,
+		"a.txt":    "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, DetectKind(name), name)
+	}
+}
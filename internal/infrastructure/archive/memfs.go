@@ -0,0 +1,163 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MemFS はアーカイブから読み込んだファイルを保持する、読み取り専用の fs.FS です。
+// ファイルを追加すると親ディレクトリは自動的に補完されるため、ディレクトリのエントリがないアーカイブもそのまま扱えます。
+// 内容はメモリ上に保持するか、tar ファイル内の位置を記録しておき参照されたときに読み込みます
+type MemFS struct {
+	root *memFile
+}
+
+// memFile は MemFS 内のファイルまたはディレクトリです
+type memFile struct {
+	name    string
+	mode    fs.FileMode
+	modTime time.Time
+	size    int64
+	// content はファイルの内容です。tar ファイルから読み込む場合は、そのファイルの該当部分を指します
+	content io.ReaderAt
+	// children はディレクトリ直下のエントリです
+	children map[string]*memFile
+}
+
+// NewMemFS は空の MemFS を作成します
+func NewMemFS() *MemFS {
+	return &MemFS{root: newMemDir(".", fs.ModeDir|0755, time.Time{})}
+}
+
+func newMemDir(name string, mode fs.FileMode, modTime time.Time) *memFile {
+	return &memFile{name: name, mode: mode, modTime: modTime, children: make(map[string]*memFile)}
+}
+
+// AddFile は name にファイルを追加します。name は fs.ValidPath を満たすパスで指定します
+func (m *MemFS) AddFile(name string, data []byte, mode fs.FileMode, modTime time.Time) {
+	m.addFile(name, bytes.NewReader(data), int64(len(data)), mode, modTime)
+}
+
+// addFile は content から size バイトを内容とするファイルを name に追加します
+func (m *MemFS) addFile(name string, content io.ReaderAt, size int64, mode fs.FileMode, modTime time.Time) {
+	parent := m.mkdirAll(path.Dir(name))
+	base := path.Base(name)
+	parent.children[base] = &memFile{name: base, mode: mode.Perm(), modTime: modTime, size: size, content: content}
+}
+
+// AddDir は name にディレクトリを追加します。すでに補完されたディレクトリがある場合は、権限と更新日時を更新します
+func (m *MemFS) AddDir(name string, mode fs.FileMode, modTime time.Time) {
+	dir := m.mkdirAll(name)
+	dir.mode = fs.ModeDir | mode.Perm()
+	dir.modTime = modTime
+}
+
+// mkdirAll は name のディレクトリを、親ディレクトリを含めて必要に応じて作成して返します
+func (m *MemFS) mkdirAll(name string) *memFile {
+	dir := m.root
+	if name == "." {
+		return dir
+	}
+	for _, elem := range strings.Split(name, "/") {
+		child := dir.children[elem]
+		if child == nil || !child.IsDir() {
+			// 同名のファイルがある場合は、後から現れたディレクトリで置き換える
+			child = newMemDir(elem, fs.ModeDir|0755, time.Time{})
+			dir.children[elem] = child
+		}
+		dir = child
+	}
+	return dir
+}
+
+// lookup は name のエントリを返します。存在しない場合は nil を返します
+func (m *MemFS) lookup(name string) *memFile {
+	f := m.root
+	if name == "." {
+		return f
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if f.children == nil {
+			return nil
+		}
+		if f = f.children[elem]; f == nil {
+			return nil
+		}
+	}
+	return f
+}
+
+// Open は fs.FS の Open を実装します
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	f := m.lookup(name)
+	if f == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.IsDir() {
+		entries := make([]fs.DirEntry, 0, len(f.children))
+		for _, child := range f.children {
+			entries = append(entries, fs.FileInfoToDirEntry(child))
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		return &openMemDir{file: f, path: name, entries: entries}, nil
+	}
+	return &openMemFile{file: f, SectionReader: io.NewSectionReader(f.content, 0, f.size)}, nil
+}
+
+// memFile は fs.FileInfo を実装します
+
+func (f *memFile) Name() string       { return f.name }
+func (f *memFile) Size() int64        { return f.size }
+func (f *memFile) Mode() fs.FileMode  { return f.mode }
+func (f *memFile) ModTime() time.Time { return f.modTime }
+func (f *memFile) IsDir() bool        { return f.mode.IsDir() }
+func (f *memFile) Sys() any           { return nil }
+
+// openMemFile は開いたファイルです
+type openMemFile struct {
+	file *memFile
+	*io.SectionReader
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return f.file, nil }
+func (f *openMemFile) Close() error               { return nil }
+
+// openMemDir は開いたディレクトリです
+type openMemDir struct {
+	file    *memFile
+	path    string
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openMemDir) Stat() (fs.FileInfo, error) { return d.file, nil }
+func (d *openMemDir) Close() error               { return nil }
+
+func (d *openMemDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: fs.ErrInvalid}
+}
+
+// ReadDir は fs.ReadDirFile の ReadDir を実装します
+func (d *openMemDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,35 @@
+package archive
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemFS(t *testing.T) {
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := NewMemFS()
+	fsys.AddFile("a.txt", []byte("alpha"), 0644, modTime)
+	fsys.AddFile("sub/deep/b.txt", []byte("beta"), 0600, modTime)
+	fsys.AddDir("sub", 0700, modTime)
+	fsys.AddDir("empty", 0755, modTime)
+
+	assert.NoError(t, fstest.TestFS(fsys, "a.txt", "sub/deep/b.txt", "empty"))
+
+	info, err := fs.Stat(fsys, "sub")
+	assert.NoError(t, err)
+	assert.Equal(t, fs.ModeDir|0700, info.Mode(), "後から追加したディレクトリの権限が反映されていません")
+	assert.Equal(t, modTime, info.ModTime())
+
+	info, err = fs.Stat(fsys, "sub/deep")
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir(), "親ディレクトリが補完されていません")
+
+	_, err = fsys.Open("missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = fsys.Open("../a.txt")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
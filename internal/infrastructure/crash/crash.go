@@ -0,0 +1,148 @@
+// Package crash は予期しないエラー（panic）が発生した際に、遠隔での調査に必要な診断情報をまとめたバンドルを作成する機能を提供します
+package crash
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"FolderScope/internal/infrastructure/logging"
+)
+
+const (
+	// DefaultLogEntries はバンドルに含める直近のログの既定の件数です
+	DefaultLogEntries = 200
+	// FilePrefix はバンドルのファイル名の接頭辞です（例: crash_20240601_120000.zip）
+	FilePrefix = "crash_"
+	// timestampLayout はバンドルのファイル名に含める日時の形式です
+	timestampLayout = "20060102_150405"
+)
+
+// sharedEnvVars はバンドルに含める環境変数です。
+// トークンなどの秘密情報を含めないよう、動作に影響する既知の変数のみを記録します
+var sharedEnvVars = []string{"LANG", "LC_ALL", "LC_CTYPE", "TERM", "XDG_CONFIG_HOME", "GOMAXPROCS", "GODEBUG"}
+
+// Report はバンドルに含める情報です
+type Report struct {
+	// Time は panic が発生した日時です
+	Time time.Time
+	// Panic は recover で得た値です
+	Panic any
+	// Stack は panic が発生したゴルーチンのスタックトレースです
+	Stack []byte
+	// Config は実行時の実際の設定です。JSON に変換して保存します
+	Config any
+	// Logs は panic までの直近のログです
+	Logs []logging.LogEntry
+}
+
+// Environment は実行環境の情報です
+type Environment struct {
+	Version    string            `json:"version"`
+	Revision   string            `json:"revision,omitempty"`
+	GoVersion  string            `json:"goVersion"`
+	OS         string            `json:"os"`
+	Arch       string            `json:"arch"`
+	NumCPU     int               `json:"numCPU"`
+	Args       []string          `json:"args"`
+	WorkingDir string            `json:"workingDir,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// CollectEnvironment は実行中のプロセスの環境の情報を収集します
+func CollectEnvironment() Environment {
+	env := Environment{
+		Version:   "(devel)",
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Args:      os.Args,
+		Env:       make(map[string]string),
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		env.Version = info.Main.Version
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				env.Revision = s.Value
+			}
+		}
+	}
+	env.WorkingDir, _ = os.Getwd()
+	for _, name := range sharedEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			env.Env[name] = value
+		}
+	}
+	return env
+}
+
+// WriteBundle は dir に診断情報をまとめた ZIP ファイルを作成し、そのパスを返します。
+// ZIP ファイルには panic.txt（panic の値とスタックトレース）、config.json、logs.jsonl、environment.json を含めます
+func WriteBundle(dir string, r Report) (string, error) {
+	path := filepath.Join(dir, FilePrefix+r.Time.Format(timestampLayout)+".zip")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", fmt.Errorf("診断情報のファイルの作成に失敗しました: %w", err)
+	}
+
+	zw := zip.NewWriter(file)
+	err = writeBundle(zw, r)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("診断情報の書き込みに失敗しました: %w", err)
+	}
+	return path, nil
+}
+
+// writeBundle はバンドルの各ファイルを zw に書き込みます
+func writeBundle(zw *zip.Writer, r Report) error {
+	w, err := zw.Create("panic.txt")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "panic: %v\n発生日時: %s\n\n%s", r.Panic, r.Time.Format(time.RFC3339), r.Stack); err != nil {
+		return err
+	}
+
+	if err := writeJSON(zw, "config.json", r.Config); err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "environment.json", CollectEnvironment()); err != nil {
+		return err
+	}
+
+	w, err = zw.Create("logs.jsonl")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for _, entry := range r.Logs {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON は v を字下げした JSON として name に書き込みます
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
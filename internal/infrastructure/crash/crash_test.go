@@ -0,0 +1,62 @@
+package crash
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/infrastructure/logging"
+)
+
+func TestWriteBundle(t *testing.T) {
+	dir := t.TempDir()
+	r := Report{
+		Time:   time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local),
+		Panic:  "index out of range",
+		Stack:  []byte("goroutine 1 [running]:\nmain.main()\n"),
+		Config: map[string]any{"format": "markdown"},
+		Logs:   []logging.LogEntry{{Level: "INFO", Message: "スキャンを開始しました"}},
+	}
+	path, err := WriteBundle(dir, r)
+	if err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+	if want := filepath.Join(dir, "crash_20240601_120000.zip"); path != want {
+		t.Errorf("WriteBundle() = %s, want %s", path, want)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("ZIP ファイルを開けませんでした: %v", err)
+	}
+	defer zr.Close()
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("%s を開けませんでした: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		contents[f.Name] = string(data)
+	}
+
+	for name, want := range map[string]string{
+		"panic.txt":        "panic: index out of range",
+		"config.json":      `"format": "markdown"`,
+		"logs.jsonl":       "スキャンを開始しました",
+		"environment.json": `"goVersion"`,
+	} {
+		if !strings.Contains(contents[name], want) {
+			t.Errorf("%s に %q が含まれていません: %q", name, want, contents[name])
+		}
+	}
+
+	// 同じ日時のバンドルは上書きしない
+	if _, err := WriteBundle(dir, r); err == nil {
+		t.Error("既存のバンドルがある場合はエラーになるべきです")
+	}
+}
@@ -0,0 +1,103 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// AccessPolicy はスキャン中に権限がないために読み込めなかったディレクトリの扱いです
+type AccessPolicy string
+
+const (
+	// AccessSkip はディレクトリの配下をスキップし、ログにのみ記録します
+	AccessSkip AccessPolicy = "skip"
+	// AccessReport はディレクトリの配下をスキップし、スキャンの統計情報の Inaccessible に記録してレポートに一覧を記載します
+	AccessReport AccessPolicy = "report"
+	// AccessFatal はスキャンを中止してエラーを返します。配下を省略したスナップショットを作成してはならない場合に使用します
+	AccessFatal AccessPolicy = "fatal"
+)
+
+// ParseAccessPolicy は文字列から読み込めなかったディレクトリの扱いを解析します。空文字列は AccessReport として扱います
+func ParseAccessPolicy(s string) (AccessPolicy, error) {
+	switch AccessPolicy(s) {
+	case "", AccessReport:
+		return AccessReport, nil
+	case AccessSkip, AccessFatal:
+		return AccessPolicy(s), nil
+	}
+	return "", fmt.Errorf("未対応の読み込めなかったディレクトリの扱いです: %s（skip, report, fatal のいずれかを指定してください）", s)
+}
+
+// CheckReadableDirectory はディレクトリの一覧を読み取れるかどうかを確認します
+func CheckReadableDirectory(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return apperrors.Wrap("ディレクトリを読み取れません", path, err)
+	}
+	defer dir.Close()
+
+	if _, err := dir.Readdirnames(1); err != nil && err != io.EOF {
+		return apperrors.Wrap("ディレクトリの一覧を取得できません", path, err)
+	}
+	return nil
+}
+
+// CheckWritableDirectory はディレクトリにファイルを作成できるかどうかを、一時ファイルを作成・削除して確認します
+func CheckWritableDirectory(path string) error {
+	probe, err := os.CreateTemp(path, ".folderscope_write_check_*")
+	if err != nil {
+		return apperrors.Wrap("ディレクトリに書き込めません", path, err)
+	}
+	name := probe.Name()
+	probe.Close()
+	if err := os.Remove(name); err != nil {
+		return apperrors.Wrap("書き込み確認用の一時ファイルを削除できません", name, err)
+	}
+	return nil
+}
+
+// IsNestedPath は child が parent と同じか、parent 配下のパスであるかどうかを返します
+func IsNestedPath(parent, child string) bool {
+	absParent, err := filepath.Abs(parent)
+	if err != nil {
+		return false
+	}
+	absChild, err := filepath.Abs(child)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absParent, absChild)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// ValidateSourceDirectory は調査対象ディレクトリとして有効で、読み取り可能であることを確認します
+func (s *Scanner) ValidateSourceDirectory(path string) error {
+	if err := s.ValidateDirectoryPath(path); err != nil {
+		return err
+	}
+	return CheckReadableDirectory(path)
+}
+
+// ValidateOutputDirectory は出力先ディレクトリとして有効で、書き込み可能であり、
+// 調査対象ディレクトリの配下ではないことを確認します。
+// 出力先が調査対象の配下にあると、生成中のレポート自体がスキャン対象に含まれてしまいます
+func (s *Scanner) ValidateOutputDirectory(path, sourceDir string) error {
+	if err := s.ValidateDirectoryPath(path); err != nil {
+		return err
+	}
+	if err := CheckWritableDirectory(path); err != nil {
+		return err
+	}
+	if sourceDir != "" && IsNestedPath(sourceDir, path) {
+		return fmt.Errorf("出力先が調査対象フォルダの中にあります")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNestedPath(t *testing.T) {
+	base := t.TempDir()
+	tests := []struct {
+		name   string
+		parent string
+		child  string
+		want   bool
+	}{
+		{"同じパス", base, base, true},
+		{"配下のパス", base, filepath.Join(base, "out"), true},
+		{"兄弟のパス", filepath.Join(base, "src"), filepath.Join(base, "src2"), false},
+		{"親のパス", filepath.Join(base, "src"), base, false},
+		{"名前が..で始まる子", base, filepath.Join(base, "..out"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsNestedPath(tt.parent, tt.child))
+		})
+	}
+}
+
+func TestScanner_ValidateSourceAndOutputDirectory(t *testing.T) {
+	scanner := NewScanner(&mockLogger{}, nil, false)
+	base := t.TempDir()
+	source := filepath.Join(base, "src")
+	output := filepath.Join(base, "out")
+	nested := filepath.Join(source, "reports")
+	for _, dir := range []string{source, output, nested} {
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+	}
+
+	assert.NoError(t, scanner.ValidateSourceDirectory(source))
+	assert.Error(t, scanner.ValidateSourceDirectory(filepath.Join(base, "missing")))
+
+	assert.NoError(t, scanner.ValidateOutputDirectory(output, source))
+	assert.NoError(t, scanner.ValidateOutputDirectory(nested, ""))
+	err := scanner.ValidateOutputDirectory(nested, source)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "調査対象フォルダの中")
+
+	// 書き込み確認用の一時ファイルが残らないこと
+	files, err := os.ReadDir(output)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestCheckWritableDirectory_ReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root 権限ではパーミッションによる書き込み制限を検証できません")
+	}
+	dir := t.TempDir()
+	assert.NoError(t, os.Chmod(dir, 0555))
+	defer os.Chmod(dir, 0755)
+
+	assert.Error(t, CheckWritableDirectory(dir))
+}
@@ -0,0 +1,166 @@
+package filesystem
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"FolderScope/internal/domain/model"
+)
+
+// DetectEncoding はテキストファイルの先頭部分 head から文字コードを推定します。
+// BOM があればそれに従い、なければ UTF-8、Shift_JIS、EUC-JP の順にバイト列として妥当かどうかを調べ、
+// いずれでもない場合は ISO-8859-1 とみなします。BOM のない NULL バイトを含む内容はテキストではないため空文字列を返します。
+// truncated はファイルの途中までしか読み込んでいないことを示し、その場合は末尾で途切れた文字を許容します
+func DetectEncoding(head []byte, truncated bool) string {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xEF, 0xBB, 0xBF}):
+		return model.EncodingUTF8
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE}):
+		return model.EncodingUTF16LE
+	case bytes.HasPrefix(head, []byte{0xFE, 0xFF}):
+		return model.EncodingUTF16BE
+	case bytes.IndexByte(head, 0x00) >= 0:
+		return ""
+	case validPrefix(head, truncated, utf8.Valid):
+		return model.EncodingUTF8
+	case validPrefix(head, truncated, validShiftJIS):
+		return model.EncodingShiftJIS
+	case validPrefix(head, truncated, validEUCJP):
+		return model.EncodingEUCJP
+	}
+	return model.EncodingLatin1
+}
+
+// DetectLineEnding はテキストファイルの先頭部分 head から改行コードを判定します。
+// 複数の種類の改行を含む場合は model.LineEndingMixed を、改行を含まない場合は空文字列を返します。
+// UTF-16 の場合は 2 バイト単位で改行を探します。truncated はファイルの途中までしか読み込んでいないことを示します
+func DetectLineEnding(head []byte, encoding string, truncated bool) string {
+	units := head
+	if isUTF16(encoding) {
+		units = utf16LineUnits(head, encoding == model.EncodingUTF16BE)
+	}
+	var lf, crlf, cr bool
+	for i := 0; i < len(units); i++ {
+		switch units[i] {
+		case '\n':
+			lf = true
+		case '\r':
+			switch {
+			case i+1 < len(units) && units[i+1] == '\n':
+				crlf = true
+				i++
+			// 途中まで読み込んだ内容の末尾の CR は、直後に LF が続く可能性があるため判定に使用しない
+			case i+1 < len(units) || !truncated:
+				cr = true
+			}
+		}
+	}
+	found := ""
+	for _, kind := range []struct {
+		present bool
+		name    string
+	}{{lf, model.LineEndingLF}, {crlf, model.LineEndingCRLF}, {cr, model.LineEndingCR}} {
+		if !kind.present {
+			continue
+		}
+		if found != "" {
+			return model.LineEndingMixed
+		}
+		found = kind.name
+	}
+	return found
+}
+
+// utf16LineUnits は UTF-16 の内容を 2 バイト単位で調べ、改行（CR・LF）はその文字に、それ以外の文字は 0 に置き換えたバイト列を返します
+func utf16LineUnits(head []byte, bigEndian bool) []byte {
+	units := make([]byte, 0, len(head)/2)
+	for i := 0; i+1 < len(head); i += 2 {
+		low, high := head[i], head[i+1]
+		if bigEndian {
+			low, high = high, low
+		}
+		if high == 0 && (low == '\r' || low == '\n') {
+			units = append(units, low)
+		} else {
+			units = append(units, 0)
+		}
+	}
+	return units
+}
+
+// isUTF16 は文字コードが UTF-16 であるかどうかを返します
+func isUTF16(encoding string) bool {
+	return encoding == model.EncodingUTF16LE || encoding == model.EncodingUTF16BE
+}
+
+// validPrefix は head が valid を満たすかどうかを返します。
+// truncated の場合は末尾で文字が途切れている可能性があるため、末尾の ASCII 以外のバイトを最大 3 バイトまで除いて再度調べます
+func validPrefix(head []byte, truncated bool, valid func([]byte) bool) bool {
+	if valid(head) {
+		return true
+	}
+	for n := len(head) - 1; truncated && n >= 0 && n >= len(head)-(utf8.UTFMax-1) && head[n] >= 0x80; n-- {
+		if valid(head[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// validShiftJIS は head が Shift_JIS として妥当かどうかを返します。
+// ISO-8859-1 のテキストと区別するため、ISO-8859-1 では制御文字にあたる 0x81〜0x9F の先行バイトを 1 つ以上含むことを条件とします
+func validShiftJIS(head []byte) bool {
+	hasLowLead := false
+	for i := 0; i < len(head); i++ {
+		b := head[i]
+		switch {
+		case b < 0x80, b >= 0xA1 && b <= 0xDF: // ASCII と半角カナ
+			continue
+		case b >= 0x81 && b <= 0x9F, b >= 0xE0 && b <= 0xFC:
+			if i+1 == len(head) {
+				return false
+			}
+			trail := head[i+1]
+			if trail < 0x40 || trail == 0x7F || trail > 0xFC {
+				return false
+			}
+			if b <= 0x9F {
+				hasLowLead = true
+			}
+			i++
+		default:
+			return false
+		}
+	}
+	return hasLowLead
+}
+
+// validEUCJP は head が EUC-JP として妥当であり、2 バイト文字を 1 つ以上含むかどうかを返します
+func validEUCJP(head []byte) bool {
+	multibyte := false
+	for i := 0; i < len(head); i++ {
+		b := head[i]
+		var n int // 先行バイトに続くバイト数
+		switch {
+		case b < 0x80:
+			continue
+		case b == 0x8E, b >= 0xA1 && b <= 0xFE: // 半角カナ、JIS X 0208
+			n = 1
+		case b == 0x8F: // JIS X 0212
+			n = 2
+		default:
+			return false
+		}
+		for j := 1; j <= n; j++ {
+			if i+j == len(head) {
+				return false
+			}
+			if trail := head[i+j]; trail < 0xA1 || trail > 0xFE {
+				return false
+			}
+		}
+		multibyte = true
+		i += n
+	}
+	return multibyte
+}
@@ -0,0 +1,84 @@
+package filesystem
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+
+	"FolderScope/internal/domain/model"
+)
+
+func mustEncode(t *testing.T, enc encoding.Encoding, s string) []byte {
+	t.Helper()
+	b, err := enc.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("テストデータのエンコードに失敗: %v", err)
+	}
+	return b
+}
+
+func TestDetectEncoding(t *testing.T) {
+	const japaneseText = "これは日本語のテキストです。\nカタカナと漢字を含みます。\n"
+	sjis := mustEncode(t, japanese.ShiftJIS, japaneseText)
+	eucjp := mustEncode(t, japanese.EUCJP, japaneseText)
+	utf8Text := []byte(japaneseText)
+
+	tests := []struct {
+		name      string
+		head      []byte
+		truncated bool
+		want      string
+	}{
+		{name: "ASCII", head: []byte("package main\n"), want: model.EncodingUTF8},
+		{name: "UTF-8の日本語", head: utf8Text, want: model.EncodingUTF8},
+		{name: "BOM付きUTF-8", head: append([]byte{0xEF, 0xBB, 0xBF}, 'a'), want: model.EncodingUTF8},
+		{name: "UTF-16LE", head: []byte{0xFF, 0xFE, 'a', 0x00}, want: model.EncodingUTF16LE},
+		{name: "UTF-16BE", head: []byte{0xFE, 0xFF, 0x00, 'a'}, want: model.EncodingUTF16BE},
+		{name: "Shift_JIS", head: sjis, want: model.EncodingShiftJIS},
+		{name: "EUC-JP", head: eucjp, want: model.EncodingEUCJP},
+		{name: "Latin-1", head: []byte("caf\xe9 cr\xe8me br\xfbl\xe9e\n"), want: model.EncodingLatin1},
+		{name: "Latin-1の連続したアクセント文字", head: []byte("\xe9l\xe8ve\n"), want: model.EncodingLatin1},
+		{name: "末尾で途切れたUTF-8", head: utf8Text[:len(utf8Text)-2], truncated: true, want: model.EncodingUTF8},
+		{name: "末尾で途切れたShift_JIS", head: sjis[:len(sjis)-2], truncated: true, want: model.EncodingShiftJIS},
+		{name: "途切れていない場合は末尾の不完全な文字を許容しない", head: []byte("caf\xe9"), want: model.EncodingLatin1},
+		{name: "NULLバイトを含むデータ", head: []byte{0x00, 0x01, 0x02}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.head, tt.truncated); got != tt.want {
+				t.Errorf("DetectEncoding() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLineEnding(t *testing.T) {
+	tests := []struct {
+		name      string
+		head      []byte
+		encoding  string
+		truncated bool
+		want      string
+	}{
+		{name: "LF", head: []byte("a\nb\n"), encoding: model.EncodingUTF8, want: model.LineEndingLF},
+		{name: "CRLF", head: []byte("a\r\nb\r\n"), encoding: model.EncodingUTF8, want: model.LineEndingCRLF},
+		{name: "CR", head: []byte("a\rb\r"), encoding: model.EncodingUTF8, want: model.LineEndingCR},
+		{name: "混在", head: []byte("a\r\nb\n"), encoding: model.EncodingUTF8, want: model.LineEndingMixed},
+		{name: "改行なし", head: []byte("a"), encoding: model.EncodingUTF8, want: ""},
+		{name: "末尾の CR", head: []byte("a\r"), encoding: model.EncodingUTF8, want: model.LineEndingCR},
+		{name: "途中まで読み込んだ末尾の CR", head: []byte("a\r"), encoding: model.EncodingUTF8, truncated: true, want: ""},
+		{name: "Shift_JIS の CRLF", head: append(mustEncode(t, japanese.ShiftJIS, "日本語"), '\r', '\n'), encoding: model.EncodingShiftJIS, want: model.LineEndingCRLF},
+		{name: "UTF-16LE の CRLF", head: []byte{0xFF, 0xFE, 'a', 0x00, '\r', 0x00, '\n', 0x00}, encoding: model.EncodingUTF16LE, want: model.LineEndingCRLF},
+		{name: "UTF-16BE の LF", head: []byte{0xFE, 0xFF, 0x00, 'a', 0x00, '\n'}, encoding: model.EncodingUTF16BE, want: model.LineEndingLF},
+		{name: "UTF-16LE の改行以外の 0x0A", head: []byte{0xFF, 0xFE, '\n', 0x30}, encoding: model.EncodingUTF16LE, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLineEnding(tt.head, tt.encoding, tt.truncated); got != tt.want {
+				t.Errorf("DetectLineEnding() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/domain/rules"
+)
+
+// Explanation は、あるパスがスキャンの結果に含まれるかどうかと、その判定に使用した設定です。
+// ファイルがレポートに含まれない理由を調べるために使用します
+type Explanation struct {
+	// RelPath は説明の対象のルートからの相対パス（'/' 区切り）です
+	RelPath string
+	// IsDir は対象がディレクトリかどうかを示します
+	IsDir bool
+	// Included はスキャンの結果に含まれるかどうかを示します
+	Included bool
+	// Reason は除外された理由です。結果に含まれる場合は空です
+	Reason model.SkipReason
+	// MatchedPath は除外の判定に一致した相対パスです。親フォルダが除外された場合は、そのフォルダのパスです
+	MatchedPath string
+	// Pattern は除外の判定に一致した無視パターン・正規表現・ルールのパスです
+	Pattern string
+	// Default は Pattern が既定の無視パターン（DefaultIgnorePatterns）かどうかを示します
+	Default bool
+	// IncludedBy は包含正規表現が指定されている場合に、ファイルが一致した正規表現です
+	IncludedBy string
+	// Rule は対象に一致したパスごとのルールです。一致するルールがない場合は nil です
+	Rule *rules.Rule
+	// Entry は結果に含まれる場合に、スキャンで記録されるエントリです（内容のハッシュは計算しません）。
+	// サイズ・更新日時・バイナリファイルで除外された場合は、判定に使用したサイズなどを記録します
+	Entry model.FileSystemEntry
+}
+
+// ExplainPath は rootDir をスキャンした場合に、rootDir からの相対パス relPath が結果に含まれるかどうかとその理由を返します
+func (s *Scanner) ExplainPath(rootDir, relPath string) (Explanation, error) {
+	fsys, absRootDir, err := OpenDir(rootDir)
+	if err != nil {
+		return Explanation{}, err
+	}
+	return s.Explain(fsys, absRootDir, relPath)
+}
+
+// Explain は fsys をスキャンした場合に、相対パス relPath が結果に含まれるかどうかとその理由を返します。
+// スキャンと同じ順序（無視パターン・隠しファイル・除外正規表現・包含正規表現・パスごとのルール・シンボリックリンク・サイズと更新日時・バイナリファイル）で、
+// ルートから relPath までの各フォルダと relPath 自身を判定し、最初に除外された判定を返します
+func (s *Scanner) Explain(fsys fs.FS, root, relPath string) (Explanation, error) {
+	relPath = path.Clean(filepath.ToSlash(relPath))
+	if relPath == "." || !fs.ValidPath(relPath) {
+		return Explanation{}, fmt.Errorf("調査対象フォルダの中の相対パスを指定してください: %s", relPath)
+	}
+	exp := Explanation{RelPath: relPath}
+	parts := strings.Split(relPath, "/")
+	for i := range parts {
+		current := strings.Join(parts[:i+1], "/")
+		d, err := lookupDirEntry(fsys, current)
+		if err != nil {
+			return Explanation{}, apperrors.Wrap("パスの情報を取得できません", current, err)
+		}
+		last := i == len(parts)-1
+		if last {
+			exp.IsDir = d.IsDir()
+		}
+		if s.explainExclusion(&exp, d, current, last) {
+			exp.MatchedPath = current
+			return exp, nil
+		}
+		// リンクとして記録する場合、リンク先のフォルダの配下は走査しない
+		if !last && isLink(d) && s.symlinkPolicy != SymlinkFollow {
+			exp.Reason, exp.MatchedPath = model.SkipSymlink, current
+			return exp, nil
+		}
+	}
+	if rule, ok := s.rules.MatchRule(relPath); ok {
+		exp.Rule = &rule
+	}
+	if !exp.IsDir {
+		if reason, excluded := s.explainFile(fsys, root, &exp); excluded {
+			exp.Reason, exp.MatchedPath = reason, relPath
+			return exp, nil
+		}
+	} else {
+		exp.Entry = model.FileSystemEntry{Path: filepath.Join(root, filepath.FromSlash(relPath)), IsDir: true, RelPath: relPath, Depth: len(parts) - 1}
+	}
+	exp.Included = true
+	return exp, nil
+}
+
+// explainExclusion は、ファイル名・相対パスで判定する除外の条件に d が一致するかどうかを判定し、一致した場合は理由とパターンを exp に記録します。
+// 包含正規表現はファイルにのみ適用するため、target（説明の対象自身）がファイルの場合のみ判定します
+func (s *Scanner) explainExclusion(exp *Explanation, d fs.DirEntry, relPath string, target bool) bool {
+	if pattern, ignored := s.ignoredBy(d.Name(), d.IsDir()); ignored {
+		exp.Reason, exp.Pattern, exp.Default = model.SkipIgnored, pattern, slices.Contains(DefaultIgnorePatterns, pattern)
+		return true
+	}
+	if s.isHiddenEntry(d) {
+		exp.Reason = model.SkipHidden
+		return true
+	}
+	if re, matched := firstMatchingRegexp(s.excludeRegexps, relPath); matched {
+		exp.Reason, exp.Pattern = model.SkipExcluded, re
+		return true
+	}
+	if target && !d.IsDir() && len(s.includeRegexps) > 0 {
+		re, matched := firstMatchingRegexp(s.includeRegexps, relPath)
+		if !matched {
+			exp.Reason = model.SkipNotIncluded
+			return true
+		}
+		exp.IncludedBy = re
+	}
+	if rule, ok := s.rules.MatchRule(relPath); ok && rule.Mode == string(rules.ModeExclude) {
+		exp.Reason, exp.Pattern, exp.Rule = model.SkipRule, rule.Path, &rule
+		return true
+	}
+	if isLink(d) && s.symlinkPolicy == SymlinkSkip {
+		exp.Reason = model.SkipSymlink
+		return true
+	}
+	return false
+}
+
+// explainFile はファイルのサイズ・更新日時と先頭部分から、スキャンで記録されるエントリを exp に記録し、
+// サイズ・更新日時の範囲外の場合やバイナリファイルを除外する設定の場合は除外の理由を返します
+func (s *Scanner) explainFile(fsys fs.FS, root string, exp *Explanation) (model.SkipReason, bool) {
+	entry := model.FileSystemEntry{
+		Path:    filepath.Join(root, filepath.FromSlash(exp.RelPath)),
+		RelPath: exp.RelPath,
+		Depth:   strings.Count(exp.RelPath, "/"),
+	}
+	if exp.Rule != nil {
+		entry.ContentMode = exp.Rule.Mode
+	}
+	// リンクをたどる場合と同じく、リンク先の情報を使用する
+	if info, err := fs.Stat(fsys, exp.RelPath); err == nil {
+		entry.Size = info.Size()
+		entry.ModTime = info.ModTime()
+		entry.Permissions = info.Mode()
+		if reason, skip := s.outOfRange(info); skip {
+			exp.Entry = entry
+			return reason, true
+		}
+	}
+	if f, err := fsys.Open(exp.RelPath); err != nil {
+		entry.ReadErr = err
+	} else {
+		head := make([]byte, s.binaryCheckSize)
+		n, err := io.ReadFull(f, head)
+		f.Close()
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			entry.ReadErr = err
+		} else {
+			head = head[:n]
+			encoding := DetectEncoding(head, n == s.binaryCheckSize)
+			entry.MIMEType = DetectMIMEType(path.Base(exp.RelPath), head)
+			entry.IsBinary = isBinaryContent(entry.MIMEType, encoding, head)
+			if !entry.IsBinary {
+				entry.Encoding = encoding
+			}
+		}
+	}
+	exp.Entry = entry
+	if s.ignoreBinaryFiles && entry.IsBinary {
+		return model.SkipBinary, true
+	}
+	return "", false
+}
+
+// lookupDirEntry は相対パス relPath のエントリを、親フォルダの一覧から取得します。
+// スキャンと同じく、シンボリックリンクや隠し属性をたどらずに判定するために使用します
+func lookupDirEntry(fsys fs.FS, relPath string) (fs.DirEntry, error) {
+	dir, name := path.Split(relPath)
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range entries {
+		if d.Name() == name {
+			return d, nil
+		}
+	}
+	return nil, fs.ErrNotExist
+}
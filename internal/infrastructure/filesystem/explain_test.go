@@ -0,0 +1,76 @@
+package filesystem
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/domain/rules"
+)
+
+func TestScanner_Explain(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":                 {Data: []byte("package main")},
+		".env":                    {Data: []byte("SECRET=1")},
+		".git/config":             {Data: []byte("[core]")},
+		"web/node_modules/dep.js": {Data: []byte("dep")},
+		"docs/guide.md":           {Data: []byte("# guide")},
+		"third_party/lib.go":      {Data: []byte("package lib")},
+		"logo.png":                {Data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+		"big.log":                 {Data: make([]byte, 2048)},
+	}
+	scanner := mustNewScanner(t, &mockLogger{}, ScannerOptions{
+		IgnorePatterns:    []string{dirOnly("node_modules")},
+		IgnoreBinaryFiles: true,
+		ExcludeRegexps:    []string{`^docs/`},
+		Rules: []rules.Rule{
+			{Path: "third_party/**", Mode: "exclude"},
+			{Path: "*.go", Mode: "outline"},
+		},
+		MaxSize: 1024,
+	})
+
+	tests := []struct {
+		name        string
+		relPath     string
+		want        model.SkipReason
+		wantMatched string
+		wantPattern string
+		wantDefault bool
+	}{
+		{name: "既定の無視パターンに一致するフォルダの配下", relPath: ".git/config", want: model.SkipIgnored, wantMatched: ".git", wantPattern: ".git", wantDefault: true},
+		{name: "追加の無視パターンに一致するフォルダの配下", relPath: "web/node_modules/dep.js", want: model.SkipIgnored, wantMatched: "web/node_modules", wantPattern: dirOnly("node_modules")},
+		{name: "隠しファイル", relPath: ".env", want: model.SkipHidden, wantMatched: ".env"},
+		{name: "除外正規表現", relPath: "docs/guide.md", want: model.SkipExcluded, wantMatched: "docs/guide.md", wantPattern: `^docs/`},
+		{name: "除外のルール", relPath: "third_party/lib.go", want: model.SkipRule, wantMatched: "third_party", wantPattern: "third_party/**"},
+		{name: "バイナリファイル", relPath: "logo.png", want: model.SkipBinary, wantMatched: "logo.png"},
+		{name: "サイズの上限", relPath: "big.log", want: model.SkipSize, wantMatched: "big.log"},
+		{name: "含まれるファイル", relPath: "./main.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scanner.Explain(fsys, "/root", tt.relPath)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want == "", got.Included)
+			assert.Equal(t, tt.want, got.Reason)
+			assert.Equal(t, tt.wantMatched, got.MatchedPath)
+			assert.Equal(t, tt.wantPattern, got.Pattern)
+			assert.Equal(t, tt.wantDefault, got.Default)
+		})
+	}
+
+	got, err := scanner.Explain(fsys, "/root", "main.go")
+	assert.NoError(t, err)
+	if assert.NotNil(t, got.Rule) {
+		assert.Equal(t, "*.go", got.Rule.Path)
+	}
+	assert.Equal(t, "outline", got.Entry.ContentMode)
+	assert.Equal(t, int64(len("package main")), got.Entry.Size)
+
+	_, err = scanner.Explain(fsys, "/root", "missing.txt")
+	assert.Error(t, err)
+	_, err = scanner.Explain(fsys, "/root", "../outside.txt")
+	assert.ErrorContains(t, err, "相対パスを指定してください")
+}
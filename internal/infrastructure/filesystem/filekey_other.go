@@ -0,0 +1,10 @@
+//go:build !unix
+
+package filesystem
+
+import "io/fs"
+
+// hardLinkKey は inode 番号を取得できない環境では常に false を返し、ハードリンクを検出しません
+func hardLinkKey(info fs.FileInfo) (fileKey, bool) {
+	return fileKey{}, false
+}
@@ -0,0 +1,128 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// hashChunkSize はハッシュの計算で 1 回に読み込む大きさです
+const hashChunkSize = 64 * 1024
+
+// hashJob はハッシュを計算するファイルです。
+// file はバイナリ判定で先頭部分 head を読み込んだ続きから読み込み、計算後にパイプラインが閉じます
+type hashJob struct {
+	index int
+	path  string
+	head  []byte
+	file  fs.File
+}
+
+// hashResult は 1 ファイルのハッシュの計算結果です
+type hashResult struct {
+	index int
+	path  string
+	hash  string
+	// bytes は先頭部分に続けて読み込んだバイト数です
+	bytes int64
+	err   error
+}
+
+// hashPipeline は、走査と並行して複数のワーカーでファイルを読み込み、SHA-256 ハッシュを計算します。
+// あるワーカーがハッシュを計算している間も、走査や他のワーカーはファイルの読み込みを続けます。
+// jobs はバッファを持たず、依頼はいずれかのワーカーが受け取るまで待つため、開いたままのファイルは
+// 計算中のワーカーごとに 1 つと、受け取りを待つ走査側の 1 つまで（ワーカー数 + 1）です。
+// 使用するメモリ（ファイルごとの先頭部分と、ワーカーごとの読み込み用のバッファ）も、ファイルの数や大きさによらず一定です
+type hashPipeline struct {
+	ctx     context.Context
+	jobs    chan hashJob
+	wg      sync.WaitGroup
+	buffers sync.Pool
+	mu      sync.Mutex
+	results []hashResult
+}
+
+// newHashPipeline は workers 個のワーカーでハッシュを計算するパイプラインを開始します。workers が 0 以下の場合は CPU 数を使用します。
+// ctx がキャンセルされた場合、計算中のファイルは読み込みを中断します
+func newHashPipeline(ctx context.Context, workers int) *hashPipeline {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	p := &hashPipeline{
+		ctx:  ctx,
+		jobs: make(chan hashJob),
+		buffers: sync.Pool{New: func() any {
+			buf := make([]byte, hashChunkSize)
+			return &buf
+		}},
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// submit はファイルのハッシュの計算を依頼します。すべてのワーカーが計算中の場合は、いずれかが空くまで待ちます。
+// ctx がキャンセルされた場合は、ファイルを閉じてエラーを返します
+func (p *hashPipeline) submit(job hashJob) error {
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-p.ctx.Done():
+		job.file.Close()
+		return p.ctx.Err()
+	}
+}
+
+// wait は依頼したすべてのファイルの計算が終わるのを待ち、結果を依頼したエントリの順に返します
+func (p *hashPipeline) wait() []hashResult {
+	close(p.jobs)
+	p.wg.Wait()
+	sort.Slice(p.results, func(i, j int) bool { return p.results[i].index < p.results[j].index })
+	return p.results
+}
+
+// work は依頼されたファイルのハッシュを順に計算します
+func (p *hashPipeline) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		hash, n, err := p.hash(job)
+		job.file.Close()
+		p.mu.Lock()
+		p.results = append(p.results, hashResult{index: job.index, path: job.path, hash: hash, bytes: n, err: err})
+		p.mu.Unlock()
+	}
+}
+
+// hash は先頭部分に続けてファイルの残りを読み込み、SHA-256 ハッシュを 16 進文字列で、続けて読み込んだバイト数とともに返します
+func (p *hashPipeline) hash(job hashJob) (string, int64, error) {
+	bufp := p.buffers.Get().(*[]byte)
+	defer p.buffers.Put(bufp)
+	buf := *bufp
+
+	h := sha256.New()
+	h.Write(job.head)
+	var read int64
+	for {
+		if err := p.ctx.Err(); err != nil {
+			return "", read, err
+		}
+		n, err := job.file.Read(buf)
+		h.Write(buf[:n])
+		read += int64(n)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", read, err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), read, nil
+}
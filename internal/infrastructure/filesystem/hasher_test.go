@@ -0,0 +1,112 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPipeline(t *testing.T) {
+	fsys := fstest.MapFS{}
+	want := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		// 読み込み用のバッファより大きいファイルも含める
+		content := strings.Repeat(fmt.Sprintf("file %d\n", i), i*hashChunkSize/32+1)
+		name := fmt.Sprintf("f%02d.txt", i)
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+		sum := sha256.Sum256([]byte(content))
+		want[name] = hex.EncodeToString(sum[:])
+	}
+
+	tests := []struct {
+		name    string
+		workers int
+	}{
+		{name: "ワーカー 1 つ", workers: 1},
+		{name: "複数のワーカー", workers: 4},
+		{name: "CPU 数のワーカー", workers: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newHashPipeline(context.Background(), tt.workers)
+			for i := 0; i < 20; i++ {
+				name := fmt.Sprintf("f%02d.txt", i)
+				f, err := fsys.Open(name)
+				assert.NoError(t, err)
+				head := make([]byte, DefaultBinaryCheckSize)
+				n, _ := f.Read(head)
+				assert.NoError(t, p.submit(hashJob{index: i, path: name, head: head[:n], file: f}))
+			}
+
+			results := p.wait()
+			assert.Len(t, results, 20)
+			for i, r := range results {
+				assert.Equal(t, i, r.index, "結果が依頼した順に並んでいません")
+				assert.NoError(t, r.err)
+				assert.Equal(t, want[r.path], r.hash, r.path)
+			}
+		})
+	}
+}
+
+func TestHashPipeline_Cancelled(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte(strings.Repeat("a", 4*hashChunkSize))}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := newHashPipeline(ctx, 1)
+	f, err := fsys.Open("a.txt")
+	assert.NoError(t, err)
+	if err := p.submit(hashJob{path: "a.txt", file: f}); err != nil {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	for _, r := range p.wait() {
+		assert.ErrorIs(t, r.err, context.Canceled)
+		assert.Empty(t, r.hash)
+	}
+}
+
+// blockingFile は release が閉じられるまで Read が戻らないファイルです
+type blockingFile struct {
+	fs.File
+	release chan struct{}
+}
+
+func (f *blockingFile) Read(p []byte) (int, error) {
+	<-f.release
+	return f.File.Read(p)
+}
+
+func TestHashPipeline_SubmitWaitsForWorker(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}, "b.txt": {Data: []byte("b")}}
+	p := newHashPipeline(context.Background(), 1)
+
+	a, err := fsys.Open("a.txt")
+	assert.NoError(t, err)
+	release := make(chan struct{})
+	assert.NoError(t, p.submit(hashJob{index: 0, path: "a.txt", file: &blockingFile{File: a, release: release}}))
+
+	// ワーカーが計算中の間は、次の依頼は受け取られずに待つ（開いたままのファイルはワーカー数 + 1 まで）
+	b, err := fsys.Open("b.txt")
+	assert.NoError(t, err)
+	submitted := make(chan error, 1)
+	go func() { submitted <- p.submit(hashJob{index: 1, path: "b.txt", file: b}) }()
+	select {
+	case <-submitted:
+		t.Fatal("ワーカーが計算中なのに次の依頼が受け取られました")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-submitted)
+	results := p.wait()
+	assert.Len(t, results, 2)
+}
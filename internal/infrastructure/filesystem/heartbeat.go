@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// DefaultHeartbeatInterval は GUI を使用しない実行でハートビートをログに出力する間隔です
+const DefaultHeartbeatInterval = 30 * time.Second
+
+// Heartbeat は長時間の処理中に、一定間隔で経過時間と進捗をログに出力します。
+// ログを監視している利用者が、プロセスが停止していないことを確認できるようにします
+type Heartbeat struct {
+	logger   logging.Logger
+	label    string
+	started  time.Time
+	mu       sync.Mutex
+	progress ScanProgress
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// StartHeartbeat は interval ごとにハートビートを出力するゴルーチンを開始します。label は処理の名前です
+func StartHeartbeat(logger logging.Logger, label string, interval time.Duration) *Heartbeat {
+	h := &Heartbeat{
+		logger:  logger,
+		label:   label,
+		started: time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go h.run(interval)
+	return h
+}
+
+// run は Stop が呼ばれるまで interval ごとにハートビートを出力します
+func (h *Heartbeat) run(interval time.Duration) {
+	defer close(h.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			h.mu.Lock()
+			p := h.progress
+			h.mu.Unlock()
+			elapsed := time.Since(h.started).Round(time.Second)
+			h.logger.Log("INFO", fmt.Sprintf("%sを実行中です（経過: %s, ファイル: %d, ディレクトリ: %d, 処理中: %s）",
+				h.label, elapsed, p.Files, p.Dirs, p.CurrentPath), nil,
+				"duration", elapsed, "files", p.Files, "dirs", p.Dirs, "path", p.CurrentPath)
+		}
+	}
+}
+
+// Update は最新の進捗を記録します。Scanner の ProgressFunc として使用できます
+func (h *Heartbeat) Update(p ScanProgress) {
+	h.mu.Lock()
+	h.progress = p
+	h.mu.Unlock()
+}
+
+// Stop はハートビートの出力を停止し、ゴルーチンの終了を待ちます
+func (h *Heartbeat) Stop() {
+	close(h.stop)
+	<-h.done
+}
@@ -0,0 +1,45 @@
+package filesystem
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger はゴルーチンから記録されたログメッセージを保持するロガーです
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Log(level, message string, err error, attrs ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, message)
+}
+
+func TestHeartbeat(t *testing.T) {
+	logger := &recordingLogger{}
+	h := StartHeartbeat(logger, "スキャン", 10*time.Millisecond)
+	h.Update(ScanProgress{CurrentPath: "dir/a.txt", Files: 3, Dirs: 1})
+	time.Sleep(50 * time.Millisecond)
+	h.Stop()
+
+	logger.mu.Lock()
+	count := len(logger.messages)
+	messages := append([]string(nil), logger.messages...)
+	logger.mu.Unlock()
+	if assert.NotEmpty(t, messages) {
+		last := messages[len(messages)-1]
+		assert.True(t, strings.Contains(last, "ファイル: 3") && strings.Contains(last, "dir/a.txt"), last)
+	}
+
+	// Stop 後は出力されない
+	time.Sleep(30 * time.Millisecond)
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	assert.Equal(t, count, len(logger.messages))
+}
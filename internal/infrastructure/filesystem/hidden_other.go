@@ -0,0 +1,10 @@
+//go:build !windows
+
+package filesystem
+
+import "io/fs"
+
+// hasHiddenAttribute は Windows 以外では隠し属性がないため常に false を返します
+func hasHiddenAttribute(d fs.DirEntry) bool {
+	return false
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package filesystem
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// hasHiddenAttribute はエントリが Windows の隠し属性を持つかどうかを返します。
+// アーカイブなど OS のファイル以外から読み込んだエントリは属性を持たないため false を返します
+func hasHiddenAttribute(d fs.DirEntry) bool {
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	attrs, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	return ok && attrs.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
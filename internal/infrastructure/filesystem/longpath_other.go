@@ -0,0 +1,15 @@
+//go:build !windows
+
+package filesystem
+
+import "io/fs"
+
+// extendedLengthPath は Windows 以外ではパスの長さの制限がないため absPath をそのまま返します
+func extendedLengthPath(absPath string) string {
+	return absPath
+}
+
+// isJunction は Windows 以外ではジャンクションがないため常に false を返します
+func isJunction(d fs.DirEntry) bool {
+	return false
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package filesystem
+
+import (
+	"io/fs"
+	"strings"
+	"syscall"
+)
+
+// extendedLengthPath は絶対パス absPath に拡張パスの接頭辞（\\?\、UNC パスは \\?\UNC\）を付けます。
+// ルートに付けておくことで、配下のパスが MAX_PATH（260 文字）を超えても開けるようにします
+func extendedLengthPath(absPath string) string {
+	switch {
+	case strings.HasPrefix(absPath, `\\?\`):
+		return absPath
+	case strings.HasPrefix(absPath, `\\`):
+		return `\\?\UNC\` + absPath[2:]
+	}
+	return `\\?\` + absPath
+}
+
+// isJunction はエントリが NTFS のジャンクションなど、シンボリックリンク以外のディレクトリの再解析ポイントかどうかを返します。
+// Go 1.23 以降はジャンクションが fs.ModeIrregular として報告されるため、再解析ポイントの属性とあわせて判定します。
+// クラウドのプレースホルダーなど通常のファイルとして扱われる再解析ポイントは対象外です
+func isJunction(d fs.DirEntry) bool {
+	if d.Type()&fs.ModeIrregular == 0 {
+		return false
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	attrs, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	return ok && attrs.FileAttributes&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0
+}
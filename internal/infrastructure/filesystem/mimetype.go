@@ -0,0 +1,60 @@
+package filesystem
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// textMIMETypes は "text/" 以外でテキストとして扱うメディアタイプです
+var textMIMETypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/javascript": true,
+	"image/svg+xml":          true,
+}
+
+// DetectMIMEType はファイル名と先頭部分の内容からメディアタイプ（"image/png" など、パラメータなし）を判定します。
+// 内容のマジックナンバーを優先し、内容からプレーンテキストとしか判定できない場合のみ、
+// 拡張子から分かるテキスト系のメディアタイプ（"application/json" など）で補完します
+func DetectMIMEType(name string, head []byte) string {
+	detected := baseMediaType(http.DetectContentType(head))
+	if detected != "text/plain" {
+		return detected
+	}
+	if byExt := baseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))); byExt != "" && IsTextMIMEType(byExt) {
+		return byExt
+	}
+	return detected
+}
+
+// IsTextMIMEType はメディアタイプがテキストとして内容を出力できる種類かどうかを返します
+func IsTextMIMEType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || textMIMETypes[mediaType]
+}
+
+// isBinaryContent はメディアタイプ・文字コードと先頭部分の内容から、ファイルをバイナリとして扱うかどうかを判定します。
+// テキスト系と判定された場合でも、UTF-16 以外で NULLバイトを含むものはそのまま出力できないためバイナリとみなします
+func isBinaryContent(mediaType, encoding string, head []byte) bool {
+	if len(head) == 0 { // 空のファイルはバイナリではない
+		return false
+	}
+	if isUTF16(encoding) {
+		return false
+	}
+	return !IsTextMIMEType(mediaType) || bytes.IndexByte(head, 0x00) >= 0
+}
+
+// baseMediaType は "text/plain; charset=utf-8" のような値からパラメータを除いたメディアタイプを返します
+func baseMediaType(value string) string {
+	if value == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return strings.TrimSpace(strings.SplitN(value, ";", 2)[0])
+	}
+	return mediaType
+}
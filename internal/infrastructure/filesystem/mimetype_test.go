@@ -0,0 +1,41 @@
+package filesystem
+
+import (
+	"testing"
+)
+
+func TestDetectMIMEType(t *testing.T) {
+	pngHeader := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name       string
+		fileName   string
+		head       []byte
+		want       string
+		wantBinary bool
+	}{
+		{name: "PNG画像", fileName: "logo.png", head: pngHeader, want: "image/png", wantBinary: true},
+		{name: "拡張子に関係なくマジックナンバーを優先", fileName: "logo.txt", head: pngHeader, want: "image/png", wantBinary: true},
+		{name: "PDF", fileName: "doc.pdf", head: []byte("%PDF-1.7\n"), want: "application/pdf", wantBinary: true},
+		{name: "ZIP", fileName: "a.zip", head: []byte("PK\x03\x04\x14\x00"), want: "application/zip", wantBinary: true},
+		{name: "プレーンテキスト", fileName: "README", head: []byte("package main\n"), want: "text/plain", wantBinary: false},
+		{name: "拡張子でJSONを補完", fileName: "config.json", head: []byte(`{"a": 1}`), want: "application/json", wantBinary: false},
+		{name: "HTML", fileName: "index.html", head: []byte("<!DOCTYPE html><html></html>"), want: "text/html", wantBinary: false},
+		{name: "制御文字を含むデータ", fileName: "data.bin", head: []byte{0x00, 0x01, 0x02, 0x03}, want: "application/octet-stream", wantBinary: true},
+		{name: "BOM付きのUTF-16テキスト", fileName: "utf16.txt", head: []byte{0xFF, 0xFE, 'a', 0x00}, want: "text/plain", wantBinary: false},
+		{name: "BOMがなくNULLバイトを含むデータ", fileName: "nul.txt", head: []byte{'a', 0x00, 'b', '\n'}, want: "application/octet-stream", wantBinary: true},
+		{name: "空のファイル", fileName: "empty.txt", head: nil, want: "text/plain", wantBinary: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectMIMEType(tt.fileName, tt.head)
+			if got != tt.want {
+				t.Errorf("DetectMIMEType() = %q, want %q", got, tt.want)
+			}
+			if gotBinary := isBinaryContent(got, DetectEncoding(tt.head, false), tt.head); gotBinary != tt.wantBinary {
+				t.Errorf("isBinaryContent() = %v, want %v", gotBinary, tt.wantBinary)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// OpenDir は OS のフォルダを fs.FS として開きます。
+// Scanner の走査処理は fs.FS に対して行うため、OS のフォルダはこのアダプターを介してスキャンします。
+// dir を絶対パスに解決し、存在するディレクトリであることを確認したうえで、fs.FS と絶対パスを返します。
+// Windows では長いパスを開けるよう fs.FS のルートに拡張パスの接頭辞を付けます（返す絶対パスには付けません）
+func OpenDir(dir string) (fs.FS, string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("ルートディレクトリの絶対パス取得に失敗: %w", err)
+	}
+
+	info, err := os.Stat(absDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", apperrors.New(apperrors.ErrNotFound, fmt.Sprintf("指定されたルートディレクトリが存在しません: %s", absDir), absDir, nil)
+		}
+		return nil, "", apperrors.Wrap("ルートディレクトリ情報の取得に失敗", absDir, err)
+	}
+	if !info.IsDir() {
+		return nil, "", apperrors.New(apperrors.ErrNotDirectory, fmt.Sprintf("指定されたルートパスはディレクトリではありません: %s", absDir), absDir, nil)
+	}
+	return os.DirFS(extendedLengthPath(absDir)), absDir, nil
+}
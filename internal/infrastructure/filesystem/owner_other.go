@@ -0,0 +1,17 @@
+//go:build !unix
+
+package filesystem
+
+import "io/fs"
+
+// ownerNames は所有者とグループを取得できない環境では何も保持しません
+type ownerNames struct{}
+
+func newOwnerNames() *ownerNames {
+	return &ownerNames{}
+}
+
+// ownerOf は所有者とグループを取得できない環境では常に空文字列を返します
+func (n *ownerNames) ownerOf(info fs.FileInfo) (string, string) {
+	return "", ""
+}
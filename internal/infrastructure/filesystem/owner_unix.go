@@ -0,0 +1,47 @@
+//go:build unix
+
+package filesystem
+
+import (
+	"io/fs"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// ownerNames は所有者とグループの ID から名前への変換結果をキャッシュします。スキャンごとに作成します
+type ownerNames struct {
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+func newOwnerNames() *ownerNames {
+	return &ownerNames{users: make(map[uint32]string), groups: make(map[uint32]string)}
+}
+
+// ownerOf は OS のファイルの情報から所有者とグループの名前を返します。
+// 名前を解決できない場合は ID の数値を返し、OS のファイル以外の情報の場合は空文字列を返します
+func (n *ownerNames) ownerOf(info fs.FileInfo) (string, string) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", ""
+	}
+	uid, gid := uint32(st.Uid), uint32(st.Gid)
+	owner, ok := n.users[uid]
+	if !ok {
+		owner = strconv.FormatUint(uint64(uid), 10)
+		if u, err := user.LookupId(owner); err == nil {
+			owner = u.Username
+		}
+		n.users[uid] = owner
+	}
+	group, ok := n.groups[gid]
+	if !ok {
+		group = strconv.FormatUint(uint64(gid), 10)
+		if g, err := user.LookupGroupId(group); err == nil {
+			group = g.Name
+		}
+		n.groups[gid] = group
+	}
+	return owner, group
+}
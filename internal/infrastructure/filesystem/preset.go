@@ -0,0 +1,60 @@
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// dirOnly はディレクトリにのみ一致する無視パターン（"node_modules/" など）を返します
+func dirOnly(name string) string {
+	return name + string(filepath.Separator)
+}
+
+// IgnorePresets は名前で指定できる組み込みの無視パターンの一覧です。
+// 依存パッケージ・ビルド成果物・キャッシュなど、生成されたフォルダを除外するために使用します。
+// ディレクトリ名のパターンはディレクトリにのみ一致させ、同じ名前のファイル（build スクリプトなど）は除外しません。
+// DefaultIgnorePatterns は常に適用するため、ここには含めません
+var IgnorePresets = map[string][]string{
+	"node":   {dirOnly("node_modules"), dirOnly("dist"), dirOnly("build"), dirOnly("coverage"), dirOnly(".next"), dirOnly(".nuxt"), dirOnly(".cache")},
+	"python": {dirOnly("__pycache__"), dirOnly(".venv"), dirOnly("venv"), dirOnly(".pytest_cache"), dirOnly(".mypy_cache"), dirOnly(".tox"), dirOnly("coverage"), "*.pyc"},
+	"go":     {dirOnly("vendor")},
+	"rust":   {dirOnly("target")},
+	"java":   {dirOnly("target"), dirOnly("build"), dirOnly(".gradle"), dirOnly("out")},
+}
+
+// codePreset はすべての言語のプリセットを合わせた "code" プリセットの名前です
+const codePreset = "code"
+
+// IgnorePresetNames は指定できるプリセットの名前を名前の順で返します
+func IgnorePresetNames() []string {
+	names := []string{codePreset}
+	for name := range IgnorePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetIgnorePatterns はプリセットの名前から無視パターンを返します。
+// "code" はすべての言語のプリセットの無視パターンを重複を除いて返します
+func PresetIgnorePatterns(name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if patterns, ok := IgnorePresets[name]; ok {
+		return slices.Clone(patterns), nil
+	}
+	if name != codePreset {
+		return nil, fmt.Errorf("未対応のプリセットです: %s（%s のいずれかを指定してください）", name, strings.Join(IgnorePresetNames(), ", "))
+	}
+	var patterns []string
+	for _, preset := range IgnorePresetNames() {
+		for _, pattern := range IgnorePresets[preset] {
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns, nil
+}
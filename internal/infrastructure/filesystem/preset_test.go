@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresetIgnorePatterns(t *testing.T) {
+	code, err := PresetIgnorePatterns("code")
+	assert.NoError(t, err)
+	for _, name := range []string{"node_modules", "vendor", "target", "dist", "build", "__pycache__", ".venv", "coverage"} {
+		assert.Contains(t, code, dirOnly(name))
+	}
+	assert.Len(t, code, len(unique(code)), "code プリセットのパターンが重複しています")
+
+	python, err := PresetIgnorePatterns(" Python ")
+	assert.NoError(t, err)
+	assert.Contains(t, python, "*.pyc")
+
+	_, err = PresetIgnorePatterns("unknown")
+	assert.ErrorContains(t, err, "未対応のプリセットです")
+}
+
+func TestFileSystemScanner_ScanPreset(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":                {Data: []byte("package main")},
+		"build":                  {Data: []byte("#!/bin/sh")},
+		"web/build/app.js":       {Data: []byte("bundle")},
+		"web/node_modules/x.js":  {Data: []byte("dep")},
+		"tool/__pycache__/t.pyc": {Data: []byte("cache")},
+		"tool/t.py":              {Data: []byte("print()")},
+	}
+	patterns, err := PresetIgnorePatterns("code")
+	assert.NoError(t, err)
+
+	entries, err := mustNewScanner(t, &mockLogger{}, ScannerOptions{IgnorePatterns: patterns}).ScanFS(context.Background(), fsys, "root")
+	assert.NoError(t, err)
+	var relPaths []string
+	for _, entry := range entries {
+		relPaths = append(relPaths, entry.RelPath)
+	}
+	// ディレクトリ名のパターンは同じ名前のファイルには一致しない
+	assert.Equal(t, []string{"build", "main.go", "tool", "tool/t.py", "web"}, relPaths)
+}
+
+func unique(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+)
+
+// DefaultPreviewBytes はプレビューで読み込む先頭部分の既定のバイト数です
+const DefaultPreviewBytes = 64 * 1024
+
+// previewDecoders はプレビューで UTF-8 に変換する文字コードと、その変換方法です
+var previewDecoders = map[string]encoding.Encoding{
+	model.EncodingUTF16LE:  unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	model.EncodingUTF16BE:  unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	model.EncodingShiftJIS: japanese.ShiftJIS,
+	model.EncodingEUCJP:    japanese.EUCJP,
+	model.EncodingLatin1:   charmap.ISO8859_1,
+}
+
+// Preview はファイルの先頭部分の内容と、スキャンと同じ方法で判定した種類です。
+// GUI のフォルダ構成で選択したファイルの内容を表示するために使用します
+type Preview struct {
+	// Content は先頭部分を UTF-8 に変換した内容です。バイナリファイルの場合は空です
+	Content string
+	// Size はファイルのサイズ（バイト）です
+	Size int64
+	// MIMEType は内容とファイル名から判定したメディアタイプです
+	MIMEType string
+	// Encoding はテキストファイルの文字コードです。バイナリファイルの場合は空です
+	Encoding string
+	// IsBinary はファイルをバイナリとして扱うかどうかを示します
+	IsBinary bool
+	// Truncated はファイルの途中までしか読み込んでいないことを示します
+	Truncated bool
+}
+
+// PreviewFile はファイル filePath の先頭 maxBytes バイトを読み込み、プレビューを返します。
+// maxBytes が 0 以下の場合は DefaultPreviewBytes を使用します
+func PreviewFile(filePath string, maxBytes int) (Preview, error) {
+	fsys, absDir, err := OpenDir(filepath.Dir(filePath))
+	if err != nil {
+		return Preview{}, err
+	}
+	name := filepath.Base(filePath)
+	p, err := PreviewFS(fsys, name, maxBytes)
+	if err != nil {
+		return Preview{}, apperrors.Wrap("ファイルのプレビューに失敗しました", filepath.Join(absDir, name), err)
+	}
+	return p, nil
+}
+
+// PreviewFS は fsys の中のファイル name の先頭 maxBytes バイトを読み込み、プレビューを返します。
+// 文字コードとバイナリの判定はスキャンと同じく先頭部分で行い、バイナリファイルの内容は返しません。
+// 途中までしか読み込まなかった場合は、末尾で途切れた文字を取り除きます
+func PreviewFS(fsys fs.FS, name string, maxBytes int) (Preview, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultPreviewBytes
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return Preview{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return Preview{}, err
+	}
+	if info.IsDir() {
+		return Preview{}, fmt.Errorf("フォルダはプレビューできません: %s", name)
+	}
+
+	head := make([]byte, maxBytes)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return Preview{}, err
+	}
+	head = head[:n]
+	p := Preview{Size: info.Size(), Truncated: int64(n) < info.Size()}
+
+	// バイナリの判定はスキャンと同じ大きさの先頭部分で行う
+	check := head[:min(n, DefaultBinaryCheckSize)]
+	checkTruncated := p.Truncated || len(check) < n
+	encodingName := DetectEncoding(check, checkTruncated)
+	p.MIMEType = DetectMIMEType(path.Base(name), check)
+	p.IsBinary = isBinaryContent(p.MIMEType, encodingName, check)
+	if p.IsBinary {
+		return p, nil
+	}
+	p.Encoding = encodingName
+	p.Content = decodePreview(head, encodingName, p.Truncated)
+	return p, nil
+}
+
+// decodePreview は先頭部分を UTF-8 に変換します。truncated の場合は末尾で途切れた文字を取り除きます
+func decodePreview(head []byte, encodingName string, truncated bool) string {
+	dec, ok := previewDecoders[encodingName]
+	if !ok {
+		for i := 0; truncated && i < utf8.UTFMax-1 && len(head) > 0 && !utf8.Valid(head); i++ {
+			head = head[:len(head)-1]
+		}
+		return strings.TrimPrefix(string(head), "\ufeff")
+	}
+	decoded, err := dec.NewDecoder().Bytes(head)
+	if err != nil {
+		return string(head)
+	}
+	content := string(decoded)
+	if truncated {
+		content = strings.TrimSuffix(content, string(utf8.RuneError))
+	}
+	return content
+}
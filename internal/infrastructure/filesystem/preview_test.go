@@ -0,0 +1,69 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/japanese"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestPreviewFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":   {Data: []byte("package main\n")},
+		"sjis.txt":  {Data: mustEncode(t, japanese.ShiftJIS, "こんにちは\n")},
+		"logo.png":  {Data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+		"long.txt":  {Data: []byte("あいうえお")},
+		"empty.txt": {Data: nil},
+		"dir/a.txt": {Data: []byte("a")},
+	}
+
+	tests := []struct {
+		name          string
+		file          string
+		maxBytes      int
+		wantContent   string
+		wantEncoding  string
+		wantBinary    bool
+		wantTruncated bool
+	}{
+		{name: "UTF-8 のテキスト", file: "main.go", wantContent: "package main\n", wantEncoding: model.EncodingUTF8},
+		{name: "Shift_JIS のテキストは UTF-8 に変換する", file: "sjis.txt", wantContent: "こんにちは\n", wantEncoding: model.EncodingShiftJIS},
+		{name: "バイナリファイルは内容を返さない", file: "logo.png", wantBinary: true},
+		{name: "途中で途切れた文字を取り除く", file: "long.txt", maxBytes: 7, wantContent: "あい", wantEncoding: model.EncodingUTF8, wantTruncated: true},
+		{name: "空のファイル", file: "empty.txt", wantEncoding: model.EncodingUTF8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PreviewFS(fsys, tt.file, tt.maxBytes)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantContent, got.Content)
+			assert.Equal(t, tt.wantEncoding, got.Encoding)
+			assert.Equal(t, tt.wantBinary, got.IsBinary)
+			assert.Equal(t, tt.wantTruncated, got.Truncated)
+			assert.Equal(t, int64(len(fsys[tt.file].Data)), got.Size)
+		})
+	}
+
+	_, err := PreviewFS(fsys, "dir", 0)
+	assert.ErrorContains(t, err, "フォルダはプレビューできません")
+	_, err = PreviewFS(fsys, "missing.txt", 0)
+	assert.Error(t, err)
+}
+
+func TestPreviewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	assert.NoError(t, os.WriteFile(path, []byte("# FolderScope\n"), 0644))
+
+	got, err := PreviewFile(path, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "# FolderScope\n", got.Content)
+	assert.False(t, got.Truncated)
+
+	_, err = PreviewFile(filepath.Join(filepath.Dir(path), "missing.md"), 0)
+	assert.ErrorContains(t, err, "ファイルのプレビューに失敗しました")
+}
@@ -3,14 +3,19 @@ package filesystem
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
+	"FolderScope/internal/domain/apperrors"
 	"FolderScope/internal/domain/model"
+	"FolderScope/internal/domain/rules"
 	"FolderScope/internal/infrastructure/logging"
 )
 
@@ -28,6 +33,7 @@ type DirectoryValidator interface {
 type FileSystemScanner interface {
 	DirectoryValidator
 	Scan(ctx context.Context, rootDir string) ([]model.FileSystemEntry, error)
+	ScanFS(ctx context.Context, fsys fs.FS, root string) ([]model.FileSystemEntry, error)
 }
 
 // Scanner はファイルシステムをスキャンするための構造体です
@@ -36,29 +42,181 @@ type Scanner struct {
 	binaryCheckSize   int
 	ignorePatterns    []string // 追加
 	ignoreBinaryFiles bool     // 追加
+	includeHidden     bool
+	computeHash       bool
+	hashWorkers       int
+	progress          ProgressFunc
+	includeRegexps    []*regexp.Regexp
+	excludeRegexps    []*regexp.Regexp
+	rules             *rules.Engine
+	minSize, maxSize  int64
+	modifiedAfter     time.Time
+	modifiedBefore    time.Time
+	symlinkPolicy     SymlinkPolicy
+	accessPolicy      AccessPolicy
 }
 
+// ScannerOptions はスキャナーの動作を制御するオプションです
+// JSON タグはエディタ連携などの外部インターフェースからオプションを受け取るために使用します
+type ScannerOptions struct {
+	// IgnorePatterns はデフォルトの無視パターンに追加するパターンです
+	IgnorePatterns []string `json:"ignorePatterns,omitempty"`
+	// IgnoreBinaryFiles はバイナリファイルを結果から除外するかどうかを示します
+	IgnoreBinaryFiles bool `json:"ignoreBinaryFiles,omitempty"`
+	// IncludeHidden は隠しファイル・隠しフォルダ（名前が '.' で始まるもの、Windows で隠し属性を持つもの）を結果に含めるかどうかを示します。
+	// false の場合は隠しフォルダの配下も含めて除外します。無視パターンに一致するものは、この指定にかかわらず除外します
+	IncludeHidden bool `json:"includeHidden,omitempty"`
+	// IncludeRegexps はルートからの相対パス（'/' 区切り）に対する正規表現です。
+	// 指定された場合、いずれかに一致するファイルのみが結果に含まれます
+	IncludeRegexps []string `json:"includeRegexps,omitempty"`
+	// ExcludeRegexps はルートからの相対パス（'/' 区切り）に対する正規表現です。
+	// いずれかに一致するファイル・ディレクトリは結果から除外されます
+	ExcludeRegexps []string `json:"excludeRegexps,omitempty"`
+	// ComputeHash はファイルごとに SHA-256 ハッシュを計算するかどうかを示します
+	ComputeHash bool `json:"computeHash,omitempty"`
+	// HashWorkers はハッシュを並行して計算するワーカーの数です。0 の場合は CPU 数を使用します
+	HashWorkers int `json:"hashWorkers,omitempty"`
+	// Rules はパスごとにファイルの扱い（除外・内容の出力方法）を上書きするルールです。
+	// 除外のルールに一致したエントリは結果から除外し、それ以外のルールはエントリの ContentMode に記録します
+	Rules []rules.Rule `json:"rules,omitempty"`
+	// MinSize と MaxSize はファイルサイズ（バイト）の範囲です。範囲外のファイルは内容を読み込まずに結果から除外します。0 は制限なしを示します
+	MinSize int64 `json:"minSize,omitempty"`
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// ModifiedAfter は更新日時の下限です。この日時より前に更新されたファイルは内容を読み込まずに結果から除外します。ゼロ値は制限なしを示します
+	ModifiedAfter time.Time `json:"modifiedAfter,omitempty"`
+	// ModifiedBefore は更新日時の上限です。この日時以降に更新されたファイルは内容を読み込まずに結果から除外します。ゼロ値は制限なしを示します
+	ModifiedBefore time.Time `json:"modifiedBefore,omitempty"`
+	// SymlinkPolicy はシンボリックリンクの扱いです。空の場合は SymlinkLink として扱います
+	SymlinkPolicy SymlinkPolicy `json:"symlinkPolicy,omitempty"`
+	// AccessPolicy は権限がないために読み込めなかったディレクトリの扱いです。空の場合は AccessReport として扱います
+	AccessPolicy AccessPolicy `json:"accessPolicy,omitempty"`
+}
+
+// CompileRegexps は正規表現パターンをコンパイルします。
+// 不正なパターンが含まれる場合は、そのパターンを示す ErrInvalidInput の種類のエラーを返します
+func CompileRegexps(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("正規表現 '%s' が不正です", pattern), "", err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// ScanProgress はスキャンの進捗状況を表します
+type ScanProgress struct {
+	// CurrentPath は最後に処理したエントリの相対パスです
+	CurrentPath string
+	// Files はこれまでに記録したファイル数です
+	Files int
+	// Dirs はこれまでに記録したディレクトリ数です
+	Dirs int
+}
+
+// ProgressFunc はスキャン中にエントリを記録するたびに呼び出されるコールバックです
+type ProgressFunc func(ScanProgress)
+
 // NewScanner は新しい Scanner インスタンスを作成します
 // 引数に ignorePatterns と ignoreBinaryFiles を追加
+// 隠しファイル・隠しフォルダは結果に含めます
 func NewScanner(logger logging.Logger, ignorePatterns []string, ignoreBinaryFiles bool) *Scanner {
+	return newScanner(logger, ScannerOptions{
+		IgnorePatterns:    ignorePatterns,
+		IgnoreBinaryFiles: ignoreBinaryFiles,
+		IncludeHidden:     true,
+	}, nil, nil)
+}
+
+// NewScannerWithOptions は ScannerOptions を指定して新しい Scanner インスタンスを作成します。
+// IncludeRegexps・ExcludeRegexps に不正な正規表現が含まれる場合は、ErrInvalidInput の種類のエラーを返します
+func NewScannerWithOptions(logger logging.Logger, opts ScannerOptions) (*Scanner, error) {
+	includeRegexps, err := CompileRegexps(opts.IncludeRegexps)
+	if err != nil {
+		return nil, err
+	}
+	excludeRegexps, err := CompileRegexps(opts.ExcludeRegexps)
+	if err != nil {
+		return nil, err
+	}
+	return newScanner(logger, opts, includeRegexps, excludeRegexps), nil
+}
+
+// newScanner はコンパイル済みの正規表現フィルタを指定して Scanner インスタンスを作成します
+func newScanner(logger logging.Logger, opts ScannerOptions, includeRegexps, excludeRegexps []*regexp.Regexp) *Scanner {
 	// デフォルトの無視パターンとユーザー指定の無視パターンをマージ
-	allIgnorePatterns := append(DefaultIgnorePatterns, ignorePatterns...) // DefaultIgnorePatterns を先に
-	// 重複を削除する場合 (オプション)
-	// uniquePatterns := make(map[string]struct{})
-	// for _, p := range allIgnorePatterns {
-	//  uniquePatterns[p] = struct{}{}
-	// }
-	// finalPatterns := make([]string, 0, len(uniquePatterns))
-	// for p := range uniquePatterns {
-	//  finalPatterns = append(finalPatterns, p)
-	// }
+	// DefaultIgnorePatterns の内部配列を共有しないよう、新しいスライスにコピーする
+	allIgnorePatterns := make([]string, 0, len(DefaultIgnorePatterns)+len(opts.IgnorePatterns))
+	allIgnorePatterns = append(allIgnorePatterns, DefaultIgnorePatterns...) // DefaultIgnorePatterns を先に
+	allIgnorePatterns = append(allIgnorePatterns, opts.IgnorePatterns...)
 
 	return &Scanner{
 		logger:            logger,
 		binaryCheckSize:   DefaultBinaryCheckSize,
 		ignorePatterns:    allIgnorePatterns, // マージしたパターンを使用
-		ignoreBinaryFiles: ignoreBinaryFiles,
+		ignoreBinaryFiles: opts.IgnoreBinaryFiles,
+		includeHidden:     opts.IncludeHidden,
+		computeHash:       opts.ComputeHash,
+		hashWorkers:       opts.HashWorkers,
+		includeRegexps:    includeRegexps,
+		excludeRegexps:    excludeRegexps,
+		rules:             compileRulesLogged(logger, opts.Rules),
+		minSize:           opts.MinSize,
+		maxSize:           opts.MaxSize,
+		modifiedAfter:     opts.ModifiedAfter,
+		modifiedBefore:    opts.ModifiedBefore,
+		symlinkPolicy:     opts.SymlinkPolicy,
+		accessPolicy:      opts.AccessPolicy,
+	}
+}
+
+// outOfRange はファイルのサイズ・更新日時が指定された範囲外の場合に、除外する理由を返します
+func (s *Scanner) outOfRange(info fs.FileInfo) (model.SkipReason, bool) {
+	size := info.Size()
+	if (s.minSize > 0 && size < s.minSize) || (s.maxSize > 0 && size > s.maxSize) {
+		return model.SkipSize, true
+	}
+	modTime := info.ModTime()
+	if (!s.modifiedAfter.IsZero() && modTime.Before(s.modifiedAfter)) || (!s.modifiedBefore.IsZero() && !modTime.Before(s.modifiedBefore)) {
+		return model.SkipModTime, true
+	}
+	return "", false
+}
+
+// compileRulesLogged はパスごとのルールをコンパイルします。不正なルールがある場合は警告を記録し、ルールを適用せずにスキャンします
+func compileRulesLogged(logger logging.Logger, list []rules.Rule) *rules.Engine {
+	engine, err := rules.Compile(list)
+	if err != nil {
+		logger.Log("WARN", "不正なルールが指定されたため、パスごとのルールを適用しません", err)
+		return nil
+	}
+	return engine
+}
+
+// matchesAnyRegexp は相対パスがいずれかの正規表現に一致するかどうかを返します
+func matchesAnyRegexp(regexps []*regexp.Regexp, relPath string) bool {
+	_, matched := firstMatchingRegexp(regexps, relPath)
+	return matched
+}
+
+// firstMatchingRegexp は相対パスに一致した最初の正規表現を返します。一致するものがない場合は false を返します
+func firstMatchingRegexp(regexps []*regexp.Regexp, relPath string) (string, bool) {
+	for _, re := range regexps {
+		if re.MatchString(relPath) {
+			return re.String(), true
+		}
 	}
+	return "", false
+}
+
+// WithProgress は進捗コールバックを設定した Scanner のコピーを返します。
+// 元の Scanner の設定は変更されません
+func (s *Scanner) WithProgress(fn ProgressFunc) *Scanner {
+	copied := *s
+	copied.progress = fn
+	return &copied
 }
 
 // ValidateDirectoryPath はパスが安全で有効なディレクトリであることを確認します
@@ -69,11 +227,14 @@ func (s *Scanner) ValidateDirectoryPath(path string) error {
 
 	fileInfo, err := os.Stat(path)
 	if err != nil {
-		return fmt.Errorf("ディレクトリが存在しません: %w", err)
+		if errors.Is(err, fs.ErrPermission) {
+			return apperrors.New(apperrors.ErrPermission, "ディレクトリにアクセスできません", path, err)
+		}
+		return apperrors.New(apperrors.ErrNotFound, "ディレクトリが存在しません", path, err)
 	}
 
 	if !fileInfo.IsDir() {
-		return fmt.Errorf("指定されたパスはディレクトリではありません")
+		return apperrors.New(apperrors.ErrNotDirectory, "指定されたパスはディレクトリではありません", path, nil)
 	}
 
 	// NULLバイトのみをチェックする
@@ -84,83 +245,100 @@ func (s *Scanner) ValidateDirectoryPath(path string) error {
 	return nil
 }
 
-// isBinaryFile は与えられたバイトデータがバイナリファイルかどうかを判定します
-func (s *Scanner) isBinaryFile(content []byte) bool {
-	limit := len(content)
-	if limit == 0 { // 空のファイルはバイナリではない
+// matchesIgnorePattern は指定されたパスが無視パターンに一致するかどうかを確認します
+func (s *Scanner) matchesIgnorePattern(path string, d fs.DirEntry) (bool, error) {
+	// ディレクトリ名またはファイル名で比較
+	return s.IsIgnoredName(d.Name(), d.IsDir()), nil
+}
+
+// isHiddenEntry は隠しファイル・隠しフォルダを含めない設定で、エントリが隠しファイル・隠しフォルダかどうかを返します
+func (s *Scanner) isHiddenEntry(d fs.DirEntry) bool {
+	if s.includeHidden {
 		return false
 	}
-	if limit > s.binaryCheckSize {
-		limit = s.binaryCheckSize
-	}
+	return strings.HasPrefix(d.Name(), ".") || hasHiddenAttribute(d)
+}
 
-	for i := 0; i < limit; i++ {
-		if content[i] == 0x00 { // NULLバイトがあればバイナリとみなす
-			return true
-		}
-		// 制御文字の判定をより厳密に (ただし、UTF-8テキスト内の特定の制御文字は許容される場合がある)
-		// ここでは簡略化のため、NULLバイトのみを主な判定基準とする
-		// if (content[i] < 0x09 && content[i] != 0x0A && content[i] != 0x0D) {
-		//  return true
-		// }
-	}
-	// textChars / totalChars の比率で判定する方法もあるが、ここでは単純な NULL バイトチェック
-	return false
+// IsIgnoredName はファイル名またはディレクトリ名が無視パターンに一致するかどうかを返します。
+// 監視モードのように、スキャン以外の処理で同じ無視パターンを適用するためにも使用します
+func (s *Scanner) IsIgnoredName(name string, isDir bool) bool {
+	_, ignored := s.ignoredBy(name, isDir)
+	return ignored
 }
 
-// matchesIgnorePattern は指定されたパスが無視パターンに一致するかどうかを確認します
-func (s *Scanner) matchesIgnorePattern(path string, d fs.DirEntry) (bool, error) {
-	name := d.Name() // ディレクトリ名またはファイル名で比較
+// ignoredBy はファイル名またはディレクトリ名が一致した最初の無視パターンを返します。一致するパターンがない場合は false を返します
+func (s *Scanner) ignoredBy(name string, isDir bool) (string, bool) {
 	for _, pattern := range s.ignorePatterns {
 		// パターンがディレクトリを示す場合 (例: "node_modules/") は、ディレクトリ名全体と比較
 		if strings.HasSuffix(pattern, string(filepath.Separator)) {
-			if d.IsDir() && strings.TrimSuffix(pattern, string(filepath.Separator)) == name {
-				return true, nil
+			if isDir && strings.TrimSuffix(pattern, string(filepath.Separator)) == name {
+				return pattern, true
 			}
 		} else {
 			// ファイル名またはディレクトリ名に対する glob パターンマッチ
 			matched, err := filepath.Match(pattern, name)
 			if err != nil {
 				s.logger.Log("WARN", fmt.Sprintf("無視パターンの評価エラー: %s on %s", pattern, name), err)
-				// パターンエラーは無視して処理を続行（またはエラーとして扱うか選択）
+				// パターンエラーは無視して処理を続行
 				continue
 			}
 			if matched {
-				return true, nil
+				return pattern, true
 			}
 		}
 	}
-	// フルパスに対するマッチも追加 (オプション)
-	// for _, pattern := range s.ignorePatterns {
-	//   if strings.HasPrefix(path, pattern) { // 例: "/abs/path/to/ignore_this_dir"
-	//     return true, nil
-	//   }
-	// }
-	return false, nil
+	return "", false
 }
 
 // Scan はファイルシステムを走査し、エントリを収集します
 // context.Context を受け取り、キャンセル可能にします
 func (s *Scanner) Scan(ctx context.Context, rootDir string) ([]model.FileSystemEntry, error) {
-	var entries []model.FileSystemEntry
-	absRootDir, err := filepath.Abs(rootDir)
+	fsys, absRootDir, err := OpenDir(rootDir)
 	if err != nil {
-		return nil, fmt.Errorf("ルートディレクトリの絶対パス取得に失敗: %w", err)
+		return nil, err
 	}
+	return s.ScanFS(ctx, fsys, absRootDir)
+}
 
-	// Scan開始前にルートディレクトリの存在と種類を確認
-	info, err := os.Stat(absRootDir)
+// ScanWithStats は Scan と同様に走査し、所要時間やエラー数、除外理由ごとの件数などの統計情報もあわせて返します
+func (s *Scanner) ScanWithStats(ctx context.Context, rootDir string) ([]model.FileSystemEntry, model.ScanStats, error) {
+	fsys, absRootDir, err := OpenDir(rootDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("指定されたルートディレクトリが存在しません: %s", absRootDir)
-		}
-		return nil, fmt.Errorf("ルートディレクトリ情報の取得に失敗: %w", err)
+		return nil, model.ScanStats{}, err
 	}
-	if !info.IsDir() {
-		return nil, fmt.Errorf("指定されたルートパスはディレクトリではありません: %s", absRootDir)
+	return s.ScanFSWithStats(ctx, fsys, absRootDir)
+}
+
+// ScanFS は fsys のルートから走査し、エントリを収集します。
+// アーカイブなど OS のファイルシステム以外の内容をスキャンするために使用します。
+// root はエントリの Path とログに表示するルートのパスで、各エントリの Path は root に相対パスを連結したものになります
+func (s *Scanner) ScanFS(ctx context.Context, fsys fs.FS, root string) ([]model.FileSystemEntry, error) {
+	entries, _, err := s.ScanFSWithStats(ctx, fsys, root)
+	return entries, err
+}
+
+// ScanFSWithStats は ScanFS と同様に走査し、統計情報もあわせて返します
+func (s *Scanner) ScanFSWithStats(ctx context.Context, fsys fs.FS, root string) ([]model.FileSystemEntry, model.ScanStats, error) {
+	var entries []model.FileSystemEntry
+	var progress ScanProgress
+	stats := model.ScanStats{StartedAt: time.Now(), Skipped: make(map[model.SkipReason]int)}
+	// dirInfos はリンクをたどる場合に、走査中のディレクトリの情報を記録して循環を検出するために使用します
+	dirInfos := make(map[string]fs.FileInfo)
+	// followedLinks はたどったディレクトリへのリンクのパスとリンク先です。リンク先のディレクトリのエントリに記録します
+	followedLinks := make(map[string]string)
+	// hardLinks は複数のハードリンクを持つファイルの識別子と、最初に記録したファイルの相対パスです
+	hardLinks := make(map[fileKey]string)
+	owners := newOwnerNames()
+	following := 0
+	// hashes はハッシュを計算する場合に、走査と並行してファイルを読み込み、ハッシュを計算するパイプラインです
+	var hashes *hashPipeline
+	if s.computeHash {
+		hashes = newHashPipeline(ctx, s.hashWorkers)
 	}
+	var walk fs.WalkDirFunc
+	walk = func(fsPath string, d fs.DirEntry, walkErr error) error {
+		path := filepath.Join(root, filepath.FromSlash(fsPath))
 
-	err = filepath.WalkDir(absRootDir, func(path string, d fs.DirEntry, walkErr error) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -169,8 +347,20 @@ func (s *Scanner) Scan(ctx context.Context, rootDir string) ([]model.FileSystemE
 
 		if walkErr != nil {
 			// WalkDir からのエラー（権限など）
-			// 特定のエラー（例: os.ErrPermission）をより詳細にハンドリングすることも可能
+			// 権限がない場合は、スナップショットが不完全であることをレポートに記載するためパスを記録する
 			s.logger.Log("WARN", fmt.Sprintf("パス '%s' のアクセス中にエラー発生 (WalkDir)", path), walkErr)
+			stats.Errors++
+			stats.RecordSkip(model.SkipAccessError)
+			if errors.Is(walkErr, fs.ErrPermission) {
+				isDir := d == nil || d.IsDir()
+				// 読み込めなかったディレクトリは、指定に応じてスキャンを中止するか、一覧に記録せずに配下をスキップする
+				switch {
+				case isDir && s.accessPolicy == AccessFatal:
+					return apperrors.New(apperrors.ErrPermission, "権限がないためディレクトリを読み込めません", path, walkErr)
+				case !isDir || s.accessPolicy != AccessSkip:
+					stats.Inaccessible = append(stats.Inaccessible, model.InaccessiblePath{RelPath: fsPath, IsDir: isDir})
+				}
+			}
 			if d != nil && d.IsDir() {
 				return fs.SkipDir // ディレクトリへのアクセスエラーの場合、そのディレクトリはスキップ
 			}
@@ -178,31 +368,96 @@ func (s *Scanner) Scan(ctx context.Context, rootDir string) ([]model.FileSystemE
 		}
 
 		// ルートディレクトリ自体は結果に含めない
-		if path == absRootDir {
+		if fsPath == "." {
+			s.markVisited(dirInfos, fsPath, d)
 			return nil
 		}
 
 		// 無視パターンのチェック
 		// WalkDir はディレクトリを先に処理するため、ここでディレクトリを無視すればその中身もスキップされる
-		isIgnored, patternErr := s.matchesIgnorePattern(path, d)
-		if patternErr != nil {
-			// パターン評価エラーのログは matchesIgnorePattern 内で記録済み
-			// ここではエラーを返さずに処理を続けるか、エラーを返すか選択
-		}
-		if isIgnored {
-			s.logger.Log("DEBUG", fmt.Sprintf("パス '%s' は無視パターンに一致しました。", path), nil)
+		if pattern, isIgnored := s.ignoredBy(d.Name(), d.IsDir()); isIgnored {
+			s.logger.Log("DEBUG", fmt.Sprintf("パス '%s' は無視パターンに一致しました。", path), nil, "path", path, "reason", model.SkipIgnored, "pattern", pattern)
+			stats.RecordSkip(model.SkipIgnored)
 			if d.IsDir() {
 				return fs.SkipDir // ディレクトリの場合は中身もスキップ
 			}
 			return nil // ファイルの場合はこのファイルのみスキップ
 		}
+		if s.isHiddenEntry(d) {
+			s.logger.Log("DEBUG", fmt.Sprintf("パス '%s' は隠しファイル・隠しフォルダのため除外されました。", path), nil, "path", path, "reason", model.SkipHidden)
+			stats.RecordSkip(model.SkipHidden)
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
 
-		relPath, err := filepath.Rel(absRootDir, path)
-		if err != nil {
-			s.logger.Log("WARN", fmt.Sprintf("相対パスの取得に失敗: %s", path), err)
+		// fs.FS のパスは常に '/' 区切りのため、そのまま相対パスとして使用する
+		relPath := fsPath
+
+		// 正規表現による除外・包含フィルタ（相対パスに対して評価）
+		if re, excluded := firstMatchingRegexp(s.excludeRegexps, relPath); excluded {
+			s.logger.Log("DEBUG", fmt.Sprintf("パス '%s' は除外正規表現に一致しました。", relPath), nil, "path", path, "reason", model.SkipExcluded, "pattern", re)
+			stats.RecordSkip(model.SkipExcluded)
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// 包含フィルタはファイルにのみ適用する（ディレクトリは配下のファイルが一致する可能性があるため走査を続ける）
+		if !d.IsDir() && len(s.includeRegexps) > 0 && !matchesAnyRegexp(s.includeRegexps, relPath) {
+			s.logger.Log("TRACE", "包含正規表現のいずれにも一致しないパスを除外しました。", nil, "path", path, "reason", model.SkipNotIncluded)
+			stats.RecordSkip(model.SkipNotIncluded)
+			return nil
+		}
+
+		// パスごとのルール（除外は配下も含めてスキップし、それ以外は内容の出力方法として記録する）
+		mode, ruled := s.rules.Match(relPath)
+		if ruled && mode == rules.ModeExclude {
+			s.logger.Log("DEBUG", fmt.Sprintf("パス '%s' はルールにより除外されました。", relPath), nil, "path", path, "reason", model.SkipRule)
+			stats.RecordSkip(model.SkipRule)
+			if d.IsDir() {
+				return fs.SkipDir
+			}
 			return nil
 		}
-		relPath = filepath.ToSlash(relPath) // パス区切りを '/' に統一
+
+		// シンボリックリンクの扱い（たどる場合は、リンク先のディレクトリをリンクのパスの配下として走査する）
+		// リンクとして記録する場合もたどる場合も、リンク先のパスとリンク切れかどうかを記録する。Windows のジャンクションも同様に扱う
+		var target fs.FileInfo
+		var linkTarget string
+		var dangling, linkToDir bool
+		if isLink(d) {
+			if s.symlinkPolicy == SymlinkSkip {
+				s.logger.Log("DEBUG", fmt.Sprintf("シンボリックリンク '%s' は除外されました。", path), nil, "path", path, "reason", model.SkipSymlink)
+				stats.RecordSkip(model.SkipSymlink)
+				return nil
+			}
+			linkTarget = readLink(fsys, fsPath, path)
+			info, statErr := fs.Stat(fsys, fsPath)
+			dangling = errors.Is(statErr, fs.ErrNotExist)
+			linkToDir = statErr == nil && info.IsDir()
+			if s.symlinkPolicy == SymlinkFollow {
+				switch {
+				case statErr != nil:
+					// リンク先が存在しない場合は、リンクとして記録する
+					s.logger.Log("WARN", fmt.Sprintf("シンボリックリンク '%s' のリンク先を取得できません", path), statErr)
+				case !info.IsDir():
+					target = info
+				case isCycle(dirInfos, fsPath, info) || following >= MaxSymlinkDepth:
+					s.logger.Log("WARN", fmt.Sprintf("シンボリックリンク '%s' は親フォルダを指して循環しているため、たどりません", path), nil)
+				default:
+					followedLinks[fsPath] = linkTarget
+					following++
+					err := fs.WalkDir(fsys, fsPath, walk)
+					following--
+					return err
+				}
+			}
+		}
+		if d.IsDir() {
+			s.markVisited(dirInfos, fsPath, d)
+		}
 
 		depth := strings.Count(relPath, "/")
 		// ルート直下は Depth 0 だが、一般的には1から数えるため調整 (オプション)
@@ -213,27 +468,72 @@ func (s *Scanner) Scan(ctx context.Context, rootDir string) ([]model.FileSystemE
 			IsDir:   d.IsDir(),
 			RelPath: relPath,
 			Depth:   depth, // ルートからの階層 (ルート直下を0とするか1とするかは要件次第)
-			// Size と ModTime は fs.DirEntry から取得可能 (d.Info())
+
+			LinkTarget: linkTarget,
+			Dangling:   dangling,
+			LinkToDir:  linkToDir,
+		}
+		if t, ok := followedLinks[fsPath]; ok {
+			entry.LinkTarget = t
+		}
+		if ruled && !d.IsDir() {
+			entry.ContentMode = string(mode)
 		}
 
-		if !d.IsDir() {
+		// サイズ・更新日時・パーミッションを取得（取得できなくてもエントリ自体は記録する）
+		// ファイルへのリンクをたどる場合は、リンク先の情報を使用する
+		info, infoErr := d.Info()
+		if target != nil {
+			info, infoErr = target, nil
+		}
+		if infoErr != nil {
+			s.logger.Log("WARN", fmt.Sprintf("パス '%s' のファイル情報取得に失敗", path), infoErr)
+		} else {
+			// サイズ・更新日時による絞り込みはファイルにのみ適用し、内容を読み込む前に判定する
+			if !d.IsDir() {
+				if reason, skip := s.outOfRange(info); skip {
+					stats.RecordSkip(reason)
+					return nil
+				}
+				entry.Size = info.Size()
+			}
+			entry.ModTime = info.ModTime()
+			entry.Permissions = info.Mode()
+			entry.Owner, entry.Group = owners.ownerOf(info)
+		}
+
+		// pending はハッシュを計算するファイルです。エントリを記録した後にパイプラインに渡します
+		var pending *hashJob
+		// ディレクトリへのリンクとリンク切れは内容を持たないため、リンクとしてのみ記録し読み込まない
+		if !d.IsDir() && !entry.IsLinkOnly() {
 			// ファイルの場合、バイナリ判定とスキップ処理
 			var fileContent []byte
+			var file fs.File
 
 			// os.ReadFile は Go 1.16+
 			// fileContent, readErrForBinaryCheck = os.ReadFile(path)
 
 			// より制御しやすくするために os.Open, Read, Close を使う
-			file, openErr := os.Open(path)
+			file, openErr := fsys.Open(fsPath)
 			if openErr != nil {
 				s.logger.Log("WARN", fmt.Sprintf("ファイル '%s' のオープンに失敗", path), openErr)
 				entry.ReadErr = openErr
+				if errors.Is(openErr, fs.ErrPermission) {
+					stats.Inaccessible = append(stats.Inaccessible, model.InaccessiblePath{RelPath: fsPath})
+				}
 				// オープン失敗時はバイナリ判定不可、エラーとしてマーク
 				// IsBinary はデフォルトで false のまま
 			} else {
-				defer file.Close() // walkDir の各イテレーションで呼ばれるため、確実にクローズする
+				// walkDir の各イテレーションで呼ばれるため、確実にクローズする。
+				// ハッシュを計算するファイルは、計算後にパイプラインがクローズする
+				defer func() {
+					if pending == nil {
+						file.Close()
+					}
+				}()
 				buffer := make([]byte, s.binaryCheckSize)
 				n, readErr := file.Read(buffer)
+				stats.BytesRead += int64(n)
 				if readErr != nil && readErr != io.EOF {
 					s.logger.Log("WARN", fmt.Sprintf("ファイル '%s' の読み込みに失敗（バイナリ判定用）", path), readErr)
 					entry.ReadErr = readErr
@@ -243,29 +543,137 @@ func (s *Scanner) Scan(ctx context.Context, rootDir string) ([]model.FileSystemE
 				// file.Close() は defer で実行される
 			}
 
-			if entry.ReadErr == nil { // ファイルが正常に（一部でも）読み込めた場合のみバイナリ判定
-				entry.IsBinary = s.isBinaryFile(fileContent)
+			if entry.ReadErr != nil {
+				stats.Errors++
+			}
+			if entry.ReadErr == nil { // ファイルが正常に（一部でも）読み込めた場合のみ種類とバイナリを判定
+				truncated := len(fileContent) == s.binaryCheckSize
+				encoding := DetectEncoding(fileContent, truncated)
+				entry.MIMEType = DetectMIMEType(d.Name(), fileContent)
+				entry.IsBinary = isBinaryContent(entry.MIMEType, encoding, fileContent)
+				if !entry.IsBinary {
+					entry.Encoding = encoding
+					entry.LineEnding = DetectLineEnding(fileContent, encoding, truncated)
+				}
 			}
 
 			if s.ignoreBinaryFiles && entry.IsBinary {
-				s.logger.Log("DEBUG", fmt.Sprintf("バイナリファイル '%s' は無視されます。", path), nil)
+				s.logger.Log("DEBUG", fmt.Sprintf("バイナリファイル '%s' は無視されます。", path), nil, "path", path, "reason", model.SkipBinary, "bytes", entry.Size)
+				stats.RecordSkip(model.SkipBinary)
 				return nil // バイナリファイルを無視する設定の場合、スキップ
 			}
+
+			// バイナリ判定で読み込んだ先頭部分に続けて残りを読み込み、1 回の読み込みでハッシュを計算する
+			if hashes != nil && entry.ReadErr == nil && file != nil {
+				pending = &hashJob{path: path, head: fileContent, file: file}
+			}
+		}
+
+		// 同じ inode を共有するファイル（ハードリンク）には、最初に記録したファイルの相対パスを記録する
+		if !entry.IsDir && infoErr == nil {
+			if key, ok := hardLinkKey(info); ok {
+				if first, seen := hardLinks[key]; seen {
+					entry.HardLinkOf = first
+				} else {
+					hardLinks[key] = relPath
+				}
+			}
 		}
 
 		entries = append(entries, entry)
+		// パスごとの判定を追跡するためのログのため、メッセージは組み立てずに項目として出力する
+		s.logger.Log("TRACE", "パスを記録しました。", nil, "path", path, "dir", entry.IsDir, "contentMode", entry.ContentMode)
+		if pending != nil {
+			pending.index = len(entries) - 1
+			if err := hashes.submit(*pending); err != nil {
+				return err
+			}
+		}
+		if s.progress != nil {
+			if entry.IsDir {
+				progress.Dirs++
+			} else {
+				progress.Files++
+			}
+			progress.CurrentPath = relPath
+			s.progress(progress)
+		}
 		return nil
-	})
+	}
+	err := fs.WalkDir(fsys, ".", walk)
+	if hashes != nil {
+		// 走査を中止した場合も、計算中のファイルを閉じるために完了を待つ
+		results := hashes.wait()
+		if err == nil {
+			for _, r := range results {
+				stats.BytesRead += r.bytes
+				if r.err != nil {
+					s.logger.Log("WARN", fmt.Sprintf("ファイル '%s' のハッシュ計算に失敗", r.path), r.err, "path", r.path)
+					continue
+				}
+				entries[r.index].Hash = r.hash
+			}
+		}
+	}
 
 	if err != nil && err != fs.SkipDir { // SkipDir はエラーとして扱わない
 		// WalkDir自体から返されたエラー、またはコールバック内で返されたエラー
 		// ctx.Err() の場合もここに到達する
-		if err == context.Canceled || err == context.DeadlineExceeded {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			s.logger.Log("INFO", "スキャン処理がキャンセルまたはタイムアウトしました。", err)
-			return nil, err
+			return nil, stats, apperrors.New(apperrors.ErrCancelled, "スキャン処理がキャンセルされました", root, err)
+		}
+		return nil, stats, apperrors.Wrap("ファイルシステムの走査中にエラーが発生しました", root, err)
+	}
+
+	if len(s.includeRegexps) > 0 {
+		entries = pruneEmptyDirs(entries)
+	}
+	for _, entry := range entries {
+		if entry.IsDir {
+			stats.Dirs++
+		} else {
+			stats.Files++
 		}
-		return nil, fmt.Errorf("ファイルシステムの走査中にエラーが発生しました: %w", err)
 	}
 
-	return entries, nil
+	if len(stats.Inaccessible) > 0 {
+		s.logger.Log("WARN", fmt.Sprintf("権限がないため %d 件のパスを読み込めませんでした。レポートの「アクセスできなかったパス」に記載します", len(stats.Inaccessible)), nil)
+	}
+	stats.DurationMillis = time.Since(stats.StartedAt).Milliseconds()
+	// 1 回のスキャンの結果をまとめた記録として、集計やアラートで参照できるよう項目に出力する
+	s.logger.Log("INFO", "スキャンの統計情報", nil,
+		"path", root, "files", stats.Files, "dirs", stats.Dirs, "bytesRead", stats.BytesRead,
+		"skipped", stats.Skipped, "errors", stats.Errors, "durationMs", stats.DurationMillis)
+	return entries, stats, nil
+}
+
+// pruneEmptyDirs は配下にファイルを 1 つも含まないディレクトリエントリを取り除きます
+// 包含フィルタ適用時に、一致するファイルがないディレクトリが構成に残らないようにするために使用します
+func pruneEmptyDirs(entries []model.FileSystemEntry) []model.FileSystemEntry {
+	nonEmpty := make(map[string]struct{})
+	for _, entry := range entries {
+		if entry.IsDir {
+			continue
+		}
+		for dir := entry.RelPath; ; {
+			idx := strings.LastIndex(dir, "/")
+			if idx < 0 {
+				break
+			}
+			dir = dir[:idx]
+			nonEmpty[dir] = struct{}{}
+		}
+	}
+
+	pruned := entries[:0]
+	for _, entry := range entries {
+		if entry.IsDir {
+			if _, ok := nonEmpty[entry.RelPath]; !ok {
+				continue
+			}
+		}
+		pruned = append(pruned, entry)
+	}
+	return pruned
 }
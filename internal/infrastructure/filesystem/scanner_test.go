@@ -2,15 +2,26 @@ package filesystem
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/user"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"FolderScope/internal/domain/apperrors"
 	"FolderScope/internal/domain/model"
+	"FolderScope/internal/domain/rules"
+	"FolderScope/internal/infrastructure/logging"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -23,7 +34,7 @@ type mockLogger struct {
 	}
 }
 
-func (m *mockLogger) Log(level, message string, err error) {
+func (m *mockLogger) Log(level, message string, err error, attrs ...any) {
 	m.logs = append(m.logs, struct {
 		level   string
 		message string
@@ -503,3 +514,641 @@ func min(a, b int) int {
 // (実際の編集時には、このコメントブロック内の思考は省略し、最終的なコードのみを提示する)
 // `time` の import も削除する。
 // `formatEntry` 内のコメントも整理する。
+
+func TestFileSystemScanner_ScanRegexFilters(t *testing.T) {
+	logger := &mockLogger{}
+	baseDir := t.TempDir()
+
+	for _, rel := range []string{"internal/a.go", "internal/a_test.go", "internal/sub/b_test.go", "cmd/main.go", "docs/readme.md"} {
+		path := filepath.Join(baseDir, filepath.FromSlash(rel))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte("package x"), 0644))
+	}
+
+	testCases := []struct {
+		name        string
+		options     ScannerOptions
+		wantRelPath []string
+	}{
+		{
+			name:        "包含正規表現（一致しないディレクトリは除去）",
+			options:     ScannerOptions{IncludeRegexps: []string{`^internal/.*_test\.go$`}},
+			wantRelPath: []string{"internal", "internal/a_test.go", "internal/sub", "internal/sub/b_test.go"},
+		},
+		{
+			name:        "除外正規表現（ディレクトリごと除外）",
+			options:     ScannerOptions{ExcludeRegexps: []string{`^internal/sub$`, `\.md$`}},
+			wantRelPath: []string{"cmd", "cmd/main.go", "docs", "internal", "internal/a.go", "internal/a_test.go"},
+		},
+		{
+			name:        "包含と除外の併用",
+			options:     ScannerOptions{IncludeRegexps: []string{`\.go$`}, ExcludeRegexps: []string{`_test\.go$`}},
+			wantRelPath: []string{"cmd", "cmd/main.go", "internal", "internal/a.go"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := mustNewScanner(t, logger, tc.options)
+			entries, err := scanner.Scan(context.Background(), baseDir)
+			assert.NoError(t, err)
+
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.RelPath)
+			}
+			sort.Strings(got)
+			assert.Equal(t, tc.wantRelPath, got)
+		})
+	}
+}
+
+// mustNewScanner は opts のスキャナーを作成します。作成に失敗した場合はテストを中止します
+func mustNewScanner(t *testing.T, logger logging.Logger, opts ScannerOptions) *Scanner {
+	t.Helper()
+	scanner, err := NewScannerWithOptions(logger, opts)
+	if err != nil {
+		t.Fatalf("NewScannerWithOptions() error = %v", err)
+	}
+	return scanner
+}
+
+func TestNewScannerWithOptions_InvalidRegexp(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ScannerOptions
+	}{
+		{"包含正規表現", ScannerOptions{IncludeRegexps: []string{`(`, `^cmd/`}}},
+		{"除外正規表現", ScannerOptions{ExcludeRegexps: []string{`[`}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, err := NewScannerWithOptions(&mockLogger{}, tt.options)
+			assert.Nil(t, scanner)
+			assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+		})
+	}
+}
+
+func TestCompileRegexps(t *testing.T) {
+	_, err := CompileRegexps([]string{`^ok$`, `(`})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "(")
+
+	compiled, err := CompileRegexps([]string{`^ok$`})
+	assert.NoError(t, err)
+	assert.Len(t, compiled, 1)
+}
+
+func TestFileSystemScanner_ScanMetadata(t *testing.T) {
+	logger := &mockLogger{}
+	baseDir := t.TempDir()
+
+	filePath := filepath.Join(baseDir, "data.txt")
+	assert.NoError(t, os.WriteFile(filePath, []byte("12345"), 0640))
+	assert.NoError(t, os.Mkdir(filepath.Join(baseDir, "sub"), 0755))
+
+	entries, err := NewScanner(logger, nil, false).Scan(context.Background(), baseDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		assert.False(t, entry.ModTime.IsZero(), "ModTime が設定されていません: %s", entry.RelPath)
+		if entry.IsDir {
+			assert.Equal(t, int64(0), entry.Size)
+			assert.True(t, entry.Permissions.IsDir())
+		} else {
+			assert.Equal(t, int64(5), entry.Size)
+			assert.True(t, entry.Permissions.IsRegular())
+		}
+	}
+}
+
+func TestFileSystemScanner_ScanHash(t *testing.T) {
+	logger := &mockLogger{}
+	baseDir := t.TempDir()
+
+	// バイナリ判定用の読み込みサイズを超える内容でも全体のハッシュが計算されること
+	large := strings.Repeat("0123456789abcdef", DefaultBinaryCheckSize/8)
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "large.txt"), []byte(large), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "empty.txt"), nil, 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(baseDir, "sub"), 0755))
+
+	hashes := map[string]string{
+		"large.txt": "",
+		"empty.txt": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}
+	sum := sha256.Sum256([]byte(large))
+	hashes["large.txt"] = hex.EncodeToString(sum[:])
+
+	entries, err := mustNewScanner(t, logger, ScannerOptions{ComputeHash: true}).Scan(context.Background(), baseDir)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		if entry.IsDir {
+			assert.Empty(t, entry.Hash)
+			continue
+		}
+		assert.Equal(t, hashes[entry.RelPath], entry.Hash, entry.RelPath)
+	}
+
+	// オプション無効時は計算しない
+	entries, err = NewScanner(logger, nil, false).Scan(context.Background(), baseDir)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		assert.Empty(t, entry.Hash)
+	}
+}
+
+func TestFileSystemScanner_ScanProgress(t *testing.T) {
+	logger := &mockLogger{}
+	baseDir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(baseDir, "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "sub", "a.txt"), []byte("a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "b.txt"), []byte("b"), 0644))
+
+	base := NewScanner(logger, nil, false)
+	var updates []ScanProgress
+	entries, err := base.WithProgress(func(p ScanProgress) {
+		updates = append(updates, p)
+	}).Scan(context.Background(), baseDir)
+	assert.NoError(t, err)
+
+	assert.Len(t, updates, len(entries))
+	last := updates[len(updates)-1]
+	assert.Equal(t, 2, last.Files)
+	assert.Equal(t, 1, last.Dirs)
+	assert.NotEmpty(t, last.CurrentPath)
+
+	// 元の Scanner には進捗コールバックが設定されない
+	assert.Nil(t, base.progress)
+}
+
+func TestFileSystemScanner_ErrorKinds(t *testing.T) {
+	scanner := NewScanner(&mockLogger{}, nil, false)
+	baseDir := t.TempDir()
+	filePath := filepath.Join(baseDir, "file.txt")
+	assert.NoError(t, os.WriteFile(filePath, []byte("a"), 0644))
+
+	assert.ErrorIs(t, scanner.ValidateDirectoryPath(filepath.Join(baseDir, "missing")), apperrors.ErrNotFound)
+	assert.ErrorIs(t, scanner.ValidateDirectoryPath(filePath), apperrors.ErrNotDirectory)
+
+	_, err := scanner.Scan(context.Background(), filepath.Join(baseDir, "missing"))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	_, err = scanner.Scan(context.Background(), filePath)
+	assert.ErrorIs(t, err, apperrors.ErrNotDirectory)
+
+	// キャンセル時は apperrors.ErrCancelled と context.Canceled のどちらでも判定できる
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = scanner.Scan(ctx, baseDir)
+	assert.ErrorIs(t, err, apperrors.ErrCancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFileSystemScanner_ScanFS(t *testing.T) {
+	logger := &mockLogger{}
+	fsys := fstest.MapFS{
+		"a.txt":       {Data: []byte("alpha")},
+		"bin.dat":     {Data: []byte{0x00, 0x01}},
+		"sub/b.txt":   {Data: []byte("beta")},
+		".git/config": {Data: []byte("ignored")},
+	}
+
+	entries, err := NewScanner(logger, nil, true).ScanFS(context.Background(), fsys, "archive.zip")
+	assert.NoError(t, err)
+
+	var relPaths []string
+	for _, entry := range entries {
+		relPaths = append(relPaths, entry.RelPath)
+		assert.Equal(t, filepath.Join("archive.zip", filepath.FromSlash(entry.RelPath)), entry.Path)
+	}
+	assert.Equal(t, []string{"a.txt", "sub", "sub/b.txt"}, relPaths)
+	assert.Equal(t, 1, entries[2].Depth)
+}
+
+func TestFileSystemScanner_ScanFSWithStats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":       {Data: []byte("alpha")},
+		"b.log":       {Data: []byte("excluded")},
+		"c.md":        {Data: []byte("not included")},
+		"bin.dat":     {Data: []byte{0x00, 0x01}},
+		".git/config": {Data: []byte("ignored")},
+	}
+	scanner := mustNewScanner(t, &mockLogger{}, ScannerOptions{
+		IgnoreBinaryFiles: true,
+		IncludeRegexps:    []string{`\.(txt|log|dat)$`},
+		ExcludeRegexps:    []string{`\.log$`},
+	})
+
+	logger := &mockLogger{}
+	scanner.logger = logger
+	entries, stats, err := scanner.ScanFSWithStats(context.Background(), fsys, "memory")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.False(t, stats.StartedAt.IsZero())
+	assert.Equal(t, 0, stats.Errors)
+	assert.Equal(t, map[model.SkipReason]int{
+		model.SkipIgnored:     1,
+		model.SkipExcluded:    1,
+		model.SkipNotIncluded: 1,
+		model.SkipBinary:      1,
+	}, stats.Skipped)
+	assert.Equal(t, 1, stats.Files)
+	assert.Equal(t, 0, stats.Dirs)
+	// 種類の判定のために a.txt と bin.dat の先頭部分を読み込む
+	assert.Equal(t, int64(len("alpha")+2), stats.BytesRead)
+	last := logger.logs[len(logger.logs)-1]
+	assert.Equal(t, "スキャンの統計情報", last.message)
+
+	// ハッシュを計算する場合は、先頭部分に続けて読み込んだ内容も数える
+	large := strings.Repeat("x", 3*DefaultBinaryCheckSize)
+	hashing := mustNewScanner(t, &mockLogger{}, ScannerOptions{ComputeHash: true})
+	_, stats, err = hashing.ScanFSWithStats(context.Background(), fstest.MapFS{"large.txt": {Data: []byte(large)}}, "memory")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(large)), stats.BytesRead)
+}
+
+func TestFileSystemScanner_ScanRules(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/guide.md":          {Data: []byte("guide")},
+		"testdata/case.json":     {Data: []byte("{}")},
+		"third_party/lib/x.go":   {Data: []byte("package lib")},
+		"web/app.min.js":         {Data: []byte("minified")},
+		"web/app.js":             {Data: []byte("source")},
+		"third_party/README.txt": {Data: []byte("excluded with the folder")},
+	}
+	scanner := mustNewScanner(t, &mockLogger{}, ScannerOptions{Rules: []rules.Rule{
+		{Path: "docs/**", Mode: "full"},
+		{Path: "testdata/**", Mode: "structure"},
+		{Path: "third_party/**", Mode: "exclude"},
+		{Path: "*.js", Mode: "headtail"},
+		{Path: "*.min.js", Mode: "skip"},
+	}})
+
+	entries, stats, err := scanner.ScanFSWithStats(context.Background(), fsys, "memory")
+	assert.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, entry := range entries {
+		got[entry.RelPath] = entry.ContentMode
+	}
+	assert.Equal(t, map[string]string{
+		"docs":               "",
+		"docs/guide.md":      "full",
+		"testdata":           "",
+		"testdata/case.json": "structure",
+		"web":                "",
+		"web/app.js":         "headtail",
+		"web/app.min.js":     "skip",
+	}, got)
+	// 除外したフォルダの配下は数えない
+	assert.Equal(t, 1, stats.Skipped[model.SkipRule])
+}
+
+func TestFileSystemScanner_ScanSizeAndDateRange(t *testing.T) {
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"old.txt":     {Data: []byte("0123456789"), ModTime: base.AddDate(0, -1, 0)},
+		"new.txt":     {Data: []byte("0123456789"), ModTime: base},
+		"future.txt":  {Data: []byte("0123456789"), ModTime: base.AddDate(0, 1, 0)},
+		"tiny.txt":    {Data: []byte("0"), ModTime: base},
+		"huge.txt":    {Data: make([]byte, 100), ModTime: base},
+		"dir/a.txt":   {Data: []byte("0123456789"), ModTime: base.AddDate(0, 0, 1)},
+		"dir/old.txt": {Data: []byte("0123456789"), ModTime: base.AddDate(-1, 0, 0)},
+	}
+	tests := []struct {
+		name        string
+		opts        ScannerOptions
+		want        []string
+		wantSkipped map[model.SkipReason]int
+	}{
+		{
+			name:        "サイズの範囲",
+			opts:        ScannerOptions{MinSize: 2, MaxSize: 50},
+			want:        []string{"dir", "dir/a.txt", "dir/old.txt", "future.txt", "new.txt", "old.txt"},
+			wantSkipped: map[model.SkipReason]int{model.SkipSize: 2},
+		},
+		{
+			name:        "更新日時の期間（下限を含み上限を含まない）",
+			opts:        ScannerOptions{ModifiedAfter: base, ModifiedBefore: base.AddDate(0, 1, 0)},
+			want:        []string{"dir", "dir/a.txt", "huge.txt", "new.txt", "tiny.txt"},
+			wantSkipped: map[model.SkipReason]int{model.SkipModTime: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, stats, err := mustNewScanner(t, &mockLogger{}, tt.opts).ScanFSWithStats(context.Background(), fsys, "memory")
+			assert.NoError(t, err)
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.RelPath)
+			}
+			sort.Strings(got)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, stats.Skipped)
+		})
+	}
+}
+
+func TestFileSystemScanner_ScanHidden(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":              {Data: []byte("a")},
+		".env":               {Data: []byte("KEY=1")},
+		".github/ci.yml":     {Data: []byte("on: push")},
+		"src/.hidden.txt":    {Data: []byte("h")},
+		"src/main.go":        {Data: []byte("package main")},
+		".git/HEAD":          {Data: []byte("ref: refs/heads/main")},
+		"src/.vscode/a.json": {Data: []byte("{}")},
+	}
+	tests := []struct {
+		name        string
+		opts        ScannerOptions
+		want        []string
+		wantSkipped map[model.SkipReason]int
+	}{
+		{
+			name:        "隠しファイルを含める（無視パターンは適用する）",
+			opts:        ScannerOptions{IncludeHidden: true},
+			want:        []string{".env", ".github", ".github/ci.yml", "a.txt", "src", "src/.hidden.txt", "src/main.go"},
+			wantSkipped: map[model.SkipReason]int{model.SkipIgnored: 2},
+		},
+		{
+			name:        "隠しファイルと隠しフォルダの配下を除外する",
+			opts:        ScannerOptions{},
+			want:        []string{"a.txt", "src", "src/main.go"},
+			wantSkipped: map[model.SkipReason]int{model.SkipIgnored: 2, model.SkipHidden: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, stats, err := mustNewScanner(t, &mockLogger{}, tt.opts).ScanFSWithStats(context.Background(), fsys, "memory")
+			assert.NoError(t, err)
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.RelPath)
+			}
+			sort.Strings(got)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, stats.Skipped)
+		})
+	}
+}
+
+func TestFileSystemScanner_ScanSymlinkPolicy(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "shared", "s.txt"), []byte("s"), 0644))
+	for link, target := range map[string]string{
+		"vendor":         "shared",
+		"link.txt":       "a.txt",
+		"loop":           ".",
+		"dangling":       "missing",
+		"shared/up":      "..",
+		"shared/sibling": "../shared",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("シンボリックリンクを作成できません: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		policy      SymlinkPolicy
+		want        []string
+		wantSkipped map[model.SkipReason]int
+	}{
+		{
+			name:        "リンクとして記録する（既定）",
+			policy:      "",
+			want:        []string{"a.txt", "dangling", "link.txt", "loop", "shared", "shared/s.txt", "shared/sibling", "shared/up", "vendor"},
+			wantSkipped: map[model.SkipReason]int{},
+		},
+		{
+			name:        "除外する",
+			policy:      SymlinkSkip,
+			want:        []string{"a.txt", "shared", "shared/s.txt"},
+			wantSkipped: map[model.SkipReason]int{model.SkipSymlink: 6},
+		},
+		{
+			name:   "たどる（親フォルダを指すリンクはたどらない）",
+			policy: SymlinkFollow,
+			want: []string{"a.txt", "dangling", "link.txt", "loop", "shared", "shared/s.txt", "shared/sibling", "shared/up",
+				"vendor", "vendor/s.txt", "vendor/sibling", "vendor/up"},
+			wantSkipped: map[model.SkipReason]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, stats, err := mustNewScanner(t, &mockLogger{}, ScannerOptions{IncludeHidden: true, SymlinkPolicy: tt.policy}).
+				ScanWithStats(context.Background(), dir)
+			assert.NoError(t, err)
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.RelPath)
+				if tt.policy == SymlinkFollow && entry.RelPath == "link.txt" {
+					assert.Equal(t, int64(5), entry.Size, "ファイルへのリンクはリンク先のサイズを記録する")
+				}
+			}
+			sort.Strings(got)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, stats.Skipped)
+		})
+	}
+}
+
+func TestFileSystemScanner_ScanSymlinkTargets(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0755))
+	for link, target := range map[string]string{"vendor": "shared", "link.txt": "a.txt", "dangling": "missing.txt"} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skipf("シンボリックリンクを作成できません: %v", err)
+		}
+	}
+
+	type linkInfo struct {
+		target    string
+		dangling  bool
+		linkToDir bool
+	}
+	tests := []struct {
+		policy SymlinkPolicy
+		want   map[string]linkInfo
+	}{
+		{
+			policy: SymlinkLink,
+			want: map[string]linkInfo{
+				"a.txt":    {},
+				"shared":   {},
+				"vendor":   {target: "shared", linkToDir: true},
+				"link.txt": {target: "a.txt"},
+				"dangling": {target: "missing.txt", dangling: true},
+			},
+		},
+		{
+			// たどったフォルダへのリンクはフォルダとして記録する
+			policy: SymlinkFollow,
+			want: map[string]linkInfo{
+				"a.txt":    {},
+				"shared":   {},
+				"vendor":   {target: "shared"},
+				"link.txt": {target: "a.txt"},
+				"dangling": {target: "missing.txt", dangling: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			entries, stats, err := mustNewScanner(t, &mockLogger{}, ScannerOptions{IncludeHidden: true, SymlinkPolicy: tt.policy}).
+				ScanWithStats(context.Background(), dir)
+			assert.NoError(t, err)
+			got := make(map[string]linkInfo)
+			for _, entry := range entries {
+				got[entry.RelPath] = linkInfo{target: entry.LinkTarget, dangling: entry.Dangling, linkToDir: entry.LinkToDir}
+				// フォルダへのリンクとリンク切れは内容を読み込まないため、読み込みエラーにならない
+				assert.NoError(t, entry.ReadErr, entry.RelPath)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, 0, stats.Errors, "リンクは読み込みのエラーとして数えない")
+		})
+	}
+}
+
+func TestFileSystemScanner_ScanHardLinks(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("gamma"), 0644))
+	if err := os.Link(filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")); err != nil {
+		t.Skipf("ハードリンクを作成できません: %v", err)
+	}
+
+	entries, err := NewScanner(&mockLogger{}, nil, false).Scan(context.Background(), dir)
+	assert.NoError(t, err)
+	got := make(map[string]string)
+	for _, entry := range entries {
+		got[entry.RelPath] = entry.HardLinkOf
+	}
+	want := map[string]string{"a.txt": "", "b.txt": "a.txt", "c.txt": ""}
+	if runtime.GOOS == "windows" {
+		// inode 番号を取得できない環境では検出しない
+		want["b.txt"] = ""
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestFileSystemScanner_ScanOwner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("所有者とグループは Unix でのみ取得します")
+	}
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0600))
+
+	entries, err := NewScanner(&mockLogger{}, nil, false).Scan(context.Background(), dir)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		want := strconv.Itoa(os.Getuid())
+		if u, err := user.LookupId(want); err == nil {
+			want = u.Username
+		}
+		assert.Equal(t, want, entries[0].Owner)
+		assert.NotEmpty(t, entries[0].Group)
+		assert.Equal(t, fs.FileMode(0600), entries[0].Permissions.Perm())
+	}
+}
+
+// deniedFS は denied に含まれるパスの読み込みを権限エラーにする fs.FS です
+type deniedFS struct {
+	fstest.MapFS
+	denied map[string]bool
+}
+
+func (f deniedFS) Open(name string) (fs.File, error) {
+	if f.denied[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MapFS.Open(name)
+}
+
+func (f deniedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if f.denied[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestFileSystemScanner_ScanInaccessible(t *testing.T) {
+	fsys := deniedFS{
+		MapFS: fstest.MapFS{
+			"a.txt":          {Data: []byte("alpha")},
+			"private/s.txt":  {Data: []byte("secret")},
+			"docs/b.txt":     {Data: []byte("beta")},
+			"docs/owner.txt": {Data: []byte("owner only")},
+		},
+		denied: map[string]bool{"private": true, "docs/owner.txt": true},
+	}
+	entries, stats, err := NewScanner(&mockLogger{}, nil, false).ScanFSWithStats(context.Background(), fsys, "/root")
+	assert.NoError(t, err)
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.RelPath)
+	}
+	assert.Equal(t, []string{"a.txt", "docs", "docs/b.txt", "docs/owner.txt", "private"}, got)
+	assert.Equal(t, []model.InaccessiblePath{
+		{RelPath: "docs/owner.txt"},
+		{RelPath: "private", IsDir: true},
+	}, stats.Inaccessible)
+}
+
+func TestFileSystemScanner_ScanInaccessiblePolicy(t *testing.T) {
+	fsys := deniedFS{
+		MapFS: fstest.MapFS{
+			"a.txt":          {Data: []byte("alpha")},
+			"private/s.txt":  {Data: []byte("secret")},
+			"docs/owner.txt": {Data: []byte("owner only")},
+		},
+		denied: map[string]bool{"private": true, "docs/owner.txt": true},
+	}
+
+	tests := []struct {
+		name             string
+		policy           AccessPolicy
+		wantErr          bool
+		wantInaccessible []model.InaccessiblePath
+	}{
+		{
+			name:   "既定ではディレクトリとファイルを記録する",
+			policy: "",
+			wantInaccessible: []model.InaccessiblePath{
+				{RelPath: "docs/owner.txt"},
+				{RelPath: "private", IsDir: true},
+			},
+		},
+		{
+			name:             "skip ではディレクトリを記録しない",
+			policy:           AccessSkip,
+			wantInaccessible: []model.InaccessiblePath{{RelPath: "docs/owner.txt"}},
+		},
+		{
+			name:    "fatal ではスキャンを中止する",
+			policy:  AccessFatal,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := mustNewScanner(t, &mockLogger{}, ScannerOptions{IncludeHidden: true, AccessPolicy: tt.policy})
+			_, stats, err := scanner.ScanFSWithStats(context.Background(), fsys, "/root")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrPermission)
+				assert.ErrorContains(t, err, "権限がないためディレクトリを読み込めません")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantInaccessible, stats.Inaccessible)
+		})
+	}
+
+	_, err := ParseAccessPolicy("abort")
+	assert.ErrorContains(t, err, "skip, report, fatal")
+}
@@ -0,0 +1,109 @@
+package filesystem
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// DefaultEstimateBudget は規模の見積もりに使う時間の上限です
+const DefaultEstimateBudget = 3 * time.Second
+
+// ScopeEstimate は調査対象フォルダの規模の見積もりです
+type ScopeEstimate struct {
+	// Files は見積もり中に見つかったファイル数です
+	Files int
+	// Dirs は見積もり中に見つかったディレクトリ数です
+	Dirs int
+	// Bytes は見積もり中に見つかったファイルの合計サイズです
+	Bytes int64
+	// Complete は時間内にすべてを走査できたかどうかを示します。false の場合、実際の規模は見積もりより大きくなります
+	Complete bool
+}
+
+// BroadScopeReason は、path がドライブのルートまたはユーザーのホームディレクトリである場合に、その種類を返します。
+// いずれにも該当しない場合は空文字列を返します
+func BroadScopeReason(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	if filepath.Dir(absPath) == absPath {
+		return "ドライブのルート"
+	}
+	if home, err := os.UserHomeDir(); err == nil && samePath(absPath, home) {
+		return "ホームディレクトリ"
+	}
+	return ""
+}
+
+// samePath は 2 つのパスが同じ場所を指すかどうかを返します。Windows では大文字・小文字を区別しません
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// EstimateScope は、無視パターンを考慮して rootDir 配下を budget の時間だけ走査し、規模を見積もります。
+// 時間内に走査が終わらなかった場合は、それまでの集計を Complete を false にして返します。
+// ctx がキャンセルされた場合は ctx のエラーを返します
+func (s *Scanner) EstimateScope(ctx context.Context, rootDir string, budget time.Duration) (ScopeEstimate, error) {
+	fsys, absRootDir, err := OpenDir(rootDir)
+	if err != nil {
+		return ScopeEstimate{}, err
+	}
+	return s.EstimateScopeFS(ctx, fsys, absRootDir, budget)
+}
+
+// EstimateScopeFS は EstimateScope と同様に、fsys のルートから budget の時間だけ走査して規模を見積もります。
+// root はエラーに表示するルートのパスです
+func (s *Scanner) EstimateScopeFS(ctx context.Context, fsys fs.FS, root string, budget time.Duration) (ScopeEstimate, error) {
+	var estimate ScopeEstimate
+	deadline := time.Now().Add(budget)
+	truncated := false
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			truncated = true
+			return fs.SkipAll
+		}
+		// 見積もりでは読み取れないパスを単に数えずに進める
+		if walkErr != nil || path == "." {
+			return nil
+		}
+		if ignored, _ := s.matchesIgnorePattern(path, d); ignored || s.isHiddenEntry(d) || (s.symlinkPolicy == SymlinkSkip && isLink(d)) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			estimate.Dirs++
+		} else {
+			estimate.Files++
+			if info, err := d.Info(); err == nil {
+				estimate.Bytes += info.Size()
+			}
+		}
+		if s.progress != nil {
+			s.progress(ScanProgress{CurrentPath: path, Files: estimate.Files, Dirs: estimate.Dirs})
+		}
+		return nil
+	})
+	if err != nil {
+		return estimate, apperrors.Wrap("フォルダの規模の見積もりに失敗", root, err)
+	}
+	estimate.Complete = !truncated
+	return estimate, nil
+}
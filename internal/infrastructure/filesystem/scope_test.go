@@ -0,0 +1,92 @@
+package filesystem
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadScopeReason(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("ホームディレクトリを取得できません")
+	}
+	root := filepath.VolumeName(home) + string(filepath.Separator)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"ドライブのルート", root, "ドライブのルート"},
+		{"ホームディレクトリ", home, "ホームディレクトリ"},
+		{"通常のフォルダ", t.TempDir(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BroadScopeReason(tt.path))
+		})
+	}
+}
+
+func TestScanner_EstimateScope(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("12345"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("123"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "config"), []byte("ignored"), 0644))
+
+	scanner := NewScanner(&mockLogger{}, nil, false)
+	estimate, err := scanner.EstimateScope(context.Background(), dir, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, ScopeEstimate{Files: 2, Dirs: 1, Bytes: 8, Complete: true}, estimate)
+
+	// 時間切れの場合は Complete が false になる
+	estimate, err = scanner.EstimateScope(context.Background(), dir, 0)
+	assert.NoError(t, err)
+	assert.False(t, estimate.Complete)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = scanner.EstimateScope(ctx, dir, time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestScanner_EstimateScopeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":       {Data: []byte("12345")},
+		"sub/b.txt":   {Data: []byte("123")},
+		".git/config": {Data: []byte("ignored")},
+	}
+
+	estimate, err := NewScanner(&mockLogger{}, nil, false).EstimateScopeFS(context.Background(), fsys, "memory", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, ScopeEstimate{Files: 2, Dirs: 1, Bytes: 8, Complete: true}, estimate)
+}
+
+func TestOpenDir(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "file.txt")
+	assert.NoError(t, os.WriteFile(filePath, []byte("x"), 0644))
+
+	fsys, absDir, err := OpenDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, absDir)
+	data, err := fs.ReadFile(fsys, "file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", string(data))
+
+	_, _, err = OpenDir(filePath)
+	assert.ErrorIs(t, err, apperrors.ErrNotDirectory)
+	_, _, err = OpenDir(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
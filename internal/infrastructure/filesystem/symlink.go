@@ -0,0 +1,93 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// SymlinkPolicy はスキャン時のシンボリックリンクの扱いです
+type SymlinkPolicy string
+
+const (
+	// SymlinkLink はリンク自体をエントリとして記録します（ディレクトリへのリンクはたどりません）
+	SymlinkLink SymlinkPolicy = "link"
+	// SymlinkSkip はリンクを結果から除外します
+	SymlinkSkip SymlinkPolicy = "skip"
+	// SymlinkFollow はリンク先をたどり、ディレクトリへのリンクは配下をリンクのパスの下に記録します。
+	// 親フォルダの情報を記録し、親フォルダと同じファイル（os.SameFile）を指す（循環する）リンクはたどらずにリンクとして記録します。
+	// Windows のジャンクションもシンボリックリンクと同様に扱います
+	SymlinkFollow SymlinkPolicy = "follow"
+)
+
+// MaxSymlinkDepth はリンクをたどる場合に、入れ子になったディレクトリへのリンクをたどる深さの上限です。
+// ディレクトリを識別できない環境でも、循環するリンクで走査が終わらなくならないようにします
+const MaxSymlinkDepth = 32
+
+// ParseSymlinkPolicy は文字列からシンボリックリンクの扱いを解析します。空文字列は SymlinkLink として扱います
+func ParseSymlinkPolicy(s string) (SymlinkPolicy, error) {
+	switch SymlinkPolicy(s) {
+	case "", SymlinkLink:
+		return SymlinkLink, nil
+	case SymlinkSkip, SymlinkFollow:
+		return SymlinkPolicy(s), nil
+	}
+	return "", fmt.Errorf("未対応のシンボリックリンクの扱いです: %s（skip, link, follow のいずれかを指定してください）", s)
+}
+
+// fileKey はファイルを識別するデバイス番号と inode 番号です
+type fileKey struct {
+	dev, ino uint64
+}
+
+// isLink はエントリがシンボリックリンク、または Windows のジャンクションかどうかを返します
+func isLink(d fs.DirEntry) bool {
+	return d.Type()&fs.ModeSymlink != 0 || isJunction(d)
+}
+
+// markVisited はリンクをたどる場合に、ディレクトリの相対パスとその情報を記録します
+func (s *Scanner) markVisited(dirInfos map[string]fs.FileInfo, fsPath string, d fs.DirEntry) {
+	if s.symlinkPolicy != SymlinkFollow {
+		return
+	}
+	if info, err := d.Info(); err == nil {
+		dirInfos[fsPath] = info
+	}
+}
+
+// isCycle は fsPath のリンクの先のディレクトリ target が、fsPath の親フォルダ（ルートを含む）のいずれかと同じかどうかを返します。
+// os.SameFile で比較するため、Unix ではデバイス番号と inode 番号、Windows ではボリュームとファイル ID で識別します。
+// アーカイブなど OS のファイル以外で識別できない場合は false を返します
+func isCycle(dirInfos map[string]fs.FileInfo, fsPath string, target fs.FileInfo) bool {
+	for dir := path.Dir(fsPath); ; dir = path.Dir(dir) {
+		if info, ok := dirInfos[dir]; ok && os.SameFile(info, target) {
+			return true
+		}
+		if dir == "." {
+			return false
+		}
+	}
+}
+
+// readLinkFS はシンボリックリンクのリンク先を読み取れる fs.FS です
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+}
+
+// readLink は fsys の fsPath のシンボリックリンクのリンク先を返します。
+// fsys がリンク先の読み取りに対応していない場合は、OS のパス osPath のリンクとして読み取ります。取得できない場合は空文字列を返します
+func readLink(fsys fs.FS, fsPath, osPath string) string {
+	var target string
+	var err error
+	if rfs, ok := fsys.(readLinkFS); ok {
+		target, err = rfs.ReadLink(fsPath)
+	} else {
+		target, err = os.Readlink(osPath)
+	}
+	if err != nil {
+		return ""
+	}
+	return target
+}
@@ -0,0 +1,142 @@
+// Package gist は GitHub Gist へのレポートエクスポート機能を提供します
+package gist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultAPIURL は GitHub REST API のベースURLです
+	DefaultAPIURL = "https://api.github.com"
+	// DefaultChunkSize は 1 つの Gist ファイルに格納する最大バイト数です。
+	// GitHub の Web 表示はおよそ 1MB を超えるファイルを切り詰めるため、余裕を持たせています。
+	DefaultChunkSize = 512 * 1024
+	// TokenEnvVar はアクセストークンを読み込む環境変数名です
+	TokenEnvVar = "GITHUB_TOKEN"
+)
+
+// Exporter はレポートをチャンクに分割し、シークレット Gist として登録します
+type Exporter struct {
+	client    *http.Client
+	apiURL    string
+	token     string
+	chunkSize int
+}
+
+// NewExporter は新しい Exporter インスタンスを作成します
+func NewExporter(token string) *Exporter {
+	return &Exporter{
+		client:    &http.Client{Timeout: 60 * time.Second},
+		apiURL:    DefaultAPIURL,
+		token:     token,
+		chunkSize: DefaultChunkSize,
+	}
+}
+
+// gistFile は Gist API に送信するファイル内容です
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+// createRequest は Gist 作成 API のリクエストボディです
+type createRequest struct {
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+// createResponse は Gist 作成 API のレスポンスのうち必要な部分です
+type createResponse struct {
+	HTMLURL string `json:"html_url"`
+}
+
+// Export はレポート内容をチャンクに分割してシークレット Gist を作成し、その URL を返します。
+// baseName はチャンクファイル名の接頭辞として使用されます（例: output_20240101_120000）。
+func (e *Exporter) Export(ctx context.Context, baseName, description string, content []byte) (string, error) {
+	if e.token == "" {
+		return "", fmt.Errorf("GitHubアクセストークンが設定されていません（環境変数 %s）", TokenEnvVar)
+	}
+
+	chunks := SplitChunks(content, e.chunkSize)
+	if len(chunks) == 0 {
+		return "", fmt.Errorf("エクスポートするレポート内容が空です")
+	}
+
+	files := make(map[string]gistFile, len(chunks))
+	for i, chunk := range chunks {
+		name := fmt.Sprintf("%s_part%03d.txt", baseName, i+1)
+		files[name] = gistFile{Content: string(chunk)}
+	}
+
+	body, err := json.Marshal(createRequest{
+		Description: description,
+		Public:      false,
+		Files:       files,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Gistリクエストのエンコードに失敗しました: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.apiURL, "/")+"/gists", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("Gistリクエストの作成に失敗しました: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+e.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Gistの作成に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("Gistの作成に失敗しました（HTTP %d）: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var created createResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("Gistレスポンスの解析に失敗しました: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// SplitChunks は内容を最大 size バイトのチャンクに分割します。
+// 可能な限り改行位置で区切り、1 行が size を超える場合のみ行の途中で分割します。
+func SplitChunks(content []byte, size int) [][]byte {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	var chunks [][]byte
+	for len(content) > 0 {
+		if len(content) <= size {
+			chunks = append(chunks, content)
+			break
+		}
+		cut := bytes.LastIndexByte(content[:size], '\n') + 1
+		if cut == 0 {
+			// UTF-8 の途中で分割しないよう、文字の先頭まで戻る
+			cut = size
+			for cut > 0 && !utf8.RuneStart(content[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				cut = size
+			}
+		}
+		chunks = append(chunks, content[:cut])
+		content = content[cut:]
+	}
+	return chunks
+}
@@ -0,0 +1,127 @@
+package gist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		size    int
+		want    []string
+	}{
+		{
+			name:    "サイズ以内ならそのまま",
+			content: "a\nb\n",
+			size:    10,
+			want:    []string{"a\nb\n"},
+		},
+		{
+			name:    "改行位置で分割",
+			content: "aaa\nbbb\nccc\n",
+			size:    9,
+			want:    []string{"aaa\nbbb\n", "ccc\n"},
+		},
+		{
+			name:    "長い行は途中で分割",
+			content: "abcdefgh",
+			size:    3,
+			want:    []string{"abc", "def", "gh"},
+		},
+		{
+			name:    "マルチバイト文字の途中では分割しない",
+			content: "あいう",
+			size:    4,
+			want:    []string{"あ", "い", "う"},
+		},
+		{
+			name:    "空の内容",
+			content: "",
+			size:    10,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitChunks([]byte(tt.content), tt.size)
+			if len(got) != len(tt.want) {
+				t.Fatalf("チャンク数が不正: got %d, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if string(got[i]) != tt.want[i] {
+					t.Errorf("チャンク %d が不正: got %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExporter_Export(t *testing.T) {
+	var received createRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gists" || r.Method != http.MethodPost {
+			t.Errorf("リクエストが不正: %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorizationヘッダーが不正: %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("リクエストボディの解析に失敗: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"html_url":"https://gist.github.com/abc"}`))
+	}))
+	defer server.Close()
+
+	exporter := NewExporter("secret")
+	exporter.apiURL = server.URL
+	exporter.chunkSize = 8
+
+	url, err := exporter.Export(context.Background(), "output", "FolderScope report", []byte("line1\nline2\n"))
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if url != "https://gist.github.com/abc" {
+		t.Errorf("URLが不正: got %v", url)
+	}
+	if received.Public {
+		t.Errorf("シークレットGistとして作成されていません")
+	}
+	if len(received.Files) != 2 {
+		t.Fatalf("ファイル数が不正: got %d, want 2", len(received.Files))
+	}
+	if received.Files["output_part001.txt"].Content != "line1\n" {
+		t.Errorf("1つ目のチャンクが不正: %q", received.Files["output_part001.txt"].Content)
+	}
+}
+
+func TestExporter_ExportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Bad credentials"}`))
+	}))
+	defer server.Close()
+
+	t.Run("トークン未設定", func(t *testing.T) {
+		exporter := NewExporter("")
+		if _, err := exporter.Export(context.Background(), "output", "", []byte("x")); err == nil {
+			t.Error("エラーが返されるべきです")
+		}
+	})
+
+	t.Run("APIエラー", func(t *testing.T) {
+		exporter := NewExporter("bad")
+		exporter.apiURL = server.URL
+		_, err := exporter.Export(context.Background(), "output", "", []byte("x"))
+		if err == nil || !strings.Contains(err.Error(), "401") {
+			t.Errorf("HTTPステータスを含むエラーが返されるべきです: %v", err)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package gitinfo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// bundleHeader は git バンドルのヘッダーに記録された前提のコミットと参照です
+type bundleHeader struct {
+	// prerequisites はバンドルを取り込むために必要なコミットです。すべての履歴を含むバンドルでは空です
+	prerequisites []string
+	// heads はバンドルに含まれる参照が指すコミットです（ヘッダーの順）
+	heads []string
+}
+
+// readBundleHeader は git バンドル（v2・v3 形式）のヘッダーを読み込みます
+func readBundleHeader(path string) (bundleHeader, error) {
+	var header bundleHeader
+	f, err := os.Open(path)
+	if err != nil {
+		return header, apperrors.Wrap("バンドルを開けませんでした", path, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	signature, err := r.ReadString('\n')
+	if err != nil || (signature != "# v2 git bundle\n" && signature != "# v3 git bundle\n") {
+		return header, fmt.Errorf("git バンドルではありません: %s", path)
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return header, fmt.Errorf("バンドルのヘッダーが途中で終わっています: %s", path)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if len(header.heads) == 0 {
+				return header, fmt.Errorf("バンドルに参照が含まれていません: %s", path)
+			}
+			return header, nil
+		case strings.HasPrefix(line, "@"):
+			// v3 形式の機能の指定（object-format など）は読み飛ばす
+		case strings.HasPrefix(line, "-"):
+			oid, _, _ := strings.Cut(line[1:], " ")
+			header.prerequisites = append(header.prerequisites, oid)
+		default:
+			oid, _, _ := strings.Cut(line, " ")
+			header.heads = append(header.heads, oid)
+		}
+	}
+}
+
+// BundleDiff は git バンドル bundlePath に含まれる変更を、dir の git リポジトリのオブジェクトを参照して unified diff 形式で返します。
+// 比較元はバンドルの前提のコミット（すべての履歴を含むバンドルでは dir の HEAD）、比較先はバンドルの最初の参照が指すコミットで、
+// パスは dir からの相対パスです。バンドルのオブジェクトは一時的なリポジトリに取り込むため、dir のリポジトリは変更しません
+func BundleDiff(ctx context.Context, dir, bundlePath string) ([]byte, error) {
+	header, err := readBundleHeader(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	absBundle, err := filepath.Abs(bundlePath)
+	if err != nil {
+		return nil, apperrors.Wrap("バンドルのパスの解決に失敗しました", bundlePath, err)
+	}
+	commonDir, err := gitOutput(ctx, dir, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(dir, commonDir)
+	}
+	objects, err := filepath.Abs(filepath.Join(commonDir, "objects"))
+	if err != nil {
+		return nil, apperrors.Wrap("リポジトリのパスの解決に失敗しました", dir, err)
+	}
+	prefix, err := gitOutput(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	base := ""
+	if len(header.prerequisites) > 0 {
+		base = header.prerequisites[0]
+	} else if base, err = gitOutput(ctx, dir, "rev-parse", "--verify", "HEAD"); err != nil {
+		return nil, err
+	}
+
+	// dir のオブジェクトを alternates で参照する一時的なリポジトリにバンドルを取り込む
+	tmp, err := os.MkdirTemp("", "folderscope-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("一時フォルダの作成に失敗しました: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	if _, err := runGit(ctx, tmp, "init", "-q", "--bare"); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "objects", "info", "alternates"), []byte(objects+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("一時的なリポジトリの作成に失敗しました: %w", err)
+	}
+	if _, err := runGit(ctx, tmp, "bundle", "unbundle", absBundle); err != nil {
+		return nil, err
+	}
+
+	args := []string{"--no-color", "--no-ext-diff"}
+	if prefix != "" {
+		args = append(args, "--relative="+prefix)
+	}
+	return runGit(ctx, tmp, "diff", append(args, base, header.heads[0])...)
+}
+
+// gitOutput は git の出力を前後の空白を取り除いて返します
+func gitOutput(ctx context.Context, dir, subcommand string, args ...string) (string, error) {
+	out, err := runGit(ctx, dir, subcommand, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
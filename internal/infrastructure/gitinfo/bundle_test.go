@@ -0,0 +1,71 @@
+package gitinfo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleDiff(t *testing.T) {
+	dir := initRepo(t)
+	bundles := t.TempDir()
+	createBundle := func(name string, revs ...string) string {
+		path := filepath.Join(bundles, name)
+		args := append([]string{"-C", dir, "bundle", "create", path}, revs...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git bundle create: %v\n%s", err, out)
+		}
+		return path
+	}
+	incremental := createBundle("incremental.bundle", "HEAD~1..HEAD")
+	full := createBundle("full.bundle", "HEAD")
+	// 受け取る側のリポジトリは、バンドルの前提のコミットまでの状態とする
+	if out, err := exec.Command("git", "-C", dir, "reset", "-q", "--hard", "HEAD~1").CombinedOutput(); err != nil {
+		t.Fatalf("git reset: %v\n%s", err, out)
+	}
+	notBundle := filepath.Join(bundles, "not.bundle")
+	if err := os.WriteFile(notBundle, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		bundle   string
+		want     []string
+		unwanted []string
+		wantErr  string
+	}{
+		{name: "前提のコミットからの変更", dir: dir, bundle: incremental, want: []string{"+++ b/sub/a.txt", "-2", "+3"}, unwanted: []string{"b.txt"}},
+		{name: "すべての履歴を含むバンドルは HEAD と比較する", dir: dir, bundle: full, want: []string{"+++ b/sub/a.txt", "-2", "+3"}},
+		{name: "サブディレクトリからの相対パス", dir: filepath.Join(dir, "sub"), bundle: incremental, want: []string{"+++ b/a.txt"}},
+		{name: "バンドルではないファイル", dir: dir, bundle: notBundle, wantErr: "git バンドルではありません"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := BundleDiff(context.Background(), tt.dir, tt.bundle)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("BundleDiff() error = %v, want %q を含むエラー", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BundleDiff() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("差分に %q が含まれていません:\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(string(out), unwanted) {
+					t.Errorf("差分に %q が含まれています:\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package gitinfo は git の履歴からファイルの情報を取得する機能を提供します
+package gitinfo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitPrefix は git log の出力でコミット行を識別するための接頭辞です
+const commitPrefix = "commit "
+
+// DefaultAuthorLimit はファイルごとに表示する主な作成者の人数です
+const DefaultAuthorLimit = 3
+
+// Author はファイルに変更をコミットした作成者です
+type Author struct {
+	Name string
+	// Commits はそのファイルを変更したコミット数です
+	Commits int
+}
+
+// LastCommitTimes は dir 配下のファイルごとに、最後にコミットされた日時を返します。
+// キーは dir からの相対パス（'/' 区切り）です。一度もコミットされていないファイルは含まれません。
+// dir が git リポジトリの中にない場合や git が利用できない場合はエラーを返します
+func LastCommitTimes(ctx context.Context, dir string) (map[string]time.Time, error) {
+	out, err := runLog(ctx, dir, "%ct")
+	if err != nil {
+		return nil, err
+	}
+	times := make(map[string]time.Time)
+	var current time.Time
+	err = parseLog(bytes.NewReader(out), func(header string) error {
+		sec, err := strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			return fmt.Errorf("コミット日時の解析に失敗しました: %q: %w", header, err)
+		}
+		current = time.Unix(sec, 0)
+		return nil
+	}, func(path string) {
+		// git log は新しいコミットから順に出力するため、最初に現れた日時を最終コミット日時とする
+		if _, ok := times[path]; !ok {
+			times[path] = current
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return times, nil
+}
+
+// FileAuthors は dir 配下のファイルごとに、変更をコミットした作成者をコミット数の多い順に返します。
+// 作成者名は .mailmap による名寄せ後の名前です。キーは dir からの相対パス（'/' 区切り）です
+func FileAuthors(ctx context.Context, dir string) (map[string][]Author, error) {
+	out, err := runLog(ctx, dir, "%aN")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]map[string]int)
+	var current string
+	err = parseLog(bytes.NewReader(out), func(header string) error {
+		current = header
+		return nil
+	}, func(path string) {
+		if counts[path] == nil {
+			counts[path] = make(map[string]int)
+		}
+		counts[path][current]++
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make(map[string][]Author, len(counts))
+	for path, byName := range counts {
+		list := make([]Author, 0, len(byName))
+		for name, n := range byName {
+			list = append(list, Author{Name: name, Commits: n})
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Commits != list[j].Commits {
+				return list[i].Commits > list[j].Commits
+			}
+			return list[i].Name < list[j].Name
+		})
+		authors[path] = list
+	}
+	return authors, nil
+}
+
+// FormatAuthors は主な作成者を "名前 (コミット数)" の形式で、最大 limit 人まで連結します
+func FormatAuthors(authors []Author, limit int) string {
+	if limit > 0 && len(authors) > limit {
+		authors = authors[:limit]
+	}
+	parts := make([]string, 0, len(authors))
+	for _, a := range authors {
+		parts = append(parts, fmt.Sprintf("%s (%d)", a.Name, a.Commits))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runLog は dir 配下を対象に、各コミットを "commit <format>" の行と変更されたファイル名の行で出力する git log を実行します
+func runLog(ctx context.Context, dir, format string) ([]byte, error) {
+	return runGit(ctx, dir, "log", "--format="+commitPrefix+format, "--name-only", "--relative", "--", ".")
+}
+
+// runGit は dir をカレントディレクトリとして git のサブコマンドを実行し、標準出力を返します。
+// 非 ASCII のファイル名をエスケープせずに出力させるため、core.quotePath を無効にします
+func runGit(ctx context.Context, dir, subcommand string, args ...string) ([]byte, error) {
+	cmdArgs := append([]string{"-c", "core.quotePath=false", "-C", dir, subcommand}, args...)
+	cmd := exec.CommandContext(ctx, "git", cmdArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s の実行に失敗しました: %s: %w", subcommand, msg, err)
+		}
+		return nil, fmt.Errorf("git %s の実行に失敗しました: %w", subcommand, err)
+	}
+	return out, nil
+}
+
+// parseLog は runLog の出力を解析し、コミット行ごとに onCommit を、ファイル名の行ごとに onFile を呼び出します
+func parseLog(r io.Reader, onCommit func(header string) error, onFile func(path string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, commitPrefix) {
+			if err := onCommit(strings.TrimPrefix(line, commitPrefix)); err != nil {
+				return err
+			}
+			continue
+		}
+		if line != "" {
+			onFile(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("git log の出力の読み込みに失敗しました: %w", err)
+	}
+	return nil
+}
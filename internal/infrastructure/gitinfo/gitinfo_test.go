@@ -0,0 +1,102 @@
+package gitinfo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// initRepo はテスト用の git リポジトリを作成し、指定された作成者・日時でファイルをコミットします
+func initRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git が見つかりません")
+	}
+	dir := t.TempDir()
+	run := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(author, date string, files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("ディレクトリの作成に失敗: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("ファイルの作成に失敗: %v", err)
+			}
+		}
+		run(nil, "add", ".")
+		run([]string{
+			"GIT_AUTHOR_NAME=" + author, "GIT_AUTHOR_EMAIL=" + author + "@example.com",
+			"GIT_COMMITTER_NAME=" + author, "GIT_COMMITTER_EMAIL=" + author + "@example.com",
+			"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date,
+		}, "commit", "-q", "-m", "update")
+	}
+
+	run(nil, "init", "-q")
+	commit("alice", "2024-01-01T00:00:00Z", map[string]string{"sub/a.txt": "1", "b.txt": "1"})
+	commit("bob", "2024-02-01T00:00:00Z", map[string]string{"sub/a.txt": "2"})
+	commit("bob", "2024-03-01T00:00:00Z", map[string]string{"sub/a.txt": "3"})
+	return dir
+}
+
+func TestLastCommitTimes(t *testing.T) {
+	dir := initRepo(t)
+
+	times, err := LastCommitTimes(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("LastCommitTimes() error = %v", err)
+	}
+	want := map[string]time.Time{
+		"sub/a.txt": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"b.txt":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for path, tm := range want {
+		if !times[path].Equal(tm) {
+			t.Errorf("%s: got %v, want %v", path, times[path], tm)
+		}
+	}
+
+	// サブフォルダを指定した場合はそのフォルダからの相対パスになる
+	times, err = LastCommitTimes(context.Background(), filepath.Join(dir, "sub"))
+	if err != nil {
+		t.Fatalf("LastCommitTimes() error = %v", err)
+	}
+	if _, ok := times["a.txt"]; !ok || len(times) != 1 {
+		t.Errorf("サブフォルダの結果が不正: %v", times)
+	}
+}
+
+func TestFileAuthors(t *testing.T) {
+	dir := initRepo(t)
+
+	authors, err := FileAuthors(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("FileAuthors() error = %v", err)
+	}
+	if got := FormatAuthors(authors["sub/a.txt"], DefaultAuthorLimit); got != "bob (2), alice (1)" {
+		t.Errorf("sub/a.txt の作成者 = %q", got)
+	}
+	if got := FormatAuthors(authors["sub/a.txt"], 1); got != "bob (2)" {
+		t.Errorf("人数を制限した作成者 = %q", got)
+	}
+	if got := FormatAuthors(authors["b.txt"], DefaultAuthorLimit); got != "alice (1)" {
+		t.Errorf("b.txt の作成者 = %q", got)
+	}
+}
+
+func TestLastCommitTimes_NotRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git が見つかりません")
+	}
+	if _, err := LastCommitTimes(context.Background(), t.TempDir()); err == nil {
+		t.Error("git リポジトリ以外ではエラーを返すべきです")
+	}
+}
@@ -0,0 +1,75 @@
+package gitinfo
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// ChangedHunks は dir 配下のファイルのうち、作業ツリーの内容が ref と異なるファイルについて、
+// git diff の変更箇所（"@@" で始まるハンク）を返します。キーは dir からの相対パス（'/' 区切り）です。
+// バイナリファイルやモードのみの変更など、ハンクがないファイルの値は空文字列です。
+// 削除されたファイルと、git の管理下にないファイルは含まれません
+func ChangedHunks(ctx context.Context, dir, ref string) (map[string]string, error) {
+	out, err := runGit(ctx, dir, "diff", "--no-color", "--no-ext-diff", "--relative", ref, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return parseDiff(string(out)), nil
+}
+
+// parseDiff は git diff の出力をファイルごとに分割し、変更後のパスとハンクの対応を返します
+func parseDiff(out string) map[string]string {
+	hunks := make(map[string]string)
+	var path string
+	var body strings.Builder
+	inHunks := false
+	flush := func() {
+		if path != "" {
+			hunks[path] = body.String()
+		}
+		path = ""
+		body.Reset()
+		inHunks = false
+	}
+
+	for _, line := range strings.SplitAfter(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+		case inHunks:
+			body.WriteString(line)
+		case strings.HasPrefix(line, "+++ "):
+			// 削除されたファイルは "+++ /dev/null" となり、パスは記録しない
+			if name := diffPath(strings.TrimPrefix(line, "+++ ")); strings.HasPrefix(name, "b/") {
+				path = strings.TrimPrefix(name, "b/")
+			}
+		case strings.HasPrefix(line, "Binary files "):
+			// バイナリファイルはハンクがないため、変更されたことのみを記録する
+			if i := strings.LastIndex(line, " and "); i >= 0 {
+				name := diffPath(strings.TrimSuffix(strings.TrimPrefix(line[i:], " and "), " differ\n"))
+				if strings.HasPrefix(name, "b/") {
+					path = strings.TrimPrefix(name, "b/")
+				}
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHunks = true
+			body.WriteString(line)
+		}
+	}
+	flush()
+	return hunks
+}
+
+// diffPath は git diff のファイル名の行からパスを取り出します。
+// 特殊な文字を含むパスは引用符で囲まれてエスケープされ、空白を含むパスの後ろにはタブが付加されます
+func diffPath(s string) string {
+	s = strings.TrimRight(s, "\n")
+	s = strings.TrimSuffix(s, "\t")
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	return s
+}
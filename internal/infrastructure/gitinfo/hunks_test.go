@@ -0,0 +1,98 @@
+package gitinfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChangedHunks(t *testing.T) {
+	dir := initRepo(t)
+	// 作業ツリーのみの変更も対象になる
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("ファイルの更新に失敗: %v", err)
+	}
+
+	hunks, err := ChangedHunks(context.Background(), dir, "HEAD~1")
+	if err != nil {
+		t.Fatalf("ChangedHunks() error = %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("変更されたファイル数 = %d, want 2: %v", len(hunks), hunks)
+	}
+	if got := hunks["sub/a.txt"]; !strings.HasPrefix(got, "@@") || !strings.Contains(got, "-2") || !strings.Contains(got, "+3") {
+		t.Errorf("sub/a.txt のハンク = %q", got)
+	}
+	if got := hunks["b.txt"]; !strings.Contains(got, "+changed") {
+		t.Errorf("b.txt のハンク = %q", got)
+	}
+
+	// サブディレクトリを対象にした場合は、そのディレクトリからの相対パスになる
+	sub, err := ChangedHunks(context.Background(), filepath.Join(dir, "sub"), "HEAD~1")
+	if err != nil {
+		t.Fatalf("ChangedHunks() error = %v", err)
+	}
+	if _, ok := sub["a.txt"]; !ok || len(sub) != 1 {
+		t.Errorf("サブディレクトリの変更 = %v", sub)
+	}
+}
+
+func TestChangedHunks_UnknownRef(t *testing.T) {
+	dir := initRepo(t)
+	if _, err := ChangedHunks(context.Background(), dir, "no-such-ref"); err == nil {
+		t.Error("存在しない参照でエラーが返されませんでした")
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	out := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+ package main
+-var a = 1
++var a = 2
+diff --git a/logo.png b/logo.png
+index 3333333..4444444 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-old
+diff --git "a/tab\tname.txt" "b/tab\tname.txt"
+--- "a/tab\tname.txt"
++++ "b/tab\tname.txt"
+@@ -1 +1 @@
+-x
++y
+`
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantHas bool
+	}{
+		{name: "テキストファイル", path: "main.go", want: "@@ -1,2 +1,2 @@\n package main\n-var a = 1\n+var a = 2\n", wantHas: true},
+		{name: "バイナリファイル", path: "logo.png", want: "", wantHas: true},
+		{name: "削除されたファイル", path: "old.txt", wantHas: false},
+		{name: "エスケープされたパス", path: "tab\tname.txt", want: "@@ -1 +1 @@\n-x\n+y\n", wantHas: true},
+	}
+
+	hunks := parseDiff(out)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := hunks[tt.path]
+			if ok != tt.wantHas {
+				t.Fatalf("%q の有無 = %v, want %v", tt.path, ok, tt.wantHas)
+			}
+			if got != tt.want {
+				t.Errorf("ハンク = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package gitinfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RefArchive は dir 配下のファイルを、作業ツリーではなく git のオブジェクトから ref（タグ・ブランチ・コミットなど）の時点の内容で読み込み、
+// tar 形式（非圧縮）で返します。tar 内のパスは dir からの相対パス（'/' 区切り）で、更新日時は ref のコミット日時です。
+// 作業ツリーの変更や git の管理下にないファイルは含まれません
+func RefArchive(ctx context.Context, dir, ref string) ([]byte, error) {
+	// git のオプションとして解釈されないよう、'-' で始まる参照は受け付けない
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("git の参照 '%s' が不正です", ref)
+	}
+	return runGit(ctx, dir, "archive", "--format=tar", ref, ".")
+}
@@ -0,0 +1,77 @@
+package gitinfo
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRefArchive(t *testing.T) {
+	dir := initRepo(t)
+	// 作業ツリーの変更と git の管理下にないファイルは含まれない
+	if err := os.WriteFile(filepath.Join(dir, "sub", "a.txt"), []byte("working tree"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "untracked.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		ref     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "過去のコミット", dir: dir, ref: "HEAD~1", want: map[string]string{"sub/a.txt": "2", "b.txt": "1"}},
+		{name: "サブディレクトリからの相対パス", dir: filepath.Join(dir, "sub"), ref: "HEAD", want: map[string]string{"a.txt": "3"}},
+		{name: "存在しない参照", dir: dir, ref: "no-such-ref", wantErr: true},
+		{name: "オプションとして解釈される参照", dir: dir, ref: "--output=x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := RefArchive(context.Background(), tt.dir, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("エラーが返されませんでした")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RefArchive() error = %v", err)
+			}
+			got := make(map[string]string)
+			tr := tar.NewReader(bytes.NewReader(data))
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("tar の読み込みに失敗: %v", err)
+				}
+				if hdr.Typeflag != tar.TypeReg {
+					continue
+				}
+				content, _ := io.ReadAll(tr)
+				got[hdr.Name] = string(content)
+				if tt.ref == "HEAD" && !hdr.ModTime.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+					t.Errorf("%s の更新日時 = %v, want コミット日時", hdr.Name, hdr.ModTime)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ファイル = %v, want %v", got, tt.want)
+			}
+			for name, content := range tt.want {
+				if got[name] != content {
+					t.Errorf("%s = %q, want %q", name, got[name], content)
+				}
+			}
+		})
+	}
+}
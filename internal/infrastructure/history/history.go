@@ -0,0 +1,177 @@
+// Package history は実行ごとの日時・オプション・対象・出力先・結果を記録する、追記専用の履歴ファイルを提供します
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"FolderScope/internal/infrastructure/state"
+)
+
+const (
+	// FileName は履歴ファイルの名前です
+	FileName = "history.jsonl"
+
+	// ResultSuccess は正常に完了した実行の結果です
+	ResultSuccess = "success"
+	// ResultFailure はエラーで終了した実行の結果です
+	ResultFailure = "failure"
+	// ResultPartial は完了したものの、一部のファイル・フォルダを読み込めなかった実行の結果です。内容は Error に記録します
+	ResultPartial = "partial"
+	// ResultCancelled は利用者の操作・シグナル・タイムアウトによって中断した実行の結果です。理由は CancelReason に記録します
+	ResultCancelled = "cancelled"
+)
+
+// Entry は 1 回の実行の記録です
+type Entry struct {
+	// Time は実行を開始した日時です
+	Time time.Time `json:"time"`
+	// User と Host は実行したユーザー名とホスト名です。取得できない場合は空です
+	User string `json:"user,omitempty"`
+	Host string `json:"host,omitempty"`
+	// Command は実行の種類（report, watch, diff, gui, snapshot, compare）です
+	Command string `json:"command"`
+	// Args はコマンドライン引数です
+	Args []string `json:"args,omitempty"`
+	// Source は調査対象のフォルダまたはアーカイブの絶対パスです
+	Source string `json:"source,omitempty"`
+	// Output は出力したレポートやスナップショットのパスです
+	Output string `json:"output,omitempty"`
+	// Result は実行の結果（success, partial, failure, cancelled）です
+	Result string `json:"result"`
+	// Error は失敗した場合のエラーメッセージです
+	Error string `json:"error,omitempty"`
+	// CancelReason は中止した場合の理由（user, timeout, signal）です
+	CancelReason string `json:"cancelReason,omitempty"`
+	// DurationMillis は実行にかかった時間（ミリ秒）です
+	DurationMillis int64 `json:"durationMs"`
+}
+
+// Filter は履歴の絞り込み条件です。ゼロ値の項目は条件に使用しません
+type Filter struct {
+	// Source は調査対象のパスです。このパスまたはその配下を対象とした実行に絞り込みます
+	Source string
+	// Command は実行の種類です
+	Command string
+	// Since より前に開始した実行は除きます
+	Since time.Time
+	// Limit は返す件数の上限です（新しいものから数えます）
+	Limit int
+}
+
+// match は entry が条件を満たすかどうかを返します
+func (f Filter) match(entry Entry) bool {
+	if f.Command != "" && entry.Command != f.Command {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if f.Source != "" && !within(f.Source, entry.Source) {
+		return false
+	}
+	return true
+}
+
+// within は path が dir と同じか、その配下のパスであるかどうかを返します
+func within(dir, path string) bool {
+	if path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// DefaultPath はユーザー設定ディレクトリ配下の履歴ファイルのパス（例: ~/.config/folderscope/history.jsonl）を返します
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("ユーザー設定ディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(configDir, state.AppDirName, FileName), nil
+}
+
+// Log は履歴ファイルへの追記と読み込みを行います
+type Log struct {
+	path string
+}
+
+// NewLog は指定されたパスの履歴ファイルを扱う Log インスタンスを作成します
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path は履歴ファイルのパスを返します
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append は entry を履歴ファイルの末尾に 1 行の JSON として追記します。
+// 既存の記録は書き換えず、同時に実行された別のプロセスの記録と行が混ざらないよう 1 回の書き込みで追記します
+func (l *Log) Append(entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("履歴ファイルのディレクトリ作成に失敗しました: %w", err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("履歴のエンコードに失敗しました: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("履歴ファイルを開けませんでした: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("履歴ファイルへの追記に失敗しました: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("履歴ファイルへの追記に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Query は条件を満たす記録を新しい順に返します。ファイルが存在しない場合は空の一覧を返します。
+// 書き込み途中で中断された行など、解析できない行は読み飛ばし、その行数を skipped に返します
+func (l *Log) Query(filter Filter) (entries []Entry, skipped int, err error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("履歴ファイルの読み込みに失敗しました: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			skipped++
+			continue
+		}
+		if filter.match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("履歴ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, skipped, nil
+}
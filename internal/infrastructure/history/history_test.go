@@ -0,0 +1,71 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog_AppendQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+	log := NewLog(path)
+
+	// ファイルが存在しない場合は空の一覧
+	entries, skipped, err := log.Query(Filter{})
+	if err != nil || len(entries) != 0 || skipped != 0 {
+		t.Fatalf("Query() = %v, %d, %v, want 空の一覧", entries, skipped, err)
+	}
+
+	base := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	records := []Entry{
+		{Time: base, Command: "snapshot", Source: "/srv/share", Output: "share.snapshot.json", Result: ResultSuccess},
+		{Time: base.Add(2 * time.Hour), Command: "report", Source: "/srv/share/docs", Result: ResultFailure, Error: "出力先フォルダが無効です"},
+		{Time: base.Add(time.Hour), Command: "report", Source: "/srv/shared", Result: ResultSuccess},
+		{Time: base.Add(3 * time.Hour), Command: "snapshot", Source: "/srv/share", Result: ResultCancelled},
+	}
+	for _, r := range records {
+		if err := log.Append(r); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	// 書き込み途中で中断された行は読み飛ばす
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("ファイルを開けませんでした: %v", err)
+	}
+	file.WriteString(`{"time":"2024-06-01T`)
+	file.Close()
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		want        string
+		wantSkipped int
+	}{
+		{name: "すべて（新しい順）", filter: Filter{}, want: "snapshot:/srv/share,report:/srv/share/docs,report:/srv/shared,snapshot:/srv/share", wantSkipped: 1},
+		{name: "調査対象とその配下", filter: Filter{Source: "/srv/share/"}, want: "snapshot:/srv/share,report:/srv/share/docs,snapshot:/srv/share", wantSkipped: 1},
+		{name: "実行の種類と件数", filter: Filter{Command: "snapshot", Limit: 1}, want: "snapshot:/srv/share", wantSkipped: 1},
+		{name: "開始日時", filter: Filter{Since: base.Add(90 * time.Minute)}, want: "snapshot:/srv/share,report:/srv/share/docs", wantSkipped: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, skipped, err := log.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Command+":"+e.Source)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("Query() = %v, want %s", got, tt.want)
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
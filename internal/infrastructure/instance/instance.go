@@ -0,0 +1,130 @@
+// Package instance は GUI を 1 つのプロセスで実行するための、起動中のインスタンスとのローカルソケットによる通信を提供します。
+// 2 つ目に起動した GUI（エクスプローラーのコンテキストメニューからの起動など）は、新しいウィンドウを開かずに、
+// 調査対象フォルダを起動中のインスタンスに渡して終了します
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// AppDirName はユーザー設定ディレクトリ配下に作成するディレクトリ名です
+	AppDirName = "folderscope"
+	// SocketFileName は起動中のインスタンスが待ち受けるソケットのファイル名です
+	SocketFileName = "gui.sock"
+	// DialTimeout は起動中のインスタンスとの通信の時間の上限です
+	DialTimeout = 2 * time.Second
+)
+
+// ErrNoInstance は起動中のインスタンスがないことを示します
+var ErrNoInstance = errors.New("起動中のインスタンスがありません")
+
+// Request は起動中のインスタンスに渡す要求です
+type Request struct {
+	// Source は調査対象フォルダの絶対パスです。空の場合はウィンドウを前面に表示するだけです
+	Source string `json:"source,omitempty"`
+}
+
+// response は要求を受け付けたことを示す応答です
+type response struct {
+	OK bool `json:"ok"`
+}
+
+// DefaultPath はユーザー設定ディレクトリ配下のソケットのパス（例: ~/.config/folderscope/gui.sock）を返します
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("ユーザー設定ディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(configDir, AppDirName, SocketFileName), nil
+}
+
+// Forward は path のソケットで待ち受けている起動中のインスタンスに、調査対象フォルダ source を渡します。
+// 起動中のインスタンスがない場合（ソケットがない、または終了したプロセスのソケットが残っている場合）は ErrNoInstance を返します
+func Forward(path, source string) error {
+	conn, err := net.DialTimeout("unix", path, DialTimeout)
+	if err != nil {
+		return ErrNoInstance
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(DialTimeout))
+
+	if err := json.NewEncoder(conn).Encode(Request{Source: source}); err != nil {
+		return fmt.Errorf("起動中のインスタンスへの送信に失敗しました: %w", err)
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return fmt.Errorf("起動中のインスタンスからの応答の受信に失敗しました: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("起動中のインスタンスが要求を受け付けませんでした")
+	}
+	return nil
+}
+
+// Listener は起動中のインスタンスとして、他のプロセスからの要求を受け付けます
+type Listener struct {
+	listener net.Listener
+	path     string
+}
+
+// Listen は path にソケットを作成して、他のプロセスからの要求の受け付けを開始します。
+// 終了したプロセスのソケットファイルが残っている場合は削除して作り直します。
+// ほかのインスタンスがすでに待ち受けている場合はエラーを返します
+func Listen(path string) (*Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("ソケットのディレクトリ作成に失敗しました: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", path, DialTimeout); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("ほかのインスタンスがすでに起動しています: %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("古いソケットの削除に失敗しました: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("ソケットの作成に失敗しました: %w", err)
+	}
+	return &Listener{listener: listener, path: path}, nil
+}
+
+// Serve は要求を受け付けるたびに handle を調査対象フォルダとともに呼び出します。Close が呼び出されるまでブロックします
+func (l *Listener) Serve(handle func(source string)) error {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("要求の受け付けに失敗しました: %w", err)
+		}
+		l.serveConn(conn, handle)
+	}
+}
+
+// serveConn は 1 つの接続から要求を読み込み、応答を返します
+func (l *Listener) serveConn(conn net.Conn, handle func(source string)) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(DialTimeout))
+	var req Request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return
+	}
+	handle(req.Source)
+	json.NewEncoder(conn).Encode(response{OK: true})
+}
+
+// Close は要求の受け付けを終了し、ソケットファイルを削除します
+func (l *Listener) Close() error {
+	err := l.listener.Close()
+	os.Remove(l.path)
+	return err
+}
@@ -0,0 +1,66 @@
+package instance
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestForward(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SocketFileName)
+	if err := Forward(path, "/src"); !errors.Is(err, ErrNoInstance) {
+		t.Fatalf("起動中のインスタンスがない場合の Forward() error = %v, want ErrNoInstance", err)
+	}
+
+	listener, err := Listen(path)
+	if err != nil {
+		t.Skipf("ソケットを作成できません: %v", err)
+	}
+	received := make(chan string, 2)
+	done := make(chan error, 1)
+	go func() { done <- listener.Serve(func(source string) { received <- source }) }()
+
+	for _, source := range []string{"/src", ""} {
+		if err := Forward(path, source); err != nil {
+			t.Fatalf("Forward(%q) error = %v", source, err)
+		}
+		if got := <-received; got != source {
+			t.Errorf("受け取った調査対象フォルダ = %q, want %q", got, source)
+		}
+	}
+	if _, err := Listen(path); err == nil {
+		t.Error("起動中のインスタンスがある場合の Listen() はエラーになるべきです")
+	}
+
+	if err := listener.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Close() 後にソケットファイルが残っています: %v", err)
+	}
+}
+
+func TestListen_StaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SocketFileName)
+	// 終了したプロセスのソケットファイルを再現する
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("ソケットを作成できません: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("ソケットファイルが残っていません: %v", err)
+	}
+
+	listener, err := Listen(path)
+	if err != nil {
+		t.Fatalf("古いソケットが残っている場合の Listen() error = %v", err)
+	}
+	listener.Close()
+}
@@ -2,60 +2,188 @@
 package logging
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// ログの項目のキーです。log/slog の既定のキー（time, msg）の代わりに使用し、従来の JSON 形式を保ちます
+const (
+	timestampKey    = "timestamp"
+	levelKey        = "level"
+	messageKey      = "message"
+	errorKey        = "error"
+	cancelReasonKey = "cancelReason"
 )
 
+// LevelTrace は DEBUG より詳細な、スキャンで記録したパスごとの判定などを出力するログレベルです
+const LevelTrace = slog.LevelDebug - 4
+
+// LevelName はログレベルの名前（TRACE, DEBUG, INFO, WARN, ERROR）を返します
+func LevelName(level slog.Level) string {
+	if level == LevelTrace {
+		return "TRACE"
+	}
+	return level.String()
+}
+
 // LogEntry はログエントリを表す構造体です
 type LogEntry struct {
 	// Timestamp はログが記録された時刻をRFC3339形式で表します
 	Timestamp string `json:"timestamp"`
-	// Level はログレベル（INFO, WARN, ERROR等）を表します
+	// Level はログレベル（TRACE, DEBUG, INFO, WARN, ERROR）を表します
 	Level string `json:"level"`
 	// Message はログメッセージの内容を表します
 	Message string `json:"message"`
 	// Error はエラーが発生した場合のエラーメッセージを表します
 	Error string `json:"error,omitempty"`
+	// CancelReason はエラーが処理の中止を示す場合の理由（user, timeout, signal）を表します
+	CancelReason apperrors.CancelReason `json:"cancelReason,omitempty"`
+	// Fields はログに付加したキーと値の組（path, duration, bytes など）を表します
+	Fields map[string]any `json:"fields,omitempty"`
 }
 
-// Logger は構造化ログを出力するためのインターフェースです
+// newLogEntry は現在時刻のログエントリを作成します
+func newLogEntry(level, message string, err error, attrs ...any) LogEntry {
+	record := newRecord(level, message, err, attrs)
+	entry := LogEntry{
+		Timestamp: record.Time.Format(time.RFC3339),
+		Level:     LevelName(record.Level),
+		Message:   message,
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case errorKey:
+			entry.Error = a.Value.String()
+		case cancelReasonKey:
+			entry.CancelReason = apperrors.CancelReason(a.Value.String())
+		default:
+			if entry.Fields == nil {
+				entry.Fields = make(map[string]any)
+			}
+			value := a.Value.Resolve().Any()
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+			entry.Fields[a.Key] = value
+		}
+		return true
+	})
+	return entry
+}
+
+// Logger は構造化ログを出力するためのインターフェースです。
+// attrs は log/slog と同じ形式のキーと値の組（"path", path, "bytes", n など）または slog.Attr で、ログの項目として出力します
 type Logger interface {
-	Log(level, message string, err error)
+	Log(level, message string, err error, attrs ...any)
 }
 
-// JSONLogger はJSONフォーマットでログを出力するロガーです
-type JSONLogger struct {
-	writer io.Writer
+// ParseLevel はログレベルの名前（TRACE, DEBUG, INFO, WARN, ERROR。大文字・小文字を区別しない）を解析します
+func ParseLevel(s string) (slog.Level, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "trace") {
+		return LevelTrace, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("未対応のログレベルです: %s（trace, debug, info, warn, error のいずれかを指定してください）", s)
+	}
+	return level, nil
 }
 
-// NewJSONLogger は新しいJSONLoggerインスタンスを作成します
-func NewJSONLogger(writer io.Writer) *JSONLogger {
+// levelOf は Log に渡されたログレベルの名前を log/slog のレベルに変換します。解析できない場合は INFO として扱います
+func levelOf(s string) slog.Level {
+	if level, err := ParseLevel(s); err == nil {
+		return level
+	}
+	return slog.LevelInfo
+}
+
+// newRecord は Log の引数から log/slog のレコードを作成します。
+// エラーはメッセージを error の項目に、処理の中止を示す場合は理由を cancelReason の項目に記録します
+func newRecord(level, message string, err error, attrs []any) slog.Record {
+	record := slog.NewRecord(time.Now(), levelOf(level), message, 0)
+	if err != nil {
+		record.AddAttrs(slog.String(errorKey, err.Error()))
+		if reason := apperrors.ReasonOf(err); reason != "" {
+			record.AddAttrs(slog.String(cancelReasonKey, string(reason)))
+		}
+	}
+	record.Add(attrs...)
+	return record
+}
+
+// SlogLogger は log/slog のハンドラーにログを出力するロガーです。
+// ハンドラーが有効にしていないレベルのログは出力しません
+type SlogLogger struct {
+	handler slog.Handler
+}
+
+// NewSlogLogger は handler に出力する SlogLogger を作成します
+func NewSlogLogger(handler slog.Handler) *SlogLogger {
+	return &SlogLogger{handler: handler}
+}
+
+// Options は JSON 形式のロガーの動作を制御するオプションです
+type Options struct {
+	// Level は出力する最も低いログレベルです。nil の場合は INFO 以上を出力します
+	Level slog.Leveler
+}
+
+// NewJSONLogger は INFO 以上のログを JSON 形式で出力するロガーを作成します
+func NewJSONLogger(writer io.Writer) *SlogLogger {
+	return NewJSONLoggerWithOptions(writer, Options{})
+}
+
+// NewJSONLoggerWithOptions は Options を指定して、JSON 形式で出力するロガーを作成します。
+// 1 件のログは 1 行の JSON（timestamp, level, message, error と付加した項目）として書き込まれ、
+// 複数のゴルーチンから同時に使用しても行が混ざりません
+func NewJSONLoggerWithOptions(writer io.Writer, opts Options) *SlogLogger {
 	if writer == nil {
 		writer = os.Stdout
 	}
-	return &JSONLogger{writer: writer}
+	return NewSlogLogger(slog.NewJSONHandler(writer, &slog.HandlerOptions{
+		Level:       opts.Level,
+		ReplaceAttr: replaceBuiltinAttr,
+	}))
 }
 
-// Log はメッセージをJSONフォーマットでログ出力します
-func (l *JSONLogger) Log(level, message string, err error) {
-	entry := LogEntry{
-		Timestamp: time.Now().Format(time.RFC3339),
-		Level:     level,
-		Message:   message,
+// replaceBuiltinAttr は log/slog の既定の項目を、従来の JSON 形式のキーと値（RFC3339 形式の時刻など）に置き換えます
+func replaceBuiltinAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
 	}
-
-	if err != nil {
-		entry.Error = err.Error()
+	switch a.Key {
+	case slog.TimeKey:
+		return slog.String(timestampKey, a.Value.Time().Format(time.RFC3339))
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String(levelKey, LevelName(level))
+		}
+		a.Key = levelKey
+	case slog.MessageKey:
+		a.Key = messageKey
 	}
+	return a
+}
 
-	jsonData, err := json.Marshal(entry)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ログのJSONエンコードに失敗: %v\n", err)
+// Log はメッセージを、エラーと付加した項目とともにハンドラーに出力します
+func (l *SlogLogger) Log(level, message string, err error, attrs ...any) {
+	ctx := context.Background()
+	if !l.handler.Enabled(ctx, levelOf(level)) {
 		return
 	}
+	if err := l.handler.Handle(ctx, newRecord(level, message, err, attrs)); err != nil {
+		fmt.Fprintf(os.Stderr, "ログの書き込みに失敗: %v\n", err)
+	}
+}
 
-	fmt.Fprintln(l.writer, string(jsonData))
+// Handler はログの出力先の log/slog のハンドラーを返します。slog.New(logger.Handler()) で標準の slog.Logger として使用できます
+func (l *SlogLogger) Handler() slog.Handler {
+	return l.handler
 }
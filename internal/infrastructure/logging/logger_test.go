@@ -3,29 +3,46 @@ package logging
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"FolderScope/internal/domain/apperrors"
 )
 
 func TestJSONLogger(t *testing.T) {
 	tests := []struct {
-		name    string
-		level   string
-		message string
-		err     error
+		name      string
+		level     string
+		wantLevel string
+		message   string
+		err       error
+		reason    apperrors.CancelReason
 	}{
 		{
-			name:    "エラーなしのログ",
-			level:   "info",
-			message: "テストメッセージ",
-			err:     nil,
+			name:      "エラーなしのログ",
+			level:     "info",
+			wantLevel: "INFO",
+			message:   "テストメッセージ",
+			err:       nil,
+		},
+		{
+			name:      "エラーありのログ",
+			level:     "error",
+			wantLevel: "ERROR",
+			message:   "エラーメッセージ",
+			err:       errors.New("テストエラー"),
 		},
 		{
-			name:    "エラーありのログ",
-			level:   "error",
-			message: "エラーメッセージ",
-			err:     errors.New("テストエラー"),
+			name:      "中止の理由を持つエラーのログ",
+			level:     "WARN",
+			wantLevel: "WARN",
+			message:   "処理を中止しました",
+			err:       fmt.Errorf("スキャンに失敗しました: %w", apperrors.Cancelled(apperrors.CancelTimeout, "10m0s")),
+			reason:    apperrors.CancelTimeout,
 		},
 	}
 
@@ -47,8 +64,8 @@ func TestJSONLogger(t *testing.T) {
 			if logEntry.Message != tt.message {
 				t.Errorf("メッセージが不正: got %v, want %v", logEntry.Message, tt.message)
 			}
-			if logEntry.Level != tt.level {
-				t.Errorf("ログレベルが不正: got %v, want %v", logEntry.Level, tt.level)
+			if logEntry.Level != tt.wantLevel {
+				t.Errorf("ログレベルが不正: got %v, want %v", logEntry.Level, tt.wantLevel)
 			}
 			if tt.err != nil {
 				if logEntry.Error != tt.err.Error() {
@@ -57,6 +74,9 @@ func TestJSONLogger(t *testing.T) {
 			} else if logEntry.Error != "" {
 				t.Errorf("エラーメッセージが不正: got %v, want empty", logEntry.Error)
 			}
+			if logEntry.CancelReason != tt.reason {
+				t.Errorf("中止の理由が不正: got %v, want %v", logEntry.CancelReason, tt.reason)
+			}
 
 			// タイムスタンプが現在時刻に近いことを確認
 			logTime, err := time.Parse(time.RFC3339, logEntry.Timestamp)
@@ -71,3 +91,163 @@ func TestJSONLogger(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONLoggerWithOptions_Level(t *testing.T) {
+	tests := []struct {
+		name  string
+		level slog.Level
+		want  []string
+	}{
+		{name: "TRACE 以上", level: LevelTrace, want: []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"}},
+		{name: "DEBUG 以上", level: slog.LevelDebug, want: []string{"DEBUG", "INFO", "WARN", "ERROR"}},
+		{name: "INFO 以上", level: slog.LevelInfo, want: []string{"INFO", "WARN", "ERROR"}},
+		{name: "WARN 以上", level: slog.LevelWarn, want: []string{"WARN", "ERROR"}},
+		{name: "ERROR のみ", level: slog.LevelError, want: []string{"ERROR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			logger := NewJSONLoggerWithOptions(&buf, Options{Level: tt.level})
+			for _, level := range []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"} {
+				logger.Log(level, "メッセージ", nil)
+			}
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if line == "" {
+					continue
+				}
+				var entry LogEntry
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("JSONの解析に失敗: %v", err)
+				}
+				got = append(got, entry.Level)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("出力されたレベル = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONLogger_Fields(t *testing.T) {
+	var buf strings.Builder
+	logger := NewJSONLogger(&buf)
+	logger.Log("INFO", "レポートを生成しました", nil, "path", "out.txt", "bytes", 1024, slog.Duration("duration", 1500*time.Millisecond))
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatalf("JSONの解析に失敗: %v", err)
+	}
+	want := map[string]any{
+		"level":    "INFO",
+		"message":  "レポートを生成しました",
+		"path":     "out.txt",
+		"bytes":    float64(1024),
+		"duration": float64(1500 * time.Millisecond),
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+	for _, key := range []string{"time", "msg"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("既定のキー %s が出力されています: %v", key, got)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{name: "小文字", input: "debug", want: slog.LevelDebug},
+		{name: "TRACE", input: "trace", want: LevelTrace},
+		{name: "大文字", input: "INFO", want: slog.LevelInfo},
+		{name: "WARN", input: "warn", want: slog.LevelWarn},
+		{name: "ERROR", input: "Error", want: slog.LevelError},
+		{name: "未対応のレベル", input: "verbose", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// chunkRecorder は Write の呼び出しごとの内容を記録する Writer です。
+// 排他制御を行わないため、ロガー側で直列化されていなければ -race で検出されます
+type chunkRecorder struct {
+	chunks []string
+}
+
+func (r *chunkRecorder) Write(p []byte) (int, error) {
+	r.chunks = append(r.chunks, string(p))
+	return len(p), nil
+}
+
+func TestJSONLogger_Concurrent(t *testing.T) {
+	const goroutines, perGoroutine = 8, 50
+	recorder := &chunkRecorder{}
+	logger := NewJSONLogger(recorder)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				logger.Log("INFO", fmt.Sprintf("goroutine %d message %d", g, i), nil)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if len(recorder.chunks) != goroutines*perGoroutine {
+		t.Fatalf("書き込み回数 = %d, want %d", len(recorder.chunks), goroutines*perGoroutine)
+	}
+	for _, chunk := range recorder.chunks {
+		// 1 回の Write が改行で終わる 1 行分の JSON であること
+		if !strings.HasSuffix(chunk, "\n") || strings.Count(chunk, "\n") != 1 {
+			t.Fatalf("1 回の書き込みが 1 行になっていません: %q", chunk)
+		}
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(chunk), &entry); err != nil {
+			t.Fatalf("JSONの解析に失敗: %v", err)
+		}
+	}
+}
+
+func TestRecentLogger(t *testing.T) {
+	var buf strings.Builder
+	logger := NewRecentLogger(NewJSONLogger(&buf), 3)
+	for i := 1; i <= 5; i++ {
+		logger.Log("INFO", fmt.Sprintf("メッセージ %d", i), nil)
+	}
+	logger.Log("ERROR", "失敗", errors.New("原因"), "path", "a.txt")
+
+	var got []string
+	for _, entry := range logger.Entries() {
+		got = append(got, entry.Message+entry.Error)
+	}
+	if want := "メッセージ 4,メッセージ 5,失敗原因"; strings.Join(got, ",") != want {
+		t.Errorf("Entries() = %v, want %s", got, want)
+	}
+	if entries := logger.Entries(); entries[len(entries)-1].Fields["path"] != "a.txt" {
+		t.Errorf("保持したログの項目 = %v, want path=a.txt", entries[len(entries)-1].Fields)
+	}
+	// 保持する件数にかかわらず、出力先にはすべてのログを書き込む
+	if lines := strings.Count(buf.String(), "\n"); lines != 6 {
+		t.Errorf("出力されたログ = %d 行, want 6", lines)
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// RecentLogger は別のロガーに出力しながら、直近のログを指定された件数まで保持するロガーです。
+// 異常終了時の診断情報に、それまでの経過を含めるために使用します
+type RecentLogger struct {
+	next Logger
+
+	mu      sync.Mutex
+	entries []LogEntry
+	// start は entries が一巡した後の最も古いログの位置です
+	start int
+}
+
+// NewRecentLogger は next に出力し、直近の capacity 件のログを保持する RecentLogger を作成します
+func NewRecentLogger(next Logger, capacity int) *RecentLogger {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RecentLogger{next: next, entries: make([]LogEntry, 0, capacity)}
+}
+
+// Log はログを保持してから next に出力します。next が出力しないレベルのログも、診断のために保持します。
+// ただし、パスごとに出力する TRACE のログは直近の経過を埋め尽くすため保持しません
+func (l *RecentLogger) Log(level, message string, err error, attrs ...any) {
+	if levelOf(level) < slog.LevelDebug {
+		l.next.Log(level, message, err, attrs...)
+		return
+	}
+	entry := newLogEntry(level, message, err, attrs...)
+
+	l.mu.Lock()
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, entry)
+	} else {
+		l.entries[l.start] = entry
+		l.start = (l.start + 1) % len(l.entries)
+	}
+	l.mu.Unlock()
+
+	l.next.Log(level, message, err, attrs...)
+}
+
+// Entries は保持している直近のログを古い順に返します
+func (l *RecentLogger) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]LogEntry, 0, len(l.entries))
+	entries = append(entries, l.entries[l.start:]...)
+	return append(entries, l.entries[:l.start]...)
+}
@@ -0,0 +1,434 @@
+// Package patch は unified diff 形式のパッチを読み込み、フォルダの内容に適用する機能を提供します
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/infrastructure/archive"
+)
+
+// Status はパッチによるファイルの変更の種類です
+type Status string
+
+const (
+	StatusAdded    Status = "added"
+	StatusModified Status = "modified"
+	StatusDeleted  Status = "deleted"
+	StatusRenamed  Status = "renamed"
+)
+
+// ErrNoChanges はパッチに変更が含まれていないことを示します
+var ErrNoChanges = errors.New("パッチに変更が含まれていません")
+
+// File はパッチに含まれる 1 ファイル分の変更です
+type File struct {
+	// OldPath と NewPath は変更前と変更後の相対パス（'/' 区切り）です。
+	// 追加されたファイルの OldPath と、削除されたファイルの NewPath は空文字列です
+	OldPath string
+	NewPath string
+	Status  Status
+	// Mode は追加されたファイルのパーミッションです。指定がない場合は 0 です
+	Mode fs.FileMode
+	// Binary はバイナリファイルの変更であることを示します。内容は適用できません
+	Binary bool
+	// Hunks は変更箇所（"@@" で始まるハンク）のテキストです
+	Hunks string
+
+	hunks []hunk
+	// markers は "---" と "+++" の行を読み込んだかどうかを示します
+	markers bool
+}
+
+// hunk は 1 つの変更箇所です。oldLines と newLines は改行を含む行です
+type hunk struct {
+	oldStart int
+	oldLines []string
+	newLines []string
+}
+
+// Path は変更後のパス（削除された場合は変更前のパス）を返します
+func (f *File) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Parse は unified diff 形式のパッチを解析し、ファイルごとの変更を返します。
+// git diff・git format-patch（メールのヘッダーとコミットメッセージを含む）・diff -u の出力に対応します。
+// パスは git apply と同様に、先頭の 1 階層（"a/" や "b/" など）を取り除いて扱います
+func Parse(data []byte) ([]File, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+	var files []File
+	var current *File
+	start := func() *File {
+		files = append(files, File{Status: StatusModified})
+		return &files[len(files)-1]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = start()
+			if oldPath, newPath, ok := parseGitHeader(strings.TrimPrefix(line, "diff --git ")); ok {
+				current.OldPath, current.NewPath = oldPath, newPath
+			}
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			// git 以外の diff はヘッダー行がないため、"---" の行から新しいファイルの変更とする
+			if current == nil || current.markers || len(current.hunks) > 0 {
+				current = start()
+			}
+			current.markers = true
+			if name := markerPath(line[len("--- "):]); name == "/dev/null" {
+				current.OldPath, current.Status = "", StatusAdded
+			} else {
+				current.OldPath = stripComponent(name)
+			}
+			i++
+			if name := markerPath(lines[i][len("+++ "):]); name == "/dev/null" {
+				current.NewPath, current.Status = "", StatusDeleted
+			} else {
+				current.NewPath = stripComponent(name)
+			}
+		case current == nil:
+			// 最初のファイルより前の行（メールのヘッダーやコミットメッセージ）は読み飛ばす
+		case strings.HasPrefix(line, "new file mode "):
+			current.Status = StatusAdded
+			current.OldPath = ""
+			if mode, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "new file mode ")), 8, 32); err == nil {
+				current.Mode = fs.FileMode(mode).Perm()
+			}
+		case strings.HasPrefix(line, "deleted file mode "):
+			current.Status = StatusDeleted
+			current.NewPath = ""
+		case strings.HasPrefix(line, "rename from "):
+			current.Status = StatusRenamed
+			current.OldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			current.Status = StatusRenamed
+			current.NewPath = unquotePath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "Binary files "), strings.HasPrefix(line, "GIT binary patch"):
+			current.Binary = true
+		case strings.HasPrefix(line, "@@ "):
+			h, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, fmt.Errorf("'%s' の変更箇所の読み込みに失敗しました: %w", current.Path(), err)
+			}
+			current.hunks = append(current.hunks, h)
+			current.Hunks += strings.Join(lines[i:next], "")
+			i = next - 1
+		}
+	}
+
+	result := make([]File, 0, len(files))
+	for _, f := range files {
+		if f.OldPath == "" && f.NewPath == "" {
+			continue
+		}
+		for _, p := range []string{f.OldPath, f.NewPath} {
+			if p != "" && !fs.ValidPath(p) {
+				return nil, fmt.Errorf("パッチのパス '%s' が不正です", p)
+			}
+		}
+		result = append(result, f)
+	}
+	if len(result) == 0 {
+		return nil, ErrNoChanges
+	}
+	return result, nil
+}
+
+// parseGitHeader は "diff --git a/x b/y" のパスの部分から、先頭の 1 階層を取り除いた変更前と変更後のパスを返します
+func parseGitHeader(s string) (string, string, bool) {
+	s = strings.TrimRight(s, "\r\n")
+	if strings.HasPrefix(s, `"`) {
+		// 引用符で囲まれたパスは、それぞれを Go の文字列リテラルとして読み込む
+		oldPath, rest, ok := cutQuoted(s)
+		if !ok {
+			return "", "", false
+		}
+		return stripComponent(oldPath), stripComponent(unquotePath(strings.TrimPrefix(rest, " "))), true
+	}
+	// 名前を変更しない場合は前後のパスが同じになるため、中央で分割できる
+	if n := len(s); n%2 == 1 {
+		oldPath, newPath := s[:n/2], s[n/2+1:]
+		if stripComponent(oldPath) == stripComponent(newPath) {
+			return stripComponent(oldPath), stripComponent(newPath), true
+		}
+	}
+	if i := strings.LastIndex(s, " b/"); i >= 0 {
+		return stripComponent(s[:i]), stripComponent(s[i+1:]), true
+	}
+	return "", "", false
+}
+
+// cutQuoted は引用符で囲まれた先頭の文字列を読み込み、その内容と残りを返します
+func cutQuoted(s string) (string, string, bool) {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == '"' {
+			unquoted, err := strconv.Unquote(s[:i+1])
+			return unquoted, s[i+1:], err == nil
+		}
+	}
+	return "", "", false
+}
+
+// markerPath は "---" と "+++" の行からパスを取り出します。diff -u がタブの後に付加する日時は取り除きます
+func markerPath(s string) string {
+	s = strings.TrimRight(s, "\r\n")
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, _, ok := cutQuoted(s); ok {
+			return unquoted
+		}
+	}
+	if i := strings.Index(s, "\t"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// unquotePath は "rename from" などの行のパスを取り出します。特殊な文字を含むパスは引用符で囲まれてエスケープされています
+func unquotePath(s string) string {
+	s = strings.TrimRight(s, "\r\n")
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	return s
+}
+
+// stripComponent はパスの先頭の 1 階層を取り除きます。'/' を含まないパスはそのまま返します
+func stripComponent(p string) string {
+	if i := strings.Index(p, "/"); i >= 0 {
+		p = p[i+1:]
+	}
+	return path.Clean(p)
+}
+
+// parseHunk は lines[i] の "@@ -a,b +c,d @@" から始まる変更箇所を読み込み、変更箇所と次の行の位置を返します
+func parseHunk(lines []string, i int) (hunk, int, error) {
+	var h hunk
+	header := lines[i]
+	end := strings.Index(header[len("@@ "):], " @@")
+	if end < 0 {
+		return h, 0, fmt.Errorf("変更箇所のヘッダーが不正です: %s", strings.TrimSpace(header))
+	}
+	fields := strings.Fields(header[len("@@ ") : len("@@ ")+end])
+	if len(fields) != 2 || !strings.HasPrefix(fields[0], "-") || !strings.HasPrefix(fields[1], "+") {
+		return h, 0, fmt.Errorf("変更箇所のヘッダーが不正です: %s", strings.TrimSpace(header))
+	}
+	oldStart, oldCount, err1 := parseRange(fields[0][1:])
+	_, newCount, err2 := parseRange(fields[1][1:])
+	if err1 != nil || err2 != nil {
+		return h, 0, fmt.Errorf("変更箇所のヘッダーが不正です: %s", strings.TrimSpace(header))
+	}
+	h.oldStart = oldStart
+
+	var last byte
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if oldCount == 0 && newCount == 0 && !strings.HasPrefix(line, `\`) {
+			break
+		}
+		kind := byte(' ')
+		text := ""
+		if line != "\n" && line != "\r\n" {
+			if line == "" {
+				break
+			}
+			kind, text = line[0], line[1:]
+		} else {
+			// メールで行末の空白が取り除かれた空の前後の行も、前後の行として扱う
+			text = line
+		}
+		switch kind {
+		case ' ':
+			h.oldLines = append(h.oldLines, text)
+			h.newLines = append(h.newLines, text)
+			oldCount--
+			newCount--
+		case '-':
+			h.oldLines = append(h.oldLines, text)
+			oldCount--
+		case '+':
+			h.newLines = append(h.newLines, text)
+			newCount--
+		case '\\':
+			// "\ No newline at end of file" は直前の行の末尾に改行がないことを示す
+			if last == ' ' || last == '-' {
+				trimLastNewline(h.oldLines)
+			}
+			if last == ' ' || last == '+' {
+				trimLastNewline(h.newLines)
+			}
+			continue
+		default:
+			return h, 0, fmt.Errorf("変更箇所の行数がヘッダーと一致しません")
+		}
+		if oldCount < 0 || newCount < 0 {
+			return h, 0, fmt.Errorf("変更箇所の行数がヘッダーと一致しません")
+		}
+		last = kind
+	}
+	if oldCount > 0 || newCount > 0 {
+		return h, 0, fmt.Errorf("変更箇所が途中で終わっています")
+	}
+	return h, i, nil
+}
+
+// parseRange は "a,b" または "a" の形式の範囲を、開始行と行数として返します
+func parseRange(s string) (int, int, error) {
+	startText, countText, found := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return start, 1, nil
+	}
+	count, err := strconv.Atoi(countText)
+	return start, count, err
+}
+
+// trimLastNewline は最後の行の末尾の改行を取り除きます
+func trimLastNewline(lines []string) {
+	if n := len(lines); n > 0 {
+		lines[n-1] = strings.TrimSuffix(strings.TrimSuffix(lines[n-1], "\n"), "\r")
+	}
+}
+
+// patchedFile は変更を適用したファイルの内容とパーミッションです
+type patchedFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// Apply は base の内容に files の変更を順に適用し、変更後に存在するファイルのみを含む fs.FS を返します。
+// 同じファイルを複数回変更するパッチ（git format-patch の連続したコミットなど）は、前の変更を適用した内容に続けて適用します。
+// 削除されたファイルとバイナリファイルは結果に含めません。ファイルの更新日時は modTime とします
+func Apply(base fs.FS, files []File, modTime time.Time) (*archive.MemFS, error) {
+	result := make(map[string]patchedFile)
+	removed := make(map[string]bool)
+	read := func(p string) (patchedFile, error) {
+		if removed[p] {
+			return patchedFile{}, fs.ErrNotExist
+		}
+		if f, ok := result[p]; ok {
+			return f, nil
+		}
+		data, err := fs.ReadFile(base, p)
+		if err != nil {
+			return patchedFile{}, err
+		}
+		mode := fs.FileMode(0644)
+		if info, err := fs.Stat(base, p); err == nil {
+			mode = info.Mode().Perm()
+		}
+		return patchedFile{data: data, mode: mode}, nil
+	}
+
+	for _, f := range files {
+		if f.Status == StatusDeleted {
+			delete(result, f.OldPath)
+			removed[f.OldPath] = true
+			continue
+		}
+		if f.Binary {
+			continue
+		}
+		source := patchedFile{mode: 0644}
+		if f.Status != StatusAdded {
+			var err error
+			if source, err = read(f.OldPath); err != nil {
+				return nil, apperrors.Wrap("パッチの適用元のファイルを読み込めませんでした", f.OldPath, err)
+			}
+		}
+		content, err := applyHunks(string(source.data), f.hunks)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' にパッチを適用できませんでした: %w", f.Path(), err)
+		}
+		mode := source.mode
+		if f.Mode != 0 {
+			mode = f.Mode
+		}
+		if f.Status == StatusRenamed && f.OldPath != f.NewPath {
+			delete(result, f.OldPath)
+			removed[f.OldPath] = true
+		}
+		delete(removed, f.NewPath)
+		result[f.NewPath] = patchedFile{data: []byte(content), mode: mode}
+	}
+
+	fsys := archive.NewMemFS()
+	for name, f := range result {
+		fsys.AddFile(name, f.data, f.mode, modTime)
+	}
+	return fsys, nil
+}
+
+// applyHunks は content に変更箇所を順に適用します。
+// 変更箇所はヘッダーの行番号の位置を優先し、一致しない場合は前後で変更前の行が一致する位置を探します
+func applyHunks(content string, hunks []hunk) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	var out []string
+	pos := 0
+	offset := 0
+	for _, h := range hunks {
+		expected := h.oldStart - 1 + offset
+		if len(h.oldLines) == 0 {
+			// 変更前の行がない変更箇所は、ヘッダーの行番号の直後に挿入する
+			expected = h.oldStart + offset
+		}
+		at, ok := findLines(lines, h.oldLines, expected, pos)
+		if !ok {
+			return "", fmt.Errorf("%d 行目からの変更箇所の変更前の内容が一致しません", h.oldStart)
+		}
+		out = append(out, lines[pos:at]...)
+		out = append(out, h.newLines...)
+		pos = at + len(h.oldLines)
+		// ヘッダーの行番号からのずれは、後続の変更箇所にも同様に生じているとみなす
+		offset += at - expected
+	}
+	out = append(out, lines[pos:]...)
+	return strings.Join(out, ""), nil
+}
+
+// findLines は lines の from 以降で want と一致する位置を、expected に近い順に探します
+func findLines(lines, want []string, expected, from int) (int, bool) {
+	matches := func(at int) bool {
+		if at < from || at+len(want) > len(lines) {
+			return false
+		}
+		for i, line := range want {
+			if lines[at+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+	for distance := 0; expected-distance >= from || expected+distance <= len(lines); distance++ {
+		if matches(expected - distance) {
+			return expected - distance, true
+		}
+		if distance > 0 && matches(expected+distance) {
+			return expected + distance, true
+		}
+	}
+	return 0, false
+}
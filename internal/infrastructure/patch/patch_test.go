@@ -0,0 +1,227 @@
+package patch
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+const formatPatch = `From 1234567890abcdef Mon Sep 17 00:00:00 2001
+From: Alice <alice@example.com>
+Date: Mon, 1 Jul 2024 10:00:00 +0900
+Subject: [PATCH] update files
+
+---
+ main.go    | 3 ++-
+ 3 files changed
+
+diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var a = 1
++var a = 2
+
+@@ -8,2 +8,3 @@ func main() {
+ 	println(a)
++	println("done")
+ }
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100755
+index 0000000..3333333
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++# New
++text
+\ No newline at end of file
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-old
+diff --git a/from.txt b/to.txt
+similarity index 100%
+rename from from.txt
+rename to to.txt
+diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+--
+2.45.0
+`
+
+func baseFS() fstest.MapFS {
+	return fstest.MapFS{
+		// 2 つ目の変更箇所がヘッダーの行番号より 1 行後ろにずれている
+		"main.go":  {Data: []byte("package main\nvar a = 1\n\n// comment\n\nfunc main() {\n\n\n\tprintln(a)\n}\n"), Mode: 0644},
+		"old.txt":  {Data: []byte("old\n")},
+		"from.txt": {Data: []byte("renamed\n"), Mode: 0600},
+		"logo.png": {Data: []byte{0x89, 'P', 'N', 'G'}},
+	}
+}
+
+func TestParse(t *testing.T) {
+	files, err := Parse([]byte(formatPatch))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []struct {
+		oldPath, newPath string
+		status           Status
+		binary           bool
+	}{
+		{"main.go", "main.go", StatusModified, false},
+		{"", "docs/new.md", StatusAdded, false},
+		{"old.txt", "", StatusDeleted, false},
+		{"from.txt", "to.txt", StatusRenamed, false},
+		{"logo.png", "logo.png", StatusModified, true},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("Parse() のファイル数 = %d, want %d", len(files), len(want))
+	}
+	for i, w := range want {
+		f := files[i]
+		if f.OldPath != w.oldPath || f.NewPath != w.newPath || f.Status != w.status || f.Binary != w.binary {
+			t.Errorf("files[%d] = %+v, want %+v", i, f, w)
+		}
+	}
+	if files[1].Mode != 0755 {
+		t.Errorf("追加されたファイルのパーミッション = %v, want 0755", files[1].Mode)
+	}
+	if !strings.HasPrefix(files[0].Hunks, "@@ -1,3 +1,3 @@\n") || !strings.HasSuffix(files[0].Hunks, "+\tprintln(\"done\")\n }\n") {
+		t.Errorf("変更箇所のテキストが不正です:\n%s", files[0].Hunks)
+	}
+}
+
+func TestApply(t *testing.T) {
+	files, err := Parse([]byte(formatPatch))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	modTime := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	result, err := Apply(baseFS(), files, modTime)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	want := map[string]string{
+		"main.go":     "package main\nvar a = 2\n\n// comment\n\nfunc main() {\n\n\n\tprintln(a)\n\tprintln(\"done\")\n}\n",
+		"docs/new.md": "# New\ntext",
+		"to.txt":      "renamed\n",
+	}
+	if names := fileNames(t, result); len(names) != len(want) {
+		t.Errorf("Apply() のファイル = %v, want %v", names, want)
+	}
+	for name, content := range want {
+		data, err := fs.ReadFile(result, name)
+		if err != nil {
+			t.Errorf("%s が含まれていません: %v", name, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s の内容 = %q, want %q", name, data, content)
+		}
+		if info, _ := fs.Stat(result, name); !info.ModTime().Equal(modTime) {
+			t.Errorf("%s の更新日時 = %v, want %v", name, info.ModTime(), modTime)
+		}
+	}
+	newInfo, _ := fs.Stat(result, "docs/new.md")
+	toInfo, _ := fs.Stat(result, "to.txt")
+	if newInfo.Mode() != 0755 || toInfo.Mode() != 0600 {
+		t.Errorf("パーミッションが引き継がれていません: %v, %v", newInfo.Mode(), toInfo.Mode())
+	}
+}
+
+func TestApply_PlainDiffAndSeries(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "diff -u の出力（日時付き）",
+			patch: "--- orig/a.txt\t2024-07-01 10:00:00.000000000 +0900\n+++ new/a.txt\t2024-07-02 10:00:00.000000000 +0900\n" +
+				"@@ -1,2 +1,2 @@\n-one\n+ONE\n two\n",
+			want: map[string]string{"a.txt": "ONE\ntwo\n"},
+		},
+		{
+			name: "同じファイルを続けて変更する",
+			patch: "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n-one\n+1\n two\n" +
+				"diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,3 @@\n 1\n two\n+three\n",
+			want: map[string]string{"a.txt": "1\ntwo\nthree\n"},
+		},
+		{
+			name:    "変更前の内容が一致しない",
+			patch:   "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-zero\n+0\n",
+			wantErr: "変更前の内容が一致しません",
+		},
+		{
+			name:    "適用元のファイルがない",
+			patch:   "--- a/missing.txt\n+++ b/missing.txt\n@@ -1 +1 @@\n-x\n+y\n",
+			wantErr: "パッチの適用元のファイルを読み込めませんでした",
+		},
+		{
+			name:    "変更箇所が途中で終わっている",
+			patch:   "--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n-one\n",
+			wantErr: "変更箇所が途中で終わっています",
+		},
+		{
+			name:    "親フォルダを指すパス",
+			patch:   "--- a/../a.txt\n+++ b/../a.txt\n@@ -1 +1 @@\n-one\n+1\n",
+			wantErr: "パッチのパス '../a.txt' が不正です",
+		},
+	}
+	base := fstest.MapFS{"a.txt": {Data: []byte("one\ntwo\n")}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Parse([]byte(tt.patch))
+			var result fs.FS
+			if err == nil {
+				result, err = Apply(base, files, time.Time{})
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q を含むエラー", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			for name, content := range tt.want {
+				if data, err := fs.ReadFile(result, name); err != nil || string(data) != content {
+					t.Errorf("%s の内容 = %q (%v), want %q", name, data, err, content)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_NoChanges(t *testing.T) {
+	if _, err := Parse([]byte("Subject: hello\n\nno diff here\n")); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("Parse() error = %v, want ErrNoChanges", err)
+	}
+}
+
+// fileNames は fsys に含まれるファイルのパスを返します
+func fileNames(t *testing.T, fsys fs.FS) []string {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			names = append(names, p)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+	return names
+}
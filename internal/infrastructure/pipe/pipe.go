@@ -0,0 +1,81 @@
+// Package pipe はファイルの内容を外部コマンドで加工する機能を提供します
+package pipe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"FolderScope/internal/infrastructure/logging"
+)
+
+const (
+	// DefaultTimeout は 1 ファイルあたりの外部コマンドの実行時間の上限です
+	DefaultTimeout = 10 * time.Second
+	// PathEnvVar は外部コマンドに処理中のファイルの相対パスを渡す環境変数名です
+	PathEnvVar = "FOLDERSCOPE_PATH"
+	// waitDelay はタイムアウト後に、外部コマンドが起動した子プロセスの終了を待つ時間です
+	waitDelay = time.Second
+)
+
+// Command はファイルの内容を標準入力から受け取り、加工した内容を標準出力に書き出す外部コマンドです
+type Command struct {
+	logger  logging.Logger
+	command string
+	timeout time.Duration
+}
+
+// NewCommand は新しい Command インスタンスを作成します。
+// command はシェル（Windows では cmd.exe）で実行するコマンドラインです。timeout が 0 以下の場合は DefaultTimeout を使用します
+func NewCommand(logger logging.Logger, command string, timeout time.Duration) *Command {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Command{logger: logger, command: command, timeout: timeout}
+}
+
+// Filter は content を外部コマンドに渡し、その標準出力を返します。
+// コマンドが 0 以外の終了コードで終了した場合やタイムアウトした場合は、警告を記録してエラーを返します
+func (c *Command) Filter(relPath string, content []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, c.command)
+	cmd.Env = append(os.Environ(), PathEnvVar+"="+relPath)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.WaitDelay = waitDelay
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("外部コマンドが %s 以内に終了しませんでした", c.timeout)
+	case err != nil:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("外部コマンドの実行に失敗しました: %s: %w", msg, err)
+		} else {
+			err = fmt.Errorf("外部コマンドの実行に失敗しました: %w", err)
+		}
+	}
+	if err != nil {
+		c.logger.Log("WARN", fmt.Sprintf("ファイル '%s' の外部コマンドによる処理に失敗", relPath), err)
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// shellCommand は command をシェルで実行する exec.Cmd を作成します
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,60 @@
+package pipe
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingLogger は記録された警告の数を数えるロガーです
+type countingLogger struct {
+	warnings int
+}
+
+func (l *countingLogger) Log(level, message string, err error, attrs ...any) {
+	if level == "WARN" {
+		l.warnings++
+	}
+}
+
+func TestCommand_Filter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX シェルのコマンドを使用するため Windows ではスキップします")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		timeout time.Duration
+		want    string
+		wantErr string
+	}{
+		{name: "標準出力を内容とする", command: "tr a-z A-Z", want: "SECRET=ABC\n"},
+		{name: "相対パスを環境変数で受け取る", command: `cat >/dev/null; printf '%s' "$FOLDERSCOPE_PATH"`, want: "dir/a.txt"},
+		{name: "終了コードが0以外", command: "echo broken >&2; exit 3", wantErr: "broken"},
+		{name: "タイムアウト", command: "sleep 5", timeout: 50 * time.Millisecond, wantErr: "終了しませんでした"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &countingLogger{}
+			got, err := NewCommand(logger, tt.command, tt.timeout).Filter("dir/a.txt", []byte("secret=abc\n"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Filter() error = %v, want %q を含むエラー", err, tt.wantErr)
+				}
+				if logger.warnings != 1 {
+					t.Errorf("警告の記録数 = %d, want 1", logger.warnings)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Filter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
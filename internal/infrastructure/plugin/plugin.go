@@ -0,0 +1,301 @@
+// Package plugin は、プラグインディレクトリから外部プロセスとして動作するプラグインを検出し、実行する機能を提供します。
+//
+// プラグインはプラグインディレクトリ直下のサブディレクトリ 1 つにつき 1 つで、マニフェスト（plugin.json）に
+// 名前・種類・実行するコマンドを記述します。プラグインとは標準入出力でデータをやり取りするため、任意の言語で実装できます。
+//   - formatter: レポートの JSONL 形式のエクスポートを標準入力から受け取り、独自形式のレポートを標準出力に書き出します
+//   - filter: ファイルの内容を標準入力から受け取り、加工した内容を標準出力に書き出します（相対パスは環境変数 FOLDERSCOPE_PATH）
+//   - source: 引数で受け取った調査対象（"s3://bucket/prefix" など）の内容を、tar 形式で標準出力に書き出します
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/infrastructure/archive"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/infrastructure/pipe"
+	"FolderScope/internal/infrastructure/state"
+)
+
+const (
+	// ManifestFileName は各プラグインのディレクトリに置くマニフェストのファイル名です
+	ManifestFileName = "plugin.json"
+	// DirName はユーザー設定ディレクトリ配下のプラグインディレクトリ名です
+	DirName = "plugins"
+	// waitDelay はタイムアウト後に、プラグインが起動した子プロセスの終了を待つ時間です
+	waitDelay = time.Second
+)
+
+// Kind はプラグインの種類です
+type Kind string
+
+const (
+	KindFormatter Kind = "formatter"
+	KindFilter    Kind = "filter"
+	KindSource    Kind = "source"
+)
+
+// Manifest はプラグインのマニフェスト（plugin.json）の内容です
+type Manifest struct {
+	// Name はプラグインの名前です。コマンドラインオプションでプラグインを指定する際に使用します
+	Name string `json:"name"`
+	// Kind はプラグインの種類です
+	Kind Kind `json:"kind"`
+	// Description はプラグインの説明です
+	Description string `json:"description,omitempty"`
+	// Command は実行するコマンドと引数です。"./" などのパス区切りを含む相対パスは、プラグインのディレクトリからの相対パスとして扱います
+	Command []string `json:"command"`
+	// Extension は formatter が出力するレポートの拡張子です（".csv" など）。省略した場合は ".txt" です
+	Extension string `json:"extension,omitempty"`
+	// Schemes は source が扱う調査対象のスキーム（"s3" など）です。"<スキーム>://" で始まる調査対象をこのプラグインで読み込みます
+	Schemes []string `json:"schemes,omitempty"`
+}
+
+// validate はマニフェストの必須項目を検証し、省略可能な項目に既定値を設定します
+func (m *Manifest) validate() error {
+	if strings.TrimSpace(m.Name) == "" || strings.ContainsAny(m.Name, " \t\r\n") {
+		return fmt.Errorf("name には空白を含まない名前を指定してください: %q", m.Name)
+	}
+	if len(m.Command) == 0 || m.Command[0] == "" {
+		return errors.New("command を指定してください")
+	}
+	switch m.Kind {
+	case KindFormatter:
+		if m.Extension == "" {
+			m.Extension = ".txt"
+		}
+		if !strings.HasPrefix(m.Extension, ".") {
+			m.Extension = "." + m.Extension
+		}
+	case KindFilter:
+	case KindSource:
+		if len(m.Schemes) == 0 {
+			return errors.New("source のプラグインには schemes を指定してください")
+		}
+	default:
+		return fmt.Errorf("未対応のプラグインの種類です: %s（formatter, filter, source のいずれかを指定してください）", m.Kind)
+	}
+	return nil
+}
+
+// Plugin は検出したプラグインです
+type Plugin struct {
+	Manifest
+	// Dir はプラグインのディレクトリです。コマンドはこのディレクトリを作業ディレクトリとして実行します
+	Dir string
+}
+
+// Registry は検出したプラグインの一覧です
+type Registry struct {
+	plugins map[string]*Plugin
+}
+
+// DefaultDir はユーザー設定ディレクトリ配下の既定のプラグインディレクトリを返します
+func DefaultDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("ユーザー設定ディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(configDir, state.AppDirName, DirName), nil
+}
+
+// Discover は dir 直下の各サブディレクトリからマニフェストを読み込み、プラグインを登録します。
+// dir が存在しない場合は、プラグインのない Registry を返します。
+// マニフェストが不正なプラグインや名前が重複するプラグインは、警告を記録して読み飛ばします
+func Discover(logger logging.Logger, dir string) (*Registry, error) {
+	r := &Registry{plugins: make(map[string]*Plugin)}
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap("プラグインディレクトリの読み込みに失敗しました", dir, err)
+	}
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, dirEntry.Name())
+		p, err := load(pluginDir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // マニフェストのないディレクトリはプラグインではない
+		}
+		if err != nil {
+			logger.Log("WARN", fmt.Sprintf("プラグイン '%s' を読み込めないためスキップ", pluginDir), err)
+			continue
+		}
+		if existing, ok := r.plugins[p.Name]; ok {
+			logger.Log("WARN", fmt.Sprintf("プラグイン名 '%s' が '%s' と重複するためスキップ", p.Name, existing.Dir), nil)
+			continue
+		}
+		r.plugins[p.Name] = p
+	}
+	return r, nil
+}
+
+// load は pluginDir のマニフェストを読み込みます
+func load(pluginDir string) (*Plugin, error) {
+	data, err := os.ReadFile(filepath.Join(pluginDir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("マニフェストの解析に失敗しました: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("マニフェストが不正です: %w", err)
+	}
+	return &Plugin{Manifest: m, Dir: pluginDir}, nil
+}
+
+// Plugins は登録されているプラグインを名前の順で返します
+func (r *Registry) Plugins() []*Plugin {
+	plugins := make([]*Plugin, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Lookup は名前が name で種類が kind のプラグインを返します
+func (r *Registry) Lookup(kind Kind, name string) (*Plugin, error) {
+	p, ok := r.plugins[name]
+	if !ok || p.Kind != kind {
+		return nil, fmt.Errorf("%s のプラグインが見つかりません: %s", kind, name)
+	}
+	return p, nil
+}
+
+// SourceFor は調査対象 location のスキーム（"<スキーム>://" の部分）を扱う source のプラグインを返します。
+// 該当するプラグインがない場合は nil を返します
+func (r *Registry) SourceFor(location string) *Plugin {
+	scheme, _, ok := strings.Cut(location, "://")
+	if !ok {
+		return nil
+	}
+	for _, p := range r.Plugins() {
+		if p.Kind != KindSource {
+			continue
+		}
+		for _, s := range p.Schemes {
+			if strings.EqualFold(s, scheme) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// command はプラグインのコマンドに args を追加して実行する exec.Cmd を作成します
+func (p *Plugin) command(ctx context.Context, args ...string) *exec.Cmd {
+	name := p.Command[0]
+	if !filepath.IsAbs(name) && strings.ContainsAny(name, `/\`) {
+		name = filepath.Join(p.Dir, name)
+	}
+	cmd := exec.CommandContext(ctx, name, append(append([]string{}, p.Command[1:]...), args...)...)
+	cmd.Dir = p.Dir
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// run は cmd を実行します。失敗した場合は、標準エラー出力の内容を含むエラーを返します
+func (p *Plugin) run(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("プラグイン '%s' の実行に失敗しました: %s: %w", p.Name, msg, err)
+		}
+		return fmt.Errorf("プラグイン '%s' の実行に失敗しました: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Format はレポートの JSONL 形式のエクスポートを formatter のプラグインに渡し、その出力を w に書き込みます。
+// export はプラグインの標準入力に JSONL 形式のエクスポートを書き込む関数です
+func (p *Plugin) Format(ctx context.Context, w io.Writer, export func(io.Writer) error) error {
+	pr, pw := io.Pipe()
+	exported := make(chan error, 1)
+	go func() {
+		err := export(pw)
+		pw.CloseWithError(err)
+		exported <- err
+	}()
+
+	cmd := p.command(ctx)
+	cmd.Stdin = pr
+	cmd.Stdout = w
+	runErr := p.run(cmd)
+	// プラグインが入力を読み終える前に終了した場合でも、書き込み側を終了させる
+	pr.Close()
+	exportErr := <-exported
+	if runErr != nil {
+		return runErr
+	}
+	if exportErr != nil {
+		return fmt.Errorf("プラグインへのエクスポートに失敗しました: %w", exportErr)
+	}
+	return nil
+}
+
+// Open は source のプラグインで調査対象 location を読み込み、プラグインが出力した tar をアーカイブとして返します
+func (p *Plugin) Open(ctx context.Context, location string) (*archive.Archive, error) {
+	var stdout bytes.Buffer
+	cmd := p.command(ctx, location)
+	cmd.Stdout = &stdout
+	if err := p.run(cmd); err != nil {
+		return nil, err
+	}
+	return archive.ReadTar(&stdout, location)
+}
+
+// Filter は filter のプラグインでファイルの内容を加工します
+type Filter struct {
+	plugin  *Plugin
+	logger  logging.Logger
+	timeout time.Duration
+}
+
+// NewFilter は p でファイルの内容を加工する Filter を作成します。timeout が 0 以下の場合は pipe.DefaultTimeout を使用します
+func NewFilter(logger logging.Logger, p *Plugin, timeout time.Duration) *Filter {
+	if timeout <= 0 {
+		timeout = pipe.DefaultTimeout
+	}
+	return &Filter{plugin: p, logger: logger, timeout: timeout}
+}
+
+// Filter は content をプラグインに渡し、その標準出力を返します。
+// プラグインが 0 以外の終了コードで終了した場合やタイムアウトした場合は、警告を記録してエラーを返します
+func (f *Filter) Filter(relPath string, content []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := f.plugin.command(ctx)
+	cmd.Env = append(os.Environ(), pipe.PathEnvVar+"="+relPath)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	err := f.plugin.run(cmd)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("プラグイン '%s' が %s 以内に終了しませんでした", f.plugin.Name, f.timeout)
+	}
+	if err != nil {
+		f.logger.Log("WARN", fmt.Sprintf("ファイル '%s' のプラグインによる処理に失敗", relPath), err)
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,162 @@
+package plugin
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingLogger は記録された警告の数を数えるロガーです
+type countingLogger struct {
+	warnings int
+}
+
+func (l *countingLogger) Log(level, message string, err error, attrs ...any) {
+	if level == "WARN" {
+		l.warnings++
+	}
+}
+
+// writePlugin は dir/name にマニフェストを作成します
+func writePlugin(t *testing.T, dir, name, manifest string) string {
+	t.Helper()
+	pluginDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatalf("ディレクトリの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, ManifestFileName), []byte(manifest), 0644); err != nil {
+		t.Fatalf("マニフェストの作成に失敗: %v", err)
+	}
+	return pluginDir
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "csv", `{"name":"csv","kind":"formatter","command":["./csv.sh"],"extension":"csv"}`)
+	writePlugin(t, dir, "upper", `{"name":"upper","kind":"filter","command":["tr","a-z","A-Z"]}`)
+	writePlugin(t, dir, "s3", `{"name":"s3","kind":"source","command":["./fetch"],"schemes":["s3"]}`)
+	writePlugin(t, dir, "broken", `{"name":"broken",`)
+	writePlugin(t, dir, "unknown", `{"name":"unknown","kind":"exporter","command":["x"]}`)
+	writePlugin(t, dir, "duplicate", `{"name":"upper","kind":"filter","command":["cat"]}`)
+	if err := os.MkdirAll(filepath.Join(dir, "not-a-plugin"), 0755); err != nil {
+		t.Fatalf("ディレクトリの作成に失敗: %v", err)
+	}
+
+	logger := &countingLogger{}
+	r, err := Discover(logger, dir)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	var names []string
+	for _, p := range r.Plugins() {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "csv,s3,upper" {
+		t.Errorf("検出したプラグイン = %s, want csv,s3,upper", got)
+	}
+	// 不正なマニフェスト 2 件と名前の重複 1 件
+	if logger.warnings != 3 {
+		t.Errorf("警告の記録数 = %d, want 3", logger.warnings)
+	}
+
+	csv, err := r.Lookup(KindFormatter, "csv")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if csv.Extension != ".csv" {
+		t.Errorf("Extension = %q, want .csv", csv.Extension)
+	}
+	if _, err := r.Lookup(KindFormatter, "upper"); err == nil {
+		t.Error("種類の異なるプラグインを指定した場合にエラーになりません")
+	}
+	if p := r.SourceFor("S3://bucket/prefix"); p == nil || p.Name != "s3" {
+		t.Errorf("SourceFor() = %v, want s3", p)
+	}
+	if p := r.SourceFor("/local/dir"); p != nil {
+		t.Errorf("SourceFor() = %v, want nil", p)
+	}
+}
+
+func TestDiscover_MissingDir(t *testing.T) {
+	r, err := Discover(&countingLogger{}, filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(r.Plugins()) != 0 {
+		t.Errorf("プラグインのないディレクトリから %d 個検出しました", len(r.Plugins()))
+	}
+}
+
+func TestPlugin_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX シェルのコマンドを使用するため Windows ではスキップします")
+	}
+	dir := t.TempDir()
+
+	t.Run("formatter", func(t *testing.T) {
+		p := &Plugin{Manifest: Manifest{Name: "count", Kind: KindFormatter, Command: []string{"wc", "-l"}}, Dir: dir}
+		var out strings.Builder
+		err := p.Format(context.Background(), &out, func(w io.Writer) error {
+			_, err := io.WriteString(w, "{\"type\":\"stats\"}\n{\"type\":\"entry\"}\n")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		if got := strings.TrimSpace(out.String()); got != "2" {
+			t.Errorf("Format() の出力 = %q, want 2", got)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		p := &Plugin{Manifest: Manifest{Name: "path", Kind: KindFilter, Command: []string{"sh", "-c", `tr a-z A-Z; printf '%s' "$FOLDERSCOPE_PATH"`}}, Dir: dir}
+		got, err := NewFilter(&countingLogger{}, p, 0).Filter("dir/a.txt", []byte("abc\n"))
+		if err != nil {
+			t.Fatalf("Filter() error = %v", err)
+		}
+		if string(got) != "ABC\ndir/a.txt" {
+			t.Errorf("Filter() = %q, want %q", got, "ABC\ndir/a.txt")
+		}
+	})
+
+	t.Run("filterの失敗", func(t *testing.T) {
+		p := &Plugin{Manifest: Manifest{Name: "slow", Kind: KindFilter, Command: []string{"sleep", "5"}}, Dir: dir}
+		logger := &countingLogger{}
+		if _, err := NewFilter(logger, p, 50*time.Millisecond).Filter("a.txt", nil); err == nil || !strings.Contains(err.Error(), "終了しませんでした") {
+			t.Errorf("Filter() error = %v, want タイムアウトのエラー", err)
+		}
+		if logger.warnings != 1 {
+			t.Errorf("警告の記録数 = %d, want 1", logger.warnings)
+		}
+	})
+
+	t.Run("source", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(dir, "data.tar"))
+		if err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+		tw := tar.NewWriter(f)
+		tw.WriteHeader(&tar.Header{Name: "sub/a.txt", Mode: 0644, Size: 5, Typeflag: tar.TypeReg})
+		tw.Write([]byte("alpha"))
+		tw.Close()
+		f.Close()
+
+		// 調査対象は最後の引数で渡される
+		p := &Plugin{Manifest: Manifest{Name: "fetch", Kind: KindSource, Command: []string{"sh", "-c", `test "$0" = "s3://bucket" && cat data.tar`}}, Dir: dir}
+		a, err := p.Open(context.Background(), "s3://bucket")
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		data, err := fs.ReadFile(a.FS(), "sub/a.txt")
+		if err != nil || string(data) != "alpha" {
+			t.Errorf("ReadFile() = %q, %v, want alpha", data, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Package state は実行をまたいで保持するアプリケーションの状態（前回の選択内容など）の永続化を提供します
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// AppDirName はユーザー設定ディレクトリ配下に作成するディレクトリ名です
+	AppDirName = "folderscope"
+	// StateFileName は状態ファイルの名前です
+	StateFileName = "state.json"
+	// MaxRecentDirs は記録する最近使用したフォルダの最大数です
+	MaxRecentDirs = 5
+)
+
+// State は永続化されるアプリケーションの状態です
+type State struct {
+	// LastOutputDir は前回レポートを出力したディレクトリです
+	LastOutputDir string `json:"lastOutputDir,omitempty"`
+	// RecentSourceDirs は最近使用した調査対象フォルダです（新しい順）
+	RecentSourceDirs []string `json:"recentSourceDirs,omitempty"`
+	// RecentOutputDirs は最近使用した出力先フォルダです（新しい順）
+	RecentOutputDirs []string `json:"recentOutputDirs,omitempty"`
+}
+
+// RecordSelection は今回使用した調査対象フォルダと出力先フォルダを記録します
+func (st *State) RecordSelection(sourceDir, outputDir string) {
+	st.LastOutputDir = outputDir
+	st.RecentSourceDirs = pushRecent(st.RecentSourceDirs, sourceDir)
+	st.RecentOutputDirs = pushRecent(st.RecentOutputDirs, outputDir)
+}
+
+// pushRecent は dir を先頭に追加した履歴を返します。
+// 同じフォルダがすでに含まれている場合は先頭に移動し、MaxRecentDirs を超えた古いものは削除します
+func pushRecent(recent []string, dir string) []string {
+	if dir == "" {
+		return recent
+	}
+	dir = filepath.Clean(dir)
+	result := make([]string, 0, MaxRecentDirs)
+	result = append(result, dir)
+	for _, d := range recent {
+		if len(result) == MaxRecentDirs {
+			break
+		}
+		if filepath.Clean(d) != dir {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Store は状態ファイルの読み書きを行います
+type Store struct {
+	path string
+}
+
+// DefaultPath はユーザー設定ディレクトリ配下の状態ファイルのパス（例: ~/.config/folderscope/state.json）を返します
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("ユーザー設定ディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(configDir, AppDirName, StateFileName), nil
+}
+
+// NewStore は指定されたパスの状態ファイルを扱う Store インスタンスを作成します
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path は状態ファイルのパスを返します
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load は状態ファイルを読み込みます。ファイルが存在しない場合は空の状態を返します
+func (s *Store) Load() (*State, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("状態ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("状態ファイルの解析に失敗しました: %w", err)
+	}
+	return &st, nil
+}
+
+// Save は状態ファイルを書き込みます。
+// 書き込み途中で中断されても既存のファイルが壊れないよう、一時ファイルに書き込んでから置き換えます
+func (s *Store) Save(st *State) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("状態ファイルのディレクトリ作成に失敗しました: %w", err)
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("状態のエンコードに失敗しました: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), StateFileName+".tmp*")
+	if err != nil {
+		return fmt.Errorf("状態ファイルの書き込みに失敗しました: %w", err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("状態ファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("状態ファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmpName, s.path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("状態ファイルの置き換えに失敗しました: %w", err)
+	}
+	return nil
+}
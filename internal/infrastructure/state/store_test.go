@@ -0,0 +1,77 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_LoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", StateFileName)
+	store := NewStore(path)
+
+	// ファイルが存在しない場合は空の状態
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if st.LastOutputDir != "" {
+		t.Errorf("空の状態が返されるべきです: %+v", st)
+	}
+
+	st.LastOutputDir = "/tmp/reports"
+	if err := store.Save(st); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := NewStore(path).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.LastOutputDir != "/tmp/reports" {
+		t.Errorf("保存した状態が読み込まれていません: %+v", loaded)
+	}
+
+	// 一時ファイルが残っていないこと
+	files, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ディレクトリの読み込みに失敗: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("状態ファイル以外のファイルが残っています: %v", files)
+	}
+}
+
+func TestStore_LoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFileName)
+	if err := os.WriteFile(path, []byte("{invalid"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	if _, err := NewStore(path).Load(); err == nil {
+		t.Error("不正な状態ファイルでエラーが返されるべきです")
+	}
+}
+
+func TestState_RecordSelection(t *testing.T) {
+	st := &State{}
+	for _, dir := range []string{"/a", "/b", "/c", "/d", "/e", "/f"} {
+		st.RecordSelection(dir, "/out")
+	}
+	st.RecordSelection("/d/", "/out2")
+
+	want := []string{"/d", "/f", "/e", "/c", "/b"}
+	if len(st.RecentSourceDirs) != len(want) {
+		t.Fatalf("RecentSourceDirs = %v, want %v", st.RecentSourceDirs, want)
+	}
+	for i := range want {
+		if st.RecentSourceDirs[i] != want[i] {
+			t.Fatalf("RecentSourceDirs = %v, want %v", st.RecentSourceDirs, want)
+		}
+	}
+	if len(st.RecentOutputDirs) != 2 || st.RecentOutputDirs[0] != "/out2" || st.RecentOutputDirs[1] != "/out" {
+		t.Errorf("RecentOutputDirs = %v", st.RecentOutputDirs)
+	}
+	if st.LastOutputDir != "/out2" {
+		t.Errorf("LastOutputDir = %q", st.LastOutputDir)
+	}
+}
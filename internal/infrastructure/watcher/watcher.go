@@ -0,0 +1,168 @@
+// Package watcher はディレクトリツリーの変更監視を提供します
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"FolderScope/internal/infrastructure/logging"
+)
+
+// DefaultDebounce は、連続した変更をまとめて 1 回の通知にするための待ち時間です。
+// エディタの保存やファイルのコピーでは短時間に多数のイベントが発生するため、落ち着くまで待ってから通知します
+const DefaultDebounce = 500 * time.Millisecond
+
+// IgnoreFunc はファイル名またはディレクトリ名を監視対象から除外するかどうかを判定します
+type IgnoreFunc func(name string, isDir bool) bool
+
+// Watcher はルートディレクトリ配下のすべてのディレクトリを監視し、変更されたパスをまとめて通知します。
+// fsnotify はサブディレクトリを再帰的に監視しないため、作成されたディレクトリも監視対象に追加します
+type Watcher struct {
+	logger   logging.Logger
+	root     string
+	ignore   IgnoreFunc
+	debounce time.Duration
+	fsw      *fsnotify.Watcher
+}
+
+// New は rootDir 配下を監視する Watcher を作成します。ignore が nil の場合はすべてのパスを監視します
+func New(logger logging.Logger, rootDir string, ignore IgnoreFunc) (*Watcher, error) {
+	absRootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("監視対象の絶対パス取得に失敗しました: %w", err)
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("ファイル監視の開始に失敗しました: %w", err)
+	}
+	if ignore == nil {
+		ignore = func(string, bool) bool { return false }
+	}
+	w := &Watcher{
+		logger:   logger,
+		root:     absRootDir,
+		ignore:   ignore,
+		debounce: DefaultDebounce,
+		fsw:      fsw,
+	}
+	if err := w.addTree(absRootDir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// SetDebounce は変更をまとめる待ち時間を変更します
+func (w *Watcher) SetDebounce(d time.Duration) {
+	w.debounce = d
+}
+
+// Close は監視を終了します
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// addTree は dir とその配下のディレクトリを監視対象に追加します
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			w.logger.Log("WARN", fmt.Sprintf("パス '%s' を監視対象に追加できません", path), walkErr)
+			if path == dir {
+				return walkErr
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && w.ignore(d.Name(), true) {
+			return fs.SkipDir
+		}
+		if err := w.fsw.Add(path); err != nil {
+			if path == w.root {
+				return fmt.Errorf("ディレクトリの監視に失敗しました: %w", err)
+			}
+			w.logger.Log("WARN", fmt.Sprintf("ディレクトリ '%s' の監視に失敗", path), err)
+		}
+		return nil
+	})
+}
+
+// isIgnored は、path 自身またはルートまでの途中のディレクトリが無視対象かどうかを返します
+func (w *Watcher) isIgnored(path string, isDir bool) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, part := range parts {
+		if w.ignore(part, isDir || i < len(parts)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run は ctx がキャンセルされるまで変更を監視し、debounce の間に発生した変更をまとめて onChange に渡します。
+// onChange にはルートからの相対パス（'/' 区切り）が重複なく昇順で渡されます
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) error {
+	pending := make(map[string]struct{})
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			isDir := false
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					isDir = true
+				}
+			}
+			if w.isIgnored(event.Name, isDir) {
+				continue
+			}
+			// 新しく作成されたディレクトリは配下も含めて監視対象に追加する
+			if isDir {
+				if err := w.addTree(event.Name); err != nil {
+					w.logger.Log("WARN", fmt.Sprintf("ディレクトリ '%s' を監視対象に追加できません", event.Name), err)
+				}
+			}
+			rel, err := filepath.Rel(w.root, event.Name)
+			if err != nil {
+				continue
+			}
+			pending[filepath.ToSlash(rel)] = struct{}{}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Log("WARN", "ファイル監視でエラーが発生しました", err)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			changed := make([]string, 0, len(pending))
+			for rel := range pending {
+				changed = append(changed, rel)
+			}
+			sort.Strings(changed)
+			pending = make(map[string]struct{})
+			onChange(changed)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLogger はテスト用のロガーです
+type mockLogger struct{}
+
+func (m *mockLogger) Log(level, message string, err error, attrs ...any) {}
+
+// waitChange は変更通知を待ち、タイムアウトした場合はテストを失敗させます
+func waitChange(t *testing.T, changes <-chan []string) []string {
+	t.Helper()
+	select {
+	case changed := <-changes:
+		return changed
+	case <-time.After(5 * time.Second):
+		t.Fatal("変更が通知されませんでした")
+		return nil
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+
+	w, err := New(&mockLogger{}, root, func(name string, isDir bool) bool { return name == ".git" })
+	assert.NoError(t, err)
+	defer w.Close()
+	w.SetDebounce(50 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(changed []string) { changes <- changed })
+	}()
+
+	// 無視されたディレクトリ内の変更は通知されず、通常のファイルの変更のみが通知される
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("x"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("a"), 0644))
+	assert.Equal(t, []string{"a.txt"}, waitChange(t, changes))
+
+	// 新しく作成されたサブディレクトリ内の変更も通知される
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0755))
+	assert.Equal(t, []string{"sub"}, waitChange(t, changes))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "sub", "b.txt"), []byte("b"), 0644))
+	assert.Equal(t, []string{"sub/b.txt"}, waitChange(t, changes))
+
+	cancel()
+	assert.NoError(t, <-done)
+}
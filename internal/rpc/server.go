@@ -0,0 +1,247 @@
+// Package rpc はエディタ拡張（VS Code / Neovim など）向けの stdio JSON-RPC サーバーを提供します
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/infrastructure/filesystem"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+// JSON-RPC 2.0 の標準エラーコード
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeRequestCancelled はリクエストがキャンセルされたことを示します（LSP の RequestCancelled と同じ値）
+	CodeRequestCancelled = -32800
+)
+
+// MaxMessageSize はリクエスト 1 件の本文の上限（バイト数）です。
+// Content-Length がこれを超えるメッセージは読み込まずに読み飛ばし、エラーのレスポンスを返します
+const MaxMessageSize = 16 << 20
+
+// errMessageTooLarge は Content-Length が MaxMessageSize を超えていることを示します
+var errMessageTooLarge = fmt.Errorf("メッセージが大きすぎます（上限: %d バイト）", MaxMessageSize)
+
+// メソッド名
+const (
+	MethodInitialize = "initialize"
+	MethodSnapshot   = "folderscope/snapshot"
+	MethodShutdown   = "shutdown"
+	MethodExit       = "exit"
+)
+
+// Request は JSON-RPC 2.0 のリクエストです
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response は JSON-RPC 2.0 のレスポンスです
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error は JSON-RPC 2.0 のエラーオブジェクトです
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// SnapshotParams は folderscope/snapshot メソッドのパラメータです
+type SnapshotParams struct {
+	// Root はスナップショットを取得するワークスペースのディレクトリです
+	Root string `json:"root"`
+	// Options はスキャナーに渡すオプションです
+	Options filesystem.ScannerOptions `json:"options"`
+}
+
+// SnapshotResult は folderscope/snapshot メソッドの結果です
+type SnapshotResult struct {
+	// Report は生成されたレポート本文です
+	Report string `json:"report"`
+	// Entries はレポートに含まれるエントリ数です
+	Entries int `json:"entries"`
+}
+
+// ServerInfo は initialize メソッドの結果です
+type ServerInfo struct {
+	Name    string   `json:"name"`
+	Methods []string `json:"methods"`
+}
+
+// Server は stdio 上で JSON-RPC リクエストを処理するサーバーです。
+// メッセージは LSP と同じ Content-Length ヘッダー形式でフレーミングされます。
+type Server struct {
+	logger    logging.Logger
+	generator *report.Generator
+	writeMu   sync.Mutex
+}
+
+// NewServer は新しい Server インスタンスを作成します
+func NewServer(logger logging.Logger, generator *report.Generator) *Server {
+	return &Server{
+		logger:    logger,
+		generator: generator,
+	}
+}
+
+// Serve は r からリクエストを読み込み、w にレスポンスを書き込みます。
+// exit 通知を受け取るか入力が終端に達すると nil を返します。
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		payload, err := readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, errMessageTooLarge) {
+			s.writeResponse(w, Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeInvalidRequest, Message: err.Error()}})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("メッセージの読み込みに失敗しました: %w", err)
+		}
+
+		var req Request
+		if err := json.Unmarshal(payload, &req); err != nil {
+			s.writeResponse(w, Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: err.Error()}})
+			continue
+		}
+		if req.Method == MethodExit {
+			return nil
+		}
+
+		result, rpcErr := s.dispatch(ctx, req)
+		if len(req.ID) == 0 {
+			// 通知にはレスポンスを返さない
+			continue
+		}
+		s.writeResponse(w, Response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+	}
+}
+
+// dispatch はメソッド名に応じてリクエストを処理します
+func (s *Server) dispatch(ctx context.Context, req Request) (interface{}, *Error) {
+	switch req.Method {
+	case MethodInitialize:
+		return ServerInfo{
+			Name:    "FolderScope",
+			Methods: []string{MethodSnapshot, MethodShutdown, MethodExit},
+		}, nil
+	case MethodShutdown:
+		return struct{}{}, nil
+	case MethodSnapshot:
+		// includeHidden を省略した場合は、従来どおり隠しファイル・隠しフォルダを含める
+		params := SnapshotParams{Options: filesystem.ScannerOptions{IncludeHidden: true}}
+		// JSON-RPC では params を省略できるため、省略した場合は既定値のまま処理する
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("パラメータが不正です: %v", err)}
+			}
+		}
+		return s.snapshot(ctx, params)
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("未対応のメソッドです: %s", req.Method)}
+	}
+}
+
+// snapshot は指定されたワークスペースをスキャンし、レポート本文を返します
+func (s *Server) snapshot(ctx context.Context, params SnapshotParams) (interface{}, *Error) {
+	scanner, err := filesystem.NewScannerWithOptions(s.logger, params.Options)
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	if err := scanner.ValidateDirectoryPath(params.Root); err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+
+	entries, err := scanner.Scan(ctx, params.Root)
+	if errors.Is(err, apperrors.ErrCancelled) {
+		return nil, &Error{Code: CodeRequestCancelled, Message: err.Error()}
+	}
+	if err != nil {
+		s.logger.Log("ERROR", "スナップショットのスキャンに失敗", err)
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+
+	var buf bytes.Buffer
+	if err := s.generator.WriteReport(&buf, entries); err != nil {
+		s.logger.Log("ERROR", "スナップショットの生成に失敗", err)
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+	s.logger.Log("INFO", fmt.Sprintf("スナップショットを生成しました: %s", params.Root), nil)
+
+	return SnapshotResult{Report: buf.String(), Entries: len(entries)}, nil
+}
+
+// writeResponse はレスポンスをフレーミングして書き込みます
+func (s *Server) writeResponse(w io.Writer, resp Response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		s.logger.Log("ERROR", "レスポンスのエンコードに失敗", err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		s.logger.Log("ERROR", "レスポンスの書き込みに失敗", err)
+	}
+}
+
+// readMessage は Content-Length ヘッダー付きのメッセージを 1 件読み込みます
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("Content-Length ヘッダーが不正です: %q", header.Get("Content-Length"))
+	}
+
+	if length > MaxMessageSize {
+		// 続くメッセージを読めるよう、本文はメモリに確保せずに読み飛ばす
+		if _, err := io.CopyN(io.Discard, r, int64(length)); err != nil {
+			return nil, err
+		}
+		return nil, errMessageTooLarge
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"FolderScope/internal/usecase/report"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Log(level, message string, err error, attrs ...any) {}
+
+func frame(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("JSONエンコードに失敗: %v", err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+func readResponses(t *testing.T, r io.Reader) []Response {
+	t.Helper()
+	var responses []Response
+	reader := bufio.NewReader(r)
+	for {
+		payload, err := readMessage(reader)
+		if err == io.EOF {
+			return responses
+		}
+		if err != nil {
+			t.Fatalf("レスポンスの読み込みに失敗: %v", err)
+		}
+		var resp Response
+		if err := json.Unmarshal(payload, &resp); err != nil {
+			t.Fatalf("レスポンスの解析に失敗: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func TestServer_Serve(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "debug.log"), []byte("noise"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+
+	input := strings.Join([]string{
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": MethodInitialize}),
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": MethodSnapshot, "params": map[string]interface{}{
+			"root":    root,
+			"options": map[string]interface{}{"ignorePatterns": []string{"*.log"}},
+		}}),
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "$/cancelRequest"}),
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 3, "method": "unknown"}),
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 4, "method": MethodSnapshot, "params": map[string]interface{}{"root": filepath.Join(root, "missing")}}),
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": MethodExit}),
+	}, "")
+
+	var out strings.Builder
+	server := NewServer(&mockLogger{}, report.NewGenerator())
+	if err := server.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	responses := readResponses(t, strings.NewReader(out.String()))
+	if len(responses) != 4 {
+		t.Fatalf("レスポンス数が不正: got %d, want 4", len(responses))
+	}
+
+	if responses[0].Error != nil {
+		t.Errorf("initialize がエラーを返しました: %v", responses[0].Error)
+	}
+
+	snapshot, ok := responses[1].Result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("snapshot の結果が不正: %#v", responses[1])
+	}
+	reportText, _ := snapshot["report"].(string)
+	if !strings.Contains(reportText, "package main") {
+		t.Errorf("レポートにファイル内容が含まれていません:\n%s", reportText)
+	}
+	if strings.Contains(reportText, "debug.log") {
+		t.Errorf("無視パターンが適用されていません:\n%s", reportText)
+	}
+
+	if responses[2].Error == nil || responses[2].Error.Code != CodeMethodNotFound {
+		t.Errorf("未対応メソッドのエラーが不正: %#v", responses[2].Error)
+	}
+	if responses[3].Error == nil || responses[3].Error.Code != CodeInvalidParams {
+		t.Errorf("存在しないルートのエラーが不正: %#v", responses[3].Error)
+	}
+}
+
+func TestServer_Serve_MessageTooLarge(t *testing.T) {
+	input := fmt.Sprintf("Content-Length: %d\r\n\r\n%s", MaxMessageSize+1, strings.Repeat(" ", MaxMessageSize+1)) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": MethodInitialize}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": MethodExit})
+
+	var out strings.Builder
+	server := NewServer(&mockLogger{}, report.NewGenerator())
+	if err := server.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	responses := readResponses(t, strings.NewReader(out.String()))
+	if len(responses) != 2 {
+		t.Fatalf("レスポンス数が不正: got %d, want 2", len(responses))
+	}
+	if responses[0].Error == nil || responses[0].Error.Code != CodeInvalidRequest {
+		t.Errorf("上限を超えたメッセージのエラーが不正: %#v", responses[0].Error)
+	}
+	if responses[1].Error != nil {
+		t.Errorf("後続の initialize がエラーを返しました: %v", responses[1].Error)
+	}
+}
+
+func TestServer_Serve_SnapshotWithoutParams(t *testing.T) {
+	input := frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": MethodSnapshot}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": MethodExit})
+
+	var out strings.Builder
+	server := NewServer(&mockLogger{}, report.NewGenerator())
+	if err := server.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	responses := readResponses(t, strings.NewReader(out.String()))
+	if len(responses) != 1 {
+		t.Fatalf("レスポンス数が不正: got %d, want 1", len(responses))
+	}
+	// params を省略してもデコードエラーにはならず、ルート未指定として扱われる
+	if responses[0].Error == nil || responses[0].Error.Code != CodeInvalidParams || strings.Contains(responses[0].Error.Message, "パラメータが不正です") {
+		t.Errorf("params 省略時のエラーが不正: %#v", responses[0].Error)
+	}
+}
+
+func TestServer_Serve_SnapshotInvalidRegexp(t *testing.T) {
+	input := frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": MethodSnapshot, "params": map[string]interface{}{
+		"root":    t.TempDir(),
+		"options": map[string]interface{}{"excludeRegexps": []string{"("}},
+	}}) + frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": MethodExit})
+
+	var out strings.Builder
+	server := NewServer(&mockLogger{}, report.NewGenerator())
+	if err := server.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	responses := readResponses(t, strings.NewReader(out.String()))
+	if len(responses) != 1 {
+		t.Fatalf("レスポンス数が不正: got %d, want 1", len(responses))
+	}
+	// 不正な正規表現を無視してスキャンせず、パラメータの誤りとして返す
+	if responses[0].Error == nil || responses[0].Error.Code != CodeInvalidParams || !strings.Contains(responses[0].Error.Message, "(") {
+		t.Errorf("不正な正規表現のエラーが不正: %#v", responses[0].Error)
+	}
+}
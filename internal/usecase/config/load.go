@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"FolderScope/internal/domain/rules"
+	"FolderScope/internal/usecase/report"
+)
+
+// RulesSchema はパスごとのルールを定義するファイル（-rules）の形式です
+var RulesSchema = &Schema{Kind: KindObject, Fields: map[string]*Schema{
+	"rules": {Kind: KindArray, Items: &Schema{Kind: KindObject, Required: []string{"path", "mode"}, Fields: map[string]*Schema{
+		"path": {Kind: KindString, Check: checkString(rules.ValidatePattern)},
+		"mode": {Kind: KindString, Check: checkString(func(s string) error {
+			_, err := rules.ParseMode(s)
+			return err
+		})},
+	}}},
+}}
+
+// MaskRulesSchema はファイル内容のマスクのルールを定義するファイル（-mask-rules）の形式です
+var MaskRulesSchema = &Schema{Kind: KindObject, Fields: map[string]*Schema{
+	"rules": {Kind: KindArray, Items: &Schema{
+		Kind: KindObject,
+		Fields: map[string]*Schema{
+			"name": {Kind: KindString},
+			"pattern": {Kind: KindString, Check: checkString(func(s string) error {
+				if _, err := regexp.Compile(s); err != nil {
+					return fmt.Errorf("正規表現が不正です: %w", err)
+				}
+				return nil
+			})},
+			"replacement": {Kind: KindString},
+			"preset": {Kind: KindString, Check: checkString(func(s string) error {
+				if _, ok := report.PresetMaskRules[strings.ToLower(strings.TrimSpace(s))]; !ok {
+					return fmt.Errorf("未対応の組み込みルールです: %s（%s のいずれかを指定してください）", s, strings.Join(report.PresetMaskRuleNames(), ", "))
+				}
+				return nil
+			})},
+		},
+		Check: func(value any) error {
+			rule := value.(map[string]any)
+			if _, ok := rule["preset"]; ok {
+				return nil
+			}
+			if rule["name"] == nil || rule["pattern"] == nil {
+				return fmt.Errorf("name と pattern（または preset）を指定してください")
+			}
+			return nil
+		},
+	}},
+}}
+
+// checkString は文字列の値を check で検証する Schema.Check を返します
+func checkString(check func(s string) error) func(value any) error {
+	return func(value any) error {
+		return check(value.(string))
+	}
+}
+
+// LoadRules はパスごとのルールを定義するファイルを読み込み、RulesSchema で検証します
+func LoadRules(path string) ([]rules.Rule, error) {
+	var file rules.File
+	if err := Load(path, RulesSchema, &file); err != nil {
+		return nil, err
+	}
+	return file.Rules, nil
+}
+
+// LoadMaskRules はファイル内容のマスクのルールを定義するファイルを読み込み、MaskRulesSchema で検証します
+func LoadMaskRules(path string) ([]report.MaskRule, error) {
+	var file report.MaskRuleFile
+	if err := Load(path, MaskRulesSchema, &file); err != nil {
+		return nil, err
+	}
+	return file.Rules, nil
+}
+
+// Load は設定ファイルを読み込み、schema で検証したうえで out に格納します。
+// 拡張子が .yaml・.yml の場合は YAML、それ以外は JSON として解析します。
+// 形式に誤りがある場合は、すべての誤りを含む *ValidationError を返します
+func Load(path string, schema *Schema, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	value, err := decode(data, path)
+	if err != nil {
+		return fmt.Errorf("設定ファイル %s の解析に失敗しました: %w", path, err)
+	}
+	if issues := schema.Validate(value); len(issues) > 0 {
+		return &ValidationError{File: path, Issues: issues}
+	}
+	// 検証済みの値を JSON に変換し、設定の構造体に格納する
+	normalized, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(normalized, out)
+	}
+	if err != nil {
+		return fmt.Errorf("設定ファイル %s の解析に失敗しました: %w", path, err)
+	}
+	return nil
+}
+
+// decode は設定ファイルの内容を、オブジェクトを map[string]any、配列を []any とする値に解析します
+func decode(data []byte, path string) (any, error) {
+	var value any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+	default:
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/rules"
+	"FolderScope/internal/usecase/report"
+)
+
+// writeConfig はテスト用の設定ファイルを一時フォルダに作成し、そのパスを返します
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRules(t *testing.T) {
+	want := []rules.Rule{{Path: "docs/**", Mode: "full"}, {Path: "*.min.js", Mode: "exclude"}}
+	for name, content := range map[string]string{
+		"rules.json": `{"rules": [{"path": "docs/**", "mode": "full"}, {"path": "*.min.js", "mode": "exclude"}]}`,
+		"rules.yaml": "rules:\n  - path: docs/**\n    mode: full\n  - path: \"*.min.js\"\n    mode: exclude\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadRules(writeConfig(t, name, content))
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadRules() = %v, %v, want %v", got, err, want)
+			}
+		})
+	}
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	path := writeConfig(t, "rules.yaml", `rules:
+  - path: docs/**
+    mode: full
+  - path: "docs/[a"
+    mode: full
+  - path: vendor/**
+    mdoe: exclude
+  - path: testdata/**
+    mode: hidden
+`)
+	_, err := LoadRules(path)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("LoadRules() error = %v, want *ValidationError", err)
+	}
+	var got []string
+	for _, issue := range validationErr.Issues {
+		got = append(got, issue.Path)
+	}
+	want := []string{"rules[1].path", "rules[2].mdoe", "rules[2]", "rules[3].mode"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("誤りの位置 = %v, want %v\n%v", got, want, err)
+	}
+	if !strings.Contains(err.Error(), "rules[2].mdoe: 未知のキーです（mode の誤りではありませんか）") {
+		t.Errorf("誤記したキーの候補が表示されていません:\n%v", err)
+	}
+}
+
+func TestLoadMaskRules(t *testing.T) {
+	path := writeConfig(t, "mask.json", `{"rules": [
+		{"preset": "email"},
+		{"name": "employee-id", "pattern": "EMP-\\d{6}"},
+		{"name": "broken", "pattern": "("},
+		{"pattern": "x"},
+		{"preset": "zip"}
+	]}`)
+	_, err := LoadMaskRules(path)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("LoadMaskRules() error = %v, want *ValidationError", err)
+	}
+	var got []string
+	for _, issue := range validationErr.Issues {
+		got = append(got, issue.Path)
+	}
+	if want := []string{"rules[2].pattern", "rules[3]", "rules[4].preset"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("誤りの位置 = %v, want %v\n%v", got, want, err)
+	}
+
+	valid := writeConfig(t, "mask.yml", "rules:\n  - preset: email\n  - name: employee-id\n    pattern: 'EMP-\\d{6}'\n")
+	loaded, err := LoadMaskRules(valid)
+	want := []report.MaskRule{{Preset: "email"}, {Name: "employee-id", Pattern: `EMP-\d{6}`}}
+	if err != nil || !reflect.DeepEqual(loaded, want) {
+		t.Errorf("LoadMaskRules() = %v, %v, want %v", loaded, err, want)
+	}
+}
+
+func TestLoad_SyntaxError(t *testing.T) {
+	_, err := LoadRules(writeConfig(t, "rules.json", `{"rules": `))
+	var validationErr *ValidationError
+	if err == nil || errors.As(err, &validationErr) {
+		t.Errorf("LoadRules() error = %v, want 解析のエラー", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package config は、ルールファイルなど利用者が記述する設定ファイル（JSON・YAML）の読み込みと検証を提供します。
+// 未知のキー（オプション名の誤記など）や値の型・内容の誤りを、"rules[3].mode" のような位置とあわせてすべて報告し、
+// 誤った設定が黙って無視されないようにします
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind は設定ファイルの値の種類です
+type Kind int
+
+const (
+	// KindObject はキーと値の組（JSON のオブジェクト・YAML のマッピング）です
+	KindObject Kind = iota
+	// KindArray は値の並び（JSON の配列・YAML のシーケンス）です
+	KindArray
+	// KindString は文字列です
+	KindString
+)
+
+// String は値の種類の表示名を返します
+func (k Kind) String() string {
+	switch k {
+	case KindObject:
+		return "オブジェクト"
+	case KindArray:
+		return "配列"
+	case KindString:
+		return "文字列"
+	}
+	return "不明な種類"
+}
+
+// Schema は設定ファイルの値に期待する形式です
+type Schema struct {
+	// Kind は値の種類です
+	Kind Kind
+	// Fields はオブジェクトに記述できるキーと、その値の形式です。これ以外のキーは誤りとして報告します
+	Fields map[string]*Schema
+	// Required はオブジェクトに必須のキーです
+	Required []string
+	// Items は配列の要素の形式です
+	Items *Schema
+	// Check は種類が正しい値に対する追加の検証です。nil の場合は検証しません
+	Check func(value any) error
+}
+
+// Issue は設定ファイルの検証で見つかった 1 つの誤りです
+type Issue struct {
+	// Path は誤りのある値の位置（"rules[3].mode" など）です。ファイル全体の誤りの場合は空です
+	Path string
+	// Message は誤りの内容です
+	Message string
+}
+
+// String は位置と誤りの内容を "rules[3].mode: ..." の形式で返します
+func (i Issue) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// ValidationError は設定ファイルの検証で見つかった誤りの一覧です
+type ValidationError struct {
+	// File は設定ファイルのパスです
+	File string
+	// Issues は見つかった誤りです（ファイル内の出現順）
+	Issues []Issue
+}
+
+// Error は誤りの件数と、1 行に 1 件の誤りを返します
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "設定ファイル %s に %d 件の誤りがあります", e.File, len(e.Issues))
+	for _, issue := range e.Issues {
+		b.WriteString("\n  ")
+		b.WriteString(issue.String())
+	}
+	return b.String()
+}
+
+// Validate は value（JSON・YAML を解析した値）が形式に従っているかを検証し、見つかった誤りをすべて返します
+func (s *Schema) Validate(value any) []Issue {
+	var issues []Issue
+	s.validate(value, "", &issues)
+	return issues
+}
+
+func (s *Schema) validate(value any, at string, issues *[]Issue) {
+	report := func(format string, args ...any) {
+		*issues = append(*issues, Issue{Path: at, Message: fmt.Sprintf(format, args...)})
+	}
+	switch s.Kind {
+	case KindObject:
+		object, ok := value.(map[string]any)
+		if !ok {
+			report("%sを指定してください（%s が指定されています）", s.Kind, describe(value))
+			return
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := s.Fields[key]
+			if !ok {
+				*issues = append(*issues, Issue{Path: joinKey(at, key), Message: s.unknownKey(key)})
+				continue
+			}
+			field.validate(object[key], joinKey(at, key), issues)
+		}
+		for _, key := range s.Required {
+			if _, ok := object[key]; !ok {
+				report("%s を指定してください", key)
+			}
+		}
+	case KindArray:
+		items, ok := value.([]any)
+		if !ok {
+			report("%sを指定してください（%s が指定されています）", s.Kind, describe(value))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", at, i), issues)
+			}
+		}
+	case KindString:
+		if _, ok := value.(string); !ok {
+			report("%sを指定してください（%s が指定されています）", s.Kind, describe(value))
+			return
+		}
+	}
+	if s.Check != nil {
+		if err := s.Check(value); err != nil {
+			report("%v", err)
+		}
+	}
+}
+
+// unknownKey は未知のキーの誤りの内容を返します。記述できるキーに綴りの近いものがある場合は候補として示します
+func (s *Schema) unknownKey(key string) string {
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if editDistance(strings.ToLower(key), name) <= 2 {
+			return fmt.Sprintf("未知のキーです（%s の誤りではありませんか）", name)
+		}
+	}
+	return fmt.Sprintf("未知のキーです（%s のいずれかを指定してください）", strings.Join(names, ", "))
+}
+
+// joinKey は位置 at の配下のキー key の位置を返します
+func joinKey(at, key string) string {
+	if at == "" {
+		return key
+	}
+	return at + "." + key
+}
+
+// describe は誤りの内容に表示する、値の種類の名前を返します
+func describe(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any, map[any]any:
+		return KindObject.String()
+	case []any:
+		return KindArray.String()
+	case string:
+		return KindString.String()
+	case bool:
+		return "真偽値"
+	}
+	return "数値"
+}
+
+// editDistance は a と b のレーベンシュタイン距離（文字単位）を返します
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr := make([]int, len(rb)+1)
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema := &Schema{Kind: KindObject, Fields: map[string]*Schema{
+		"ignore": {Kind: KindArray, Items: &Schema{Kind: KindString}},
+		"name":   {Kind: KindString},
+	}, Required: []string{"name"}}
+	tests := []struct {
+		name  string
+		value any
+		want  []Issue
+	}{
+		{
+			name:  "正しい値",
+			value: map[string]any{"name": "x", "ignore": []any{"a", "b"}},
+		},
+		{
+			name:  "綴りの近い未知のキー",
+			value: map[string]any{"name": "x", "ignroe": []any{}},
+			want:  []Issue{{Path: "ignroe", Message: "未知のキーです（ignore の誤りではありませんか）"}},
+		},
+		{
+			name:  "綴りの遠い未知のキー",
+			value: map[string]any{"name": "x", "output": "y"},
+			want:  []Issue{{Path: "output", Message: "未知のキーです（ignore, name のいずれかを指定してください）"}},
+		},
+		{
+			name:  "要素の型の誤りと必須のキーの不足",
+			value: map[string]any{"ignore": []any{"a", 3.0}},
+			want: []Issue{
+				{Path: "ignore[1]", Message: "文字列を指定してください（数値 が指定されています）"},
+				{Message: "name を指定してください"},
+			},
+		},
+		{
+			name:  "ルートがオブジェクトでない",
+			value: []any{},
+			want:  []Issue{{Message: "オブジェクトを指定してください（配列 が指定されています）"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schema.Validate(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package diff は 2 つのフォルダのスキャン結果を比較し、差分レポートを生成する機能を提供します
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// Method はファイルが変更されたかどうかの判定方法です
+type Method string
+
+const (
+	// MethodHash は SHA-256 ハッシュで判定します。両方のスキャンでハッシュを計算しておく必要があります
+	MethodHash Method = "hash"
+	// MethodModTime はサイズと更新日時で判定します。ハッシュの計算が不要な代わりに、
+	// コピー時に更新日時が変わったファイルも変更として扱われます
+	MethodModTime Method = "mtime"
+)
+
+// ParseMethod は文字列から判定方法を解決します。空文字列はハッシュによる判定として扱います
+func ParseMethod(s string) (Method, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "hash", "sha256":
+		return MethodHash, nil
+	case "mtime", "time":
+		return MethodModTime, nil
+	}
+	return "", fmt.Errorf("未対応の判定方法です: %s（hash, mtime のいずれかを指定してください）", s)
+}
+
+// ChangeKind は差分の種類です
+type ChangeKind string
+
+const (
+	// Added は比較先にのみ存在するファイルです
+	Added ChangeKind = "added"
+	// Removed は比較元にのみ存在するファイルです
+	Removed ChangeKind = "removed"
+	// Modified は両方に存在し、内容が異なるファイルです
+	Modified ChangeKind = "modified"
+)
+
+// Change は 1 ファイル分の差分です
+type Change struct {
+	Kind    ChangeKind
+	RelPath string
+	// Old は比較元のエントリです（Added の場合は nil）
+	Old *model.FileSystemEntry
+	// New は比較先のエントリです（Removed の場合は nil）
+	New *model.FileSystemEntry
+}
+
+// Result は比較結果です
+type Result struct {
+	// Changes は相対パスの昇順に並んだ差分の一覧です
+	Changes []Change
+	// Unchanged は内容が一致したファイル数です
+	Unchanged int
+}
+
+// Count は指定された種類の差分の件数を返します
+func (r Result) Count(kind ChangeKind) int {
+	count := 0
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare は比較元 oldEntries と比較先 newEntries のファイルを相対パスで突き合わせ、差分を返します。
+// ディレクトリは比較の対象外です
+func Compare(oldEntries, newEntries []model.FileSystemEntry, method Method) Result {
+	oldFiles := indexFiles(oldEntries)
+	newFiles := indexFiles(newEntries)
+
+	var result Result
+	for relPath, oldEntry := range oldFiles {
+		newEntry, ok := newFiles[relPath]
+		switch {
+		case !ok:
+			result.Changes = append(result.Changes, Change{Kind: Removed, RelPath: relPath, Old: oldEntry})
+		case isModified(oldEntry, newEntry, method):
+			result.Changes = append(result.Changes, Change{Kind: Modified, RelPath: relPath, Old: oldEntry, New: newEntry})
+		default:
+			result.Unchanged++
+		}
+	}
+	for relPath, newEntry := range newFiles {
+		if _, ok := oldFiles[relPath]; !ok {
+			result.Changes = append(result.Changes, Change{Kind: Added, RelPath: relPath, New: newEntry})
+		}
+	}
+
+	sort.Slice(result.Changes, func(i, j int) bool {
+		return result.Changes[i].RelPath < result.Changes[j].RelPath
+	})
+	return result
+}
+
+// indexFiles はファイルエントリを相対パスで引けるようにします
+func indexFiles(entries []model.FileSystemEntry) map[string]*model.FileSystemEntry {
+	files := make(map[string]*model.FileSystemEntry, len(entries))
+	for i := range entries {
+		if entries[i].IsDir {
+			continue
+		}
+		files[entries[i].RelPath] = &entries[i]
+	}
+	return files
+}
+
+// isModified は 2 つのエントリの内容が異なるかどうかを判定します。
+// ハッシュによる判定でハッシュがない場合（読み込みエラーなど）は、サイズと更新日時で判定します
+func isModified(oldEntry, newEntry *model.FileSystemEntry, method Method) bool {
+	if method == MethodHash && oldEntry.Hash != "" && newEntry.Hash != "" {
+		return oldEntry.Hash != newEntry.Hash
+	}
+	return oldEntry.Size != newEntry.Size || !oldEntry.ModTime.Equal(newEntry.ModTime)
+}
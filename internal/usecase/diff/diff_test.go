@@ -0,0 +1,155 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestParseMethod(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Method
+		wantErr bool
+	}{
+		{"", MethodHash, false},
+		{"hash", MethodHash, false},
+		{"MTIME", MethodModTime, false},
+		{"size", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMethod(tt.input)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseMethod(%q) = %v, %v; want %v, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	oldEntries := []model.FileSystemEntry{
+		{RelPath: "dir", IsDir: true},
+		{RelPath: "dir/same.txt", Size: 1, ModTime: t1, Hash: "h1"},
+		{RelPath: "dir/touched.txt", Size: 1, ModTime: t1, Hash: "h2"},
+		{RelPath: "edited.txt", Size: 1, ModTime: t1, Hash: "h3"},
+		{RelPath: "removed.txt", Size: 1, ModTime: t1, Hash: "h4"},
+	}
+	newEntries := []model.FileSystemEntry{
+		{RelPath: "dir/same.txt", Size: 1, ModTime: t1, Hash: "h1"},
+		{RelPath: "dir/touched.txt", Size: 1, ModTime: t2, Hash: "h2"},
+		{RelPath: "edited.txt", Size: 2, ModTime: t1, Hash: "h3x"},
+		{RelPath: "new.txt", Size: 1, ModTime: t1, Hash: "h5"},
+		{RelPath: "newdir", IsDir: true},
+	}
+
+	tests := []struct {
+		name      string
+		method    Method
+		want      []string
+		unchanged int
+	}{
+		{"ハッシュで判定", MethodHash, []string{"modified edited.txt", "added new.txt", "removed removed.txt"}, 2},
+		{"更新日時で判定", MethodModTime, []string{"modified dir/touched.txt", "modified edited.txt", "added new.txt", "removed removed.txt"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare(oldEntries, newEntries, tt.method)
+			var got []string
+			for _, c := range result.Changes {
+				got = append(got, string(c.Kind)+" "+c.RelPath)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Changes = %v, want %v", got, tt.want)
+			}
+			if result.Unchanged != tt.unchanged {
+				t.Errorf("Unchanged = %d, want %d", result.Unchanged, tt.unchanged)
+			}
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(oldDir, "a.txt"): "line1\nline2\n",
+		filepath.Join(newDir, "a.txt"): "line1\nchanged\n",
+		filepath.Join(newDir, "b.txt"): "new\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+	}
+	result := Compare(
+		[]model.FileSystemEntry{{Path: filepath.Join(oldDir, "a.txt"), RelPath: "a.txt", Size: 12, Hash: "x"}},
+		[]model.FileSystemEntry{
+			{Path: filepath.Join(newDir, "a.txt"), RelPath: "a.txt", Size: 14, Hash: "y"},
+			{Path: filepath.Join(newDir, "b.txt"), RelPath: "b.txt", Size: 4, Hash: "z"},
+		},
+		MethodHash,
+	)
+
+	var buf strings.Builder
+	WriteReport(&buf, result, ReportOptions{OldRoot: oldDir, NewRoot: newDir, Unified: true, ContextLines: DefaultContextLines})
+
+	output := buf.String()
+	for _, want := range []string{
+		"追加: 1, 削除: 0, 変更: 1, 変更なし: 0",
+		"[ADDED] b.txt (4 B)",
+		"[MODIFIED] a.txt (12 B -> 14 B)",
+		"--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n line1\n-line2\n+changed\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("出力に期待される内容が含まれていない: %q\nOutput:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "削除されたファイル") {
+		t.Errorf("該当がない区分は出力されるべきではありません:\n%s", output)
+	}
+}
+
+func TestWriteReport_Summary(t *testing.T) {
+	result := Compare(
+		[]model.FileSystemEntry{
+			{RelPath: "README.md", Size: 100, Hash: "a"},
+			{RelPath: "src/app.go", Size: 300, Hash: "b"},
+			{RelPath: "src/old.go", Size: 50, Hash: "c"},
+			{RelPath: "docs/guide.md", Size: 10, Hash: "d"},
+		},
+		[]model.FileSystemEntry{
+			{RelPath: "README.md", Size: 100, Hash: "a"},
+			{RelPath: "src/app.go", Size: 350, Hash: "x"},
+			{RelPath: "src/new.go", Size: 2048, Hash: "e"},
+			{RelPath: "docs/guide.md", Size: 20, Hash: "y"},
+		},
+		MethodHash,
+	)
+
+	summary := Summarize(result)
+	if summary.Added != 1 || summary.Removed != 1 || summary.Modified != 2 || summary.Unchanged != 1 || summary.SizeDelta != 2058 {
+		t.Errorf("Summarize() = %+v", summary)
+	}
+
+	var buf strings.Builder
+	WriteReport(&buf, result, ReportOptions{OldRoot: "v1", NewRoot: "v2", Summary: true})
+	output := buf.String()
+	want := "===== 変更の概要 =====\n" +
+		"比較元 v1 から比較先 v2 への変更は 4 件です（追加 1 件、削除 1 件、変更 2 件）。\n" +
+		"- 追加されたファイル: 1 件\n" +
+		"- 削除されたファイル: 1 件\n" +
+		"- 変更されたファイル: 2 件\n" +
+		"- 変更のないファイル: 1 件\n" +
+		"- 合計サイズの増減: +2.0 KB\n" +
+		"\n変更のあったフォルダ（変更の多い順）:\n" +
+		"- src: 3 件（追加 1、削除 1、変更 1）\n" +
+		"- docs: 1 件（追加 0、削除 0、変更 1）\n" +
+		"\n===== 差分サマリー =====\n"
+	if !strings.HasPrefix(output, want) {
+		t.Errorf("概要が詳細な差分の前に出力されていません:\n%s\nwant prefix:\n%s", output, want)
+	}
+}
@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/usecase/report"
+)
+
+// OutputFilePrefix は差分レポートのファイル名の接頭辞です
+const OutputFilePrefix = "diff_"
+
+// ReportOptions は差分レポートの出力内容を制御するオプションです
+type ReportOptions struct {
+	// OldRoot と NewRoot はレポートに表示する比較元・比較先のフォルダです
+	OldRoot string
+	NewRoot string
+	// Unified は変更されたテキストファイルの内容の差分を unified 形式で出力するかどうかを示します
+	Unified bool
+	// ContextLines は unified 形式の差分で変更箇所の前後に表示する行数です
+	ContextLines int
+	// Summary は差分の一覧の前に、変更の件数とフォルダごとの変更の件数を文章と箇条書きでまとめた概要を出力するかどうかを示します。
+	// LLM に変更のレビューを依頼する場合に、依頼文に貼り付けて使用します
+	Summary bool
+}
+
+// WriteReport は比較結果をテキスト形式の差分レポートとして出力します
+func WriteReport(writer io.Writer, result Result, opts ReportOptions) {
+	if opts.Summary {
+		writeSummary(writer, result, opts)
+	}
+	fmt.Fprintln(writer, "===== 差分サマリー =====")
+	fmt.Fprintf(writer, "比較元: %s\n", opts.OldRoot)
+	fmt.Fprintf(writer, "比較先: %s\n", opts.NewRoot)
+	fmt.Fprintf(writer, "追加: %d, 削除: %d, 変更: %d, 変更なし: %d\n",
+		result.Count(Added), result.Count(Removed), result.Count(Modified), result.Unchanged)
+
+	sections := []struct {
+		kind  ChangeKind
+		title string
+		label string
+	}{
+		{Added, "追加されたファイル", "[ADDED]"},
+		{Removed, "削除されたファイル", "[REMOVED]"},
+		{Modified, "変更されたファイル", "[MODIFIED]"},
+	}
+	for _, section := range sections {
+		if result.Count(section.kind) == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, "\n===== %s =====\n", section.title)
+		for _, c := range result.Changes {
+			if c.Kind == section.kind {
+				fmt.Fprintf(writer, "%s %s%s\n", section.label, c.RelPath, sizeAnnotation(c))
+			}
+		}
+	}
+
+	if opts.Unified && result.Count(Modified) > 0 {
+		fmt.Fprintln(writer, "\n===== 内容の差分 =====")
+		for _, c := range result.Changes {
+			if c.Kind == Modified {
+				writeContentDiff(writer, c, opts.ContextLines)
+			}
+		}
+	}
+}
+
+// sizeAnnotation はファイルサイズの注記を返します。変更されたファイルは変更前後のサイズを表示します
+func sizeAnnotation(c Change) string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf(" (%s)", report.FormatSize(c.New.Size))
+	case Removed:
+		return fmt.Sprintf(" (%s)", report.FormatSize(c.Old.Size))
+	}
+	return fmt.Sprintf(" (%s -> %s)", report.FormatSize(c.Old.Size), report.FormatSize(c.New.Size))
+}
+
+// writeContentDiff は変更されたファイル 1 件分の内容の差分を出力します。
+// バイナリファイルや読み込めないファイルは、差分の代わりに理由を出力します
+func writeContentDiff(writer io.Writer, c Change, contextLines int) {
+	oldName, newName := "a/"+c.RelPath, "b/"+c.RelPath
+	if c.Old.IsBinary || c.New.IsBinary {
+		fmt.Fprintf(writer, "バイナリファイル %s は異なります\n\n", c.RelPath)
+		return
+	}
+	oldContent, err := os.ReadFile(c.Old.Path)
+	if err != nil {
+		fmt.Fprintf(writer, "[ファイル読み込みエラー] %s: %v\n\n", oldName, err)
+		return
+	}
+	newContent, err := os.ReadFile(c.New.Path)
+	if err != nil {
+		fmt.Fprintf(writer, "[ファイル読み込みエラー] %s: %v\n\n", newName, err)
+		return
+	}
+	if bytes.Equal(oldContent, newContent) {
+		fmt.Fprintf(writer, "[内容は同一です（更新日時のみ異なります）] %s\n\n", c.RelPath)
+		return
+	}
+	if !WriteUnified(writer, oldName, newName, string(oldContent), string(newContent), contextLines) {
+		fmt.Fprintf(writer, "[差分が大きすぎるため省略] %s\n\n", c.RelPath)
+		return
+	}
+	fmt.Fprintln(writer)
+}
+
+// CreateOutputFile は差分レポートの出力ファイルを作成します。
+// 同じ名前のファイルがすでに存在する場合は上書きせず、apperrors.ErrOutputExists を返します
+func CreateOutputFile(outputDir string) (*os.File, string, error) {
+	timestamp := time.Now().Format(report.TimestampLayout)
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("%s%s%s", OutputFilePrefix, timestamp, report.OutputFileSuffix))
+
+	outputFile, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, "", apperrors.New(apperrors.ErrOutputExists, "出力ファイルがすでに存在します", outputPath, err)
+	}
+	if err != nil {
+		return nil, "", apperrors.Wrap("出力ファイルの作成に失敗しました", outputPath, err)
+	}
+	return outputFile, outputPath, nil
+}
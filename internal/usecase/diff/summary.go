@@ -0,0 +1,116 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"FolderScope/internal/usecase/report"
+)
+
+// MaxSummaryDirs は変更の概要に表示するフォルダの最大数です。これを超えるフォルダは件数のみを表示します
+const MaxSummaryDirs = 20
+
+// DirChurn はフォルダ直下のファイルの変更の件数です
+type DirChurn struct {
+	// Dir はフォルダの相対パスです。ルートフォルダの場合は "." です
+	Dir      string
+	Added    int
+	Removed  int
+	Modified int
+}
+
+// Total は変更の件数の合計を返します
+func (d DirChurn) Total() int {
+	return d.Added + d.Removed + d.Modified
+}
+
+// Summary は比較結果の概要です
+type Summary struct {
+	Added     int
+	Removed   int
+	Modified  int
+	Unchanged int
+	// SizeDelta は比較先の合計サイズから比較元の合計サイズを引いた値（バイト）です。変更のないファイルは含みません
+	SizeDelta int64
+	// Dirs はフォルダごとの変更の件数です。件数の多い順（同じ場合は相対パスの順）に並びます
+	Dirs []DirChurn
+}
+
+// Summarize は比較結果から、変更の種類ごとの件数とフォルダごとの変更の件数を集計します
+func Summarize(result Result) Summary {
+	summary := Summary{Unchanged: result.Unchanged}
+	byDir := make(map[string]*DirChurn)
+	for _, c := range result.Changes {
+		dir := path.Dir(c.RelPath)
+		churn, ok := byDir[dir]
+		if !ok {
+			churn = &DirChurn{Dir: dir}
+			byDir[dir] = churn
+		}
+		switch c.Kind {
+		case Added:
+			summary.Added++
+			churn.Added++
+			summary.SizeDelta += c.New.Size
+		case Removed:
+			summary.Removed++
+			churn.Removed++
+			summary.SizeDelta -= c.Old.Size
+		case Modified:
+			summary.Modified++
+			churn.Modified++
+			summary.SizeDelta += c.New.Size - c.Old.Size
+		}
+	}
+	for _, churn := range byDir {
+		summary.Dirs = append(summary.Dirs, *churn)
+	}
+	sort.Slice(summary.Dirs, func(i, j int) bool {
+		if a, b := summary.Dirs[i].Total(), summary.Dirs[j].Total(); a != b {
+			return a > b
+		}
+		return summary.Dirs[i].Dir < summary.Dirs[j].Dir
+	})
+	return summary
+}
+
+// writeSummary は比較結果の概要を、LLM へのレビューの依頼文にそのまま貼り付けられるよう、文章と箇条書きで出力します
+func writeSummary(writer io.Writer, result Result, opts ReportOptions) {
+	s := Summarize(result)
+	fmt.Fprintln(writer, "===== 変更の概要 =====")
+	fmt.Fprintf(writer, "比較元 %s から比較先 %s への変更は %d 件です（追加 %d 件、削除 %d 件、変更 %d 件）。\n",
+		opts.OldRoot, opts.NewRoot, s.Added+s.Removed+s.Modified, s.Added, s.Removed, s.Modified)
+	fmt.Fprintf(writer, "- 追加されたファイル: %d 件\n", s.Added)
+	fmt.Fprintf(writer, "- 削除されたファイル: %d 件\n", s.Removed)
+	fmt.Fprintf(writer, "- 変更されたファイル: %d 件\n", s.Modified)
+	fmt.Fprintf(writer, "- 変更のないファイル: %d 件\n", s.Unchanged)
+	fmt.Fprintf(writer, "- 合計サイズの増減: %s\n", formatSizeDelta(s.SizeDelta))
+	if len(s.Dirs) == 0 {
+		fmt.Fprintln(writer)
+		return
+	}
+
+	fmt.Fprintln(writer, "\n変更のあったフォルダ（変更の多い順）:")
+	for i, d := range s.Dirs {
+		if i == MaxSummaryDirs {
+			fmt.Fprintf(writer, "- ほか %d フォルダ\n", len(s.Dirs)-MaxSummaryDirs)
+			break
+		}
+		dir := d.Dir
+		if dir == "." {
+			dir = "（ルート）"
+		}
+		fmt.Fprintf(writer, "- %s: %d 件（追加 %d、削除 %d、変更 %d）\n", dir, d.Total(), d.Added, d.Removed, d.Modified)
+	}
+	fmt.Fprintln(writer)
+}
+
+// formatSizeDelta はサイズの増減を符号付きで返します
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + report.FormatSize(-delta)
+	}
+	return "+" + report.FormatSize(delta)
+}
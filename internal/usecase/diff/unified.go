@@ -0,0 +1,221 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// DefaultContextLines は unified 形式の差分で変更箇所の前後に表示する行数です
+	DefaultContextLines = 3
+	// MaxEditDistance は行単位の差分を計算する編集距離の上限です。
+	// これを超える差分は計算量とメモリ使用量が大きくなるため、差分の表示を省略します
+	MaxEditDistance = 2000
+)
+
+// opKind は行単位の編集操作の種類です
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// lineEdit は 1 行分の編集操作です
+type lineEdit struct {
+	op   opKind
+	text string
+}
+
+// splitLines はテキストを行に分割します。末尾の改行による空行は含めません
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines は Myers のアルゴリズムで a から b への最短の編集手順を求めます。
+// 編集距離が maxEdits を超える場合は false を返します
+func diffLines(a, b []string, maxEdits int) ([]lineEdit, bool) {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit > maxEdits {
+		limit = maxEdits
+	}
+
+	// v[k+offset] は対角線 k 上で到達できた最も遠い a 側の位置です。
+	// 経路を復元するため、各ステップ開始時の v のうち参照しうる範囲 [-d-1, d+1] を trace に保存します
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset]
+			} else {
+				x = v[k-1+offset] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+offset] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrack は trace から編集手順を復元します
+func backtrack(trace [][]int, a, b []string) []lineEdit {
+	x, y := len(a), len(b)
+	var edits []lineEdit
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, lineEdit{op: opEqual, text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, lineEdit{op: opInsert, text: b[y-1]})
+				y--
+			} else {
+				edits = append(edits, lineEdit{op: opDelete, text: a[x-1]})
+				x--
+			}
+		}
+	}
+
+	// 末尾から復元しているため逆順にする
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// WriteUnified は oldContent から newContent への差分を unified 形式で出力します。
+// 差分がない場合は何も出力しません。差分が MaxEditDistance を超える場合は false を返し、何も出力しません
+func WriteUnified(writer io.Writer, oldName, newName, oldContent, newContent string, contextLines int) bool {
+	edits, ok := diffLines(splitLines(oldContent), splitLines(newContent), MaxEditDistance)
+	if !ok {
+		return false
+	}
+
+	hunks := groupHunks(edits, contextLines)
+	if len(hunks) == 0 {
+		return true
+	}
+	fmt.Fprintf(writer, "--- %s\n", oldName)
+	fmt.Fprintf(writer, "+++ %s\n", newName)
+	for _, h := range hunks {
+		fmt.Fprintf(writer, "@@ -%s +%s @@\n", hunkRange(h.oldStart, h.oldLines), hunkRange(h.newStart, h.newLines))
+		for _, e := range edits[h.from:h.to] {
+			fmt.Fprintf(writer, "%c%s\n", e.op, e.text)
+		}
+	}
+	return true
+}
+
+// hunk は unified 形式の差分の 1 ブロックです
+type hunk struct {
+	// from, to は edits 内の範囲です
+	from, to           int
+	oldStart, oldLines int
+	newStart, newLines int
+}
+
+// groupHunks は変更箇所を前後 contextLines 行の文脈とともにブロックにまとめます。
+// 文脈が重なる変更箇所は同じブロックにします
+func groupHunks(edits []lineEdit, contextLines int) []hunk {
+	var hunks []hunk
+	for i := 0; i < len(edits); {
+		if edits[i].op == opEqual {
+			i++
+			continue
+		}
+		from := i - contextLines
+		if from < 0 {
+			from = 0
+		}
+		// 次の変更箇所までの変更なしの行が文脈 2 つ分以下であれば同じブロックに含める
+		to := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].op != opEqual {
+				to = j + 1
+				continue
+			}
+			if j-to >= 2*contextLines {
+				break
+			}
+		}
+		to += contextLines
+		if to > len(edits) {
+			to = len(edits)
+		}
+		if len(hunks) > 0 && from < hunks[len(hunks)-1].to {
+			from = hunks[len(hunks)-1].to
+		}
+		hunks = append(hunks, newHunk(edits, from, to))
+		i = to
+	}
+	return hunks
+}
+
+// newHunk は edits[from:to] のブロックについて、比較元・比較先での開始行と行数を求めます
+func newHunk(edits []lineEdit, from, to int) hunk {
+	h := hunk{from: from, to: to, oldStart: 1, newStart: 1}
+	for _, e := range edits[:from] {
+		if e.op != opInsert {
+			h.oldStart++
+		}
+		if e.op != opDelete {
+			h.newStart++
+		}
+	}
+	for _, e := range edits[from:to] {
+		if e.op != opInsert {
+			h.oldLines++
+		}
+		if e.op != opDelete {
+			h.newLines++
+		}
+	}
+	return h
+}
+
+// hunkRange はブロックの範囲を "開始行,行数" の形式で返します。
+// 行数が 0 の場合は、慣例に従い直前の行番号を開始行とします
+func hunkRange(start, lines int) string {
+	if lines == 0 {
+		start--
+	}
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
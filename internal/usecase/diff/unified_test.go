@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteUnified(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldContent string
+		newContent string
+		want       string
+	}{
+		{
+			name:       "差分なし",
+			oldContent: "a\nb\n",
+			newContent: "a\nb\n",
+			want:       "",
+		},
+		{
+			name:       "1行の変更",
+			oldContent: "a\nb\nc\n",
+			newContent: "a\nB\nc\n",
+			want:       "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:       "新規の内容",
+			oldContent: "",
+			newContent: "x\n",
+			want:       "--- a/f\n+++ b/f\n@@ -0,0 +1 @@\n+x\n",
+		},
+		{
+			name:       "離れた変更は別のブロック",
+			oldContent: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			newContent: "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -1,2 +1,2 @@\n-1\n+one\n 2\n" +
+				"@@ -9,2 +9,2 @@\n 9\n-10\n+ten\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if !WriteUnified(&buf, "a/f", "b/f", tt.oldContent, tt.newContent, 1) {
+				t.Fatal("差分の計算に失敗しました")
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteUnified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines_EditLimit(t *testing.T) {
+	a := strings.Split(strings.Repeat("a\n", 10), "\n")
+	b := strings.Split(strings.Repeat("b\n", 10), "\n")
+	if _, ok := diffLines(a, b, 5); ok {
+		t.Error("編集距離が上限を超える場合は false が返されるべきです")
+	}
+	edits, ok := diffLines(a, b, 100)
+	if !ok {
+		t.Fatal("上限以内の差分は計算されるべきです")
+	}
+	var inserted, deleted int
+	for _, e := range edits {
+		switch e.op {
+		case opInsert:
+			inserted++
+		case opDelete:
+			deleted++
+		}
+	}
+	if inserted != 10 || deleted != 10 {
+		t.Errorf("挿入 %d 行・削除 %d 行、want 10 行ずつ", inserted, deleted)
+	}
+}
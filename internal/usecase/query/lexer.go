@@ -0,0 +1,154 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// tokenType は字句の種類です
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdent
+	tokenNumber
+	tokenDuration
+	tokenString
+	tokenOperator
+)
+
+// token は条件式の字句です
+type token struct {
+	typ tokenType
+	// text は字句の文字列です。識別子とキーワードは小文字に変換します
+	text string
+	// num は数値（サイズの単位は換算済みのバイト数）または期間（秒）です
+	num float64
+	// str は文字列リテラルの値です
+	str string
+	// pos は条件式の先頭からの位置（1 始まりの文字数）です
+	pos int
+}
+
+// sizeUnits はサイズの単位ごとのバイト数です（FormatSize と同じく 1024 倍ごとの単位）
+var sizeUnits = map[string]float64{
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+}
+
+// durationUnits は期間の単位ごとの長さです
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// operators は記号の演算子です。長いものから順に照合します
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "=", "!", "(", ")", "[", "]", ","}
+
+// tokenize は条件式を字句に分割します
+func tokenize(src string) ([]token, error) {
+	runes := []rune(src)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			str, n, err := scanString(runes[i:])
+			if err != nil {
+				return nil, errorAt(pos, "%v", err)
+			}
+			tokens = append(tokens, token{typ: tokenString, text: string(runes[i : i+n]), str: str, pos: pos})
+			i += n
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			num, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, errorAt(pos, "数値 '%s' が不正です", string(runes[i:j]))
+			}
+			k := j
+			for k < len(runes) && unicode.IsLetter(runes[k]) {
+				k++
+			}
+			t := token{typ: tokenNumber, text: string(runes[i:k]), num: num, pos: pos}
+			if unit := strings.ToLower(string(runes[j:k])); unit != "" {
+				if size, ok := sizeUnits[unit]; ok {
+					t.num *= size
+				} else if d, ok := durationUnits[unit]; ok {
+					t.typ = tokenDuration
+					t.num *= d.Seconds()
+				} else {
+					return nil, errorAt(pos, "未対応の単位です: %s（B, KB, MB, GB, TB, s, m, h, d, w のいずれかを指定してください）", string(runes[j:k]))
+				}
+			}
+			tokens = append(tokens, t)
+			i = k
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{typ: tokenIdent, text: strings.ToLower(string(runes[i:j])), pos: pos})
+			i = j
+		default:
+			op := matchOperator(runes[i:])
+			if op == "" {
+				return nil, errorAt(pos, "不正な文字です: %q", r)
+			}
+			tokens = append(tokens, token{typ: tokenOperator, text: op, pos: pos})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// matchOperator は runes の先頭に一致する記号の演算子を返します。一致しない場合は空文字列を返します
+func matchOperator(runes []rune) string {
+	for _, op := range operators {
+		if strings.HasPrefix(string(runes[:min(len(runes), 2)]), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// scanString は先頭の文字列リテラルを読み込み、値と文字数を返します。
+// 二重引用符の文字列では Go と同じエスケープを解釈し、単一引用符の文字列は正規表現を書きやすいよう内容をそのまま使用します
+func scanString(runes []rune) (string, int, error) {
+	quote := runes[0]
+	for i := 1; i < len(runes); i++ {
+		switch {
+		case quote == '"' && runes[i] == '\\':
+			i++
+		case runes[i] == quote:
+			if quote == '\'' {
+				return string(runes[1:i]), i + 1, nil
+			}
+			str, err := strconv.Unquote(string(runes[:i+1]))
+			if err != nil {
+				return "", 0, fmt.Errorf("文字列 %s が不正です", string(runes[:i+1]))
+			}
+			return str, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("文字列が閉じられていません")
+}
+
+// errorAt は条件式の位置を含むエラーを返します
+func errorAt(pos int, format string, args ...any) error {
+	return fmt.Errorf("%d 文字目: %s", pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,246 @@
+package query
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// compareOps は比較演算子です。"=" は "==" と同じ意味です
+var compareOps = map[string]bool{"==": true, "=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// parser は字句の列から構文木を組み立てます。演算子の優先順位は低い順に or, and, not, 比較です
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept は次の字句が texts のいずれかの演算子またはキーワードであれば読み進めて true を返します
+func (p *parser) accept(texts ...string) (token, bool) {
+	t := p.peek()
+	if t.typ != tokenOperator && t.typ != tokenIdent {
+		return t, false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			return p.next(), true
+		}
+	}
+	return t, false
+}
+
+// expect は次の字句が text であることを確認して読み進めます
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		return errorAt(p.peek().pos, "'%s' が必要です", text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLogical(false, []string{"or", "||"}, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogical(true, []string{"and", "&&"}, p.parseNot)
+}
+
+// parseLogical は operand を ops で連結した論理式を解析します
+func (p *parser) parseLogical(and bool, ops []string, operand func() (node, error)) (node, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept(ops...)
+		if !ok {
+			return l, nil
+		}
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireBool(t, l, r); err != nil {
+			return nil, err
+		}
+		l = logicalNode{and: and, l: l, r: r}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	t, ok := p.accept("not", "!")
+	if !ok {
+		return p.parseComparison()
+	}
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if err := requireBool(t, x); err != nil {
+		return nil, err
+	}
+	return notNode{x: x}, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.typ == tokenOperator && compareOps[t.text]:
+		p.next()
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		if op == "=" {
+			op = "=="
+		}
+		if l.kind() != r.kind() {
+			return nil, errorAt(t.pos, "'%s' の両辺の型が一致しません（%s と %s）", t.text, l.kind(), r.kind())
+		}
+		if l.kind() == kindBool && op != "==" && op != "!=" {
+			return nil, errorAt(t.pos, "真偽値は '%s' で比較できません", t.text)
+		}
+		return compareNode{op: op, l: l, r: r}, nil
+	case t.typ == tokenIdent && t.text == "matches":
+		p.next()
+		pattern := p.next()
+		if pattern.typ != tokenString {
+			return nil, errorAt(pattern.pos, "matches の右辺には正規表現の文字列を指定してください")
+		}
+		re, err := regexp.Compile(pattern.str)
+		if err != nil {
+			return nil, errorAt(pattern.pos, "正規表現 '%s' が不正です: %v", pattern.str, err)
+		}
+		if err := requireKind(t, kindString, l); err != nil {
+			return nil, err
+		}
+		return matchNode{x: l, re: re}, nil
+	case t.typ == tokenIdent && stringOps[t.text] != nil:
+		p.next()
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireKind(t, kindString, l, r); err != nil {
+			return nil, err
+		}
+		return stringOpNode{fn: stringOps[t.text], l: l, r: r}, nil
+	case t.typ == tokenIdent && t.text == "in":
+		p.next()
+		list, err := p.parseList(l.kind())
+		if err != nil {
+			return nil, err
+		}
+		return inNode{x: l, list: list}, nil
+	}
+	return l, nil
+}
+
+// parseList は "[" リテラル, ... "]" の形式のリストを解析します。要素の型はすべて k である必要があります
+func (p *parser) parseList(k kind) ([]value, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var list []value
+	for {
+		if _, ok := p.accept("]"); ok {
+			return list, nil
+		}
+		if len(list) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		t := p.peek()
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := item.(literalNode)
+		if !ok {
+			return nil, errorAt(t.pos, "リストの要素には値を指定してください")
+		}
+		if lit.k != k {
+			return nil, errorAt(t.pos, "リストの要素の型が一致しません（%s と %s）", k, lit.k)
+		}
+		list = append(list, lit.v)
+	}
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.typ {
+	case tokenNumber:
+		return literalNode{k: kindNumber, v: value{num: t.num}}, nil
+	case tokenDuration:
+		return literalNode{k: kindDuration, v: value{num: t.num}}, nil
+	case tokenString:
+		return literalNode{k: kindString, v: value{str: t.str}}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return literalNode{k: kindBool, v: value{b: t.text == "true"}}, nil
+		}
+		f, ok := fields[t.text]
+		if !ok {
+			return nil, errorAt(t.pos, "未知の項目です: %s（%s のいずれかを指定してください）", t.text, strings.Join(fieldNames(), ", "))
+		}
+		return fieldNode{f: f}, nil
+	case tokenOperator:
+		if t.text == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	case tokenEOF:
+		return nil, errorAt(t.pos, "条件式が途中で終わっています")
+	}
+	return nil, errorAt(t.pos, "'%s' は使用できません", t.text)
+}
+
+// requireBool は論理演算子 t のオペランドがすべて真偽値であることを確認します
+func requireBool(t token, operands ...node) error {
+	return requireKind(t, kindBool, operands...)
+}
+
+// requireKind は演算子 t のオペランドがすべて型 k であることを確認します
+func requireKind(t token, k kind, operands ...node) error {
+	for _, x := range operands {
+		if x.kind() != k {
+			return errorAt(t.pos, "'%s' には%sを指定してください（%s が指定されています）", t.text, k, x.kind())
+		}
+	}
+	return nil
+}
+
+// fieldNames は参照できる項目名を名前の順で返します
+func fieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,282 @@
+// Package query は、ファイルをレポートに含めるかどうかを決める条件式を解釈する機能を提供します。
+//
+// 条件式はファイルの項目（path, size, age など）と比較演算子・論理演算子で記述します。例:
+//
+//	size < 1MB and not path matches '^vendor/'
+//	ext in [".go", ".md"] or (age < 7d and not binary)
+//
+// 条件式はコンパイル時に項目名と型を検証するため、ファイルごとの評価でエラーになることはありません
+package query
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// kind は式の値の型です
+type kind int
+
+const (
+	kindBool kind = iota
+	kindNumber
+	kindString
+	kindDuration
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindBool:
+		return "真偽値"
+	case kindNumber:
+		return "数値"
+	case kindString:
+		return "文字列"
+	}
+	return "期間"
+}
+
+// value は式の値です。どのフィールドを使用するかは式の型で決まります。期間は秒数を num に保持します
+type value struct {
+	b   bool
+	num float64
+	str string
+}
+
+// env は式を評価する対象のファイルと、経過時間の基準となる現在時刻です
+type env struct {
+	entry model.FileSystemEntry
+	now   time.Time
+}
+
+// field は条件式で参照できるファイルの項目です
+type field struct {
+	kind kind
+	get  func(e *env) value
+	// description はヘルプに表示する説明です
+	description string
+}
+
+// fields は条件式で参照できる項目の一覧です
+var fields = map[string]field{
+	"path": {kindString, func(e *env) value { return value{str: e.entry.RelPath} }, "ルートからの相対パス（'/' 区切り）"},
+	"name": {kindString, func(e *env) value { return value{str: path.Base(e.entry.RelPath)} }, "ファイル名"},
+	"ext":  {kindString, func(e *env) value { return value{str: strings.ToLower(path.Ext(e.entry.RelPath))} }, "小文字の拡張子（\".go\" など）"},
+	"dir": {kindString, func(e *env) value {
+		if dir := path.Dir(e.entry.RelPath); dir != "." {
+			return value{str: dir}
+		}
+		return value{}
+	}, "親フォルダの相対パス（ルート直下は空文字列）"},
+	"size":     {kindNumber, func(e *env) value { return value{num: float64(e.entry.Size)} }, "サイズ（バイト、1MB などの単位付きで比較可能）"},
+	"depth":    {kindNumber, func(e *env) value { return value{num: float64(e.entry.Depth)} }, "ルートからの深さ（ルート直下は 0）"},
+	"age":      {kindDuration, func(e *env) value { return value{num: e.now.Sub(e.entry.ModTime).Seconds()} }, "最終更新からの経過時間（7d などの単位付きで比較）"},
+	"binary":   {kindBool, func(e *env) value { return value{b: e.entry.IsBinary} }, "バイナリファイルかどうか"},
+	"mime":     {kindString, func(e *env) value { return value{str: e.entry.MIMEType} }, "内容から判定したメディアタイプ"},
+	"encoding": {kindString, func(e *env) value { return value{str: e.entry.Encoding} }, "文字コード"},
+	"eol":      {kindString, func(e *env) value { return value{str: e.entry.LineEnding} }, "改行コード（LF, CRLF, CR, mixed）"},
+	"hash":     {kindString, func(e *env) value { return value{str: e.entry.Hash} }, "SHA-256 ハッシュ（-hash 指定時のみ）"},
+}
+
+// FieldHelp は条件式で参照できる項目とその説明を、項目名の順に "名前: 説明" の形式で返します
+func FieldHelp() []string {
+	names := fieldNames()
+	help := make([]string, 0, len(names))
+	for _, name := range names {
+		help = append(help, fmt.Sprintf("%s: %s", name, fields[name].description))
+	}
+	return help
+}
+
+// node は式の構文木の節です。型はコンパイル時に検証するため、eval は型に応じたフィールドのみを参照します
+type node interface {
+	kind() kind
+	eval(e *env) value
+}
+
+// literalNode は数値・期間・文字列・真偽値のリテラルです
+type literalNode struct {
+	k kind
+	v value
+}
+
+func (n literalNode) kind() kind      { return n.k }
+func (n literalNode) eval(*env) value { return n.v }
+
+// fieldNode はファイルの項目の参照です
+type fieldNode struct {
+	f field
+}
+
+func (n fieldNode) kind() kind        { return n.f.kind }
+func (n fieldNode) eval(e *env) value { return n.f.get(e) }
+
+// notNode は否定です
+type notNode struct {
+	x node
+}
+
+func (n notNode) kind() kind        { return kindBool }
+func (n notNode) eval(e *env) value { return value{b: !n.x.eval(e).b} }
+
+// logicalNode は and または or です。左辺で結果が決まる場合は右辺を評価しません
+type logicalNode struct {
+	and  bool
+	l, r node
+}
+
+func (n logicalNode) kind() kind { return kindBool }
+
+func (n logicalNode) eval(e *env) value {
+	l := n.l.eval(e).b
+	if n.and {
+		return value{b: l && n.r.eval(e).b}
+	}
+	return value{b: l || n.r.eval(e).b}
+}
+
+// compareNode は同じ型の値の比較です
+type compareNode struct {
+	op   string
+	l, r node
+}
+
+func (n compareNode) kind() kind { return kindBool }
+
+func (n compareNode) eval(e *env) value {
+	l, r := n.l.eval(e), n.r.eval(e)
+	var c int
+	switch n.l.kind() {
+	case kindBool:
+		if l.b != r.b {
+			c = 1
+		}
+	case kindString:
+		c = strings.Compare(l.str, r.str)
+	default:
+		switch {
+		case l.num < r.num:
+			c = -1
+		case l.num > r.num:
+			c = 1
+		}
+	}
+	switch n.op {
+	case "==":
+		return value{b: c == 0}
+	case "!=":
+		return value{b: c != 0}
+	case "<":
+		return value{b: c < 0}
+	case "<=":
+		return value{b: c <= 0}
+	case ">":
+		return value{b: c > 0}
+	}
+	return value{b: c >= 0}
+}
+
+// matchNode は文字列が正規表現に一致するかどうかの判定です
+type matchNode struct {
+	x  node
+	re *regexp.Regexp
+}
+
+func (n matchNode) kind() kind        { return kindBool }
+func (n matchNode) eval(e *env) value { return value{b: n.re.MatchString(n.x.eval(e).str)} }
+
+// inNode は値がリストのいずれかと等しいかどうかの判定です
+type inNode struct {
+	x    node
+	list []value
+}
+
+func (n inNode) kind() kind { return kindBool }
+
+func (n inNode) eval(e *env) value {
+	v := n.x.eval(e)
+	for _, item := range n.list {
+		if item == v {
+			return value{b: true}
+		}
+	}
+	return value{b: false}
+}
+
+// stringOpNode は contains, startswith, endswith による文字列の判定です
+type stringOpNode struct {
+	fn   func(s, substr string) bool
+	l, r node
+}
+
+func (n stringOpNode) kind() kind        { return kindBool }
+func (n stringOpNode) eval(e *env) value { return value{b: n.fn(n.l.eval(e).str, n.r.eval(e).str)} }
+
+// stringOps は文字列に対する演算子です
+var stringOps = map[string]func(s, substr string) bool{
+	"contains":   strings.Contains,
+	"startswith": strings.HasPrefix,
+	"endswith":   strings.HasSuffix,
+}
+
+// Query はコンパイル済みの条件式です
+type Query struct {
+	src  string
+	root node
+}
+
+// Compile は条件式を解析し、項目名と型を検証します
+func Compile(src string) (*Query, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, fmt.Errorf("条件式が不正です: %w", err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().typ != tokenEOF {
+		err = errorAt(p.peek().pos, "'%s' は不要です", p.peek().text)
+	}
+	if err == nil && root.kind() != kindBool {
+		err = fmt.Errorf("条件式の結果が真偽値ではありません（%s）", root.kind())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("条件式が不正です: %w", err)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// String は条件式の文字列を返します
+func (q *Query) String() string {
+	return q.src
+}
+
+// Match はファイル entry が条件式を満たすかどうかを返します。経過時間（age）は now を基準にします
+func (q *Query) Match(entry model.FileSystemEntry, now time.Time) bool {
+	return q.root.eval(&env{entry: entry, now: now}).b
+}
+
+// Filter は entries のうち条件式を満たすファイルと、それらを含むフォルダのみを返します
+func (q *Query) Filter(entries []model.FileSystemEntry, now time.Time) []model.FileSystemEntry {
+	matched := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir || !q.Match(entry, now) {
+			continue
+		}
+		matched[entry.RelPath] = true
+		for dir := path.Dir(entry.RelPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	filtered := make([]model.FileSystemEntry, 0, len(matched)+len(dirs))
+	for _, entry := range entries {
+		if (entry.IsDir && dirs[entry.RelPath]) || (!entry.IsDir && matched[entry.RelPath]) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,110 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestQuery_Match(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	entry := model.FileSystemEntry{
+		RelPath:  "vendor/lib/util.Go",
+		Depth:    2,
+		Size:     1536,
+		ModTime:  now.Add(-3 * 24 * time.Hour),
+		MIMEType: "text/plain",
+		Encoding: model.EncodingUTF8,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "size < 1MB", want: true},
+		{expr: "size >= 1.5KB and size <= 1536", want: true},
+		{expr: "size > 2KB", want: false},
+		{expr: "age < 7d", want: true},
+		{expr: "age > 48h", want: true},
+		{expr: "path matches '^vendor/'", want: true},
+		{expr: `not path matches "^vendor/"`, want: false},
+		{expr: `name == "util.Go" && ext = ".go"`, want: true},
+		{expr: `dir == "vendor/lib"`, want: true},
+		{expr: `ext in [".go", ".md"]`, want: true},
+		{expr: `not ext in [".md"]`, want: true},
+		{expr: `path contains "/lib/" and path startswith "vendor" and path endswith ".Go"`, want: true},
+		{expr: "binary or depth > 1", want: true},
+		{expr: "binary == false and (depth == 0 || mime == 'text/plain')", want: true},
+		{expr: "!(encoding != 'UTF-8')", want: true},
+		{expr: "size < 1MB AND NOT binary", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			q, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if got := q.Match(entry, now); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{name: "未知の項目", expr: "owner == 'root'", wantErr: "未知の項目です: owner"},
+		{name: "型の不一致", expr: "size < 7d", wantErr: "両辺の型が一致しません"},
+		{name: "真偽値ではない結果", expr: "size", wantErr: "真偽値ではありません"},
+		{name: "論理演算子に真偽値以外", expr: "size and binary", wantErr: "'and' には真偽値を指定してください"},
+		{name: "不正な正規表現", expr: "path matches '('", wantErr: "正規表現 '(' が不正です"},
+		{name: "未対応の単位", expr: "size < 10XB", wantErr: "未対応の単位です: XB"},
+		{name: "閉じていない括弧", expr: "(binary", wantErr: "')' が必要です"},
+		{name: "閉じていない文字列", expr: "path == 'a", wantErr: "9 文字目: 文字列が閉じられていません"},
+		{name: "余分な字句", expr: "binary binary", wantErr: "'binary' は不要です"},
+		{name: "途中で終わる", expr: "binary and", wantErr: "途中で終わっています"},
+		{name: "リストの型の不一致", expr: "ext in ['.go', 1]", wantErr: "リストの要素の型が一致しません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile(%q) error = %v, want %q を含むエラー", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuery_Filter(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "docs", IsDir: true},
+		{RelPath: "docs/a.md", Size: 10},
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/big.go", Size: 2 << 20},
+		{RelPath: "src/small.go", Size: 100},
+		{RelPath: "vendor", IsDir: true},
+		{RelPath: "vendor/x.go", Size: 10},
+	}
+	q, err := Compile("size < 1MB and not path startswith 'vendor/'")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	var got []string
+	for _, entry := range q.Filter(entries, time.Now()) {
+		got = append(got, entry.RelPath)
+	}
+	// 一致するファイルを含まないフォルダ（vendor）は除く
+	want := "docs,docs/a.md,src,src/small.go"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Filter() = %v, want %s", got, want)
+	}
+}
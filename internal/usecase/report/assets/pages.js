@@ -0,0 +1,30 @@
+(function () {
+  var view = document.getElementById("page-view");
+  var pages = document.querySelectorAll("template.page");
+  var owner = {};
+  pages.forEach(function (tpl, i) {
+    tpl.content.querySelectorAll("section.file").forEach(function (s) { owner[s.id] = i; });
+  });
+  var current = -1;
+  function show(i) {
+    if (i === current || i < 0 || i >= pages.length) { return; }
+    view.textContent = "";
+    view.appendChild(pages[i].content.cloneNode(true));
+    current = i;
+  }
+  function route() {
+    var id = decodeURIComponent(location.hash.slice(1));
+    if (id in owner) {
+      show(owner[id]);
+      var el = document.getElementById(id);
+      if (el) { el.scrollIntoView(); }
+    } else if (/^page-\d+$/.test(id)) {
+      show(parseInt(id.slice(5), 10) - 1);
+      view.scrollIntoView();
+    }
+  }
+  document.body.classList.add("paged");
+  window.addEventListener("hashchange", route);
+  show(0);
+  route();
+})();
@@ -0,0 +1,163 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// AuditCount は文字コードまたは改行コードごとのファイル数です
+type AuditCount struct {
+	Name  string `json:"name"`
+	Files int    `json:"files"`
+}
+
+// AuditIssue は文字コードまたは改行コードがプロジェクトの主なものとそろっていないファイルです
+type AuditIssue struct {
+	RelPath    string `json:"relPath"`
+	Encoding   string `json:"encoding,omitempty"`
+	LineEnding string `json:"lineEnding,omitempty"`
+	// Reasons はそろっていない理由です
+	Reasons []string `json:"reasons"`
+}
+
+// EncodingAudit はテキストファイルの文字コードと改行コードの集計と、不統一なファイルの一覧です
+type EncodingAudit struct {
+	// Encodings と LineEndings はファイル数の多い順（同数の場合は名前の順）に並べた集計です
+	Encodings   []AuditCount `json:"encodings"`
+	LineEndings []AuditCount `json:"lineEndings"`
+	// MainEncoding と MainLineEnding は最も多くのファイルで使われている文字コードと改行コードです。
+	// 改行コードが混在したファイルは主な改行コードの候補にしません
+	MainEncoding   string       `json:"mainEncoding,omitempty"`
+	MainLineEnding string       `json:"mainLineEnding,omitempty"`
+	Issues         []AuditIssue `json:"issues"`
+}
+
+// computeEncodingAudit はテキストファイルの文字コードと改行コードを集計し、
+// 主な文字コード・改行コードと異なるファイルと、改行コードが混在したファイルを相対パスの順に返します
+func computeEncodingAudit(entries []model.FileSystemEntry) EncodingAudit {
+	encodings := make(map[string]int)
+	lineEndings := make(map[string]int)
+	var files []model.FileSystemEntry
+	for _, entry := range entries {
+		if entry.IsDir || entry.IsBinary || entry.Encoding == "" {
+			continue
+		}
+		files = append(files, entry)
+		encodings[entry.Encoding]++
+		if entry.LineEnding != "" {
+			lineEndings[entry.LineEnding]++
+		}
+	}
+
+	audit := EncodingAudit{Encodings: sortAuditCounts(encodings), LineEndings: sortAuditCounts(lineEndings), Issues: []AuditIssue{}}
+	if len(audit.Encodings) > 0 {
+		audit.MainEncoding = audit.Encodings[0].Name
+	}
+	for _, c := range audit.LineEndings {
+		if c.Name != model.LineEndingMixed {
+			audit.MainLineEnding = c.Name
+			break
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].RelPath < files[j].RelPath
+	})
+	for _, entry := range files {
+		var reasons []string
+		if entry.Encoding != audit.MainEncoding {
+			reasons = append(reasons, fmt.Sprintf("文字コードが %s です（主な文字コード: %s）", entry.Encoding, audit.MainEncoding))
+		}
+		switch entry.LineEnding {
+		case "", audit.MainLineEnding:
+		case model.LineEndingMixed:
+			reasons = append(reasons, "改行コードが混在しています")
+		default:
+			reasons = append(reasons, fmt.Sprintf("改行コードが %s です（主な改行コード: %s）", entry.LineEnding, audit.MainLineEnding))
+		}
+		if len(reasons) > 0 {
+			audit.Issues = append(audit.Issues, AuditIssue{RelPath: entry.RelPath, Encoding: entry.Encoding, LineEnding: entry.LineEnding, Reasons: reasons})
+		}
+	}
+	return audit
+}
+
+// sortAuditCounts は名前ごとのファイル数をファイル数の多い順（同数の場合は名前の順）に並べます
+func sortAuditCounts(counts map[string]int) []AuditCount {
+	result := make([]AuditCount, 0, len(counts))
+	for name, files := range counts {
+		result = append(result, AuditCount{Name: name, Files: files})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Files != result[j].Files {
+			return result[i].Files > result[j].Files
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// formatAuditCounts は集計を "UTF-8 (10), Shift_JIS (2)" の形式で返します
+func formatAuditCounts(counts []AuditCount) string {
+	if len(counts) == 0 {
+		return "なし"
+	}
+	parts := make([]string, len(counts))
+	for i, c := range counts {
+		parts[i] = fmt.Sprintf("%s (%d)", c.Name, c.Files)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeEncodingAudit は文字コード・改行コードの監査結果を出力形式に応じて出力します
+func (g *Generator) writeEncodingAudit(writer io.Writer, audit EncodingAudit) {
+	encodings := "文字コード: " + formatAuditCounts(audit.Encodings)
+	lineEndings := "改行コード: " + formatAuditCounts(audit.LineEndings)
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "## 文字コード・改行コードの監査")
+		fmt.Fprintln(writer)
+		fmt.Fprintf(writer, "- %s\n- %s\n\n", escapeMarkdown(encodings), escapeMarkdown(lineEndings))
+		if len(audit.Issues) == 0 {
+			fmt.Fprintln(writer, "文字コードと改行コードはそろっています。")
+			fmt.Fprintln(writer)
+			return
+		}
+		fmt.Fprintln(writer, "| ファイル | 文字コード | 改行コード | 内容 |\n|---|---|---|---|")
+		for _, issue := range audit.Issues {
+			fmt.Fprintf(writer, "| %s | %s | %s | %s |\n", escapeMarkdown(issue.RelPath), escapeMarkdown(issue.Encoding),
+				escapeMarkdown(issue.LineEnding), escapeMarkdown(strings.Join(issue.Reasons, "、")))
+		}
+		fmt.Fprintln(writer)
+	case FormatHTML:
+		fmt.Fprintln(writer, "<h2>文字コード・改行コードの監査</h2>")
+		fmt.Fprintf(writer, "<ul>\n<li>%s</li>\n<li>%s</li>\n</ul>\n", html.EscapeString(encodings), html.EscapeString(lineEndings))
+		if len(audit.Issues) == 0 {
+			fmt.Fprintln(writer, "<p>文字コードと改行コードはそろっています。</p>")
+			return
+		}
+		fmt.Fprintln(writer, "<table>\n<tr><th>ファイル</th><th>文字コード</th><th>改行コード</th><th>内容</th></tr>")
+		for _, issue := range audit.Issues {
+			fmt.Fprintf(writer, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n", html.EscapeString(issue.RelPath), html.EscapeString(issue.Encoding),
+				html.EscapeString(issue.LineEnding), html.EscapeString(strings.Join(issue.Reasons, "、")))
+		}
+		fmt.Fprintln(writer, "</table>")
+	default:
+		fmt.Fprintln(writer, "===== 文字コード・改行コードの監査 =====")
+		fmt.Fprintf(writer, "  %s\n  %s\n", encodings, lineEndings)
+		if len(audit.Issues) == 0 {
+			fmt.Fprintln(writer, "  文字コードと改行コードはそろっています")
+			fmt.Fprintln(writer)
+			return
+		}
+		for _, issue := range audit.Issues {
+			fmt.Fprintf(writer, "  %s: %s\n", issue.RelPath, strings.Join(issue.Reasons, "、"))
+		}
+		fmt.Fprintln(writer)
+	}
+}
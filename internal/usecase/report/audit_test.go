@@ -0,0 +1,69 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestComputeEncodingAudit(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/b.go", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingLF},
+		{RelPath: "src/a.go", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingLF},
+		{RelPath: "win.bat", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingCRLF},
+		{RelPath: "legacy.txt", Encoding: model.EncodingShiftJIS, LineEnding: model.LineEndingMixed},
+		{RelPath: "empty.txt", Encoding: model.EncodingUTF8},
+		{RelPath: "logo.png", IsBinary: true},
+	}
+	audit := computeEncodingAudit(entries)
+
+	wantEncodings := []AuditCount{{Name: model.EncodingUTF8, Files: 4}, {Name: model.EncodingShiftJIS, Files: 1}}
+	if !reflect.DeepEqual(audit.Encodings, wantEncodings) {
+		t.Errorf("Encodings = %v, want %v", audit.Encodings, wantEncodings)
+	}
+	if audit.MainEncoding != model.EncodingUTF8 || audit.MainLineEnding != model.LineEndingLF {
+		t.Errorf("主な文字コード・改行コード = %s, %s, want UTF-8, LF", audit.MainEncoding, audit.MainLineEnding)
+	}
+	wantIssues := []AuditIssue{
+		{RelPath: "legacy.txt", Encoding: model.EncodingShiftJIS, LineEnding: model.LineEndingMixed,
+			Reasons: []string{"文字コードが Shift_JIS です（主な文字コード: UTF-8）", "改行コードが混在しています"}},
+		{RelPath: "win.bat", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingCRLF,
+			Reasons: []string{"改行コードが CRLF です（主な改行コード: LF）"}},
+	}
+	if !reflect.DeepEqual(audit.Issues, wantIssues) {
+		t.Errorf("Issues = %v, want %v", audit.Issues, wantIssues)
+	}
+}
+
+func TestGenerator_WriteEncodingAudit(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.go", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingLF},
+		{RelPath: "b.go", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingLF},
+		{RelPath: "c.go", Encoding: model.EncodingUTF8, LineEnding: model.LineEndingCRLF},
+	}
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{format: FormatText, want: []string{"===== 文字コード・改行コードの監査 =====", "改行コード: LF (2), CRLF (1)", "  c.go: 改行コードが CRLF です"}},
+		{format: FormatMarkdown, want: []string{"## 文字コード・改行コードの監査", "| c.go | UTF-8 | CRLF | 改行コードが CRLF です"}},
+		{format: FormatHTML, want: []string{"<h2>文字コード・改行コードの監査</h2>", "<td>c.go</td><td>UTF-8</td><td>CRLF</td>"}},
+		{format: FormatJSON, want: []string{`"encodingAudit":{"encodings":[{"name":"UTF-8","files":3}]`, `"mainLineEnding":"LF","issues":[{"relPath":"c.go"`, `"lineEnding":"CRLF"`}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGeneratorWithOptions(Options{Format: tt.format, ShowEncodingAudit: true}).WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
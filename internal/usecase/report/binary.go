@@ -0,0 +1,175 @@
+package report
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// BinaryPolicy はバイナリファイルの扱いです
+type BinaryPolicy string
+
+const (
+	// BinaryOmit はバイナリファイルをスキャン結果から除外し、フォルダ構成にも表示しません
+	BinaryOmit BinaryPolicy = "omit"
+	// BinarySkip はバイナリファイルをフォルダ構成に表示せず、ファイル内容にスキップした旨のみを記載します（既定）
+	BinarySkip BinaryPolicy = "skip"
+	// BinaryStructure はバイナリファイルをフォルダ構成にのみ表示し、ファイル内容のセクションは出力しません
+	BinaryStructure BinaryPolicy = "structure"
+	// BinaryHexdump はバイナリファイルの先頭 HexdumpPreviewBytes バイトを 16 進ダンプで出力します
+	BinaryHexdump BinaryPolicy = "hexdump"
+	// BinaryBase64 はバイナリファイルの内容全体を Base64 で埋め込みます。埋め込むサイズには上限があります
+	BinaryBase64 BinaryPolicy = "base64"
+)
+
+// BinaryPolicies は選択可能なバイナリファイルの扱いの一覧です
+var BinaryPolicies = []BinaryPolicy{BinarySkip, BinaryOmit, BinaryStructure, BinaryHexdump, BinaryBase64}
+
+const (
+	// HexdumpPreviewBytes は BinaryHexdump で出力する先頭のバイト数です
+	HexdumpPreviewBytes = 256
+	// DefaultBinaryEmbedLimit は BinaryBase64 で埋め込むファイルサイズの既定の上限（バイト）です
+	DefaultBinaryEmbedLimit = 64 * 1024
+	// base64LineLength は Base64 で埋め込む内容の 1 行の文字数です（MIME と同じ）
+	base64LineLength = 76
+)
+
+// ParseBinaryPolicy は文字列からバイナリファイルの扱いを解決します。空文字列は BinarySkip として扱います
+func ParseBinaryPolicy(s string) (BinaryPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "skip":
+		return BinarySkip, nil
+	case "omit":
+		return BinaryOmit, nil
+	case "structure":
+		return BinaryStructure, nil
+	case "hexdump", "hex":
+		return BinaryHexdump, nil
+	case "base64":
+		return BinaryBase64, nil
+	}
+	return "", fmt.Errorf("未対応のバイナリファイルの扱いです: %s（skip, omit, structure, hexdump, base64 のいずれかを指定してください）", s)
+}
+
+// inStructure はエントリをフォルダ構成に表示するかどうかを返します。
+// バイナリファイルは、BinaryStructure・BinaryHexdump・BinaryBase64 の場合のみ表示します
+func (g *Generator) inStructure(entry model.FileSystemEntry) bool {
+	if entry.IsDir || !entry.IsBinary {
+		return true
+	}
+	switch g.options.BinaryPolicy {
+	case BinaryStructure, BinaryHexdump, BinaryBase64:
+		return true
+	}
+	return false
+}
+
+// hasSection はエントリのファイル内容のセクションを出力するかどうかを返します。
+// BinaryStructure のバイナリファイルと、サイズの段階が ContentStructure のファイルは出力しません
+func (g *Generator) hasSection(entry model.FileSystemEntry) bool {
+	if entry.IsDir || (entry.IsBinary && g.options.BinaryPolicy == BinaryStructure) {
+		return false
+	}
+	mode, _ := g.contentMode(entry)
+	return mode != ContentStructure
+}
+
+// embedLimit は BinaryBase64 で埋め込むファイルサイズの上限を返します
+func (g *Generator) embedLimit() int64 {
+	if g.options.BinaryEmbedLimit > 0 {
+		return g.options.BinaryEmbedLimit
+	}
+	return DefaultBinaryEmbedLimit
+}
+
+// readBinaryContent はバイナリファイルの扱いに応じて、ファイルセクションに出力する内容を返します。
+// 内容を出力しない場合は、その理由を示す注記を返します
+func (g *Generator) readBinaryContent(entry model.FileSystemEntry) ([]byte, string) {
+	kind := "バイナリファイル"
+	if entry.MIMEType != "" {
+		kind = fmt.Sprintf("バイナリファイル（%s）", entry.MIMEType)
+	}
+	switch g.options.BinaryPolicy {
+	case BinaryHexdump:
+		head, err := g.readFileHead(entry, HexdumpPreviewBytes)
+		if err != nil {
+			return nil, fmt.Sprintf("[ファイル読み込みエラー（レポート生成時）] %v", err)
+		}
+		return []byte(formatHexdump(head, entry.Size)), ""
+	case BinaryBase64:
+		if limit := g.embedLimit(); entry.Size > limit {
+			return nil, fmt.Sprintf("[%sのサイズ（%s）が埋め込みの上限（%s）を超えるためスキップ]", kind, FormatSize(entry.Size), FormatSize(limit))
+		}
+		content, err := g.readStableFile(entry)
+		if err != nil {
+			return nil, fmt.Sprintf("[ファイル読み込みエラー（レポート生成時）] %v", err)
+		}
+		return []byte(formatBase64(content)), ""
+	}
+	return nil, fmt.Sprintf("[%sのためスキップ]", kind)
+}
+
+// readFileHead はエントリのファイル内容の先頭 n バイトまでを読み込みます
+func (g *Generator) readFileHead(entry model.FileSystemEntry, n int64) ([]byte, error) {
+	var (
+		f   fs.File
+		err error
+	)
+	if g.fsys != nil {
+		f, err = g.fsys.Open(entry.RelPath)
+	} else {
+		f, err = os.Open(entry.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, n))
+}
+
+// formatHexdump は先頭 head の 16 進ダンプ（hexdump -C と同じ形式）を返します。
+// ファイル全体のサイズ size が head より大きい場合は、省略したバイト数を末尾に記載します
+func formatHexdump(head []byte, size int64) string {
+	dump := hex.Dump(head)
+	if rest := size - int64(len(head)); rest > 0 {
+		dump += fmt.Sprintf("... 残り %d バイトは省略\n", rest)
+	}
+	return strings.TrimSuffix(dump, "\n")
+}
+
+// formatBase64 は content を base64LineLength 文字ごとに改行した Base64 の文字列で返します
+func formatBase64(content []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var b strings.Builder
+	for len(encoded) > base64LineLength {
+		b.WriteString(encoded[:base64LineLength])
+		b.WriteByte('\n')
+		encoded = encoded[base64LineLength:]
+	}
+	b.WriteString(encoded)
+	return b.String()
+}
+
+// binaryContentSize はバイナリファイルの扱いに応じて出力する内容のサイズを、ファイルを読み込まずに見積もります。
+// 内容を出力しない場合は false を返します
+func (g *Generator) binaryContentSize(entry model.FileSystemEntry) (int64, bool) {
+	switch g.options.BinaryPolicy {
+	case BinaryHexdump:
+		// hex.Dump は 16 バイトごとに 79 バイトの行を出力する。省略したバイト数の記載は見積もりの本文を空にしたレポートに含まれる
+		n := min(entry.Size, HexdumpPreviewBytes)
+		return (n + 15) / 16 * 79, true
+	case BinaryBase64:
+		if entry.Size > g.embedLimit() {
+			return 0, false
+		}
+		encoded := int64(base64.StdEncoding.EncodedLen(int(entry.Size)))
+		return encoded + encoded/base64LineLength, true
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestParseBinaryPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BinaryPolicy
+		wantErr bool
+	}{
+		{input: "", want: BinarySkip},
+		{input: "omit", want: BinaryOmit},
+		{input: " Structure ", want: BinaryStructure},
+		{input: "hex", want: BinaryHexdump},
+		{input: "BASE64", want: BinaryBase64},
+		{input: "zip", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBinaryPolicy(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBinaryPolicy(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestGenerator_BinaryPolicy(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":    {Data: []byte("text\n")},
+		"logo.png": {Data: []byte("\x89PNG\r\n\x1a\n\x00\x00")},
+		"big.bin":  {Data: make([]byte, 300)},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.txt", Size: 5},
+		{RelPath: "big.bin", Size: 300, IsBinary: true},
+		{RelPath: "logo.png", Size: 10, IsBinary: true, MIMEType: "image/png"},
+	}
+
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "既定では構成に表示せず内容を省略する",
+			options: Options{},
+			want:    []string{"----- logo.png -----\n[バイナリファイル（image/png）のためスキップ]"},
+			notWant: []string{"[FILE] logo.png"},
+		},
+		{
+			name:    "構成にのみ表示する",
+			options: Options{BinaryPolicy: BinaryStructure},
+			want:    []string{"[FILE] logo.png", "[FILE] big.bin"},
+			notWant: []string{"----- logo.png -----", "----- big.bin -----"},
+		},
+		{
+			name:    "先頭を16進ダンプで出力する",
+			options: Options{BinaryPolicy: BinaryHexdump},
+			want: []string{
+				"[FILE] logo.png",
+				"----- logo.png -----\n00000000  89 50 4e 47 0d 0a 1a 0a  00 00                    |.PNG......|\n------",
+				"000000f0  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n... 残り 44 バイトは省略\n",
+			},
+			notWant: []string{"00000100"},
+		},
+		{
+			name:    "上限以下のファイルを Base64 で埋め込む",
+			options: Options{BinaryPolicy: BinaryBase64, BinaryEmbedLimit: 100},
+			want: []string{
+				"----- logo.png -----\niVBORw0KGgoAAA==\n------",
+				"[バイナリファイルのサイズ（300 B）が埋め込みの上限（100 B）を超えるためスキップ]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGeneratorWithOptions(tt.options).WithFS(fsys).WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, buf.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(buf.String(), notWant) {
+					t.Errorf("出力に %q が含まれています:\n%s", notWant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestFormatBase64(t *testing.T) {
+	got := formatBase64(make([]byte, 60))
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 || len(lines[0]) != base64LineLength || len(lines[1]) != 4 {
+		t.Errorf("formatBase64() = %q, want 76 文字と 4 文字の 2 行", got)
+	}
+}
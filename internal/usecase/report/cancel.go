@@ -0,0 +1,65 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// WithContext は、ctx がキャンセルされた時点でファイル内容の出力を中止する Generator のコピーを返します。
+// 中止した場合は、出力したところまでのレポートの末尾に中止の理由を記載し、apperrors.ErrCancelled の種類のエラーを返します。
+// 中止できるのはテキスト・Markdown・HTML 形式の既定の構成と、WithFormatter で指定した Formatter で出力する場合です
+func (g *Generator) WithContext(ctx context.Context) *Generator {
+	copied := *g
+	copied.ctx = ctx
+	return &copied
+}
+
+// cancelled は WithContext で指定したコンテキストがキャンセルされたかどうかを返します
+func (g *Generator) cancelled() bool {
+	return g.ctx != nil && g.ctx.Err() != nil
+}
+
+// cancelReason はファイル内容の出力を中止した理由を返します。中止していない場合は空です
+func (g *Generator) cancelReason() apperrors.CancelReason {
+	if !g.interrupted {
+		return ""
+	}
+	return apperrors.ContextReason(g.ctx, context.Cause(g.ctx))
+}
+
+// cancelError は、ファイル内容の出力を中止した場合に、中止を示すエラーを返します。中止していない場合は nil を返します
+func (g *Generator) cancelError() error {
+	if !g.interrupted {
+		return nil
+	}
+	cause := context.Cause(g.ctx)
+	if !errors.Is(cause, apperrors.ErrCancelled) {
+		cause = apperrors.New(apperrors.ErrCancelled, "", "", cause)
+	}
+	return fmt.Errorf("レポートの出力を中止しました: %w", cause)
+}
+
+// writeCancelNote は、ファイル内容の出力を中止した場合に、レポートが途中までであることと中止の理由を出力形式に応じて出力します。
+// reason が空の場合（中止していない場合や、すべてのファイルを出力した後にキャンセルされた場合）は何も出力しません
+func (g *Generator) writeCancelNote(writer io.Writer, reason apperrors.CancelReason) {
+	if reason == "" {
+		return
+	}
+	note := fmt.Sprintf("レポートの出力は途中で中止されました（理由: %s）。以降のファイルの内容は含まれていません。", reason.Label())
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "\n## 出力の中止")
+		fmt.Fprintf(writer, "\n%s\n\n- 理由: `%s`\n", note, reason)
+	case FormatHTML:
+		fmt.Fprintf(writer, "<h2>出力の中止</h2>\n<p data-cancel-reason=\"%s\">%s</p>\n", html.EscapeString(string(reason)), html.EscapeString(note))
+	default:
+		fmt.Fprintln(writer, "\n===== 出力の中止 =====")
+		fmt.Fprintln(writer, note)
+		fmt.Fprintf(writer, "cancelReason: %s\n", reason)
+	}
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_Cancelled(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("content of a")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 12}}
+	signalled, cancel := context.WithCancelCause(context.Background())
+	cancel(apperrors.Cancelled(apperrors.CancelSignal, "interrupt"))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		format  Format
+		want    []string
+		wantErr apperrors.CancelReason
+	}{
+		{
+			name:    "シグナルで中止したテキスト形式",
+			ctx:     signalled,
+			format:  FormatText,
+			want:    []string{"===== 出力の中止 =====", "理由: シグナル", "cancelReason: signal"},
+			wantErr: apperrors.CancelSignal,
+		},
+		{
+			name:    "シグナルで中止した HTML 形式",
+			ctx:     signalled,
+			format:  FormatHTML,
+			want:    []string{"<h2>出力の中止</h2>", `data-cancel-reason="signal"`, "</html>"},
+			wantErr: apperrors.CancelSignal,
+		},
+		{
+			name:   "キャンセルされていない",
+			ctx:    context.Background(),
+			format: FormatMarkdown,
+			want:   []string{"content of a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			err := NewGeneratorWithOptions(Options{Format: tt.format}).WithFS(fsys).WithContext(tt.ctx).WriteReport(&buf, entries)
+			if got := apperrors.ReasonOf(err); got != tt.wantErr {
+				t.Errorf("WriteReport() の中止の理由 = %q, want %q (err = %v)", got, tt.wantErr, err)
+			}
+			if tt.wantErr != "" && !errors.Is(err, apperrors.ErrCancelled) {
+				t.Errorf("WriteReport() error = %v, want ErrCancelled", err)
+			}
+			output := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+				}
+			}
+			if tt.wantErr != "" && strings.Contains(output, "content of a") {
+				t.Errorf("中止した後のファイルの内容が出力されています:\n%s", output)
+			}
+		})
+	}
+}
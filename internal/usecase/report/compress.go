@@ -0,0 +1,182 @@
+package report
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// Compression はレポートファイルの圧縮方式です
+type Compression string
+
+const (
+	// CompressionNone は圧縮せずに出力します
+	CompressionNone Compression = "none"
+	// CompressionGzip はレポートを gzip で圧縮して出力します
+	CompressionGzip Compression = "gzip"
+	// CompressionZip はレポートとインデックスなどの付随するファイルを 1 つの zip にまとめて出力します
+	CompressionZip Compression = "zip"
+)
+
+// ParseCompression は文字列から圧縮方式を解決します。空文字列は CompressionNone として扱います
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip", "gz":
+		return CompressionGzip, nil
+	case "zip":
+		return CompressionZip, nil
+	}
+	return "", fmt.Errorf("未対応の圧縮方式です: %s（none, gzip, zip のいずれかを指定してください）", s)
+}
+
+// OutputExtension は拡張子 extension（先頭のドットを含む）のレポートを圧縮した出力ファイルの拡張子を返します
+func (c Compression) OutputExtension(extension string) string {
+	switch c {
+	case CompressionGzip:
+		return extension + ".gz"
+	case CompressionZip:
+		return ".zip"
+	}
+	return extension
+}
+
+// OutputWriter はレポートを圧縮方式に応じて圧縮しながら出力先に書き込む Writer です。
+// zip の場合は、レポートの後に AddFile でインデックスなどの付随するファイルを追加できます。
+// Close で圧縮を終えてから出力先を閉じます
+type OutputWriter struct {
+	dest io.WriteCloser
+	// reportName は圧縮したファイル内のレポートのファイル名です
+	reportName string
+	// w は Write の書き込み先です。圧縮しない場合は dest です
+	w  io.Writer
+	gz *gzip.Writer
+	zw *zip.Writer
+}
+
+// NewOutputWriter は dest に圧縮方式 c で書き込む OutputWriter を作成します。reportName は zip 内のレポートのファイル名です
+func NewOutputWriter(dest io.WriteCloser, c Compression, reportName string) (*OutputWriter, error) {
+	w := &OutputWriter{dest: dest, reportName: reportName, w: dest}
+	switch c {
+	case CompressionGzip:
+		w.gz = gzip.NewWriter(dest)
+		w.gz.Name = reportName
+		w.gz.ModTime = time.Now()
+		w.w = w.gz
+	case CompressionZip:
+		w.zw = zip.NewWriter(dest)
+		entry, err := w.createEntry(reportName)
+		if err != nil {
+			return nil, err
+		}
+		w.w = entry
+	}
+	return w, nil
+}
+
+// createEntry は zip に name のファイルを追加し、その書き込み先を返します
+func (w *OutputWriter) createEntry(name string) (io.Writer, error) {
+	entry, err := w.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return nil, fmt.Errorf("zip へのファイルの追加に失敗しました: %w", err)
+	}
+	return entry, nil
+}
+
+// Write はレポートを書き込みます
+func (w *OutputWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+// ReportName は圧縮したファイル内のレポートのファイル名です
+func (w *OutputWriter) ReportName() string {
+	return w.reportName
+}
+
+// Bundles は AddFile で付随するファイルを同じ出力ファイルにまとめられるかどうか（zip かどうか）を返します
+func (w *OutputWriter) Bundles() bool {
+	return w.zw != nil
+}
+
+// AddFile は zip に name のファイルを content の内容で追加します。
+// 以降の Write は追加したファイルに書き込まれるため、レポートの書き込みを終えてから呼び出してください
+func (w *OutputWriter) AddFile(name string, content []byte) error {
+	if w.zw == nil {
+		return fmt.Errorf("zip 以外の出力ファイルには %s を追加できません", name)
+	}
+	entry, err := w.createEntry(name)
+	if err != nil {
+		return err
+	}
+	w.w = entry
+	if _, err := entry.Write(content); err != nil {
+		return fmt.Errorf("zip への %s の書き込みに失敗しました: %w", name, err)
+	}
+	return nil
+}
+
+// Close は圧縮を終えてから出力先を閉じます。両方を試み、最初に発生したエラーを返します
+func (w *OutputWriter) Close() error {
+	var err error
+	switch {
+	case w.gz != nil:
+		err = w.gz.Close()
+	case w.zw != nil:
+		err = w.zw.Close()
+	}
+	if closeErr := w.dest.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// CreateOutput は圧縮の指定に応じた拡張子で出力ファイルを作成し、圧縮しながら書き込む OutputWriter を返します。
+// 同じ名前のファイルがすでに存在する場合は apperrors.ErrOutputExists を返します
+func (g *Generator) CreateOutput(outputDir string) (*OutputWriter, string, error) {
+	return CreateOutput(outputDir, g.options.Format.Extension(), g.options.Compression)
+}
+
+// WrapOutput は dest に圧縮の指定に応じて書き込む OutputWriter を返します。標準出力のようにファイル以外に出力する場合に使用します。
+// name は拡張子を除いた圧縮したファイル内のレポートのファイル名です
+func (g *Generator) WrapOutput(dest io.WriteCloser, name string) (*OutputWriter, error) {
+	return NewOutputWriter(dest, g.options.Compression, name+g.options.Format.Extension())
+}
+
+// CreateNamedOutput は outputPath にレポートを書き込む出力ファイルを作成します。
+// 利用者がファイル名を指定した場合に使用し、同じ名前のファイルがすでに存在する場合は上書きします
+func CreateNamedOutput(outputPath string) (*OutputWriter, error) {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return nil, apperrors.Wrap("出力ファイルの作成に失敗しました", outputPath, err)
+	}
+	w, err := NewOutputWriter(file, CompressionNone, filepath.Base(outputPath))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// CreateOutput は拡張子 extension（先頭のドットを含む）のレポートを圧縮方式 c で書き込む出力ファイルを作成します。
+// プラグインのように出力形式が Format で表せない場合に使用します
+func CreateOutput(outputDir, extension string, c Compression) (*OutputWriter, string, error) {
+	file, outputPath, err := CreateOutputFile(outputDir, c.OutputExtension(extension))
+	if err != nil {
+		return nil, "", err
+	}
+	name := strings.TrimSuffix(filepath.Base(outputPath), c.OutputExtension(extension)) + extension
+	w, err := NewOutputWriter(file, c, name)
+	if err != nil {
+		file.Close()
+		return nil, "", err
+	}
+	return w, outputPath, nil
+}
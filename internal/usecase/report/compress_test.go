@@ -0,0 +1,131 @@
+package report
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Compression
+		wantErr bool
+	}{
+		{input: "", want: CompressionNone},
+		{input: "gz", want: CompressionGzip},
+		{input: " ZIP ", want: CompressionZip},
+		{input: "xz", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCompression(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCompression(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestGenerator_CreateOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression Compression
+		wantExt     string
+		read        func(t *testing.T, path string) map[string]string
+	}{
+		{
+			name:        "圧縮しない",
+			compression: CompressionNone,
+			wantExt:     ".md",
+			read: func(t *testing.T, path string) map[string]string {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("ReadFile() error = %v", err)
+				}
+				return map[string]string{"": string(data)}
+			},
+		},
+		{
+			name:        "gzip",
+			compression: CompressionGzip,
+			wantExt:     ".md.gz",
+			read: func(t *testing.T, path string) map[string]string {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("Open() error = %v", err)
+				}
+				defer f.Close()
+				gz, err := gzip.NewReader(f)
+				if err != nil {
+					t.Fatalf("gzip.NewReader() error = %v", err)
+				}
+				data, err := io.ReadAll(gz)
+				if err != nil {
+					t.Fatalf("ReadAll() error = %v", err)
+				}
+				return map[string]string{"": string(data)}
+			},
+		},
+		{
+			name:        "zip",
+			compression: CompressionZip,
+			wantExt:     ".zip",
+			read: func(t *testing.T, path string) map[string]string {
+				zr, err := zip.OpenReader(path)
+				if err != nil {
+					t.Fatalf("zip.OpenReader() error = %v", err)
+				}
+				defer zr.Close()
+				files := make(map[string]string)
+				for _, f := range zr.File {
+					rc, err := f.Open()
+					if err != nil {
+						t.Fatalf("Open(%s) error = %v", f.Name, err)
+					}
+					data, _ := io.ReadAll(rc)
+					rc.Close()
+					files[f.Name] = string(data)
+				}
+				return files
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewGeneratorWithOptions(Options{Format: FormatMarkdown, Compression: tt.compression})
+			output, path, err := generator.CreateOutput(t.TempDir())
+			if err != nil {
+				t.Fatalf("CreateOutput() error = %v", err)
+			}
+			if !strings.HasSuffix(path, tt.wantExt) {
+				t.Errorf("出力ファイル = %s, want 拡張子 %s", path, tt.wantExt)
+			}
+			if _, err := io.WriteString(output, "# report\n"); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			err = output.AddFile("extra.json", []byte("{}"))
+			if output.Bundles() != (err == nil) {
+				t.Errorf("Bundles() = %v, AddFile() error = %v", output.Bundles(), err)
+			}
+			if err := output.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			files := tt.read(t, path)
+			if tt.compression != CompressionZip {
+				if files[""] != "# report\n" {
+					t.Errorf("内容 = %q", files[""])
+				}
+				return
+			}
+			reportName := strings.TrimSuffix(filepath.Base(path), ".zip") + ".md"
+			if output.ReportName() != reportName || files[reportName] != "# report\n" || files["extra.json"] != "{}" {
+				t.Errorf("zip の内容 = %v, ReportName() = %s", files, output.ReportName())
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+
+	"FolderScope/internal/domain/model"
+)
+
+// textDecoders は UTF-8 に変換して出力する文字コードと、その変換方法です
+var textDecoders = map[string]encoding.Encoding{
+	model.EncodingUTF16LE:  unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	model.EncodingUTF16BE:  unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	model.EncodingShiftJIS: japanese.ShiftJIS,
+	model.EncodingEUCJP:    japanese.EUCJP,
+	model.EncodingLatin1:   charmap.ISO8859_1,
+}
+
+// decodeText はスキャン時に判定された文字コードの内容を UTF-8 に変換します。
+// UTF-8 の場合や文字コードが不明な場合は、内容をそのまま返します
+func decodeText(content []byte, encodingName string) ([]byte, error) {
+	enc, ok := textDecoders[encodingName]
+	if !ok {
+		return content, nil
+	}
+	return enc.NewDecoder().Bytes(content)
+}
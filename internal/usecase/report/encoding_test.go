@@ -0,0 +1,41 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_DecodesNonUTF8Text(t *testing.T) {
+	const want = "日本語のテキスト"
+
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding string
+		want     string
+	}{
+		{name: "Shift_JIS", data: []byte("\x93\xfa\x96{\x8c\xea\x82\xcc\x83e\x83L\x83X\x83g"), encoding: model.EncodingShiftJIS, want: want},
+		{name: "EUC-JP", data: []byte("\xc6\xfc\xcb\xdc\xb8\xec\xa4\xce\xa5\xc6\xa5\xad\xa5\xb9\xa5\xc8"), encoding: model.EncodingEUCJP, want: want},
+		{name: "BOM付きUTF-16LE", data: []byte("\xff\xfeA\x00B\x00"), encoding: model.EncodingUTF16LE, want: "AB"},
+		{name: "Latin-1", data: []byte("caf\xe9"), encoding: model.EncodingLatin1, want: "café"},
+		{name: "UTF-8はそのまま", data: []byte(want), encoding: model.EncodingUTF8, want: want},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"a.txt": {Data: tt.data}}
+			entries := []model.FileSystemEntry{{RelPath: "a.txt", Encoding: tt.encoding}}
+
+			var buf strings.Builder
+			if err := NewGenerator().WithFS(fsys).WriteFileContents(&buf, entries); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("出力に %q が含まれていない:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
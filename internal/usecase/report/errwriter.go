@@ -0,0 +1,58 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// errWriter は下位の Writer で最初に発生した書き込みエラーを記録します。
+// エラーの発生後は書き込みを行わずに同じエラーを返すため、各出力処理で個別にエラーを確認する必要がありません
+type errWriter struct {
+	writer io.Writer
+	err    error
+}
+
+// newErrWriter は writer をラップした errWriter を返します。writer がすでに errWriter の場合はそのまま返します
+func newErrWriter(writer io.Writer) *errWriter {
+	if ew, ok := writer.(*errWriter); ok {
+		return ew
+	}
+	return &errWriter{writer: writer}
+}
+
+// Write は下位の Writer に書き込みます。一部のみ書き込まれた場合は io.ErrShortWrite を記録します
+func (w *errWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.writer.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	w.err = err
+	return n, err
+}
+
+// MarkSectionStart は下位の Writer にセクション開始を通知します
+func (w *errWriter) MarkSectionStart(relPath string) {
+	markSectionStart(w.writer, relPath)
+}
+
+// MarkSectionEnd は下位の Writer にセクション終了を通知します
+func (w *errWriter) MarkSectionEnd() {
+	markSectionEnd(w.writer)
+}
+
+// Err は記録された書き込みエラーを返します
+func (w *errWriter) Err() error {
+	if w.err != nil {
+		return fmt.Errorf("レポートの書き込みに失敗しました: %w", w.err)
+	}
+	return nil
+}
+
+// writeFailed は writer で書き込みエラーがすでに発生しているかどうかを返します
+func writeFailed(writer io.Writer) bool {
+	ew, ok := writer.(*errWriter)
+	return ok && ew.err != nil
+}
@@ -0,0 +1,152 @@
+package report
+
+import (
+	"io"
+	"io/fs"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// BytesPerToken はトークン数を見積もる際の 1 トークンあたりのバイト数です（英語のテキストやソースコードでのおおよその目安）
+const BytesPerToken = 4
+
+// FormatEstimate は出力形式ごとのレポートの規模の見積もりです
+type FormatEstimate struct {
+	Format Format `json:"format"`
+	// Bytes はレポートのサイズ（バイト）の見積もりです
+	Bytes int64 `json:"bytes"`
+	// Tokens はレポートのトークン数の見積もりです
+	Tokens int64 `json:"tokens"`
+}
+
+// Estimate はファイル内容を読み込まずに見積もったレポートの規模です
+type Estimate struct {
+	// Dirs と Files はレポートに含まれるフォルダ数とファイル数です
+	Dirs  int `json:"dirs"`
+	Files int `json:"files"`
+	// ContentFiles は内容を出力するファイル数です
+	ContentFiles int `json:"contentFiles"`
+	// BinaryFiles, OversizedFiles, UnreadableFiles は、それぞれバイナリ・サイズの段階（上限の超過）・スキャン時の読み込みエラーにより内容を出力しないファイル数です。
+	// 16 進ダンプや Base64 で内容を出力するバイナリファイルは ContentFiles に数えます
+	BinaryFiles     int `json:"binaryFiles"`
+	OversizedFiles  int `json:"oversizedFiles"`
+	UnreadableFiles int `json:"unreadableFiles"`
+	// ContentBytes は内容を出力するファイルの合計サイズ（バイト）です。先頭と末尾のみを出力するファイルは、その行数から見積もったサイズを加えます
+	ContentBytes int64 `json:"contentBytes"`
+	// Formats は出力形式ごとの見積もりです
+	Formats []FormatEstimate `json:"formats"`
+}
+
+// EstimateTokens はサイズ bytes のテキストのトークン数を BytesPerToken で見積もります
+func EstimateTokens(bytes int64) int64 {
+	return (bytes + BytesPerToken - 1) / BytesPerToken
+}
+
+// Estimate はファイル内容を読み込まずに、formats の各出力形式で entries のレポートを生成した場合の規模を見積もります。
+// 各ファイルの本文を空にしたレポートを実際に生成し、その大きさに内容を出力するファイルのサイズを加えるため、
+// フォルダ構成・見出し・HTML の定型部分などは正確に、本文の文字コードの変換やエスケープによる増減は含めずに見積もります
+func (g *Generator) Estimate(entries []model.FileSystemEntry, formats []Format) (Estimate, error) {
+	var estimate Estimate
+	for _, entry := range entries {
+		if entry.IsDir {
+			estimate.Dirs++
+			continue
+		}
+		estimate.Files++
+		switch {
+		case entry.IsLinkOnly(), g.options.DedupHardLinks && entry.HardLinkOf != "":
+			// 内容を持たないリンクと内容を省略するハードリンクは、注記のみを出力する
+		case entry.IsBinary:
+			if size, ok := g.binaryContentSize(entry); ok {
+				estimate.ContentFiles++
+				estimate.ContentBytes += size
+			} else {
+				estimate.BinaryFiles++
+			}
+		case entry.ReadErr != nil:
+			estimate.UnreadableFiles++
+		default:
+			mode, _ := g.contentMode(entry)
+			if mode == ContentSkip || mode == ContentStructure {
+				estimate.OversizedFiles++
+				continue
+			}
+			estimate.ContentFiles++
+			estimate.ContentBytes += g.estimatedSize(entry, mode)
+		}
+	}
+
+	for _, format := range formats {
+		skeleton := *g
+		skeleton.options.Format = format
+		skeleton.fsys = emptyFS{}
+		// 外部コマンドでの加工や git の変更箇所は本文を読み込むため、見積もりでは使用しない
+		skeleton.filter = nil
+		skeleton.hunks = nil
+		var counter byteCounter
+		if err := skeleton.WriteReport(&counter, entries); err != nil {
+			return estimate, err
+		}
+		bytes := int64(counter) + estimate.ContentBytes
+		estimate.Formats = append(estimate.Formats, FormatEstimate{Format: format, Bytes: bytes, Tokens: EstimateTokens(bytes)})
+	}
+	return estimate, nil
+}
+
+// DeltaTokens は基準からの変更のみを出力したレポートと、すべてのファイルを出力した場合のレポートのトークン数です
+type DeltaTokens struct {
+	// Delta は出力した変更のみのレポートのトークン数の見積もりです
+	Delta int64 `json:"delta"`
+	// Full はすべてのファイルの本文を出力した場合のレポートのトークン数の見積もりです
+	Full int64 `json:"full"`
+}
+
+// Percent は全体のレポートに対する変更のみのレポートのトークン数の割合（%）です
+func (d DeltaTokens) Percent() float64 {
+	if d.Full == 0 {
+		return 0
+	}
+	return float64(d.Delta) * 100 / float64(d.Full)
+}
+
+// CompareDeltaTokens は変更のみを出力したレポートのサイズ deltaBytes と、full のすべてのエントリを同じ出力形式で
+// 出力した場合のレポートの見積もり（Estimate と同様にファイル内容を読み込まずに見積もります）から、トークン数を比較します。
+// 変更のみと全体のどちらを送るほうがプロンプトのトークン数を抑えられるかを判断するために使用します
+func (g *Generator) CompareDeltaTokens(full []model.FileSystemEntry, deltaBytes int64) (DeltaTokens, error) {
+	estimate, err := g.Estimate(full, []Format{g.options.Format})
+	if err != nil {
+		return DeltaTokens{}, err
+	}
+	return DeltaTokens{Delta: EstimateTokens(deltaBytes), Full: estimate.Formats[0].Tokens}, nil
+}
+
+// byteCounter は書き込まれたバイト数のみを数える Writer です
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// emptyFS はすべてのファイルを空の内容として返すファイルシステムです。見積もりでファイル内容を読み込まずにレポートを生成するために使用します
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	return emptyFile{name: name}, nil
+}
+
+// emptyFile は emptyFS が返す空のファイルです
+type emptyFile struct {
+	name string
+}
+
+func (f emptyFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (emptyFile) Read([]byte) (int, error)     { return 0, io.EOF }
+func (emptyFile) Close() error                 { return nil }
+func (f emptyFile) Name() string               { return f.name }
+func (emptyFile) Size() int64                  { return 0 }
+func (emptyFile) Mode() fs.FileMode            { return 0444 }
+func (emptyFile) ModTime() time.Time           { return time.Time{} }
+func (emptyFile) IsDir() bool                  { return false }
+func (emptyFile) Sys() any                     { return nil }
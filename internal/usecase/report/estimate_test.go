@@ -0,0 +1,116 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_Estimate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/main.go": {Data: []byte("package main\n\nfunc main() {}\n")},
+		"README.md":   {Data: []byte("# sample\n")},
+		"big.log":     {Data: []byte(strings.Repeat("x", 2048))},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "README.md", Size: 9},
+		{RelPath: "big.log", Size: 2048},
+		{RelPath: "logo.png", Size: 100, IsBinary: true, MIMEType: "image/png"},
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/main.go", Depth: 1, Size: 29},
+	}
+	generator := NewGeneratorWithOptions(Options{MaxContentSize: 1024}).WithFS(fsys)
+
+	estimate, err := generator.Estimate(entries, []Format{FormatText, FormatHTML})
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if estimate.Dirs != 1 || estimate.Files != 4 || estimate.ContentFiles != 2 ||
+		estimate.BinaryFiles != 1 || estimate.OversizedFiles != 1 || estimate.ContentBytes != 38 {
+		t.Errorf("Estimate() = %+v", estimate)
+	}
+	if len(estimate.Formats) != 2 {
+		t.Fatalf("形式ごとの見積もり = %d 件, want 2", len(estimate.Formats))
+	}
+
+	// 加工を伴わないテキスト形式では、実際に生成したレポートと同じサイズになる
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	text := estimate.Formats[0]
+	if text.Format != FormatText || text.Bytes != int64(buf.Len()) {
+		t.Errorf("テキスト形式の見積もり = %+v, want %d バイト", text, buf.Len())
+	}
+	if text.Tokens != EstimateTokens(text.Bytes) {
+		t.Errorf("Tokens = %d, want %d", text.Tokens, EstimateTokens(text.Bytes))
+	}
+	if html := estimate.Formats[1]; html.Bytes <= text.Bytes {
+		t.Errorf("HTML 形式の見積もり（%d）がテキスト形式（%d）以下です", html.Bytes, text.Bytes)
+	}
+}
+
+func TestGenerator_Estimate_Truncated(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "small.txt", Size: 100},
+		{RelPath: "huge.log", Size: 10 * TruncateSize},
+	}
+	full, err := NewGenerator().Estimate(entries, []Format{FormatText})
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	truncated, err := NewGeneratorWithOptions(Options{SizeTiers: TruncatedSizeTiers}).Estimate(entries, []Format{FormatText})
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if truncated.ContentFiles != 2 || truncated.Formats[0].Bytes >= full.Formats[0].Bytes-9*TruncateSize {
+		t.Errorf("切り詰めた場合の見積もり = %+v, 切り詰めない場合 = %+v", truncated, full)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  int64
+	}{
+		{bytes: 0, want: 0},
+		{bytes: 1, want: 1},
+		{bytes: 4, want: 1},
+		{bytes: 4097, want: 1025},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.bytes); got != tt.want {
+			t.Errorf("EstimateTokens(%d) = %d, want %d", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestGenerator_CompareDeltaTokens(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.go": {Data: []byte(strings.Repeat("a", 400))},
+		"b.go": {Data: []byte(strings.Repeat("b", 400))},
+	}
+	full := []model.FileSystemEntry{{RelPath: "a.go", Size: 400}, {RelPath: "b.go", Size: 400}}
+	// 変更箇所のみを出力する設定でも、全体は本文を出力した場合として見積もる
+	generator := NewGeneratorWithOptions(Options{}).WithFS(fsys).WithHunks(map[string]string{"a.go": "@@ -1 +1 @@\n"})
+
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, full[:1]); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	got, err := generator.CompareDeltaTokens(full, int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("CompareDeltaTokens() error = %v", err)
+	}
+	if got.Delta != EstimateTokens(int64(buf.Len())) || got.Full <= EstimateTokens(800) {
+		t.Errorf("CompareDeltaTokens() = %+v", got)
+	}
+	if p := got.Percent(); p <= 0 || p >= 50 {
+		t.Errorf("Percent() = %v, want 0〜50", p)
+	}
+	if p := (DeltaTokens{}).Percent(); p != 0 {
+		t.Errorf("全体が 0 の場合の Percent() = %v, want 0", p)
+	}
+}
@@ -0,0 +1,202 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// ExportStats はデータ形式のエクスポートに含める統計情報です。
+// 利用側で集計し直さなくてよいよう、エントリ一覧から算出した統計とスキャンの統計をまとめて出力します
+type ExportStats struct {
+	Statistics
+	// ReadErrors は内容を読み込めなかったファイル数です
+	ReadErrors int `json:"readErrors"`
+	// BinaryFiles はバイナリファイルと判定されたファイル数です
+	BinaryFiles int `json:"binaryFiles"`
+	// Languages は言語ごとのファイル数と行数です。言語別の統計が有効な場合のみ出力します
+	Languages []LanguageStats `json:"languages,omitempty"`
+	// Licenses は検出したライセンスファイルとその種類です。ライセンスの検出が有効な場合のみ出力します
+	Licenses []LicenseFile `json:"licenses,omitempty"`
+	// EncodingAudit は文字コード・改行コードの集計と不統一なファイルです。監査が有効な場合のみ出力します
+	EncodingAudit *EncodingAudit `json:"encodingAudit,omitempty"`
+	// Scan はスキャンの所要時間・エラー数・除外理由ごとの件数です。スキャンの統計情報がない場合は省略します
+	Scan *model.ScanStats `json:"scan,omitempty"`
+}
+
+// ExportEntry はデータ形式でエクスポートする 1 要素分の情報です。LoadExport で読み込んだレポートのエントリにも使用します
+type ExportEntry struct {
+	// Type は JSONL 形式でのレコードの種類です（"entry"）
+	Type        string     `json:"type,omitempty"`
+	RelPath     string     `json:"relPath"`
+	IsDir       bool       `json:"isDir"`
+	Size        int64      `json:"size"`
+	ModTime     *time.Time `json:"modTime,omitempty"`
+	Permissions string     `json:"permissions"`
+	Owner       string     `json:"owner,omitempty"`
+	Group       string     `json:"group,omitempty"`
+	Hash        string     `json:"hash,omitempty"`
+	IsBinary    bool       `json:"isBinary,omitempty"`
+	MIMEType    string     `json:"mimeType,omitempty"`
+	Encoding    string     `json:"encoding,omitempty"`
+	LineEnding  string     `json:"lineEnding,omitempty"`
+	// LinkTarget はシンボリックリンクのリンク先、Dangling はリンク先が存在しないかどうか、LinkToDir はリンク先がフォルダかどうかです
+	LinkTarget string `json:"linkTarget,omitempty"`
+	Dangling   bool   `json:"dangling,omitempty"`
+	LinkToDir  bool   `json:"linkToDir,omitempty"`
+	// HardLinkOf は同じ inode を共有するファイルのうち、先に記録したファイルの相対パスです
+	HardLinkOf string `json:"hardLinkOf,omitempty"`
+	// Authors は主な作成者です。作成者の表示が有効な場合のみ出力します
+	Authors string `json:"authors,omitempty"`
+	// Metrics は行数・コメント行数・関数の数などの指標です。指標の表示が有効な場合のみ出力します
+	Metrics *FileMetrics `json:"metrics,omitempty"`
+	// Redactions は内容からマスクした情報の種類と件数です。マスクした場合のみ出力します
+	Redactions []Redaction `json:"redactions,omitempty"`
+	// Content はファイルの内容です。内容を出力できない場合は省略し、Notice に理由を記載します
+	Content *string `json:"content,omitempty"`
+	Notice  string  `json:"notice,omitempty"`
+}
+
+// exportHeader は JSON 形式のドキュメントの先頭部分、および JSONL 形式の先頭行です
+type exportHeader struct {
+	// Type は JSONL 形式でのレコードの種類です（"stats"）
+	Type        string     `json:"type,omitempty"`
+	GeneratedAt *time.Time `json:"generatedAt,omitempty"`
+	// Command はレポートを再作成するコマンドラインです（Options.ReproduceCommand）
+	Command string      `json:"command,omitempty"`
+	Stats   ExportStats `json:"stats"`
+}
+
+// computeExportStats はエクスポートに含める統計情報を算出します
+func (g *Generator) computeExportStats(entries []model.FileSystemEntry) ExportStats {
+	stats := ExportStats{Statistics: ComputeStatistics(entries, DefaultLargestFiles), Scan: g.scanStats}
+	if g.options.ShowLanguages {
+		stats.Languages = g.computeLanguageStats(entries)
+	}
+	if g.options.ShowLicenses {
+		stats.Licenses = g.detectLicenses(entries)
+	}
+	if g.options.ShowEncodingAudit {
+		audit := computeEncodingAudit(entries)
+		stats.EncodingAudit = &audit
+	}
+	for _, entry := range entries {
+		if entry.IsDir {
+			continue
+		}
+		if entry.ReadErr != nil {
+			stats.ReadErrors++
+		}
+		if entry.IsBinary {
+			stats.BinaryFiles++
+		}
+	}
+	return stats
+}
+
+// newExportEntry はエントリをエクスポート用の形式に変換し、ファイルの場合は内容を読み込みます
+func (g *Generator) newExportEntry(entry model.FileSystemEntry) ExportEntry {
+	e := ExportEntry{
+		RelPath:     entry.RelPath,
+		IsDir:       entry.IsDir,
+		Size:        entry.Size,
+		Permissions: entry.Permissions.String(),
+		Owner:       entry.Owner,
+		Group:       entry.Group,
+		Hash:        entry.Hash,
+		IsBinary:    entry.IsBinary,
+		MIMEType:    entry.MIMEType,
+		Encoding:    entry.Encoding,
+		LineEnding:  entry.LineEnding,
+		LinkTarget:  entry.LinkTarget,
+		Dangling:    entry.Dangling,
+		LinkToDir:   entry.LinkToDir,
+		HardLinkOf:  entry.HardLinkOf,
+		Authors:     g.authorsOf(entry),
+	}
+	// 更新日時が不明なエントリと正規化した出力では省略する
+	if !entry.ModTime.IsZero() {
+		e.ModTime = &entry.ModTime
+	}
+	if entry.IsDir {
+		return e
+	}
+	content, notice := g.loadContent(entry)
+	e.Metrics = g.metricsOf(entry, content, notice)
+	e.Redactions = g.redactionsOf(entry.RelPath)
+	if notice != "" {
+		e.Notice = notice
+	} else {
+		text := string(content)
+		e.Content = &text
+	}
+	return e
+}
+
+// writeDataExport は統計情報とエントリ一覧を JSON・JSONL・XML・YAML 形式で出力します。
+// XML と YAML は JSON 形式と同じ構造で出力します。
+// エントリは 1 件ずつ書き出すため、ファイル数が多くても内容をまとめてメモリに保持しません
+func (g *Generator) writeDataExport(writer io.Writer, entries []model.FileSystemEntry) {
+	header := exportHeader{Command: g.options.ReproduceCommand, Stats: g.computeExportStats(entries)}
+	if generatedAt := g.generatedAt(); !generatedAt.IsZero() {
+		header.GeneratedAt = &generatedAt
+	}
+	format := g.options.Format
+	switch format {
+	case FormatJSONL:
+		header.Type = "stats"
+		writeJSONLine(writer, header)
+	case FormatXML:
+		writeXMLHeader(writer, header)
+	case FormatYAML:
+		writeYAMLHeader(writer, header, len(entries) == 0)
+	default:
+		data, _ := json.Marshal(header)
+		// ヘッダーの末尾の '}' を取り除き、entries 配列を続けて出力する
+		fmt.Fprintf(writer, "%s,\"entries\":[", data[:len(data)-1])
+	}
+
+	for i, entry := range entries {
+		if writeFailed(writer) {
+			return
+		}
+		e := g.newExportEntry(entry)
+		if !entry.IsDir {
+			markSectionStart(writer, entry.RelPath)
+		}
+		switch format {
+		case FormatJSONL:
+			e.Type = "entry"
+			writeJSONLine(writer, e)
+		case FormatXML:
+			writeXMLEntry(writer, e)
+		case FormatYAML:
+			writeYAMLEntry(writer, e)
+		default:
+			if i > 0 {
+				io.WriteString(writer, ",")
+			}
+			data, _ := json.Marshal(e)
+			writer.Write(data)
+		}
+		if !entry.IsDir {
+			markSectionEnd(writer)
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		io.WriteString(writer, "]}\n")
+	case FormatXML:
+		writeXMLEnd(writer)
+	}
+}
+
+// writeJSONLine は v を 1 行の JSON として出力します
+func writeJSONLine(writer io.Writer, v any) {
+	data, _ := json.Marshal(v)
+	writer.Write(append(data, '\n'))
+}
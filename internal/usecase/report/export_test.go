@@ -0,0 +1,198 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"FolderScope/internal/domain/model"
+)
+
+func exportTestEntries(t *testing.T) []model.FileSystemEntry {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("alpha"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	return []model.FileSystemEntry{
+		{Path: dir, RelPath: "sub", IsDir: true},
+		{Path: path, RelPath: "sub/a.txt", Size: 5},
+		{Path: filepath.Join(dir, "b.bin"), RelPath: "sub/b.bin", Size: 3, IsBinary: true},
+	}
+}
+
+func TestGenerator_WriteReportJSON(t *testing.T) {
+	scanStats := model.ScanStats{DurationMillis: 42, Errors: 1, Skipped: map[model.SkipReason]int{model.SkipIgnored: 2}}
+	generator := NewGeneratorWithOptions(Options{Format: FormatJSON}).WithScanStats(scanStats)
+
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, exportTestEntries(t)); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	var doc struct {
+		Stats   ExportStats   `json:"stats"`
+		Entries []ExportEntry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &doc); err != nil {
+		t.Fatalf("JSONの解析に失敗: %v\n%s", err, buf.String())
+	}
+	if doc.Stats.TotalFiles != 2 || doc.Stats.TotalDirs != 1 || doc.Stats.TotalBytes != 8 || doc.Stats.BinaryFiles != 1 {
+		t.Errorf("統計情報が不正: %+v", doc.Stats)
+	}
+	if doc.Stats.Scan == nil || doc.Stats.Scan.DurationMillis != 42 || doc.Stats.Scan.Skipped[model.SkipIgnored] != 2 {
+		t.Errorf("スキャンの統計情報が不正: %+v", doc.Stats.Scan)
+	}
+	if len(doc.Entries) != 3 {
+		t.Fatalf("エントリ数 = %d, want 3", len(doc.Entries))
+	}
+	if c := doc.Entries[1].Content; c == nil || *c != "alpha" {
+		t.Errorf("ファイルの内容が出力されていません: %+v", doc.Entries[1])
+	}
+	if doc.Entries[2].Content != nil || doc.Entries[2].Notice == "" {
+		t.Errorf("バイナリファイルは内容の代わりに注記を出力すべきです: %+v", doc.Entries[2])
+	}
+}
+
+func TestGenerator_WriteReportJSON_LargestFiles(t *testing.T) {
+	for _, normalize := range []bool{false, true} {
+		generator := NewGeneratorWithOptions(Options{Format: FormatJSON, Normalize: normalize})
+
+		var buf strings.Builder
+		if err := generator.WriteReport(&buf, exportTestEntries(t)); err != nil {
+			t.Fatalf("WriteReport() error = %v", err)
+		}
+
+		var doc struct {
+			Stats struct {
+				LargestFiles []map[string]any `json:"largestFiles"`
+			} `json:"stats"`
+		}
+		if err := json.Unmarshal([]byte(buf.String()), &doc); err != nil {
+			t.Fatalf("JSONの解析に失敗: %v\n%s", err, buf.String())
+		}
+		want := []map[string]any{
+			{"relPath": "sub/a.txt", "size": float64(5)},
+			{"relPath": "sub/b.bin", "size": float64(3)},
+		}
+		if !reflect.DeepEqual(doc.Stats.LargestFiles, want) {
+			t.Errorf("normalize=%v: largestFiles = %v; want %v", normalize, doc.Stats.LargestFiles, want)
+		}
+	}
+}
+
+func TestGenerator_WriteReportJSONL(t *testing.T) {
+	var buf strings.Builder
+	writer := NewIndexingWriter(&buf)
+	if err := NewGeneratorWithOptions(Options{Format: FormatJSONL}).WriteReport(writer, exportTestEntries(t)); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	var types []string
+	scanner := bufio.NewScanner(strings.NewReader(buf.String()))
+	for scanner.Scan() {
+		var record struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("JSONの解析に失敗: %v\n%s", err, scanner.Text())
+		}
+		types = append(types, record.Type)
+	}
+	if got := strings.Join(types, ","); got != "stats,entry,entry,entry" {
+		t.Errorf("レコードの種類 = %s", got)
+	}
+
+	// ファイルのレコードは 1 行ずつインデックスに記録される
+	entries := writer.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("インデックスのエントリ数 = %d, want 2", len(entries))
+	}
+	section, err := ReadSection(strings.NewReader(buf.String()), entries[0])
+	if err != nil || !strings.Contains(string(section), `"relPath":"sub/a.txt"`) {
+		t.Errorf("セクション = %q, error = %v", section, err)
+	}
+}
+
+func TestGenerator_WriteReportXML(t *testing.T) {
+	scanStats := model.ScanStats{DurationMillis: 42, Skipped: map[model.SkipReason]int{model.SkipIgnored: 2}}
+	var buf strings.Builder
+	if err := NewGeneratorWithOptions(Options{Format: FormatXML}).WithScanStats(scanStats).WriteReport(&buf, exportTestEntries(t)); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"folderscope"`
+		Stats   struct {
+			TotalFiles int `xml:"totalFiles"`
+			Scan       struct {
+				DurationMillis int64 `xml:"durationMs"`
+				Ignored        int   `xml:"skipped>ignored"`
+			} `xml:"scan"`
+		} `xml:"stats"`
+		Entries []struct {
+			RelPath string  `xml:"relPath"`
+			IsDir   bool    `xml:"isDir"`
+			Content *string `xml:"content"`
+			Notice  string  `xml:"notice"`
+		} `xml:"entries>entry"`
+	}
+	if err := xml.Unmarshal([]byte(buf.String()), &doc); err != nil {
+		t.Fatalf("XMLの解析に失敗: %v\n%s", err, buf.String())
+	}
+	if doc.Stats.TotalFiles != 2 || doc.Stats.Scan.DurationMillis != 42 || doc.Stats.Scan.Ignored != 2 {
+		t.Errorf("統計情報が不正: %+v", doc.Stats)
+	}
+	if len(doc.Entries) != 3 {
+		t.Fatalf("エントリ数 = %d, want 3\n%s", len(doc.Entries), buf.String())
+	}
+	if e := doc.Entries[1]; e.RelPath != "sub/a.txt" || e.Content == nil || *e.Content != "alpha" {
+		t.Errorf("ファイルの内容が出力されていません: %+v", e)
+	}
+	if e := doc.Entries[2]; e.Content != nil || e.Notice == "" {
+		t.Errorf("バイナリファイルは内容の代わりに注記を出力すべきです: %+v", e)
+	}
+}
+
+func TestGenerator_WriteReportYAML(t *testing.T) {
+	entries := exportTestEntries(t)
+	if err := os.WriteFile(entries[1].Path, []byte("line1\nline2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	if err := NewGeneratorWithOptions(Options{Format: FormatYAML}).WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	output := buf.String()
+	// entries の要素は stats の largestFiles と同じ字下げで出力する
+	if !strings.Contains(output, "  largestFiles:\n    - relPath: sub/a.txt\n") || !strings.Contains(output, "entries:\n  - relPath: sub\n") {
+		t.Errorf("シーケンスの字下げが揃っていません:\n%s", output)
+	}
+	// 複数行の内容はリテラルブロックで出力する
+	if !strings.Contains(output, "content: |\n      line1\n      line2\n") {
+		t.Errorf("内容がリテラルブロックで出力されていません:\n%s", output)
+	}
+
+	var doc struct {
+		Stats   ExportStats   `yaml:"stats"`
+		Entries []ExportEntry `yaml:"entries"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("YAMLの解析に失敗: %v\n%s", err, output)
+	}
+	if len(doc.Entries) != 3 || doc.Entries[1].Content == nil || *doc.Entries[1].Content != "line1\nline2\n" {
+		t.Errorf("エントリが不正: %+v\n%s", doc.Entries, output)
+	}
+
+	buf.Reset()
+	if err := NewGeneratorWithOptions(Options{Format: FormatYAML}).WriteReport(&buf, nil); err != nil || !strings.HasSuffix(buf.String(), "entries: []\n") {
+		t.Errorf("エントリがない場合の出力 = %q, %v", buf.String(), err)
+	}
+}
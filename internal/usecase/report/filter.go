@@ -0,0 +1,66 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContentFilter はレポートに含める前にファイルの内容を加工します（機密情報のマスキングや形式の変換など）
+type ContentFilter interface {
+	// Filter は relPath のファイルの内容 content を加工した結果を返します
+	Filter(relPath string, content []byte) ([]byte, error)
+}
+
+// FilterFallback は ContentFilter が失敗した場合の扱いです
+type FilterFallback string
+
+const (
+	// FallbackOriginal は加工前の内容をそのまま出力します
+	FallbackOriginal FilterFallback = "original"
+	// FallbackSkip は内容を出力せず、失敗した旨を記載します
+	FallbackSkip FilterFallback = "skip"
+)
+
+// ParseFilterFallback は文字列から失敗時の扱いを解決します。空文字列は加工前の内容を出力する扱いとします
+func ParseFilterFallback(s string) (FilterFallback, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "original":
+		return FallbackOriginal, nil
+	case "skip":
+		return FallbackSkip, nil
+	}
+	return "", fmt.Errorf("未対応の失敗時の扱いです: %s（original, skip のいずれかを指定してください）", s)
+}
+
+// WithContentFilter は、出力するファイルの内容を filter で加工する Generator のコピーを返します。
+// 加工に失敗した場合は fallback に従って出力します
+func (g *Generator) WithContentFilter(filter ContentFilter, fallback FilterFallback) *Generator {
+	copied := *g
+	copied.filter = filter
+	copied.filterFallback = fallback
+	return &copied
+}
+
+// chainFilter は複数の ContentFilter を順に適用します
+type chainFilter []ContentFilter
+
+// ChainFilters は filters を指定された順に適用する ContentFilter を返します。
+// いずれかが失敗した場合は、その時点で処理を中止してエラーを返します
+func ChainFilters(filters ...ContentFilter) ContentFilter {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	return chainFilter(filters)
+}
+
+// Filter は各 ContentFilter を順に適用した結果を返します
+func (c chainFilter) Filter(relPath string, content []byte) ([]byte, error) {
+	for _, filter := range c {
+		filtered, err := filter.Filter(relPath, content)
+		if err != nil {
+			return nil, err
+		}
+		content = filtered
+	}
+	return content, nil
+}
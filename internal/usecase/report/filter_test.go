@@ -0,0 +1,117 @@
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+// upperFilter は内容を大文字に変換し、fail に含まれるファイルでは失敗するフィルタです
+type upperFilter struct {
+	fail map[string]bool
+}
+
+func (f upperFilter) Filter(relPath string, content []byte) ([]byte, error) {
+	if f.fail[relPath] {
+		return nil, errors.New("filter failed")
+	}
+	return []byte(strings.ToUpper(string(content))), nil
+}
+
+func TestParseFilterFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FilterFallback
+		wantErr bool
+	}{
+		{name: "空文字列", input: "", want: FallbackOriginal},
+		{name: "original", input: "original", want: FallbackOriginal},
+		{name: "大文字のskip", input: "SKIP", want: FallbackSkip},
+		{name: "未対応の値", input: "retry", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilterFallback(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilterFallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFilterFallback() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_WithContentFilter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ok.txt":   {Data: []byte("secret")},
+		"fail.txt": {Data: []byte("original")},
+	}
+	entries := []model.FileSystemEntry{{RelPath: "fail.txt"}, {RelPath: "ok.txt"}}
+	filter := upperFilter{fail: map[string]bool{"fail.txt": true}}
+
+	tests := []struct {
+		name     string
+		fallback FilterFallback
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "失敗時は加工前の内容を出力",
+			fallback: FallbackOriginal,
+			want:     []string{"SECRET", "original"},
+			notWant:  []string{"スキップ"},
+		},
+		{
+			name:     "失敗時はスキップ",
+			fallback: FallbackSkip,
+			want:     []string{"SECRET", "[外部コマンドによる処理に失敗したためスキップ] filter failed"},
+			notWant:  []string{"original"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			generator := NewGenerator().WithFS(fsys).WithContentFilter(filter, tt.fallback)
+			if err := generator.WriteFileContents(&buf, entries); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("出力に %q が含まれていない:\n%s", s, buf.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("出力に %q が含まれている:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
+
+// suffixFilter は内容の末尾に文字列を追加するフィルタです
+type suffixFilter string
+
+func (f suffixFilter) Filter(relPath string, content []byte) ([]byte, error) {
+	return append(content, f...), nil
+}
+
+func TestChainFilters(t *testing.T) {
+	chain := ChainFilters(upperFilter{fail: map[string]bool{"ng.txt": true}}, suffixFilter("!"))
+
+	got, err := chain.Filter("a.txt", []byte("abc"))
+	if err != nil || string(got) != "ABC!" {
+		t.Errorf("Filter() = %q, %v, want ABC!", got, err)
+	}
+	// 途中のフィルタが失敗した場合は、以降のフィルタを適用せずにエラーを返す
+	if _, err := chain.Filter("ng.txt", []byte("abc")); err == nil {
+		t.Error("Filter() error = nil, want エラー")
+	}
+}
@@ -0,0 +1,183 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// Format はレポートの出力形式を表します
+type Format string
+
+const (
+	// FormatText はインデントと [DIR]/[FILE] による従来のテキスト形式です
+	FormatText Format = "text"
+	// FormatMarkdown は構成と内容が相互リンクされた Markdown 形式です
+	FormatMarkdown Format = "markdown"
+	// FormatHTML は構成と内容が相互リンクされた HTML 形式です
+	FormatHTML Format = "html"
+	// FormatJSON は統計情報とエントリ一覧を 1 つの JSON ドキュメントとして出力する形式です
+	FormatJSON Format = "json"
+	// FormatJSONL は統計情報とエントリを 1 行に 1 件ずつ JSON で出力する形式です
+	FormatJSONL Format = "jsonl"
+	// FormatXML は JSON 形式と同じ構造の統計情報とエントリ一覧を XML で出力する形式です
+	FormatXML Format = "xml"
+	// FormatYAML は JSON 形式と同じ構造の統計情報とエントリ一覧を YAML で出力する形式です
+	FormatYAML Format = "yaml"
+	// FormatPDF はテキスト形式の内容を等幅フォントで組版し、ページヘッダーとしおりを付けた PDF 形式です
+	FormatPDF Format = "pdf"
+	// FormatSQLite はスキャン結果を scans・entries・contents テーブルに記録した SQLite データベースの形式です
+	FormatSQLite Format = "sqlite"
+)
+
+// SupportedFormats は対応している出力形式の一覧です
+var SupportedFormats = []Format{FormatText, FormatMarkdown, FormatHTML, FormatJSON, FormatJSONL, FormatXML, FormatYAML, FormatPDF, FormatSQLite}
+
+// ParseFormat は文字列から出力形式を解決します。空文字列はテキスト形式として扱います
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "text", "txt":
+		return FormatText, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "xml":
+		return FormatXML, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "pdf":
+		return FormatPDF, nil
+	case "sqlite", "sqlite3", "db":
+		return FormatSQLite, nil
+	}
+	return "", fmt.Errorf("未対応の出力形式です: %s", s)
+}
+
+// FormatFromPath は出力ファイルのパスの拡張子から出力形式を判定します。
+// 拡張子が出力形式に対応しない場合や、SQLite のように出力先フォルダのファイルに追記する形式の場合は false を返します
+func FormatFromPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt":
+		return FormatText, true
+	case ".md", ".markdown":
+		return FormatMarkdown, true
+	case ".html", ".htm":
+		return FormatHTML, true
+	case ".json":
+		return FormatJSON, true
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, true
+	case ".xml":
+		return FormatXML, true
+	case ".yaml", ".yml":
+		return FormatYAML, true
+	case ".pdf":
+		return FormatPDF, true
+	}
+	return "", false
+}
+
+// Extension は出力形式に対応するファイル拡張子を返します
+func (f Format) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatHTML:
+		return ".html"
+	case FormatJSON:
+		return ".json"
+	case FormatJSONL:
+		return ".jsonl"
+	case FormatXML:
+		return ".xml"
+	case FormatYAML:
+		return ".yaml"
+	case FormatPDF:
+		return ".pdf"
+	case FormatSQLite:
+		return ".sqlite"
+	default:
+		return OutputFileSuffix
+	}
+}
+
+// isData は人が読む文書ではなく、他のツールで処理するためのデータ形式かどうかを返します
+func (f Format) isData() bool {
+	return f == FormatJSON || f == FormatJSONL || f == FormatXML || f == FormatYAML
+}
+
+// anchors はエントリの相対パスからレポート内のアンカーIDを決定します。
+// 構成側（tree-）と内容側（file-）で同じ接尾辞を使用し、相互にリンクできるようにします
+type anchors map[string]string
+
+// anchorHashLength はアンカーIDの接尾辞に含める、相対パスのハッシュの桁数（16進）です
+const anchorHashLength = 8
+
+// buildAnchors はエントリ一覧からアンカーIDを生成します。
+// IDは相対パスを英数字に変換した文字列と、相対パスの SHA-256 ハッシュの先頭 anchorHashLength 桁から作るため、
+// エントリの並び順や他のエントリの有無によらず、同じパスには再生成したレポートでも常に同じIDが付きます。
+// 外部の差分ツールや注釈ツールが、再生成したレポート間でファイルのセクションを参照するために使用できます
+func buildAnchors(entries []model.FileSystemEntry) anchors {
+	result := make(anchors, len(entries))
+	taken := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		base := anchorID(entry.RelPath)
+		// ハッシュの先頭の桁まで一致することはほぼないが、IDの重複だけは避ける
+		slug := base
+		for n := 2; taken[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[slug] = true
+		result[entry.RelPath] = slug
+	}
+	return result
+}
+
+// anchorID は相対パスからアンカーIDの接尾辞（"src-main-go-1a2b3c4d" など）を返します。
+// 区切り文字は "/" にそろえてからハッシュを求めるため、OS によらず同じIDになります
+func anchorID(relPath string) string {
+	slashed := filepath.ToSlash(relPath)
+	sum := sha256.Sum256([]byte(slashed))
+	return slugify(slashed) + "-" + hex.EncodeToString(sum[:])[:anchorHashLength]
+}
+
+// tree は構成側のアンカーIDを返します
+func (a anchors) tree(relPath string) string {
+	return "tree-" + a[relPath]
+}
+
+// file は内容側のアンカーIDを返します
+func (a anchors) file(relPath string) string {
+	return "file-" + a[relPath]
+}
+
+// slugify はパスを英数字とハイフンのみからなるアンカー用文字列に変換します
+func slugify(path string) string {
+	var b strings.Builder
+	lastHyphen := false
+	for _, r := range strings.ToLower(path) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastHyphen = false
+			continue
+		}
+		if !lastHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		slug = "entry"
+	}
+	return slug
+}
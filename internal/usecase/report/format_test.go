@@ -0,0 +1,218 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"txt", FormatText, false},
+		{"md", FormatMarkdown, false},
+		{"Markdown", FormatMarkdown, false},
+		{"html", FormatHTML, false},
+		{"pdf", FormatPDF, false},
+		{"sqlite3", FormatSQLite, false},
+		{"docx", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %v, %v; want %v, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		want   Format
+		wantOK bool
+	}{
+		{"Markdown", "out/report.md", FormatMarkdown, true},
+		{"大文字の拡張子", "REPORT.JSON", FormatJSON, true},
+		{"HTML", "report.htm", FormatHTML, true},
+		{"テキスト", "report.txt", FormatText, true},
+		{"JSONL", "report.ndjson", FormatJSONL, true},
+		{"出力形式に対応しない拡張子", "report.log", "", false},
+		{"SQLite はフォルダに追記するため判定しない", "report.sqlite", "", false},
+		{"拡張子なし", "report", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FormatFromPath(tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FormatFromPath(%q) = %v, %v; want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBuildAnchors(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "dir/file.txt"},
+		{RelPath: "dir_file.txt"},
+		{RelPath: "日本語.txt"},
+	}
+	a := buildAnchors(entries)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "パスの英数字とハッシュ", got: a.file("dir/file.txt"), want: "file-dir-file-txt-781336c8"},
+		{name: "英数字が同じパスはハッシュで区別", got: a.tree("dir_file.txt"), want: "tree-dir-file-txt-5f2804d7"},
+		{name: "非ASCIIパス", got: a.file("日本語.txt"), want: "file-txt-e1422b28"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: アンカー = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// 並び順や他のエントリの有無によらず、同じパスには同じアンカーが付く
+	reordered := buildAnchors([]model.FileSystemEntry{{RelPath: "dir_file.txt"}, {RelPath: "new.txt"}, {RelPath: "dir/file.txt"}})
+	for _, relPath := range []string{"dir/file.txt", "dir_file.txt"} {
+		if reordered.file(relPath) != a.file(relPath) {
+			t.Errorf("%s のアンカーが並び順で変わります: %v, %v", relPath, reordered.file(relPath), a.file(relPath))
+		}
+	}
+}
+
+func writeCrossRefFixture(t *testing.T) []model.FileSystemEntry {
+	t.Helper()
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "src", "main.go")
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		t.Fatalf("ディレクトリの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte("package main // <tag> & ```"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	return []model.FileSystemEntry{
+		{Path: filepath.Dir(filePath), IsDir: true, RelPath: "src", Depth: 0},
+		{Path: filePath, RelPath: "src/main.go", Depth: 1},
+		{Path: filepath.Join(tempDir, "img.png"), RelPath: "img.png", Depth: 0, IsBinary: true},
+	}
+}
+
+func TestGenerator_WriteReportMarkdown(t *testing.T) {
+	generator := NewGeneratorWithOptions(Options{Format: FormatMarkdown})
+	var buf strings.Builder
+	generator.WriteReport(&buf, writeCrossRefFixture(t))
+
+	output := buf.String()
+	expected := []string{
+		"## フォルダ・ファイル構成",
+		`- <a id="tree-src-25a66342"></a>📁 src/`,
+		`  - <a id="tree-src-main-go-9e185f29"></a>[src/main.go](#file-src-main-go-9e185f29)`,
+		`### <a id="file-src-main-go-9e185f29"></a>src/main.go`,
+		"[↑ 構成に戻る](#tree-src-main-go-9e185f29)",
+		"````go\npackage main // <tag> & ```\n````",
+		"[バイナリファイルのためスキップ]",
+	}
+	for _, sub := range expected {
+		if !strings.Contains(output, sub) {
+			t.Errorf("出力に期待される部分文字列が含まれていない: %q\nOutput:\n%s", sub, output)
+		}
+	}
+	if strings.Contains(output, "(#tree-img-png-2fcbc7ca)") {
+		t.Errorf("構成に含まれないバイナリファイルへの戻りリンクが出力されています")
+	}
+}
+
+func TestGenerator_WriteReportHTML(t *testing.T) {
+	generator := NewGeneratorWithOptions(Options{Format: FormatHTML})
+	var buf strings.Builder
+	generator.WriteReport(&buf, writeCrossRefFixture(t))
+
+	output := buf.String()
+	expected := []string{
+		"<!DOCTYPE html>",
+		`<span id="tree-src-main-go-9e185f29">  [FILE] <a href="#file-src-main-go-9e185f29">src/main.go</a></span>`,
+		`<section class="file" id="file-src-main-go-9e185f29">`,
+		`<a class="back" href="#tree-src-main-go-9e185f29">`,
+		"package main // &lt;tag&gt; &amp; ```",
+		"</html>",
+	}
+	for _, sub := range expected {
+		if !strings.Contains(output, sub) {
+			t.Errorf("出力に期待される部分文字列が含まれていない: %q\nOutput:\n%s", sub, output)
+		}
+	}
+}
+
+func TestGenerator_CreateOutputFileExtension(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, format := range SupportedFormats {
+		file, path, err := NewGeneratorWithOptions(Options{Format: format}).CreateOutputFile(tempDir)
+		if err != nil {
+			t.Fatalf("CreateOutputFile() error = %v", err)
+		}
+		file.Close()
+		if filepath.Ext(path) != format.Extension() {
+			t.Errorf("%s 形式の拡張子が不正: %v", format, path)
+		}
+	}
+}
+
+func TestGenerator_WriteReportHTMLPaginated(t *testing.T) {
+	tempDir := t.TempDir()
+	var entries []model.FileSystemEntry
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+		entries = append(entries, model.FileSystemEntry{Path: path, RelPath: name})
+	}
+
+	generator := NewGeneratorWithOptions(Options{Format: FormatHTML, HTMLPageSize: 2})
+	var buf strings.Builder
+	generator.WriteReport(&buf, entries)
+	output := buf.String()
+
+	if got := strings.Count(output, `<template class="page"`); got != 2 {
+		t.Errorf("ページ数が不正: got %d, want 2", got)
+	}
+	if got := strings.Count(output, "</template>"); got != 2 {
+		t.Errorf("ページ終了タグの数が不正: got %d, want 2", got)
+	}
+
+	// 2 ページ目のテンプレート内に c.txt のセクションが含まれること
+	page2 := output[strings.Index(output, `data-page="2"`):]
+	if !strings.Contains(page2[:strings.Index(page2, "</template>")], `id="file-c-txt-4fe00619"`) {
+		t.Errorf("2ページ目の内容が不正:\n%s", page2)
+	}
+
+	expected := []string{
+		`<nav class="pages">`,
+		`<a href="#page-2">ページ 2</a> (1件)`,
+		`<li><a href="#file-a-txt-18b7cb09">a.txt</a></li>`,
+		`<div id="page-view"></div>`,
+		"<script>",
+	}
+	for _, sub := range expected {
+		if !strings.Contains(output, sub) {
+			t.Errorf("出力に期待される部分文字列が含まれていない: %q", sub)
+		}
+	}
+
+	// 増分生成でも同じページ構成になること
+	var incremental strings.Builder
+	NewIncrementalGenerator(generator).Write(&incremental, entries)
+	if incremental.String() != output {
+		t.Errorf("増分生成の出力がページ分割された全生成と一致しません")
+	}
+}
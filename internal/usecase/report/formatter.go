@@ -0,0 +1,255 @@
+package report
+
+import (
+	"io"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+)
+
+// Formatter はレポートの出力形式を実装するインターフェースです。
+// Generator はエントリの絞り込み・並べ替え、ファイル内容の読み込みとマスク、中止の判定を行い、
+// WriteHeader、フォルダ構成のエントリごとの WriteEntry、内容を出力するファイルごとの WriteContent、WriteFooter の順に呼び出します。
+// WithFormatter で指定すると、既存のコードを変更せずに新しい出力形式を追加できます。
+// テキスト・Markdown・HTML 形式も、引数で渡される値のみから書き込む Formatter として実装しています。
+// いずれかのメソッドがエラーを返した場合は、以降の出力を中止してそのエラーを返します
+type Formatter interface {
+	// WriteHeader はレポートの先頭部分（文書の開始・サマリー・フォルダ構成の見出しなど）を書き込みます
+	WriteHeader(w io.Writer, header ReportHeader) error
+	// WriteEntry はフォルダ構成の 1 エントリを書き込みます
+	WriteEntry(w io.Writer, entry StructureEntry) error
+	// WriteContent は 1 ファイル分の内容のセクションを書き込みます
+	WriteContent(w io.Writer, content FileContent) error
+	// WriteFooter はレポートの末尾部分（中止の理由・スキャンの統計・文書の終了など）を書き込みます
+	WriteFooter(w io.Writer, footer ReportFooter) error
+}
+
+// ReportHeader は Formatter.WriteHeader に渡す、レポート全体の情報です
+type ReportHeader struct {
+	// GeneratedAt はレポートの作成日時です。正規化した出力（Options.Normalize）ではゼロ値です
+	GeneratedAt time.Time
+	// Entries はフォルダ構成のすべてのエントリ（フォルダを含む）です
+	Entries []model.FileSystemEntry
+	// Files は WriteContent で内容を書き込むファイルを、書き込む順に並べたものです
+	Files []model.FileSystemEntry
+	// Stats はファイル数・合計サイズ・拡張子別などの統計情報です
+	Stats Statistics
+	// ScanStats はスキャンの統計情報です。WithScanStats で指定していない場合は nil です
+	ScanStats *model.ScanStats
+	// Inaccessible は権限がないために読み込めなかったパスです（WithScanStats で指定した統計情報のもの）
+	Inaccessible []model.InaccessiblePath
+}
+
+// StructureEntry は Formatter.WriteEntry に渡す、フォルダ構成の 1 行です
+type StructureEntry struct {
+	model.FileSystemEntry
+	// Prefix は行頭の字下げ、または罫線（Options.TreeStyle が TreeLines の場合）です
+	Prefix string
+	// Listing は Options.LongListing が有効な場合に Prefix の前に表示する、桁をそろえたパーミッション・所有者・サイズなどの列です
+	Listing string
+	// Annotation はリンク先・ハードリンク・メタデータ・ハッシュなどの補足情報です
+	Annotation string
+}
+
+// FileContent は Formatter.WriteContent に渡す、1 ファイル分の内容です
+type FileContent struct {
+	model.FileSystemEntry
+	// Content は秘密情報をマスクし、抜粋・行番号などを適用した本文です。Notice が空でない場合は空です
+	Content []byte
+	// Notice は本文を出力しない理由（バイナリファイル・サイズの上限超過など）です
+	Notice string
+	// Authors は git の主な作成者です（WithAuthors を指定した場合のみ）
+	Authors string
+	// Metrics は行数などの指標です（Options.ShowMetrics が有効な場合のみ）
+	Metrics *FileMetrics
+	// Redactions は本文でマスクした情報の種類と件数です
+	Redactions []Redaction
+	// Language はコードブロックに指定する言語名です。変更箇所のみを出力する場合は "diff" です
+	Language string
+	// InStructure はフォルダ構成にも表示しているかどうかを示します
+	InStructure bool
+}
+
+// ReportFooter は Formatter.WriteFooter に渡す、レポートの末尾に記載する情報です
+type ReportFooter struct {
+	// CancelReason は、WithContext で指定したコンテキストがキャンセルされてファイル内容の出力を中止した場合の理由です。中止していない場合は空です
+	CancelReason apperrors.CancelReason
+	// ScanStats はスキャンの統計情報です。WithScanStats で指定していない場合と、正規化した出力では nil です
+	ScanStats *model.ScanStats
+	// ReproduceCommand はレポートを再作成するコマンドライン（Options.ReproduceCommand）です
+	ReproduceCommand string
+	// Redactions は秘密情報などをマスクしたファイルと、マスクした情報の種類と件数です（内容を出力した順）
+	Redactions []RedactedFile
+	// Modified はスキャンの後に変更されていたため、スキャン時とは異なる内容を出力したファイルです（内容を出力した順）
+	Modified []ModifiedFile
+}
+
+// ContentFramer は、ファイルごとの内容のセクションの前後に、セクションに含めない内容（内容の見出し・ページの区切りなど）を書き込む
+// Formatter が追加で実装するインターフェースです。実装している場合は、WriteContent の前に BeforeContent を、後に AfterContent を呼び出します。
+// インデックス（NewIndexingWriter）に記録するセクションの範囲には、BeforeContent と AfterContent で書き込んだ内容を含めません
+type ContentFramer interface {
+	BeforeContent(w io.Writer, entry model.FileSystemEntry) error
+	AfterContent(w io.Writer, entry model.FileSystemEntry) error
+}
+
+// WithFormatter は、出力形式（Options.Format）にかかわらず f でレポートを書き込む Generator のコピーを返します
+func (g *Generator) WithFormatter(f Formatter) *Generator {
+	copied := *g
+	copied.formatter = f
+	return &copied
+}
+
+// writeFormatted は f でレポート全体を書き込みます。
+// 書き込みエラーが発生した時点や、WithContext で指定したコンテキストがキャンセルされた時点で、残りのファイルの処理を中止します
+func (g *Generator) writeFormatted(writer io.Writer, f Formatter, entries []model.FileSystemEntry) error {
+	return g.writeFormattedSections(writer, f, entries, func(w io.Writer, entry model.FileSystemEntry) error {
+		return f.WriteContent(w, g.fileContent(entry))
+	})
+}
+
+// writeFormattedSections は writeFormatted と同様に f でレポート全体を書き込みます。
+// ファイルごとの内容のセクションは writeSection で書き込むため、IncrementalGenerator はキャッシュしたセクションを再利用できます
+func (g *Generator) writeFormattedSections(writer io.Writer, f Formatter, entries []model.FileSystemEntry, writeSection func(w io.Writer, entry model.FileSystemEntry) error) error {
+	ew := newErrWriter(writer)
+	files := g.contentFiles(entries)
+	header := ReportHeader{
+		GeneratedAt:  g.generatedAt(),
+		Entries:      entries,
+		Files:        files,
+		Stats:        ComputeStatistics(entries, DefaultLargestFiles),
+		ScanStats:    g.scanStats,
+		Inaccessible: g.inaccessible,
+	}
+	if err := f.WriteHeader(ew, header); err != nil {
+		return err
+	}
+	for _, entry := range g.structureEntries(entries) {
+		if writeFailed(ew) {
+			return ew.Err()
+		}
+		if err := f.WriteEntry(ew, entry); err != nil {
+			return err
+		}
+	}
+
+	framer, _ := f.(ContentFramer)
+	for _, entry := range files {
+		if writeFailed(ew) {
+			return ew.Err()
+		}
+		if g.cancelled() {
+			g.interrupted = true
+			break
+		}
+		if framer != nil {
+			if err := framer.BeforeContent(ew, entry); err != nil {
+				return err
+			}
+		}
+		markSectionStart(ew, entry.RelPath)
+		err := writeSection(ew, entry)
+		markSectionEnd(ew)
+		if err != nil {
+			return err
+		}
+		if framer != nil {
+			if err := framer.AfterContent(ew, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	footer := ReportFooter{
+		CancelReason:     g.cancelReason(),
+		ScanStats:        g.scanStats,
+		ReproduceCommand: g.options.ReproduceCommand,
+		Redactions:       g.redactedFiles(),
+		Modified:         g.modifiedFiles(),
+	}
+	if g.options.Normalize {
+		footer.ScanStats = nil
+	}
+	if err := f.WriteFooter(ew, footer); err != nil {
+		return err
+	}
+	if err := ew.Err(); err != nil {
+		return err
+	}
+	return g.cancelError()
+}
+
+// builtinFormatter はテキスト・Markdown・HTML 形式の既定の構成でレポートを書き込む Formatter です。
+// g は出力形式などの設定にのみ使用し、書き込む内容は各メソッドに渡される値から作成します
+type builtinFormatter struct {
+	g *Generator
+	// a は WriteHeader で受け取ったエントリから作成したアンカーです
+	a anchors
+	// files は内容を書き込むファイルです。HTML 形式のページ一覧に使用します
+	files []model.FileSystemEntry
+	// pages は内容の見出しを出力した後の、HTML 形式のページ分割の状態です。見出しを出力するまでは nil です
+	pages *htmlPages
+}
+
+// newBuiltinFormatter は g の出力形式でレポートを書き込む Formatter を作成します。1 回のレポートの書き込みごとに作成します
+func newBuiltinFormatter(g *Generator) *builtinFormatter {
+	return &builtinFormatter{g: g}
+}
+
+// WriteHeader は文書の先頭部分とサマリー、フォルダ構成の見出しを書き込みます
+func (f *builtinFormatter) WriteHeader(w io.Writer, header ReportHeader) error {
+	f.a = buildAnchors(header.Entries)
+	f.files = header.Files
+	f.g.writePreamble(w, header)
+	f.g.writeStructureStart(w)
+	return nil
+}
+
+// WriteEntry はフォルダ構成の 1 行を書き込みます
+func (f *builtinFormatter) WriteEntry(w io.Writer, entry StructureEntry) error {
+	f.g.writeStructureEntry(w, entry, f.a)
+	return nil
+}
+
+// WriteContent は 1 ファイル分の内容のセクションを書き込みます
+func (f *builtinFormatter) WriteContent(w io.Writer, content FileContent) error {
+	f.g.writeSection(w, content, f.a)
+	return nil
+}
+
+// WriteFooter は、マスクした情報・変更されたファイル・中止の理由・スキャンの統計・再作成するコマンドと文書の末尾部分を書き込みます
+func (f *builtinFormatter) WriteFooter(w io.Writer, footer ReportFooter) error {
+	f.startContents(w)
+	f.pages.finish(w)
+	f.g.writeRedactionSummary(w, footer.Redactions)
+	f.g.writeModifiedSummary(w, footer.Modified)
+	f.g.writeCancelNote(w, footer.CancelReason)
+	f.g.writeScanStatsFooter(w, footer.ScanStats)
+	f.g.writeReproduceFooter(w, footer.ReproduceCommand)
+	f.g.writeDocumentEnd(w)
+	return nil
+}
+
+// BeforeContent は最初のセクションの前にフォルダ構成を閉じて内容の見出しを書き込み、HTML 形式ではページを開始します
+func (f *builtinFormatter) BeforeContent(w io.Writer, _ model.FileSystemEntry) error {
+	f.startContents(w)
+	f.pages.beforeSection(w)
+	return nil
+}
+
+// AfterContent は HTML 形式でページの最後のセクションを書き込んだ後に、ページを閉じます
+func (f *builtinFormatter) AfterContent(w io.Writer, _ model.FileSystemEntry) error {
+	f.pages.afterSection(w)
+	return nil
+}
+
+// startContents は、まだ書き込んでいなければ、フォルダ構成を閉じて内容の見出しと HTML 形式のページ一覧を書き込みます
+func (f *builtinFormatter) startContents(w io.Writer) {
+	if f.pages != nil {
+		return
+	}
+	f.g.writeStructureEnd(w)
+	f.g.writeContentsHeading(w)
+	f.pages = f.g.newHTMLPages(len(f.files))
+	f.pages.start(w, f.files, f.a)
+}
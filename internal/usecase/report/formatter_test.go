@@ -0,0 +1,188 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+)
+
+// csvFormatter は呼び出された順に 1 行ずつ書き込む、テスト用の Formatter です
+type csvFormatter struct {
+	failOn string
+}
+
+func (f *csvFormatter) fail(method string) error {
+	if f.failOn == method {
+		return errors.New(method + " の失敗")
+	}
+	return nil
+}
+
+func (f *csvFormatter) WriteHeader(w io.Writer, header ReportHeader) error {
+	fmt.Fprintf(w, "header,%d,%d\n", len(header.Entries), len(header.Files))
+	return f.fail("WriteHeader")
+}
+
+func (f *csvFormatter) WriteEntry(w io.Writer, entry StructureEntry) error {
+	fmt.Fprintf(w, "entry,%s,%q\n", entry.RelPath, entry.Prefix)
+	return f.fail("WriteEntry")
+}
+
+func (f *csvFormatter) WriteContent(w io.Writer, content FileContent) error {
+	fmt.Fprintf(w, "content,%s,%q,%q,%s\n", content.RelPath, content.Content, content.Notice, content.Language)
+	return f.fail("WriteContent")
+}
+
+func (f *csvFormatter) WriteFooter(w io.Writer, footer ReportFooter) error {
+	fmt.Fprintf(w, "footer,%s,%s\n", footer.CancelReason, footer.ReproduceCommand)
+	return f.fail("WriteFooter")
+}
+
+func TestGenerator_WithFormatter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/main.go": {Data: []byte("package main\n")},
+		"logo.png":    {Data: []byte("\x89PNG")},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/main.go", Size: 13, Depth: 1},
+		{RelPath: "logo.png", Size: 4, IsBinary: true},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "テキスト形式の指定にかかわらず Formatter で書き込む",
+			format: FormatText,
+			want: "header,3,2\n" +
+				"entry,src,\"\"\n" +
+				"entry,src/main.go,\"  \"\n" +
+				"content,src/main.go,\"package main\\n\",\"\",go\n" +
+				"content,logo.png,\"\",\"[バイナリファイルのためスキップ]\",png\n" +
+				"footer,,folderscope -source .\n",
+		},
+		{
+			name:   "JSON 形式の指定でも Formatter を優先する",
+			format: FormatJSON,
+			want:   "header,3,2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			g := NewGeneratorWithOptions(Options{Format: tt.format, ReproduceCommand: "folderscope -source ."}).WithFS(fsys)
+			if err := g.WithFormatter(&csvFormatter{}).WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			if !strings.HasPrefix(buf.String(), tt.want) {
+				t.Errorf("WriteReport() =\n%s\nwant prefix\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_WithFormatter_Error(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 1}}
+
+	for _, method := range []string{"WriteHeader", "WriteEntry", "WriteContent", "WriteFooter"} {
+		t.Run(method+" のエラーで中止する", func(t *testing.T) {
+			var buf strings.Builder
+			err := NewGenerator().WithFS(fsys).WithFormatter(&csvFormatter{failOn: method}).WriteReport(&buf, entries)
+			if err == nil || !strings.Contains(err.Error(), method+" の失敗") {
+				t.Fatalf("WriteReport() error = %v, want %s の失敗", err, method)
+			}
+			if method != "WriteFooter" && strings.Contains(buf.String(), "footer") {
+				t.Errorf("エラーの後も書き込みが続いています:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestGenerator_WithFormatter_Cancelled(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 1}}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(apperrors.Cancelled(apperrors.CancelUser, "stop"))
+
+	var buf strings.Builder
+	err := NewGenerator().WithFS(fsys).WithContext(ctx).WithFormatter(&csvFormatter{}).WriteReport(&buf, entries)
+	if apperrors.ReasonOf(err) != apperrors.CancelUser {
+		t.Fatalf("WriteReport() error = %v, want user cancellation", err)
+	}
+	if strings.Contains(buf.String(), "content,") {
+		t.Errorf("中止後にファイル内容を書き込んでいます:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "footer,user,") {
+		t.Errorf("WriteFooter に中止の理由が渡されていません:\n%s", buf.String())
+	}
+}
+
+// framedFormatter はセクションの前後に区切りを書き込む、テスト用の ContentFramer です
+type framedFormatter struct {
+	csvFormatter
+}
+
+func (f *framedFormatter) BeforeContent(w io.Writer, entry model.FileSystemEntry) error {
+	fmt.Fprintf(w, "before,%s\n", entry.RelPath)
+	return nil
+}
+
+func (f *framedFormatter) AfterContent(w io.Writer, entry model.FileSystemEntry) error {
+	fmt.Fprintf(w, "after,%s\n", entry.RelPath)
+	return nil
+}
+
+func TestGenerator_WithFormatter_ContentFramer(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 1}}
+
+	var buf strings.Builder
+	w := NewIndexingWriter(&buf)
+	if err := NewGenerator().WithFS(fsys).WithFormatter(&framedFormatter{}).WriteReport(w, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	want := "before,a.txt\ncontent,a.txt,\"a\",\"\",txt\nafter,a.txt\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("WriteReport() =\n%s\nwant\n%s", buf.String(), want)
+	}
+	// インデックスのセクションの範囲には、BeforeContent・AfterContent で書き込んだ内容を含めない
+	indexed := w.Entries()
+	if len(indexed) != 1 {
+		t.Fatalf("インデックス = %+v", indexed)
+	}
+	section := buf.String()[indexed[0].Offset : indexed[0].Offset+indexed[0].Length]
+	if section != "content,a.txt,\"a\",\"\",txt\n" {
+		t.Errorf("セクションの範囲 = %q", section)
+	}
+}
+
+func TestBuiltinFormatter_WriteFooter(t *testing.T) {
+	// 末尾部分は Generator の状態ではなく、渡された ReportFooter の値から書き込む
+	footer := ReportFooter{
+		CancelReason:     apperrors.CancelTimeout,
+		ScanStats:        &model.ScanStats{Files: 3},
+		ReproduceCommand: "folderscope -source .",
+		Redactions:       []RedactedFile{{RelPath: "a.env", Redactions: []Redaction{{Rule: "password", Count: 2}}}},
+		Modified:         []ModifiedFile{{RelPath: "b.txt", ScannedSize: 10, CurrentSize: 10}},
+	}
+	var buf strings.Builder
+	if err := newBuiltinFormatter(NewGenerator()).WriteFooter(&buf, footer); err != nil {
+		t.Fatalf("WriteFooter() error = %v", err)
+	}
+	for _, want := range []string{"a.env: password (2)", "b.txt: 更新日時のみ変更", "cancelReason: timeout", "スキャンしたファイル: 3", "folderscope -source ."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteFooter() に %q が含まれていません:\n%s", want, buf.String())
+		}
+	}
+}
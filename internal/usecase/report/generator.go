@@ -2,13 +2,19 @@
 package report
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
+	"FolderScope/internal/domain/apperrors"
 	"FolderScope/internal/domain/model"
 )
 
@@ -18,22 +24,268 @@ const (
 	TimestampLayout  = "20060102_150405"
 )
 
+// MetadataTimeLayout はフォルダ構成に表示する更新日時のフォーマットです
+const MetadataTimeLayout = "2006-01-02 15:04:05"
+
+// Options はレポート生成の動作を制御するオプションです
+type Options struct {
+	// Format はレポートの出力形式です。空の場合はテキスト形式として扱います
+	Format Format `json:"format,omitempty"`
+	// ShowMetadata はフォルダ構成にサイズ・更新日時・パーミッションを表示するかどうかを示します
+	ShowMetadata bool `json:"showMetadata,omitempty"`
+	// HTMLPageSize は HTML 形式で 1 ページに含めるファイル数です。0 の場合はページ分割しません。
+	// ページ分割時は表示中のページのみが DOM に展開されるため、巨大なレポートでもブラウザが応答を保てます
+	HTMLPageSize int `json:"htmlPageSize,omitempty"`
+	// ShowSummary はレポート冒頭にファイル数・合計サイズ・拡張子別などのサマリーを出力するかどうかを示します
+	ShowSummary bool `json:"showSummary,omitempty"`
+	// MaxContentSize は内容を出力するファイルサイズの上限（バイト）です。
+	// 上限を超えるファイルはフォルダ構成には表示されますが、内容は出力されません。0 の場合は無制限です。
+	// SizeTiers と同時に指定した場合は、SizeTiers のうち上限以下の段階のみを適用します
+	MaxContentSize int64 `json:"maxContentSize,omitempty"`
+	// SizeTiers はファイルサイズの段階ごとの内容の出力方法（すべて・先頭と末尾のみ・スキップ・構成のみ）です。空の場合はすべて出力します
+	SizeTiers SizeTiers `json:"sizeTiers,omitempty"`
+	// HeadLines と TailLines は ContentHeadTail で出力する先頭と末尾の行数です。0 の場合は既定の行数（定数の HeadLines, TailLines）です
+	HeadLines int `json:"headLines,omitempty"`
+	TailLines int `json:"tailLines,omitempty"`
+	// ContentOrder はファイル内容セクションの並び順です。空の場合は相対パスの順です。
+	// フォルダ構成の並び順は変わりません
+	ContentOrder ContentOrder `json:"contentOrder,omitempty"`
+	// LineNumbers はファイル内容の各行の先頭に行番号を付けるかどうかを示します。
+	// データ形式（JSON/JSONL）と、変更箇所のみを出力するファイルには付けません
+	LineNumbers bool `json:"lineNumbers,omitempty"`
+	// ShowMetrics は各ファイルのヘッダーに行数・コメント率・関数の数などの指標を表示するかどうかを示します
+	ShowMetrics bool `json:"showMetrics,omitempty"`
+	// Highlight はファイル内容を言語に応じて色付けするかどうかを示します。
+	// HTML 形式ではスタイルシートで、テキスト形式では端末向けの ANSI エスケープシーケンスで色付けします
+	Highlight bool `json:"highlight,omitempty"`
+	// ShowLanguages はレポート冒頭に、言語ごとのファイル数と行数（空行・コメント・コード）の統計を出力するかどうかを示します
+	ShowLanguages bool `json:"showLanguages,omitempty"`
+	// ShowLicenses はレポート冒頭に、LICENSE や COPYING などのライセンスファイルと判定したライセンスの種類の一覧を出力するかどうかを示します
+	ShowLicenses bool `json:"showLicenses,omitempty"`
+	// ShowEncodingAudit はレポート冒頭に、テキストファイルの文字コード・改行コードの集計と、
+	// 主な文字コード・改行コードとそろっていないファイルの一覧を出力するかどうかを示します
+	ShowEncodingAudit bool `json:"showEncodingAudit,omitempty"`
+	// LongListing はフォルダ構成の各行の先頭に、ls -l のようにパーミッション・所有者・グループ・サイズ・更新日時の列を表示するかどうかを示します。
+	// 配布先のフォルダの権限を確認するセキュリティレビューなどに使用します
+	LongListing bool `json:"longListing,omitempty"`
+	// TreeStyle はフォルダ構成の描画方法です。空の場合は字下げ（TreeIndent）で描画します。JSON/JSONL には影響しません
+	TreeStyle TreeStyle `json:"treeStyle,omitempty"`
+	// BinaryPolicy はバイナリファイルの扱いです。空の場合は BinarySkip として扱います。
+	// BinaryOmit の場合、バイナリファイルはスキャン時に除外されるため、ここでは BinarySkip と同じく扱います
+	BinaryPolicy BinaryPolicy `json:"binaryPolicy,omitempty"`
+	// BinaryEmbedLimit は BinaryBase64 で埋め込むファイルサイズの上限（バイト）です。0 の場合は DefaultBinaryEmbedLimit です
+	BinaryEmbedLimit int64 `json:"binaryEmbedLimit,omitempty"`
+	// Compression は CreateOutput で作成する出力ファイルの圧縮方式です。空の場合は圧縮しません
+	Compression Compression `json:"compression,omitempty"`
+	// DisableRedaction はファイル内容に含まれる秘密情報（アクセスキー・秘密鍵・トークン・パスワードなど）のマスクを無効にするかどうかを示します。
+	// 既定ではマスクし、マスクしたファイルの一覧をレポートの末尾に出力します
+	DisableRedaction bool `json:"disableRedaction,omitempty"`
+	// PDFFont は PDF 形式で使用する TrueType フォントファイルのパスです。
+	// 空の場合は PDF 標準の等幅フォント（Courier）を使用し、日本語など表示できない文字は '.' に置き換わります
+	PDFFont string `json:"pdfFont,omitempty"`
+	// Normalize は、リポジトリにコミットして git diff で比較できるよう、実行のたびに変わる情報を含めずに出力するかどうかを示します。
+	// 作成日時・スキャンの所要時間・更新日時を出力せず、ファイル内容を相対パスの順に並べ、改行を LF にそろえます
+	Normalize bool `json:"normalize,omitempty"`
+	// DedupHardLinks は、同じ inode を共有するファイル（ハードリンク）のうち、先に記録したファイル以外の内容を省略するかどうかを示します。
+	// 同じ大きなファイルの内容が複数回出力されないようにします
+	DedupHardLinks bool `json:"dedupHardLinks,omitempty"`
+	// ReproduceCommand は同じレポートを再作成するコマンドラインです。空でない場合はレポートの末尾に記載し、
+	// データ形式では command の項目に出力します
+	ReproduceCommand string `json:"reproduceCommand,omitempty"`
+}
+
 // Generator はレポート生成機能を提供します
-type Generator struct{}
+type Generator struct {
+	options Options
+	// fsys はファイル内容の読み込み元です。nil の場合は OS のファイルシステムからエントリの Path で読み込みます
+	fsys fs.FS
+	// scanStats はデータ形式のエクスポートに含めるスキャンの統計情報です。nil の場合は含めません
+	scanStats *model.ScanStats
+	// inaccessible は権限がないためにスキャンできなかったパスです。レポートの「アクセスできなかったパス」に記載します
+	inaccessible []model.InaccessiblePath
+	// commitTimes は OrderGitRecent で使用する相対パスごとの最終コミット日時です
+	commitTimes map[string]time.Time
+	// authors は相対パスごとの主な作成者の表示文字列です。設定されている場合は各ファイルのヘッダーに表示します
+	authors map[string]string
+	// hunks は相対パスごとの変更箇所（git diff のハンク）です。設定されている場合は、該当するファイルの本文の代わりに出力します
+	hunks map[string]string
+	// filter はファイルの内容を出力前に加工します。nil の場合は加工しません
+	filter ContentFilter
+	// filterFallback は filter が失敗した場合の扱いです
+	filterFallback FilterFallback
+	// processors は既定の加工の後にファイルの内容に適用する ContentProcessor です
+	processors []ContentProcessor
+	// maskRules は利用者が定義したマスクのルールです
+	maskRules MaskRules
+	// redactions は出力中のレポートでマスクした情報の記録です。出力ごとに withOutputLog で作成します
+	redactions *redactionLog
+	// modified は出力中のレポートで、スキャン後に変更されていたファイルの記録です。出力ごとに withOutputLog で作成します
+	modified *modifiedLog
+	// template はレポート全体の構成を決めるテンプレートです。nil の場合は出力形式の既定の構成で出力します
+	template *template.Template
+	// ctx はファイル内容の出力を途中で中止するためのコンテキストです。nil の場合は中止しません
+	ctx context.Context
+	// interrupted は出力中のレポートで、ctx のキャンセルによってファイル内容の出力を中止したかどうかを示します
+	interrupted bool
+	// formatter はレポートを書き込む Formatter です。nil の場合は出力形式の既定の Formatter を使用します
+	formatter Formatter
+}
 
 // NewGenerator は新しい Generator インスタンスを作成します
 func NewGenerator() *Generator { // [cite: 270]
-	return &Generator{} // [cite: 270]
+	return NewGeneratorWithOptions(Options{}) // [cite: 270]
+}
+
+// NewGeneratorWithOptions は Options を指定して新しい Generator インスタンスを作成します
+func NewGeneratorWithOptions(options Options) *Generator {
+	if options.Format == "" {
+		options.Format = FormatText
+	}
+	return &Generator{options: options}
+}
+
+// WithFS はファイル内容を fsys から読み込む Generator のコピーを返します。
+// アーカイブのように OS のファイルシステム以外をスキャンした場合に使用し、各ファイルはエントリの RelPath で参照します
+func (g *Generator) WithFS(fsys fs.FS) *Generator {
+	copied := *g
+	copied.fsys = fsys
+	return &copied
 }
 
-// CreateOutputFile は出力ファイルを作成します
+// WithScanStats はデータ形式（JSON・JSONL）のエクスポートにスキャンの統計情報を含める Generator のコピーを返します。
+// 権限がないために読み込めなかったパスがある場合は、それ以外の形式でもレポートの先頭に一覧を記載します
+func (g *Generator) WithScanStats(stats model.ScanStats) *Generator {
+	copied := *g
+	copied.scanStats = &stats
+	copied.inaccessible = stats.Inaccessible
+	return &copied
+}
+
+// WithAuthors は各ファイルのヘッダーに主な作成者を表示する Generator のコピーを返します。
+// authors のキーは相対パス、値は表示する作成者の文字列です
+func (g *Generator) WithAuthors(authors map[string]string) *Generator {
+	copied := *g
+	copied.authors = authors
+	return &copied
+}
+
+// authorsOf はエントリの主な作成者の表示文字列を返します。不明な場合は空文字列です
+func (g *Generator) authorsOf(entry model.FileSystemEntry) string {
+	return g.authors[entry.RelPath]
+}
+
+// WithHunks は、ファイルの本文の代わりに変更箇所のみを出力する Generator のコピーを返します。
+// hunks のキーは相対パス、値は git diff のハンクです。hunks に含まれないファイルは本文をそのまま出力します
+func (g *Generator) WithHunks(hunks map[string]string) *Generator {
+	copied := *g
+	copied.hunks = hunks
+	return &copied
+}
+
+// hunksOf はエントリの変更箇所を返します。変更箇所のみを出力しないファイルの場合は false を返します
+func (g *Generator) hunksOf(entry model.FileSystemEntry) (string, bool) {
+	hunks, ok := g.hunks[entry.RelPath]
+	return hunks, ok
+}
+
+// readFile はエントリのファイル内容を読み込みます
+func (g *Generator) readFile(entry model.FileSystemEntry) ([]byte, error) {
+	if g.fsys != nil {
+		return fs.ReadFile(g.fsys, entry.RelPath)
+	}
+	return os.ReadFile(entry.Path)
+}
+
+// statFile はエントリのファイル情報を取得します
+func (g *Generator) statFile(entry model.FileSystemEntry) (fs.FileInfo, error) {
+	if g.fsys != nil {
+		return fs.Stat(g.fsys, entry.RelPath)
+	}
+	return os.Stat(entry.Path)
+}
+
+// WriteReport はフォルダ構成とファイル内容を、出力形式に応じた前後の定型部分とともに出力します。
+// 書き込みに失敗した場合（ディスクの空き容量不足など）は、以降の出力を中止してエラーを返します
+func (g *Generator) WriteReport(writer io.Writer, entries []model.FileSystemEntry) error {
+	if g.options.Normalize {
+		g, entries = g.normalized(entries)
+	}
+	if g.formatter != nil {
+		g = g.withOutputLog()
+		return g.writeFormatted(writer, g.formatter, entries)
+	}
+	if g.options.Format == FormatPDF {
+		return g.writePDF(writer, entries)
+	}
+	if g.options.Format == FormatSQLite {
+		return g.writeSQLite(writer, entries)
+	}
+	g = g.withOutputLog()
+	ew := newErrWriter(writer)
+	if g.options.Format.isData() {
+		g.writeDataExport(ew, entries)
+		return ew.Err()
+	}
+	if g.template != nil {
+		if err := g.writeTemplate(ew, entries); err != nil && ew.Err() == nil {
+			return err
+		}
+		return ew.Err()
+	}
+	return g.writeFormatted(ew, newBuiltinFormatter(g), entries)
+}
+
+// writePreamble は文書の先頭部分と、アクセスできなかったパス、有効な場合はサマリーを出力します
+func (g *Generator) writePreamble(writer io.Writer, header ReportHeader) {
+	entries := header.Entries
+	g.writeDocumentStart(writer)
+	g.writeInaccessible(writer, header.Inaccessible)
+	if g.options.ShowSummary {
+		g.writeSummary(writer, header.Stats)
+	}
+	if g.options.ShowLanguages {
+		g.writeLanguageStats(writer, g.computeLanguageStats(entries))
+	}
+	if g.options.ShowLicenses {
+		g.writeLicenses(writer, g.detectLicenses(entries))
+	}
+	if g.options.ShowEncodingAudit {
+		g.writeEncodingAudit(writer, computeEncodingAudit(entries))
+	}
+}
+
+// writeDocumentStart は出力形式に応じた文書の先頭部分を出力します
+func (g *Generator) writeDocumentStart(writer io.Writer) {
+	if g.options.Format == FormatHTML {
+		writeHTMLDocumentStart(writer, g.highlightCSS())
+	}
+}
+
+// writeDocumentEnd は出力形式に応じた文書の末尾部分を出力します
+func (g *Generator) writeDocumentEnd(writer io.Writer) {
+	if g.options.Format == FormatHTML {
+		writeHTMLDocumentEnd(writer)
+	}
+}
+
+// CreateOutputFile は出力ファイルを作成します。
+// 同じ名前のファイルがすでに存在する場合は上書きせず、apperrors.ErrOutputExists を返します
 func (g *Generator) CreateOutputFile(outputDir string) (*os.File, string, error) {
+	return CreateOutputFile(outputDir, g.options.Format.Extension())
+}
+
+// CreateOutputFile は拡張子 extension（先頭のドットを含む）の出力ファイルを作成します。
+// プラグインのように出力形式が Format で表せない場合に使用します。同じ名前のファイルがすでに存在する場合は apperrors.ErrOutputExists を返します
+func CreateOutputFile(outputDir, extension string) (*os.File, string, error) {
 	timestamp := time.Now().Format(TimestampLayout)
-	outputPath := filepath.Join(outputDir, fmt.Sprintf("%s%s%s", OutputFilePrefix, timestamp, OutputFileSuffix))
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("%s%s%s", OutputFilePrefix, timestamp, extension))
 
-	outputFile, err := os.Create(outputPath)
+	outputFile, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, "", apperrors.New(apperrors.ErrOutputExists, "出力ファイルがすでに存在します", outputPath, err)
+	}
 	if err != nil {
-		return nil, "", fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		return nil, "", apperrors.Wrap("出力ファイルの作成に失敗しました", outputPath, err)
 	}
 
 	return outputFile, outputPath, nil
@@ -41,55 +293,356 @@ func (g *Generator) CreateOutputFile(outputDir string) (*os.File, string, error)
 
 // WriteFileSystemStructure はエントリの深さに応じたインデントを付与し,
 // フォルダ（[DIR]）とファイル（[FILE]）を一覧で出力します。
-// バイナリファイルは出力から除外されます。
-func (g *Generator) WriteFileSystemStructure(writer io.Writer, entries []model.FileSystemEntry) {
-	fmt.Fprintln(writer, "===== フォルダ・ファイル構成 =====")
+// バイナリファイルは出力から除外されます。書き込みに失敗した場合はエラーを返します
+func (g *Generator) WriteFileSystemStructure(writer io.Writer, entries []model.FileSystemEntry) error {
+	ew := newErrWriter(writer)
+	g.writeStructure(ew, entries)
+	return ew.Err()
+}
 
-	for _, entry := range entries {
-		// バイナリファイルであり、かつディレクトリでない場合はスキップ
-		if !entry.IsDir && entry.IsBinary {
-			continue
-		}
+// writeStructure は出力形式に応じたフォルダ構成を出力します
+func (g *Generator) writeStructure(writer io.Writer, entries []model.FileSystemEntry) {
+	a := buildAnchors(entries)
+	g.writeStructureStart(writer)
+	for _, entry := range g.structureEntries(entries) {
+		g.writeStructureEntry(writer, entry, a)
+	}
+	g.writeStructureEnd(writer)
+}
 
-		indent := strings.Repeat("  ", entry.Depth)
-		entryType := "[FILE]"
-		if entry.IsDir {
-			entryType = "[DIR] "
+// structureEntries はフォルダ構成に表示するエントリを、行頭の文字列と補足情報とともに返します
+func (g *Generator) structureEntries(entries []model.FileSystemEntry) []StructureEntry {
+	lines := g.structureLines(entries)
+	structure := make([]StructureEntry, len(lines))
+	for i, line := range lines {
+		structure[i] = StructureEntry{
+			FileSystemEntry: line.entry,
+			Prefix:          line.prefix,
+			Listing:         line.listing,
+			Annotation:      g.annotation(line.entry),
 		}
-		fmt.Fprintf(writer, "%s%s %s\n", indent, entryType, entry.RelPath)
 	}
+	return structure
+}
+
+// writeStructureStart は出力形式に応じたフォルダ構成の見出しと開始部分を出力します
+func (g *Generator) writeStructureStart(writer io.Writer) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		g.writeMarkdownStructureStart(writer)
+		return
+	case FormatHTML:
+		g.writeHTMLStructureStart(writer)
+		return
+	}
+	fmt.Fprintln(writer, "===== フォルダ・ファイル構成 =====")
+	if g.options.TreeStyle == TreeLines {
+		fmt.Fprintln(writer, ".")
+	}
+}
+
+// writeStructureEntry は出力形式に応じたフォルダ構成の 1 行を出力します
+func (g *Generator) writeStructureEntry(writer io.Writer, entry StructureEntry, a anchors) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		g.writeMarkdownStructureEntry(writer, entry, a)
+		return
+	case FormatHTML:
+		g.writeHTMLStructureEntry(writer, entry, a)
+		return
+	}
+	if g.options.TreeStyle == TreeLines {
+		fmt.Fprintf(writer, "%s%s%s%s\n", entry.Listing, entry.Prefix, treeName(entry.FileSystemEntry), entry.Annotation)
+		return
+	}
+	entryType := "[FILE]"
+	switch {
+	case entry.IsDir:
+		entryType = "[DIR] "
+	case entry.IsLinkOnly():
+		entryType = "[LINK]"
+	}
+	fmt.Fprintf(writer, "%s%s%s %s%s\n", entry.Listing, entry.Prefix, entryType, entry.RelPath, entry.Annotation)
+}
+
+// writeStructureEnd は出力形式に応じたフォルダ構成の終了部分を出力します
+func (g *Generator) writeStructureEnd(writer io.Writer) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		g.writeMarkdownStructureEnd(writer)
+	case FormatHTML:
+		writeHTMLStructureEnd(writer)
+	}
+}
+
+// annotation はフォルダ構成の各行に付与する補足情報（リンク先・ハードリンク・メタデータ・ハッシュ）を返します
+func (g *Generator) annotation(entry model.FileSystemEntry) string {
+	var b strings.Builder
+	if entry.LinkTarget != "" {
+		fmt.Fprintf(&b, " → %s", entry.LinkTarget)
+	}
+	if entry.Dangling {
+		b.WriteString(" (リンク切れ)")
+	}
+	if entry.HardLinkOf != "" {
+		fmt.Fprintf(&b, " (ハードリンク: %s)", entry.HardLinkOf)
+	}
+	if g.options.ShowMetadata {
+		fmt.Fprintf(&b, " (%s)", formatMetadata(entry))
+	}
+	if entry.Hash != "" {
+		fmt.Fprintf(&b, " sha256:%s", entry.Hash)
+	}
+	return b.String()
+}
+
+// formatMetadata はエントリのサイズ・更新日時・メディアタイプ・文字コード（UTF-8 以外の場合）・改行コード（LF 以外の場合）・パーミッションを表示用の文字列に整形します
+// ディレクトリの場合、サイズは表示しません
+func formatMetadata(entry model.FileSystemEntry) string {
+	parts := make([]string, 0, 5)
+	if !entry.IsDir {
+		parts = append(parts, FormatSize(entry.Size))
+	}
+	if !entry.ModTime.IsZero() {
+		parts = append(parts, entry.ModTime.Format(MetadataTimeLayout))
+	}
+	if entry.MIMEType != "" {
+		parts = append(parts, entry.MIMEType)
+	}
+	if entry.Encoding != "" && entry.Encoding != model.EncodingUTF8 {
+		parts = append(parts, entry.Encoding)
+	}
+	if entry.LineEnding != "" && entry.LineEnding != model.LineEndingLF {
+		parts = append(parts, entry.LineEnding)
+	}
+	parts = append(parts, entry.Permissions.String())
+	return strings.Join(parts, ", ")
+}
+
+// FormatSize はバイト数を人が読みやすい単位（B, KB, MB, GB, TB）に整形します
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	units := []string{"KB", "MB", "GB", "TB"}
+	i := -1
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
 }
 
 // WriteFileContents はファイルの内容を読み込んで出力します
 // バイナリファイルの場合は内容をスキップし、その旨を記述します。
-func (g *Generator) WriteFileContents(writer io.Writer, entries []model.FileSystemEntry) {
-	fmt.Fprintln(writer, "\n===== ファイル内容 =====")
+// 書き込みに失敗した場合は、残りのファイルを読み込まずにエラーを返します
+func (g *Generator) WriteFileContents(writer io.Writer, entries []model.FileSystemEntry) error {
+	g = g.withOutputLog()
+	ew := newErrWriter(writer)
+	g.writeContents(ew, entries)
+	return ew.Err()
+}
+
+// writeContents はファイル内容セクションの見出しと、ファイルごとのセクションを出力します
+func (g *Generator) writeContents(writer io.Writer, entries []model.FileSystemEntry) {
+	a := buildAnchors(entries)
+	g.writeContentsHeading(writer)
+	g.writeSections(writer, entries, a, func(entry model.FileSystemEntry) {
+		g.writeFileSection(writer, entry, a)
+	})
+}
 
+// writeSections はファイルエントリごとに writeSection を呼び出します。
+// HTML 形式でページ分割が有効な場合は、セクションをページ単位にまとめ、ページ一覧のサイドバーを出力します。
+// 書き込みエラーが発生した時点や、WithContext で指定したコンテキストがキャンセルされた時点で、残りのファイルの処理を中止します
+func (g *Generator) writeSections(writer io.Writer, entries []model.FileSystemEntry, a anchors, writeSection func(model.FileSystemEntry)) {
+	files := g.contentFiles(entries)
+	pages := g.newHTMLPages(len(files))
+	pages.start(writer, files, a)
+	for _, entry := range files {
+		if writeFailed(writer) {
+			return
+		}
+		if g.cancelled() {
+			g.interrupted = true
+			break
+		}
+		pages.beforeSection(writer)
+		writeSection(entry)
+		pages.afterSection(writer)
+	}
+	pages.finish(writer)
+}
+
+// contentFiles は内容のセクションを出力するファイルを、出力する順に並べて返します
+func (g *Generator) contentFiles(entries []model.FileSystemEntry) []model.FileSystemEntry {
+	files := make([]model.FileSystemEntry, 0, len(entries))
 	for _, entry := range entries {
-		if entry.IsDir {
-			continue
+		if g.hasSection(entry) {
+			files = append(files, entry)
 		}
+	}
+	g.sortContents(files)
+	return files
+}
+
+// writeContentsHeading は出力形式に応じたファイル内容セクションの見出しを出力します
+func (g *Generator) writeContentsHeading(writer io.Writer) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "\n## ファイル内容")
+	case FormatHTML:
+		fmt.Fprintln(writer, "<h2>ファイル内容</h2>")
+	default:
+		fmt.Fprintln(writer, "\n===== ファイル内容 =====")
+	}
+}
+
+// writeFileSection は 1 ファイル分の内容セクション（ヘッダー・本文・区切り線）を出力します
+func (g *Generator) writeFileSection(writer io.Writer, entry model.FileSystemEntry, a anchors) {
+	markSectionStart(writer, entry.RelPath)
+	defer markSectionEnd(writer)
+	g.writeSection(writer, g.fileContent(entry), a)
+}
+
+// fileContent はファイルの本文を読み込み、マスク・色付け・行番号を適用した、セクションに出力する内容を返します
+func (g *Generator) fileContent(entry model.FileSystemEntry) FileContent {
+	content, notice := g.loadContent(entry)
+	c := FileContent{
+		FileSystemEntry: entry,
+		Notice:          notice,
+		Authors:         g.authorsOf(entry),
+		Metrics:         g.metricsOf(entry, content, notice),
+		Redactions:      g.redactionsOf(entry.RelPath),
+		Language:        languageHint(entry.RelPath),
+		InStructure:     g.inStructure(entry),
+	}
+	if _, ok := g.hunksOf(entry); ok {
+		c.Language = "diff"
+	}
+	// バイナリファイルの 16 進ダンプと Base64 は、そのまま復元・照合できるよう色付けと行番号を付けない
+	if notice == "" && !entry.IsBinary && g.highlights() {
+		content = g.highlight(entry, content)
+	}
+	if g.options.LineNumbers && notice == "" && !g.isExcerpt(entry) && !entry.IsBinary {
+		content = numberLines(content)
+	}
+	c.Content = content
+	return c
+}
+
+// writeSection は出力形式に応じた 1 ファイル分の内容セクションを出力します
+func (g *Generator) writeSection(writer io.Writer, c FileContent, a anchors) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		writeMarkdownSection(writer, c, a)
+		return
+	case FormatHTML:
+		g.writeHTMLSection(writer, c, a)
+		return
+	}
+
+	fmt.Fprintf(writer, "----- %s -----\n", c.RelPath)
+	if c.Authors != "" {
+		fmt.Fprintf(writer, "作成者: %s\n", c.Authors)
+	}
+	if c.Metrics != nil {
+		fmt.Fprintf(writer, "指標: %s\n", c.Metrics)
+	}
+	if c.Notice != "" {
+		fmt.Fprintln(writer, c.Notice)
+	} else {
+		fmt.Fprintln(writer, string(c.Content))
+	}
+	fmt.Fprintln(writer, "------------------------")
+}
+
+// metricsOf は、指標の表示が有効な場合に、読み込んだ本文から算出したファイルの指標を返します。
+// 本文を出力しないファイル、変更箇所のみを出力するファイル、バイナリファイルの場合は nil を返します
+func (g *Generator) metricsOf(entry model.FileSystemEntry, content []byte, notice string) *FileMetrics {
+	if !g.options.ShowMetrics || notice != "" || g.isExcerpt(entry) || entry.IsBinary {
+		return nil
+	}
+	metrics := computeMetrics(entry.RelPath, content)
+	return &metrics
+}
+
+// numberLines は内容の各行の先頭に、右揃えの行番号と区切りの " | " を付けます
+func numberLines(content []byte) []byte {
+	if len(content) == 0 {
+		return content
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" { // 末尾の改行の後は行として数えない
+		lines = lines[:len(lines)-1]
+	}
+	width := len(strconv.Itoa(len(lines)))
+	var b strings.Builder
+	b.Grow(len(content) + len(lines)*(width+3))
+	for i, line := range lines {
+		fmt.Fprintf(&b, "%*d | %s", width, i+1, line)
+	}
+	return []byte(b.String())
+}
 
-		fmt.Fprintf(writer, "----- %s -----\n", entry.RelPath)
-
-		if entry.IsBinary {
-			fmt.Fprintln(writer, "[バイナリファイルのためスキップ]")
-		} else if entry.ReadErr != nil {
-			// Scannerでのバイナリ判定時の読み込みエラーを考慮
-			fmt.Fprintf(writer, "[ファイル読み込みエラー（スキャン時）のため内容表示不可] %v\n", entry.ReadErr)
-		} else {
-			// テキストファイルと判定された（かつスキャン時にエラーがなかった）場合のみ内容を読み込む
-			content, err := os.ReadFile(entry.Path)
-			if err != nil {
-				fmt.Fprintf(writer, "[ファイル読み込みエラー（レポート生成時）] %v\n", err)
-			} else {
-				// 念のため、ここで再度バイナリチェックを行うことも検討可能だが、
-				// 基本的にはScannerの判定を信頼する。
-				// もしScannerの判定が不完全で、大きなファイルの場合、
-				// ここでの読み込みが問題になる可能性はある。
-				fmt.Fprintln(writer, string(content))
-			}
+// loadContent はファイルセクションに出力する本文を読み込み、テキストの内容には contentProcessors の加工（秘密情報のマスクなど）を適用します。
+// バイナリファイルの 16 進ダンプと Base64 は、符号化した表現を壊さないようマスクしません。
+// 本文を出力できない場合は、その理由を示す注記を返します
+func (g *Generator) loadContent(entry model.FileSystemEntry) ([]byte, string) {
+	content, notice := g.readContent(entry)
+	if notice != "" {
+		return nil, notice
+	}
+	if entry.IsBinary {
+		return content, ""
+	}
+	return ProcessContent(entry, content, g.contentProcessors(entry)...)
+}
+
+// readContent はファイルの本文（または変更箇所）を加工せずに読み込みます
+func (g *Generator) readContent(entry model.FileSystemEntry) ([]byte, string) {
+	if entry.IsLinkOnly() {
+		return nil, linkNotice(entry)
+	}
+	if g.options.DedupHardLinks && entry.HardLinkOf != "" {
+		return nil, fmt.Sprintf("[%s のハードリンクのため内容表示省略]", entry.HardLinkOf)
+	}
+	if entry.IsBinary {
+		return g.readBinaryContent(entry)
+	}
+	if entry.ReadErr != nil {
+		// Scannerでのバイナリ判定時の読み込みエラーを考慮
+		return nil, fmt.Sprintf("[ファイル読み込みエラー（スキャン時）のため内容表示不可] %v", entry.ReadErr)
+	}
+	if hunks, ok := g.hunksOf(entry); ok {
+		if hunks == "" {
+			return nil, "[内容の変更箇所はありません]"
 		}
-		fmt.Fprintln(writer, "------------------------")
+		return []byte(hunks), ""
+	}
+	mode, lower := g.contentMode(entry)
+	if mode == ContentSkip || mode == ContentStructure {
+		return nil, sizeNotice(entry, mode, lower)
+	}
+
+	// テキストファイルと判定された（かつスキャン時にエラーがなかった）場合のみ内容を読み込む
+	content, err := g.readStableFile(entry)
+	if err != nil {
+		return nil, fmt.Sprintf("[ファイル読み込みエラー（レポート生成時）] %v", err)
+	}
+	// 念のため、ここで再度バイナリチェックを行うことも検討可能だが、
+	// 基本的にはScannerの判定を信頼する。
+	// もしScannerの判定が不完全で、大きなファイルの場合、
+	// ここでの読み込みが問題になる可能性はある。
+	return content, ""
+}
+
+// linkNotice は内容を持たないシンボリックリンク（ディレクトリへのリンク、リンク切れ）の内容セクションに出力する注記を返します
+func linkNotice(entry model.FileSystemEntry) string {
+	if entry.Dangling {
+		return fmt.Sprintf("[リンク切れのシンボリックリンクのため内容なし] → %s", entry.LinkTarget)
 	}
+	return fmt.Sprintf("[フォルダへのシンボリックリンクのため内容なし] → %s", entry.LinkTarget)
 }
@@ -1,11 +1,16 @@
 package report
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"FolderScope/internal/domain/apperrors"
 	"FolderScope/internal/domain/model"
 )
 
@@ -55,6 +60,28 @@ func TestGenerator_CreateOutputFile(t *testing.T) {
 	}
 }
 
+func TestGenerator_CreateOutputFileExists(t *testing.T) {
+	generator := NewGenerator()
+	tempDir := t.TempDir()
+
+	// 同じ秒に作成されるファイル名をあらかじめ用意しておき、上書きされないことを確認する
+	for i := 0; i < 2; i++ {
+		name := OutputFilePrefix + time.Now().Add(time.Duration(i)*time.Second).Format(TimestampLayout) + OutputFileSuffix
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("existing"), 0644); err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+	}
+
+	file, _, err := generator.CreateOutputFile(tempDir)
+	if err == nil {
+		file.Close()
+		t.Fatal("既存のファイルがある場合はエラーが返されるべきです")
+	}
+	if !errors.Is(err, apperrors.ErrOutputExists) {
+		t.Errorf("apperrors.ErrOutputExists で判定できるべきです: %v", err)
+	}
+}
+
 func TestGenerator_WriteFileSystemStructure(t *testing.T) {
 	generator := NewGenerator()
 	var buf strings.Builder
@@ -178,3 +205,418 @@ func TestGenerator_WriteFileContents(t *testing.T) {
 		t.Errorf("Directory entry was processed in WriteFileContents")
 	}
 }
+
+func TestGenerator_WriteFileSystemStructureWithMetadata(t *testing.T) {
+	generator := NewGeneratorWithOptions(Options{ShowMetadata: true})
+	var buf strings.Builder
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	entries := []model.FileSystemEntry{
+		{Path: "/test/dir", IsDir: true, RelPath: "dir", Depth: 0, ModTime: modTime, Permissions: fs.ModeDir | 0755},
+		{Path: "/test/dir/file.txt", RelPath: "dir/file.txt", Depth: 1, Size: 2048, ModTime: modTime, Permissions: 0644},
+		{Path: "/test/dir/config.json", RelPath: "dir/config.json", Depth: 1, Size: 512, ModTime: modTime, Permissions: 0644, MIMEType: "application/json"},
+	}
+
+	generator.WriteFileSystemStructure(&buf, entries)
+
+	output := buf.String()
+	expectedLines := []string{
+		"[DIR]  dir (2024-01-02 03:04:05, drwxr-xr-x)",
+		"  [FILE] dir/file.txt (2.0 KB, 2024-01-02 03:04:05, -rw-r--r--)",
+		"  [FILE] dir/config.json (512 B, 2024-01-02 03:04:05, application/json, -rw-r--r--)",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line) {
+			t.Errorf("出力に期待される行が含まれていない: %v\nOutput:\n%s", line, output)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.size); got != tt.want {
+			t.Errorf("FormatSize(%d) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestGenerator_WriteFileSystemStructureWithHash(t *testing.T) {
+	generator := NewGenerator()
+	var buf strings.Builder
+
+	entries := []model.FileSystemEntry{
+		{Path: "/test/file.txt", RelPath: "file.txt", Hash: "abc123"},
+		{Path: "/test/other.txt", RelPath: "other.txt"},
+	}
+	generator.WriteFileSystemStructure(&buf, entries)
+
+	output := buf.String()
+	if !strings.Contains(output, "[FILE] file.txt sha256:abc123\n") {
+		t.Errorf("ハッシュが出力されていません:\n%s", output)
+	}
+	if !strings.Contains(output, "[FILE] other.txt\n") {
+		t.Errorf("ハッシュのないエントリの出力が不正:\n%s", output)
+	}
+}
+
+func TestGenerator_WriteFileSystemStructureWithLinkTarget(t *testing.T) {
+	generator := NewGenerator()
+	var buf strings.Builder
+
+	entries := []model.FileSystemEntry{
+		{Path: "/test/vendor", RelPath: "vendor", IsDir: true, LinkTarget: "../shared"},
+		{Path: "/test/link.txt", RelPath: "link.txt", LinkTarget: "missing.txt", Dangling: true},
+	}
+	generator.WriteFileSystemStructure(&buf, entries)
+
+	output := buf.String()
+	for _, want := range []string{"[DIR]  vendor → ../shared\n", "[LINK] link.txt → missing.txt (リンク切れ)\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("リンク先 %q が出力されていません:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerator_WriteReport_LinkOnlyEntries(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{Path: "/test/vendor", RelPath: "vendor", LinkTarget: "../shared", LinkToDir: true},
+		{Path: "/test/dangling", RelPath: "dangling", LinkTarget: "missing.txt", Dangling: true},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		want   []string
+	}{
+		{
+			name:   "テキスト形式",
+			format: FormatText,
+			want: []string{
+				"[LINK] vendor → ../shared\n",
+				"[LINK] dangling → missing.txt (リンク切れ)\n",
+				"[フォルダへのシンボリックリンクのため内容なし] → ../shared",
+				"[リンク切れのシンボリックリンクのため内容なし] → missing.txt",
+			},
+		},
+		{
+			name:   "HTML 形式",
+			format: FormatHTML,
+			want:   []string{"[LINK] <a href=", "[フォルダへのシンボリックリンクのため内容なし] → ../shared"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGeneratorWithOptions(Options{Format: tt.format}).WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			output := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+				}
+			}
+			if strings.Contains(output, "ファイル読み込みエラー") {
+				t.Errorf("リンクの内容を読み込もうとしています:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestGenerator_DedupHardLinks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("shared content")},
+		"b.txt": {Data: []byte("shared content")},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.txt", Size: 14},
+		{RelPath: "b.txt", Size: 14, HardLinkOf: "a.txt"},
+	}
+	tests := []struct {
+		name      string
+		dedup     bool
+		wantCount int
+	}{
+		{name: "すべての内容を出力する（既定）", dedup: false, wantCount: 2},
+		{name: "ハードリンクの内容を省略する", dedup: true, wantCount: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			generator := NewGeneratorWithOptions(Options{DedupHardLinks: tt.dedup}).WithFS(fsys)
+			if err := generator.WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			output := buf.String()
+			if got := strings.Count(output, "shared content"); got != tt.wantCount {
+				t.Errorf("内容の出力回数 = %d, want %d\n%s", got, tt.wantCount, output)
+			}
+			if !strings.Contains(output, "[FILE] b.txt (ハードリンク: a.txt)\n") {
+				t.Errorf("フォルダ構成にハードリンクが表示されていません:\n%s", output)
+			}
+			if tt.dedup && !strings.Contains(output, "[a.txt のハードリンクのため内容表示省略]") {
+				t.Errorf("省略の注記が出力されていません:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteFileContentsWithMaxContentSize(t *testing.T) {
+	tempDir := t.TempDir()
+	smallPath := filepath.Join(tempDir, "small.txt")
+	largePath := filepath.Join(tempDir, "large.txt")
+	if err := os.WriteFile(smallPath, []byte("small"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(largePath, []byte(strings.Repeat("x", 2048)), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+
+	generator := NewGeneratorWithOptions(Options{MaxContentSize: 1024})
+	var buf strings.Builder
+	generator.WriteFileContents(&buf, []model.FileSystemEntry{
+		{Path: smallPath, RelPath: "small.txt", Size: 5},
+		{Path: largePath, RelPath: "large.txt", Size: 2048},
+	})
+
+	output := buf.String()
+	if !strings.Contains(output, "small\n") {
+		t.Errorf("上限以下のファイルの内容が出力されていません:\n%s", output)
+	}
+	if strings.Contains(output, strings.Repeat("x", 2048)) {
+		t.Errorf("上限を超えるファイルの内容が出力されています:\n%s", output)
+	}
+	if !strings.Contains(output, "[ファイルサイズ（2.0 KB）が上限（1.0 KB）を超えるためスキップ]") {
+		t.Errorf("上限超過の注記が出力されていません:\n%s", output)
+	}
+}
+
+// failingWriter は limit バイトを超える書き込みでエラーを返す Writer です
+type failingWriter struct {
+	limit   int
+	written int
+}
+
+var errDiskFull = errors.New("no space left on device")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.limit {
+		n := w.limit - w.written
+		w.written = w.limit
+		return n, errDiskFull
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+func TestGenerator_WriteErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	var entries []model.FileSystemEntry
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0644); err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+		entries = append(entries, model.FileSystemEntry{Path: path, RelPath: name})
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		write func(g *Generator, w *failingWriter) error
+	}{
+		{"レポート全体の途中で失敗", 150, func(g *Generator, w *failingWriter) error { return g.WriteReport(w, entries) }},
+		{"フォルダ構成の出力で失敗", 10, func(g *Generator, w *failingWriter) error { return g.WriteFileSystemStructure(w, entries) }},
+		{"ファイル内容の出力で失敗", 10, func(g *Generator, w *failingWriter) error { return g.WriteFileContents(w, entries) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &failingWriter{limit: tt.limit}
+			err := tt.write(NewGenerator(), w)
+			if !errors.Is(err, errDiskFull) {
+				t.Errorf("error = %v, want %v", err, errDiskFull)
+			}
+		})
+	}
+
+	if err := NewGenerator().WriteReport(&failingWriter{limit: 1 << 20}, entries); err != nil {
+		t.Errorf("書き込みに成功した場合はエラーを返すべきではありません: %v", err)
+	}
+}
+
+func TestGenerator_WithFS(t *testing.T) {
+	fsys := fstest.MapFS{"sub/a.txt": {Data: []byte("from archive")}}
+	entries := []model.FileSystemEntry{{Path: "/not/exist/sub/a.txt", RelPath: "sub/a.txt", Depth: 1}}
+
+	var buf strings.Builder
+	if err := NewGenerator().WithFS(fsys).WriteFileContents(&buf, entries); err != nil {
+		t.Fatalf("WriteFileContents() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "from archive") {
+		t.Errorf("fs.FS から内容が読み込まれていません:\n%s", buf.String())
+	}
+}
+
+func TestGenerator_WithAuthors(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.txt", IsBinary: true},
+		{RelPath: "b.txt", IsBinary: true},
+	}
+	authors := map[string]string{"a.txt": "alice (2), bob (1)"}
+
+	for _, format := range []Format{FormatText, FormatMarkdown, FormatHTML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf strings.Builder
+			generator := NewGeneratorWithOptions(Options{Format: format}).WithAuthors(authors)
+			if err := generator.WriteFileContents(&buf, entries); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			if got := strings.Count(buf.String(), "作成者: alice (2), bob (1)"); got != 1 {
+				t.Errorf("作成者の表示回数 = %d, want 1\n%s", got, buf.String())
+			}
+		})
+	}
+}
+
+func TestGenerator_BinaryNoticeWithMIMEType(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry model.FileSystemEntry
+		want  string
+	}{
+		{
+			name:  "メディアタイプあり",
+			entry: model.FileSystemEntry{RelPath: "logo.png", IsBinary: true, MIMEType: "image/png"},
+			want:  "[バイナリファイル（image/png）のためスキップ]",
+		},
+		{
+			name:  "メディアタイプなし",
+			entry: model.FileSystemEntry{RelPath: "data.bin", IsBinary: true},
+			want:  "[バイナリファイルのためスキップ]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGenerator().WriteFileContents(&buf, []model.FileSystemEntry{tt.entry}); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("出力に %q が含まれていない:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestGenerator_WithHunks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.go": {Data: []byte("package a\n\nvar full = true\n")},
+		"b.go": {Data: []byte("package b\n")},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.go"},
+		{RelPath: "b.go"},
+		{RelPath: "mode.sh"},
+	}
+	hunks := map[string]string{
+		"a.go":    "@@ -1 +1 @@\n-package old\n+package a\n",
+		"mode.sh": "",
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "テキスト形式",
+			format:  FormatText,
+			want:    []string{"+package a", "package b", "[内容の変更箇所はありません]"},
+			notWant: []string{"var full = true"},
+		},
+		{
+			name:    "Markdown形式ではdiffとして表示",
+			format:  FormatMarkdown,
+			want:    []string{"```diff\n@@ -1 +1 @@", "```go\npackage b"},
+			notWant: []string{"var full = true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			generator := NewGeneratorWithOptions(Options{Format: tt.format}).WithFS(fsys).WithHunks(hunks)
+			if err := generator.WriteFileContents(&buf, entries); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("出力に %q が含まれていない:\n%s", s, buf.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("出力に %q が含まれている:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNumberLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "空の内容", content: "", want: ""},
+		{name: "末尾に改行あり", content: "a\nb\n", want: "1 | a\n2 | b\n"},
+		{name: "末尾に改行なし", content: "a\nb", want: "1 | a\n2 | b"},
+		{name: "空行を含む", content: "a\n\nb\n", want: "1 | a\n2 | \n3 | b\n"},
+		{name: "桁数をそろえる", content: strings.Repeat("x\n", 10), want: " 1 | x\n" + " 2 | x\n" + " 3 | x\n" + " 4 | x\n" + " 5 | x\n" + " 6 | x\n" + " 7 | x\n" + " 8 | x\n" + " 9 | x\n" + "10 | x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(numberLines([]byte(tt.content))); got != tt.want {
+				t.Errorf("numberLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteFileContentsWithLineNumbers(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.go":   {Data: []byte("package a\n\nfunc A() {}\n")},
+		"b.diff": {Data: []byte("unused\n")},
+	}
+	entries := []model.FileSystemEntry{{RelPath: "a.go"}, {RelPath: "b.diff"}}
+	generator := NewGeneratorWithOptions(Options{LineNumbers: true}).
+		WithFS(fsys).
+		WithHunks(map[string]string{"b.diff": "@@ -1 +1 @@\n-x\n+y\n"})
+
+	var buf strings.Builder
+	if err := generator.WriteFileContents(&buf, entries); err != nil {
+		t.Fatalf("WriteFileContents() error = %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "1 | package a\n2 | \n3 | func A() {}\n") {
+		t.Errorf("行番号が付いていない:\n%s", output)
+	}
+	if !strings.Contains(output, "\n@@ -1 +1 @@\n-x\n+y\n") {
+		t.Errorf("変更箇所に行番号が付いている:\n%s", output)
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"bytes"
+	"html"
+	"path"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+
+	"FolderScope/internal/domain/model"
+)
+
+// シンタックスハイライトに使用する配色です。
+// HTML は白背景のページに合わせ、端末は暗い背景を想定します
+const (
+	htmlHighlightStyle     = "github"
+	terminalHighlightStyle = "monokai"
+)
+
+// htmlHighlighter は HTML 形式でクラス名による色付けを行うフォーマッタです。
+// 配色は文書の先頭に埋め込むスタイルシートで指定します
+var htmlHighlighter = chromahtml.New(chromahtml.WithClasses(true), chromahtml.PreventSurroundingPre(true))
+
+// highlights は、ファイル内容をシンタックスハイライトして出力するかどうかを返します。
+// ハイライトはテキスト形式（ANSI エスケープシーケンス）と HTML 形式のみに対応し、WithFormatter で指定した Formatter では行いません
+func (g *Generator) highlights() bool {
+	return g.options.Highlight && g.formatter == nil && (g.options.Format == FormatText || g.options.Format == "" || g.options.Format == FormatHTML)
+}
+
+// highlight はファイルの内容を、拡張子から判定した言語に応じて色付けします。
+// HTML 形式ではエスケープ済みのマークアップを返します。言語を判定できない場合は色付けせずに返します
+func (g *Generator) highlight(entry model.FileSystemEntry, content []byte) []byte {
+	lexer := lexers.Match(path.Base(entry.RelPath))
+	if _, isHunks := g.hunksOf(entry); isHunks {
+		lexer = lexers.Get("diff")
+	}
+
+	formatter, style := formatters.TTY256, styles.Get(terminalHighlightStyle)
+	if g.options.Format == FormatHTML {
+		formatter, style = htmlHighlighter, styles.Get(htmlHighlightStyle)
+	}
+	if lexer == nil {
+		return g.plain(content)
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, string(content))
+	if err != nil {
+		return g.plain(content)
+	}
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, style, iterator); err != nil {
+		return g.plain(content)
+	}
+	return buf.Bytes()
+}
+
+// plain は色付けしない内容を、出力形式に合わせて返します
+func (g *Generator) plain(content []byte) []byte {
+	if g.options.Format == FormatHTML {
+		return []byte(html.EscapeString(string(content)))
+	}
+	return content
+}
+
+// highlightCSS は HTML 形式でシンタックスハイライトする場合に、文書の先頭に埋め込むスタイルシートを返します
+func (g *Generator) highlightCSS() string {
+	if !g.highlights() || g.options.Format != FormatHTML {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := htmlHighlighter.WriteCSS(&buf, styles.Get(htmlHighlightStyle)); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_Highlight(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":   {Data: []byte("package main\n\nfunc main() { println(\"<hi>\") }\n")},
+		"notes.xyz": {Data: []byte("<plain & text>\n")},
+	}
+	entries := []model.FileSystemEntry{{RelPath: "main.go"}, {RelPath: "notes.xyz"}}
+
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "テキスト形式ではANSIエスケープシーケンスで色付け",
+			options: Options{Format: FormatText, Highlight: true},
+			want:    []string{"\x1b[", "<plain & text>"},
+		},
+		{
+			name:    "HTML形式ではクラスで色付けし、スタイルシートを埋め込む",
+			options: Options{Format: FormatHTML, Highlight: true},
+			want:    []string{`<pre class="chroma"><code>`, `<span class="kd">func</span>`, "&lt;plain &amp; text&gt;", ".chroma .kd {"},
+			notWant: []string{"<hi>", "<plain & text>"},
+		},
+		{
+			name:    "Markdown形式には影響しない",
+			options: Options{Format: FormatMarkdown, Highlight: true},
+			want:    []string{"```go\npackage main"},
+			notWant: []string{"\x1b[", "<span"},
+		},
+		{
+			name:    "無効の場合は色付けしない",
+			options: Options{Format: FormatText},
+			want:    []string{"func main()"},
+			notWant: []string{"\x1b["},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGeneratorWithOptions(tt.options).WithFS(fsys).WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("出力に %q が含まれていない:\n%s", s, buf.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("出力に %q が含まれている:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
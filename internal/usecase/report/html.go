@@ -0,0 +1,198 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// htmlStyleFile は HTML レポートに埋め込むスタイルシートです
+//
+//go:embed assets/report.css
+var htmlStyleFile string
+
+// htmlStyle は末尾の改行を除いたスタイルシートです
+var htmlStyle = strings.TrimSuffix(htmlStyleFile, "\n")
+
+// writeHTMLDocumentStart は HTML 文書の先頭部分を出力します。
+// extraCSS はシンタックスハイライト用など、標準のスタイルシートに追加するスタイルです
+func writeHTMLDocumentStart(writer io.Writer, extraCSS string) {
+	fmt.Fprintln(writer, "<!DOCTYPE html>")
+	fmt.Fprintln(writer, `<html lang="ja">`)
+	fmt.Fprintln(writer, "<head>")
+	fmt.Fprintln(writer, `<meta charset="utf-8">`)
+	fmt.Fprintln(writer, "<title>FolderScope レポート</title>")
+	fmt.Fprintf(writer, "<style>\n%s\n%s</style>\n", htmlStyle, extraCSS)
+	fmt.Fprintln(writer, "</head>")
+	fmt.Fprintln(writer, "<body>")
+}
+
+// writeHTMLDocumentEnd は HTML 文書の末尾部分を出力します
+func writeHTMLDocumentEnd(writer io.Writer) {
+	fmt.Fprintln(writer, "</body>")
+	fmt.Fprintln(writer, "</html>")
+}
+
+// writeHTMLStructureStart はフォルダ構成の見出しを出力し、構成を囲む要素を開始します
+func (g *Generator) writeHTMLStructureStart(writer io.Writer) {
+	fmt.Fprintln(writer, "<h2>フォルダ・ファイル構成</h2>")
+	fmt.Fprintln(writer, `<div class="tree">`)
+	if g.options.TreeStyle == TreeLines {
+		fmt.Fprintln(writer, ".")
+	}
+}
+
+// writeHTMLStructureEntry はフォルダ構成の 1 行を出力します。
+// 各ファイルは内容セクションへのリンクとなり、各項目には内容側から戻るためのアンカーを付与します
+func (g *Generator) writeHTMLStructureEntry(writer io.Writer, entry StructureEntry, a anchors) {
+	var label string
+	switch {
+	case g.options.TreeStyle == TreeLines && entry.IsDir:
+		label = html.EscapeString(treeName(entry.FileSystemEntry))
+	case g.options.TreeStyle == TreeLines:
+		label = fmt.Sprintf(`<a href="#%s">%s</a>`, a.file(entry.RelPath), html.EscapeString(treeName(entry.FileSystemEntry)))
+	case entry.IsDir:
+		label = fmt.Sprintf("[DIR]  %s", html.EscapeString(entry.RelPath))
+	case entry.IsLinkOnly():
+		label = fmt.Sprintf(`[LINK] <a href="#%s">%s</a>`, a.file(entry.RelPath), html.EscapeString(entry.RelPath))
+	default:
+		label = fmt.Sprintf(`[FILE] <a href="#%s">%s</a>`, a.file(entry.RelPath), html.EscapeString(entry.RelPath))
+	}
+	label += html.EscapeString(entry.Annotation)
+	fmt.Fprintf(writer, "<span id=\"%s\">%s%s%s</span>\n", a.tree(entry.RelPath), html.EscapeString(entry.Listing), entry.Prefix, label)
+}
+
+// writeHTMLStructureEnd はフォルダ構成を囲む要素を閉じます
+func writeHTMLStructureEnd(writer io.Writer) {
+	fmt.Fprintln(writer, "</div>")
+}
+
+// writeHTMLSection は 1 ファイル分の内容を section 要素として出力します。
+// 見出しには構成内の位置へ戻るリンクを付与します
+func (g *Generator) writeHTMLSection(writer io.Writer, c FileContent, a anchors) {
+	fmt.Fprintf(writer, "<section class=\"file\" id=\"%s\">\n", a.file(c.RelPath))
+	fmt.Fprintf(writer, "<h3>%s", html.EscapeString(c.RelPath))
+	if c.InStructure {
+		fmt.Fprintf(writer, `<a class="back" href="#%s">↑ 構成に戻る</a>`, a.tree(c.RelPath))
+	}
+	fmt.Fprintln(writer, "</h3>")
+	if c.Authors != "" {
+		fmt.Fprintf(writer, "<p class=\"authors\">作成者: %s</p>\n", html.EscapeString(c.Authors))
+	}
+	if c.Metrics != nil {
+		fmt.Fprintf(writer, "<p class=\"metrics\">指標: %s</p>\n", html.EscapeString(c.Metrics.String()))
+	}
+
+	if c.Notice != "" {
+		fmt.Fprintf(writer, "<p class=\"notice\">%s</p>\n", html.EscapeString(c.Notice))
+	} else if g.highlights() {
+		// シンタックスハイライト済みの内容はエスケープ済みのマークアップ
+		fmt.Fprintf(writer, "<pre class=\"chroma\"><code>%s</code></pre>\n", c.Content)
+	} else {
+		fmt.Fprintf(writer, "<pre><code>%s</code></pre>\n", html.EscapeString(string(c.Content)))
+	}
+	fmt.Fprintln(writer, "</section>")
+}
+
+// htmlPageScriptFile はページ分割された HTML レポートで、選択されたページの template のみを DOM に展開するスクリプトです。
+// リンク先（#file-...）を含むページを自動的に表示し、戻りリンク（#tree-...）はそのまま構成へ移動します
+//
+//go:embed assets/pages.js
+var htmlPageScriptFile string
+
+// htmlPageScript は末尾の改行を除いたスクリプトです
+var htmlPageScript = strings.TrimSuffix(htmlPageScriptFile, "\n")
+
+// writeHTMLPageSidebar はページごとのファイル一覧をサイドバーとして出力します
+func writeHTMLPageSidebar(writer io.Writer, files []model.FileSystemEntry, pageSize int, a anchors) {
+	fmt.Fprintln(writer, `<nav class="pages">`)
+	fmt.Fprintln(writer, "<strong>ページ一覧</strong>")
+	for start := 0; start < len(files); start += pageSize {
+		end := start + pageSize
+		if end > len(files) {
+			end = len(files)
+		}
+		page := start/pageSize + 1
+		fmt.Fprintf(writer, "<details><summary><a href=\"#page-%d\">ページ %d</a> (%d件)</summary>\n<ul>\n", page, page, end-start)
+		for _, entry := range files[start:end] {
+			fmt.Fprintf(writer, "<li><a href=\"#%s\">%s</a></li>\n", a.file(entry.RelPath), html.EscapeString(entry.RelPath))
+		}
+		fmt.Fprintln(writer, "</ul></details>")
+	}
+	fmt.Fprintln(writer, "</nav>")
+}
+
+// writeHTMLPageStart はページを表す template 要素の開始タグを出力します。
+// template の内容は表示されるまで描画されないため、DOM の肥大化を防げます
+func writeHTMLPageStart(writer io.Writer, page int) {
+	fmt.Fprintf(writer, "<template class=\"page\" data-page=\"%d\">\n", page)
+}
+
+// writeHTMLPageEnd はページを表す template 要素の終了タグを出力します
+func writeHTMLPageEnd(writer io.Writer) {
+	fmt.Fprintln(writer, "</template>")
+}
+
+// writeHTMLPageViewer は選択されたページを展開する領域とスクリプトを出力します
+func writeHTMLPageViewer(writer io.Writer) {
+	fmt.Fprintln(writer, `<div id="page-view"></div>`)
+	fmt.Fprintf(writer, "<script>\n%s\n</script>\n", htmlPageScript)
+}
+
+// htmlPages は HTML 形式でファイル内容をページに分割して出力する状態です。
+// ページ分割しない場合（HTML 以外の形式、または HTMLPageSize が 0 の場合）は何も出力しません
+type htmlPages struct {
+	size int
+	// total は出力するセクションの数、written は出力したセクションの数です
+	total, written int
+	// open はページの template 要素を開始したまま、閉じていないことを示します
+	open bool
+}
+
+// newHTMLPages は total 個のセクションを出力する場合のページ分割の状態を作成します
+func (g *Generator) newHTMLPages(total int) *htmlPages {
+	if g.options.Format != FormatHTML || g.options.HTMLPageSize <= 0 {
+		return &htmlPages{}
+	}
+	return &htmlPages{size: g.options.HTMLPageSize, total: total}
+}
+
+// start はページ一覧のサイドバーを出力します
+func (p *htmlPages) start(writer io.Writer, files []model.FileSystemEntry, a anchors) {
+	if p.size > 0 {
+		writeHTMLPageSidebar(writer, files, p.size, a)
+	}
+}
+
+// beforeSection はページの最初のセクションの前に、ページを開始します
+func (p *htmlPages) beforeSection(writer io.Writer) {
+	if p.size > 0 && p.written%p.size == 0 {
+		writeHTMLPageStart(writer, p.written/p.size+1)
+		p.open = true
+	}
+}
+
+// afterSection はページの最後のセクション、またはすべてのセクションを出力した後に、ページを閉じます
+func (p *htmlPages) afterSection(writer io.Writer) {
+	p.written++
+	if p.size > 0 && (p.written%p.size == 0 || p.written == p.total) {
+		writeHTMLPageEnd(writer)
+		p.open = false
+	}
+}
+
+// finish は出力中のページがあれば閉じ（途中で中止した場合、出力したページのみを表示できるようにする）、ページを展開する領域を出力します
+func (p *htmlPages) finish(writer io.Writer) {
+	if p.size <= 0 {
+		return
+	}
+	if p.open {
+		writeHTMLPageEnd(writer)
+		p.open = false
+	}
+	writeHTMLPageViewer(writer)
+}
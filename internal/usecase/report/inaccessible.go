@@ -0,0 +1,51 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+
+	"FolderScope/internal/domain/model"
+)
+
+// inaccessibleNotice はアクセスできなかったパスの一覧の前に記載する説明です
+const inaccessibleNotice = "権限がないため、次のパスの内容はレポートに含まれていません。このスナップショットは不完全です"
+
+// writeInaccessible は権限がないために読み込めなかったパスの一覧を出力します。該当するパスがない場合は何も出力しません
+func (g *Generator) writeInaccessible(writer io.Writer, paths []model.InaccessiblePath) {
+	if len(paths) == 0 {
+		return
+	}
+	label := func(relPath string, isDir bool) string {
+		if relPath == "." {
+			return "（ルートフォルダ）"
+		}
+		if isDir {
+			return relPath + "/（フォルダの配下すべて）"
+		}
+		return relPath
+	}
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintf(writer, "## アクセスできなかったパス（%d 件）\n\n", len(paths))
+		fmt.Fprintf(writer, "%s。\n\n", inaccessibleNotice)
+		for _, p := range paths {
+			fmt.Fprintf(writer, "- %s\n", escapeMarkdown(label(p.RelPath, p.IsDir)))
+		}
+		fmt.Fprintln(writer)
+	case FormatHTML:
+		fmt.Fprintf(writer, "<h2>アクセスできなかったパス（%d 件）</h2>\n", len(paths))
+		fmt.Fprintf(writer, "<p>%s。</p>\n<ul>\n", inaccessibleNotice)
+		for _, p := range paths {
+			fmt.Fprintf(writer, "<li>%s</li>\n", html.EscapeString(label(p.RelPath, p.IsDir)))
+		}
+		fmt.Fprintln(writer, "</ul>")
+	default:
+		fmt.Fprintf(writer, "===== アクセスできなかったパス（%d 件） =====\n", len(paths))
+		fmt.Fprintf(writer, "  %s\n", inaccessibleNotice)
+		for _, p := range paths {
+			fmt.Fprintf(writer, "  %s\n", label(p.RelPath, p.IsDir))
+		}
+		fmt.Fprintln(writer)
+	}
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteInaccessible(t *testing.T) {
+	stats := model.ScanStats{Inaccessible: []model.InaccessiblePath{
+		{RelPath: "private", IsDir: true},
+		{RelPath: "docs/owner.txt"},
+	}}
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{FormatText, []string{"===== アクセスできなかったパス（2 件） =====", "  private/（フォルダの配下すべて）\n", "  docs/owner.txt\n"}},
+		{FormatMarkdown, []string{"## アクセスできなかったパス（2 件）", "- private/（フォルダの配下すべて）\n", "- docs/owner.txt\n"}},
+		{FormatHTML, []string{"<h2>アクセスできなかったパス（2 件）</h2>", "<li>docs/owner.txt</li>"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf strings.Builder
+			g := NewGeneratorWithOptions(Options{Format: tt.format}).WithScanStats(stats)
+			if err := g.WriteReport(&buf, nil); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			for _, want := range append(tt.want, "このスナップショットは不完全です") {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	// アクセスできなかったパスがない場合は記載しない
+	var buf strings.Builder
+	if err := NewGenerator().WithScanStats(model.ScanStats{}).WriteReport(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "アクセスできなかったパス") {
+		t.Errorf("アクセスできなかったパスがないのに記載されています:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,167 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// IncrementalStats は増分レポート生成の結果を表します
+type IncrementalStats struct {
+	// Rendered は再生成したファイルセクション数です
+	Rendered int
+	// Reused はキャッシュから再利用したファイルセクション数です
+	Reused int
+	// Pruned は削除されたファイルのためにキャッシュから破棄したセクション数です
+	Pruned int
+}
+
+// cachedSection はレンダリング済みのファイルセクションと、その生成時のファイル状態です
+type cachedSection struct {
+	size     int64
+	modTime  time.Time
+	isBinary bool
+	readErr  bool
+	anchor   string
+	// hash はスキャン時に計算した内容のハッシュです。計算していない場合は空です
+	hash string
+	body []byte
+	// redactions はセクションの生成時に内容からマスクした情報です。再利用時にマスクしたファイルの一覧へ含めます
+	redactions []Redaction
+}
+
+// IncrementalGenerator はファイルごとの内容セクションをキャッシュし、
+// 変更されたファイルのセクションのみを再生成します。
+// 監視モードのように同じツリーのレポートを繰り返し生成する用途で、
+// 大きなツリーでも再生成を高速に保つために使用します。
+type IncrementalGenerator struct {
+	generator *Generator
+	mu        sync.Mutex
+	sections  map[string]cachedSection
+}
+
+// NewIncrementalGenerator は新しい IncrementalGenerator インスタンスを作成します
+func NewIncrementalGenerator(generator *Generator) *IncrementalGenerator {
+	return &IncrementalGenerator{
+		generator: generator,
+		sections:  make(map[string]cachedSection),
+	}
+}
+
+// Write はレポート全体（フォルダ構成と内容セクション）を、WithFormatter で指定した Formatter、または出力形式の既定の構成で出力します。
+// 内容セクションは、前回の生成時からサイズ・更新日時・判定結果が変わっていなければキャッシュを再利用します。
+// 書き込みに失敗した場合は、それまでの統計とともにエラーを返します。
+// WithContext で指定したコンテキストがキャンセルされて出力を中止した場合は、レポートの末尾に中止の理由を記載し、
+// apperrors.ErrCancelled の種類のエラーを返します
+func (ig *IncrementalGenerator) Write(w io.Writer, entries []model.FileSystemEntry) (IncrementalStats, error) {
+	ig.mu.Lock()
+	defer ig.mu.Unlock()
+
+	var stats IncrementalStats
+
+	// データ形式・PDF・SQLite・テンプレートでの出力はセクション単位で再利用できないため、常に全体を出力する
+	g := ig.generator
+	if g.formatter == nil && (g.options.Format.isData() || g.options.Format == FormatPDF || g.options.Format == FormatSQLite || g.template != nil) {
+		ig.sections = make(map[string]cachedSection)
+		for _, entry := range entries {
+			if !entry.IsDir {
+				stats.Rendered++
+			}
+		}
+		return stats, g.WriteReport(w, entries)
+	}
+	generator := g.withOutputLog()
+	f := generator.formatter
+	if f == nil {
+		f = newBuiltinFormatter(generator)
+	}
+	a := buildAnchors(entries)
+
+	seen := make(map[string]struct{}, len(entries))
+	err := generator.writeFormattedSections(w, f, entries, func(w io.Writer, entry model.FileSystemEntry) error {
+		seen[entry.RelPath] = struct{}{}
+
+		current, statOK := ig.fingerprint(entry, a)
+		if cached, ok := ig.sections[entry.RelPath]; ok && statOK && cached.matches(current) {
+			w.Write(cached.body)
+			generator.redactions.record(entry.RelPath, cached.redactions)
+			stats.Reused++
+			// 前回はハッシュを計算していなかった場合も、次回以降は内容の変更を検出できるよう記録する
+			if current.hash != "" {
+				cached.hash = current.hash
+				ig.sections[entry.RelPath] = cached
+			}
+			return nil
+		}
+
+		var buf bytes.Buffer
+		if err := f.WriteContent(&buf, generator.fileContent(entry)); err != nil {
+			return err
+		}
+		w.Write(buf.Bytes())
+		stats.Rendered++
+
+		if statOK {
+			current.body = buf.Bytes()
+			current.redactions = generator.redactionsOf(entry.RelPath)
+			ig.sections[entry.RelPath] = current
+		} else {
+			delete(ig.sections, entry.RelPath)
+		}
+		return nil
+	})
+	// 書き込みに失敗した場合や中止した場合は途中までしか処理しておらず、出力していないファイルも seen に含まれないため、
+	// キャッシュを残したまま終了して次回の Write で再利用する
+	if err != nil {
+		return stats, err
+	}
+
+	// 今回のエントリに含まれないファイルのキャッシュを破棄
+	for relPath := range ig.sections {
+		if _, ok := seen[relPath]; !ok {
+			delete(ig.sections, relPath)
+			stats.Pruned++
+		}
+	}
+	return stats, nil
+}
+
+// Invalidate は指定された相対パスのキャッシュを破棄し、次回の Write で必ず再生成させます
+func (ig *IncrementalGenerator) Invalidate(relPaths ...string) {
+	ig.mu.Lock()
+	defer ig.mu.Unlock()
+	for _, relPath := range relPaths {
+		delete(ig.sections, relPath)
+	}
+}
+
+// fingerprint はエントリの現在の状態を取得します。
+// ファイル情報を取得できない場合は false を返し、そのセクションはキャッシュしません。
+func (ig *IncrementalGenerator) fingerprint(entry model.FileSystemEntry, a anchors) (cachedSection, bool) {
+	info, err := ig.generator.statFile(entry)
+	if err != nil {
+		return cachedSection{}, false
+	}
+	return cachedSection{
+		size:     info.Size(),
+		modTime:  info.ModTime(),
+		isBinary: entry.IsBinary,
+		readErr:  entry.ReadErr != nil,
+		anchor:   a[entry.RelPath],
+		hash:     entry.Hash,
+	}, true
+}
+
+// matches はキャッシュ済みセクションが現在のファイル状態と一致するかどうかを返します。
+// 両方のスキャンでハッシュを計算している場合は、サイズと更新日時が同じでも内容が変わったファイルを検出します
+func (c cachedSection) matches(current cachedSection) bool {
+	return c.size == current.size &&
+		c.modTime.Equal(current.modTime) &&
+		c.isBinary == current.isBinary &&
+		c.readErr == current.readErr &&
+		c.anchor == current.anchor &&
+		(c.hash == "" || current.hash == "" || c.hash == current.hash)
+}
@@ -0,0 +1,161 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+)
+
+func TestIncrementalGenerator_Write(t *testing.T) {
+	tempDir := t.TempDir()
+	file1Path := filepath.Join(tempDir, "a.txt")
+	file2Path := filepath.Join(tempDir, "b.txt")
+	if err := os.WriteFile(file1Path, []byte("alpha"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(file2Path, []byte("beta"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+
+	entries := []model.FileSystemEntry{
+		{Path: file1Path, RelPath: "a.txt"},
+		{Path: file2Path, RelPath: "b.txt"},
+	}
+
+	ig := NewIncrementalGenerator(NewGenerator())
+
+	var first strings.Builder
+	stats, err := ig.Write(&first, entries)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if stats.Rendered != 2 || stats.Reused != 0 {
+		t.Errorf("初回生成の統計が不正: %+v", stats)
+	}
+
+	// b.txt のみ更新する（更新日時の粒度に依存しないよう明示的に変更）
+	if err := os.WriteFile(file2Path, []byte("beta updated"), 0644); err != nil {
+		t.Fatalf("ファイルの更新に失敗: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file2Path, future, future); err != nil {
+		t.Fatalf("更新日時の変更に失敗: %v", err)
+	}
+
+	var second strings.Builder
+	stats, err = ig.Write(&second, entries)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if stats.Rendered != 1 || stats.Reused != 1 {
+		t.Errorf("増分生成の統計が不正: %+v", stats)
+	}
+	if !strings.Contains(second.String(), "beta updated") {
+		t.Errorf("更新された内容が出力されていません:\n%s", second.String())
+	}
+	if !strings.Contains(second.String(), "alpha") {
+		t.Errorf("キャッシュされた内容が出力されていません:\n%s", second.String())
+	}
+
+	// 全生成した場合と同じ出力になること
+	var full strings.Builder
+	generator := NewGenerator()
+	generator.WriteFileSystemStructure(&full, entries)
+	generator.WriteFileContents(&full, entries)
+	if full.String() != second.String() {
+		t.Errorf("増分生成の出力が全生成と一致しません\n増分:\n%s\n全生成:\n%s", second.String(), full.String())
+	}
+
+	// サイズと更新日時が同じでも、スキャン時のハッシュが変わったファイルは再生成される
+	hashed := []model.FileSystemEntry{entries[0], entries[1]}
+	hashed[0].Hash = "1111"
+	if _, err := ig.Write(&strings.Builder{}, hashed); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	hashed[0].Hash = "2222"
+	stats, err = ig.Write(&strings.Builder{}, hashed)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if stats.Rendered != 1 || stats.Reused != 1 {
+		t.Errorf("ハッシュの変更後の統計が不正: %+v", stats)
+	}
+
+	// 削除されたファイルのキャッシュは破棄される
+	var third strings.Builder
+	stats, err = ig.Write(&third, entries[:1])
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if stats.Pruned != 1 || stats.Reused != 1 {
+		t.Errorf("削除後の統計が不正: %+v", stats)
+	}
+
+	// Invalidate したファイルは再生成される
+	ig.Invalidate("a.txt")
+	var fourth strings.Builder
+	stats, err = ig.Write(&fourth, entries[:1])
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if stats.Rendered != 1 {
+		t.Errorf("Invalidate 後の統計が不正: %+v", stats)
+	}
+}
+
+func TestIncrementalGenerator_Write_Cancelled(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("alpha")},
+		"b.txt": {Data: []byte("beta")},
+	}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 5}, {RelPath: "b.txt", Size: 4}}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	ig := NewIncrementalGenerator(NewGenerator().WithFS(fsys).WithContext(ctx))
+	if _, err := ig.Write(&strings.Builder{}, entries); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// 中止した場合は末尾に理由を記載してエラーを返し、出力していないファイルのキャッシュは破棄しない
+	cancel(apperrors.Cancelled(apperrors.CancelSignal, "interrupt"))
+	var out strings.Builder
+	stats, err := ig.Write(&out, entries)
+	if !errors.Is(err, apperrors.ErrCancelled) || apperrors.ReasonOf(err) != apperrors.CancelSignal {
+		t.Errorf("Write() error = %v, want ErrCancelled（理由: signal）", err)
+	}
+	if !strings.Contains(out.String(), "===== 出力の中止 =====") {
+		t.Errorf("中止したレポートに理由が記載されていません:\n%s", out.String())
+	}
+	if stats.Pruned != 0 || len(ig.sections) != 2 {
+		t.Errorf("中止後の統計 = %+v, キャッシュ %d 件, want 破棄なし", stats, len(ig.sections))
+	}
+}
+
+func TestIncrementalGenerator_Write_Formatter(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("alpha")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 5}}
+	ig := NewIncrementalGenerator(NewGenerator().WithFS(fsys).WithFormatter(&csvFormatter{}))
+
+	// WithFormatter で指定した Formatter で書き込み、2 回目はキャッシュしたセクションを再利用する
+	for i, want := range []IncrementalStats{{Rendered: 1}, {Reused: 1}} {
+		var buf strings.Builder
+		stats, err := ig.Write(&buf, entries)
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if stats != want {
+			t.Errorf("%d 回目の統計 = %+v, want %+v", i+1, stats, want)
+		}
+		wantOut := "header,1,1\nentry,a.txt,\"\"\ncontent,a.txt,\"alpha\",\"\",txt\nfooter,,\n"
+		if buf.String() != wantOut {
+			t.Errorf("%d 回目の出力 =\n%s\nwant\n%s", i+1, buf.String(), wantOut)
+		}
+	}
+}
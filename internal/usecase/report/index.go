@@ -0,0 +1,150 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// IndexFileSuffix はインデックスファイルのレポートパスに付与する接尾辞です
+const IndexFileSuffix = ".index.json"
+
+// IndexEntry はレポート内の 1 ファイル分の内容セクションの位置を表します
+type IndexEntry struct {
+	// RelPath はファイルのルートからの相対パスです
+	RelPath string `json:"relPath"`
+	// Offset はレポート先頭からセクション開始位置までのバイト数です
+	Offset int64 `json:"offset"`
+	// Length はセクションのバイト数です
+	Length int64 `json:"length"`
+}
+
+// Index はレポートファイルと、その内容セクションの位置一覧です
+type Index struct {
+	// Report はインデックス対象のレポートファイル名です
+	Report string `json:"report"`
+	// Entries はセクションの位置一覧（レポート内の出現順）です
+	Entries []IndexEntry `json:"entries"`
+}
+
+// SectionMarker はファイルセクションの開始・終了を通知される Writer が実装するインターフェースです。
+// Generator は書き込み先がこのインターフェースを実装している場合、各セクションの前後で通知します
+type SectionMarker interface {
+	MarkSectionStart(relPath string)
+	MarkSectionEnd()
+}
+
+// IndexingWriter は書き込まれたバイト数を数え、ファイルセクションの位置を記録する io.Writer です
+type IndexingWriter struct {
+	writer  io.Writer
+	offset  int64
+	current *IndexEntry
+	entries []IndexEntry
+}
+
+// NewIndexingWriter は新しい IndexingWriter インスタンスを作成します
+func NewIndexingWriter(writer io.Writer) *IndexingWriter {
+	return &IndexingWriter{writer: writer}
+}
+
+// Write は下位の Writer に書き込み、書き込まれたバイト数を加算します
+func (w *IndexingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.offset += int64(n)
+	return n, err
+}
+
+// MarkSectionStart は現在の位置をセクションの開始位置として記録します
+func (w *IndexingWriter) MarkSectionStart(relPath string) {
+	w.current = &IndexEntry{RelPath: relPath, Offset: w.offset}
+}
+
+// MarkSectionEnd は現在の位置までをセクションとして確定します
+func (w *IndexingWriter) MarkSectionEnd() {
+	if w.current == nil {
+		return
+	}
+	w.current.Length = w.offset - w.current.Offset
+	w.entries = append(w.entries, *w.current)
+	w.current = nil
+}
+
+// Written はこれまでに書き込まれたバイト数を返します
+func (w *IndexingWriter) Written() int64 {
+	return w.offset
+}
+
+// Entries は記録されたセクション位置の一覧を返します
+func (w *IndexingWriter) Entries() []IndexEntry {
+	return w.entries
+}
+
+// markSectionStart は writer が SectionMarker を実装していればセクション開始を通知します
+func markSectionStart(writer io.Writer, relPath string) {
+	if marker, ok := writer.(SectionMarker); ok {
+		marker.MarkSectionStart(relPath)
+	}
+}
+
+// markSectionEnd は writer が SectionMarker を実装していればセクション終了を通知します
+func markSectionEnd(writer io.Writer) {
+	if marker, ok := writer.(SectionMarker); ok {
+		marker.MarkSectionEnd()
+	}
+}
+
+// WriteIndexFile はインデックスを JSON としてファイルに書き込みます
+func WriteIndexFile(path string, index Index) error {
+	data, err := EncodeIndex(index)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("インデックスファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// EncodeIndex はインデックスファイルの内容を返します。zip の出力ファイルにインデックスを含める場合に使用します
+func EncodeIndex(index Index) ([]byte, error) {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("インデックスのエンコードに失敗しました: %w", err)
+	}
+	return data, nil
+}
+
+// LoadIndexFile はインデックスファイルを読み込みます
+func LoadIndexFile(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("インデックスファイルの読み込みに失敗しました: %w", err)
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("インデックスファイルの解析に失敗しました: %w", err)
+	}
+	return &index, nil
+}
+
+// Lookup は相対パスに対応するセクション位置を返します
+func (idx *Index) Lookup(relPath string) (IndexEntry, bool) {
+	for _, entry := range idx.Entries {
+		if entry.RelPath == relPath {
+			return entry, true
+		}
+	}
+	return IndexEntry{}, false
+}
+
+// ReadSection はレポートからインデックスが示すセクションのみを読み込みます。
+// レポート全体を走査せずに、指定位置へ直接シークします
+func ReadSection(report io.ReaderAt, entry IndexEntry) ([]byte, error) {
+	buf := make([]byte, entry.Length)
+	n, err := report.ReadAt(buf, entry.Offset)
+	if err != nil && !(err == io.EOF && int64(n) == entry.Length) {
+		return nil, fmt.Errorf("セクション '%s' の読み込みに失敗しました: %w", entry.RelPath, err)
+	}
+	return buf, nil
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestIndexingWriter(t *testing.T) {
+	tempDir := t.TempDir()
+	paths := map[string]string{"a.txt": "alpha", "dir/b.txt": "beta"}
+	var entries []model.FileSystemEntry
+	for _, rel := range []string{"a.txt", "dir/b.txt"} {
+		path := filepath.Join(tempDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("ディレクトリの作成に失敗: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(paths[rel]), 0644); err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+		entries = append(entries, model.FileSystemEntry{Path: path, RelPath: rel})
+	}
+
+	var buf bytes.Buffer
+	writer := NewIndexingWriter(&buf)
+	generator := NewGenerator()
+	generator.WriteFileSystemStructure(writer, entries)
+	generator.WriteFileContents(writer, entries)
+
+	index := Index{Report: "report.txt", Entries: writer.Entries()}
+	if len(index.Entries) != 2 {
+		t.Fatalf("インデックス件数が不正: got %d, want 2", len(index.Entries))
+	}
+
+	for rel, content := range paths {
+		entry, ok := index.Lookup(rel)
+		if !ok {
+			t.Fatalf("インデックスに %s が含まれていません", rel)
+		}
+		section, err := ReadSection(bytes.NewReader(buf.Bytes()), entry)
+		if err != nil {
+			t.Fatalf("ReadSection() error = %v", err)
+		}
+		if !strings.HasPrefix(string(section), "----- "+rel+" -----") || !strings.Contains(string(section), content) {
+			t.Errorf("セクションの内容が不正: %q", section)
+		}
+	}
+
+	// ファイルへの保存と読み込み
+	indexPath := filepath.Join(tempDir, "report.txt"+IndexFileSuffix)
+	if err := WriteIndexFile(indexPath, index); err != nil {
+		t.Fatalf("WriteIndexFile() error = %v", err)
+	}
+	loaded, err := LoadIndexFile(indexPath)
+	if err != nil {
+		t.Fatalf("LoadIndexFile() error = %v", err)
+	}
+	if loaded.Report != "report.txt" || len(loaded.Entries) != 2 || loaded.Entries[1] != index.Entries[1] {
+		t.Errorf("読み込んだインデックスが不正: %+v", loaded)
+	}
+}
@@ -0,0 +1,171 @@
+package report
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// commentSyntax は言語ごとのコメントの記法です
+type commentSyntax struct {
+	line       []string
+	blockStart string
+	blockEnd   string
+}
+
+var (
+	cStyleComments = &commentSyntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashComments   = &commentSyntax{line: []string{"#"}}
+	dashComments   = &commentSyntax{line: []string{"--"}}
+	markupComments = &commentSyntax{blockStart: "<!--", blockEnd: "-->"}
+	cssComments    = &commentSyntax{blockStart: "/*", blockEnd: "*/"}
+	matlabComments = &commentSyntax{line: []string{"%"}}
+)
+
+var (
+	jsFunctions    = regexp.MustCompile(`\bfunction\b|\)\s*=>`)
+	tsFunctions    = regexp.MustCompile(`\bfunction\b|\)\s*(:\s*[^=]+)?=>`)
+	shellFunctions = regexp.MustCompile(`^\s*(function\s+\w+|\w+\s*\(\)\s*\{)`)
+)
+
+// language は言語の判定方法と、行の分類に使用する記法です
+type language struct {
+	name string
+	// extensions は小文字の拡張子（先頭のドットを含む）です
+	extensions []string
+	// filenames は拡張子のないファイル名（Makefile など）です
+	filenames []string
+	// interpreters はシェバン行で指定されるインタプリタ名です
+	interpreters []string
+	// comments はコメントの記法です。コメントのない形式や不明な場合は nil です
+	comments *commentSyntax
+	// functions は関数定義とみなす行の正規表現です。定義の書式に決まったキーワードがある言語のみ設定します
+	functions *regexp.Regexp
+}
+
+// languages は判定できる言語の一覧です
+var languages = []language{
+	{name: "Go", extensions: []string{".go"}, comments: cStyleComments, functions: regexp.MustCompile(`^\s*func\b`)},
+	{name: "C", extensions: []string{".c"}, comments: cStyleComments},
+	{name: "C++", extensions: []string{".cc", ".cpp", ".cxx", ".hpp", ".hh"}, comments: cStyleComments},
+	{name: "C#", extensions: []string{".cs"}, comments: cStyleComments},
+	{name: "Java", extensions: []string{".java"}, comments: cStyleComments},
+	{name: "JavaScript", extensions: []string{".js", ".jsx", ".mjs", ".cjs"}, interpreters: []string{"node", "nodejs"}, comments: cStyleComments, functions: jsFunctions},
+	{name: "TypeScript", extensions: []string{".ts", ".tsx"}, comments: cStyleComments, functions: tsFunctions},
+	{name: "Kotlin", extensions: []string{".kt", ".kts"}, comments: cStyleComments, functions: regexp.MustCompile(`\bfun\s`)},
+	{name: "Swift", extensions: []string{".swift"}, comments: cStyleComments, functions: regexp.MustCompile(`\bfunc\s`)},
+	{name: "Rust", extensions: []string{".rs"}, comments: cStyleComments, functions: regexp.MustCompile(`\bfn\s+\w`)},
+	{name: "Scala", extensions: []string{".scala"}, comments: cStyleComments, functions: regexp.MustCompile(`\bdef\s`)},
+	{name: "PHP", extensions: []string{".php"}, interpreters: []string{"php"}, comments: cStyleComments, functions: regexp.MustCompile(`\bfunction\b`)},
+	{name: "Dart", extensions: []string{".dart"}, comments: cStyleComments},
+	{name: "Objective-C", extensions: []string{".mm"}, comments: cStyleComments},
+	{name: "SCSS", extensions: []string{".scss"}, comments: cStyleComments},
+	{name: "CSS", extensions: []string{".css"}, comments: cssComments},
+	{name: "Python", extensions: []string{".py"}, interpreters: []string{"python"}, comments: hashComments, functions: regexp.MustCompile(`^\s*(async\s+)?def\s`)},
+	{name: "Ruby", extensions: []string{".rb"}, filenames: []string{"Rakefile", "Gemfile"}, interpreters: []string{"ruby"}, comments: hashComments, functions: regexp.MustCompile(`^\s*def\s`)},
+	{name: "Shell", extensions: []string{".sh", ".bash", ".zsh"}, interpreters: []string{"sh", "bash", "zsh", "dash", "ksh"}, comments: hashComments, functions: shellFunctions},
+	{name: "Perl", extensions: []string{".pl", ".pm"}, interpreters: []string{"perl"}, comments: hashComments, functions: regexp.MustCompile(`^\s*sub\s+\w`)},
+	{name: "R", extensions: []string{".r"}, interpreters: []string{"Rscript"}, comments: hashComments},
+	{name: "PowerShell", extensions: []string{".ps1", ".psm1"}, interpreters: []string{"pwsh"}, comments: hashComments, functions: regexp.MustCompile(`(?i)^\s*function\s`)},
+	{name: "YAML", extensions: []string{".yaml", ".yml"}, comments: hashComments},
+	{name: "TOML", extensions: []string{".toml"}, comments: hashComments},
+	{name: "Makefile", extensions: []string{".mk"}, filenames: []string{"Makefile", "makefile", "GNUmakefile"}, comments: hashComments},
+	{name: "Dockerfile", extensions: []string{".dockerfile"}, filenames: []string{"Dockerfile"}, comments: hashComments},
+	{name: "SQL", extensions: []string{".sql"}, comments: dashComments},
+	{name: "Lua", extensions: []string{".lua"}, interpreters: []string{"lua"}, comments: dashComments, functions: regexp.MustCompile(`\bfunction\b`)},
+	{name: "Haskell", extensions: []string{".hs"}, comments: dashComments},
+	{name: "MATLAB", comments: matlabComments},
+	{name: "HTML", extensions: []string{".html", ".htm"}, comments: markupComments},
+	{name: "XML", extensions: []string{".xml"}, comments: markupComments},
+	{name: "Vue", extensions: []string{".vue"}, comments: markupComments},
+	{name: "Markdown", extensions: []string{".md", ".markdown"}},
+	{name: "JSON", extensions: []string{".json"}},
+}
+
+var (
+	languagesByName        = make(map[string]*language)
+	languagesByExtension   = make(map[string]*language)
+	languagesByFilename    = make(map[string]*language)
+	languagesByInterpreter = make(map[string]*language)
+)
+
+func init() {
+	for i := range languages {
+		lang := &languages[i]
+		languagesByName[lang.name] = lang
+		for _, ext := range lang.extensions {
+			languagesByExtension[ext] = lang
+		}
+		for _, name := range lang.filenames {
+			languagesByFilename[name] = lang
+		}
+		for _, name := range lang.interpreters {
+			languagesByInterpreter[name] = lang
+		}
+	}
+}
+
+var (
+	// cppHeaderPattern は .h ファイルを C++ のヘッダーとみなす記述です
+	cppHeaderPattern = regexp.MustCompile(`\b(class|namespace|template)\b|std::`)
+	// objectiveCPattern は .m ファイルを Objective-C とみなす記述です（それ以外は MATLAB とみなします）
+	objectiveCPattern = regexp.MustCompile(`(?m)^\s*(@interface|@implementation|#import)\b`)
+	// interpreterVersion はインタプリタ名の末尾のバージョン番号です（python3.11 など）
+	interpreterVersion = regexp.MustCompile(`[0-9.]+$`)
+)
+
+// detectLanguage はファイル名（拡張子・特定のファイル名）、シェバン行、内容の特徴からファイルの言語を判定します。
+// content にはファイルの先頭部分のみを渡すこともできます。判定できない場合は nil を返します
+func detectLanguage(relPath string, content []byte) *language {
+	base := path.Base(relPath)
+	ext := strings.ToLower(path.Ext(base))
+	switch ext {
+	case ".h":
+		// C と C++ で共通の拡張子のため、C++ に特有の記述があるかどうかで判定する
+		if cppHeaderPattern.Match(content) {
+			return languagesByName["C++"]
+		}
+		return languagesByName["C"]
+	case ".m":
+		if objectiveCPattern.Match(content) {
+			return languagesByName["Objective-C"]
+		}
+		return languagesByName["MATLAB"]
+	}
+	if lang := languagesByExtension[ext]; lang != nil {
+		return lang
+	}
+	if lang := languagesByFilename[base]; lang != nil {
+		return lang
+	}
+	return languagesByInterpreter[shebangInterpreter(content)]
+}
+
+// shebangInterpreter はシェバン行（#!/usr/bin/env python3 など）からバージョン番号を除いたインタプリタ名を返します。
+// シェバン行がない場合は空文字列を返します
+func shebangInterpreter(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+	line := content[2:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	name := path.Base(fields[0])
+	if name == "env" {
+		// env のオプション（-S など）を読み飛ばす
+		name = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				name = path.Base(f)
+				break
+			}
+		}
+	}
+	return interpreterVersion.ReplaceAllString(name, "")
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		relPath string
+		content string
+		want    string
+	}{
+		{name: "拡張子", relPath: "cmd/main.go", want: "Go"},
+		{name: "大文字の拡張子", relPath: "App.PY", want: "Python"},
+		{name: "ファイル名", relPath: "build/Makefile", want: "Makefile"},
+		{name: "シェバン行", relPath: "bin/run", content: "#!/bin/bash\necho hi\n", want: "Shell"},
+		{name: "envを使用したシェバン行", relPath: "tool", content: "#!/usr/bin/env python3.11\nprint(1)\n", want: "Python"},
+		{name: "envのオプション付きのシェバン行", relPath: "tool", content: "#!/usr/bin/env -S node --no-warnings\n", want: "JavaScript"},
+		{name: "C++のヘッダー", relPath: "a.h", content: "namespace a {\nclass B {};\n}\n", want: "C++"},
+		{name: "Cのヘッダー", relPath: "a.h", content: "int add(int a, int b);\n", want: "C"},
+		{name: "Objective-C", relPath: "a.m", content: "#import <Foundation/Foundation.h>\n@implementation A\n@end\n", want: "Objective-C"},
+		{name: "MATLAB", relPath: "a.m", content: "% comment\nx = 1;\n", want: "MATLAB"},
+		{name: "判定できない", relPath: "notes.txt", content: "hello\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if lang := detectLanguage(tt.relPath, []byte(tt.content)); lang != nil {
+				got = lang.name
+			}
+			if got != tt.want {
+				t.Errorf("detectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_LanguageStats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.go":      {Data: []byte("// a\npackage a\n\nfunc A() {}\n")},
+		"b.go":      {Data: []byte("package b\n")},
+		"run":       {Data: []byte("#!/bin/sh\necho run\n")},
+		"notes.txt": {Data: []byte("ignored\n")},
+		"image.png": {Data: []byte("\x89PNG")},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.go"},
+		{RelPath: "b.go"},
+		{RelPath: "image.png", IsBinary: true},
+		{RelPath: "notes.txt"},
+		{RelPath: "run"},
+	}
+
+	stats := NewGenerator().WithFS(fsys).computeLanguageStats(entries)
+	want := []LanguageStats{
+		{Language: "Go", Files: 2, Blank: 1, Comment: 1, Code: 3},
+		{Language: "Shell", Files: 1, Comment: 1, Code: 1},
+		{Language: LanguageTotal, Files: 3, Blank: 1, Comment: 2, Code: 4},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("computeLanguageStats() = %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Errorf("stats[%d] = %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+
+	var buf strings.Builder
+	generator := NewGeneratorWithOptions(Options{ShowLanguages: true}).WithFS(fsys)
+	if err := generator.WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "===== 言語別の統計 =====") || !strings.Contains(buf.String(), "  Go                      2        1        1        3\n") {
+		t.Errorf("言語別の統計が出力されていない:\n%s", buf.String())
+	}
+}
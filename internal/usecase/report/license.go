@@ -0,0 +1,201 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// LicenseUnknown はライセンスファイルの内容から種類を判定できなかった場合の表示です
+const LicenseUnknown = "不明"
+
+// LicenseFile は検出したライセンスファイルとその種類です
+type LicenseFile struct {
+	// RelPath はライセンスファイルの相対パスです
+	RelPath string `json:"relPath"`
+	// License は SPDX 識別子（MIT, Apache-2.0 など）です。判定できない場合は LicenseUnknown です
+	License string `json:"license"`
+}
+
+// licenseFileNames はライセンスファイルとみなすファイル名（拡張子を除く大文字）です。
+// "LICENSE-MIT" や "COPYING.LESSER" のように、区切り文字に続けて補足が付いたものも含みます
+var licenseFileNames = []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"}
+
+// licenseDocExtensions はライセンスファイルに使われる文書の拡張子です
+var licenseDocExtensions = map[string]bool{"": true, ".txt": true, ".md": true, ".markdown": true, ".rst": true}
+
+// isLicenseFile は relPath のファイル名がライセンスファイルのものかどうかを返します。
+// license.go のようなソースコードは、名前が一致してもライセンスファイルとみなしません
+func isLicenseFile(relPath string) bool {
+	base := path.Base(relPath)
+	ext := strings.ToLower(path.Ext(base))
+	stem := base
+	if licenseDocExtensions[ext] {
+		stem = strings.TrimSuffix(base, path.Ext(base))
+	} else if detectLanguage(relPath, nil) != nil {
+		return false
+	}
+	stem = strings.ToUpper(stem)
+	for _, name := range licenseFileNames {
+		if stem == name {
+			return true
+		}
+		if rest, ok := strings.CutPrefix(stem, name); ok && strings.ContainsRune("-_.", rune(rest[0])) {
+			return true
+		}
+	}
+	return false
+}
+
+// licenseText はよく知られたライセンスを判定するための本文の特徴です。
+// phrases はすべてが含まれている必要があり、excluded が含まれている場合は一致しません
+type licenseText struct {
+	id       string
+	phrases  []string
+	excluded string
+}
+
+// knownLicenses は判定できるライセンスの一覧です。
+// 他のライセンスの名前を本文に含むもの（LGPL は GPL を参照するなど）を先に判定するよう並べています
+var knownLicenses = []licenseText{
+	{id: "AGPL-3.0", phrases: []string{"gnu affero general public license", "version 3"}},
+	{id: "LGPL-3.0", phrases: []string{"gnu lesser general public license", "version 3"}},
+	{id: "LGPL-2.1", phrases: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "LGPL-2.0", phrases: []string{"gnu library general public license"}},
+	{id: "GPL-3.0", phrases: []string{"gnu general public license", "version 3"}},
+	{id: "GPL-2.0", phrases: []string{"gnu general public license", "version 2"}},
+	{id: "Apache-2.0", phrases: []string{"apache license", "version 2.0"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license", "2.0"}},
+	{id: "EPL-2.0", phrases: []string{"eclipse public license - v 2.0"}},
+	{id: "EPL-1.0", phrases: []string{"eclipse public license - v 1.0"}},
+	{id: "BSL-1.0", phrases: []string{"boost software license - version 1.0"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+	{id: "CC0-1.0", phrases: []string{"cc0 1.0 universal"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge, to any person obtaining a copy"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and", "distribute this software for any purpose with or without fee is hereby granted"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}, excluded: "neither the name"},
+}
+
+// spdxIdentifier は SPDX のライセンス識別子の記述（"SPDX-License-Identifier: MIT OR Apache-2.0" など）です
+var spdxIdentifier = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+(?:\s+(?:OR|AND|WITH)\s+[A-Za-z0-9.+-]+)*)`)
+
+// identifyLicense はライセンスファイルの内容から、SPDX 識別子の記述またはよく知られたライセンスの本文を探して種類を返します。
+// 判定できない場合は LicenseUnknown を返します
+func identifyLicense(content []byte) string {
+	if m := spdxIdentifier.FindSubmatch(content); m != nil {
+		return string(m[1])
+	}
+	// 改行位置や大文字・小文字の違いに左右されないよう、空白を 1 つにまとめて小文字で比較する
+	text := strings.ToLower(strings.Join(strings.Fields(string(content)), " "))
+	for _, license := range knownLicenses {
+		if license.excluded != "" && strings.Contains(text, license.excluded) {
+			continue
+		}
+		matched := true
+		for _, phrase := range license.phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return license.id
+		}
+	}
+	return LicenseUnknown
+}
+
+// detectLicenses は entries のうちライセンスファイルの内容を読み込み、相対パスの順に種類を判定した結果を返します。
+// 読み込めないファイルは LicenseUnknown とします
+func (g *Generator) detectLicenses(entries []model.FileSystemEntry) []LicenseFile {
+	var licenses []LicenseFile
+	for _, entry := range entries {
+		if entry.IsDir || entry.IsBinary || !isLicenseFile(entry.RelPath) {
+			continue
+		}
+		license := LicenseFile{RelPath: entry.RelPath, License: LicenseUnknown}
+		if content, err := g.readFile(entry); err == nil && entry.ReadErr == nil {
+			if decoded, err := decodeText(content, entry.Encoding); err == nil {
+				content = decoded
+			}
+			license.License = identifyLicense(content)
+		}
+		licenses = append(licenses, license)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		return licenses[i].RelPath < licenses[j].RelPath
+	})
+	return licenses
+}
+
+// countLicenses はライセンスの種類ごとのファイル数を "MIT (2), Apache-2.0 (1)" の形式で返します。多い順（同数の場合は名前の順）に並べます
+func countLicenses(licenses []LicenseFile) string {
+	counts := make(map[string]int)
+	var ids []string
+	for _, l := range licenses {
+		if counts[l.License] == 0 {
+			ids = append(ids, l.License)
+		}
+		counts[l.License]++
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if counts[ids[i]] != counts[ids[j]] {
+			return counts[ids[i]] > counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%s (%d)", id, counts[id])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeLicenses は検出したライセンスの一覧を出力形式に応じて出力します
+func (g *Generator) writeLicenses(writer io.Writer, licenses []LicenseFile) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "## 検出したライセンス")
+		fmt.Fprintln(writer)
+		if len(licenses) == 0 {
+			fmt.Fprintln(writer, "ライセンスファイルは見つかりませんでした。")
+			fmt.Fprintln(writer)
+			return
+		}
+		fmt.Fprintf(writer, "%s\n\n| ファイル | ライセンス |\n|---|---|\n", escapeMarkdown(countLicenses(licenses)))
+		for _, l := range licenses {
+			fmt.Fprintf(writer, "| %s | %s |\n", escapeMarkdown(l.RelPath), escapeMarkdown(l.License))
+		}
+		fmt.Fprintln(writer)
+	case FormatHTML:
+		fmt.Fprintln(writer, "<h2>検出したライセンス</h2>")
+		if len(licenses) == 0 {
+			fmt.Fprintln(writer, "<p>ライセンスファイルは見つかりませんでした。</p>")
+			return
+		}
+		fmt.Fprintf(writer, "<p>%s</p>\n<table>\n<tr><th>ファイル</th><th>ライセンス</th></tr>\n", html.EscapeString(countLicenses(licenses)))
+		for _, l := range licenses {
+			fmt.Fprintf(writer, "<tr><td>%s</td><td>%s</td></tr>\n", html.EscapeString(l.RelPath), html.EscapeString(l.License))
+		}
+		fmt.Fprintln(writer, "</table>")
+	default:
+		fmt.Fprintln(writer, "===== 検出したライセンス =====")
+		if len(licenses) == 0 {
+			fmt.Fprintln(writer, "  ライセンスファイルは見つかりませんでした")
+			fmt.Fprintln(writer)
+			return
+		}
+		fmt.Fprintf(writer, "  %s\n", countLicenses(licenses))
+		for _, l := range licenses {
+			fmt.Fprintf(writer, "  %s: %s\n", l.RelPath, l.License)
+		}
+		fmt.Fprintln(writer)
+	}
+}
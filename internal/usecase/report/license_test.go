@@ -0,0 +1,95 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestIsLicenseFile(t *testing.T) {
+	tests := []struct {
+		relPath string
+		want    bool
+	}{
+		{relPath: "LICENSE", want: true},
+		{relPath: "vendor/lib/license.md", want: true},
+		{relPath: "LICENSE-APACHE", want: true},
+		{relPath: "LICENSE.MIT", want: true},
+		{relPath: "COPYING.LESSER", want: true},
+		{relPath: "Licence.txt", want: true},
+		{relPath: "UNLICENSE", want: true},
+		{relPath: "internal/license.go", want: false},
+		{relPath: "LICENSES", want: false},
+		{relPath: "README.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			if got := isLicenseFile(tt.relPath); got != tt.want {
+				t.Errorf("isLicenseFile(%q) = %v, want %v", tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyLicense(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "MIT", content: "MIT License\n\nPermission is hereby granted, free of charge, to any\nperson obtaining a copy of this software", want: "MIT"},
+		{name: "Apache", content: "                                 Apache License\n                           Version 2.0, January 2004\n", want: "Apache-2.0"},
+		{name: "GPLv3", content: "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n", want: "GPL-3.0"},
+		{name: "GPLv2", content: "GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991\n", want: "GPL-2.0"},
+		{name: "LGPLはGPLより優先", content: "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\nthe GNU General Public License", want: "LGPL-3.0"},
+		{name: "BSD-3-Clause", content: "Redistribution and use in source and binary forms, with or without\nmodification...\n3. Neither the name of the copyright holder", want: "BSD-3-Clause"},
+		{name: "BSD-2-Clause", content: "Redistribution and use in source and binary forms, with or without\nmodification, are permitted", want: "BSD-2-Clause"},
+		{name: "ISC", content: "Permission to use, copy, modify, and/or distribute this software for any\npurpose with or without fee is hereby granted", want: "ISC"},
+		{name: "SPDX識別子", content: "SPDX-License-Identifier: MIT OR Apache-2.0\n", want: "MIT OR Apache-2.0"},
+		{name: "判定できない", content: "All rights reserved.\n", want: LicenseUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := identifyLicense([]byte(tt.content)); got != tt.want {
+				t.Errorf("identifyLicense() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_Licenses(t *testing.T) {
+	fsys := fstest.MapFS{
+		"LICENSE":                 {Data: []byte("Permission is hereby granted, free of charge, to any person obtaining a copy")},
+		"vendor/a/LICENSE.txt":    {Data: []byte("Permission is hereby granted, free of charge, to any person obtaining a copy")},
+		"vendor/b/COPYING":        {Data: []byte("GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991")},
+		"vendor/c/LICENSE-CUSTOM": {Data: []byte("Internal use only.")},
+		"main.go":                 {Data: []byte("package main\n")},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "LICENSE"},
+		{RelPath: "main.go"},
+		{RelPath: "vendor", IsDir: true},
+		{RelPath: "vendor/a/LICENSE.txt"},
+		{RelPath: "vendor/b/COPYING"},
+		{RelPath: "vendor/c/LICENSE-CUSTOM"},
+	}
+	generator := NewGeneratorWithOptions(Options{ShowLicenses: true}).WithFS(fsys)
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	want := "===== 検出したライセンス =====\n" +
+		"  MIT (2), GPL-2.0 (1), 不明 (1)\n" +
+		"  LICENSE: MIT\n" +
+		"  vendor/a/LICENSE.txt: MIT\n" +
+		"  vendor/b/COPYING: GPL-2.0\n" +
+		"  vendor/c/LICENSE-CUSTOM: 不明\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("ライセンスの一覧が出力されていません:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// ErrNotExport は読み込んだファイルが JSON・JSONL 形式でエクスポートしたレポートではないことを示します
+var ErrNotExport = errors.New("JSON・JSONL 形式で出力したレポートではありません")
+
+// ExportedScan は JSON・JSONL 形式でエクスポートしたレポートから読み込んだスキャン結果です
+type ExportedScan struct {
+	// GeneratedAt はレポートの作成日時です。正規化した出力では nil です
+	GeneratedAt *time.Time    `json:"generatedAt,omitempty"`
+	Stats       ExportStats   `json:"stats"`
+	Entries     []ExportEntry `json:"entries"`
+}
+
+// LoadExport は JSON・JSONL 形式でエクスポートしたレポートを読み込みます。拡張子が .gz の場合は gzip で展開して読み込みます
+func LoadExport(path string) (*ExportedScan, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, apperrors.Wrap("レポートを開けませんでした", path, err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, apperrors.Wrap("gzipの展開に失敗しました", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	scan, err := ReadExport(r)
+	if err != nil {
+		return nil, apperrors.Wrap("レポートの読み込みに失敗しました", path, err)
+	}
+	return scan, nil
+}
+
+// ReadExport は r から JSON 形式のドキュメント、または JSONL 形式（先頭行が統計情報、以降の各行がエントリ）のレポートを読み込みます
+func ReadExport(r io.Reader) (*ExportedScan, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var first struct {
+		Type        string        `json:"type"`
+		GeneratedAt *time.Time    `json:"generatedAt"`
+		Stats       *ExportStats  `json:"stats"`
+		Entries     []ExportEntry `json:"entries"`
+	}
+	if err := dec.Decode(&first); err != nil {
+		if err == io.EOF {
+			return nil, ErrNotExport
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNotExport, err)
+	}
+	if first.Stats == nil {
+		return nil, ErrNotExport
+	}
+	scan := &ExportedScan{GeneratedAt: first.GeneratedAt, Stats: *first.Stats, Entries: first.Entries}
+	if first.Type != "stats" {
+		return scan, nil
+	}
+
+	for line := 2; ; line++ {
+		var e ExportEntry
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%d 行目の読み込みに失敗しました: %w", line, err)
+		}
+		if e.Type == "entry" {
+			scan.Entries = append(scan.Entries, e)
+		}
+	}
+	return scan, nil
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadExport(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatJSONL} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGeneratorWithOptions(Options{Format: format}).WriteReport(&buf, exportTestEntries(t)); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			scan, err := ReadExport(strings.NewReader(buf.String()))
+			if err != nil {
+				t.Fatalf("ReadExport() error = %v", err)
+			}
+			if scan.GeneratedAt == nil || scan.Stats.TotalFiles != 2 || len(scan.Entries) != 3 {
+				t.Fatalf("読み込んだスキャン結果が不正です: %+v", scan)
+			}
+			if e := scan.Entries[1]; e.RelPath != "sub/a.txt" || e.Content == nil || *e.Content != "alpha" {
+				t.Errorf("ファイルの内容が読み込まれていません: %+v", e)
+			}
+			if e := scan.Entries[2]; e.Content != nil || e.Notice == "" {
+				t.Errorf("バイナリファイルの注記が読み込まれていません: %+v", e)
+			}
+		})
+	}
+}
+
+func TestReadExport_NotExport(t *testing.T) {
+	for name, input := range map[string]string{
+		"空のファイル":    "",
+		"JSON ではない": "hello",
+		"統計情報がない":   `{"entries":[]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadExport(strings.NewReader(input)); !errors.Is(err, ErrNotExport) {
+				t.Errorf("ReadExport(%q) error = %v, want ErrNotExport", input, err)
+			}
+		})
+	}
+}
+
+func TestLoadExport_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	if err := NewGeneratorWithOptions(Options{Format: FormatJSON}).WriteReport(gz, exportTestEntries(t)); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	gz.Close()
+	file.Close()
+
+	scan, err := LoadExport(path)
+	if err != nil {
+		t.Fatalf("LoadExport() error = %v", err)
+	}
+	if len(scan.Entries) != 3 {
+		t.Errorf("エントリ数 = %d, want 3", len(scan.Entries))
+	}
+}
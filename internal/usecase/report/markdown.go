@@ -0,0 +1,114 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"strings"
+)
+
+// writeMarkdownStructureStart はフォルダ構成の見出しを出力します。
+// 罫線で描画する場合は、罫線の位置が崩れないよう、リストではなくリンクを含められる pre 要素を開始します
+func (g *Generator) writeMarkdownStructureStart(writer io.Writer) {
+	fmt.Fprintln(writer, "## フォルダ・ファイル構成")
+	fmt.Fprintln(writer)
+	if g.options.TreeStyle == TreeLines {
+		fmt.Fprintln(writer, "<pre>\n.")
+	}
+}
+
+// writeMarkdownStructureEntry はフォルダ構成の 1 項目を、入れ子のリストの項目（罫線で描画する場合は pre 要素の 1 行）として出力します。
+// 各ファイルは内容セクションへのリンクとなり、各項目には内容側から戻るためのアンカーを付与します
+func (g *Generator) writeMarkdownStructureEntry(writer io.Writer, entry StructureEntry, a anchors) {
+	if g.options.TreeStyle == TreeLines {
+		label := html.EscapeString(treeName(entry.FileSystemEntry))
+		if !entry.IsDir {
+			label = fmt.Sprintf(`<a href="#%s">%s</a>`, a.file(entry.RelPath), label)
+		}
+		fmt.Fprintf(writer, "%s%s<a id=\"%s\"></a>%s%s\n", html.EscapeString(entry.Listing), entry.Prefix, a.tree(entry.RelPath), label, html.EscapeString(entry.Annotation))
+		return
+	}
+
+	anchor := fmt.Sprintf(`<a id="%s"></a>`, a.tree(entry.RelPath))
+	var label string
+	if entry.Listing != "" {
+		label = fmt.Sprintf("`%s` ", strings.TrimSpace(entry.Listing))
+	}
+	if entry.IsDir {
+		label += fmt.Sprintf("📁 %s/", escapeMarkdown(entry.RelPath))
+	} else {
+		label += fmt.Sprintf("[%s](#%s)", escapeMarkdown(entry.RelPath), a.file(entry.RelPath))
+	}
+	label += entry.Annotation
+	fmt.Fprintf(writer, "%s- %s%s\n", entry.Prefix, anchor, label)
+}
+
+// writeMarkdownStructureEnd は罫線で描画する場合に、フォルダ構成の pre 要素を閉じます
+func (g *Generator) writeMarkdownStructureEnd(writer io.Writer) {
+	if g.options.TreeStyle == TreeLines {
+		fmt.Fprintln(writer, "</pre>")
+	}
+}
+
+// writeMarkdownSection は 1 ファイル分の内容をコードブロックとして出力します。
+// 見出しには構成内の位置へ戻るリンクを付与します
+func writeMarkdownSection(writer io.Writer, c FileContent, a anchors) {
+	fmt.Fprintf(writer, "\n### <a id=\"%s\"></a>%s\n\n", a.file(c.RelPath), escapeMarkdown(c.RelPath))
+	if c.InStructure {
+		fmt.Fprintf(writer, "[↑ 構成に戻る](#%s)\n\n", a.tree(c.RelPath))
+	}
+	if c.Authors != "" {
+		fmt.Fprintf(writer, "作成者: %s\n\n", escapeMarkdown(c.Authors))
+	}
+	if c.Metrics != nil {
+		fmt.Fprintf(writer, "指標: %s\n\n", c.Metrics)
+	}
+
+	if c.Notice != "" {
+		fmt.Fprintln(writer, c.Notice)
+		return
+	}
+
+	content := c.Content
+	fence := codeFence(string(content))
+	fmt.Fprintf(writer, "%s%s\n", fence, c.Language)
+	fmt.Fprint(writer, string(content))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		fmt.Fprintln(writer)
+	}
+	fmt.Fprintln(writer, fence)
+}
+
+// codeFence は内容に含まれるバッククォートの連続より長いフェンスを返します
+func codeFence(content string) string {
+	longest, current := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			current++
+			if current > longest {
+				longest = current
+			}
+			continue
+		}
+		current = 0
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// languageHint はファイルの拡張子からコードブロックの言語指定を推定します
+func languageHint(relPath string) string {
+	return strings.TrimPrefix(strings.ToLower(path.Ext(relPath)), ".")
+}
+
+// escapeMarkdown はパス中の Markdown として解釈される記号をエスケープします
+func escapeMarkdown(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+		"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+	)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaskRule は利用者が定義する、ファイル内容のマスクのルールです
+type MaskRule struct {
+	// Name はルールの名前です。マスクしたファイルの一覧に表示します。Preset を指定した場合は省略できます
+	Name string `json:"name,omitempty"`
+	// Pattern は置き換える部分の正規表現（Go の regexp 構文）です
+	Pattern string `json:"pattern,omitempty"`
+	// Replacement は置き換える文字列です。"$1" や "${name}" で一致した部分を参照できます（"$" そのものは "$$"）。
+	// 省略した場合は "[MASKED:ルール名]" です
+	Replacement string `json:"replacement,omitempty"`
+	// Preset は組み込みのルールの名前です（"email" など）。指定した場合、Pattern を省略すると組み込みのルールの正規表現を使用します
+	Preset string `json:"preset,omitempty"`
+}
+
+// MaskRuleFile はマスクのルールを定義するファイルの内容です
+type MaskRuleFile struct {
+	Rules []MaskRule `json:"rules"`
+}
+
+// PresetMaskRules は名前で指定できる組み込みのマスクのルールです
+var PresetMaskRules = map[string]MaskRule{
+	"email": {Name: "email", Pattern: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`},
+	// 国内の電話番号（03-1234-5678, 090-1234-5678）と国際表記（+81 90 1234 5678）。区切りのない数字の並びは対象外です
+	"phone": {Name: "phone", Pattern: `(?:\+\d{1,3}[\s-]?)?\(?\b0?\d{1,4}\)?[\s-]\d{1,4}[\s-]\d{3,4}\b`},
+	"ipv4":  {Name: "ipv4", Pattern: `\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`},
+}
+
+// PresetMaskRuleNames は組み込みのマスクのルールの名前を名前の順で返します
+func PresetMaskRuleNames() []string {
+	names := make([]string, 0, len(PresetMaskRules))
+	for name := range PresetMaskRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MaskRules はコンパイル済みのマスクのルールです
+type MaskRules []redactionRule
+
+// CompileMaskRules はマスクのルールを検証し、ファイル内容に適用できる形式に変換します。
+// ルールは指定された順に適用します
+func CompileMaskRules(rules []MaskRule) (MaskRules, error) {
+	compiled := make(MaskRules, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Preset != "" {
+			preset, ok := PresetMaskRules[strings.ToLower(strings.TrimSpace(rule.Preset))]
+			if !ok {
+				return nil, fmt.Errorf("未対応の組み込みルールです: %s（%s のいずれかを指定してください）", rule.Preset, strings.Join(PresetMaskRuleNames(), ", "))
+			}
+			if rule.Name == "" {
+				rule.Name = preset.Name
+			}
+			if rule.Pattern == "" {
+				rule.Pattern = preset.Pattern
+			}
+		}
+		if rule.Name == "" || rule.Pattern == "" {
+			return nil, fmt.Errorf("マスクのルール %d: name と pattern（または preset）を指定してください", i+1)
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("マスクのルール '%s' の正規表現が不正です: %w", rule.Name, err)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = fmt.Sprintf("[MASKED:%s]", rule.Name)
+		}
+		compiled = append(compiled, redactionRule{name: rule.Name, pattern: pattern, replacement: replacement})
+	}
+	return compiled, nil
+}
+
+// LoadMaskRuleFile はマスクのルールを定義した JSON ファイル（{"rules": [...]}）を読み込みます
+func LoadMaskRuleFile(path string) ([]MaskRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("マスクのルールファイルの読み込みに失敗しました: %w", err)
+	}
+	var file MaskRuleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("マスクのルールファイルの解析に失敗しました: %w", err)
+	}
+	return file.Rules, nil
+}
+
+// WithMaskRules は、出力するファイルの内容のうち rules に一致した部分を置き換える Generator のコピーを返します。
+// rules は秘密情報のマスク（Options.DisableRedaction）の有効・無効にかかわらず適用し、マスクしたファイルの一覧に含めます
+func (g *Generator) WithMaskRules(rules MaskRules) *Generator {
+	copied := *g
+	copied.maskRules = rules
+	return &copied
+}
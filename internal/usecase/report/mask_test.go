@@ -0,0 +1,81 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestCompileMaskRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []MaskRule
+		wantErr string
+	}{
+		{name: "組み込みのルール", rules: []MaskRule{{Preset: "Email"}, {Preset: "phone", Replacement: "<tel>"}}},
+		{name: "利用者定義のルール", rules: []MaskRule{{Name: "host", Pattern: `\w+\.corp`}}},
+		{name: "未対応の組み込みルール", rules: []MaskRule{{Preset: "address"}}, wantErr: "未対応の組み込みルールです"},
+		{name: "正規表現がない", rules: []MaskRule{{Name: "host"}}, wantErr: "マスクのルール 1"},
+		{name: "不正な正規表現", rules: []MaskRule{{Name: "host", Pattern: `(`}}, wantErr: "正規表現が不正です"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := CompileMaskRules(tt.rules)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CompileMaskRules() error = %v, want %q を含むエラー", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompileMaskRules() error = %v", err)
+			}
+			if len(rules) != len(tt.rules) {
+				t.Errorf("ルール数 = %d, want %d", len(rules), len(tt.rules))
+			}
+		})
+	}
+}
+
+func TestGenerator_WithMaskRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	ruleFile := `{"rules": [
+		{"preset": "email"},
+		{"preset": "phone", "replacement": "<tel>"},
+		{"name": "employee-id", "pattern": "EMP-(\\d{2})\\d{4}", "replacement": "EMP-${1}****"}
+	]}`
+	if err := os.WriteFile(path, []byte(ruleFile), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	loaded, err := LoadMaskRuleFile(path)
+	if err != nil {
+		t.Fatalf("LoadMaskRuleFile() error = %v", err)
+	}
+	rules, err := CompileMaskRules(loaded)
+	if err != nil {
+		t.Fatalf("CompileMaskRules() error = %v", err)
+	}
+
+	fsys := fstest.MapFS{"team.md": {Data: []byte("担当: taro@example.co.jp（03-1234-5678）EMP-123456\n日付: 2024-01-15\n")}}
+	entries := []model.FileSystemEntry{{RelPath: "team.md"}}
+	// 秘密情報のマスクを無効にしても、利用者定義のルールは適用する
+	generator := NewGeneratorWithOptions(Options{DisableRedaction: true}).WithFS(fsys).WithMaskRules(rules)
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"担当: [MASKED:email]（<tel>）EMP-12****\n日付: 2024-01-15\n",
+		"team.md: email (1), employee-id (1), phone (1)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("出力に %q が含まれていません:\n%s", want, buf.String())
+		}
+	}
+}
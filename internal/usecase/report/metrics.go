@@ -0,0 +1,113 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FileMetrics はファイルの規模の目安となる指標です。
+// 構文解析は行わず、行単位の簡易的な判定で算出します
+type FileMetrics struct {
+	// Lines は総行数です
+	Lines int `json:"lines"`
+	// Code はコード行（空行・コメント行以外）の数です
+	Code int `json:"code"`
+	// Comment はコメントのみの行の数です
+	Comment int `json:"comment"`
+	// Blank は空行の数です
+	Blank int `json:"blank"`
+	// Functions は関数定義とみなした行の数です。言語が不明な場合は -1 です
+	Functions int `json:"functions"`
+	// commentSyntax はコメントの記法が分かる言語であるかどうかを示します
+	commentSyntax bool
+}
+
+// computeMetrics はファイルの内容から指標を算出します。言語はファイル名と内容から判定します
+func computeMetrics(relPath string, content []byte) FileMetrics {
+	return measure(detectLanguage(relPath, content), content)
+}
+
+// measure は lang の記法に従って内容の各行を分類し、指標を算出します。lang が nil の場合は空行のみを区別します
+func measure(lang *language, content []byte) FileMetrics {
+	var syntax commentSyntax
+	var funcPattern *regexp.Regexp
+	hasSyntax := false
+	if lang != nil {
+		funcPattern = lang.functions
+		if lang.comments != nil {
+			syntax, hasSyntax = *lang.comments, true
+		}
+	}
+
+	m := FileMetrics{Functions: -1, commentSyntax: hasSyntax}
+	if funcPattern != nil {
+		m.Functions = 0
+	}
+	text := strings.TrimSuffix(string(content), "\n")
+	if text == "" {
+		return m
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(text, "\n") {
+		m.Lines++
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			m.Comment++
+			if strings.Contains(trimmed, syntax.blockEnd) {
+				inBlock = false
+			}
+			continue
+		case trimmed == "":
+			m.Blank++
+			continue
+		case hasSyntax && isLineComment(trimmed, syntax):
+			m.Comment++
+			continue
+		case hasSyntax && syntax.blockStart != "" && strings.HasPrefix(trimmed, syntax.blockStart):
+			m.Comment++
+			inBlock = !strings.Contains(trimmed[len(syntax.blockStart):], syntax.blockEnd)
+			continue
+		}
+		m.Code++
+		if funcPattern != nil && funcPattern.MatchString(line) {
+			m.Functions++
+		}
+	}
+	return m
+}
+
+// isLineComment は行が 1 行コメントの記号で始まるかどうかを返します
+func isLineComment(trimmed string, syntax commentSyntax) bool {
+	for _, prefix := range syntax.line {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommentRatio はコード行とコメント行の合計に対するコメント行の割合（0〜1）を返します
+func (m FileMetrics) CommentRatio() float64 {
+	if m.Code+m.Comment == 0 {
+		return 0
+	}
+	return float64(m.Comment) / float64(m.Code+m.Comment)
+}
+
+// String は指標を "120 行（コード 90、コメント 20、空行 10）、コメント率 18.2%、関数 約 5 個" の形式で返します。
+// コメントの記法や関数の定義方法が分からない言語では、該当する項目を省略します
+func (m FileMetrics) String() string {
+	var b strings.Builder
+	if m.commentSyntax {
+		fmt.Fprintf(&b, "%d 行（コード %d、コメント %d、空行 %d）、コメント率 %.1f%%", m.Lines, m.Code, m.Comment, m.Blank, m.CommentRatio()*100)
+	} else {
+		fmt.Fprintf(&b, "%d 行（空行 %d）", m.Lines, m.Blank)
+	}
+	if m.Functions >= 0 {
+		fmt.Fprintf(&b, "、関数 約 %d 個", m.Functions)
+	}
+	return b.String()
+}
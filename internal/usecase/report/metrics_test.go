@@ -0,0 +1,104 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		relPath string
+		content string
+		want    FileMetrics
+	}{
+		{
+			name:    "Go",
+			relPath: "main.go",
+			content: "// Package main\npackage main\n\n/*\n複数行コメント\n*/\nfunc main() {}\n\nfunc (s *S) m() {}\n",
+			want:    FileMetrics{Lines: 9, Code: 3, Comment: 4, Blank: 2, Functions: 2, commentSyntax: true},
+		},
+		{
+			name:    "Python",
+			relPath: "app.py",
+			content: "# comment\ndef a():\n    pass\n\nasync def b():\n    pass\n",
+			want:    FileMetrics{Lines: 6, Code: 4, Comment: 1, Blank: 1, Functions: 2, commentSyntax: true},
+		},
+		{
+			name:    "1行のブロックコメント",
+			relPath: "style.css",
+			content: "/* header */\nbody { margin: 0; }\n",
+			want:    FileMetrics{Lines: 2, Code: 1, Comment: 1, Functions: -1, commentSyntax: true},
+		},
+		{
+			name:    "言語が不明なファイル",
+			relPath: "notes.txt",
+			content: "# 見出し\n\n本文\n",
+			want:    FileMetrics{Lines: 3, Code: 2, Blank: 1, Functions: -1},
+		},
+		{
+			name:    "空のファイル",
+			relPath: "empty.go",
+			content: "",
+			want:    FileMetrics{Functions: 0, commentSyntax: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeMetrics(tt.relPath, []byte(tt.content)); got != tt.want {
+				t.Errorf("computeMetrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileMetrics_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics FileMetrics
+		want    string
+	}{
+		{
+			name:    "すべての項目",
+			metrics: FileMetrics{Lines: 120, Code: 90, Comment: 10, Blank: 20, Functions: 5, commentSyntax: true},
+			want:    "120 行（コード 90、コメント 10、空行 20）、コメント率 10.0%、関数 約 5 個",
+		},
+		{
+			name:    "コメントの記法と関数の定義方法が不明",
+			metrics: FileMetrics{Lines: 3, Code: 2, Blank: 1, Functions: -1},
+			want:    "3 行（空行 1）",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metrics.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteFileContentsWithMetrics(t *testing.T) {
+	fsys := fstest.MapFS{"a.go": {Data: []byte("package a\n\n// A は例です\nfunc A() {}\n")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.go"}, {RelPath: "b.bin", IsBinary: true}}
+
+	for _, format := range []Format{FormatText, FormatMarkdown, FormatHTML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf strings.Builder
+			generator := NewGeneratorWithOptions(Options{Format: format, ShowMetrics: true, LineNumbers: true}).WithFS(fsys)
+			if err := generator.WriteFileContents(&buf, entries); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			// 行番号を付ける前の内容から算出し、バイナリファイルには表示しない
+			want := "指標: 4 行（コード 2、コメント 1、空行 1）、コメント率 33.3%、関数 約 1 個"
+			if got := strings.Count(buf.String(), want); got != 1 {
+				t.Errorf("指標の表示回数 = %d, want 1\n%s", got, buf.String())
+			}
+		})
+	}
+}
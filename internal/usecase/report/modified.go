@@ -0,0 +1,139 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+
+	"FolderScope/internal/domain/model"
+)
+
+// maxStableReadAttempts は、読み込み中にファイルが変更された場合に読み直す最大の回数です
+const maxStableReadAttempts = 3
+
+// errUnstableFile は、読み直しても読み込み中にファイルが変更され続けたことを示します
+var errUnstableFile = errors.New("読み込み中にファイルが変更され続けたため、内容を確定できませんでした")
+
+// ModifiedFile はスキャンの後、内容を出力するまでの間に変更されたファイルです
+type ModifiedFile struct {
+	RelPath string
+	// ScannedSize と CurrentSize は、スキャン時と内容の読み込み時のファイルサイズです
+	ScannedSize, CurrentSize int64
+}
+
+// modifiedLog は 1 回のレポート出力で、スキャン後に変更されていたファイルをファイルの出力順に記録します
+type modifiedLog struct {
+	files  []ModifiedFile
+	byPath map[string]bool
+}
+
+// record は relPath のファイルがスキャン後に変更されていたことを記録します
+func (l *modifiedLog) record(f ModifiedFile) {
+	if l == nil || l.byPath[f.RelPath] {
+		return
+	}
+	l.byPath[f.RelPath] = true
+	l.files = append(l.files, f)
+}
+
+// readStableFile はエントリのファイル内容を読み込みます。
+// 読み込みの前後でファイルのサイズ・更新日時が変わった場合は、書き込み途中の内容を出力しないよう maxStableReadAttempts 回まで読み直し、
+// それでも変わり続ける場合は errUnstableFile を返します。
+// 読み込んだ内容がスキャン時（エントリのサイズ・更新日時）から変更されていた場合は、レポートの末尾に一覧を出力するために記録します
+func (g *Generator) readStableFile(entry model.FileSystemEntry) ([]byte, error) {
+	before, err := g.statFile(entry)
+	if err != nil {
+		// 情報を取得できない場合は変更を検出せずに読み込む
+		return g.readFile(entry)
+	}
+	for attempt := 1; ; attempt++ {
+		content, err := g.readFile(entry)
+		if err != nil {
+			return nil, err
+		}
+		after, err := g.statFile(entry)
+		if err != nil {
+			return nil, err
+		}
+		if sameFileState(before, after) {
+			g.recordModified(entry, after)
+			return content, nil
+		}
+		if attempt == maxStableReadAttempts {
+			return nil, errUnstableFile
+		}
+		before = after
+	}
+}
+
+// sameFileState は 2 回取得したファイル情報のサイズと更新日時が同じかどうかを返します
+func sameFileState(a, b fs.FileInfo) bool {
+	return a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+// recordModified は、読み込み時のファイル情報 info がスキャン時のエントリのサイズ・更新日時と異なる場合に記録します。
+// 更新日時を持たないエントリ（テストなどで作成したエントリ）と、シンボリックリンクのエントリは比較しません。
+// リンクのエントリはシンボリックリンクの扱いに応じてリンク自体またはリンク先の情報を記録しており、リンク先の情報を取得する info とは比較できないためです
+func (g *Generator) recordModified(entry model.FileSystemEntry, info fs.FileInfo) {
+	if entry.ModTime.IsZero() || entry.LinkTarget != "" || (info.Size() == entry.Size && info.ModTime().Equal(entry.ModTime)) {
+		return
+	}
+	g.modified.record(ModifiedFile{RelPath: entry.RelPath, ScannedSize: entry.Size, CurrentSize: info.Size()})
+}
+
+// modifiedFiles は、この出力でスキャン後に変更されていたファイルを出力順に返します
+func (g *Generator) modifiedFiles() []ModifiedFile {
+	if g.modified == nil {
+		return nil
+	}
+	return g.modified.files
+}
+
+// withOutputLog は、1 回のレポート出力でマスクした情報と、スキャン後に変更されていたファイルを記録する Generator のコピーを返します。
+// 同じ Generator を複数の出力で同時に使用しても記録が混ざらないよう、出力ごとに呼び出します
+func (g *Generator) withOutputLog() *Generator {
+	copied := *g
+	copied.redactions = &redactionLog{byPath: make(map[string][]Redaction)}
+	copied.modified = &modifiedLog{byPath: make(map[string]bool)}
+	copied.interrupted = false
+	return &copied
+}
+
+// writeModifiedSummary は、スキャンの後に変更されていたため、スキャン時とは異なる内容を出力したファイルの一覧を出力形式に応じて出力します。
+// 該当するファイルがない場合は何も出力しません
+func (g *Generator) writeModifiedSummary(writer io.Writer, files []ModifiedFile) {
+	if len(files) == 0 {
+		return
+	}
+	const note = "以下のファイルはスキャンの後に変更されたため、構成のサイズ・更新日時はスキャン時、内容は読み込み時のものです。"
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "\n## スキャン中に変更されたファイル")
+		fmt.Fprintf(writer, "\n%s\n\n", note)
+		for _, f := range files {
+			fmt.Fprintf(writer, "- %s: %s\n", escapeMarkdown(f.RelPath), f.sizeChange())
+		}
+	case FormatHTML:
+		fmt.Fprintf(writer, "<h2>スキャン中に変更されたファイル</h2>\n<p>%s</p>\n<ul>\n", note)
+		for _, f := range files {
+			fmt.Fprintf(writer, "<li>%s: %s</li>\n", html.EscapeString(f.RelPath), html.EscapeString(f.sizeChange()))
+		}
+		fmt.Fprintln(writer, "</ul>")
+	default:
+		fmt.Fprintln(writer, "\n===== スキャン中に変更されたファイル =====")
+		fmt.Fprintln(writer, note)
+		for _, f := range files {
+			fmt.Fprintf(writer, "%s: %s\n", f.RelPath, f.sizeChange())
+		}
+	}
+}
+
+// sizeChange はスキャン時と読み込み時のサイズを "1.0 KB → 1.2 KB" の形式で返します。サイズが同じ場合は更新日時のみが変わった旨を返します
+func (f ModifiedFile) sizeChange() string {
+	if f.ScannedSize == f.CurrentSize {
+		return fmt.Sprintf("更新日時のみ変更（%s）", FormatSize(f.CurrentSize))
+	}
+	return fmt.Sprintf("%s → %s", FormatSize(f.ScannedSize), FormatSize(f.CurrentSize))
+}
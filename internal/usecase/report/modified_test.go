@@ -0,0 +1,111 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_ModifiedAfterScan(t *testing.T) {
+	scanned := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"grown.txt":     {Data: []byte("hello, world"), ModTime: scanned.Add(time.Minute)},
+		"touched.txt":   {Data: []byte("same"), ModTime: scanned.Add(time.Minute)},
+		"unchanged.txt": {Data: []byte("keep"), ModTime: scanned},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "grown.txt", Size: 5, ModTime: scanned},
+		{RelPath: "touched.txt", Size: 4, ModTime: scanned},
+		{RelPath: "unchanged.txt", Size: 4, ModTime: scanned},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		want   []string
+	}{
+		{
+			name:   "テキスト形式",
+			format: FormatText,
+			want:   []string{"===== スキャン中に変更されたファイル =====", "grown.txt: 5 B → 12 B", "touched.txt: 更新日時のみ変更（4 B）", "hello, world"},
+		},
+		{
+			name:   "Markdown 形式",
+			format: FormatMarkdown,
+			want:   []string{"## スキャン中に変更されたファイル", "- grown.txt: 5 B → 12 B"},
+		},
+		{
+			name:   "HTML 形式",
+			format: FormatHTML,
+			want:   []string{"<h2>スキャン中に変更されたファイル</h2>", "<li>touched.txt: 更新日時のみ変更（4 B）</li>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := NewGeneratorWithOptions(Options{Format: tt.format}).WithFS(fsys).WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			output := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+				}
+			}
+			if strings.Contains(output, "unchanged.txt: ") {
+				t.Errorf("変更されていないファイルが一覧に含まれています:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteReport_NoModifiedSummary(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	// 更新日時を持たないエントリは比較しない
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 10}}
+
+	var buf strings.Builder
+	if err := NewGeneratorWithOptions(Options{}).WithFS(fsys).WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "スキャン中に変更されたファイル") {
+		t.Errorf("変更されたファイルの一覧が出力されています:\n%s", buf.String())
+	}
+}
+
+func TestGenerator_WriteReport_SymlinkNotModified(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("シンボリックリンクの作成に権限が必要なため Windows ではスキップします")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "target.txt"), []byte("target"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	link := filepath.Join(dir, "linkfile")
+	if err := os.Symlink("target.txt", link); err != nil {
+		t.Fatalf("シンボリックリンクの作成に失敗: %v", err)
+	}
+	// -symlinks link のスキャンと同じく、リンク自体の情報（lstat）を記録したエントリ
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatalf("ファイル情報の取得に失敗: %v", err)
+	}
+	entries := []model.FileSystemEntry{{Path: link, RelPath: "linkfile", Size: info.Size(), ModTime: info.ModTime(), LinkTarget: "target.txt"}}
+
+	var buf strings.Builder
+	if err := NewGeneratorWithOptions(Options{}).WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "スキャン中に変更されたファイル") {
+		t.Errorf("シンボリックリンクが変更されたファイルとして出力されています:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "target") {
+		t.Errorf("リンク先の内容が出力されていません:\n%s", buf.String())
+	}
+}
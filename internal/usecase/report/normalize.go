@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+)
+
+// NormalizedReportName は正規化した出力（Options.Normalize）のファイル名（拡張子を除く）です。
+// 出力のたびに同じファイルを上書きするため、リポジトリにコミットして git diff で変更を確認できます
+const NormalizedReportName = "folderscope"
+
+// normalized は正規化した出力のための Generator のコピーと、更新日時を取り除いたエントリを返します。
+// 実行のたびに変わる情報（作成日時・スキャンの所要時間・更新日時）を含めず、ファイル内容は相対パスの順に並べます
+func (g *Generator) normalized(entries []model.FileSystemEntry) (*Generator, []model.FileSystemEntry) {
+	copied := *g
+	copied.scanStats = nil
+	copied.options.ContentOrder = OrderPath
+	normalized := make([]model.FileSystemEntry, len(entries))
+	for i, entry := range entries {
+		entry.ModTime = time.Time{}
+		normalized[i] = entry
+	}
+	return &copied, normalized
+}
+
+// generatedAt はレポートの作成日時を返します。正規化した出力ではゼロ値を返します
+func (g *Generator) generatedAt() time.Time {
+	if g.options.Normalize {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// normalizeLineEndings はファイル内容の改行を LF にそろえます
+func normalizeLineEndings(content []byte) []byte {
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(content, []byte("\r"), []byte("\n"))
+}
+
+// CreateNormalizedOutput は正規化した出力のファイル（outputDir/folderscope.<拡張子>）を作成し、すでに存在する場合は上書きします
+func (g *Generator) CreateNormalizedOutput(outputDir string) (*OutputWriter, string, error) {
+	outputPath := filepath.Join(outputDir, NormalizedReportName+g.options.Format.Extension())
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return nil, "", apperrors.Wrap("出力ファイルの作成に失敗しました", outputPath, err)
+	}
+	w, err := NewOutputWriter(file, CompressionNone, filepath.Base(outputPath))
+	if err != nil {
+		file.Close()
+		return nil, "", fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+	}
+	return w, outputPath, nil
+}
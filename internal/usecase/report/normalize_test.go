@@ -0,0 +1,94 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_Normalize(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"b.txt": "line1\r\nline2\r\n", "a.txt": "alpha\n"}
+	var entries []model.FileSystemEntry
+	for _, name := range []string{"b.txt", "a.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, model.FileSystemEntry{Path: path, RelPath: name, Size: int64(len(files[name])), ModTime: time.Now()})
+	}
+
+	tests := []struct {
+		name      string
+		format    Format
+		want      []string
+		unwanted  []string
+		wantOrder []string
+	}{
+		{
+			name:      "テキスト形式",
+			format:    FormatText,
+			want:      []string{"line1\nline2\n"},
+			unwanted:  []string{"\r", time.Now().Format("2006-01-02")},
+			wantOrder: []string{"----- a.txt", "----- b.txt"},
+		},
+		{
+			name:      "JSON 形式",
+			format:    FormatJSON,
+			want:      []string{`"content":"line1\nline2\n"`},
+			unwanted:  []string{`\r`, "generatedAt", "modTime\":\"2", "startedAt", dir},
+			wantOrder: []string{`"relPath":"a.txt","isDir"`, `"relPath":"b.txt","isDir"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewGeneratorWithOptions(Options{Format: tt.format, ShowMetadata: true, ContentOrder: OrderGitRecent, Normalize: true}).
+				WithScanStats(model.ScanStats{StartedAt: time.Now(), DurationMillis: 42})
+			var buf strings.Builder
+			if err := generator.WriteReport(&buf, []model.FileSystemEntry{entries[1], entries[0]}); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			output := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("出力に %q が含まれています:\n%s", unwanted, output)
+				}
+			}
+			if first, second := strings.Index(output, tt.wantOrder[0]), strings.Index(output, tt.wantOrder[1]); first < 0 || second < first {
+				t.Errorf("ファイルが相対パスの順に並んでいません:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestGenerator_CreateNormalizedOutput(t *testing.T) {
+	dir := t.TempDir()
+	generator := NewGeneratorWithOptions(Options{Format: FormatMarkdown, Normalize: true})
+	for _, content := range []string{"first report", "second"} {
+		w, path, err := generator.CreateNormalizedOutput(dir)
+		if err != nil {
+			t.Fatalf("CreateNormalizedOutput() error = %v", err)
+		}
+		if filepath.Base(path) != "folderscope.md" {
+			t.Errorf("出力ファイル名 = %s, want folderscope.md", filepath.Base(path))
+		}
+		w.Write([]byte(content))
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+	// 2 回目の出力で上書きされる
+	data, err := os.ReadFile(filepath.Join(dir, "folderscope.md"))
+	if err != nil || string(data) != "second" {
+		t.Errorf("出力ファイルの内容 = %q, %v, want %q", data, err, "second")
+	}
+}
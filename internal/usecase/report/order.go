@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// ContentOrder はファイル内容セクションの並び順です
+type ContentOrder string
+
+const (
+	// OrderPath はスキャン結果の順（相対パスの順）に並べます
+	OrderPath ContentOrder = "path"
+	// OrderGitRecent は git で最後にコミットされた日時の新しい順に並べます。
+	// トークン数に上限のある用途で、最近変更されたファイルを先頭に置くために使用します
+	OrderGitRecent ContentOrder = "git-recent"
+)
+
+// ParseContentOrder は文字列から並び順を解決します。空文字列は相対パスの順として扱います
+func ParseContentOrder(s string) (ContentOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "path":
+		return OrderPath, nil
+	case "git-recent", "recent":
+		return OrderGitRecent, nil
+	}
+	return "", fmt.Errorf("未対応の並び順です: %s（path, git-recent のいずれかを指定してください）", s)
+}
+
+// WithCommitTimes は OrderGitRecent で並べる際に使用する、相対パスごとの最終コミット日時を設定した Generator のコピーを返します
+func (g *Generator) WithCommitTimes(times map[string]time.Time) *Generator {
+	copied := *g
+	copied.commitTimes = times
+	return &copied
+}
+
+// sortContents はファイル内容セクションの並び順に従って files を並べ替えます。
+// OrderGitRecent では、コミットされていないファイルを最も新しいものとして先頭に置き、
+// 続けて最終コミット日時の新しい順に並べます。同じ日時のファイルは相対パスの順を保ちます
+func (g *Generator) sortContents(files []model.FileSystemEntry) {
+	if g.options.ContentOrder != OrderGitRecent {
+		return
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		ti, iok := g.commitTimes[files[i].RelPath]
+		tj, jok := g.commitTimes[files[j].RelPath]
+		if iok != jok {
+			return !iok
+		}
+		return ti.After(tj)
+	})
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_ContentOrderGitRecent(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []model.FileSystemEntry{
+		{RelPath: "a.txt", IsBinary: true},
+		{RelPath: "b.txt", IsBinary: true},
+		{RelPath: "c.txt", IsBinary: true},
+		{RelPath: "untracked.txt", IsBinary: true},
+	}
+	times := map[string]time.Time{
+		"a.txt": base,
+		"b.txt": base.Add(48 * time.Hour),
+		"c.txt": base.Add(24 * time.Hour),
+	}
+
+	tests := []struct {
+		name  string
+		order ContentOrder
+		want  []string
+	}{
+		{"相対パスの順", OrderPath, []string{"a.txt", "b.txt", "c.txt", "untracked.txt"}},
+		{"最終コミット日時の新しい順", OrderGitRecent, []string{"untracked.txt", "b.txt", "c.txt", "a.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			generator := NewGeneratorWithOptions(Options{ContentOrder: tt.order}).WithCommitTimes(times)
+			if err := generator.WriteFileContents(&buf, entries); err != nil {
+				t.Fatalf("WriteFileContents() error = %v", err)
+			}
+			var got []string
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if strings.HasPrefix(line, "----- ") {
+					got = append(got, strings.TrimSuffix(strings.TrimPrefix(line, "----- "), " -----"))
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("並び順 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseContentOrder(t *testing.T) {
+	if got, err := ParseContentOrder(""); err != nil || got != OrderPath {
+		t.Errorf("ParseContentOrder(\"\") = %v, %v", got, err)
+	}
+	if got, err := ParseContentOrder("Git-Recent"); err != nil || got != OrderGitRecent {
+		t.Errorf("ParseContentOrder(\"Git-Recent\") = %v, %v", got, err)
+	}
+	if _, err := ParseContentOrder("size"); err == nil {
+		t.Error("未対応の並び順でエラーを返すべきです")
+	}
+}
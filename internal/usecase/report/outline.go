@@ -0,0 +1,365 @@
+package report
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// outlineLine はアウトラインに含める 1 つの宣言です
+type outlineLine struct {
+	// line は宣言の開始行（1 から数える）です
+	line int
+	// text は宣言のシグネチャです。入れ子の宣言は元の字下げを保ちます
+	text string
+}
+
+// outliner は言語ごとに内容から宣言を抽出する関数です
+type outliner func(content []byte) []outlineLine
+
+// outliners は拡張子ごとの宣言の抽出方法です。
+// Go は go/parser で構文解析し、それ以外の言語は構文解析器を使わず、コメント・文字列リテラル・波括弧の対応のみを追う行単位の判定で抽出します
+var outliners = map[string]outliner{
+	".go":   goOutline,
+	".py":   pythonOutline,
+	".ts":   typeScriptOutline,
+	".tsx":  typeScriptOutline,
+	".java": javaOutline,
+}
+
+// outline はファイルの内容から関数・クラス・型などの宣言のみを抽出し、行番号を付けて返します。
+// 宣言を抽出できない言語の場合や、宣言が見つからない場合は false を返します
+func outline(relPath string, content []byte) ([]byte, bool) {
+	extract, ok := outliners[strings.ToLower(path.Ext(relPath))]
+	if !ok {
+		return nil, false
+	}
+	decls := extract(content)
+	if len(decls) == 0 {
+		return nil, false
+	}
+	width := len(strconv.Itoa(strings.Count(string(content), "\n") + 1))
+	var b strings.Builder
+	for _, d := range decls {
+		fmt.Fprintf(&b, "%*d | %s\n", width, d.line, d.text)
+	}
+	return []byte(b.String()), true
+}
+
+// goOutline は Go のソースから型・関数・メソッドの宣言を抽出します。
+// インターフェースはメソッドも含め、構文エラーがある場合は解析できた部分までを返します
+func goOutline(content []byte) []outlineLine {
+	fset := token.NewFileSet()
+	file, _ := parser.ParseFile(fset, "", content, parser.SkipObjectResolution)
+	if file == nil {
+		return nil
+	}
+	source := func(from, to token.Pos) string {
+		start, end := fset.Position(from).Offset, fset.Position(to).Offset
+		if start < 0 || end > len(content) || start > end {
+			return ""
+		}
+		return strings.Join(strings.Fields(string(content[start:end])), " ")
+	}
+	line := func(pos token.Pos) int { return fset.Position(pos).Line }
+
+	var decls []outlineLine
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			end := d.End()
+			if d.Body != nil {
+				end = d.Body.Lbrace
+			}
+			decls = append(decls, outlineLine{line: line(d.Pos()), text: source(d.Pos(), end)})
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				switch t := ts.Type.(type) {
+				case *ast.StructType:
+					decls = append(decls, outlineLine{line: line(ts.Pos()), text: "type " + source(ts.Pos(), t.Fields.Opening)})
+				case *ast.InterfaceType:
+					decls = append(decls, outlineLine{line: line(ts.Pos()), text: "type " + source(ts.Pos(), t.Methods.Opening)})
+					for _, m := range t.Methods.List {
+						decls = append(decls, outlineLine{line: line(m.Pos()), text: "\t" + source(m.Pos(), m.End())})
+					}
+				default:
+					decls = append(decls, outlineLine{line: line(ts.Pos()), text: "type " + source(ts.Pos(), ts.End())})
+				}
+			}
+		}
+	}
+	return decls
+}
+
+var (
+	pythonDecl = regexp.MustCompile(`^\s*(async\s+def|def|class)\s+\w`)
+
+	tsTypeDecl     = regexp.MustCompile(`^\s*(export\s+)?(default\s+)?(declare\s+)?(abstract\s+)?(class|interface|enum|type|namespace)\s+[\w$]`)
+	tsFunctionDecl = regexp.MustCompile(`^\s*(export\s+)?(default\s+)?(declare\s+)?(async\s+)?function\b`)
+	tsArrowDecl    = regexp.MustCompile(`^\s*(export\s+)?(const|let)\s+[\w$]+\s*(:[^=]+)?=\s*(async\s+)?(\([^)]*\)|[\w$]+)\s*(:[^=]+)?=>`)
+	tsMethodDecl   = regexp.MustCompile(`^\s+((public|private|protected|static|readonly|async|abstract|override|get|set)\s+)*#?([\w$]+)\s*(<[^>]*>)?\s*\(`)
+
+	javaTypeDecl   = regexp.MustCompile(`^\s*((public|protected|private|static|final|abstract|sealed|non-sealed|strictfp)\s+)*(class|interface|enum|record|@interface)\s+\w`)
+	javaMethodDecl = regexp.MustCompile(`^\s+((public|protected|private|static|final|abstract|synchronized|native|default|strictfp)\s+)*(<[^>]+>\s+)?([\w.$\[\]]+(<.*>)?(\[\])*\s+)?([\w$]+)\s*\(`)
+)
+
+// statementKeywords は関数呼び出しと同じ形で始まる制御構文などのキーワードです。メソッドの宣言とみなしません
+var statementKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "return": true, "new": true,
+	"throw": true, "else": true, "case": true, "do": true, "try": true, "synchronized": true, "function": true,
+	"super": true, "this": true, "await": true, "yield": true, "typeof": true,
+}
+
+// declKind は行単位の判定で見つけた宣言の種類です
+type declKind int
+
+const (
+	// declNone は宣言ではないことを示します
+	declNone declKind = iota
+	// declPlain は関数などの宣言です
+	declPlain
+	// declContainer はクラス・インターフェースなど、続くブロックの直下にメソッドを宣言できる型の宣言です
+	declContainer
+)
+
+// syntax は宣言を抽出する際に読み飛ばすコメントと文字列リテラルの書き方です
+type syntax struct {
+	// lineComment は行末までのコメントの開始記号です
+	lineComment string
+	// blockComment は "/* */" のブロックコメントを使用するかどうかです
+	blockComment bool
+	// multilineQuotes は複数行にわたる文字列リテラルの区切り記号です
+	multilineQuotes []string
+	// quotes は 1 行で閉じる文字列リテラルの区切り文字です
+	quotes string
+}
+
+var (
+	pythonSyntax     = syntax{lineComment: "#", multilineQuotes: []string{`"""`, `'''`}, quotes: `"'`}
+	typeScriptSyntax = syntax{lineComment: "//", blockComment: true, multilineQuotes: []string{"`"}, quotes: `"'`}
+	javaSyntax       = syntax{lineComment: "//", blockComment: true, multilineQuotes: []string{`"""`}, quotes: `"'`}
+)
+
+// pythonOutline は Python のソースから class と def の行を抽出します。引数が複数行にわたる場合は閉じ括弧までを 1 行にまとめます。
+// docstring などの複数行の文字列リテラルの中の行は判定しません
+func pythonOutline(content []byte) []outlineLine {
+	return matchDecls(content, pythonSyntax, func(line string, _ bool) declKind {
+		if pythonDecl.MatchString(line) {
+			return declPlain
+		}
+		return declNone
+	})
+}
+
+// typeScriptOutline は TypeScript のソースからクラス・インターフェース・型・関数・アロー関数の定数・メソッドの行を抽出します
+func typeScriptOutline(content []byte) []outlineLine {
+	return matchDecls(content, typeScriptSyntax, func(line string, inClass bool) declKind {
+		if m := tsTypeDecl.FindStringSubmatch(line); m != nil {
+			if m[5] == "class" || m[5] == "interface" {
+				return declContainer
+			}
+			return declPlain
+		}
+		if tsFunctionDecl.MatchString(line) || tsArrowDecl.MatchString(line) {
+			return declPlain
+		}
+		// クラスのメソッドはクラスの本体の直下で "名前(" で始まり "{" で終わる行とする。
+		// 関数の中でコールバックを渡す呼び出し（it("...", function () { など）は本体の直下にないため除く
+		if !inClass {
+			return declNone
+		}
+		m := tsMethodDecl.FindStringSubmatch(line)
+		if m != nil && !statementKeywords[m[3]] && !strings.Contains(line, "=>") && strings.HasSuffix(strings.TrimSpace(line), "{") {
+			return declPlain
+		}
+		return declNone
+	})
+}
+
+// javaOutline は Java のソースからクラス・インターフェース・列挙型・レコードとメソッド・コンストラクターの行を抽出します。
+// メソッドはクラスなどの本体の直下にある行のみを判定します
+func javaOutline(content []byte) []outlineLine {
+	return matchDecls(content, javaSyntax, func(line string, inClass bool) declKind {
+		if javaTypeDecl.MatchString(line) {
+			return declContainer
+		}
+		if !inClass {
+			return declNone
+		}
+		m := javaMethodDecl.FindStringSubmatch(line)
+		if m == nil || statementKeywords[m[7]] || strings.Contains(line, "->") {
+			return declNone
+		}
+		// 代入の右辺の呼び出しや、型のない文としての呼び出しは除く
+		head := line[:strings.Index(line, "(")]
+		if strings.Contains(head, "=") || statementKeywords[strings.Fields(head)[0]] {
+			return declNone
+		}
+		if m[1] == "" && m[4] == "" && !strings.HasSuffix(strings.TrimSpace(line), "{") {
+			return declNone
+		}
+		return declPlain
+	})
+}
+
+// matchDecls は isDecl が宣言と判定した行を抽出します。コメントと文字列リテラルの中から始まる行は判定しません。
+// isDecl の inClass は、その行が declContainer の宣言に続く波括弧のブロックの直下にあるかどうかです。
+// 宣言の括弧が閉じていない場合は、閉じるまでの行を空白でつなげ、末尾の "{" を取り除きます
+func matchDecls(content []byte, sx syntax, isDecl func(line string, inClass bool) declKind) []outlineLine {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	var starts []int
+	var st scanState
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		inCode := !st.inComment && st.inQuote == "" && trimmed != "" && !strings.HasPrefix(trimmed, sx.lineComment) &&
+			!(sx.blockComment && strings.HasPrefix(trimmed, "*"))
+		if inCode {
+			switch isDecl(line, st.inClass()) {
+			case declContainer:
+				st.pendingClass = true
+				starts = append(starts, i)
+			case declPlain:
+				starts = append(starts, i)
+			}
+		}
+		st.scan(line, sx)
+	}
+
+	var decls []outlineLine
+	next := 0
+	for _, start := range starts {
+		if start < next {
+			// 前の宣言の引数の続きとしてつなげた行
+			continue
+		}
+		text := strings.TrimRight(lines[start], " \t")
+		i := start
+		for depth := parenDepth(text); depth > 0 && i+1 < len(lines) && i-start < 20; depth = parenDepth(text) {
+			i++
+			text += " " + strings.TrimSpace(lines[i])
+		}
+		next = i + 1
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimRight(text, " \t"), "{"))
+		indent := lines[start][:len(lines[start])-len(strings.TrimLeft(lines[start], " \t"))]
+		decls = append(decls, outlineLine{line: start + 1, text: indent + text})
+	}
+	return decls
+}
+
+// scanState は行をまたいで引き継ぐコメント・文字列リテラル・波括弧の状態です
+type scanState struct {
+	// inComment はブロックコメントの中かどうかです
+	inComment bool
+	// inQuote は閉じていない複数行の文字列リテラルの区切り記号です
+	inQuote string
+	// depth は閉じていない波括弧の数です
+	depth int
+	// classes はクラスなどの本体のブロックの深さです。最後の要素が最も内側です
+	classes []int
+	// pendingClass は declContainer の宣言の後で、本体の "{" がまだ現れていないことを示します
+	pendingClass bool
+}
+
+// inClass は現在の位置がクラスなどの本体の直下かどうかを返します
+func (st *scanState) inClass() bool {
+	return len(st.classes) > 0 && st.classes[len(st.classes)-1] == st.depth
+}
+
+// scan は 1 行を読み進め、コメント・文字列リテラルの外にある波括弧を数えます
+func (st *scanState) scan(line string, sx syntax) {
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		if st.inComment {
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				return
+			}
+			st.inComment = false
+			i += end + len("*/")
+			continue
+		}
+		if st.inQuote != "" {
+			end := closingQuote(rest, st.inQuote)
+			if end < 0 {
+				return
+			}
+			i += end + len(st.inQuote)
+			st.inQuote = ""
+			continue
+		}
+		if strings.HasPrefix(rest, sx.lineComment) {
+			return
+		}
+		if sx.blockComment && strings.HasPrefix(rest, "/*") {
+			st.inComment = true
+			i += len("/*")
+			continue
+		}
+		if q := multilineQuote(rest, sx); q != "" {
+			st.inQuote = q
+			i += len(q)
+			continue
+		}
+
+		switch c := line[i]; {
+		case strings.IndexByte(sx.quotes, c) >= 0:
+			end := closingQuote(rest[1:], string(c))
+			if end < 0 {
+				// 閉じていない文字列リテラルは行末までとする
+				return
+			}
+			i += end + 1
+		case c == '{':
+			st.depth++
+			if st.pendingClass {
+				st.classes = append(st.classes, st.depth)
+				st.pendingClass = false
+			}
+		case c == '}':
+			if st.inClass() {
+				st.classes = st.classes[:len(st.classes)-1]
+			}
+			if st.depth > 0 {
+				st.depth--
+			}
+		}
+		i++
+	}
+}
+
+// multilineQuote は s が複数行の文字列リテラルの区切り記号で始まる場合に、その記号を返します
+func multilineQuote(s string, sx syntax) string {
+	for _, q := range sx.multilineQuotes {
+		if strings.HasPrefix(s, q) {
+			return q
+		}
+	}
+	return ""
+}
+
+// closingQuote は s の中で、バックスラッシュでエスケープされていない quote の位置を返します。見つからない場合は -1 を返します
+func closingQuote(s, quote string) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], quote):
+			return i
+		}
+	}
+	return -1
+}
+
+// parenDepth は閉じていない丸括弧の数を返します
+func parenDepth(s string) int {
+	return strings.Count(s, "(") - strings.Count(s, ")")
+}
@@ -0,0 +1,288 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestOutline(t *testing.T) {
+	tests := []struct {
+		name    string
+		relPath string
+		content string
+		want    string
+	}{
+		{
+			name:    "Go",
+			relPath: "scanner.go",
+			content: `package filesystem
+
+// Scanner はスキャナーです
+type Scanner struct {
+	root string
+}
+
+type Logger interface {
+	Log(level, msg string)
+}
+
+type Size int64
+
+func (s *Scanner) Scan(ctx context.Context,
+	dir string) error {
+	return nil
+}
+`,
+			want: " 4 | type Scanner struct\n" +
+				" 8 | type Logger interface\n" +
+				" 9 | \tLog(level, msg string)\n" +
+				"12 | type Size int64\n" +
+				"14 | func (s *Scanner) Scan(ctx context.Context, dir string) error\n",
+		},
+		{
+			name:    "Python",
+			relPath: "app.py",
+			content: `import os
+
+# def commented_out():
+class Handler(Base):
+    def __init__(self, name):
+        self.name = name
+
+    async def handle(self,
+                     request):
+        return None
+
+def main():
+    pass
+`,
+			want: " 4 | class Handler(Base):\n" +
+				" 5 |     def __init__(self, name):\n" +
+				" 8 |     async def handle(self, request):\n" +
+				"12 | def main():\n",
+		},
+		{
+			name:    "TypeScript",
+			relPath: "service.ts",
+			content: `import { Api } from "./api";
+
+export interface Options {
+  retries: number;
+}
+
+export class Service {
+  constructor(private api: Api) {}
+
+  async fetch(id: string): Promise<Item> {
+    if (id === "") {
+      return this.api.get(id);
+    }
+    items.forEach((item) => {
+      console.log(item);
+    });
+  }
+}
+
+export const parse = (text: string): Item => JSON.parse(text);
+
+export function createService(api: Api): Service {
+  return new Service(api);
+}
+`,
+			want: " 3 | export interface Options\n" +
+				" 7 | export class Service\n" +
+				"10 |   async fetch(id: string): Promise<Item>\n" +
+				"20 | export const parse = (text: string): Item => JSON.parse(text);\n" +
+				"22 | export function createService(api: Api): Service\n",
+		},
+		{
+			name:    "Java",
+			relPath: "Service.java",
+			content: `package app;
+
+/**
+ * public void documented() {
+ */
+public class Service {
+    private final Map<String, Item> cache = new HashMap<>();
+
+    public Service(Api api) {
+        this.api = api;
+    }
+
+    public Optional<Item> find(String id) throws IOException {
+        if (id.isEmpty()) {
+            return Optional.empty();
+        }
+        Item item = api.get(id);
+        log(item);
+        items.forEach(i -> {
+            print(i);
+        });
+        return Optional.of(item);
+    }
+
+    interface Listener {
+        void onChange(Item item);
+    }
+}
+`,
+			want: " 6 | public class Service\n" +
+				" 9 |     public Service(Api api)\n" +
+				"13 |     public Optional<Item> find(String id) throws IOException\n" +
+				"25 |     interface Listener\n" +
+				"26 |         void onChange(Item item);\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := outline(tt.relPath, []byte(tt.content))
+			if !ok {
+				t.Fatalf("outline() は宣言を抽出できませんでした")
+			}
+			if string(got) != tt.want {
+				t.Errorf("outline() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutline_NotDeclarations(t *testing.T) {
+	tests := []struct {
+		name    string
+		relPath string
+		content string
+		want    string
+	}{
+		{
+			name:    "TypeScript のテストのコールバック",
+			relPath: "service.test.ts",
+			content: `describe("Service", () => {
+  beforeEach(function () {
+    setup();
+  });
+
+  it("works", function () {
+    expect(run()).toBe(true);
+  });
+});
+
+export function helper(): void {
+  items.forEach(function (item) {
+    log(item);
+  });
+}
+`,
+			want: "11 | export function helper(): void\n",
+		},
+		{
+			name:    "TypeScript の文字列リテラルの中の波括弧",
+			relPath: "view.ts",
+			content: "export class View {\n" +
+				"  template = `\n" +
+				"    render() {\n" +
+				"  `;\n" +
+				"  label = \"}\";\n" +
+				"\n" +
+				"  render(): string {\n" +
+				"    return this.template;\n" +
+				"  }\n" +
+				"}\n",
+			want: " 1 | export class View\n" +
+				" 7 |   render(): string\n",
+		},
+		{
+			name:    "Python の docstring の中の def",
+			relPath: "doc.py",
+			content: `def run():
+    """Run the task.
+
+    Example:
+        def callback(event):
+            pass
+    """
+    return None
+
+class Task:
+    '''
+    class Nested:
+    '''
+    def start(self):
+        pass
+`,
+			want: " 1 | def run():\n" +
+				"10 | class Task:\n" +
+				"14 |     def start(self):\n",
+		},
+		{
+			name:    "Java のメソッドの中の呼び出し",
+			relPath: "Main.java",
+			content: `public class Main {
+    static {
+        register(Main.class) {
+        }
+    }
+
+    public static void main(String[] args) {
+        String text = """
+            public void fake() {
+            """;
+        run(text);
+    }
+}
+`,
+			want: " 1 | public class Main\n" +
+				" 7 |     public static void main(String[] args)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := outline(tt.relPath, []byte(tt.content))
+			if !ok {
+				t.Fatalf("outline() は宣言を抽出できませんでした")
+			}
+			if string(got) != tt.want {
+				t.Errorf("outline() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutline_Unsupported(t *testing.T) {
+	for name, tt := range map[string]struct{ relPath, content string }{
+		"対応していない言語": {"README.md", "# title\n"},
+		"宣言がない":     {"const.py", "VALUE = 1\n"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := outline(tt.relPath, []byte(tt.content)); ok {
+				t.Errorf("outline(%q) は false を返すべきです", tt.relPath)
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteReport_Outline(t *testing.T) {
+	long := strings.Repeat("x\n", HeadLines+TailLines+10)
+	fsys := fstest.MapFS{
+		"main.go":  {Data: []byte("package main\n\nfunc main() {\n\tprintln(\"body\")\n}\n")},
+		"notes.md": {Data: []byte(long)},
+	}
+	entries := []model.FileSystemEntry{
+		{RelPath: "main.go", Size: 48, ContentMode: "outline"},
+		{RelPath: "notes.md", Size: int64(len(long)), ContentMode: "outline"},
+	}
+	var buf strings.Builder
+	if err := NewGeneratorWithOptions(Options{LineNumbers: true}).WithFS(fsys).WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "3 | func main()\n") || strings.Contains(output, "body") {
+		t.Errorf("Go のファイルが宣言のみになっていません:\n%s", output)
+	}
+	if !strings.Contains(output, "... 中略") {
+		t.Errorf("宣言を抽出できないファイルが先頭と末尾のみになっていません:\n%s", output)
+	}
+}
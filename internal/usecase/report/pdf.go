@@ -0,0 +1,183 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"FolderScope/internal/domain/model"
+)
+
+const (
+	// pdfFontFamily は PDFFont で指定したフォントを登録するフォントファミリー名です
+	pdfFontFamily = "report"
+	// pdfCoreFontFamily は PDFFont を指定しない場合に使用する PDF 標準の等幅フォントです
+	pdfCoreFontFamily = "Courier"
+	// pdfFontSize は本文のフォントサイズ（ポイント）です
+	pdfFontSize = 8
+	// pdfHeadingFontSize は "===== 見出し =====" の行のフォントサイズ（ポイント）です
+	pdfHeadingFontSize = 11
+	// pdfHeaderFontSize はページヘッダーのフォントサイズ（ポイント）です
+	pdfHeaderFontSize = 7
+	// pdfLineHeight は本文の 1 行の高さ（mm）です
+	pdfLineHeight = 3.6
+	// pdfTabWidth はタブを展開する空白の数です
+	pdfTabWidth = 4
+)
+
+// pdfCoreReplacer は PDF 標準フォントで表示できない罫線を ASCII 文字に置き換えます
+var pdfCoreReplacer = strings.NewReplacer(treeBranch, "|-- ", treeLast, "`-- ", treeVertical, "|   ")
+
+// writePDF はテキスト形式のレポートを PDF に組版して出力します。
+// 各ページにはヘッダー（生成日時・表示中のファイル・ページ番号）を付け、見出しとファイルごとにしおりを作成します
+func (g *Generator) writePDF(writer io.Writer, entries []model.FileSystemEntry) error {
+	doc, err := newPDFDocument(g.options.PDFFont, time.Now())
+	if err != nil {
+		return err
+	}
+	text := *g
+	text.options.Format = FormatText
+	// ANSI エスケープシーケンスによる色付けは PDF では表示できないため使用しない
+	text.options.Highlight = false
+	text.template = nil
+	if err := text.WriteReport(doc, entries); err != nil {
+		return err
+	}
+	return doc.output(writer)
+}
+
+// pdfDocument はテキスト形式のレポートを 1 行ずつ PDF に組版する Writer です。
+// SectionMarker を実装し、ファイルセクションの見出しにしおりを作成します
+type pdfDocument struct {
+	pdf    *fpdf.Fpdf
+	family string
+	// translate は PDF 標準フォントの文字コードに変換する関数です。PDFFont を指定した場合は nil です
+	translate func(string) string
+	// pending は改行がまだ書き込まれていない行の途中までの内容です
+	pending []byte
+	// section はセクション開始の通知を受けたファイルの相対パスです。見出しの行を出力すると空にします
+	section string
+	// current はページヘッダーに表示する、出力中のファイルの相対パスです
+	current string
+}
+
+// newPDFDocument は A4 縦の PDF を作成します。fontPath が空の場合は PDF 標準の等幅フォントを使用し、
+// 表示できない文字（日本語など）は '.' に置き換えます
+func newPDFDocument(fontPath string, generatedAt time.Time) (*pdfDocument, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	doc := &pdfDocument{pdf: pdf, family: pdfCoreFontFamily}
+	if fontPath != "" {
+		font, err := os.ReadFile(fontPath)
+		if err != nil {
+			return nil, fmt.Errorf("PDF のフォントの読み込みに失敗しました: %w", err)
+		}
+		pdf.AddUTF8FontFromBytes(pdfFontFamily, "", font)
+		doc.family = pdfFontFamily
+	} else {
+		doc.translate = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("PDF のフォントの登録に失敗しました: %w", err)
+	}
+
+	pdf.SetTitle("FolderScope", true)
+	pdf.SetCreator("FolderScope", true)
+	pdf.AliasNbPages("")
+	pdf.SetAutoPageBreak(true, 12)
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont(doc.family, "", pdfHeaderFontSize)
+		pdf.SetTextColor(96, 96, 96)
+		pdf.CellFormat(0, 4, doc.text("FolderScope  "+generatedAt.Format(MetadataTimeLayout)), "", 0, "L", false, 0, "")
+		left, _, right, _ := pdf.GetMargins()
+		pdf.SetX(left)
+		pageLabel := fmt.Sprintf("%s  %d/{nb}", doc.current, pdf.PageNo())
+		pdf.CellFormat(0, 4, doc.text(strings.TrimSpace(pageLabel)), "", 1, "R", false, 0, "")
+		width, _ := pdf.GetPageSize()
+		pdf.SetDrawColor(192, 192, 192)
+		pdf.Line(left, pdf.GetY(), width-right, pdf.GetY())
+		pdf.Ln(3)
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.SetFont(doc.family, "", pdfFontSize)
+	pdf.AddPage()
+	return doc, nil
+}
+
+// text は文字列を出力するフォントで表示できる形に変換します
+func (d *pdfDocument) text(s string) string {
+	if d.translate == nil {
+		return s
+	}
+	return d.translate(pdfCoreReplacer.Replace(s))
+}
+
+// Write はレポートを行ごとに組版します。改行で終わらない最後の行は次の書き込みまで保留します
+func (d *pdfDocument) Write(p []byte) (int, error) {
+	d.pending = append(d.pending, p...)
+	for {
+		i := bytes.IndexByte(d.pending, '\n')
+		if i < 0 {
+			break
+		}
+		d.writeLine(string(d.pending[:i]))
+		d.pending = d.pending[i+1:]
+	}
+	if err := d.pdf.Error(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// MarkSectionStart はファイルセクションの開始を記録し、続く見出しの行にしおりを作成できるようにします
+func (d *pdfDocument) MarkSectionStart(relPath string) {
+	d.section = relPath
+	d.current = relPath
+}
+
+// MarkSectionEnd はファイルセクションの終了を記録します
+func (d *pdfDocument) MarkSectionEnd() {
+	d.current = ""
+}
+
+// writeLine は 1 行を組版します。"===== 見出し =====" の行とファイルセクションの見出しの行は背景色を付け、しおりを作成します
+func (d *pdfDocument) writeLine(line string) {
+	line = strings.ReplaceAll(strings.TrimSuffix(line, "\r"), "\t", strings.Repeat(" ", pdfTabWidth))
+	pdf := d.pdf
+	switch {
+	case strings.HasPrefix(line, "===== ") && strings.HasSuffix(line, " ====="):
+		title := strings.TrimSuffix(strings.TrimPrefix(line, "===== "), " =====")
+		pdf.Ln(2)
+		pdf.SetFont(d.family, "", pdfHeadingFontSize)
+		pdf.SetFillColor(220, 228, 240)
+		pdf.Bookmark(d.text(title), 0, -1)
+		pdf.MultiCell(0, pdfLineHeight*1.6, d.text(title), "", "L", true)
+		pdf.SetFont(d.family, "", pdfFontSize)
+		pdf.Ln(1)
+	case d.section != "" && strings.HasPrefix(line, "----- "):
+		pdf.SetFillColor(236, 236, 236)
+		pdf.Bookmark(d.text(d.section), 1, -1)
+		pdf.MultiCell(0, pdfLineHeight, d.text(line), "", "L", true)
+		d.section = ""
+	case line == "":
+		pdf.Ln(pdfLineHeight)
+	default:
+		pdf.MultiCell(0, pdfLineHeight, d.text(line), "", "L", false)
+	}
+}
+
+// output は保留中の行を組版してから、PDF を writer に書き込みます
+func (d *pdfDocument) output(writer io.Writer) error {
+	if len(d.pending) > 0 {
+		d.writeLine(string(d.pending))
+		d.pending = nil
+	}
+	if err := d.pdf.Output(writer); err != nil {
+		return fmt.Errorf("PDF の出力に失敗しました: %w", err)
+	}
+	return nil
+}
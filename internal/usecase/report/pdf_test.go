@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_PDF(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Repeat("func main() {\n\tprintln(\"hello\")\n}\n", 200)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries := []model.FileSystemEntry{
+		{Path: filepath.Join(dir, "main.go"), RelPath: "main.go", Size: int64(len(content))},
+	}
+
+	var buf bytes.Buffer
+	if err := NewGeneratorWithOptions(Options{Format: FormatPDF}).WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	got := buf.String()
+	if !strings.HasPrefix(got, "%PDF-") {
+		t.Fatalf("PDF のヘッダーがありません: %q", got[:min(len(got), 16)])
+	}
+	// 見出しとファイルセクションはしおり（/Outlines）になる
+	for _, want := range []string{"/Outlines", "(main.go)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("出力に %q が含まれていません", want)
+		}
+	}
+	// 600 行の本文は複数ページに分かれる
+	if pages := strings.Count(got, "/Type /Page\n"); pages < 2 {
+		t.Errorf("ページ数 = %d, want 2 以上", pages)
+	}
+}
+
+func TestGenerator_WriteReport_PDFFontNotFound(t *testing.T) {
+	generator := NewGeneratorWithOptions(Options{Format: FormatPDF, PDFFont: filepath.Join(t.TempDir(), "missing.ttf")})
+	err := generator.WriteReport(&bytes.Buffer{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "PDF のフォントの読み込みに失敗しました") {
+		t.Errorf("WriteReport() error = %v, want フォントの読み込みエラー", err)
+	}
+}
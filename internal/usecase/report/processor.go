@@ -0,0 +1,153 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+
+	"FolderScope/internal/domain/model"
+)
+
+// ContentProcessor はファイルの内容を、Formatter に渡す前に加工する処理の 1 段です。
+// Generator は読み込んだテキストファイルの内容に、文字コードの変換・外部コマンドでの加工・抜粋・秘密情報のマスク・改行の正規化と、
+// WithContentProcessors で追加した ContentProcessor をこの順に適用します
+type ContentProcessor interface {
+	// Process は entry の内容 content を加工した結果を返します。
+	// エラーを返した場合は以降の処理を中止し、内容の代わりにエラーを記載した注記を出力します
+	Process(entry model.FileSystemEntry, content []byte) ([]byte, error)
+}
+
+// ContentProcessorFunc は関数を ContentProcessor として使用するための型です
+type ContentProcessorFunc func(entry model.FileSystemEntry, content []byte) ([]byte, error)
+
+// Process は f(entry, content) を返します
+func (f ContentProcessorFunc) Process(entry model.FileSystemEntry, content []byte) ([]byte, error) {
+	return f(entry, content)
+}
+
+// ContentNotice は、ContentProcessor が内容を出力せずに、エラーの代わりにこの文字列をそのまま注記として記載することを示すエラーです
+type ContentNotice string
+
+// Error は注記の文字列を返します
+func (n ContentNotice) Error() string {
+	return string(n)
+}
+
+// ProcessContent は content に processors を順に適用した結果を返します。
+// いずれかが失敗した場合は、その時点で処理を中止し、内容の代わりに出力する注記を返します
+func ProcessContent(entry model.FileSystemEntry, content []byte, processors ...ContentProcessor) ([]byte, string) {
+	for _, p := range processors {
+		processed, err := p.Process(entry, content)
+		if err != nil {
+			var notice ContentNotice
+			if errors.As(err, &notice) {
+				return nil, string(notice)
+			}
+			return nil, fmt.Sprintf("[内容の加工に失敗したため内容表示不可] %v", err)
+		}
+		content = processed
+	}
+	return content, ""
+}
+
+// TranscodeProcessor は、スキャン時に判定した文字コード（entry.Encoding）が UTF-8 以外のテキストを UTF-8 に変換する ContentProcessor を返します
+func TranscodeProcessor() ContentProcessor {
+	return ContentProcessorFunc(func(entry model.FileSystemEntry, content []byte) ([]byte, error) {
+		decoded, err := decodeText(content, entry.Encoding)
+		if err != nil {
+			return nil, ContentNotice(fmt.Sprintf("[文字コード（%s）の変換に失敗したため内容表示不可] %v", entry.Encoding, err))
+		}
+		return decoded, nil
+	})
+}
+
+// FilterProcessor は filter で内容を加工する ContentProcessor を返します。
+// 加工に失敗した場合、FallbackSkip であれば内容を出力せず、それ以外は加工前の内容をそのまま返します
+func FilterProcessor(filter ContentFilter, fallback FilterFallback) ContentProcessor {
+	return ContentProcessorFunc(func(entry model.FileSystemEntry, content []byte) ([]byte, error) {
+		filtered, err := filter.Filter(entry.RelPath, content)
+		if err == nil {
+			return filtered, nil
+		}
+		if fallback == FallbackSkip {
+			return nil, ContentNotice(fmt.Sprintf("[外部コマンドによる処理に失敗したためスキップ] %v", err))
+		}
+		return content, nil
+	})
+}
+
+// HeadTailProcessor は先頭 head 行と末尾 tail 行を残し、間を省略する ContentProcessor を返します
+func HeadTailProcessor(head, tail int) ContentProcessor {
+	return ContentProcessorFunc(func(_ model.FileSystemEntry, content []byte) ([]byte, error) {
+		return headTail(content, head, tail), nil
+	})
+}
+
+// OutlineProcessor は関数・クラス・型などの宣言のみを抽出する ContentProcessor を返します。
+// 宣言を抽出できない場合は、先頭 head 行と末尾 tail 行を残して間を省略します
+func OutlineProcessor(head, tail int) ContentProcessor {
+	return ContentProcessorFunc(func(entry model.FileSystemEntry, content []byte) ([]byte, error) {
+		if outlined, ok := outline(entry.RelPath, content); ok {
+			return outlined, nil
+		}
+		return headTail(content, head, tail), nil
+	})
+}
+
+// RedactProcessor は既定の規則で秘密情報（API キーやパスワードなど）をマスクする ContentProcessor を返します
+func RedactProcessor() ContentProcessor {
+	return ContentProcessorFunc(func(_ model.FileSystemEntry, content []byte) ([]byte, error) {
+		redacted, _ := redactSecrets(string(content))
+		return []byte(redacted), nil
+	})
+}
+
+// LineEndingProcessor は改行を LF にそろえる ContentProcessor を返します
+func LineEndingProcessor() ContentProcessor {
+	return ContentProcessorFunc(func(_ model.FileSystemEntry, content []byte) ([]byte, error) {
+		return normalizeLineEndings(content), nil
+	})
+}
+
+// LineNumberProcessor は各行の先頭に行番号を付ける ContentProcessor を返します
+func LineNumberProcessor() ContentProcessor {
+	return ContentProcessorFunc(func(_ model.FileSystemEntry, content []byte) ([]byte, error) {
+		return numberLines(content), nil
+	})
+}
+
+// WithContentProcessors は、既定の加工の後に processors を指定された順に適用する Generator のコピーを返します。
+// 行番号は processors を適用した後の内容に付けます
+func (g *Generator) WithContentProcessors(processors ...ContentProcessor) *Generator {
+	copied := *g
+	copied.processors = append(g.processors[:len(g.processors):len(g.processors)], processors...)
+	return &copied
+}
+
+// contentProcessors は entry のテキストの内容に適用する ContentProcessor を、Options と With で始まるメソッドの設定から適用する順に組み立てます。
+// 変更箇所のみを出力するファイルは、外部コマンドでの加工・マスク・改行の正規化のみを適用します
+func (g *Generator) contentProcessors(entry model.FileSystemEntry) []ContentProcessor {
+	_, isHunks := g.hunksOf(entry)
+	var processors []ContentProcessor
+	if !isHunks {
+		processors = append(processors, TranscodeProcessor())
+	}
+	if g.filter != nil {
+		processors = append(processors, FilterProcessor(g.filter, g.filterFallback))
+	}
+	if !isHunks {
+		head, tail := g.headTailLines()
+		switch mode, _ := g.contentMode(entry); mode {
+		case ContentOutline:
+			processors = append(processors, OutlineProcessor(head, tail))
+		case ContentHeadTail:
+			processors = append(processors, HeadTailProcessor(head, tail))
+		}
+	}
+	processors = append(processors, ContentProcessorFunc(func(entry model.FileSystemEntry, content []byte) ([]byte, error) {
+		return g.redact(entry.RelPath, content), nil
+	}))
+	if g.options.Normalize {
+		processors = append(processors, LineEndingProcessor())
+	}
+	return append(processors, g.processors...)
+}
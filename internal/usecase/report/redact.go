@@ -0,0 +1,183 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactionRule はマスクする情報を検出する規則です。
+// 正規表現に "secret" という名前のグループがある場合はその部分のみを、ない場合は一致した全体を置き換えます
+type redactionRule struct {
+	name    string
+	pattern *regexp.Regexp
+	// replacement は置き換える文字列です。"$1" などで一致した部分を参照できます。空の場合は "[REDACTED:規則名]" です
+	replacement string
+}
+
+// redactionRules は既定でマスクする秘密情報の規則です
+var redactionRules = []redactionRule{
+	{name: "private-key", pattern: regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)},
+	{name: "aws-access-key-id", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "aws-secret-access-key", pattern: regexp.MustCompile(`(?i)\baws_?secret_?(?:access_?)?key\b["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})\b`)},
+	{name: "github-token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{name: "slack-token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{name: "google-api-key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{name: "stripe-secret-key", pattern: regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{24,}\b`)},
+	{name: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	// 設定ファイルやコード中の "password = ..." のような代入。変数や関数呼び出しへの誤検出を避けるため、
+	// 値が引用符で囲まれている場合か、行末までが 1 語の値である場合のみを対象とします
+	{name: "password", pattern: regexp.MustCompile(`(?im)(?:\b|_)(?:password|passwd|pwd|secret|token|api_?key|access_?key|client_?secret)["']?\s*[:=]\s*(?:"(?P<secret>[^"\s]{4,})"|'(?P<secret>[^'\s]{4,})'|(?P<secret>[^\s"'(){}\[\];,$]{4,})\s*$)`)},
+}
+
+// Redaction はファイル内でマスクした情報の種類と件数です
+type Redaction struct {
+	// Rule は検出した規則の名前です（"aws-access-key-id" など）
+	Rule string `json:"rule"`
+	// Count はマスクした箇所の数です
+	Count int `json:"count"`
+}
+
+// RedactedFile はマスクした情報を含むファイルです
+type RedactedFile struct {
+	RelPath    string
+	Redactions []Redaction
+}
+
+// redactionLog は 1 回のレポート出力でマスクした情報を、ファイルの出力順に記録します
+type redactionLog struct {
+	files  []RedactedFile
+	byPath map[string][]Redaction
+}
+
+// record は relPath のファイルでマスクした情報を記録します
+func (l *redactionLog) record(relPath string, redactions []Redaction) {
+	if l == nil || len(redactions) == 0 {
+		return
+	}
+	if _, ok := l.byPath[relPath]; ok {
+		return
+	}
+	l.byPath[relPath] = redactions
+	l.files = append(l.files, RedactedFile{RelPath: relPath, Redactions: redactions})
+}
+
+// redactedFiles は、この出力でマスクした情報を含むファイルを出力順に返します
+func (g *Generator) redactedFiles() []RedactedFile {
+	if g.redactions == nil {
+		return nil
+	}
+	return g.redactions.files
+}
+
+// redactionsOf は直前に読み込んだ relPath のファイルでマスクした情報を返します
+func (g *Generator) redactionsOf(relPath string) []Redaction {
+	if g.redactions == nil {
+		return nil
+	}
+	return g.redactions.byPath[relPath]
+}
+
+// redact は内容に含まれる秘密情報（マスキングが有効な場合）と、利用者定義のルールに一致した部分を置き換え、マスクした内容を記録します
+func (g *Generator) redact(relPath string, content []byte) []byte {
+	var rules []redactionRule
+	if !g.options.DisableRedaction {
+		rules = redactionRules
+	}
+	rules = append(rules[:len(rules):len(rules)], g.maskRules...)
+	if len(rules) == 0 {
+		return content
+	}
+	redacted, redactions := redactText(string(content), rules)
+	if len(redactions) == 0 {
+		return content
+	}
+	g.redactions.record(relPath, redactions)
+	return []byte(redacted)
+}
+
+// redactSecrets は text に含まれる秘密情報を既定の規則でマスクし、規則ごとの件数を規則名の順で返します
+func redactSecrets(text string) (string, []Redaction) {
+	return redactText(text, redactionRules)
+}
+
+// redactText は text のうち rules に一致した部分を規則の順に置き換え、規則ごとの件数を規則名の順で返します
+func redactText(text string, rules []redactionRule) (string, []Redaction) {
+	counts := make(map[string]int)
+	for _, rule := range rules {
+		matches := rule.pattern.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		template := rule.replacement
+		if template == "" {
+			template = fmt.Sprintf("[REDACTED:%s]", rule.name)
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := secretSpan(rule.pattern, m)
+			b.WriteString(text[last:start])
+			b.Write(rule.pattern.ExpandString(nil, template, text, m))
+			last = end
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+		counts[rule.name] += len(matches)
+	}
+
+	redactions := make([]Redaction, 0, len(counts))
+	for rule, n := range counts {
+		redactions = append(redactions, Redaction{Rule: rule, Count: n})
+	}
+	sort.Slice(redactions, func(i, j int) bool { return redactions[i].Rule < redactions[j].Rule })
+	return text, redactions
+}
+
+// secretSpan は一致箇所 m のうちマスクする範囲を返します。"secret" グループに一致した部分があればその範囲、なければ一致した全体です
+func secretSpan(pattern *regexp.Regexp, m []int) (int, int) {
+	for i, name := range pattern.SubexpNames() {
+		if name == "secret" && m[2*i] >= 0 {
+			return m[2*i], m[2*i+1]
+		}
+	}
+	return m[0], m[1]
+}
+
+// formatRedactions はマスクした情報を "規則名 (件数), ..." の形式で連結します
+func formatRedactions(redactions []Redaction) string {
+	parts := make([]string, 0, len(redactions))
+	for _, r := range redactions {
+		parts = append(parts, fmt.Sprintf("%s (%d)", r.Rule, r.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeRedactionSummary は、秘密情報や個人情報などをマスクしたファイルの一覧を出力形式に応じて出力します。マスクしたファイルがない場合は何も出力しません
+func (g *Generator) writeRedactionSummary(writer io.Writer, files []RedactedFile) {
+	if len(files) == 0 {
+		return
+	}
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "\n## マスクした情報")
+		fmt.Fprintln(writer)
+		for _, f := range files {
+			fmt.Fprintf(writer, "- %s: %s\n", escapeMarkdown(f.RelPath), formatRedactions(f.Redactions))
+		}
+	case FormatHTML:
+		fmt.Fprintln(writer, "<h2>マスクした情報</h2>\n<ul>")
+		for _, f := range files {
+			fmt.Fprintf(writer, "<li>%s: %s</li>\n", html.EscapeString(f.RelPath), html.EscapeString(formatRedactions(f.Redactions)))
+		}
+		fmt.Fprintln(writer, "</ul>")
+	default:
+		fmt.Fprintln(writer, "\n===== マスクした情報 =====")
+		for _, f := range files {
+			fmt.Fprintf(writer, "%s: %s\n", f.RelPath, formatRedactions(f.Redactions))
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+// reproduceHeading はレポートの末尾に記載する、再作成するコマンドの見出しです
+const reproduceHeading = "このレポートを再作成するコマンド"
+
+// writeReproduceFooter は command（Options.ReproduceCommand）が空でない場合に、レポートを再作成するコマンドを出力形式に応じて出力します
+func (g *Generator) writeReproduceFooter(writer io.Writer, command string) {
+	if command == "" {
+		return
+	}
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintf(writer, "\n## %s\n\n", reproduceHeading)
+		fence := codeFence(command)
+		fmt.Fprintf(writer, "%ssh\n%s\n%s\n", fence, command, fence)
+	case FormatHTML:
+		fmt.Fprintf(writer, "<h2>%s</h2>\n<pre class=\"reproduce-command\"><code>%s</code></pre>\n", reproduceHeading, html.EscapeString(command))
+	default:
+		fmt.Fprintf(writer, "\n===== %s =====\n", reproduceHeading)
+		fmt.Fprintln(writer, command)
+	}
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_ReproduceCommand(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("content of a")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 12}}
+	const command = "folderscope -source . -output 'my reports' -format md"
+
+	tests := []struct {
+		name    string
+		format  Format
+		command string
+		want    []string
+		wantEnd string
+	}{
+		{
+			name:    "テキスト形式は末尾に記載する",
+			format:  FormatText,
+			command: command,
+			want:    []string{"===== このレポートを再作成するコマンド =====\n" + command + "\n"},
+			wantEnd: command + "\n",
+		},
+		{
+			name:    "Markdown 形式はコードブロックで記載する",
+			format:  FormatMarkdown,
+			command: command,
+			want:    []string{"## このレポートを再作成するコマンド", "```sh\n" + command + "\n```"},
+			wantEnd: "```\n",
+		},
+		{
+			name:    "HTML 形式はエスケープして文書の終わりの前に記載する",
+			format:  FormatHTML,
+			command: "folderscope -ignore '<tmp>'",
+			want:    []string{"<h2>このレポートを再作成するコマンド</h2>", "folderscope -ignore &#39;&lt;tmp&gt;&#39;"},
+			wantEnd: "</html>\n",
+		},
+		{
+			name:    "JSON 形式は command の項目に出力する",
+			format:  FormatJSON,
+			command: command,
+			want:    []string{`"command":"folderscope -source . -output 'my reports' -format md"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			err := NewGeneratorWithOptions(Options{Format: tt.format, ReproduceCommand: tt.command}).WithFS(fsys).WriteReport(&buf, entries)
+			if err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			output := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+				}
+			}
+			if tt.wantEnd != "" && !strings.HasSuffix(output, tt.wantEnd) {
+				t.Errorf("出力の末尾が %q ではありません:\n%s", tt.wantEnd, output)
+			}
+		})
+	}
+
+	var buf strings.Builder
+	if err := NewGeneratorWithOptions(Options{}).WithFS(fsys).WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "再作成するコマンド") {
+		t.Errorf("コマンドを指定していないのに記載されています:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+// ErrRestoreExists は復元先に同じ名前のファイルがすでに存在することを示します。
+// 出力ファイルが存在する場合と同じく、errors.Is で apperrors.ErrOutputExists とも判定できます
+var ErrRestoreExists error = apperrors.New(apperrors.ErrOutputExists, "復元先にファイルがすでに存在します", "", nil)
+
+// RestoreOptions は Restore の設定です
+type RestoreOptions struct {
+	// Overwrite は復元先にすでに存在するファイルを上書きするかどうかです。false の場合は ErrRestoreExists を返します
+	Overwrite bool
+}
+
+// RestoreResult は Restore で復元したフォルダとファイルの件数です
+type RestoreResult struct {
+	Dirs  int
+	Files int
+	// Skipped は内容がレポートに含まれていない、またはバイナリファイルのため復元しなかったファイルの相対パスです
+	Skipped []string
+}
+
+// Restore は JSON・JSONL 形式でエクスポートしたレポートから、フォルダ構成とテキストファイルの内容を dir に復元します。
+// 内容はレポートに出力したもの（マスクや改行コードの正規化を適用したもの）で、パーミッションと更新日時もレポートの値に合わせます。
+// 相対パスが dir の外を指すエントリがある場合は、何も書き込まずにエラーを返します。
+// dir の中にすでにあるシンボリックリンクはたどらず、途中のフォルダがシンボリックリンクの場合もエラーにします
+func Restore(scan *ExportedScan, dir string, opts RestoreOptions) (RestoreResult, error) {
+	var result RestoreResult
+	for _, e := range scan.Entries {
+		// fs.ValidPath はバックスラッシュやドライブ名を許すため、OS のパスとしてもローカルかどうかを確認する
+		if !fs.ValidPath(e.RelPath) || e.RelPath == "." || !filepath.IsLocal(filepath.FromSlash(e.RelPath)) {
+			return result, fmt.Errorf("レポートのパス '%s' が不正です", e.RelPath)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return result, apperrors.Wrap("復元先のフォルダの作成に失敗しました", dir, err)
+	}
+
+	// フォルダのパーミッションと更新日時は、配下のファイルを書き込んだ後に設定する
+	var dirs []ExportEntry
+	for _, e := range scan.Entries {
+		path := filepath.Join(dir, filepath.FromSlash(e.RelPath))
+		if e.IsDir {
+			if err := checkNoSymlink(dir, e.RelPath); err != nil {
+				return result, err
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return result, apperrors.Wrap("フォルダの作成に失敗しました", path, err)
+			}
+			dirs = append(dirs, e)
+			result.Dirs++
+			continue
+		}
+		if e.Content == nil || e.IsBinary {
+			result.Skipped = append(result.Skipped, e.RelPath)
+			continue
+		}
+		if i := strings.LastIndex(e.RelPath, "/"); i >= 0 {
+			if err := checkNoSymlink(dir, e.RelPath[:i]); err != nil {
+				return result, err
+			}
+		}
+		if err := restoreFile(path, e, opts); err != nil {
+			return result, err
+		}
+		result.Files++
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		path := filepath.Join(dir, filepath.FromSlash(dirs[i].RelPath))
+		if err := restoreAttributes(path, dirs[i]); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// RedactedFiles は、秘密情報をマスクした内容のまま Restore で書き込まれるファイルと、マスクした情報の種類を返します。
+// これらのファイルには元の値の代わりに "[REDACTED:規則名]" などの文字列が書き込まれます
+func RedactedFiles(scan *ExportedScan) []RedactedFile {
+	var files []RedactedFile
+	for _, e := range scan.Entries {
+		if e.IsDir || e.Content == nil || e.IsBinary || len(e.Redactions) == 0 {
+			continue
+		}
+		files = append(files, RedactedFile{RelPath: e.RelPath, Redactions: e.Redactions})
+	}
+	return files
+}
+
+// checkNoSymlink は dir から rel（スラッシュ区切り）までの各フォルダが、シンボリックリンクでないことを確認します。
+// 存在しない部分以降は Restore が作成するため確認しません
+func checkNoSymlink(dir, rel string) error {
+	path := dir
+	for _, name := range strings.Split(rel, "/") {
+		path = filepath.Join(path, name)
+		info, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return apperrors.Wrap("復元先のパスの確認に失敗しました", path, err)
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("復元先のパス '%s' がシンボリックリンクのため書き込めません", path)
+		}
+	}
+	return nil
+}
+
+// restoreFile は 1 ファイル分の内容を書き込み、パーミッションと更新日時を設定します
+func restoreFile(path string, e ExportEntry, opts RestoreOptions) error {
+	if info, err := os.Lstat(path); err == nil {
+		if !opts.Overwrite || info.IsDir() {
+			return fmt.Errorf("%w: %s", ErrRestoreExists, path)
+		}
+		// シンボリックリンクの先に書き込まないよう、既存のファイルは削除してから作成する
+		if err := os.Remove(path); err != nil {
+			return apperrors.Wrap("既存のファイルの削除に失敗しました", path, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return apperrors.Wrap("フォルダの作成に失敗しました", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(*e.Content), 0644); err != nil {
+		return apperrors.Wrap("ファイルの書き込みに失敗しました", path, err)
+	}
+	return restoreAttributes(path, e)
+}
+
+// restoreAttributes はレポートに記録されたパーミッションと更新日時を設定します。記録されていない値は設定しません
+func restoreAttributes(path string, e ExportEntry) error {
+	if perm, ok := parsePermissions(e.Permissions); ok {
+		if err := os.Chmod(path, perm); err != nil {
+			return apperrors.Wrap("パーミッションの設定に失敗しました", path, err)
+		}
+	}
+	if e.ModTime != nil {
+		if err := os.Chtimes(path, time.Time{}, *e.ModTime); err != nil {
+			return apperrors.Wrap("更新日時の設定に失敗しました", path, err)
+		}
+	}
+	return nil
+}
+
+// parsePermissions は fs.FileMode.String() 形式（"-rw-r--r--" など）の文字列から、パーミッションビットを解析します
+func parsePermissions(s string) (fs.FileMode, bool) {
+	if len(s) < 9 {
+		return 0, false
+	}
+	const rwx = "rwxrwxrwx"
+	bits := s[len(s)-9:]
+	var perm fs.FileMode
+	for i := 0; i < len(bits); i++ {
+		switch bits[i] {
+		case rwx[i]:
+			perm |= 1 << (8 - i)
+		case '-':
+		default:
+			return 0, false
+		}
+	}
+	return perm, true
+}
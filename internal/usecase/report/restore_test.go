@@ -0,0 +1,142 @@
+package report
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/apperrors"
+)
+
+func TestRestore(t *testing.T) {
+	modTime := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	text := "alpha\n"
+	scan := &ExportedScan{Entries: []ExportEntry{
+		{RelPath: "sub", IsDir: true, Permissions: "drwxr-x---", ModTime: &modTime},
+		{RelPath: "sub/a.txt", Permissions: "-rw-------", ModTime: &modTime, Content: &text},
+		// 親フォルダのエントリがないファイル
+		{RelPath: "other/b.txt", Content: &text},
+		{RelPath: "sub/logo.png", IsBinary: true, Notice: "[バイナリファイルのため内容表示不可]"},
+	}}
+	dir := filepath.Join(t.TempDir(), "restored")
+
+	result, err := Restore(scan, dir, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result.Dirs != 1 || result.Files != 2 || len(result.Skipped) != 1 || result.Skipped[0] != "sub/logo.png" {
+		t.Errorf("Restore() = %+v", result)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "sub", "a.txt"))
+	if err != nil || string(data) != text {
+		t.Fatalf("復元したファイルの内容 = %q, %v", data, err)
+	}
+	for path, perm := range map[string]fs.FileMode{"sub": 0750, "sub/a.txt": 0600} {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != perm || !info.ModTime().Equal(modTime) {
+			t.Errorf("%s のパーミッション・更新日時 = %v, %v, want %v, %v", path, info.Mode().Perm(), info.ModTime(), perm, modTime)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sub", "logo.png")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("内容のないファイルが作成されています: %v", err)
+	}
+
+	// 既存のファイルは上書きを指定した場合のみ書き込む
+	if _, err := Restore(scan, dir, RestoreOptions{}); !errors.Is(err, ErrRestoreExists) || !errors.Is(err, apperrors.ErrOutputExists) {
+		t.Errorf("既存のファイルがある場合の error = %v, want ErrRestoreExists", err)
+	}
+	if _, err := Restore(scan, dir, RestoreOptions{Overwrite: true}); err != nil {
+		t.Errorf("上書きする場合の error = %v", err)
+	}
+}
+
+func TestRedactedFiles(t *testing.T) {
+	text := "key = [REDACTED:aws-access-key-id]\n"
+	redactions := []Redaction{{Rule: "aws-access-key-id", Count: 1}}
+	scan := &ExportedScan{Entries: []ExportEntry{
+		{RelPath: "sub", IsDir: true},
+		{RelPath: "sub/config.ini", Content: &text, Redactions: redactions},
+		{RelPath: "sub/plain.txt", Content: &text},
+		// 内容を出力していないファイルは復元しないため対象外
+		{RelPath: "sub/omitted.ini", Redactions: redactions},
+	}}
+
+	got := RedactedFiles(scan)
+	want := []RedactedFile{{RelPath: "sub/config.ini", Redactions: redactions}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactedFiles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRestore_InvalidPath(t *testing.T) {
+	text := "x"
+	dir := filepath.Join(t.TempDir(), "restored")
+	for _, relPath := range []string{"../escape.txt", "/abs.txt", "."} {
+		scan := &ExportedScan{Entries: []ExportEntry{{RelPath: relPath, Content: &text}}}
+		if _, err := Restore(scan, dir, RestoreOptions{}); err == nil {
+			t.Errorf("Restore(%q) はエラーになるべきです", relPath)
+		}
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("不正なパスのレポートで復元先が作成されています: %v", err)
+	}
+}
+
+func TestRestore_SymlinkParent(t *testing.T) {
+	text := "x"
+	root := t.TempDir()
+	outside := filepath.Join(root, "outside")
+	dir := filepath.Join(root, "restored")
+	for _, d := range []string{outside, dir} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skipf("シンボリックリンクを作成できません: %v", err)
+	}
+	for _, e := range []ExportEntry{
+		{RelPath: "link/escape.txt", Content: &text},
+		{RelPath: "link/sub/escape.txt", Content: &text},
+		{RelPath: "link", IsDir: true, Permissions: "drwx------"},
+	} {
+		scan := &ExportedScan{Entries: []ExportEntry{e}}
+		if _, err := Restore(scan, dir, RestoreOptions{Overwrite: true}); err == nil {
+			t.Errorf("Restore(%q) はエラーになるべきです", e.RelPath)
+		}
+	}
+	entries, err := os.ReadDir(outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("シンボリックリンクの先に書き込まれています: %v", entries)
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   fs.FileMode
+		wantOK bool
+	}{
+		{"-rw-r--r--", 0644, true},
+		{"drwxr-xr-x", 0755, true},
+		{"-rwxrwxrwx", 0777, true},
+		{"-rwsr-xr-x", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePermissions(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parsePermissions(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// scanStatsHeading はレポートの末尾に記載する、スキャンの統計情報の見出しです
+const scanStatsHeading = "スキャンの統計"
+
+// writeScanStatsFooter はスキャンの統計情報（ファイル数・読み込んだサイズ・除外の理由ごとの件数・所要時間）を、
+// レポートの作成方法の記録として出力形式に応じて出力します。stats が nil の場合は何も出力しません
+func (g *Generator) writeScanStatsFooter(writer io.Writer, stats *model.ScanStats) {
+	if stats == nil {
+		return
+	}
+	lines := scanStatsLines(*stats)
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintf(writer, "\n## %s\n\n", scanStatsHeading)
+		for _, line := range lines {
+			fmt.Fprintf(writer, "- %s\n", line)
+		}
+	case FormatHTML:
+		fmt.Fprintf(writer, "<h2>%s</h2>\n<ul class=\"scan-stats\">\n", scanStatsHeading)
+		for _, line := range lines {
+			fmt.Fprintf(writer, "<li>%s</li>\n", html.EscapeString(line))
+		}
+		fmt.Fprintln(writer, "</ul>")
+	default:
+		fmt.Fprintf(writer, "\n===== %s =====\n", scanStatsHeading)
+		for _, line := range lines {
+			fmt.Fprintln(writer, line)
+		}
+	}
+}
+
+// scanStatsLines はスキャンの統計情報を 1 項目 1 行の文字列で返します。除外の理由は理由の名前の順に並べます
+func scanStatsLines(stats model.ScanStats) []string {
+	skipped := "なし"
+	if len(stats.Skipped) > 0 {
+		reasons := make([]string, 0, len(stats.Skipped))
+		for reason, count := range stats.Skipped {
+			reasons = append(reasons, fmt.Sprintf("%s %d", reason, count))
+		}
+		slices.Sort(reasons)
+		skipped = strings.Join(reasons, ", ")
+	}
+	return []string{
+		fmt.Sprintf("スキャンしたファイル: %d / フォルダ: %d", stats.Files, stats.Dirs),
+		fmt.Sprintf("読み込んだサイズ: %s", FormatSize(stats.BytesRead)),
+		fmt.Sprintf("除外したエントリ: %s", skipped),
+		fmt.Sprintf("エラー: %d", stats.Errors),
+		fmt.Sprintf("所要時間: %s", time.Duration(stats.DurationMillis)*time.Millisecond),
+	}
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_ScanStatsFooter(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("content of a")}}
+	entries := []model.FileSystemEntry{{RelPath: "a.txt", Size: 12}}
+	stats := model.ScanStats{
+		StartedAt:      time.Now(),
+		DurationMillis: 1500,
+		Files:          1,
+		Dirs:           2,
+		BytesRead:      2048,
+		Errors:         1,
+		Skipped:        map[model.SkipReason]int{model.SkipIgnored: 3, model.SkipBinary: 1},
+	}
+
+	tests := []struct {
+		name      string
+		format    Format
+		normalize bool
+		want      []string
+		wantNone  bool
+	}{
+		{
+			name:   "テキスト形式",
+			format: FormatText,
+			want: []string{
+				"===== スキャンの統計 =====",
+				"スキャンしたファイル: 1 / フォルダ: 2",
+				"読み込んだサイズ: 2.0 KB",
+				"除外したエントリ: binary 1, ignored 3",
+				"エラー: 1",
+				"所要時間: 1.5s",
+			},
+		},
+		{
+			name:   "Markdown 形式",
+			format: FormatMarkdown,
+			want:   []string{"## スキャンの統計", "- 除外したエントリ: binary 1, ignored 3"},
+		},
+		{
+			name:   "HTML 形式",
+			format: FormatHTML,
+			want:   []string{"<h2>スキャンの統計</h2>", "<li>所要時間: 1.5s</li>"},
+		},
+		{
+			name:      "正規化した出力には記載しない",
+			format:    FormatText,
+			normalize: true,
+			wantNone:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			g := NewGeneratorWithOptions(Options{Format: tt.format, Normalize: tt.normalize}).WithFS(fsys).WithScanStats(stats)
+			if err := g.WriteReport(&buf, entries); err != nil {
+				t.Fatalf("WriteReport() error = %v", err)
+			}
+			output := buf.String()
+			if tt.wantNone && strings.Contains(output, "スキャンの統計") {
+				t.Errorf("スキャンの統計が記載されています:\n%s", output)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// ContentMode はファイル内容の出力方法です
+type ContentMode string
+
+const (
+	// ContentFull はファイル内容をすべて出力します
+	ContentFull ContentMode = "full"
+	// ContentHeadTail はファイル内容の先頭と末尾の行（既定では HeadLines 行と TailLines 行）のみを出力し、間を省略します
+	ContentHeadTail ContentMode = "headtail"
+	// ContentOutline はソースコードの関数・クラス・型などの宣言の行のみを行番号とともに出力し、本文を省略します。
+	// 宣言を抽出できない言語のファイルは ContentHeadTail と同じく出力します
+	ContentOutline ContentMode = "outline"
+	// ContentSkip はファイル内容のセクションに、サイズのためスキップした旨のみを記載します
+	ContentSkip ContentMode = "skip"
+	// ContentStructure はファイルをフォルダ構成にのみ表示し、ファイル内容のセクションは出力しません
+	ContentStructure ContentMode = "structure"
+)
+
+const (
+	// HeadLines と TailLines は ContentHeadTail で出力する先頭と末尾の既定の行数です
+	HeadLines = 60
+	TailLines = 20
+	// estimatedLineBytes は見積もりで ContentHeadTail の出力サイズを求める際の 1 行あたりのバイト数の目安です
+	estimatedLineBytes = 40
+)
+
+// SizeTier はファイルサイズの段階ごとの内容の出力方法です
+type SizeTier struct {
+	// MaxSize はこの段階に含めるファイルサイズの上限（バイト、上限を含む）です。0 の場合は上限がありません
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// Mode はこの段階のファイルの内容の出力方法です
+	Mode ContentMode `json:"mode"`
+}
+
+// SizeTiers はファイルサイズの小さい段階から順に並べた出力方法の一覧です。
+// ファイルは MaxSize 以下となる最初の段階の出力方法で出力し、どの段階にも含まれない場合は ContentFull で出力します
+type SizeTiers []SizeTier
+
+// TruncateSize は TruncatedSizeTiers で内容をすべて出力するファイルサイズの上限（バイト）です
+const TruncateSize = 64 << 10
+
+// TruncatedSizeTiers は見積もったレポートが大きすぎる場合に、TruncateSize を超えるファイルを先頭と末尾のみに切り詰めて出力するサイズの段階です
+var TruncatedSizeTiers = SizeTiers{{MaxSize: TruncateSize, Mode: ContentFull}, {Mode: ContentHeadTail}}
+
+// ParseContentMode は文字列から内容の出力方法を解決します
+func ParseContentMode(s string) (ContentMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "full":
+		return ContentFull, nil
+	case "headtail", "head-tail":
+		return ContentHeadTail, nil
+	case "outline":
+		return ContentOutline, nil
+	case "skip":
+		return ContentSkip, nil
+	case "structure":
+		return ContentStructure, nil
+	}
+	return "", fmt.Errorf("未対応の内容の出力方法です: %s（full, headtail, outline, skip, structure のいずれかを指定してください）", s)
+}
+
+// ParseSizeTiers は "64KB:full,1MB:headtail,*:structure" の形式の文字列からサイズの段階を解決します。
+// 各段階は "上限:出力方法" で、上限は小さい順に指定します。最後の段階の上限には上限なしを表す "*" を指定できます。
+// 空文字列は段階なし（すべて ContentFull）として扱います
+func ParseSizeTiers(s string) (SizeTiers, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var tiers SizeTiers
+	for _, item := range strings.Split(s, ",") {
+		if len(tiers) > 0 && tiers[len(tiers)-1].MaxSize == 0 {
+			return nil, fmt.Errorf("サイズの段階が不正です: 上限なし（*）の段階の後に %q は指定できません", strings.TrimSpace(item))
+		}
+		limit, modeName, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("サイズの段階が不正です: %q（\"上限:出力方法\" の形式で指定してください）", strings.TrimSpace(item))
+		}
+		mode, err := ParseContentMode(modeName)
+		if err != nil {
+			return nil, err
+		}
+		tier := SizeTier{Mode: mode}
+		if limit = strings.TrimSpace(limit); limit != "*" {
+			if tier.MaxSize, err = ParseByteSize(limit); err != nil {
+				return nil, fmt.Errorf("サイズの段階が不正です: %w", err)
+			}
+			if len(tiers) > 0 && tier.MaxSize <= tiers[len(tiers)-1].MaxSize {
+				return nil, fmt.Errorf("サイズの段階が不正です: 上限 %s は前の段階より大きくしてください", limit)
+			}
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// ParseByteSize は "64KB" や "1.5MB" のような単位付きのサイズをバイト数に変換します。単位は 1024 倍ごとの B, KB, MB, GB で、省略した場合はバイトです
+func ParseByteSize(s string) (int64, error) {
+	upper := strings.ToUpper(s)
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			multiplier = unit.size
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("サイズ %q は正の数に B, KB, MB, GB のいずれかの単位を付けて指定してください", s)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// sizeTiers は適用するサイズの段階を返します。
+// MaxContentSize が指定されている場合は、SizeTiers のうち MaxContentSize 以下の部分に、それを超えるファイルを ContentSkip とする段階を加えます
+func (g *Generator) sizeTiers() SizeTiers {
+	limit := g.options.MaxContentSize
+	if limit <= 0 {
+		return g.options.SizeTiers
+	}
+	tiers := make(SizeTiers, 0, len(g.options.SizeTiers)+1)
+	for _, tier := range g.options.SizeTiers {
+		if tier.MaxSize == 0 || tier.MaxSize >= limit {
+			tiers = append(tiers, SizeTier{MaxSize: limit, Mode: tier.Mode})
+			break
+		}
+		tiers = append(tiers, tier)
+	}
+	if len(tiers) == 0 || tiers[len(tiers)-1].MaxSize != limit {
+		tiers = append(tiers, SizeTier{MaxSize: limit, Mode: ContentFull})
+	}
+	return append(tiers, SizeTier{Mode: ContentSkip})
+}
+
+// contentMode はテキストファイルの内容の出力方法と、その段階の下限（前の段階の上限、最初の段階の場合は 0）を返します。
+// パスごとのルールで出力方法が指定されている場合（entry.ContentMode）は、サイズの段階より優先します。
+// バイナリファイルの扱いは BinaryPolicy で決まるため、バイナリファイルには常に ContentFull を返します
+func (g *Generator) contentMode(entry model.FileSystemEntry) (ContentMode, int64) {
+	if entry.IsDir || entry.IsBinary {
+		return ContentFull, 0
+	}
+	if entry.ContentMode != "" {
+		if mode, err := ParseContentMode(entry.ContentMode); err == nil {
+			return mode, 0
+		}
+	}
+	var lower int64
+	for _, tier := range g.sizeTiers() {
+		if tier.MaxSize == 0 || entry.Size <= tier.MaxSize {
+			return tier.Mode, lower
+		}
+		lower = tier.MaxSize
+	}
+	return ContentFull, 0
+}
+
+// isExcerpt はファイル内容の一部のみ（変更箇所、先頭と末尾、または宣言）を出力するかどうかを返します。
+// 一部のみを出力するファイルには行番号と指標を付けません
+func (g *Generator) isExcerpt(entry model.FileSystemEntry) bool {
+	if _, isHunks := g.hunksOf(entry); isHunks {
+		return true
+	}
+	mode, _ := g.contentMode(entry)
+	return mode == ContentHeadTail || mode == ContentOutline
+}
+
+// sizeNotice はサイズの段階またはパスごとのルールにより内容を出力しないファイルの注記を返します
+func sizeNotice(entry model.FileSystemEntry, mode ContentMode, lower int64) string {
+	action := "スキップ"
+	if mode == ContentStructure {
+		action = "構成にのみ表示"
+	}
+	if entry.ContentMode != "" {
+		return fmt.Sprintf("[ルールの指定により%s]", action)
+	}
+	if lower == 0 {
+		return fmt.Sprintf("[ファイルサイズ（%s）のため%s]", FormatSize(entry.Size), action)
+	}
+	return fmt.Sprintf("[ファイルサイズ（%s）が上限（%s）を超えるため%s]", FormatSize(entry.Size), FormatSize(lower), action)
+}
+
+// headTailLines は ContentHeadTail で出力する先頭と末尾の行数を返します
+func (g *Generator) headTailLines() (head, tail int) {
+	head, tail = g.options.HeadLines, g.options.TailLines
+	if head <= 0 {
+		head = HeadLines
+	}
+	if tail <= 0 {
+		tail = TailLines
+	}
+	return head, tail
+}
+
+// headTail は content の先頭 head 行と末尾 tail 行を残し、間を省略した行数を記載した行に置き換えます。
+// 行数が head + tail 以下の場合はそのまま返します
+func headTail(content []byte, head, tail int) []byte {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" { // 末尾の改行の後は行として数えない
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= head+tail {
+		return content
+	}
+	omitted := len(lines) - head - tail
+	var b strings.Builder
+	for _, line := range lines[:head] {
+		b.WriteString(line)
+	}
+	fmt.Fprintf(&b, "... 中略（%d〜%d 行目、%d 行） ...\n", head+1, head+omitted, omitted)
+	for _, line := range lines[len(lines)-tail:] {
+		b.WriteString(line)
+	}
+	return []byte(b.String())
+}
+
+// estimatedSize はファイルを読み込まずに、内容の出力方法に応じて出力する内容のサイズを見積もります
+func (g *Generator) estimatedSize(entry model.FileSystemEntry, mode ContentMode) int64 {
+	if mode == ContentHeadTail || mode == ContentOutline {
+		head, tail := g.headTailLines()
+		return min(entry.Size, int64(head+tail+1)*estimatedLineBytes)
+	}
+	return entry.Size
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestParseSizeTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    SizeTiers
+		wantErr string
+	}{
+		{name: "空文字列", input: "", want: nil},
+		{
+			name:  "上限なしの段階で終わる",
+			input: "64KB:full, 1MB:headtail, *:structure",
+			want:  SizeTiers{{MaxSize: 64 << 10, Mode: ContentFull}, {MaxSize: 1 << 20, Mode: ContentHeadTail}, {Mode: ContentStructure}},
+		},
+		{
+			name:  "小数と単位なし",
+			input: "512:full,1.5mb:skip",
+			want:  SizeTiers{{MaxSize: 512, Mode: ContentFull}, {MaxSize: 1536 << 10, Mode: ContentSkip}},
+		},
+		{name: "区切りがない", input: "64KB", wantErr: "\"上限:出力方法\" の形式"},
+		{name: "未対応の出力方法", input: "64KB:all", wantErr: "未対応の内容の出力方法です: all"},
+		{name: "不正なサイズ", input: "64XB:full", wantErr: "正の数に B, KB, MB, GB"},
+		{name: "上限が小さい順ではない", input: "1MB:full,64KB:skip", wantErr: "前の段階より大きく"},
+		{name: "上限なしの後に段階", input: "*:full,1MB:skip", wantErr: "上限なし（*）の段階の後"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSizeTiers(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseSizeTiers(%q) error = %v, want %q を含むエラー", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSizeTiers(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_ContentMode(t *testing.T) {
+	tiers := SizeTiers{{MaxSize: 100, Mode: ContentFull}, {MaxSize: 1000, Mode: ContentHeadTail}, {Mode: ContentStructure}}
+	tests := []struct {
+		name      string
+		options   Options
+		entry     model.FileSystemEntry
+		want      ContentMode
+		wantLower int64
+	}{
+		{name: "段階なし", options: Options{}, entry: model.FileSystemEntry{Size: 1 << 30}, want: ContentFull},
+		{name: "上限ちょうど", options: Options{SizeTiers: tiers}, entry: model.FileSystemEntry{Size: 100}, want: ContentFull},
+		{name: "2 段階目", options: Options{SizeTiers: tiers}, entry: model.FileSystemEntry{Size: 101}, want: ContentHeadTail, wantLower: 100},
+		{name: "上限なしの段階", options: Options{SizeTiers: tiers}, entry: model.FileSystemEntry{Size: 5000}, want: ContentStructure, wantLower: 1000},
+		{name: "ルールの指定を優先", options: Options{SizeTiers: tiers, MaxContentSize: 500}, entry: model.FileSystemEntry{Size: 5000, ContentMode: "full"}, want: ContentFull},
+		{name: "バイナリは対象外", options: Options{SizeTiers: tiers}, entry: model.FileSystemEntry{Size: 5000, IsBinary: true}, want: ContentFull},
+		{name: "MaxContentSize のみ", options: Options{MaxContentSize: 100}, entry: model.FileSystemEntry{Size: 101}, want: ContentSkip, wantLower: 100},
+		{name: "MaxContentSize で段階を打ち切る", options: Options{SizeTiers: tiers, MaxContentSize: 500}, entry: model.FileSystemEntry{Size: 400}, want: ContentHeadTail, wantLower: 100},
+		{name: "MaxContentSize を超える", options: Options{SizeTiers: tiers, MaxContentSize: 500}, entry: model.FileSystemEntry{Size: 501}, want: ContentSkip, wantLower: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, lower := NewGeneratorWithOptions(tt.options).contentMode(tt.entry)
+			if got != tt.want || lower != tt.wantLower {
+				t.Errorf("contentMode() = %s, %d, want %s, %d", got, lower, tt.want, tt.wantLower)
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteReport_SizeTiers(t *testing.T) {
+	dir := t.TempDir()
+	var long strings.Builder
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+	files := map[string]string{
+		"small.txt":  "small content\n",
+		"medium.txt": long.String(),
+		"large.txt":  strings.Repeat("x", 4000),
+	}
+	var entries []model.FileSystemEntry
+	for _, name := range []string{"large.txt", "medium.txt", "small.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, model.FileSystemEntry{Path: path, RelPath: name, Size: int64(len(files[name]))})
+	}
+
+	generator := NewGeneratorWithOptions(Options{
+		SizeTiers:   SizeTiers{{MaxSize: 100, Mode: ContentFull}, {MaxSize: 3000, Mode: ContentHeadTail}, {Mode: ContentStructure}},
+		LineNumbers: true,
+	})
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"[FILE] large.txt",
+		"1 | small content",
+		"line 60\n... 中略（61〜180 行目、120 行） ...\nline 181\n",
+		"line 200\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("出力に %q が含まれていません:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"----- large.txt -----", "line 61\n", "| line 1\n"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("出力に %q が含まれています:\n%s", unwanted, output)
+		}
+	}
+}
+
+func TestGenerator_WriteReport_HeadTailLines(t *testing.T) {
+	var lines strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&lines, "line %d\n", i)
+	}
+	content := lines.String()
+	fsys := fstest.MapFS{"app.log": {Data: []byte(content)}}
+	entries := []model.FileSystemEntry{{RelPath: "app.log", Size: int64(len(content))}}
+
+	generator := NewGeneratorWithOptions(Options{SizeTiers: SizeTiers{{Mode: ContentHeadTail}}, HeadLines: 3, TailLines: 2}).WithFS(fsys)
+	var buf strings.Builder
+	if err := generator.WriteReport(&buf, entries); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	want := "line 1\nline 2\nline 3\n... 中略（4〜8 行目、5 行） ...\nline 9\nline 10\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("出力に %q が含まれていません:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// SortKey はフォルダ構成で同じフォルダ内のエントリを並べる基準です
+type SortKey string
+
+const (
+	// SortPath は名前の順（バイト順）に並べます
+	SortPath SortKey = "path"
+	// SortSize はサイズの大きい順に並べます。フォルダは配下のファイルの合計サイズで比較します
+	SortSize SortKey = "size"
+	// SortModTime は更新日時の新しい順に並べます。フォルダは配下で最も新しい更新日時で比較します
+	SortModTime SortKey = "mtime"
+)
+
+// ParseSortKey は文字列から並び順の基準を解決します。空文字列は名前の順として扱います
+func ParseSortKey(s string) (SortKey, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "path", "name":
+		return SortPath, nil
+	case "size":
+		return SortSize, nil
+	case "mtime", "time", "modtime":
+		return SortModTime, nil
+	}
+	return "", fmt.Errorf("未対応の並び順です: %s（path, size, mtime のいずれかを指定してください）", s)
+}
+
+// sortNode は並べ替えに使用するフォルダ構成の節です
+type sortNode struct {
+	entry    model.FileSystemEntry
+	children []*sortNode
+	// size と modTime は比較に使用する値です。フォルダの場合は配下の集計値です
+	size    int64
+	modTime time.Time
+}
+
+// SortEntries は、フォルダ構成の階層（フォルダの直後にその配下が続く順）を保ったまま、同じフォルダ内のエントリを key の順に並べ替えた一覧を返します。
+// dirsFirst が true の場合は、同じフォルダ内でフォルダをファイルより先に置きます。
+// 基準の値が等しいエントリは名前の順に並べるため、同じ内容のフォルダからは常に同じ順の一覧が得られます。
+// スキャンの順（アーカイブの格納順など）に依存せず、2 回の実行で作成したレポートを比較しやすくするために使用します
+func SortEntries(entries []model.FileSystemEntry, key SortKey, dirsFirst bool) []model.FileSystemEntry {
+	nodes := make(map[string]*sortNode, len(entries))
+	for _, entry := range entries {
+		n := &sortNode{entry: entry, modTime: entry.ModTime}
+		if !entry.IsDir {
+			n.size = entry.Size
+		}
+		nodes[entry.RelPath] = n
+	}
+	root := &sortNode{}
+	for _, entry := range entries {
+		parent := root
+		// 親フォルダが一覧にない場合（絞り込みで除かれた場合など）は最上位に置く
+		if p, ok := nodes[path.Dir(entry.RelPath)]; ok && p.entry.IsDir {
+			parent = p
+		}
+		parent.children = append(parent.children, nodes[entry.RelPath])
+	}
+	root.aggregate()
+	root.sortChildren(key, dirsFirst)
+
+	sorted := make([]model.FileSystemEntry, 0, len(entries))
+	var walk func(n *sortNode)
+	walk = func(n *sortNode) {
+		for _, child := range n.children {
+			sorted = append(sorted, child.entry)
+			walk(child)
+		}
+	}
+	walk(root)
+	return sorted
+}
+
+// aggregate はフォルダの比較に使用する、配下のファイルの合計サイズと最も新しい更新日時を算出します
+func (n *sortNode) aggregate() {
+	for _, child := range n.children {
+		child.aggregate()
+		n.size += child.size
+		if child.modTime.After(n.modTime) {
+			n.modTime = child.modTime
+		}
+	}
+}
+
+// sortChildren は配下のエントリを再帰的に並べ替えます
+func (n *sortNode) sortChildren(key SortKey, dirsFirst bool) {
+	sort.SliceStable(n.children, func(i, j int) bool {
+		a, b := n.children[i], n.children[j]
+		if dirsFirst && a.entry.IsDir != b.entry.IsDir {
+			return a.entry.IsDir
+		}
+		switch {
+		case key == SortSize && a.size != b.size:
+			return a.size > b.size
+		case key == SortModTime && !a.modTime.Equal(b.modTime):
+			return a.modTime.After(b.modTime)
+		}
+		return path.Base(a.entry.RelPath) < path.Base(b.entry.RelPath)
+	})
+	for _, child := range n.children {
+		child.sortChildren(key, dirsFirst)
+	}
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestSortEntries(t *testing.T) {
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	// アーカイブの格納順のように、名前の順ではないスキャン結果
+	entries := []model.FileSystemEntry{
+		{RelPath: "z.txt", Size: 10, ModTime: base},
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/small.go", Depth: 1, Size: 1, ModTime: base.Add(3 * time.Hour)},
+		{RelPath: "src/big.go", Depth: 1, Size: 100, ModTime: base},
+		{RelPath: "a.txt", Size: 50, ModTime: base.Add(time.Hour)},
+		{RelPath: "docs", IsDir: true},
+		{RelPath: "docs/readme.md", Depth: 1, Size: 5, ModTime: base.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		key       SortKey
+		dirsFirst bool
+		want      string
+	}{
+		{name: "名前の順", key: SortPath, want: "a.txt,docs,docs/readme.md,src,src/big.go,src/small.go,z.txt"},
+		{name: "フォルダを先に", key: SortPath, dirsFirst: true, want: "docs,docs/readme.md,src,src/big.go,src/small.go,a.txt,z.txt"},
+		{name: "サイズの大きい順（フォルダは合計）", key: SortSize, want: "src,src/big.go,src/small.go,a.txt,z.txt,docs,docs/readme.md"},
+		{name: "更新日時の新しい順（フォルダは最新）", key: SortModTime, want: "src,src/small.go,src/big.go,docs,docs/readme.md,a.txt,z.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range SortEntries(entries, tt.key, tt.dirsFirst) {
+				got = append(got, entry.RelPath)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("SortEntries() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	for input, want := range map[string]SortKey{"": SortPath, "Size": SortSize, " mtime ": SortModTime} {
+		if got, err := ParseSortKey(input); err != nil || got != want {
+			t.Errorf("ParseSortKey(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseSortKey("random"); err == nil {
+		t.Error("未対応の並び順はエラーになるべきです")
+	}
+}
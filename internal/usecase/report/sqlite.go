@@ -0,0 +1,50 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// SQLiteWriter は sqlite 形式のレポートを writer に出力する関数です
+type SQLiteWriter func(g *Generator, writer io.Writer, entries []model.FileSystemEntry) error
+
+// sqliteWriter は RegisterSQLiteWriter で登録された sqlite 形式の出力です
+var sqliteWriter SQLiteWriter
+
+// RegisterSQLiteWriter は sqlite 形式の出力を登録します。
+// SQLite のドライバーは依存するパッケージが大きく、対応していないプラットフォームもあるため、report パッケージには含めず、
+// 必要とするコマンドから登録します。登録されていない場合、sqlite 形式の WriteReport はエラーを返します
+func RegisterSQLiteWriter(w SQLiteWriter) {
+	sqliteWriter = w
+}
+
+// writeSQLite は登録された sqlite 形式の出力で、スキャン結果を writer に出力します
+func (g *Generator) writeSQLite(writer io.Writer, entries []model.FileSystemEntry) error {
+	if sqliteWriter == nil {
+		return errors.New("sqlite 形式の出力は利用できません")
+	}
+	return sqliteWriter(g, writer, entries)
+}
+
+// Export はデータ形式のエクスポートと同じ内容（正規化とマスクを適用したもの）を、report パッケージの外で実装する出力形式に渡します。
+// 先に生成日時（正規化した場合はゼロ値）と統計情報を start に、続けて各エントリを entry に渡します。
+// start・entry がエラーを返した場合は、以降を中止してそのエラーを返します
+func (g *Generator) Export(entries []model.FileSystemEntry, start func(generatedAt time.Time, stats ExportStats) error, entry func(ExportEntry) error) error {
+	if g.options.Normalize {
+		g, entries = g.normalized(entries)
+	}
+	g = g.withOutputLog()
+	if err := start(g.generatedAt(), g.computeExportStats(entries)); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		// 内容はエクスポートと同じ方法で読み込むため、マスク・フィルター・サイズの段階も同様に適用される
+		if err := entry(g.newExportEntry(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
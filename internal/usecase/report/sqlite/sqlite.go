@@ -0,0 +1,192 @@
+// Package sqlite はスキャン結果を SQLite データベースに記録する、sqlite 形式のレポートの出力です。
+// パッケージを読み込むと report パッケージに sqlite 形式の出力を登録します
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	// database/sql に "sqlite" ドライバーを登録する（cgo を必要としない実装）
+	_ "modernc.org/sqlite"
+
+	"FolderScope/internal/domain/apperrors"
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/usecase/report"
+)
+
+func init() {
+	report.RegisterSQLiteWriter(Write)
+}
+
+// DatabaseName は sqlite 形式で出力先フォルダに作成するデータベースのファイル名です。
+// 実行のたびに同じデータベースへスキャン結果を追加するため、過去のスキャンと SQL で比較できます
+const DatabaseName = "folderscope.sqlite"
+
+// schema は sqlite 形式のデータベースのテーブル定義です。
+// scans に 1 回のスキャンを 1 行で記録し、entries と contents は scan_id で scans を参照します
+const schema = `
+CREATE TABLE IF NOT EXISTS scans (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	generated_at TEXT,
+	file_count   INTEGER NOT NULL,
+	dir_count    INTEGER NOT NULL,
+	total_bytes  INTEGER NOT NULL,
+	stats        TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS entries (
+	scan_id     INTEGER NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+	rel_path    TEXT NOT NULL,
+	is_dir      INTEGER NOT NULL,
+	size        INTEGER NOT NULL,
+	mod_time    TEXT,
+	permissions TEXT NOT NULL,
+	hash        TEXT,
+	is_binary   INTEGER NOT NULL,
+	mime_type   TEXT,
+	encoding    TEXT,
+	line_ending TEXT,
+	authors     TEXT,
+	PRIMARY KEY (scan_id, rel_path)
+);
+CREATE TABLE IF NOT EXISTS contents (
+	scan_id  INTEGER NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+	rel_path TEXT NOT NULL,
+	content  TEXT,
+	notice   TEXT,
+	PRIMARY KEY (scan_id, rel_path)
+);
+`
+
+// Append は path の SQLite データベースに generator の設定でスキャン結果を 1 回分追加し、追加したスキャンの ID を返します。
+// データベースが存在しない場合は作成します。ファイルの内容は contents テーブルに、内容を出力できない場合は notice に理由を記録します
+func Append(generator *report.Generator, path string, entries []model.FileSystemEntry) (int64, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return 0, apperrors.Wrap("データベースを開けませんでした", path, err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(schema); err != nil {
+		return 0, apperrors.Wrap("データベースのテーブルの作成に失敗しました", path, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("データベースへの書き込みの開始に失敗しました: %w", err)
+	}
+	scanID, err := insertScan(tx, generator, entries)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("スキャン結果のデータベースへの書き込みに失敗しました: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("スキャン結果のデータベースへの書き込みに失敗しました: %w", err)
+	}
+	return scanID, nil
+}
+
+// insertScan は scans に 1 行を追加し、その ID で entries と contents にエントリを追加します
+func insertScan(tx *sql.Tx, generator *report.Generator, entries []model.FileSystemEntry) (int64, error) {
+	var (
+		scanID        int64
+		insertEntry   *sql.Stmt
+		insertContent *sql.Stmt
+	)
+	defer func() {
+		if insertEntry != nil {
+			insertEntry.Close()
+		}
+		if insertContent != nil {
+			insertContent.Close()
+		}
+	}()
+
+	start := func(generatedAt time.Time, stats report.ExportStats) error {
+		statsJSON, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		var generated any
+		if !generatedAt.IsZero() {
+			generated = generatedAt.Format(time.RFC3339)
+		}
+		result, err := tx.Exec(`INSERT INTO scans (generated_at, file_count, dir_count, total_bytes, stats) VALUES (?, ?, ?, ?, ?)`,
+			generated, stats.TotalFiles, stats.TotalDirs, stats.TotalBytes, string(statsJSON))
+		if err != nil {
+			return err
+		}
+		if scanID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		insertEntry, err = tx.Prepare(`INSERT INTO entries (scan_id, rel_path, is_dir, size, mod_time, permissions, hash, is_binary, mime_type, encoding, line_ending, authors)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		insertContent, err = tx.Prepare(`INSERT INTO contents (scan_id, rel_path, content, notice) VALUES (?, ?, ?, ?)`)
+		return err
+	}
+
+	entry := func(e report.ExportEntry) error {
+		var modTime any
+		if e.ModTime != nil {
+			modTime = e.ModTime.Format(time.RFC3339)
+		}
+		if _, err := insertEntry.Exec(scanID, e.RelPath, e.IsDir, e.Size, modTime, e.Permissions,
+			nullString(e.Hash), e.IsBinary, nullString(e.MIMEType), nullString(e.Encoding), nullString(e.LineEnding), nullString(e.Authors)); err != nil {
+			return fmt.Errorf("'%s': %w", e.RelPath, err)
+		}
+		if e.IsDir {
+			return nil
+		}
+		var content any
+		if e.Content != nil {
+			content = *e.Content
+		}
+		if _, err := insertContent.Exec(scanID, e.RelPath, content, nullString(e.Notice)); err != nil {
+			return fmt.Errorf("'%s': %w", e.RelPath, err)
+		}
+		return nil
+	}
+
+	if err := generator.Export(entries, start, entry); err != nil {
+		return 0, err
+	}
+	return scanID, nil
+}
+
+// nullString は空文字列を NULL として記録するための値を返します
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// Write はスキャン結果を 1 回分だけ含む SQLite データベースを作成し、その内容を writer に出力します。
+// SQLite はファイルに対してのみ書き込めるため、一時ファイルに作成してから書き出します
+func Write(generator *report.Generator, writer io.Writer, entries []model.FileSystemEntry) error {
+	tmp, err := os.CreateTemp("", "folderscope-*.sqlite")
+	if err != nil {
+		return fmt.Errorf("一時ファイルの作成に失敗しました: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	if _, err := Append(generator, tmpPath, entries); err != nil {
+		return err
+	}
+	file, err := os.Open(tmpPath)
+	if err != nil {
+		return apperrors.Wrap("データベースの読み込みに失敗しました", tmpPath, err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(writer, file); err != nil {
+		return fmt.Errorf("データベースの書き出しに失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/usecase/report"
+)
+
+func TestAppend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	entries := []model.FileSystemEntry{
+		{Path: dir, RelPath: "docs", IsDir: true},
+		{Path: path, RelPath: "a.txt", Size: 5, Hash: "abc"},
+		{Path: filepath.Join(dir, "missing.bin"), RelPath: "missing.bin", Size: 10, IsBinary: true},
+	}
+	dbPath := filepath.Join(dir, DatabaseName)
+	generator := report.NewGeneratorWithOptions(report.Options{Format: report.FormatSQLite})
+
+	// 2 回のスキャンを同じデータベースに追加し、変更されたファイルを SQL で取り出せる
+	for i, content := range []string{"first", "again"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		scanID, err := Append(generator, dbPath, entries)
+		if err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if scanID != int64(i+1) {
+			t.Errorf("スキャンの ID = %d, want %d", scanID, i+1)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var scans, files, dirs int
+	if err := db.QueryRow(`SELECT COUNT(*), MAX(file_count), MAX(dir_count) FROM scans`).Scan(&scans, &files, &dirs); err != nil {
+		t.Fatal(err)
+	}
+	if scans != 2 || files != 2 || dirs != 1 {
+		t.Errorf("scans = %d 件, ファイル数 %d, フォルダ数 %d, want 2, 2, 1", scans, files, dirs)
+	}
+
+	var relPath, oldContent, newContent string
+	err = db.QueryRow(`SELECT n.rel_path, o.content, n.content FROM contents n
+		JOIN contents o ON o.rel_path = n.rel_path AND o.scan_id = 1
+		WHERE n.scan_id = 2 AND o.content IS NOT n.content`).Scan(&relPath, &oldContent, &newContent)
+	if err != nil || relPath != "a.txt" || oldContent != "first" || newContent != "again" {
+		t.Errorf("変更されたファイル = %q, %q → %q, %v", relPath, oldContent, newContent, err)
+	}
+
+	var hash, notice sql.NullString
+	var isDir bool
+	if err := db.QueryRow(`SELECT e.is_dir, e.hash, c.notice FROM entries e LEFT JOIN contents c USING (scan_id, rel_path) WHERE e.scan_id = 2 AND e.rel_path = 'missing.bin'`).Scan(&isDir, &hash, &notice); err != nil {
+		t.Fatal(err)
+	}
+	if isDir || hash.Valid || !notice.Valid || notice.String == "" {
+		t.Errorf("missing.bin: is_dir = %v, hash = %v, notice = %v, want 内容を出力しない理由", isDir, hash, notice)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := report.NewGeneratorWithOptions(report.Options{Format: report.FormatSQLite}).WriteReport(&buf, []model.FileSystemEntry{{RelPath: "docs", IsDir: true}}); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("SQLite format 3\x00")) {
+		t.Errorf("SQLite のデータベースではありません: %q", buf.Bytes()[:min(buf.Len(), 16)])
+	}
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_WriteReport_SQLiteNotRegistered(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewGeneratorWithOptions(Options{Format: FormatSQLite}).WriteReport(&buf, []model.FileSystemEntry{{RelPath: "docs", IsDir: true}})
+	if err == nil {
+		t.Error("sqlite 形式の出力が登録されていない場合はエラーを返すべきです")
+	}
+}
+
+func TestGenerator_Export(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "docs", IsDir: true},
+		{RelPath: "docs/b.txt", IsBinary: true},
+	}
+	var (
+		stats ExportStats
+		paths []string
+	)
+	err := NewGeneratorWithOptions(Options{Normalize: true}).Export(entries,
+		func(generatedAt time.Time, s ExportStats) error {
+			if !generatedAt.IsZero() {
+				t.Errorf("正規化した場合の生成日時 = %v, want ゼロ値", generatedAt)
+			}
+			stats = s
+			return nil
+		},
+		func(e ExportEntry) error {
+			paths = append(paths, e.RelPath)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if stats.TotalFiles != 1 || stats.TotalDirs != 1 {
+		t.Errorf("統計情報 = %d ファイル, %d フォルダ, want 1, 1", stats.TotalFiles, stats.TotalDirs)
+	}
+	if len(paths) != 2 || paths[0] != "docs" || paths[1] != "docs/b.txt" {
+		t.Errorf("エントリ = %v", paths)
+	}
+}
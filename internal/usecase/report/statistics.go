@@ -0,0 +1,285 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// DefaultLargestFiles はサマリーに表示する最大ファイルの件数です
+const DefaultLargestFiles = 10
+
+// NoExtension は拡張子のないファイルを集計する際の表示名です
+const NoExtension = "(拡張子なし)"
+
+// ExtensionStats は拡張子ごとのファイル数と合計サイズです
+type ExtensionStats struct {
+	// Extension は小文字の拡張子（先頭のドットを含む）です
+	Extension string `json:"extension"`
+	// Files はファイル数です
+	Files int `json:"files"`
+	// Bytes は合計サイズ（バイト）です
+	Bytes int64 `json:"bytes"`
+}
+
+// LargestFile はサマリーに表示する最大ファイルの 1 件です。
+// エクスポートに絶対パスや権限などを含めないよう、相対パスとサイズのみを持ちます
+type LargestFile struct {
+	// RelPath はスキャンのルートからの相対パスです
+	RelPath string `json:"relPath"`
+	// Size はファイルサイズ（バイト）です
+	Size int64 `json:"size"`
+}
+
+// Statistics はエントリ一覧から算出したフォルダ全体の統計情報です
+type Statistics struct {
+	// TotalFiles はファイル数です
+	TotalFiles int `json:"totalFiles"`
+	// TotalDirs はディレクトリ数です
+	TotalDirs int `json:"totalDirs"`
+	// TotalBytes はファイルの合計サイズ（バイト）です
+	TotalBytes int64 `json:"totalBytes"`
+	// LargestFiles はサイズの大きい順に並べたファイルです
+	LargestFiles []LargestFile `json:"largestFiles"`
+	// Extensions は合計サイズの大きい順に並べた拡張子ごとの統計です
+	Extensions []ExtensionStats `json:"extensions"`
+}
+
+// ComputeStatistics はエントリ一覧から統計情報を算出します。
+// largest には LargestFiles に含める最大件数を指定します
+func ComputeStatistics(entries []model.FileSystemEntry, largest int) Statistics {
+	var stats Statistics
+	byExt := make(map[string]*ExtensionStats)
+	files := make([]model.FileSystemEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir {
+			stats.TotalDirs++
+			continue
+		}
+		stats.TotalFiles++
+		stats.TotalBytes += entry.Size
+		files = append(files, entry)
+
+		ext := strings.ToLower(path.Ext(entry.RelPath))
+		if ext == "" {
+			ext = NoExtension
+		}
+		if byExt[ext] == nil {
+			byExt[ext] = &ExtensionStats{Extension: ext}
+		}
+		byExt[ext].Files++
+		byExt[ext].Bytes += entry.Size
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Size != files[j].Size {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].RelPath < files[j].RelPath
+	})
+	if largest >= 0 && len(files) > largest {
+		files = files[:largest]
+	}
+	stats.LargestFiles = make([]LargestFile, len(files))
+	for i, entry := range files {
+		stats.LargestFiles[i] = LargestFile{RelPath: entry.RelPath, Size: entry.Size}
+	}
+
+	stats.Extensions = make([]ExtensionStats, 0, len(byExt))
+	for _, ext := range byExt {
+		stats.Extensions = append(stats.Extensions, *ext)
+	}
+	sort.Slice(stats.Extensions, func(i, j int) bool {
+		a, b := stats.Extensions[i], stats.Extensions[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Extension < b.Extension
+	})
+	return stats
+}
+
+// writeSummary は統計情報のサマリーを出力形式に応じて出力します
+func (g *Generator) writeSummary(writer io.Writer, stats Statistics) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		writeMarkdownSummary(writer, stats)
+	case FormatHTML:
+		writeHTMLSummary(writer, stats)
+	default:
+		writeTextSummary(writer, stats)
+	}
+}
+
+// writeTextSummary は統計情報をテキスト形式で出力します
+func writeTextSummary(writer io.Writer, stats Statistics) {
+	fmt.Fprintln(writer, "===== サマリー =====")
+	fmt.Fprintf(writer, "ファイル数: %d\n", stats.TotalFiles)
+	fmt.Fprintf(writer, "ディレクトリ数: %d\n", stats.TotalDirs)
+	fmt.Fprintf(writer, "合計サイズ: %s\n", FormatSize(stats.TotalBytes))
+
+	if len(stats.LargestFiles) > 0 {
+		fmt.Fprintln(writer, "\n最大ファイル:")
+		for _, entry := range stats.LargestFiles {
+			fmt.Fprintf(writer, "  %10s  %s\n", FormatSize(entry.Size), entry.RelPath)
+		}
+	}
+	if len(stats.Extensions) > 0 {
+		fmt.Fprintln(writer, "\n拡張子別:")
+		for _, ext := range stats.Extensions {
+			fmt.Fprintf(writer, "  %-16s %6d files  %10s\n", ext.Extension, ext.Files, FormatSize(ext.Bytes))
+		}
+	}
+	fmt.Fprintln(writer)
+}
+
+// writeMarkdownSummary は統計情報を Markdown の表として出力します
+func writeMarkdownSummary(writer io.Writer, stats Statistics) {
+	fmt.Fprintln(writer, "## サマリー")
+	fmt.Fprintln(writer)
+	fmt.Fprintf(writer, "- ファイル数: %d\n", stats.TotalFiles)
+	fmt.Fprintf(writer, "- ディレクトリ数: %d\n", stats.TotalDirs)
+	fmt.Fprintf(writer, "- 合計サイズ: %s\n", FormatSize(stats.TotalBytes))
+
+	if len(stats.LargestFiles) > 0 {
+		fmt.Fprintln(writer, "\n### 最大ファイル")
+		fmt.Fprintln(writer, "\n| サイズ | パス |\n|---:|---|")
+		for _, entry := range stats.LargestFiles {
+			fmt.Fprintf(writer, "| %s | %s |\n", FormatSize(entry.Size), escapeMarkdown(entry.RelPath))
+		}
+	}
+	if len(stats.Extensions) > 0 {
+		fmt.Fprintln(writer, "\n### 拡張子別")
+		fmt.Fprintln(writer, "\n| 拡張子 | ファイル数 | 合計サイズ |\n|---|---:|---:|")
+		for _, ext := range stats.Extensions {
+			fmt.Fprintf(writer, "| %s | %d | %s |\n", escapeMarkdown(ext.Extension), ext.Files, FormatSize(ext.Bytes))
+		}
+	}
+	fmt.Fprintln(writer)
+}
+
+// writeHTMLSummary は統計情報を HTML の表として出力します
+func writeHTMLSummary(writer io.Writer, stats Statistics) {
+	fmt.Fprintln(writer, "<h2>サマリー</h2>")
+	fmt.Fprintln(writer, "<ul>")
+	fmt.Fprintf(writer, "<li>ファイル数: %d</li>\n", stats.TotalFiles)
+	fmt.Fprintf(writer, "<li>ディレクトリ数: %d</li>\n", stats.TotalDirs)
+	fmt.Fprintf(writer, "<li>合計サイズ: %s</li>\n", FormatSize(stats.TotalBytes))
+	fmt.Fprintln(writer, "</ul>")
+
+	if len(stats.LargestFiles) > 0 {
+		fmt.Fprintln(writer, "<h3>最大ファイル</h3>\n<table>\n<tr><th>サイズ</th><th>パス</th></tr>")
+		for _, entry := range stats.LargestFiles {
+			fmt.Fprintf(writer, "<tr><td>%s</td><td>%s</td></tr>\n", FormatSize(entry.Size), html.EscapeString(entry.RelPath))
+		}
+		fmt.Fprintln(writer, "</table>")
+	}
+	if len(stats.Extensions) > 0 {
+		fmt.Fprintln(writer, "<h3>拡張子別</h3>\n<table>\n<tr><th>拡張子</th><th>ファイル数</th><th>合計サイズ</th></tr>")
+		for _, ext := range stats.Extensions {
+			fmt.Fprintf(writer, "<tr><td>%s</td><td>%d</td><td>%s</td></tr>\n", html.EscapeString(ext.Extension), ext.Files, FormatSize(ext.Bytes))
+		}
+		fmt.Fprintln(writer, "</table>")
+	}
+}
+
+// LanguageTotal は言語別の統計で合計を表す行の名前です
+const LanguageTotal = "合計"
+
+// LanguageStats は言語ごとのファイル数と行数です
+type LanguageStats struct {
+	// Language は言語名です
+	Language string `json:"language"`
+	// Files はファイル数です
+	Files int `json:"files"`
+	// Blank は空行の数です
+	Blank int `json:"blank"`
+	// Comment はコメントのみの行の数です
+	Comment int `json:"comment"`
+	// Code はコード行の数です
+	Code int `json:"code"`
+}
+
+// computeLanguageStats はテキストファイルの内容を読み込み、言語ごとの行数を集計します。
+// 言語を判定できないファイル、バイナリファイル、読み込めないファイルは集計しません。
+// 結果はコード行の多い順に並べ、最後に合計の行を加えます
+func (g *Generator) computeLanguageStats(entries []model.FileSystemEntry) []LanguageStats {
+	byName := make(map[string]*LanguageStats)
+	for _, entry := range entries {
+		if entry.IsDir || entry.IsBinary || entry.ReadErr != nil || entry.IsLinkOnly() {
+			continue
+		}
+		content, err := g.readFile(entry)
+		if err != nil {
+			continue
+		}
+		if decoded, err := decodeText(content, entry.Encoding); err == nil {
+			content = decoded
+		}
+		lang := detectLanguage(entry.RelPath, content)
+		if lang == nil {
+			continue
+		}
+		m := measure(lang, content)
+		if byName[lang.name] == nil {
+			byName[lang.name] = &LanguageStats{Language: lang.name}
+		}
+		s := byName[lang.name]
+		s.Files++
+		s.Blank += m.Blank
+		s.Comment += m.Comment
+		s.Code += m.Code
+	}
+
+	stats := make([]LanguageStats, 0, len(byName)+1)
+	total := LanguageStats{Language: LanguageTotal}
+	for _, s := range byName {
+		stats = append(stats, *s)
+		total.Files += s.Files
+		total.Blank += s.Blank
+		total.Comment += s.Comment
+		total.Code += s.Code
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Code != stats[j].Code {
+			return stats[i].Code > stats[j].Code
+		}
+		return stats[i].Language < stats[j].Language
+	})
+	if len(stats) > 0 {
+		stats = append(stats, total)
+	}
+	return stats
+}
+
+// writeLanguageStats は言語別の統計を出力形式に応じて出力します
+func (g *Generator) writeLanguageStats(writer io.Writer, stats []LanguageStats) {
+	switch g.options.Format {
+	case FormatMarkdown:
+		fmt.Fprintln(writer, "## 言語別の統計")
+		fmt.Fprintln(writer, "\n| 言語 | ファイル数 | 空行 | コメント | コード |\n|---|---:|---:|---:|---:|")
+		for _, s := range stats {
+			fmt.Fprintf(writer, "| %s | %d | %d | %d | %d |\n", escapeMarkdown(s.Language), s.Files, s.Blank, s.Comment, s.Code)
+		}
+		fmt.Fprintln(writer)
+	case FormatHTML:
+		fmt.Fprintln(writer, "<h2>言語別の統計</h2>\n<table>\n<tr><th>言語</th><th>ファイル数</th><th>空行</th><th>コメント</th><th>コード</th></tr>")
+		for _, s := range stats {
+			fmt.Fprintf(writer, "<tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td></tr>\n", html.EscapeString(s.Language), s.Files, s.Blank, s.Comment, s.Code)
+		}
+		fmt.Fprintln(writer, "</table>")
+	default:
+		fmt.Fprintln(writer, "===== 言語別の統計 =====")
+		fmt.Fprintf(writer, "  %-16s %8s %8s %8s %8s\n", "language", "files", "blank", "comment", "code")
+		for _, s := range stats {
+			fmt.Fprintf(writer, "  %-16s %8d %8d %8d %8d\n", s.Language, s.Files, s.Blank, s.Comment, s.Code)
+		}
+		fmt.Fprintln(writer)
+	}
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestComputeStatistics(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "src", IsDir: true},
+		{RelPath: "src/main.go", Size: 300},
+		{RelPath: "src/util.go", Size: 200},
+		{RelPath: "README.MD", Size: 1000},
+		{RelPath: "Makefile", Size: 50},
+	}
+
+	stats := ComputeStatistics(entries, 2)
+
+	if stats.TotalFiles != 4 || stats.TotalDirs != 1 || stats.TotalBytes != 1550 {
+		t.Errorf("合計値が不正: %+v", stats)
+	}
+	if len(stats.LargestFiles) != 2 || stats.LargestFiles[0].RelPath != "README.MD" || stats.LargestFiles[1].RelPath != "src/main.go" {
+		t.Errorf("最大ファイルが不正: %+v", stats.LargestFiles)
+	}
+
+	want := []ExtensionStats{
+		{Extension: ".md", Files: 1, Bytes: 1000},
+		{Extension: ".go", Files: 2, Bytes: 500},
+		{Extension: NoExtension, Files: 1, Bytes: 50},
+	}
+	if len(stats.Extensions) != len(want) {
+		t.Fatalf("拡張子別の件数が不正: %+v", stats.Extensions)
+	}
+	for i := range want {
+		if stats.Extensions[i] != want[i] {
+			t.Errorf("拡張子別 %d が不正: got %+v, want %+v", i, stats.Extensions[i], want[i])
+		}
+	}
+}
+
+func TestGenerator_WriteReportSummary(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "dir", IsDir: true},
+		{RelPath: "dir/a.txt", Size: 2048, IsBinary: true},
+	}
+
+	for _, format := range SupportedFormats {
+		// データ形式は ShowSummary にかかわらず統計情報を含むため、export_test.go で検証する。
+		// PDF はテキスト形式を組版したもので、本文が圧縮されるため対象外とする。SQLite はサマリーを出力しない
+		if format.isData() || format == FormatPDF || format == FormatSQLite {
+			continue
+		}
+		t.Run(string(format), func(t *testing.T) {
+			var buf strings.Builder
+			NewGeneratorWithOptions(Options{Format: format, ShowSummary: true}).WriteReport(&buf, entries)
+			output := buf.String()
+
+			summaryPos := strings.Index(output, "サマリー")
+			structurePos := strings.Index(output, "フォルダ・ファイル構成")
+			if summaryPos < 0 || summaryPos > structurePos {
+				t.Errorf("サマリーが構成より前に出力されていません:\n%s", output)
+			}
+			for _, sub := range []string{"ファイル数: 1", "ディレクトリ数: 1", "2.0 KB", ".txt"} {
+				if !strings.Contains(output, sub) {
+					t.Errorf("出力に期待される部分文字列が含まれていない: %q\n%s", sub, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// builtinTemplates は組み込みのレポートテンプレートです。ファイル名から拡張子を除いたものがテンプレート名です
+//
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+const (
+	// templateExtension はテンプレートファイルの拡張子です
+	templateExtension = ".tmpl"
+	// TemplateDirName は組み込みのテンプレートを上書きするテンプレートを置く、ユーザー設定ディレクトリ配下のディレクトリ名です
+	TemplateDirName = "templates"
+)
+
+// BuiltinTemplateNames は組み込みのテンプレート名を名前の順に返します
+func BuiltinTemplateNames() []string {
+	files, _ := fs.Glob(builtinTemplates, "templates/*"+templateExtension)
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), templateExtension))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateFuncs はテンプレートで使用できる関数です
+var templateFuncs = template.FuncMap{
+	// size はバイト数を FormatSize と同じ形式（1.5 KB など）に整形します
+	"size": FormatSize,
+	// date は日時をローカル時刻の "2006-01-02 15:04:05" の形式に整形します
+	"date": func(t time.Time) string { return t.Local().Format(MetadataTimeLayout) },
+	// indent は各行の先頭に n 個の空白を付けます
+	"indent": func(n int, s string) string {
+		prefix := strings.Repeat(" ", n)
+		return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+	},
+	// fence は内容をMarkdownのコードブロックで囲むためのフェンスを返します
+	"fence":   codeFence,
+	"repeat":  func(s string, n int) string { return strings.Repeat(s, n) },
+	"replace": strings.ReplaceAll,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+}
+
+// ParseTemplate は text/template の形式のレポートテンプレートを解析します
+func ParseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートの解析に失敗しました: %w", err)
+	}
+	return t, nil
+}
+
+// LoadTemplate はテンプレート名、またはテンプレートファイルのパスからレポートテンプレートを読み込みます。
+// テンプレート名は userDir の "名前.tmpl"、組み込みのテンプレートの順に探すため、userDir に同じ名前のファイルを置くと組み込みのテンプレートを上書きできます。
+// userDir が空の場合は組み込みのテンプレートのみを探します。
+// テンプレート名と同じ名前のファイルを使用する場合は "./text" のようにパスとして指定してください
+func LoadTemplate(nameOrPath, userDir string) (*template.Template, error) {
+	if isTemplateName(nameOrPath) {
+		if userDir != "" {
+			data, err := os.ReadFile(filepath.Join(userDir, nameOrPath+templateExtension))
+			if err == nil {
+				return ParseTemplate(nameOrPath, string(data))
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("テンプレートファイルの読み込みに失敗しました: %w", err)
+			}
+		}
+		if data, err := builtinTemplates.ReadFile("templates/" + nameOrPath + templateExtension); err == nil {
+			return ParseTemplate(nameOrPath, string(data))
+		}
+	}
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートファイルの読み込みに失敗しました（組み込みのテンプレートは %s）: %w", strings.Join(BuiltinTemplateNames(), ", "), err)
+	}
+	return ParseTemplate(filepath.Base(nameOrPath), string(data))
+}
+
+// isTemplateName は s がパスではなくテンプレート名（区切り文字と拡張子を含まない名前）かどうかを返します
+func isTemplateName(s string) bool {
+	return s != "" && !strings.ContainsAny(s, `/\.`)
+}
+
+// WithTemplate はレポートを t で出力する Generator のコピーを返します。
+// テンプレートは人が読む形式（text, markdown, html）のレポート全体の構成に使用し、JSON/JSONL には影響しません
+func (g *Generator) WithTemplate(t *template.Template) *Generator {
+	copied := *g
+	copied.template = t
+	return &copied
+}
+
+// TemplateData はレポートテンプレートに渡すデータです
+type TemplateData struct {
+	// GeneratedAt はレポートの作成日時です。正規化した出力（Options.Normalize）ではゼロ値です
+	GeneratedAt time.Time
+	// Entries はフォルダ構成のすべてのエントリ（フォルダを含む）です
+	Entries []TemplateEntry
+	// Files はファイル内容を出力する順（-order の指定に従う）に並べたファイルです
+	Files []TemplateEntry
+	// Stats はファイル数・合計サイズ・拡張子別などの統計情報です
+	Stats Statistics
+	// Command はレポートを再作成するコマンドラインです。空の場合もあります
+	Command string
+}
+
+// TemplateEntry はテンプレートで参照するエントリです。RelPath, IsDir, Size, ModTime, Depth などのエントリの項目を参照できます
+type TemplateEntry struct {
+	model.FileSystemEntry
+	g *Generator
+}
+
+// Name はファイル名（フォルダ名）を返します
+func (e TemplateEntry) Name() string {
+	return path.Base(e.RelPath)
+}
+
+// Content はファイルの内容を読み込みます。読み込みのたびにファイルを読むため、1 つのエントリで 1 回のみ呼び出してください
+func (e TemplateEntry) Content() TemplateContent {
+	g, entry := e.g, e.FileSystemEntry
+	content, notice := g.loadContent(entry)
+	c := TemplateContent{
+		RelPath:  entry.RelPath,
+		Authors:  g.authorsOf(entry),
+		Metrics:  g.metricsOf(entry, content, notice),
+		Notice:   notice,
+		Language: languageHint(entry.RelPath),
+	}
+	_, isHunks := g.hunksOf(entry)
+	if isHunks {
+		c.Language = "diff"
+	}
+	if g.options.LineNumbers && notice == "" && !g.isExcerpt(entry) {
+		content = numberLines(content)
+	}
+	c.Text = strings.TrimSuffix(string(content), "\n")
+	c.Redactions = formatRedactions(g.redactionsOf(entry.RelPath))
+	return c
+}
+
+// TemplateContent はテンプレートで参照するファイルの内容です
+type TemplateContent struct {
+	// RelPath はファイルの相対パスです
+	RelPath string
+	// Text は秘密情報をマスクした本文です。末尾の改行は除きます。本文を出力できない場合は空です
+	Text string
+	// Notice は本文を出力できない理由（バイナリファイル・サイズの上限超過など）です
+	Notice string
+	// Language はMarkdownのコードブロックに指定する言語名です
+	Language string
+	// Authors は git の主な作成者です（-authors 指定時のみ）
+	Authors string
+	// Metrics は行数などの指標です（-metrics 指定時のみ）
+	Metrics *FileMetrics
+	// Redactions は本文でマスクした情報の種類と件数（"email (1), jwt (2)" など）です
+	Redactions string
+}
+
+// writeTemplate はテンプレートでレポート全体を出力します
+func (g *Generator) writeTemplate(writer io.Writer, entries []model.FileSystemEntry) error {
+	data := TemplateData{
+		GeneratedAt: g.generatedAt(),
+		Stats:       ComputeStatistics(entries, DefaultLargestFiles),
+		Command:     g.options.ReproduceCommand,
+	}
+	files := make([]model.FileSystemEntry, 0, len(entries))
+	for _, entry := range entries {
+		data.Entries = append(data.Entries, TemplateEntry{FileSystemEntry: entry, g: g})
+		if g.hasSection(entry) {
+			files = append(files, entry)
+		}
+	}
+	g.sortContents(files)
+	for _, entry := range files {
+		data.Files = append(data.Files, TemplateEntry{FileSystemEntry: entry, g: g})
+	}
+	if err := g.template.Execute(writer, data); err != nil {
+		return fmt.Errorf("テンプレート %s の実行に失敗しました: %w", g.template.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"FolderScope/internal/domain/model"
+)
+
+// TreeStyle はフォルダ構成の描画方法です
+type TreeStyle string
+
+const (
+	// TreeIndent は字下げと [DIR]/[FILE] の種別で描画します（既定）
+	TreeIndent TreeStyle = "indent"
+	// TreeLines は tree コマンドのように罫線（├──, └──, │）で描画し、各行には名前のみを表示します
+	TreeLines TreeStyle = "tree"
+)
+
+// ParseTreeStyle は文字列からフォルダ構成の描画方法を解決します。空文字列は字下げとして扱います
+func ParseTreeStyle(s string) (TreeStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "indent":
+		return TreeIndent, nil
+	case "tree", "lines":
+		return TreeLines, nil
+	}
+	return "", fmt.Errorf("未対応のフォルダ構成の描画方法です: %s（indent, tree のいずれかを指定してください）", s)
+}
+
+const (
+	treeBranch   = "├── "
+	treeLast     = "└── "
+	treeVertical = "│   "
+	treeBlank    = "    "
+)
+
+// structureLine はフォルダ構成の 1 行です
+type structureLine struct {
+	entry model.FileSystemEntry
+	// prefix は行頭の字下げまたは罫線です
+	prefix string
+	// listing は LongListing が有効な場合に prefix の前に表示する、桁をそろえたパーミッション・所有者・グループ・サイズ・更新日時の列です
+	listing string
+}
+
+// structureLines はフォルダ構成に表示するエントリ（既定ではバイナリファイルを除く）と、描画方法に応じた行頭の文字列を返します
+func (g *Generator) structureLines(entries []model.FileSystemEntry) []structureLine {
+	lines := make([]structureLine, 0, len(entries))
+	for _, entry := range entries {
+		if !g.inStructure(entry) {
+			continue
+		}
+		lines = append(lines, structureLine{entry: entry, prefix: strings.Repeat("  ", entry.Depth)})
+	}
+	if g.options.LongListing {
+		fillListing(lines)
+	}
+	if g.options.TreeStyle != TreeLines {
+		return lines
+	}
+
+	// 同じフォルダの最後の項目には └── を使い、その配下では縦線を引かない
+	lastChild := make(map[string]int, len(lines))
+	for i, line := range lines {
+		lastChild[path.Dir(line.entry.RelPath)] = i
+	}
+	// ancestorsLast[d] は深さ d の祖先が、そのフォルダの最後の項目であるかどうかです
+	var ancestorsLast []bool
+	for i := range lines {
+		entry := lines[i].entry
+		isLast := lastChild[path.Dir(entry.RelPath)] == i
+		var b strings.Builder
+		for d := 0; d < entry.Depth; d++ {
+			if d < len(ancestorsLast) && !ancestorsLast[d] {
+				b.WriteString(treeVertical)
+			} else {
+				b.WriteString(treeBlank)
+			}
+		}
+		if isLast {
+			b.WriteString(treeLast)
+		} else {
+			b.WriteString(treeBranch)
+		}
+		lines[i].prefix = b.String()
+		ancestorsLast = append(ancestorsLast[:min(entry.Depth, len(ancestorsLast))], isLast)
+	}
+	return lines
+}
+
+// treeName は罫線で描画する場合に表示する名前です。フォルダには末尾に "/" を付けます
+func treeName(entry model.FileSystemEntry) string {
+	name := path.Base(entry.RelPath)
+	if entry.IsDir {
+		name += "/"
+	}
+	return name
+}
+
+// fillListing は各行に ls -l のような列を設定します。所有者・グループ・サイズは最も長い値に桁をそろえます。
+// 所有者とグループを取得できないエントリは "-"、フォルダのサイズは "-" と表示し、更新日時が不明な場合は空欄にします
+func fillListing(lines []structureLine) {
+	columns := make([][3]string, len(lines))
+	var widths [3]int
+	for i, line := range lines {
+		entry := line.entry
+		size := "-"
+		if !entry.IsDir {
+			size = FormatSize(entry.Size)
+		}
+		columns[i] = [3]string{orDash(entry.Owner), orDash(entry.Group), size}
+		for j, c := range columns[i] {
+			widths[j] = max(widths[j], len(c))
+		}
+	}
+	for i := range lines {
+		entry := lines[i].entry
+		modTime := strings.Repeat(" ", len(MetadataTimeLayout))
+		if !entry.ModTime.IsZero() {
+			modTime = entry.ModTime.Format(MetadataTimeLayout)
+		}
+		c := columns[i]
+		lines[i].listing = fmt.Sprintf("%s  %-*s  %-*s  %*s  %s  ",
+			entry.Permissions, widths[0], c[0], widths[1], c[1], widths[2], c[2], modTime)
+	}
+}
+
+// orDash は空文字列の場合に "-" を返します
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
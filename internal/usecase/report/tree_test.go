@@ -0,0 +1,87 @@
+package report
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestGenerator_TreeStyle(t *testing.T) {
+	entries := []model.FileSystemEntry{
+		{RelPath: "cmd", IsDir: true},
+		{RelPath: "cmd/app", IsDir: true, Depth: 1},
+		{RelPath: "cmd/app/main.go", Depth: 2},
+		{RelPath: "cmd/tool.go", Depth: 1},
+		{RelPath: "internal", IsDir: true},
+		{RelPath: "internal/a.go", Depth: 1},
+		{RelPath: "internal/b.go", Depth: 1},
+		// バイナリファイルは表示しないため、README.md が最後の項目になる
+		{RelPath: "README.md"},
+		{RelPath: "logo.png", IsBinary: true},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "テキスト形式",
+			format: FormatText,
+			want: "===== フォルダ・ファイル構成 =====\n" +
+				".\n" +
+				"├── cmd/\n" +
+				"│   ├── app/\n" +
+				"│   │   └── main.go\n" +
+				"│   └── tool.go\n" +
+				"├── internal/\n" +
+				"│   ├── a.go\n" +
+				"│   └── b.go\n" +
+				"└── README.md\n",
+		},
+		{
+			name:   "Markdown形式",
+			format: FormatMarkdown,
+			want:   "│   │   └── <a id=\"tree-cmd-app-main-go-1f7127b0\"></a><a href=\"#file-cmd-app-main-go-1f7127b0\">main.go</a>\n",
+		},
+		{
+			name:   "HTML形式",
+			format: FormatHTML,
+			want:   "<span id=\"tree-internal-3bed2cb3\">├── internal/</span>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewGeneratorWithOptions(Options{Format: tt.format, TreeStyle: TreeLines})
+			var buf strings.Builder
+			if err := generator.WriteFileSystemStructure(&buf, entries); err != nil {
+				t.Fatalf("WriteFileSystemStructure() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("出力に %q が含まれていません:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestGenerator_WriteFileSystemStructureLongListing(t *testing.T) {
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	entries := []model.FileSystemEntry{
+		{RelPath: "bin", IsDir: true, Permissions: fs.ModeDir | 0755, Owner: "root", Group: "wheel", ModTime: modTime},
+		{RelPath: "bin/run.sh", Depth: 1, Size: 2048, Permissions: 0700, Owner: "deploy", Group: "staff", ModTime: modTime},
+		{RelPath: "notes.txt", Size: 10, Permissions: 0644},
+	}
+	var buf strings.Builder
+	NewGeneratorWithOptions(Options{LongListing: true}).WriteFileSystemStructure(&buf, entries)
+
+	want := "drwxr-xr-x  root    wheel       -  2024-01-02 03:04:05  [DIR]  bin\n" +
+		"-rwx------  deploy  staff  2.0 KB  2024-01-02 03:04:05    [FILE] bin/run.sh\n" +
+		"-rw-r--r--  -       -        10 B                       [FILE] notes.txt\n"
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("WriteFileSystemStructure() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// DefaultWriteBufferSize は ReportWriter のバッファサイズです
+	DefaultWriteBufferSize = 64 * 1024
+	// DefaultFlushInterval は ReportWriter がバッファの内容を出力先に書き出す間隔です。
+	// 巨大なレポートの生成中でも、出力先のファイルに一定間隔で内容が反映されます
+	DefaultFlushInterval = time.Second
+)
+
+// ReportWriter はレポートの出力先をバッファリングして書き込む Writer です。
+// 一定間隔でバッファを書き出し、書き込んだバイト数を数えます。
+// Close でバッファの残りを書き出してから出力先を閉じます
+type ReportWriter struct {
+	dest          io.WriteCloser
+	buf           *bufio.Writer
+	written       int64
+	flushInterval time.Duration
+	lastFlush     time.Time
+	closed        bool
+}
+
+// NewReportWriter は dest に書き込む ReportWriter を作成します
+func NewReportWriter(dest io.WriteCloser) *ReportWriter {
+	return &ReportWriter{
+		dest:          dest,
+		buf:           bufio.NewWriterSize(dest, DefaultWriteBufferSize),
+		flushInterval: DefaultFlushInterval,
+		lastFlush:     time.Now(),
+	}
+}
+
+// SetFlushInterval はバッファを書き出す間隔を設定します。0 以下の場合はバッファが満杯になるか Flush・Close を呼ぶまで書き出しません
+func (w *ReportWriter) SetFlushInterval(interval time.Duration) {
+	w.flushInterval = interval
+}
+
+// Write はバッファに書き込みます。前回の書き出しから一定時間が経過している場合はバッファを書き出します
+func (w *ReportWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("閉じられた出力先には書き込めません")
+	}
+	n, err := w.buf.Write(p)
+	w.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+	if w.flushInterval > 0 && time.Since(w.lastFlush) >= w.flushInterval {
+		if err := w.Flush(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Flush はバッファの内容を出力先に書き出します
+func (w *ReportWriter) Flush() error {
+	w.lastFlush = time.Now()
+	return w.buf.Flush()
+}
+
+// Written はこれまでに書き込まれたバイト数を返します
+func (w *ReportWriter) Written() int64 {
+	return w.written
+}
+
+// Close はバッファの残りを書き出してから出力先を閉じます。
+// 書き出しと出力先のクローズの両方を試み、最初に発生したエラーを返します
+func (w *ReportWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	flushErr := w.buf.Flush()
+	closeErr := w.dest.Close()
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// closeRecorder は書き込まれた内容と Close の呼び出しを記録する WriteCloser です
+type closeRecorder struct {
+	bytes.Buffer
+	closed   int
+	closeErr error
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return c.closeErr
+}
+
+func TestReportWriter(t *testing.T) {
+	dest := &closeRecorder{}
+	w := NewReportWriter(dest)
+	w.SetFlushInterval(0)
+
+	if _, err := w.Write([]byte("hello, ")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := w.Write([]byte("world")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if dest.Len() != 0 {
+		t.Errorf("Flush 前に出力先へ書き込まれています: %q", dest.String())
+	}
+	if got := w.Written(); got != 12 {
+		t.Errorf("Written() = %d, want 12", got)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := dest.String(); got != "hello, world" {
+		t.Errorf("出力内容 = %q, want %q", got, "hello, world")
+	}
+	// 2 回目の Close は何もしない
+	if err := w.Close(); err != nil || dest.closed != 1 {
+		t.Errorf("2 回目の Close() error = %v, 出力先の Close 回数 = %d", err, dest.closed)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Close 後の Write はエラーを返すべきです")
+	}
+}
+
+func TestReportWriter_PeriodicFlush(t *testing.T) {
+	dest := &closeRecorder{}
+	w := NewReportWriter(dest)
+	w.SetFlushInterval(time.Nanosecond)
+
+	time.Sleep(time.Millisecond)
+	if _, err := w.Write([]byte("data")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := dest.String(); got != "data" {
+		t.Errorf("一定時間経過後の Write で書き出されていません: %q", got)
+	}
+}
+
+func TestReportWriter_CloseError(t *testing.T) {
+	closeErr := errors.New("close failed")
+	w := NewReportWriter(&closeRecorder{closeErr: closeErr})
+	if err := w.Close(); !errors.Is(err, closeErr) {
+		t.Errorf("Close() error = %v, want %v", err, closeErr)
+	}
+}
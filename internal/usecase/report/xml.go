@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+const (
+	// xmlRootElement は XML 形式のエクスポートのルート要素の名前です
+	xmlRootElement = "folderscope"
+	// xmlItemElement は JSON の配列の要素、および XML の要素名に使用できないキーを出力する要素の名前です
+	xmlItemElement = "item"
+)
+
+// writeXMLHeader は XML 宣言とルート要素の開始タグ、JSON 形式と同じ構造の統計情報を出力し、entries 要素を開始します
+func writeXMLHeader(writer io.Writer, header exportHeader) {
+	io.WriteString(writer, xml.Header)
+	fmt.Fprintf(writer, "<%s>\n", xmlRootElement)
+	data, _ := json.Marshal(header)
+	dec := newJSONTokenDecoder(data)
+	// ヘッダーのオブジェクトのメンバーをルート要素の直下に出力する
+	dec.Token()
+	for dec.More() {
+		key, _ := dec.Token()
+		writeXMLValue(writer, dec, key.(string), 1)
+	}
+	io.WriteString(writer, "  <entries>\n")
+}
+
+// writeXMLEntry はエントリを JSON 形式と同じ構造の entry 要素として出力します
+func writeXMLEntry(writer io.Writer, e ExportEntry) {
+	data, _ := json.Marshal(e)
+	writeXMLValue(writer, newJSONTokenDecoder(data), "entry", 2)
+}
+
+// writeXMLEnd は entries 要素とルート要素を閉じます
+func writeXMLEnd(writer io.Writer) {
+	fmt.Fprintf(writer, "  </entries>\n</%s>\n", xmlRootElement)
+}
+
+// newJSONTokenDecoder は数値を元の表記のまま読み込む JSON のデコーダーを作成します
+func newJSONTokenDecoder(data []byte) *json.Decoder {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec
+}
+
+// writeXMLValue は dec から JSON の値を 1 つ読み込み、name の要素として出力します。
+// オブジェクトのメンバーは子要素に、配列の要素は item 要素になります。null の値は要素を出力しません。
+// json.Marshal の出力を読み込むため、構文エラーは発生しません
+func writeXMLValue(writer io.Writer, dec *json.Decoder, name string, depth int) {
+	token, _ := dec.Token()
+	indent := strings.Repeat("  ", depth)
+	start, end := xmlTags(name)
+	switch v := token.(type) {
+	case json.Delim:
+		if !dec.More() {
+			dec.Token()
+			fmt.Fprintf(writer, "%s%s%s\n", indent, start, end)
+			return
+		}
+		fmt.Fprintf(writer, "%s%s\n", indent, start)
+		for dec.More() {
+			child := xmlItemElement
+			if v == '{' {
+				key, _ := dec.Token()
+				child = key.(string)
+			}
+			writeXMLValue(writer, dec, child, depth+1)
+		}
+		dec.Token()
+		fmt.Fprintf(writer, "%s%s\n", indent, end)
+	case nil:
+	default:
+		fmt.Fprintf(writer, "%s%s", indent, start)
+		xml.EscapeText(writer, []byte(fmt.Sprint(v)))
+		fmt.Fprintf(writer, "%s\n", end)
+	}
+}
+
+// xmlTags は name の要素の開始タグと終了タグを返します。
+// name が XML の要素名に使用できない場合は、item 要素の key 属性に name を出力します
+func xmlTags(name string) (string, string) {
+	if isXMLName(name) {
+		return "<" + name + ">", "</" + name + ">"
+	}
+	var key strings.Builder
+	xml.EscapeText(&key, []byte(name))
+	return fmt.Sprintf("<%s key=\"%s\">", xmlItemElement, key.String()), "</" + xmlItemElement + ">"
+}
+
+// isXMLName は name が名前空間の接頭辞を含まない XML の要素名として使用できるかどうかを返します
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r), r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeYAMLHeader は JSON 形式と同じ構造の統計情報を YAML のマッピングとして出力し、entries キーを開始します。
+// エントリがない場合は空のシーケンスを出力します
+func writeYAMLHeader(writer io.Writer, header exportHeader, empty bool) {
+	data, _ := json.Marshal(header)
+	writeYAMLNode(writer, yamlNodeFromJSON(newJSONTokenDecoder(data)))
+	if empty {
+		io.WriteString(writer, "entries: []\n")
+		return
+	}
+	io.WriteString(writer, "entries:\n")
+}
+
+// writeYAMLEntry はエントリを JSON 形式と同じ構造で、entries のシーケンスの要素として出力します。
+// stats の largestFiles などのシーケンスと同じ字下げになるよう、entries キーの値として出力してからキーの行を取り除きます
+func writeYAMLEntry(writer io.Writer, e ExportEntry) {
+	data, _ := json.Marshal(e)
+	var buf strings.Builder
+	writeYAMLNode(&buf, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "entries"},
+		{Kind: yaml.SequenceNode, Content: []*yaml.Node{yamlNodeFromJSON(newJSONTokenDecoder(data))}},
+	}})
+	io.WriteString(writer, strings.TrimPrefix(buf.String(), "entries:\n"))
+}
+
+// writeYAMLNode は node を 1 つの YAML ドキュメントとして、ドキュメントの区切りを付けずに出力します
+func writeYAMLNode(writer io.Writer, node *yaml.Node) {
+	enc := yaml.NewEncoder(writer)
+	enc.SetIndent(2)
+	enc.Encode(node)
+	enc.Close()
+}
+
+// yamlNodeFromJSON は dec から JSON の値を 1 つ読み込み、キーの順序を保った YAML のノードに変換します。
+// 複数行の文字列はリテラルブロック（|）で出力します。json.Marshal の出力を読み込むため、構文エラーは発生しません
+func yamlNodeFromJSON(dec *json.Decoder) *yaml.Node {
+	token, _ := dec.Token()
+	switch v := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if v == '{' {
+			node.Kind = yaml.MappingNode
+		}
+		for dec.More() {
+			if v == '{' {
+				key, _ := dec.Token()
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			node.Content = append(node.Content, yamlNodeFromJSON(dec))
+		}
+		dec.Token()
+		return node
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if strings.Contains(v, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
@@ -0,0 +1,112 @@
+// Package scanresult はフォルダのスキャン結果をファイルに保存し、フォルダを再びスキャンせずに
+// 別の出力形式や条件でレポートを出力し直すための形式を提供します。
+//
+// スキャン結果はファイルの内容を含まず、各ファイルの内容は Path（アーカイブの場合は RelPath）で参照します。
+// レポートの出力時に参照先から内容を読み込むため、スキャン後にファイルが変更された場合は変更後の内容が出力されます
+package scanresult
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+// FormatVersion はスキャン結果のファイルの形式のバージョンです。
+// 互換性のない変更を加えた場合に更新します
+const FormatVersion = 1
+
+// Entry はスキャン結果に記録する 1 要素分の情報です
+type Entry struct {
+	model.FileSystemEntry
+	// ReadError はスキャン時の読み込みエラーのメッセージです。エラーがない場合は空です
+	ReadError string `json:"readError,omitempty"`
+}
+
+// Result は保存したスキャン結果です
+type Result struct {
+	Version int `json:"version"`
+	// Root はスキャンした調査対象のフォルダまたはアーカイブです
+	Root      string          `json:"root"`
+	ScannedAt time.Time       `json:"scannedAt"`
+	Stats     model.ScanStats `json:"stats"`
+	Entries   []Entry         `json:"entries"`
+}
+
+// New はスキャン結果 entries と統計情報 stats から、保存するスキャン結果を作成します
+func New(root string, entries []model.FileSystemEntry, stats model.ScanStats, scannedAt time.Time) *Result {
+	result := &Result{
+		Version:   FormatVersion,
+		Root:      root,
+		ScannedAt: scannedAt,
+		Stats:     stats,
+		Entries:   make([]Entry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		entry := Entry{FileSystemEntry: e}
+		if e.ReadErr != nil {
+			entry.ReadError = e.ReadErr.Error()
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	return result
+}
+
+// FileSystemEntries は記録された要素をレポート出力用のエントリに変換します。スキャン時の読み込みエラーも復元します
+func (r *Result) FileSystemEntries() []model.FileSystemEntry {
+	entries := make([]model.FileSystemEntry, 0, len(r.Entries))
+	for _, e := range r.Entries {
+		entry := e.FileSystemEntry
+		if e.ReadError != "" {
+			entry.ReadErr = errors.New(e.ReadError)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ChangedFiles は、ファイルの内容を Path で参照する場合に、スキャン後にサイズか更新日時が変わったか削除されたファイルの相対パスを返します
+func (r *Result) ChangedFiles() []string {
+	var changed []string
+	for _, e := range r.Entries {
+		if e.IsDir || e.Path == "" {
+			continue
+		}
+		info, err := os.Stat(e.Path)
+		if err != nil || info.Size() != e.Size || !info.ModTime().Equal(e.ModTime) {
+			changed = append(changed, e.RelPath)
+		}
+	}
+	return changed
+}
+
+// WriteFile はスキャン結果を JSON としてファイルに書き込みます
+func WriteFile(path string, r *Result) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("スキャン結果のエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("スキャン結果のファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// LoadFile はスキャン結果のファイルを読み込みます。対応していない形式のバージョンの場合はエラーを返します
+func LoadFile(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("スキャン結果のファイルの読み込みに失敗しました: %w", err)
+	}
+	var r Result
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("スキャン結果のファイルの解析に失敗しました: %w", err)
+	}
+	if r.Version != FormatVersion {
+		return nil, fmt.Errorf("対応していないスキャン結果の形式です（バージョン %d）", r.Version)
+	}
+	return &r, nil
+}
@@ -0,0 +1,90 @@
+package scanresult
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+)
+
+func TestResult_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	keptPath := filepath.Join(dir, "kept.txt")
+	changedPath := filepath.Join(dir, "changed.txt")
+	for _, path := range []string{keptPath, changedPath} {
+		if err := os.WriteFile(path, []byte("before"), 0644); err != nil {
+			t.Fatalf("ファイルの作成に失敗: %v", err)
+		}
+	}
+	stat := func(path string) os.FileInfo {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		return info
+	}
+	kept, changed := stat(keptPath), stat(changedPath)
+	entries := []model.FileSystemEntry{
+		{Path: dir, RelPath: "", IsDir: true},
+		{Path: changedPath, RelPath: "changed.txt", Size: changed.Size(), ModTime: changed.ModTime(), Encoding: model.EncodingUTF8},
+		{Path: filepath.Join(dir, "gone.txt"), RelPath: "gone.txt", Size: 3, ReadErr: errors.New("permission denied")},
+		{Path: keptPath, RelPath: "kept.txt", Size: kept.Size(), ModTime: kept.ModTime()},
+	}
+	stats := model.ScanStats{Errors: 1, Skipped: map[model.SkipReason]int{model.SkipIgnored: 2}}
+
+	path := filepath.Join(dir, "last-scan.json")
+	if err := WriteFile(path, New(dir, entries, stats, time.Now())); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(changedPath, []byte("after the scan"), 0644); err != nil {
+		t.Fatalf("ファイルの更新に失敗: %v", err)
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if loaded.Root != dir || loaded.Stats.Skipped[model.SkipIgnored] != 2 {
+		t.Errorf("LoadFile() = %+v", loaded)
+	}
+	restored := loaded.FileSystemEntries()
+	if len(restored) != len(entries) {
+		t.Fatalf("エントリ数 = %d, want %d", len(restored), len(entries))
+	}
+	if restored[1].Encoding != model.EncodingUTF8 || !restored[3].ModTime.Equal(kept.ModTime()) {
+		t.Errorf("復元したエントリ = %+v", restored)
+	}
+	if restored[2].ReadErr == nil || restored[2].ReadErr.Error() != "permission denied" {
+		t.Errorf("ReadErr = %v, want permission denied", restored[2].ReadErr)
+	}
+	if got := strings.Join(loaded.ChangedFiles(), ","); got != "changed.txt,gone.txt" {
+		t.Errorf("ChangedFiles() = %s, want changed.txt,gone.txt", got)
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "JSONではない", content: "not json", wantErr: "解析に失敗"},
+		{name: "未対応のバージョン", content: `{"version": 99}`, wantErr: "バージョン 99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("ファイルの作成に失敗: %v", err)
+			}
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFile() error = %v, want %q を含むエラー", err, tt.wantErr)
+			}
+		})
+	}
+}
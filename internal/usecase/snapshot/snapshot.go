@@ -0,0 +1,142 @@
+// Package snapshot はフォルダのスキャン結果をファイルに保存し、後から現在のフォルダと比較するための
+// スナップショット形式を提供します
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/usecase/diff"
+)
+
+// FormatVersion はスナップショットファイルの形式のバージョンです。
+// 互換性のない変更を加えた場合に更新します
+const FormatVersion = 1
+
+// FileSuffix はスナップショットファイルの既定の拡張子です
+const FileSuffix = ".snapshot.json"
+
+// Filters はスナップショットの作成時に適用したスキャンの絞り込み条件です。
+// 比較時に同じ条件でスキャンすることで、条件の違いによる差分が報告されないようにします
+type Filters struct {
+	IgnorePatterns    []string `json:"ignorePatterns,omitempty"`
+	IgnoreBinaryFiles bool     `json:"ignoreBinaryFiles,omitempty"`
+	IncludeRegexps    []string `json:"includeRegexps,omitempty"`
+	ExcludeRegexps    []string `json:"excludeRegexps,omitempty"`
+}
+
+// Entry はスナップショットに記録する 1 要素分の情報です
+type Entry struct {
+	RelPath     string      `json:"relPath"`
+	IsDir       bool        `json:"isDir,omitempty"`
+	Size        int64       `json:"size"`
+	ModTime     time.Time   `json:"modTime"`
+	Permissions fs.FileMode `json:"permissions"`
+	Hash        string      `json:"hash,omitempty"`
+}
+
+// Snapshot はある時点のフォルダの状態です
+type Snapshot struct {
+	Version   int       `json:"version"`
+	Root      string    `json:"root"`
+	CreatedAt time.Time `json:"createdAt"`
+	Filters   Filters   `json:"filters"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// New はスキャン結果からスナップショットを作成します
+func New(root string, entries []model.FileSystemEntry, filters Filters, createdAt time.Time) *Snapshot {
+	snap := &Snapshot{
+		Version:   FormatVersion,
+		Root:      root,
+		CreatedAt: createdAt,
+		Filters:   filters,
+		Entries:   make([]Entry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		snap.Entries = append(snap.Entries, Entry{
+			RelPath:     e.RelPath,
+			IsDir:       e.IsDir,
+			Size:        e.Size,
+			ModTime:     e.ModTime,
+			Permissions: e.Permissions,
+			Hash:        e.Hash,
+		})
+	}
+	return snap
+}
+
+// FileSystemEntries は記録された要素を比較用のエントリに変換します。
+// スナップショットはファイルの内容を含まないため、Path は空になります
+func (s *Snapshot) FileSystemEntries() []model.FileSystemEntry {
+	entries := make([]model.FileSystemEntry, 0, len(s.Entries))
+	for _, e := range s.Entries {
+		entries = append(entries, model.FileSystemEntry{
+			RelPath:     e.RelPath,
+			IsDir:       e.IsDir,
+			Size:        e.Size,
+			ModTime:     e.ModTime,
+			Permissions: e.Permissions,
+			Hash:        e.Hash,
+		})
+	}
+	return entries
+}
+
+// CompareMethod はスナップショットと比較する際の判定方法を返します。
+// ハッシュを記録したファイルがあればハッシュで、なければサイズと更新日時で判定します
+func (s *Snapshot) CompareMethod() diff.Method {
+	for _, e := range s.Entries {
+		if !e.IsDir && e.Hash != "" {
+			return diff.MethodHash
+		}
+	}
+	return diff.MethodModTime
+}
+
+// Compare は現在のスキャン結果 current をスナップショットと比較し、スナップショット作成後の変化を返します
+func (s *Snapshot) Compare(current []model.FileSystemEntry) diff.Result {
+	return diff.Compare(s.FileSystemEntries(), current, s.CompareMethod())
+}
+
+// CompareSnapshot は後から作成したスナップショット later をこのスナップショットと比較し、その間の変化を返します。
+// 両方のスナップショットがハッシュを記録している場合はハッシュで、それ以外はサイズと更新日時で判定します
+func (s *Snapshot) CompareSnapshot(later *Snapshot) diff.Result {
+	method := diff.MethodModTime
+	if s.CompareMethod() == diff.MethodHash && later.CompareMethod() == diff.MethodHash {
+		method = diff.MethodHash
+	}
+	return diff.Compare(s.FileSystemEntries(), later.FileSystemEntries(), method)
+}
+
+// WriteFile はスナップショットを JSON としてファイルに書き込みます
+func WriteFile(path string, snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("スナップショットのエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("スナップショットファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// LoadFile はスナップショットファイルを読み込みます。対応していない形式のバージョンの場合はエラーを返します
+func LoadFile(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("スナップショットファイルの読み込みに失敗しました: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("スナップショットファイルの解析に失敗しました: %w", err)
+	}
+	if snap.Version != FormatVersion {
+		return nil, fmt.Errorf("対応していないスナップショットの形式です（バージョン %d）", snap.Version)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,105 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/usecase/diff"
+)
+
+func TestSnapshot_RoundTripAndCompare(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []model.FileSystemEntry{
+		{Path: "/root/dir", RelPath: "dir", IsDir: true},
+		{Path: "/root/dir/a.txt", RelPath: "dir/a.txt", Size: 3, ModTime: modTime, Hash: "aaa"},
+		{Path: "/root/b.txt", RelPath: "b.txt", Size: 3, ModTime: modTime, Hash: "bbb"},
+	}
+	filters := Filters{IgnorePatterns: []string{"*.log"}}
+
+	path := filepath.Join(t.TempDir(), "test"+FileSuffix)
+	if err := WriteFile(path, New("/root", entries, filters, modTime)); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	snap, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if snap.Root != "/root" || len(snap.Entries) != 3 || snap.Filters.IgnorePatterns[0] != "*.log" {
+		t.Errorf("読み込んだスナップショットが一致しません: %+v", snap)
+	}
+	if snap.CompareMethod() != diff.MethodHash {
+		t.Errorf("CompareMethod() = %v, want %v", snap.CompareMethod(), diff.MethodHash)
+	}
+
+	// 更新日時のみ変わったファイルはハッシュが一致するため変化なしとして扱う
+	current := []model.FileSystemEntry{
+		{RelPath: "dir/a.txt", Size: 3, ModTime: modTime.Add(time.Hour), Hash: "aaa"},
+		{RelPath: "b.txt", Size: 4, ModTime: modTime, Hash: "bbb2"},
+		{RelPath: "c.txt", Size: 1, ModTime: modTime, Hash: "ccc"},
+	}
+	result := snap.Compare(current)
+	if result.Count(diff.Modified) != 1 || result.Count(diff.Added) != 1 || result.Unchanged != 1 {
+		t.Errorf("Compare() = %+v", result)
+	}
+}
+
+func TestSnapshot_CompareMethodWithoutHashes(t *testing.T) {
+	snap := New("/root", []model.FileSystemEntry{{RelPath: "a.txt"}}, Filters{}, time.Now())
+	if snap.CompareMethod() != diff.MethodModTime {
+		t.Errorf("CompareMethod() = %v, want %v", snap.CompareMethod(), diff.MethodModTime)
+	}
+}
+
+func TestSnapshot_CompareSnapshot(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	older := New("/root", []model.FileSystemEntry{
+		{RelPath: "a.txt", Size: 3, ModTime: modTime, Hash: "aaa"},
+		{RelPath: "b.txt", Size: 3, ModTime: modTime, Hash: "bbb"},
+	}, Filters{}, modTime)
+	// 更新日時のみ変わったファイルは、両方にハッシュがある場合は変化なしとして扱う
+	later := []model.FileSystemEntry{
+		{RelPath: "a.txt", Size: 3, ModTime: modTime.Add(time.Hour), Hash: "aaa"},
+		{RelPath: "c.txt", Size: 1, ModTime: modTime, Hash: "ccc"},
+	}
+	result := older.CompareSnapshot(New("/root", later, Filters{}, modTime.Add(time.Hour)))
+	if result.Count(diff.Added) != 1 || result.Count(diff.Removed) != 1 || result.Unchanged != 1 {
+		t.Errorf("CompareSnapshot() = %+v", result)
+	}
+
+	// 一方にハッシュがない場合はサイズと更新日時で判定する
+	for i := range later {
+		later[i].Hash = ""
+	}
+	result = older.CompareSnapshot(New("/root", later, Filters{}, modTime.Add(time.Hour)))
+	if result.Count(diff.Modified) != 1 {
+		t.Errorf("ハッシュのないスナップショットとの CompareSnapshot() = %+v", result)
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantMsg string
+	}{
+		{"不正なJSON", "{", "解析に失敗"},
+		{"未対応のバージョン", `{"version": 99}`, "バージョン 99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("ファイルの作成に失敗: %v", err)
+			}
+			_, err := LoadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("LoadFile() error = %v, want containing %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// Package folderscope は FolderScope のスキャンとレポート生成を、他の Go プログラムから利用するための公開パッケージです。
+//
+// Scanner でフォルダ（または fs.FS）を走査してエントリの一覧と統計情報を取得し、
+// Generator でテキスト・Markdown・HTML・JSON などの形式のレポートを書き込みます。
+// 1 回で済ませる場合は Run を使用します。
+//
+//	var buf bytes.Buffer
+//	stats, err := folderscope.Run(ctx, "./src", &buf,
+//		folderscope.ScanOptions{IgnorePatterns: []string{"*.log"}},
+//		folderscope.ReportOptions{Format: folderscope.FormatMarkdown})
+//
+// このパッケージで公開する型・関数・オプションの項目は、互換性を保って維持します。
+// 項目の追加は行いますが、既存の項目の意味は変更しません。
+// コマンドラインツールのすべての機能（GUI・監視・差分・プラグインなど）を公開するものではありません
+package folderscope
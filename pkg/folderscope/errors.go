@@ -0,0 +1,22 @@
+package folderscope
+
+import "FolderScope/internal/domain/apperrors"
+
+// エラーの種類です。Scanner・Generator・Run が返すエラーは、該当する種類を errors.Is で判定できます
+var (
+	// ErrInvalidInput はオプションの値（正規表現・出力形式など）が不正であることを示します
+	ErrInvalidInput = apperrors.ErrInvalidInput
+	// ErrNotFound は調査対象のパスが存在しないことを示します
+	ErrNotFound = apperrors.ErrNotFound
+	// ErrNotDirectory は調査対象のパスがフォルダではないことを示します
+	ErrNotDirectory = apperrors.ErrNotDirectory
+	// ErrPermission はアクセス権限が不足していることを示します
+	ErrPermission = apperrors.ErrPermission
+	// ErrCancelled はコンテキストのキャンセルによって処理を中止したことを示します
+	ErrCancelled = apperrors.ErrCancelled
+)
+
+// invalidInput は err を ErrInvalidInput の種類のエラーにします
+func invalidInput(err error) error {
+	return apperrors.New(apperrors.ErrInvalidInput, "", "", err)
+}
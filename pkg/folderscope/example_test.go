@@ -0,0 +1,95 @@
+package folderscope_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing/fstest"
+
+	"FolderScope/pkg/folderscope"
+)
+
+func Example() {
+	fsys := fstest.MapFS{
+		"main.go":   {Data: []byte("package main\n")},
+		"debug.log": {Data: []byte("ignored\n")},
+	}
+	ctx := context.Background()
+
+	scanner, err := folderscope.NewScanner(folderscope.ScanOptions{IgnorePatterns: []string{"*.log"}})
+	if err != nil {
+		panic(err)
+	}
+	entries, _, err := scanner.ScanFS(ctx, fsys, "project")
+	if err != nil {
+		panic(err)
+	}
+	generator, err := folderscope.NewGenerator(folderscope.ReportOptions{Format: folderscope.FormatMarkdown, Normalize: true})
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.WithFS(fsys).WriteReport(ctx, os.Stdout, entries); err != nil {
+		panic(err)
+	}
+	// Output:
+	// ## フォルダ・ファイル構成
+	//
+	// - <a id="tree-main-go-2873f79a"></a>[main.go](#file-main-go-2873f79a)
+	//
+	// ## ファイル内容
+	//
+	// ### <a id="file-main-go-2873f79a"></a>main.go
+	//
+	// [↑ 構成に戻る](#tree-main-go-2873f79a)
+	//
+	// ```go
+	// package main
+	// ```
+}
+
+// listFormatter はファイルのパスと行数だけを書き込む Formatter です
+type listFormatter struct{}
+
+func (listFormatter) WriteHeader(w io.Writer, header folderscope.ReportHeader) error {
+	_, err := fmt.Fprintf(w, "%d files\n", len(header.Files))
+	return err
+}
+
+func (listFormatter) WriteEntry(io.Writer, folderscope.StructureEntry) error { return nil }
+
+func (listFormatter) WriteContent(w io.Writer, content folderscope.FileContent) error {
+	_, err := fmt.Fprintf(w, "%s: %d lines\n", content.RelPath, bytes.Count(content.Content, []byte("\n")))
+	return err
+}
+
+func (listFormatter) WriteFooter(io.Writer, folderscope.ReportFooter) error { return nil }
+
+func ExampleGenerator_WithFormatter() {
+	fsys := fstest.MapFS{
+		"main.go":   {Data: []byte("package main\n\nfunc main() {}\n")},
+		"README.md": {Data: []byte("# Example\n")},
+	}
+	ctx := context.Background()
+
+	scanner, err := folderscope.NewScanner(folderscope.ScanOptions{})
+	if err != nil {
+		panic(err)
+	}
+	entries, _, err := scanner.ScanFS(ctx, fsys, "project")
+	if err != nil {
+		panic(err)
+	}
+	generator, err := folderscope.NewGenerator(folderscope.ReportOptions{})
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.WithFS(fsys).WithFormatter(listFormatter{}).WriteReport(ctx, os.Stdout, entries); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 2 files
+	// README.md: 1 lines
+	// main.go: 3 lines
+}
@@ -0,0 +1,105 @@
+package folderscope
+
+import (
+	"context"
+	"io"
+
+	"FolderScope/internal/domain/model"
+	"FolderScope/internal/infrastructure/logging"
+	"FolderScope/internal/usecase/report"
+)
+
+// Entry はスキャンで記録したファイル・フォルダ 1 件分の情報です。
+// RelPath はルートからの '/' 区切りの相対パス、Path は OS のパスです
+type Entry = model.FileSystemEntry
+
+// ScanStats は 1 回のスキャンの統計情報（ファイル数・読み込んだバイト数・除外の理由ごとの件数・所要時間など）です
+type ScanStats = model.ScanStats
+
+// SkipReason はスキャン時にエントリが結果から除外された理由です。ScanStats.Skipped のキーに使用します
+type SkipReason = model.SkipReason
+
+// スキャン時にエントリが除外された理由です
+const (
+	SkipIgnored     = model.SkipIgnored
+	SkipHidden      = model.SkipHidden
+	SkipExcluded    = model.SkipExcluded
+	SkipNotIncluded = model.SkipNotIncluded
+	SkipBinary      = model.SkipBinary
+	SkipSize        = model.SkipSize
+	SkipModTime     = model.SkipModTime
+	SkipSymlink     = model.SkipSymlink
+	SkipAccessError = model.SkipAccessError
+)
+
+// Logger はスキャンとレポート生成のログの出力先です。
+// level は "TRACE", "DEBUG", "INFO", "WARN", "ERROR" のいずれかで、attrs は log/slog と同じ形式のキーと値の組です
+type Logger = logging.Logger
+
+// Format はレポートの出力形式です
+type Format = report.Format
+
+// 対応している出力形式です。sqlite 形式は SQLite のドライバーを必要とするため、このパッケージでは出力できません
+const (
+	FormatText     = report.FormatText
+	FormatMarkdown = report.FormatMarkdown
+	FormatHTML     = report.FormatHTML
+	FormatJSON     = report.FormatJSON
+	FormatJSONL    = report.FormatJSONL
+	FormatXML      = report.FormatXML
+	FormatYAML     = report.FormatYAML
+	FormatPDF      = report.FormatPDF
+)
+
+// BinaryPolicy はレポートでのバイナリファイルの扱いです
+type BinaryPolicy = report.BinaryPolicy
+
+// 選択可能なバイナリファイルの扱いです
+const (
+	// BinarySkip はバイナリファイルの内容を省略し、省略した旨のみを記載します（既定）
+	BinarySkip = report.BinarySkip
+	// BinaryOmit はバイナリファイルをレポートから除外します。スキャン時に除外するには ScanOptions.IgnoreBinaryFiles も指定します
+	BinaryOmit = report.BinaryOmit
+	// BinaryStructure はバイナリファイルをフォルダ構成にのみ表示します
+	BinaryStructure = report.BinaryStructure
+	// BinaryHexdump はバイナリファイルの先頭を 16 進ダンプで出力します
+	BinaryHexdump = report.BinaryHexdump
+	// BinaryBase64 はバイナリファイルの内容を Base64 で埋め込みます
+	BinaryBase64 = report.BinaryBase64
+)
+
+// ParseFormat は出力形式の名前（"text", "md", "html", "json" など）を解決します。空文字列はテキスト形式として扱います
+func ParseFormat(name string) (Format, error) {
+	return report.ParseFormat(name)
+}
+
+// FormatFromPath はファイル名の拡張子から出力形式を判定します。判定できない場合は false を返します
+func FormatFromPath(path string) (Format, bool) {
+	return report.FormatFromPath(path)
+}
+
+// Run は root のフォルダをスキャンし、レポートを w に書き込みます。
+// reportOpts.BinaryPolicy が BinaryOmit の場合は、スキャン時にもバイナリファイルを除外します
+func Run(ctx context.Context, root string, w io.Writer, scanOpts ScanOptions, reportOpts ReportOptions) (ScanStats, error) {
+	if reportOpts.BinaryPolicy == BinaryOmit {
+		scanOpts.IgnoreBinaryFiles = true
+	}
+	scanner, err := NewScanner(scanOpts)
+	if err != nil {
+		return ScanStats{}, err
+	}
+	generator, err := NewGenerator(reportOpts)
+	if err != nil {
+		return ScanStats{}, err
+	}
+	entries, stats, err := scanner.Scan(ctx, root)
+	if err != nil {
+		return stats, err
+	}
+	return stats, generator.WithScanStats(stats).WriteReport(ctx, w, entries)
+}
+
+// nopLogger はログを出力しないロガーです。オプションでロガーが指定されていない場合に使用します
+type nopLogger struct{}
+
+func (nopLogger) Log(string, string, error, ...any) {}
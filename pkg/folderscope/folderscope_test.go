@@ -0,0 +1,113 @@
+package folderscope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":      "package main\n",
+		"docs/a.md":    "# A\n",
+		"build.log":    "ignored\n",
+		"image.bin":    "\x00\x01\x02",
+		".env/secrets": "hidden\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		scanOpts   ScanOptions
+		reportOpts ReportOptions
+		wantFiles  []string
+		wantSkip   map[SkipReason]int
+	}{
+		{
+			name:       "既定のオプション",
+			reportOpts: ReportOptions{Format: FormatJSON},
+			wantFiles:  []string{"build.log", "docs/a.md", "image.bin", "main.go"},
+			wantSkip:   map[SkipReason]int{SkipHidden: 1},
+		},
+		{
+			name:       "無視パターンとバイナリファイルの除外",
+			scanOpts:   ScanOptions{IgnorePatterns: []string{"*.log"}},
+			reportOpts: ReportOptions{Format: FormatJSON, BinaryPolicy: BinaryOmit},
+			wantFiles:  []string{"docs/a.md", "main.go"},
+			wantSkip:   map[SkipReason]int{SkipHidden: 1, SkipIgnored: 1, SkipBinary: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			stats, err := Run(context.Background(), dir, &buf, tt.scanOpts, tt.reportOpts)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			var got struct {
+				Entries []struct {
+					RelPath string `json:"relPath"`
+					IsDir   bool   `json:"isDir"`
+				} `json:"entries"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("レポートが JSON として読み込めません: %v\n%s", err, buf.String())
+			}
+			var relPaths []string
+			for _, e := range got.Entries {
+				if !e.IsDir {
+					relPaths = append(relPaths, e.RelPath)
+				}
+			}
+			if strings.Join(relPaths, ",") != strings.Join(tt.wantFiles, ",") {
+				t.Errorf("レポートのファイル = %v, want %v", relPaths, tt.wantFiles)
+			}
+			if stats.Files != len(tt.wantFiles) {
+				t.Errorf("ScanStats.Files = %d, want %d", stats.Files, len(tt.wantFiles))
+			}
+			for reason, want := range tt.wantSkip {
+				if stats.Skipped[reason] != want {
+					t.Errorf("ScanStats.Skipped[%s] = %d, want %d", reason, stats.Skipped[reason], want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewScanner_InvalidRegexp(t *testing.T) {
+	if _, err := NewScanner(ScanOptions{ExcludeRegexps: []string{"("}}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("NewScanner() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestNewGenerator_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ReportOptions
+	}{
+		{name: "未対応の出力形式", opts: ReportOptions{Format: "docx"}},
+		{name: "ドライバーを必要とする sqlite 形式", opts: ReportOptions{Format: "sqlite"}},
+		{name: "未対応のバイナリファイルの扱い", opts: ReportOptions{BinaryPolicy: "embed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGenerator(tt.opts); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("NewGenerator() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package folderscope
+
+import "FolderScope/internal/usecase/report"
+
+// Formatter はレポートの出力形式を実装するインターフェースです。
+// Generator.WithFormatter で指定すると、WriteHeader、フォルダ構成のエントリごとの WriteEntry、
+// 内容を出力するファイルごとの WriteContent、WriteFooter の順に呼び出します。
+// ファイル内容は秘密情報をマスクした後のものを渡します
+type Formatter = report.Formatter
+
+// ReportHeader は Formatter.WriteHeader に渡す、レポート全体の情報です
+type ReportHeader = report.ReportHeader
+
+// StructureEntry は Formatter.WriteEntry に渡す、フォルダ構成の 1 行です
+type StructureEntry = report.StructureEntry
+
+// FileContent は Formatter.WriteContent に渡す、1 ファイル分の内容です
+type FileContent = report.FileContent
+
+// ReportFooter は Formatter.WriteFooter に渡す、レポートの末尾に記載する情報です
+type ReportFooter = report.ReportFooter
+
+// RedactedFile は ReportFooter に含める、秘密情報などをマスクしたファイルです
+type RedactedFile = report.RedactedFile
+
+// ModifiedFile は ReportFooter に含める、スキャンの後に変更されていたファイルです
+type ModifiedFile = report.ModifiedFile
+
+// ContentFramer は、ファイルごとの内容のセクションの前後に内容の見出しやページの区切りを書き込む Formatter が追加で実装するインターフェースです。
+// 実装している場合は、WriteContent の前に BeforeContent を、後に AfterContent を呼び出します
+type ContentFramer = report.ContentFramer
+
+// WithFormatter は、ReportOptions.Format にかかわらず f でレポートを書き込む Generator のコピーを返します
+func (g *Generator) WithFormatter(f Formatter) *Generator {
+	return &Generator{generator: g.generator.WithFormatter(f)}
+}
@@ -0,0 +1,79 @@
+package folderscope
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"FolderScope/internal/usecase/report"
+)
+
+// ReportOptions はレポートの内容を制御するオプションです。ゼロ値ではテキスト形式で、すべてのファイルの内容を出力します
+type ReportOptions struct {
+	// Format はレポートの出力形式です。空の場合はテキスト形式です
+	Format Format
+	// ShowMetadata はフォルダ構成にサイズ・更新日時・パーミッションを表示するかどうかを示します
+	ShowMetadata bool
+	// ShowSummary はレポートの先頭にファイル数・合計サイズ・拡張子別の集計を出力するかどうかを示します
+	ShowSummary bool
+	// LineNumbers はファイル内容に行番号を付けるかどうかを示します
+	LineNumbers bool
+	// MaxContentSize は内容を出力するファイルサイズの上限（バイト）です。0 は無制限を示します
+	MaxContentSize int64
+	// BinaryPolicy はバイナリファイルの扱いです。空の場合は BinarySkip です
+	BinaryPolicy BinaryPolicy
+	// DisableRedaction はファイル内容の秘密情報（API キーやパスワードなど）をマスクしないかどうかを示します
+	DisableRedaction bool
+	// Normalize は作成日時などの実行のたびに変わる情報を含めずに出力するかどうかを示します
+	Normalize bool
+	// ReproduceCommand はレポートの末尾に記載する、レポートを再作成するコマンドラインです。空の場合は記載しません
+	ReproduceCommand string
+}
+
+// Generator はエントリの一覧からレポートを書き込みます。With で始まるメソッドは設定を加えたコピーを返し、元の Generator は変更しません
+type Generator struct {
+	generator *report.Generator
+}
+
+// NewGenerator は ReportOptions を指定して Generator を作成します。出力形式またはバイナリファイルの扱いが不正な場合は ErrInvalidInput の種類のエラーを返します
+func NewGenerator(opts ReportOptions) (*Generator, error) {
+	format, err := report.ParseFormat(string(opts.Format))
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+	if format == report.FormatSQLite {
+		return nil, invalidInput(fmt.Errorf("未対応の出力形式です: %s", opts.Format))
+	}
+	binaryPolicy, err := report.ParseBinaryPolicy(string(opts.BinaryPolicy))
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+	return &Generator{generator: report.NewGeneratorWithOptions(report.Options{
+		Format:           format,
+		ShowMetadata:     opts.ShowMetadata,
+		ShowSummary:      opts.ShowSummary,
+		LineNumbers:      opts.LineNumbers,
+		MaxContentSize:   opts.MaxContentSize,
+		BinaryPolicy:     binaryPolicy,
+		DisableRedaction: opts.DisableRedaction,
+		Normalize:        opts.Normalize,
+		ReproduceCommand: opts.ReproduceCommand,
+	})}, nil
+}
+
+// WithFS はファイル内容を fsys から読み込む Generator のコピーを返します。Scanner.ScanFS でスキャンした場合に使用します
+func (g *Generator) WithFS(fsys fs.FS) *Generator {
+	return &Generator{generator: g.generator.WithFS(fsys)}
+}
+
+// WithScanStats はスキャンの統計情報をレポートに記載する Generator のコピーを返します
+func (g *Generator) WithScanStats(stats ScanStats) *Generator {
+	return &Generator{generator: g.generator.WithScanStats(stats)}
+}
+
+// WriteReport は entries のフォルダ構成とファイル内容を w に書き込みます。
+// ctx がキャンセルされた場合は、ファイル内容の出力を中止して中止の理由をレポートの末尾に記載し、エラーを返します
+func (g *Generator) WriteReport(ctx context.Context, w io.Writer, entries []Entry) error {
+	return g.generator.WithContext(ctx).WriteReport(w, entries)
+}
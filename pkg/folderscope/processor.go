@@ -0,0 +1,18 @@
+package folderscope
+
+import "FolderScope/internal/usecase/report"
+
+// ContentProcessor はファイルの内容を、レポートに書き込む前に加工する処理の 1 段です。
+// Generator.WithContentProcessors で指定すると、秘密情報のマスクなどの既定の加工の後、行番号を付ける前に適用します
+type ContentProcessor = report.ContentProcessor
+
+// ContentProcessorFunc は関数を ContentProcessor として使用するための型です
+type ContentProcessorFunc = report.ContentProcessorFunc
+
+// ContentNotice は、ContentProcessor が内容を出力せずに、この文字列を注記として記載することを示すエラーです
+type ContentNotice = report.ContentNotice
+
+// WithContentProcessors は、既定の加工の後に processors を指定された順に適用する Generator のコピーを返します
+func (g *Generator) WithContentProcessors(processors ...ContentProcessor) *Generator {
+	return &Generator{generator: g.generator.WithContentProcessors(processors...)}
+}
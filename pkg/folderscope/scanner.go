@@ -0,0 +1,82 @@
+package folderscope
+
+import (
+	"context"
+	"io/fs"
+	"time"
+
+	"FolderScope/internal/infrastructure/filesystem"
+)
+
+// DefaultIgnorePatterns は ScanOptions.IgnorePatterns に加えて常に無視するファイル・フォルダ名のパターンです
+func DefaultIgnorePatterns() []string {
+	return append([]string(nil), filesystem.DefaultIgnorePatterns...)
+}
+
+// ScanOptions はスキャンの動作を制御するオプションです。ゼロ値では隠しファイルを除外し、既定の無視パターンのみを適用します
+type ScanOptions struct {
+	// IgnorePatterns は既定の無視パターンに追加する、ファイル・フォルダ名のパターン（"*.log", "node_modules" など）です
+	IgnorePatterns []string
+	// IncludeHidden は隠しファイル・隠しフォルダを結果に含めるかどうかを示します
+	IncludeHidden bool
+	// IgnoreBinaryFiles はバイナリファイルを結果から除外するかどうかを示します
+	IgnoreBinaryFiles bool
+	// IncludeRegexps はルートからの相対パス（'/' 区切り）に対する正規表現です。指定した場合、いずれかに一致するファイルのみを含めます
+	IncludeRegexps []string
+	// ExcludeRegexps はルートからの相対パス（'/' 区切り）に対する正規表現です。いずれかに一致するファイル・フォルダを除外します
+	ExcludeRegexps []string
+	// MinSize と MaxSize はファイルサイズ（バイト）の範囲です。0 は制限なしを示します
+	MinSize int64
+	MaxSize int64
+	// ModifiedAfter と ModifiedBefore は更新日時の範囲です。ゼロ値は制限なしを示します
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+	// ComputeHash はファイルごとに SHA-256 ハッシュを計算し、Entry.Hash に記録するかどうかを示します
+	ComputeHash bool
+	// HashWorkers はハッシュを並行して計算するワーカーの数です。0 の場合は CPU 数を使用します
+	HashWorkers int
+	// Logger はスキャン中のログの出力先です。nil の場合はログを出力しません
+	Logger Logger
+}
+
+// Scanner はフォルダを走査し、エントリの一覧を収集します。複数のゴルーチンから同時に使用できません
+type Scanner struct {
+	scanner *filesystem.Scanner
+}
+
+// NewScanner は ScanOptions を指定して Scanner を作成します。正規表現が不正な場合は ErrInvalidInput の種類のエラーを返します
+func NewScanner(opts ScanOptions) (*Scanner, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	scanner, err := filesystem.NewScannerWithOptions(logger, filesystem.ScannerOptions{
+		IgnorePatterns:    opts.IgnorePatterns,
+		IgnoreBinaryFiles: opts.IgnoreBinaryFiles,
+		IncludeHidden:     opts.IncludeHidden,
+		IncludeRegexps:    opts.IncludeRegexps,
+		ExcludeRegexps:    opts.ExcludeRegexps,
+		ComputeHash:       opts.ComputeHash,
+		HashWorkers:       opts.HashWorkers,
+		MinSize:           opts.MinSize,
+		MaxSize:           opts.MaxSize,
+		ModifiedAfter:     opts.ModifiedAfter,
+		ModifiedBefore:    opts.ModifiedBefore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Scanner{scanner: scanner}, nil
+}
+
+// Scan は root のフォルダを走査し、エントリの一覧と統計情報を返します。
+// ctx がキャンセルされた場合は走査を中止してエラーを返します
+func (s *Scanner) Scan(ctx context.Context, root string) ([]Entry, ScanStats, error) {
+	return s.scanner.ScanWithStats(ctx, root)
+}
+
+// ScanFS は fsys のルートから走査し、エントリの一覧と統計情報を返します。
+// root は各エントリの Path とログに表示するルートの名前です。レポートを書き込む際は Generator.WithFS に同じ fsys を指定します
+func (s *Scanner) ScanFS(ctx context.Context, fsys fs.FS, root string) ([]Entry, ScanStats, error) {
+	return s.scanner.ScanFSWithStats(ctx, fsys, root)
+}